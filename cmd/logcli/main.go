@@ -17,6 +17,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/logcli/client"
 	"github.com/grafana/loki/v3/pkg/logcli/detected"
+	"github.com/grafana/loki/v3/pkg/logcli/history"
 	"github.com/grafana/loki/v3/pkg/logcli/index"
 	"github.com/grafana/loki/v3/pkg/logcli/labelquery"
 	"github.com/grafana/loki/v3/pkg/logcli/output"
@@ -287,6 +288,27 @@ The query is limited to processing 1000 lines per subquery; use --line-limit to
 `)
 
 	detectedFieldsQuery = newDetectedFieldsQuery(detectedFieldsCmd)
+
+	historyCmd = app.Command("history", `List the queries recorded in the query history.
+
+The "history" command lists the log and metric queries executed by the
+query frontend for the tenant, with their time range, duration, bytes
+processed, status and user agent. The query history must be enabled
+in the query frontend. Saving queries by name isn't supported, only the
+executed queries are recorded.
+
+By default the queries of the last hour are listed; use --since to modify
+or provide specific start and end times with --from and --to respectively.
+
+With --replay, the queries are executed again, the oldest first, and
+their recorded and replayed duration and bytes processed are compared.
+Use --shift-to-now to replay them over the same duration ending now.
+
+Example:
+
+	logcli history --since=24h --limit=50 --replay --shift-to-now
+`)
+	historyQuery = newHistoryQuery(historyCmd)
 )
 
 func main() {
@@ -424,6 +446,8 @@ func main() {
 		}
 	case detectedFieldsCmd.FullCommand():
 		detectedFieldsQuery.Do(queryClient, *outputMode)
+	case historyCmd.FullCommand():
+		historyQuery.Do(queryClient, os.Stdout, *outputMode)
 	}
 }
 
@@ -476,6 +500,8 @@ func newQueryClient(app *kingpin.Application) client.Client {
 	app.Flag("org-id", "adds X-Scope-OrgID to API requests for representing tenant ID. Useful for requesting tenant data when bypassing an auth gateway. Can also be set using LOKI_ORG_ID env var.").Default("").Envar("LOKI_ORG_ID").StringVar(&client.OrgID)
 	app.Flag("query-tags", "adds X-Query-Tags http header to API requests. This header value will be part of `metrics.go` statistics. Useful for tracking the query. Can also be set using LOKI_QUERY_TAGS env var.").Default("").Envar("LOKI_QUERY_TAGS").StringVar(&client.QueryTags)
 	app.Flag("nocache", "adds Cache-Control: no-cache http header to API requests. Can also be set using LOKI_NO_CACHE env var.").Default("false").Envar("LOKI_NO_CACHE").BoolVar(&client.NoCache)
+	app.Flag("explain", "adds X-Loki-Explain http header to query requests. With 'analyze' the query is executed and the wall time, lines and bytes of each evaluator and pipeline stage are printed to stderr. Can also be set using LOKI_EXPLAIN env var.").Default("").Envar("LOKI_EXPLAIN").EnumVar(&client.Explain, "", "analyze")
+	app.Flag("pipeline-stats", "adds X-Loki-Pipeline-Stats http header to query requests. The stages of the log pipelines count the lines they process, shown with --stats. Can also be set using LOKI_PIPELINE_STATS env var.").Default("false").Envar("LOKI_PIPELINE_STATS").BoolVar(&client.PipelineStats)
 	app.Flag("bearer-token", "adds the Authorization header to API requests for authentication purposes. Can also be set using LOKI_BEARER_TOKEN env var.").Default("").Envar("LOKI_BEARER_TOKEN").StringVar(&client.BearerToken)
 	app.Flag("bearer-token-file", "adds the Authorization header to API requests for authentication purposes. Can also be set using LOKI_BEARER_TOKEN_FILE env var.").Default("").Envar("LOKI_BEARER_TOKEN_FILE").StringVar(&client.BearerTokenFile)
 	app.Flag("retries", "How many times to retry each query when getting an error response from Loki. Can also be set using LOKI_CLIENT_RETRIES env var.").Default("0").Envar("LOKI_CLIENT_RETRIES").IntVar(&client.Retries)
@@ -727,3 +753,33 @@ func newDetectedFieldsQuery(cmd *kingpin.CmdClause) *detected.FieldsQuery {
 
 	return q
 }
+
+func newHistoryQuery(cmd *kingpin.CmdClause) *history.Query {
+	// calculate query range from cli params
+	var from, to string
+	var since time.Duration
+
+	q := &history.Query{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(_ *kingpin.ParseContext) error {
+		defaultEnd := time.Now()
+		defaultStart := defaultEnd.Add(-since)
+
+		q.Start = mustParse(from, defaultStart)
+		q.End = mustParse(to, defaultEnd)
+
+		q.Quiet = *quiet
+
+		return nil
+	})
+
+	cmd.Flag("limit", "Limit on number of recorded queries to return, the most recent ones.").Default("100").IntVar(&q.Limit)
+	cmd.Flag("since", "Lookback window.").Default("1h").DurationVar(&since)
+	cmd.Flag("from", "Start looking for queries at this absolute time (inclusive)").StringVar(&from)
+	cmd.Flag("to", "Stop looking for queries at this absolute time (exclusive)").StringVar(&to)
+	cmd.Flag("replay", "Execute the recorded queries again and compare their duration and bytes processed.").BoolVar(&q.Replay)
+	cmd.Flag("shift-to-now", "Replay the queries over a time range of the same duration ending now.").BoolVar(&q.ShiftToNow)
+
+	return q
+}
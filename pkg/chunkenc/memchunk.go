@@ -1229,25 +1229,23 @@ func (hb *headBlock) Iterator(ctx context.Context, direction logproto.Direction,
 			return
 		}
 		stats.AddHeadChunkBytes(int64(len(e.s)))
-		newLine, parsedLbs, matches := pipeline.ProcessString(e.t, e.s, e.structuredMetadata...)
-		if !matches {
-			return
-		}
-		stats.AddPostFilterLines(1)
-		var stream *logproto.Stream
-		labels := parsedLbs.String()
-		var ok bool
-		if stream, ok = streams[labels]; !ok {
-			stream = &logproto.Stream{
-				Labels: labels,
-				Hash:   baseHash,
+		log.ProcessEach(pipeline, e.t, e.s, e.structuredMetadata, func(_ int, newLine string, parsedLbs log.LabelsResult) {
+			stats.AddPostFilterLines(1)
+			var stream *logproto.Stream
+			labels := parsedLbs.String()
+			var ok bool
+			if stream, ok = streams[labels]; !ok {
+				stream = &logproto.Stream{
+					Labels: labels,
+					Hash:   baseHash,
+				}
+				streams[labels] = stream
 			}
-			streams[labels] = stream
-		}
-		stream.Entries = append(stream.Entries, logproto.Entry{
-			Timestamp:          time.Unix(0, e.t),
-			Line:               newLine,
-			StructuredMetadata: logproto.FromLabelsToLabelAdapters(e.structuredMetadata),
+			stream.Entries = append(stream.Entries, logproto.Entry{
+				Timestamp:          time.Unix(0, e.t),
+				Line:               newLine,
+				StructuredMetadata: logproto.FromLabelsToLabelAdapters(e.structuredMetadata),
+			})
 		})
 	}
 
@@ -1286,30 +1284,28 @@ func (hb *headBlock) SampleIterator(ctx context.Context, mint, maxt int64, extra
 
 	for _, e := range hb.entries {
 		stats.AddHeadChunkBytes(int64(len(e.s)))
-		value, parsedLabels, ok := extractor.ProcessString(e.t, e.s, e.structuredMetadata...)
-		if !ok {
-			continue
-		}
-		stats.AddPostFilterLines(1)
-		var (
-			found bool
-			s     *logproto.Series
-		)
-
-		lbs := parsedLabels.String()
-		if s, found = series[lbs]; !found {
-			s = &logproto.Series{
-				Labels:     lbs,
-				Samples:    SamplesPool.Get(len(hb.entries)).([]logproto.Sample)[:0],
-				StreamHash: baseHash,
+		log.ExtractEach(extractor, e.t, e.s, e.structuredMetadata, func(n int, value float64, parsedLabels log.LabelsResult) {
+			stats.AddPostFilterLines(1)
+			var (
+				found bool
+				s     *logproto.Series
+			)
+
+			lbs := parsedLabels.String()
+			if s, found = series[lbs]; !found {
+				s = &logproto.Series{
+					Labels:     lbs,
+					Samples:    SamplesPool.Get(len(hb.entries)).([]logproto.Sample)[:0],
+					StreamHash: baseHash,
+				}
+				series[lbs] = s
 			}
-			series[lbs] = s
-		}
 
-		s.Samples = append(s.Samples, logproto.Sample{
-			Timestamp: e.t,
-			Value:     value,
-			Hash:      xxhash.Sum64(unsafeGetBytes(e.s)),
+			s.Samples = append(s.Samples, logproto.Sample{
+				Timestamp: e.t,
+				Value:     value,
+				Hash:      sampleHash(unsafeGetBytes(e.s), n),
+			})
 		})
 	}
 
@@ -1625,6 +1621,7 @@ func newEntryIterator(ctx context.Context, pool compression.ReaderPool, b []byte
 		bufferedIterator: newBufferedIterator(ctx, pool, b, format, symbolizer),
 		pipeline:         pipeline,
 		stats:            stats.FromContext(ctx),
+		exploding:        log.AsExplodingPipeline(pipeline),
 	}
 }
 
@@ -1635,6 +1632,11 @@ type entryBufferedIterator struct {
 
 	cur        logproto.Entry
 	currLabels log.LabelsResult
+
+	// exploding is set when the pipeline can return several entries for a single line.
+	exploding   log.ExplodingStreamPipeline
+	explodeN    int
+	explodeMore bool
 }
 
 func (e *entryBufferedIterator) At() logproto.Entry {
@@ -1646,8 +1648,8 @@ func (e *entryBufferedIterator) Labels() string { return e.currLabels.String() }
 func (e *entryBufferedIterator) StreamHash() uint64 { return e.pipeline.BaseLabels().Hash() }
 
 func (e *entryBufferedIterator) Next() bool {
-	for e.bufferedIterator.Next() {
-		newLine, lbs, matches := e.pipeline.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
+	for e.explodeMore || e.bufferedIterator.Next() {
+		newLine, lbs, matches := e.process()
 		if !matches {
 			continue
 		}
@@ -1664,6 +1666,21 @@ func (e *entryBufferedIterator) Next() bool {
 	return false
 }
 
+// process processes the current line, or its next result when the line is exploded.
+func (e *entryBufferedIterator) process() ([]byte, log.LabelsResult, bool) {
+	if e.exploding == nil {
+		return e.pipeline.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
+	}
+	if e.explodeMore {
+		e.explodeN++
+	} else {
+		e.explodeN = 0
+	}
+	newLine, lbs, matches, more := e.exploding.ProcessExploded(e.currTs, e.currLine, e.explodeN, e.currStructuredMetadata...)
+	e.explodeMore = more
+	return newLine, lbs, matches
+}
+
 func (e *entryBufferedIterator) Close() error {
 	if e.pipeline.ReferencedStructuredMetadata() {
 		e.stats.SetQueryReferencedStructuredMetadata()
@@ -1677,6 +1694,7 @@ func newSampleIterator(ctx context.Context, pool compression.ReaderPool, b []byt
 		bufferedIterator: newBufferedIterator(ctx, pool, b, format, symbolizer),
 		extractor:        extractor,
 		stats:            stats.FromContext(ctx),
+		exploding:        log.AsExplodingSampleExtractor(extractor),
 	}
 }
 
@@ -1688,24 +1706,44 @@ type sampleBufferedIterator struct {
 
 	cur        logproto.Sample
 	currLabels log.LabelsResult
+
+	// exploding is set when the extractor can return several samples for a single line.
+	exploding   log.ExplodingStreamSampleExtractor
+	explodeN    int
+	explodeMore bool
 }
 
 func (e *sampleBufferedIterator) Next() bool {
-	for e.bufferedIterator.Next() {
-		val, labels, ok := e.extractor.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
+	for e.explodeMore || e.bufferedIterator.Next() {
+		val, labels, ok := e.process()
 		if !ok {
 			continue
 		}
 		e.stats.AddPostFilterLines(1)
 		e.currLabels = labels
 		e.cur.Value = val
-		e.cur.Hash = xxhash.Sum64(e.currLine)
+		e.cur.Hash = sampleHash(e.currLine, e.explodeN)
 		e.cur.Timestamp = e.currTs
 		return true
 	}
 	return false
 }
 
+// process extracts the sample of the current line, or its next sample when the line is exploded.
+func (e *sampleBufferedIterator) process() (float64, log.LabelsResult, bool) {
+	if e.exploding == nil {
+		return e.extractor.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
+	}
+	if e.explodeMore {
+		e.explodeN++
+	} else {
+		e.explodeN = 0
+	}
+	val, labels, ok, more := e.exploding.ProcessExploded(e.currTs, e.currLine, e.explodeN, e.currStructuredMetadata...)
+	e.explodeMore = more
+	return val, labels, ok
+}
+
 func (e *sampleBufferedIterator) Close() error {
 	if e.extractor.ReferencedStructuredMetadata() {
 		e.stats.SetQueryReferencedStructuredMetadata()
@@ -1722,6 +1760,12 @@ func (e *sampleBufferedIterator) At() logproto.Sample {
 	return e.cur
 }
 
+// sampleHash returns the hash of the n-th sample of a log line,
+// samples of a line exploded by the pipeline must have different hashes to not be deduplicated.
+func sampleHash(line []byte, n int) uint64 {
+	return xxhash.Sum64(line) + uint64(n)
+}
+
 // validateBlock validates block by doing following checks:
 // 1. Offset+length do not overrun size of the chunk from which we are reading the block.
 // 2. Checksum of the block we will read matches the stored checksum in the chunk.
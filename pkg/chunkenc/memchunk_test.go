@@ -1164,6 +1164,57 @@ func TestMemchunkLongLine(t *testing.T) {
 	}
 }
 
+func TestMemChunk_ExplodedLines(t *testing.T) {
+	lines := []string{
+		`{"errors":[{"code":"E1"},{"code":"E2"}]}`,
+		`{"errors":[]}`,
+		`{"errors":[{"code":"E1"},{"code":"E1"},{"code":"E3"}]}`,
+	}
+	for _, f := range allPossibleFormats {
+		t.Run(fmt.Sprintf("%v-%v", f.chunkFormat, f.headBlockFmt), func(t *testing.T) {
+			c := NewMemChunk(f.chunkFormat, compression.EncNone, f.headBlockFmt, testBlockSize, testTargetSize)
+			for i, line := range lines {
+				dup, err := c.Append(&logproto.Entry{Timestamp: time.Unix(0, int64(i)), Line: line})
+				require.False(t, dup)
+				require.NoError(t, err)
+				if i == 1 {
+					// the last line stays in the head block.
+					require.NoError(t, c.cut())
+				}
+			}
+
+			expr, err := syntax.ParseLogSelector(`{app="foo"} | json_array "errors" | json | code!="E3"`, true)
+			require.NoError(t, err)
+			p, err := expr.Pipeline()
+			require.NoError(t, err)
+			it, err := c.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 100), logproto.FORWARD, p.ForStream(labels.Labels{}))
+			require.NoError(t, err)
+
+			var entries []string
+			for it.Next() {
+				entries = append(entries, fmt.Sprintf("%d %s", it.At().Timestamp.UnixNano(), it.At().Line))
+			}
+			require.NoError(t, it.Close())
+			require.Equal(t, []string{`0 {"code":"E1"}`, `0 {"code":"E2"}`, `2 {"code":"E1"}`, `2 {"code":"E1"}`}, entries)
+
+			sampleExpr, err := syntax.ParseSampleExpr(`count_over_time({app="foo"} | json_array "errors" | json | code="E1" [1m])`)
+			require.NoError(t, err)
+			ex, err := sampleExpr.Extractor()
+			require.NoError(t, err)
+			sampleIt := c.SampleIterator(context.Background(), time.Unix(0, 0), time.Unix(0, 100), ex.ForStream(labels.Labels{}))
+
+			var samples []logproto.Sample
+			for sampleIt.Next() {
+				samples = append(samples, sampleIt.At())
+			}
+			require.NoError(t, sampleIt.Close())
+			require.Len(t, samples, 3)
+			// samples of the same line have different hashes to not be deduplicated.
+			require.NotEqual(t, samples[1].Hash, samples[2].Hash)
+		})
+	}
+}
+
 // Ensure passing a reusable []byte doesn't affect output
 func TestBytesWith(t *testing.T) {
 	t.Parallel()
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/Workiva/go-datastructures/rangetree"
-	"github.com/cespare/xxhash/v2"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
 
@@ -260,27 +259,25 @@ func (hb *unorderedHeadBlock) Iterator(ctx context.Context, direction logproto.D
 		maxt,
 		func(statsCtx *stats.Context, ts int64, line string, structuredMetadataSymbols symbols) error {
 			structuredMetadata = hb.symbolizer.Lookup(structuredMetadataSymbols, structuredMetadata)
-			newLine, parsedLbs, matches := pipeline.ProcessString(ts, line, structuredMetadata...)
-			if !matches {
-				return nil
-			}
-			statsCtx.AddPostFilterLines(1)
-			var stream *logproto.Stream
-			labels := parsedLbs.String()
-			var ok bool
-			if stream, ok = streams[labels]; !ok {
-				stream = &logproto.Stream{
-					Labels: labels,
-					Hash:   baseHash,
+			log.ProcessEach(pipeline, ts, line, structuredMetadata, func(_ int, newLine string, parsedLbs log.LabelsResult) {
+				statsCtx.AddPostFilterLines(1)
+				var stream *logproto.Stream
+				labels := parsedLbs.String()
+				var ok bool
+				if stream, ok = streams[labels]; !ok {
+					stream = &logproto.Stream{
+						Labels: labels,
+						Hash:   baseHash,
+					}
+					streams[labels] = stream
 				}
-				streams[labels] = stream
-			}
 
-			stream.Entries = append(stream.Entries, logproto.Entry{
-				Timestamp:          time.Unix(0, ts),
-				Line:               newLine,
-				StructuredMetadata: logproto.FromLabelsToLabelAdapters(parsedLbs.StructuredMetadata()),
-				Parsed:             logproto.FromLabelsToLabelAdapters(parsedLbs.Parsed()),
+				stream.Entries = append(stream.Entries, logproto.Entry{
+					Timestamp:          time.Unix(0, ts),
+					Line:               newLine,
+					StructuredMetadata: logproto.FromLabelsToLabelAdapters(parsedLbs.StructuredMetadata()),
+					Parsed:             logproto.FromLabelsToLabelAdapters(parsedLbs.Parsed()),
+				})
 			})
 			return nil
 		},
@@ -322,29 +319,27 @@ func (hb *unorderedHeadBlock) SampleIterator(
 		maxt,
 		func(statsCtx *stats.Context, ts int64, line string, structuredMetadataSymbols symbols) error {
 			structuredMetadata = hb.symbolizer.Lookup(structuredMetadataSymbols, structuredMetadata)
-			value, parsedLabels, ok := extractor.ProcessString(ts, line, structuredMetadata...)
-			if !ok {
-				return nil
-			}
-			statsCtx.AddPostFilterLines(1)
-			var (
-				found bool
-				s     *logproto.Series
-			)
-			lbs := parsedLabels.String()
-			s, found = series[lbs]
-			if !found {
-				s = &logproto.Series{
-					Labels:     lbs,
-					Samples:    SamplesPool.Get(hb.lines).([]logproto.Sample)[:0],
-					StreamHash: baseHash,
+			log.ExtractEach(extractor, ts, line, structuredMetadata, func(n int, value float64, parsedLabels log.LabelsResult) {
+				statsCtx.AddPostFilterLines(1)
+				var (
+					found bool
+					s     *logproto.Series
+				)
+				lbs := parsedLabels.String()
+				s, found = series[lbs]
+				if !found {
+					s = &logproto.Series{
+						Labels:     lbs,
+						Samples:    SamplesPool.Get(hb.lines).([]logproto.Sample)[:0],
+						StreamHash: baseHash,
+					}
+					series[lbs] = s
 				}
-				series[lbs] = s
-			}
-			s.Samples = append(s.Samples, logproto.Sample{
-				Timestamp: ts,
-				Value:     value,
-				Hash:      xxhash.Sum64(unsafeGetBytes(line)),
+				s.Samples = append(s.Samples, logproto.Sample{
+					Timestamp: ts,
+					Value:     value,
+					Hash:      sampleHash(unsafeGetBytes(line), n),
+				})
 			})
 			return nil
 		},
@@ -41,6 +41,8 @@ import (
 	"github.com/grafana/loki/v3/pkg/analytics"
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
 	"github.com/grafana/loki/v3/pkg/distributor/clientpool"
+	"github.com/grafana/loki/v3/pkg/distributor/ingestpipeline"
+	"github.com/grafana/loki/v3/pkg/distributor/redaction"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
 	"github.com/grafana/loki/v3/pkg/ingester"
@@ -91,6 +93,8 @@ type Config struct {
 
 	OTLPConfig push.GlobalOTLPConfig `yaml:"otlp_config"`
 
+	SplunkHEC SplunkHECConfig `yaml:"splunk_hec" doc:"description=Configures the Splunk HTTP Event Collector endpoint."`
+
 	KafkaEnabled    bool         `yaml:"kafka_writes_enabled"`
 	IngesterEnabled bool         `yaml:"ingester_writes_enabled"`
 	KafkaConfig     kafka.Config `yaml:"-"`
@@ -111,6 +115,9 @@ func (cfg *Config) Validate() error {
 	if !cfg.KafkaEnabled && !cfg.IngesterEnabled {
 		return fmt.Errorf("at least one of kafka and ingestor writes must be enabled")
 	}
+	if err := cfg.SplunkHEC.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -135,6 +142,7 @@ type Distributor struct {
 	tenantsRetention *retention.TenantsRetention
 	ingestersRing    ring.ReadRing
 	validator        *Validator
+	tenantPipelines  *tenantPipelines
 	pool             *ring_client.Pool
 	tee              Tee
 
@@ -246,6 +254,7 @@ func New(
 		tenantsRetention:      retention.NewTenantsRetention(overrides),
 		ingestersRing:         ingestersRing,
 		validator:             validator,
+		tenantPipelines:       newTenantPipelines(),
 		pool:                  clientpool.NewPool("ingester", clientCfg.PoolConfig, ingestersRing, factory, logger, metricsNamespace),
 		labelCache:            labelCache,
 		shardTracker:          NewShardTracker(),
@@ -409,6 +418,12 @@ func (p *pushTracker) doneWithResult(err error) {
 // Push a set of streams.
 // The returned error is the last one seen.
 func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*logproto.PushResponse, error) {
+	var rejected int
+	return d.push(ctx, req, &rejected)
+}
+
+// push pushes a set of streams, and adds the number of entries rejected by the validation to rejected.
+func (d *Distributor) push(ctx context.Context, req *logproto.PushRequest, rejected *int) (*logproto.PushResponse, error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -429,6 +444,12 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	var validationErrors util.GroupedErrors
 	validationContext := d.validator.getValidationContextForTime(time.Now(), tenantID)
 
+	pipelines := d.tenantPipelines.get(tenantID, validationContext.ingestPipeline, validationContext.redaction)
+	if pipelines.err != nil {
+		return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid ingest pipeline or redaction config: %s", pipelines.err)
+	}
+	ingestPipeline, redactor := pipelines.ingestPipeline(), pipelines.redactor
+
 	func() {
 		sp := opentracing.SpanFromContext(ctx)
 		if sp != nil {
@@ -443,6 +464,20 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				continue
 			}
 
+			// The ingest pipeline sees the lines as they were pushed.
+			if ingestPipeline != nil {
+				if stream = d.applyIngestPipeline(validationContext, ingestPipeline, stream); len(stream.Entries) == 0 {
+					continue
+				}
+			}
+
+			// Secrets are redacted before the lines are truncated, which could leave a part of them undetected.
+			if redactor != nil {
+				if stream.Entries = d.redact(validationContext, redactor, stream); len(stream.Entries) == 0 {
+					continue
+				}
+			}
+
 			// Truncate first so subsequent steps have consistent line lengths
 			d.truncateLines(validationContext, &stream)
 
@@ -451,6 +486,7 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			if err != nil {
 				d.writeFailuresManager.Log(tenantID, err)
 				validationErrors.Add(err)
+				*rejected += len(stream.Entries)
 				validation.DiscardedSamples.WithLabelValues(validation.InvalidLabels, tenantID).Add(float64(len(stream.Entries)))
 				bytes := 0
 				for _, e := range stream.Entries {
@@ -470,6 +506,7 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				if err := d.validator.ValidateEntry(ctx, validationContext, lbs, entry); err != nil {
 					d.writeFailuresManager.Log(tenantID, err)
 					validationErrors.Add(err)
+					*rejected++
 					continue
 				}
 
@@ -529,6 +566,7 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			}
 		}
 	}()
+	pipelines.releaseIngestPipeline(ingestPipeline)
 
 	var validationErr error
 	if validationErrors.Err() != nil {
@@ -811,6 +849,40 @@ func maxT(t1, t2 time.Time) time.Time {
 	return t1
 }
 
+func (d *Distributor) applyIngestPipeline(vContext validationContext, pipeline *ingestpipeline.Pipeline, stream logproto.Stream) logproto.Stream {
+	var stats ingestpipeline.Stats
+	stream = pipeline.Process(stream, &stats)
+
+	validation.DiscardedSamples.WithLabelValues(validation.IngestPipeline, vContext.userID).Add(float64(stats.DroppedEntries))
+	validation.DiscardedBytes.WithLabelValues(validation.IngestPipeline, vContext.userID).Add(float64(stats.DroppedBytes))
+	validation.MutatedSamples.WithLabelValues(validation.IngestPipeline, vContext.userID).Add(float64(stats.MutatedEntries))
+	validation.MutatedBytes.WithLabelValues(validation.IngestPipeline, vContext.userID).Add(float64(stats.MutatedBytes))
+	return stream
+}
+
+func (d *Distributor) redact(vContext validationContext, redactor *redaction.Redactor, stream logproto.Stream) []logproto.Entry {
+	var stats redaction.Stats
+	entries := redactor.Redact(stream.Entries, &stats)
+	if len(stats.Detected) == 0 {
+		return entries
+	}
+
+	detected := make([]string, 0, len(stats.Detected))
+	for name, n := range stats.Detected {
+		validation.RedactedSecrets.WithLabelValues(name, vContext.userID).Add(float64(n))
+		detected = append(detected, fmt.Sprintf("%s=%d", name, n))
+	}
+	sort.Strings(detected)
+	d.writeFailuresManager.Log(vContext.userID, fmt.Errorf("secrets redacted from stream %s: %s, %d entries dropped, %d entries mutated",
+		stream.Labels, strings.Join(detected, ", "), stats.DroppedEntries, stats.MutatedEntries))
+
+	validation.DiscardedSamples.WithLabelValues(validation.Redacted, vContext.userID).Add(float64(stats.DroppedEntries))
+	validation.DiscardedBytes.WithLabelValues(validation.Redacted, vContext.userID).Add(float64(stats.DroppedBytes))
+	validation.MutatedSamples.WithLabelValues(validation.Redacted, vContext.userID).Add(float64(stats.MutatedEntries))
+	validation.MutatedBytes.WithLabelValues(validation.Redacted, vContext.userID).Add(float64(stats.MutatedBytes))
+	return entries
+}
+
 func (d *Distributor) truncateLines(vContext validationContext, stream *logproto.Stream) {
 	if !vContext.maxLineSizeTruncate {
 		return
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/httpgrpc"
@@ -13,18 +14,48 @@ import (
 	"github.com/grafana/dskit/tenant"
 
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/logproto"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 	"github.com/grafana/loki/v3/pkg/validation"
 )
 
 // PushHandler reads a snappy-compressed proto from the HTTP body.
 func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
-	d.pushHandler(w, r, push.ParseLokiRequest)
+	d.pushHandler(w, r, push.ParseLokiRequest, writeNoContent)
 }
 
 func (d *Distributor) OTLPPushHandler(w http.ResponseWriter, r *http.Request) {
 	interceptor := newOtelErrorHeaderInterceptor(w)
-	d.pushHandler(interceptor, r, push.ParseOTLPRequest)
+	d.pushHandler(interceptor, r, push.ParseOTLPRequest, writeNoContent)
+}
+
+// ElasticsearchBulkHandler pushes the documents of an Elasticsearch _bulk request, mapped with the Elasticsearch
+// mapping of the tenant, and reports them with the actions of the request.
+func (d *Distributor) ElasticsearchBulkHandler(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	var actions []push.ElasticsearchBulkAction
+	parser := func(userID string, r *http.Request, tenantsRetention push.TenantsRetention, limits push.Limits, tracker push.UsageTracker) (*logproto.PushRequest, *push.Stats, error) {
+		req, stats, bulkActions, err := push.ParseElasticsearchBulkRequest(userID, r, tenantsRetention, limits, tracker)
+		actions = bulkActions
+		return req, stats, err
+	}
+	d.pushHandler(w, r, parser, func(w http.ResponseWriter, _ int) {
+		if err := push.WriteElasticsearchBulkResponse(w, actions, time.Since(start)); err != nil {
+			level.Error(util_log.WithContext(r.Context(), util_log.Logger)).Log("msg", "error writing bulk response", "err", err)
+		}
+	})
+}
+
+// ElasticsearchInfoHandler returns the version of Elasticsearch compatible with the _bulk endpoint, checked by the
+// Elastic shippers before they send documents.
+func (d *Distributor) ElasticsearchInfoHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	_, _ = w.Write([]byte(`{"name":"loki","cluster_name":"loki","version":{"number":"8.11.0","build_flavor":"default"},"tagline":"You Know, for Search"}`))
+}
+
+func writeNoContent(w http.ResponseWriter, _ int) {
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // otelErrorHeaderInterceptor maps 500 errors to 503.
@@ -45,7 +76,9 @@ func (i *otelErrorHeaderInterceptor) WriteHeader(statusCode int) {
 	i.ResponseWriter.WriteHeader(statusCode)
 }
 
-func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRequestParser push.RequestParser) {
+// pushHandler pushes the request parsed by pushRequestParser, writeResponse writes the response once its entries are
+// pushed.
+func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRequestParser push.RequestParser, writeResponse func(w http.ResponseWriter, entries int)) {
 	logger := util_log.WithContext(r.Context(), util_log.Logger)
 	tenantID, err := tenant.TenantID(r.Context())
 	if err != nil {
@@ -84,6 +117,11 @@ func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRe
 		)
 	}
 
+	var entries int
+	for _, s := range req.Streams {
+		entries += len(s.Entries)
+	}
+
 	_, err = d.Push(r.Context(), req)
 	if err == nil {
 		if d.tenantConfigs.LogPushRequest(tenantID) {
@@ -91,7 +129,7 @@ func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRe
 				"msg", "push request successful",
 			)
 		}
-		w.WriteHeader(http.StatusNoContent)
+		writeResponse(w, entries)
 		return
 	}
 
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/grafana/dskit/user"
@@ -13,6 +14,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logproto"
 
 	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/validation"
@@ -77,7 +79,7 @@ func TestRequestParserWrapping(t *testing.T) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "fake-path", nil)
 	require.NoError(t, err)
 
-	distributors[0].pushHandler(httptest.NewRecorder(), req, stubParser)
+	distributors[0].pushHandler(httptest.NewRecorder(), req, stubParser, writeNoContent)
 
 	require.True(t, called)
 }
@@ -117,3 +119,27 @@ func Test_OtelErrorHeaderInterceptor(t *testing.T) {
 func stubParser(_ string, _ *http.Request, _ push.TenantsRetention, _ push.Limits, _ push.UsageTracker) (*logproto.PushRequest, *push.Stats, error) {
 	return &logproto.PushRequest{}, &push.Stats{}, nil
 }
+
+func TestElasticsearchBulkHandler(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectOldSamples = false
+	limits.ElasticsearchMapping.IndexLabels = []string{"service.name"}
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	body := "{\"index\":{}}\n{\"@timestamp\":\"2024-05-01T10:00:00Z\",\"message\":\"line 1\",\"service\":{\"name\":\"api\"}}\n" +
+		"{\"create\":{}}\n{\"@timestamp\":\"2024-05-01T10:00:01Z\",\"message\":\"line 2\",\"service\":{\"name\":\"api\"}}\n"
+	req := httptest.NewRequest(http.MethodPost, "/elasticsearch/_bulk", strings.NewReader(body))
+	req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+	w := httptest.NewRecorder()
+	distributors[0].ElasticsearchBulkHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"errors":false,"items":[{"index":{"status":201}},{"create":{"status":201}}]`)
+	pushed := ingester.Peek()
+	require.Len(t, pushed.Streams, 1)
+	require.Equal(t, `{service_name="api"}`, pushed.Streams[0].Labels)
+	require.Len(t, pushed.Streams[0].Entries, 2)
+}
@@ -0,0 +1,114 @@
+package ingestpipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// Config is the ingest pipeline of a tenant. Its stages are applied in order to the streams pushed by the tenant,
+// before they are validated.
+type Config []StageConfig
+
+// StageConfig is a stage of an ingest pipeline, exactly one of its fields must be set.
+type StageConfig struct {
+	Drop               *DropConfig               `yaml:"drop,omitempty" json:"drop,omitempty"`
+	Relabel            []*relabel.Config         `yaml:"relabel,omitempty" json:"relabel,omitempty"`
+	Redact             *RedactConfig             `yaml:"redact,omitempty" json:"redact,omitempty"`
+	StructuredMetadata *StructuredMetadataConfig `yaml:"structured_metadata,omitempty" json:"structured_metadata,omitempty"`
+}
+
+// DropConfig drops the entries matching a LogQL log query, like `{app="api"} |= "DEBUG"`.
+type DropConfig struct {
+	Selector string `yaml:"selector" json:"selector"`
+
+	expr syntax.LogSelectorExpr
+}
+
+// RedactConfig replaces the matches of a regex in the lines of the entries matching a LogQL log query, or of all the
+// entries if the selector is empty. The replacement can reference the capture groups of the regex, like `$1`.
+type RedactConfig struct {
+	Selector    string `yaml:"selector,omitempty" json:"selector,omitempty"`
+	Regex       string `yaml:"regex" json:"regex"`
+	Replacement string `yaml:"replacement" json:"replacement"`
+
+	expr  syntax.LogSelectorExpr
+	regex *regexp.Regexp
+}
+
+// StructuredMetadataConfig adds the labels extracted by the parsers of a LogQL log query, like
+// `{app="api"} | json trace_id="trace.id"`, to the structured metadata of the entries matching it.
+type StructuredMetadataConfig struct {
+	Selector string   `yaml:"selector" json:"selector"`
+	Labels   []string `yaml:"labels" json:"labels"`
+
+	expr syntax.LogSelectorExpr
+}
+
+// Validate validates the stages of the pipeline and parses their selectors and regexes.
+func (c Config) Validate() error {
+	for i := range c {
+		if err := c[i].validate(); err != nil {
+			return fmt.Errorf("invalid ingest pipeline stage %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *StageConfig) validate() error {
+	var set int
+	for _, ok := range []bool{s.Drop != nil, s.Relabel != nil, s.Redact != nil, s.StructuredMetadata != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one of drop, relabel, redact or structured_metadata must be set")
+	}
+
+	var err error
+	switch {
+	case s.Drop != nil:
+		s.Drop.expr, err = parseSelector(s.Drop.Selector)
+	case s.Relabel != nil:
+		for _, cfg := range s.Relabel {
+			if cfg == nil {
+				return errors.New("empty relabel config")
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+		}
+	case s.Redact != nil:
+		if s.Redact.Regex == "" {
+			return errors.New("redact regex must be set")
+		}
+		if s.Redact.regex, err = regexp.Compile(s.Redact.Regex); err != nil {
+			return fmt.Errorf("invalid redact regex: %w", err)
+		}
+		if s.Redact.Selector != "" {
+			s.Redact.expr, err = parseSelector(s.Redact.Selector)
+		}
+	case s.StructuredMetadata != nil:
+		if len(s.StructuredMetadata.Labels) == 0 {
+			return errors.New("structured_metadata labels must be set")
+		}
+		s.StructuredMetadata.expr, err = parseSelector(s.StructuredMetadata.Selector)
+	}
+	return err
+}
+
+func parseSelector(selector string) (syntax.LogSelectorExpr, error) {
+	if selector == "" {
+		return nil, errors.New("selector must be set")
+	}
+	expr, err := syntax.ParseLogSelector(selector, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+	return expr, nil
+}
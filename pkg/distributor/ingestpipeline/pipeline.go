@@ -0,0 +1,304 @@
+package ingestpipeline
+
+import (
+	"regexp"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// Pipeline applies the stages of an ingest pipeline to the streams of a push request.
+// The log pipelines of its stages are stateful, a Pipeline must not be used concurrently.
+type Pipeline struct {
+	stages []stage
+}
+
+// Stats are the number of entries dropped and mutated by a pipeline, and their bytes before the pipeline.
+type Stats struct {
+	DroppedEntries, DroppedBytes int
+	MutatedEntries, MutatedBytes int
+}
+
+type stage interface {
+	// process processes the entries of the stream with the labels, it returns the labels and entries of the stream
+	// after the stage. The lines and structured metadata of the entries are updated in place.
+	process(lbs labels.Labels, entries []logproto.Entry, stats *Stats) (labels.Labels, []logproto.Entry)
+}
+
+// New returns the pipeline of the config, nil if the config has no stages.
+func New(cfg Config) (*Pipeline, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+
+	p := &Pipeline{stages: make([]stage, 0, len(cfg))}
+	for i := range cfg {
+		// the stages of the overrides have their selectors and regexes parsed by the validation of the limits,
+		// other stages are parsed on a copy so a config shared between goroutines isn't written to.
+		s := cfg[i]
+		if !s.validated() {
+			s = s.clone()
+			if err := s.validate(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case s.Drop != nil:
+			selector, err := newLogSelector(s.Drop.expr)
+			if err != nil {
+				return nil, err
+			}
+			p.stages = append(p.stages, &dropStage{selector})
+		case s.Relabel != nil:
+			p.stages = append(p.stages, relabelStage(s.Relabel))
+		case s.Redact != nil:
+			var selector *logSelector
+			if s.Redact.expr != nil {
+				var err error
+				if selector, err = newLogSelector(s.Redact.expr); err != nil {
+					return nil, err
+				}
+			}
+			p.stages = append(p.stages, &redactStage{selector: selector, regex: s.Redact.regex, replacement: s.Redact.Replacement})
+		case s.StructuredMetadata != nil:
+			selector, err := newLogSelector(s.StructuredMetadata.expr)
+			if err != nil {
+				return nil, err
+			}
+			p.stages = append(p.stages, &structuredMetadataStage{selector: selector, labels: s.StructuredMetadata.Labels})
+		}
+	}
+	return p, nil
+}
+
+// validated returns true if the selectors and regexes of the stage have been parsed.
+func (s *StageConfig) validated() bool {
+	switch {
+	case s.Drop != nil:
+		return s.Drop.expr != nil
+	case s.Redact != nil:
+		return s.Redact.regex != nil && (s.Redact.Selector == "" || s.Redact.expr != nil)
+	case s.StructuredMetadata != nil:
+		return s.StructuredMetadata.expr != nil
+	}
+	return s.Relabel != nil
+}
+
+func (s StageConfig) clone() StageConfig {
+	if s.Drop != nil {
+		drop := *s.Drop
+		s.Drop = &drop
+	}
+	if s.Redact != nil {
+		redact := *s.Redact
+		s.Redact = &redact
+	}
+	if s.StructuredMetadata != nil {
+		structuredMetadata := *s.StructuredMetadata
+		s.StructuredMetadata = &structuredMetadata
+	}
+	return s
+}
+
+// Process applies the stages of the pipeline to the stream. It returns the stream with its labels and entries
+// updated, the stream has no entries if they were all dropped. Streams with invalid labels are left untouched.
+func (p *Pipeline) Process(stream logproto.Stream, stats *Stats) logproto.Stream {
+	lbs, err := syntax.ParseLabels(stream.Labels)
+	if err != nil {
+		return stream
+	}
+
+	entries := stream.Entries
+	for _, s := range p.stages {
+		if lbs, entries = s.process(lbs, entries, stats); len(entries) == 0 {
+			break
+		}
+	}
+	stream.Labels = lbs.String()
+	stream.Entries = entries
+	return stream
+}
+
+// Reset resets the log pipelines of the stages, so the pipeline can be reused without keeping the state of the
+// streams it processed.
+func (p *Pipeline) Reset() {
+	for _, s := range p.stages {
+		switch s := s.(type) {
+		case *dropStage:
+			s.selector.pipeline.Reset()
+		case *redactStage:
+			if s.selector != nil {
+				s.selector.pipeline.Reset()
+			}
+		case *structuredMetadataStage:
+			s.selector.pipeline.Reset()
+		}
+	}
+}
+
+// logSelector matches the entries of the streams selected by a LogQL log query and returned by its pipeline.
+type logSelector struct {
+	matchers []*labels.Matcher
+	pipeline log.Pipeline
+}
+
+func newLogSelector(expr syntax.LogSelectorExpr) (*logSelector, error) {
+	pipeline, err := expr.Pipeline()
+	if err != nil {
+		return nil, err
+	}
+	return &logSelector{matchers: expr.Matchers(), pipeline: pipeline}, nil
+}
+
+// forStream returns the pipeline of the stream, nil if the stream isn't selected.
+func (s *logSelector) forStream(lbs labels.Labels) log.StreamPipeline {
+	for _, m := range s.matchers {
+		if !m.Matches(lbs.Get(m.Name)) {
+			return nil
+		}
+	}
+	return s.pipeline.ForStream(lbs)
+}
+
+func process(pipeline log.StreamPipeline, entry logproto.Entry) (log.LabelsResult, bool) {
+	_, result, ok := pipeline.ProcessString(entry.Timestamp.UnixNano(), entry.Line, logproto.FromLabelAdaptersToLabels(entry.StructuredMetadata)...)
+	return result, ok
+}
+
+type dropStage struct {
+	selector *logSelector
+}
+
+func (s *dropStage) process(lbs labels.Labels, entries []logproto.Entry, stats *Stats) (labels.Labels, []logproto.Entry) {
+	pipeline := s.selector.forStream(lbs)
+	if pipeline == nil {
+		return lbs, entries
+	}
+	return lbs, DropEntries(entries, func(entry *logproto.Entry) bool {
+		if _, ok := process(pipeline, *entry); !ok {
+			return false
+		}
+		stats.DroppedEntries++
+		stats.DroppedBytes += len(entry.Line)
+		return true
+	})
+}
+
+// DropEntries returns the entries for which drop returns false, in order. The entries are only copied once the
+// first one is dropped, the slice of entries is left as is.
+func DropEntries(entries []logproto.Entry, drop func(*logproto.Entry) bool) []logproto.Entry {
+	var kept []logproto.Entry
+	for i := range entries {
+		if drop(&entries[i]) {
+			if kept == nil {
+				kept = make([]logproto.Entry, i, len(entries))
+				copy(kept, entries[:i])
+			}
+			continue
+		}
+		if kept != nil {
+			kept = append(kept, entries[i])
+		}
+	}
+	if kept == nil {
+		return entries
+	}
+	return kept
+}
+
+// relabelStage applies Prometheus relabel configs to the labels of the streams. The entries of the streams left
+// without labels are dropped.
+type relabelStage []*relabel.Config
+
+func (s relabelStage) process(lbs labels.Labels, entries []logproto.Entry, stats *Stats) (labels.Labels, []logproto.Entry) {
+	relabeled, keep := relabel.Process(lbs, s...)
+	if keep && relabeled.Len() > 0 {
+		return relabeled, entries
+	}
+	for _, entry := range entries {
+		stats.DroppedEntries++
+		stats.DroppedBytes += len(entry.Line)
+	}
+	return lbs, entries[:0]
+}
+
+type redactStage struct {
+	selector    *logSelector
+	regex       *regexp.Regexp
+	replacement string
+}
+
+func (s *redactStage) process(lbs labels.Labels, entries []logproto.Entry, stats *Stats) (labels.Labels, []logproto.Entry) {
+	var pipeline log.StreamPipeline
+	if s.selector != nil {
+		if pipeline = s.selector.forStream(lbs); pipeline == nil {
+			return lbs, entries
+		}
+	}
+	for i, entry := range entries {
+		if pipeline != nil {
+			if _, ok := process(pipeline, entry); !ok {
+				continue
+			}
+		}
+		if !s.regex.MatchString(entry.Line) {
+			continue
+		}
+		stats.MutatedEntries++
+		stats.MutatedBytes += len(entry.Line)
+		entries[i].Line = s.regex.ReplaceAllString(entry.Line, s.replacement)
+	}
+	return lbs, entries
+}
+
+type structuredMetadataStage struct {
+	selector *logSelector
+	labels   []string
+}
+
+func (s *structuredMetadataStage) process(lbs labels.Labels, entries []logproto.Entry, _ *Stats) (labels.Labels, []logproto.Entry) {
+	pipeline := s.selector.forStream(lbs)
+	if pipeline == nil {
+		return lbs, entries
+	}
+	for i, entry := range entries {
+		result, ok := process(pipeline, entry)
+		if !ok {
+			continue
+		}
+		parsed := result.Parsed()
+		var metadata push.LabelsAdapter
+		for _, name := range s.labels {
+			value := parsed.Get(name)
+			if value == "" || hasStructuredMetadata(entry, name) {
+				continue
+			}
+			// the structured metadata of the request can be shared with other entries.
+			if metadata == nil {
+				metadata = make(push.LabelsAdapter, len(entry.StructuredMetadata), len(entry.StructuredMetadata)+len(s.labels))
+				copy(metadata, entry.StructuredMetadata)
+			}
+			metadata = append(metadata, logproto.LabelAdapter{Name: name, Value: value})
+		}
+		if metadata != nil {
+			entries[i].StructuredMetadata = metadata
+		}
+	}
+	return lbs, entries
+}
+
+func hasStructuredMetadata(entry logproto.Entry, name string) bool {
+	for _, l := range entry.StructuredMetadata {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package ingestpipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func newPipeline(t *testing.T, config string) *Pipeline {
+	t.Helper()
+	var cfg Config
+	require.NoError(t, yaml.UnmarshalStrict([]byte(config), &cfg))
+	require.NoError(t, cfg.Validate())
+	p, err := New(cfg)
+	require.NoError(t, err)
+	return p
+}
+
+func newStream(labels string, lines ...string) logproto.Stream {
+	stream := logproto.Stream{Labels: labels}
+	for i, line := range lines {
+		stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: line})
+	}
+	return stream
+}
+
+func lines(stream logproto.Stream) []string {
+	var res []string
+	for _, e := range stream.Entries {
+		res = append(res, e.Line)
+	}
+	return res
+}
+
+func TestPipeline(t *testing.T) {
+	p := newPipeline(t, `
+- drop:
+    selector: '{app="api"} |= "DEBUG"'
+- relabel:
+  - action: labeldrop
+    regex: pod_uid
+  - source_labels: [namespace]
+    regex: dev
+    action: drop
+- redact:
+    regex: 'password=\S+'
+    replacement: 'password=<redacted>'
+- redact:
+    selector: '{app="web"}'
+    regex: '(\d{3})\d{3}'
+    replacement: '${1}xxx'
+- structured_metadata:
+    selector: '{app="api"} | logfmt'
+    labels: [trace_id, level]
+`)
+
+	var stats Stats
+	stream := p.Process(newStream(`{app="api", pod_uid="123"}`,
+		"level=DEBUG msg=hello",
+		"level=INFO trace_id=abc msg=login password=secret",
+		"level=INFO msg=123456",
+	), &stats)
+	require.Equal(t, `{app="api"}`, stream.Labels)
+	require.Equal(t, []string{"level=INFO trace_id=abc msg=login password=<redacted>", "level=INFO msg=123456"}, lines(stream))
+	require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "abc"}, {Name: "level", Value: "INFO"}}, stream.Entries[0].StructuredMetadata)
+	require.Equal(t, push.LabelsAdapter{{Name: "level", Value: "INFO"}}, stream.Entries[1].StructuredMetadata)
+	require.Equal(t, Stats{DroppedEntries: 1, DroppedBytes: 21, MutatedEntries: 1, MutatedBytes: 49}, stats)
+
+	stats = Stats{}
+	stream = p.Process(newStream(`{app="web"}`, "DEBUG code=123456"), &stats)
+	require.Equal(t, []string{"DEBUG code=123xxx"}, lines(stream))
+	require.Equal(t, Stats{MutatedEntries: 1, MutatedBytes: 17}, stats)
+
+	stats = Stats{}
+	stream = p.Process(newStream(`{app="api", namespace="dev"}`, "a", "b"), &stats)
+	require.Empty(t, stream.Entries)
+	require.Equal(t, Stats{DroppedEntries: 2, DroppedBytes: 2}, stats)
+
+	// the structured metadata already set is kept.
+	stream = newStream(`{app="api"}`, "level=INFO")
+	stream.Entries[0].StructuredMetadata = push.LabelsAdapter{{Name: "level", Value: "info"}}
+	stream = p.Process(stream, &Stats{})
+	require.Equal(t, push.LabelsAdapter{{Name: "level", Value: "info"}}, stream.Entries[0].StructuredMetadata)
+}
+
+func TestPipelineStructuredMetadataIsCopied(t *testing.T) {
+	p := newPipeline(t, `
+- structured_metadata:
+    selector: '{app="api"} | logfmt'
+    labels: [level]
+`)
+	// the entries of a request can share their structured metadata.
+	shared := make(push.LabelsAdapter, 1, 2)
+	shared[0] = push.LabelAdapter{Name: "trace_id", Value: "abc"}
+	stream := newStream(`{app="api"}`, "level=info", "level=error")
+	stream.Entries[0].StructuredMetadata = shared
+	stream.Entries[1].StructuredMetadata = shared
+
+	stream = p.Process(stream, &Stats{})
+	require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "abc"}, {Name: "level", Value: "info"}}, stream.Entries[0].StructuredMetadata)
+	require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "abc"}, {Name: "level", Value: "error"}}, stream.Entries[1].StructuredMetadata)
+	require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "abc"}}, shared)
+}
+
+func TestPipelineDropKeepsRequestEntries(t *testing.T) {
+	p := newPipeline(t, `
+- drop:
+    selector: '{app="api"} |= "drop"'
+`)
+	stream := newStream(`{app="api"}`, "keep 1", "drop", "keep 2")
+	processed := p.Process(stream, &Stats{})
+	require.Equal(t, []string{"keep 1", "keep 2"}, lines(processed))
+	require.Equal(t, []string{"keep 1", "drop", "keep 2"}, lines(stream))
+}
+
+func TestNewUnvalidatedConfig(t *testing.T) {
+	cfg := Config{{Drop: &DropConfig{Selector: `{app="api"}`}}}
+	p, err := New(cfg)
+	require.NoError(t, err)
+	require.Nil(t, cfg[0].Drop.expr)
+	require.Empty(t, p.Process(newStream(`{app="api"}`, "line"), &Stats{}).Entries)
+
+	p, err = New(nil)
+	require.NoError(t, err)
+	require.Nil(t, p)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		desc   string
+		config string
+	}{
+		{desc: "no stage", config: `[{}]`},
+		{desc: "several stages", config: `[{drop: {selector: '{app="api"}'}, redact: {regex: 'a'}}]`},
+		{desc: "drop without selector", config: `[{drop: {}}]`},
+		{desc: "invalid selector", config: `[{drop: {selector: 'app="api"'}}]`},
+		{desc: "redact without regex", config: `[{redact: {replacement: 'a'}}]`},
+		{desc: "invalid redact regex", config: `[{redact: {regex: '('}}]`},
+		{desc: "structured metadata without labels", config: `[{structured_metadata: {selector: '{app="api"} | json'}}]`},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var cfg Config
+			require.NoError(t, yaml.UnmarshalStrict([]byte(tc.config), &cfg))
+			require.Error(t, cfg.Validate())
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
+	"github.com/grafana/loki/v3/pkg/distributor/ingestpipeline"
+	"github.com/grafana/loki/v3/pkg/distributor/redaction"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 )
@@ -26,6 +28,8 @@ type Limits interface {
 	DiscoverLogLevels(userID string) bool
 
 	ShardStreams(userID string) shardstreams.Config
+	IngestPipeline(userID string) ingestpipeline.Config
+	Redaction(userID string) redaction.Config
 	IngestionRateStrategy() string
 	IngestionRateBytes(userID string) float64
 	IngestionBurstSizeBytes(userID string) int
@@ -33,6 +37,8 @@ type Limits interface {
 	MaxStructuredMetadataSize(userID string) int
 	MaxStructuredMetadataCount(userID string) int
 	OTLPConfig(userID string) push.OTLPConfig
+	ElasticsearchMapping(userID string) push.DocumentMapping
+	SplunkHECMapping(userID string) push.DocumentMapping
 
 	BlockIngestionUntil(userID string) time.Time
 	BlockIngestionStatusCode(userID string) int
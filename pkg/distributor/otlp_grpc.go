@@ -0,0 +1,86 @@
+package distributor
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+// otlpLogsServer receives the OTLP logs export requests sent over gRPC.
+type otlpLogsServer struct {
+	plogotlp.UnimplementedGRPCServer
+
+	d *Distributor
+}
+
+// OTLPLogsServer returns the OTLP LogsService gRPC server of the distributor. The logs are converted to streams with
+// the OTLP config of the tenant, like the logs pushed to the OTLP HTTP endpoint.
+func (d *Distributor) OTLPLogsServer() plogotlp.GRPCServer {
+	return &otlpLogsServer{d: d}
+}
+
+// Export pushes the logs of the request. The log records rejected by the validation are reported as a partial
+// success, as required by the OTLP specification.
+func (s *otlpLogsServer) Export(ctx context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	resp := plogotlp.NewExportResponse()
+	logger := util_log.WithContext(ctx, util_log.Logger)
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		level.Error(logger).Log("msg", "error getting tenant id", "err", err)
+		return resp, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	pushReq := push.ParseOTLPExportRequest(ctx, logger, tenantID, req, s.d.tenantsRetention, s.d.validator.Limits, s.d.usageTracker)
+
+	var rejected int
+	_, err = s.d.push(ctx, pushReq, &rejected)
+	if err == nil {
+		if s.d.tenantConfigs.LogPushRequest(tenantID) {
+			level.Debug(logger).Log("msg", "push request successful")
+		}
+		return resp, nil
+	}
+
+	code, msg := http.StatusInternalServerError, err.Error()
+	if httpResp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		code, msg = int(httpResp.Code), string(httpResp.Body)
+	}
+	if s.d.tenantConfigs.LogPushRequest(tenantID) {
+		level.Debug(logger).Log("msg", "push request failed", "code", code, "err", msg)
+	}
+
+	if code == http.StatusBadRequest && rejected > 0 {
+		resp.PartialSuccess().SetRejectedLogRecords(int64(rejected))
+		resp.PartialSuccess().SetErrorMessage(msg)
+		return resp, nil
+	}
+	return resp, status.Error(otlpStatusCode(code), msg)
+}
+
+// otlpStatusCode maps the HTTP status codes of the push errors to the gRPC status codes retried by the OTLP clients
+// when the HTTP status codes are retried by the OTLP HTTP clients.
+func otlpStatusCode(code int) codes.Code {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case code >= 500:
+		return codes.Unavailable
+	case code == http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case code == http.StatusForbidden:
+		return codes.PermissionDenied
+	case code >= 400:
+		return codes.InvalidArgument
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,82 @@
+package distributor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func newOTLPExportRequest(lines ...string) plogotlp.ExportRequest {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "api")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+	for _, line := range lines {
+		record := records.AppendEmpty()
+		record.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+		record.Body().SetStr(line)
+	}
+	return plogotlp.NewExportRequestFromLogs(ld)
+}
+
+func TestOTLPLogsServer(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.MaxLineSize = 10
+	limits.OTLPConfig = push.DefaultOTLPConfig(push.GlobalOTLPConfig{DefaultOTLPResourceAttributesAsIndexLabels: []string{"service.name"}})
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+	server := distributors[0].OTLPLogsServer()
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	resp, err := server.Export(ctx, newOTLPExportRequest("line 1", "line 2"))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), resp.PartialSuccess().RejectedLogRecords())
+	pushed := ingester.Peek()
+	require.Len(t, pushed.Streams, 1)
+	require.Equal(t, `{service_name="api"}`, pushed.Streams[0].Labels)
+	require.Len(t, pushed.Streams[0].Entries, 2)
+
+	// the log records rejected by the validation are a partial success.
+	resp, err = server.Export(ctx, newOTLPExportRequest("line 3", "too long line", "way too long line"))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), resp.PartialSuccess().RejectedLogRecords())
+	require.Contains(t, resp.PartialSuccess().ErrorMessage(), "Max entry size '10' bytes exceeded")
+
+	_, err = server.Export(context.Background(), newOTLPExportRequest("line"))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestOTLPLogsServerRateLimited(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.IngestionRateMB = 0.000001
+	limits.IngestionBurstSizeMB = 0.000001
+	limits.OTLPConfig = push.DefaultOTLPConfig(push.GlobalOTLPConfig{DefaultOTLPResourceAttributesAsIndexLabels: []string{"service.name"}})
+
+	distributors, _ := prepare(t, 1, 5, limits, nil)
+	_, err := distributors[0].OTLPLogsServer().Export(user.InjectOrgID(context.Background(), "test"), newOTLPExportRequest("line 1", "line 2"))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestOTLPStatusCode(t *testing.T) {
+	require.Equal(t, codes.ResourceExhausted, otlpStatusCode(429))
+	require.Equal(t, codes.Unavailable, otlpStatusCode(500))
+	require.Equal(t, codes.Unavailable, otlpStatusCode(503))
+	require.Equal(t, codes.InvalidArgument, otlpStatusCode(400))
+	require.Equal(t, codes.PermissionDenied, otlpStatusCode(403))
+}
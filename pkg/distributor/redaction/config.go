@@ -0,0 +1,95 @@
+package redaction
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+// Mode is what is done with the secrets found by a detector.
+type Mode string
+
+const (
+	// Mask replaces the secrets with the name of their detector.
+	Mask Mode = "mask"
+	// Hash replaces the secrets with a HMAC of the secrets, so equal secrets can still be correlated.
+	Hash Mode = "hash"
+	// Drop drops the entries containing secrets.
+	Drop Mode = "drop"
+)
+
+var errUnsupportedMode = fmt.Errorf("unsupported redaction mode, it must be one of: %s, %s, %s", Mask, Hash, Drop)
+
+// Config is the redaction config of a tenant.
+type Config struct {
+	Detectors []DetectorConfig `yaml:"detectors,omitempty" json:"detectors,omitempty" doc:"description=Detectors of the secrets to redact from the lines and structured metadata of the pushed entries. The built-in detectors are credit_card (Luhn-validated card numbers of the known card networks), email, jwt, aws_access_key and github_token, other detectors must set a regex. The mode of a detector is mask (the default), hash or drop, which drops the entries containing the secrets.\nExample:\n detectors:\n - name: credit_card\n - name: aws_access_key\n mode: drop\n - name: session_id\n regex: 'sid=[0-9a-f]{32}'\n mode: hash"`
+	HashKey   flagext.Secret   `yaml:"hash_key" json:"-" doc:"description=Key of the HMAC of the secrets redacted by the detectors in hash mode. It's hidden from the exported configs."`
+
+	detectors []*detector
+}
+
+// DetectorConfig is a detector of secrets.
+type DetectorConfig struct {
+	Name  string `yaml:"name" json:"name" doc:"description=Name of a built-in detector, or of a custom detector if the regex is set."`
+	Regex string `yaml:"regex,omitempty" json:"regex,omitempty" doc:"description=Regex matching the secrets of a custom detector."`
+	Mode  Mode   `yaml:"mode,omitempty" json:"mode,omitempty" doc:"description=What is done with the secrets: mask, hash or drop the entry. Defaults to mask."`
+}
+
+// Validate validates the detectors and compiles their regexes.
+func (c *Config) Validate() error {
+	if len(c.Detectors) == 0 {
+		c.detectors = nil
+		return nil
+	}
+
+	detectors := make([]*detector, 0, len(c.Detectors))
+	names := make(map[string]struct{}, len(c.Detectors))
+	for _, cfg := range c.Detectors {
+		if _, ok := names[cfg.Name]; ok {
+			return fmt.Errorf("duplicate redaction detector %q", cfg.Name)
+		}
+		names[cfg.Name] = struct{}{}
+
+		d, err := newDetector(cfg)
+		if err != nil {
+			return err
+		}
+		if d.mode == Hash && c.HashKey.String() == "" {
+			return fmt.Errorf("redaction detector %q: hash_key must be set in hash mode", cfg.Name)
+		}
+		detectors = append(detectors, d)
+	}
+	c.detectors = detectors
+	return nil
+}
+
+func newDetector(cfg DetectorConfig) (*detector, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("redaction detector name must be set")
+	}
+
+	mode := cfg.Mode
+	switch mode {
+	case "":
+		mode = Mask
+	case Mask, Hash, Drop:
+	default:
+		return nil, fmt.Errorf("redaction detector %q: %w", cfg.Name, errUnsupportedMode)
+	}
+
+	if cfg.Regex == "" {
+		builtin, ok := builtinDetectors[cfg.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction detector %q, custom detectors must set a regex", cfg.Name)
+		}
+		return &detector{name: cfg.Name, regex: builtin.regex, valid: builtin.valid, mode: mode}, nil
+	}
+
+	regex, err := regexp.Compile(cfg.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("redaction detector %q: invalid regex: %w", cfg.Name, err)
+	}
+	return &detector{name: cfg.Name, regex: regex, mode: mode}, nil
+}
@@ -0,0 +1,147 @@
+package redaction
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type detector struct {
+	name  string
+	regex *regexp.Regexp
+	// valid filters the matches of the regex, all of them are secrets if it's nil.
+	valid func(string) bool
+	mode  Mode
+}
+
+var builtinDetectors = map[string]detector{
+	// 13 to 19 digits, optionally grouped by spaces or dashes.
+	"credit_card": {
+		regex: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid: cardNumber,
+	},
+	"email": {
+		regex: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`),
+	},
+	// the base64url encoded JSON header and payload start with `{"`.
+	"jwt": {
+		regex: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	},
+	"aws_access_key": {
+		regex: regexp.MustCompile(`\b(?:AKIA|ASIA|AIDA|AROA)[A-Z0-9]{16}\b`),
+	},
+	"github_token": {
+		regex: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+}
+
+// find returns the indexes of the secrets of s. A match failing the validation can still contain a secret, such as a
+// spaced card number followed by other digits, so its groups separated by spaces or dashes are searched as well.
+func (d *detector) find(s string) [][]int {
+	matches := d.regex.FindAllStringIndex(s, -1)
+	if d.valid == nil {
+		return matches
+	}
+	var secrets [][]int
+	for _, m := range matches {
+		if d.valid(s[m[0]:m[1]]) {
+			secrets = append(secrets, m)
+			continue
+		}
+		secrets = d.findGroups(secrets, s, m[0], m[1])
+	}
+	return secrets
+}
+
+// findGroups appends the secrets of s[start:end] made of whole groups. From the first group, the longest valid run of
+// groups is a secret and the search resumes after it, the search resumes from the next group otherwise.
+func (d *detector) findGroups(secrets [][]int, s string, start, end int) [][]int {
+	for start < end {
+		e := end
+		for e > start && !d.valid(s[start:e]) {
+			e = start + max(strings.LastIndexAny(s[start:e], " -"), 0)
+		}
+		if e > start {
+			secrets = append(secrets, []int{start, e})
+			start = e
+		}
+		next := strings.IndexAny(s[start:end], " -")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return secrets
+}
+
+// cardNetwork is a range of issuer identification numbers, the leading digits of the card numbers of a network, and
+// the lengths of these card numbers.
+type cardNetwork struct {
+	low, high int
+	lengths   []int
+}
+
+// cardNetworks are the networks of the card numbers detected, the other numbers with a valid checksum are left as is
+// since about 1 in 10 of the nanosecond timestamps and numeric IDs of the logs have a valid checksum.
+var cardNetworks = []cardNetwork{
+	{low: 4, high: 4, lengths: []int{13, 16, 19}},                 // Visa
+	{low: 51, high: 55, lengths: []int{16}},                       // Mastercard
+	{low: 2221, high: 2720, lengths: []int{16}},                   // Mastercard
+	{low: 34, high: 34, lengths: []int{15}},                       // American Express
+	{low: 37, high: 37, lengths: []int{15}},                       // American Express
+	{low: 300, high: 305, lengths: []int{14, 15, 16, 17, 18, 19}}, // Diners Club
+	{low: 36, high: 36, lengths: []int{14, 15, 16, 17, 18, 19}},   // Diners Club
+	{low: 38, high: 39, lengths: []int{14, 15, 16, 17, 18, 19}},   // Diners Club
+	{low: 6011, high: 6011, lengths: []int{16, 17, 18, 19}},       // Discover
+	{low: 644, high: 649, lengths: []int{16, 17, 18, 19}},         // Discover
+	{low: 65, high: 65, lengths: []int{16, 17, 18, 19}},           // Discover
+	{low: 3528, high: 3589, lengths: []int{16, 17, 18, 19}},       // JCB
+	{low: 62, high: 62, lengths: []int{16, 17, 18, 19}},           // UnionPay
+}
+
+// cardNumber returns true if the number, ignoring spaces and dashes, is a number of a known card network with a
+// valid Luhn checksum.
+func cardNumber(number string) bool {
+	digits := make([]byte, 0, len(number))
+	for i := 0; i < len(number); i++ {
+		if c := number[i]; c != ' ' && c != '-' {
+			digits = append(digits, c)
+		}
+	}
+	for _, n := range cardNetworks {
+		if slices.Contains(n.lengths, len(digits)) && n.matches(digits) {
+			return luhn(number)
+		}
+	}
+	return false
+}
+
+// matches returns true if the leading digits of the number are in the range of the network.
+func (n cardNetwork) matches(digits []byte) bool {
+	var prefix int
+	for _, c := range digits[:len(strconv.Itoa(n.low))] {
+		prefix = prefix*10 + int(c-'0')
+	}
+	return prefix >= n.low && prefix <= n.high
+}
+
+// luhn returns true if the digits of the number, ignoring spaces and dashes, have a valid Luhn checksum.
+func luhn(number string) bool {
+	var sum, n int
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
@@ -0,0 +1,132 @@
+package redaction
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/distributor/ingestpipeline"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// Redactor redacts the secrets found by the detectors of a tenant from the lines and structured metadata values of
+// the entries. It's safe for concurrent use.
+type Redactor struct {
+	detectors []*detector
+	key       []byte
+}
+
+// Stats are the secrets found by a redactor per detector, and the number of entries dropped and mutated with
+// their bytes before the redaction.
+type Stats struct {
+	Detected                     map[string]int
+	DroppedEntries, DroppedBytes int
+	MutatedEntries, MutatedBytes int
+}
+
+func (s *Stats) detected(name string) {
+	if s.Detected == nil {
+		s.Detected = map[string]int{}
+	}
+	s.Detected[name]++
+}
+
+// New returns the redactor of the config, nil if the config has no detectors.
+func New(cfg Config) (*Redactor, error) {
+	if len(cfg.Detectors) == 0 {
+		return nil, nil
+	}
+	// the detectors are compiled by the validation of the limits, a config built otherwise is compiled here.
+	if cfg.detectors == nil {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &Redactor{detectors: cfg.detectors, key: []byte(cfg.HashKey.String())}, nil
+}
+
+// Redact redacts the entries of the stream. It returns the entries left, the entries with secrets detected in drop
+// mode are dropped. The redacted entries are updated in place.
+func (r *Redactor) Redact(entries []logproto.Entry, stats *Stats) []logproto.Entry {
+	return ingestpipeline.DropEntries(entries, func(entry *logproto.Entry) bool {
+		if !r.redact(entry, stats) {
+			return false
+		}
+		stats.DroppedEntries++
+		stats.DroppedBytes += len(entry.Line)
+		return true
+	})
+}
+
+// redact redacts the entry, it returns true if the entry must be dropped.
+func (r *Redactor) redact(entry *logproto.Entry, stats *Stats) bool {
+	line, drop := r.redactString(entry.Line, stats)
+	if drop {
+		return true
+	}
+	mutated := line != entry.Line
+
+	var metadata push.LabelsAdapter
+	for i, l := range entry.StructuredMetadata {
+		value, drop := r.redactString(l.Value, stats)
+		if drop {
+			return true
+		}
+		if value == l.Value {
+			continue
+		}
+		// the structured metadata of the request can be shared with other entries.
+		if metadata == nil {
+			metadata = make(push.LabelsAdapter, len(entry.StructuredMetadata))
+			copy(metadata, entry.StructuredMetadata)
+		}
+		metadata[i].Value = value
+	}
+
+	if mutated || metadata != nil {
+		stats.MutatedEntries++
+		stats.MutatedBytes += len(entry.Line)
+		entry.Line = line
+		if metadata != nil {
+			entry.StructuredMetadata = metadata
+		}
+	}
+	return false
+}
+
+// redactString returns the string with its secrets redacted, and true if a secret was detected in drop mode.
+func (r *Redactor) redactString(s string, stats *Stats) (string, bool) {
+	for _, d := range r.detectors {
+		secrets := d.find(s)
+		if len(secrets) == 0 {
+			continue
+		}
+		if d.mode == Drop {
+			stats.detected(d.name)
+			return s, true
+		}
+		var sb strings.Builder
+		last := 0
+		for _, secret := range secrets {
+			stats.detected(d.name)
+			sb.WriteString(s[last:secret[0]])
+			sb.WriteString(r.replacement(d, s[secret[0]:secret[1]]))
+			last = secret[1]
+		}
+		sb.WriteString(s[last:])
+		s = sb.String()
+	}
+	return s, false
+}
+
+func (r *Redactor) replacement(d *detector, secret string) string {
+	if d.mode == Hash {
+		mac := hmac.New(sha256.New, r.key)
+		mac.Write([]byte(secret))
+		return "<" + d.name + ":" + hex.EncodeToString(mac.Sum(nil)[:8]) + ">"
+	}
+	return "<" + d.name + ">"
+}
@@ -0,0 +1,87 @@
+package distributor
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+// SplunkHECConfig configures the Splunk HTTP Event Collector endpoint.
+type SplunkHECConfig struct {
+	Tokens []SplunkHECToken `yaml:"tokens" doc:"description=HEC tokens accepted by the Splunk HTTP Event Collector endpoint, with the tenant their events are pushed to. When tokens are set, the tenant of the requests is the tenant of their token instead of the authenticated tenant.\nExample:\n tokens:\n - token: 5f2e1b4c-0000-4000-8000-000000000000\n tenant: team-a"`
+}
+
+// SplunkHECToken maps a HEC token to a tenant.
+type SplunkHECToken struct {
+	Token  flagext.Secret `yaml:"token" doc:"description=HEC token sent by the clients in the Authorization header, as 'Splunk <token>'."`
+	Tenant string         `yaml:"tenant" doc:"description=Tenant the events sent with the token are pushed to."`
+}
+
+// Validate validates the HEC tokens.
+func (cfg *SplunkHECConfig) Validate() error {
+	tokens := make(map[string]struct{}, len(cfg.Tokens))
+	for _, t := range cfg.Tokens {
+		if t.Token.String() == "" || t.Tenant == "" {
+			return errors.New("splunk_hec tokens must set both a token and a tenant")
+		}
+		if _, ok := tokens[t.Token.String()]; ok {
+			return errors.New("splunk_hec tokens must be unique")
+		}
+		tokens[t.Token.String()] = struct{}{}
+	}
+	return nil
+}
+
+// TenantsFromTokens returns whether the tenant of the requests is the tenant of their HEC token.
+func (cfg *SplunkHECConfig) TenantsFromTokens() bool {
+	return len(cfg.Tokens) > 0
+}
+
+// tenant returns the tenant of a HEC token. The tokens are compared in constant time, and all of them are compared so
+// the time taken does not tell which token matched.
+func (cfg *SplunkHECConfig) tenant(token string) (string, bool) {
+	tenant, found := "", false
+	for _, t := range cfg.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Token.String()), []byte(token)) == 1 && !found {
+			tenant, found = t.Tenant, true
+		}
+	}
+	return tenant, found
+}
+
+// SplunkHECHandler pushes the events of a Splunk HTTP Event Collector request, mapped with the Splunk HEC mapping of
+// the tenant. If HEC tokens are configured, the events are pushed to the tenant of the token of the request.
+func (d *Distributor) SplunkHECHandler(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), util_log.Logger)
+	if d.cfg.SplunkHEC.TenantsFromTokens() {
+		token := push.SplunkHECToken(r)
+		if token == "" {
+			d.writeSplunkHECResponse(w, http.StatusUnauthorized, push.SplunkHECTokenRequired, "Token is required")
+			return
+		}
+		tenantID, ok := d.cfg.SplunkHEC.tenant(token)
+		if !ok {
+			level.Warn(logger).Log("msg", "invalid Splunk HEC token")
+			d.writeSplunkHECResponse(w, http.StatusForbidden, push.SplunkHECInvalidToken, "Invalid token")
+			return
+		}
+		r = r.WithContext(user.InjectOrgID(r.Context(), tenantID))
+	}
+
+	d.pushHandler(w, r, push.ParseSplunkHECRequest, func(w http.ResponseWriter, _ int) {
+		d.writeSplunkHECResponse(w, http.StatusOK, push.SplunkHECSuccess, "Success")
+	})
+}
+
+func (d *Distributor) writeSplunkHECResponse(w http.ResponseWriter, status, code int, text string) {
+	if err := push.WriteSplunkHECResponse(w, status, code, text); err != nil {
+		level.Error(util_log.Logger).Log("msg", "error writing Splunk HEC response", "err", err)
+	}
+}
@@ -0,0 +1,71 @@
+package distributor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestSplunkHECHandler(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectOldSamples = false
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+	d := distributors[0]
+	d.cfg.SplunkHEC = SplunkHECConfig{Tokens: []SplunkHECToken{{Token: flagext.SecretWithValue("token-a"), Tenant: "team-a"}}}
+
+	push := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/services/collector/event", strings.NewReader(`{"time":1714557600,"host":"h1","event":"line"}`))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		d.SplunkHECHandler(w, req)
+		return w
+	}
+
+	w := push("Splunk token-a")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"text":"Success","code":0}`, w.Body.String())
+	pushed := ingester.Peek()
+	require.Len(t, pushed.Streams, 1)
+	require.Equal(t, `{host="h1", service_name="unknown_service"}`, pushed.Streams[0].Labels)
+	require.Equal(t, "line", pushed.Streams[0].Entries[0].Line)
+
+	w = push("")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.JSONEq(t, `{"text":"Token is required","code":2}`, w.Body.String())
+
+	w = push("Splunk token-b")
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.JSONEq(t, `{"text":"Invalid token","code":4}`, w.Body.String())
+}
+
+func TestSplunkHECConfigValidate(t *testing.T) {
+	require.NoError(t, (&SplunkHECConfig{}).Validate())
+	require.NoError(t, (&SplunkHECConfig{Tokens: []SplunkHECToken{{Token: flagext.SecretWithValue("a"), Tenant: "t"}, {Token: flagext.SecretWithValue("b"), Tenant: "t"}}}).Validate())
+	require.Error(t, (&SplunkHECConfig{Tokens: []SplunkHECToken{{Token: flagext.SecretWithValue("a")}}}).Validate())
+	require.Error(t, (&SplunkHECConfig{Tokens: []SplunkHECToken{{Token: flagext.SecretWithValue("a"), Tenant: "t"}, {Token: flagext.SecretWithValue("a"), Tenant: "u"}}}).Validate())
+}
+
+func TestSplunkHECConfigHidesTokens(t *testing.T) {
+	var cfg SplunkHECConfig
+	require.NoError(t, yaml.Unmarshal([]byte("tokens:\n- token: token-a\n  tenant: team-a\n"), &cfg))
+	tenant, ok := cfg.tenant("token-a")
+	require.True(t, ok)
+	require.Equal(t, "team-a", tenant)
+
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NotContains(t, string(out), "token-a")
+}
@@ -0,0 +1,89 @@
+package distributor
+
+import (
+	"sync"
+
+	"github.com/grafana/loki/v3/pkg/distributor/ingestpipeline"
+	"github.com/grafana/loki/v3/pkg/distributor/redaction"
+)
+
+// tenantPipelines caches the ingest pipelines and redactors built from the configs of the tenants. The configs are
+// validated when the overrides are loaded, and a reload gives the tenants new configs, so the cached pipelines and
+// redactor of a tenant are rebuilt when its configs aren't the ones they were built from.
+type tenantPipelines struct {
+	mtx     sync.RWMutex
+	tenants map[string]*tenantPipeline
+}
+
+type tenantPipeline struct {
+	ingestPipelineCfg ingestpipeline.Config
+	redactionCfg      redaction.Config
+
+	// pipelines are the ingest pipelines built from the config, they are stateful so each push takes its own.
+	pipelines sync.Pool
+	redactor  *redaction.Redactor
+	err       error
+}
+
+func newTenantPipelines() *tenantPipelines {
+	return &tenantPipelines{tenants: map[string]*tenantPipeline{}}
+}
+
+// get returns the cached pipelines of the configs of the tenant.
+func (t *tenantPipelines) get(tenantID string, ingestPipeline ingestpipeline.Config, redactionCfg redaction.Config) *tenantPipeline {
+	t.mtx.RLock()
+	p, ok := t.tenants[tenantID]
+	t.mtx.RUnlock()
+	if ok && p.builtFrom(ingestPipeline, redactionCfg) {
+		return p
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if p, ok := t.tenants[tenantID]; ok && p.builtFrom(ingestPipeline, redactionCfg) {
+		return p
+	}
+
+	p = &tenantPipeline{ingestPipelineCfg: ingestPipeline, redactionCfg: redactionCfg}
+	var pipeline *ingestpipeline.Pipeline
+	if pipeline, p.err = ingestpipeline.New(ingestPipeline); p.err == nil && pipeline != nil {
+		p.pipelines.Put(pipeline)
+		p.pipelines.New = func() any {
+			// the config has been built once already, it can't fail.
+			pipeline, _ := ingestpipeline.New(ingestPipeline)
+			return pipeline
+		}
+	}
+	if p.err == nil {
+		p.redactor, p.err = redaction.New(redactionCfg)
+	}
+	t.tenants[tenantID] = p
+	return p
+}
+
+// builtFrom returns true if the pipelines were built from the configs.
+func (p *tenantPipeline) builtFrom(ingestPipeline ingestpipeline.Config, redactionCfg redaction.Config) bool {
+	return sameSlice(p.ingestPipelineCfg, ingestPipeline) && sameSlice(p.redactionCfg.Detectors, redactionCfg.Detectors) && p.redactionCfg.HashKey == redactionCfg.HashKey
+}
+
+// ingestPipeline returns an ingest pipeline of the tenant, nil if the tenant has none. It must be released with
+// releaseIngestPipeline once the push is processed.
+func (p *tenantPipeline) ingestPipeline() *ingestpipeline.Pipeline {
+	if len(p.ingestPipelineCfg) == 0 {
+		return nil
+	}
+	return p.pipelines.Get().(*ingestpipeline.Pipeline)
+}
+
+func (p *tenantPipeline) releaseIngestPipeline(pipeline *ingestpipeline.Pipeline) {
+	if pipeline == nil {
+		return
+	}
+	pipeline.Reset()
+	p.pipelines.Put(pipeline)
+}
+
+// sameSlice returns true if the slices share their elements, which are the configs loaded by the same overrides.
+func sameSlice[T any](a, b []T) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
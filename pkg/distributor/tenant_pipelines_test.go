@@ -0,0 +1,39 @@
+package distributor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/distributor/ingestpipeline"
+	"github.com/grafana/loki/v3/pkg/distributor/redaction"
+)
+
+func TestTenantPipelines(t *testing.T) {
+	pipelineCfg := ingestpipeline.Config{{Drop: &ingestpipeline.DropConfig{Selector: `{app="api"} |= "debug"`}}}
+	require.NoError(t, pipelineCfg.Validate())
+	redactionCfg := redaction.Config{Detectors: []redaction.DetectorConfig{{Name: "email"}}}
+	require.NoError(t, redactionCfg.Validate())
+
+	cache := newTenantPipelines()
+	p := cache.get("fake", pipelineCfg, redactionCfg)
+	require.NoError(t, p.err)
+	require.NotNil(t, p.redactor)
+	pipeline := p.ingestPipeline()
+	require.NotNil(t, pipeline)
+	p.releaseIngestPipeline(pipeline)
+
+	// the same configs give the cached pipelines.
+	require.Same(t, p, cache.get("fake", pipelineCfg, redactionCfg))
+
+	// reloaded configs are rebuilt, and their errors are cached.
+	reloaded := ingestpipeline.Config{{Drop: &ingestpipeline.DropConfig{Selector: `{app=`}}}
+	p = cache.get("fake", reloaded, redactionCfg)
+	require.Error(t, p.err)
+	require.Same(t, p, cache.get("fake", reloaded, redactionCfg))
+
+	p = cache.get("other", nil, redaction.Config{})
+	require.NoError(t, p.err)
+	require.Nil(t, p.ingestPipeline())
+	require.Nil(t, p.redactor)
+}
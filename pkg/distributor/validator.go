@@ -9,6 +9,8 @@ import (
 
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/loki/v3/pkg/distributor/ingestpipeline"
+	"github.com/grafana/loki/v3/pkg/distributor/redaction"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/validation"
@@ -53,6 +55,9 @@ type validationContext struct {
 	blockIngestionUntil      time.Time
 	blockIngestionStatusCode int
 
+	ingestPipeline ingestpipeline.Config
+	redaction      redaction.Config
+
 	userID string
 }
 
@@ -75,6 +80,8 @@ func (v Validator) getValidationContextForTime(now time.Time, userID string) val
 		maxStructuredMetadataCount:   v.MaxStructuredMetadataCount(userID),
 		blockIngestionUntil:          v.BlockIngestionUntil(userID),
 		blockIngestionStatusCode:     v.BlockIngestionStatusCode(userID),
+		ingestPipeline:               v.IngestPipeline(userID),
+		redaction:                    v.Redaction(userID),
 	}
 }
 
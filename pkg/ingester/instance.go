@@ -451,6 +451,16 @@ func (i *instance) query(ctx context.Context, req logql.SelectLogParams) (iter.E
 		return nil, err
 	}
 
+	expr, err = logql.OptimizePipelines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err = logql.RecordPipelineStages(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+
 	pipeline, err := expr.Pipeline()
 	if err != nil {
 		return nil, err
@@ -511,6 +521,16 @@ func (i *instance) querySample(ctx context.Context, req logql.SelectSampleParams
 		return nil, err
 	}
 
+	expr, err = logql.OptimizePipelines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err = logql.RecordPipelineStages(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+
 	extractor, err := expr.Extractor()
 	if err != nil {
 		return nil, err
@@ -194,22 +194,20 @@ func (t *tailer) processStream(stream logproto.Stream, lbs labels.Labels) []*log
 
 	sp := t.pipeline.ForStream(lbs)
 	for _, e := range stream.Entries {
-		newLine, parsedLbs, ok := sp.ProcessString(e.Timestamp.UnixNano(), e.Line, logproto.FromLabelAdaptersToLabels(e.StructuredMetadata)...)
-		if !ok {
-			continue
-		}
-		var stream *logproto.Stream
-		if stream, ok = streams[parsedLbs.Hash()]; !ok {
-			stream = &logproto.Stream{
-				Labels: parsedLbs.String(),
+		log.ProcessEach(sp, e.Timestamp.UnixNano(), e.Line, logproto.FromLabelAdaptersToLabels(e.StructuredMetadata), func(_ int, newLine string, parsedLbs log.LabelsResult) {
+			stream, ok := streams[parsedLbs.Hash()]
+			if !ok {
+				stream = &logproto.Stream{
+					Labels: parsedLbs.String(),
+				}
+				streams[parsedLbs.Hash()] = stream
 			}
-			streams[parsedLbs.Hash()] = stream
-		}
-		stream.Entries = append(stream.Entries, logproto.Entry{
-			Timestamp:          e.Timestamp,
-			Line:               newLine,
-			StructuredMetadata: logproto.FromLabelsToLabelAdapters(parsedLbs.StructuredMetadata()),
-			Parsed:             logproto.FromLabelsToLabelAdapters(parsedLbs.Parsed()),
+			stream.Entries = append(stream.Entries, logproto.Entry{
+				Timestamp:          e.Timestamp,
+				Line:               newLine,
+				StructuredMetadata: logproto.FromLabelsToLabelAdapters(parsedLbs.StructuredMetadata()),
+				Parsed:             logproto.FromLabelsToLabelAdapters(parsedLbs.Parsed()),
+			})
 		})
 	}
 	streamsResult := make([]*logproto.Stream, 0, len(streams))
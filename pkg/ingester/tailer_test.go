@@ -387,3 +387,27 @@ func Benchmark_isClosed(t *testing.B) {
 	tail.close()
 	require.Equal(t, true, tail.isClosed())
 }
+
+func Test_ExplodedLines(t *testing.T) {
+	t.Parallel()
+	lbs := labels.FromStrings("app", "foo")
+	expr, err := syntax.ParseLogSelector(`{app="foo"} | json_array "errors" | json`, true)
+	require.NoError(t, err)
+	tail, err := newTailer("foo", expr, &fakeTailServer{}, 10)
+	require.NoError(t, err)
+
+	streams := tail.processStream(logproto.Stream{
+		Labels: lbs.String(),
+		Entries: []logproto.Entry{
+			{Timestamp: time.Unix(0, 1), Line: `{"errors":[{"code":"E1"},{"code":"E2"}]}`},
+		},
+	}, lbs)
+
+	var lines []string
+	for _, stream := range streams {
+		for _, e := range stream.Entries {
+			lines = append(lines, e.Line)
+		}
+	}
+	require.ElementsMatch(t, []string{`{"code":"E1"}`, `{"code":"E2"}`}, lines)
+}
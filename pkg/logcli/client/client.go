@@ -38,6 +38,7 @@ const (
 	volumePath         = "/loki/api/v1/index/volume"
 	volumeRangePath    = "/loki/api/v1/index/volume_range"
 	detectedFieldsPath = "/loki/api/v1/detected_fields"
+	queryHistoryPath   = "/loki/api/v1/query_history"
 	defaultAuthHeader  = "Authorization"
 
 	// HTTP header keys
@@ -45,6 +46,8 @@ const (
 	HTTPQueryTags           = "X-Query-Tags"
 	HTTPCacheControl        = "Cache-Control"
 	HTTPCacheControlNoCache = "no-cache"
+	HTTPExplain             = "X-Loki-Explain"
+	HTTPPipelineStats       = "X-Loki-Pipeline-Stats"
 )
 
 var userAgent = fmt.Sprintf("loki-logcli/%s", build.Version)
@@ -62,6 +65,7 @@ type Client interface {
 	GetVolume(query *volume.Query) (*loghttp.QueryResponse, error)
 	GetVolumeRange(query *volume.Query) (*loghttp.QueryResponse, error)
 	GetDetectedFields(queryStr string, fieldLimit, lineLimit int, start, end time.Time, step time.Duration, quiet bool) (*loghttp.DetectedFieldsResponse, error)
+	GetQueryHistory(limit int, start, end time.Time, quiet bool) (*loghttp.QueryHistoryResponse, error)
 }
 
 // Tripperware can wrap a roundtripper.
@@ -84,6 +88,8 @@ type DefaultClient struct {
 	Retries         int
 	QueryTags       string
 	NoCache         bool
+	Explain         string
+	PipelineStats   bool
 	AuthHeader      string
 	ProxyURL        string
 	BackoffConfig   BackoffConfig
@@ -258,6 +264,20 @@ func (c *DefaultClient) GetDetectedFields(
 	return &r, nil
 }
 
+// GetQueryHistory uses the /loki/api/v1/query_history endpoint to list the queries executed by the query frontend
+func (c *DefaultClient) GetQueryHistory(limit int, start, end time.Time, quiet bool) (*loghttp.QueryHistoryResponse, error) {
+	params := util.NewQueryStringBuilder()
+	params.SetInt32("limit", limit)
+	params.SetInt("start", start.UnixNano())
+	params.SetInt("end", end.UnixNano())
+
+	var resp loghttp.QueryHistoryResponse
+	if err := c.doRequest(queryHistoryPath, params.Encode(), quiet, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 func (c *DefaultClient) doQuery(
 	path string,
 	query string,
@@ -390,6 +410,14 @@ func (c *DefaultClient) getHTTPRequestHeader() (http.Header, error) {
 		h.Set(HTTPQueryTags, c.QueryTags)
 	}
 
+	if c.Explain != "" {
+		h.Set(HTTPExplain, c.Explain)
+	}
+
+	if c.PipelineStats {
+		h.Set(HTTPPipelineStats, "true")
+	}
+
 	if (c.Username != "" || c.Password != "") && (len(c.BearerToken) > 0 || len(c.BearerTokenFile) > 0) {
 		return nil, fmt.Errorf("at most one of HTTP basic auth (username/password), bearer-token & bearer-token-file is allowed to be configured")
 	}
@@ -217,6 +217,10 @@ func (f *FileClient) GetDetectedFields(
 	return nil, ErrNotSupported
 }
 
+func (f *FileClient) GetQueryHistory(_ int, _, _ time.Time, _ bool) (*loghttp.QueryHistoryResponse, error) {
+	return nil, ErrNotSupported
+}
+
 type limiter struct {
 	n int
 }
@@ -237,6 +241,10 @@ func (l *limiter) BlockedQueries(_ context.Context, _ string) []*validation.Bloc
 	return []*validation.BlockedQuery{}
 }
 
+func (l *limiter) MaxQueryJoinWindowEntries(_ context.Context, _ string) int {
+	return 0
+}
+
 func (l *limiter) RequiredLabels(_ context.Context, _ string) []string {
 	return nil
 }
@@ -285,24 +293,21 @@ func newFileIterator(
 
 	processLine := func(line string) {
 		ts := time.Now()
-		parsedLine, parsedLabels, matches := pipeline.ProcessString(ts.UnixNano(), line)
-		if !matches {
-			return
-		}
-
-		var stream *logproto.Stream
-		lhash := parsedLabels.Hash()
-		var ok bool
-		if stream, ok = streams[lhash]; !ok {
-			stream = &logproto.Stream{
-				Labels: parsedLabels.String(),
+		logqllog.ProcessEach(pipeline, ts.UnixNano(), line, nil, func(_ int, parsedLine string, parsedLabels logqllog.LabelsResult) {
+			var stream *logproto.Stream
+			lhash := parsedLabels.Hash()
+			var ok bool
+			if stream, ok = streams[lhash]; !ok {
+				stream = &logproto.Stream{
+					Labels: parsedLabels.String(),
+				}
+				streams[lhash] = stream
 			}
-			streams[lhash] = stream
-		}
 
-		stream.Entries = append(stream.Entries, logproto.Entry{
-			Timestamp: ts,
-			Line:      parsedLine,
+			stream.Entries = append(stream.Entries, logproto.Entry{
+				Timestamp: ts,
+				Line:      parsedLine,
+			})
 		})
 	}
 
@@ -0,0 +1,107 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logcli/client"
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+)
+
+// Query lists the queries recorded in the query history and optionally replays them.
+type Query struct {
+	Start time.Time
+	End   time.Time
+	Limit int
+	Quiet bool
+
+	// Replay executes the recorded queries again and compares their duration and bytes processed.
+	Replay bool
+	// ShiftToNow moves the time range of the replayed queries so they end at the time they are replayed.
+	ShiftToNow bool
+}
+
+// Do lists or replays the recorded queries, the oldest first.
+func (q *Query) Do(c client.Client, w io.Writer, outputMode string) {
+	resp, err := c.GetQueryHistory(q.Limit, q.Start, q.End, q.Quiet)
+	if err != nil {
+		log.Fatalf("Error doing request: %+v", err)
+	}
+
+	records := resp.Data
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	if q.Replay {
+		q.replay(c, w, records)
+		return
+	}
+
+	switch outputMode {
+	case "raw", "jsonl":
+		enc := json.NewEncoder(w)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				log.Fatalf("Error marshalling record: %+v", err)
+			}
+		}
+	default:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIMESTAMP\tTYPE\tRANGE\tDURATION\tBYTES PROCESSED\tSTATUS\tUSER AGENT\tQUERY")
+		for _, record := range records {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+				record.Timestamp.Format(time.RFC3339), record.Type, record.End.Sub(record.Start), record.Duration,
+				record.BytesProcessed, record.Status, record.UserAgent, record.Query)
+		}
+		tw.Flush()
+	}
+}
+
+// replay executes the recorded queries one after the other and prints their recorded and replayed statistics.
+func (q *Query) replay(c client.Client, w io.Writer, records []loghttp.QueryHistoryRecord) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIMESTAMP\tRECORDED DURATION\tREPLAYED DURATION\tRECORDED BYTES\tREPLAYED BYTES\tERROR\tQUERY")
+	for _, record := range records {
+		start := time.Now()
+		replayed, err := replayRecord(c, record, q.ShiftToNow, start)
+		duration := time.Since(start)
+
+		var bytes int64
+		var errMsg string
+		if err != nil {
+			errMsg = strings.ReplaceAll(err.Error(), "\n", " ")
+		} else {
+			bytes = replayed.Data.Statistics.Summary.TotalBytesProcessed
+			if replayed.Data.Statistics.Summary.ExecTime > 0 {
+				duration = time.Duration(replayed.Data.Statistics.Summary.ExecTime * float64(time.Second))
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			record.Timestamp.Format(time.RFC3339), record.Duration, duration, record.BytesProcessed, bytes, errMsg, record.Query)
+	}
+	tw.Flush()
+}
+
+func replayRecord(c client.Client, record loghttp.QueryHistoryRecord, shiftToNow bool, now time.Time) (*loghttp.QueryResponse, error) {
+	start, end := record.Start, record.End
+	if shiftToNow {
+		start, end = now.Add(-end.Sub(start)), now
+	}
+	direction := logproto.BACKWARD
+	if d, ok := logproto.Direction_value[record.Direction]; ok {
+		direction = logproto.Direction(d)
+	}
+
+	if record.Type == string(logql.InstantType) {
+		return c.Query(record.Query, int(record.Limit), end, direction, true)
+	}
+	return c.QueryRange(record.Query, int(record.Limit), start, end, direction, record.Step, 0, true)
+}
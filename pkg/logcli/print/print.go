@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logcli/output"
 	"github.com/grafana/loki/v3/pkg/logcli/util"
 	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 )
@@ -212,6 +213,14 @@ func (r *QueryResultPrinter) PrintStats(stats stats.Result) {
 	stats.Log(kvLogger{Writer: writer})
 }
 
+// PrintAnalysis prints the per-node statistics of a query executed with explain=analyze, if any.
+func (r *QueryResultPrinter) PrintAnalysis(stats stats.Result) {
+	if stats.Analysis == nil {
+		return
+	}
+	fmt.Fprint(os.Stderr, logql.PrintAnalysis(stats.Analysis))
+}
+
 func matchLabels(on bool, l loghttp.LabelSet, names []string) loghttp.LabelSet {
 	return util.MatchLabels(on, l, names)
 }
@@ -128,6 +128,7 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 		if statistics {
 			result.PrintStats(resp.Data.Statistics)
 		}
+		result.PrintAnalysis(resp.Data.Statistics)
 		_, _ = result.PrintResult(resp.Data.Result, out, nil)
 	} else {
 		unlimited := q.Limit == 0
@@ -159,6 +160,7 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 			if statistics {
 				result.PrintStats(resp.Data.Statistics)
 			}
+			result.PrintAnalysis(resp.Data.Statistics)
 
 			resultLength, lastEntry = result.PrintResult(resp.Data.Result, out, lastEntry)
 			// Was not a log stream query, or no results, no more batching
@@ -0,0 +1,63 @@
+package loghttp
+
+import (
+	"net/http"
+	"time"
+)
+
+// QueryHistoryRecord is a query executed by the query frontend, as recorded in the query history.
+type QueryHistoryRecord struct {
+	// Timestamp is when the query completed.
+	Timestamp time.Time `json:"timestamp"`
+	Query     string    `json:"query"`
+	// Type is either range or instant.
+	Type      string        `json:"type"`
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end"`
+	Step      time.Duration `json:"step,omitempty"`
+	Limit     uint32        `json:"limit,omitempty"`
+	Direction string        `json:"direction,omitempty"`
+
+	// Duration is the execution time of the query.
+	Duration       time.Duration `json:"duration"`
+	BytesProcessed int64         `json:"bytes_processed"`
+	LinesProcessed int64         `json:"lines_processed"`
+	// Status is the HTTP status code of the response.
+	Status    string `json:"status"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// QueryHistoryResponse represents the http json response to a query history request.
+type QueryHistoryResponse struct {
+	Status string               `json:"status"`
+	Data   []QueryHistoryRecord `json:"data"`
+}
+
+// QueryHistoryRequest selects the most recent records of the query history completed between start and end.
+type QueryHistoryRequest struct {
+	Start time.Time
+	End   time.Time
+	Limit uint32
+}
+
+// ParseQueryHistoryQuery parses a query history request.
+func ParseQueryHistoryQuery(r *http.Request) (*QueryHistoryRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	req := &QueryHistoryRequest{}
+	var err error
+	req.Start, req.End, err = bounds(r)
+	if err != nil {
+		return nil, err
+	}
+	if !req.End.After(req.Start) {
+		return nil, errEndBeforeStart
+	}
+	req.Limit, err = limit(r)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
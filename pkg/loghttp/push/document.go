@@ -0,0 +1,228 @@
+package push
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage/remote/otlptranslator/prometheus"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// DocumentMapping maps the fields of the JSON documents pushed to the Elasticsearch and Splunk HEC endpoints to the
+// stream labels, structured metadata, timestamp and line of the entries. Nested fields are addressed by their path,
+// like kubernetes.pod.name, and are named after their normalized path. The metadata fields of the requests, like the
+// _index of the Elasticsearch documents, are mapped like the fields of the documents and named without their leading
+// underscore, they are only stored when mapped.
+type DocumentMapping struct {
+	LineField          string                 `yaml:"line_field" json:"line_field"`
+	TimestampField     string                 `yaml:"timestamp_field" json:"timestamp_field"`
+	IndexLabels        flagext.StringSliceCSV `yaml:"index_labels" json:"index_labels"`
+	StructuredMetadata flagext.StringSliceCSV `yaml:"structured_metadata" json:"structured_metadata"`
+}
+
+// RegisterFlagsWithPrefix registers the flags of the mapping, with the defaults of the endpoint.
+func (m *DocumentMapping) RegisterFlagsWithPrefix(prefix string, defaults DocumentMapping, f *flag.FlagSet) {
+	m.IndexLabels = append(flagext.StringSliceCSV(nil), defaults.IndexLabels...)
+	m.StructuredMetadata = append(flagext.StringSliceCSV(nil), defaults.StructuredMetadata...)
+	f.StringVar(&m.LineField, prefix+".line-field", defaults.LineField, "Field of the documents stored as the log line. If the documents don't have this field, the line is the JSON of the fields not mapped to the labels, structured metadata or timestamp, the other fields are dropped otherwise.")
+	f.StringVar(&m.TimestampField, prefix+".timestamp-field", defaults.TimestampField, "Field of the documents holding their timestamp, as RFC3339 or as a number. The documents without this field are timestamped when they are received.")
+	f.Var(&m.IndexLabels, prefix+".index-labels", "Comma separated fields of the documents stored as stream labels.")
+	f.Var(&m.StructuredMetadata, prefix+".structured-metadata", "Comma separated fields of the documents stored as structured metadata.")
+}
+
+// documentToEntry maps a document to the labels and entry of its stream. Numeric timestamps are converted with unit,
+// the duration of one unit of the timestamps.
+func (m DocumentMapping) documentToEntry(doc document, unit time.Duration, now time.Time) (labels.Labels, logproto.Entry, error) {
+	entry := logproto.Entry{Timestamp: now}
+	if m.TimestampField != "" {
+		if value, _, ok := doc.take(m.TimestampField); ok {
+			ts, err := parseDocumentTimestamp(value, unit)
+			if err != nil {
+				return nil, entry, fmt.Errorf("invalid timestamp field %q: %w", m.TimestampField, err)
+			}
+			entry.Timestamp = ts
+		}
+	}
+
+	lb := labels.NewScratchBuilder(len(m.IndexLabels))
+	for _, field := range m.IndexLabels {
+		if value, name, ok := doc.take(field); ok {
+			if s := fieldString(value); s != "" {
+				lb.Add(name, s)
+			}
+		}
+	}
+	lb.Sort()
+
+	for _, field := range m.StructuredMetadata {
+		if value, name, ok := doc.take(field); ok {
+			if s := fieldString(value); s != "" {
+				entry.StructuredMetadata = append(entry.StructuredMetadata, push.LabelAdapter{Name: name, Value: s})
+			}
+		}
+	}
+
+	if m.LineField != "" {
+		if value, _, ok := doc.take(m.LineField); ok {
+			entry.Line = fieldString(value)
+			return lb.Labels(), entry, nil
+		}
+	}
+	line, err := json.Marshal(doc.fields)
+	if err != nil {
+		return nil, entry, err
+	}
+	entry.Line = string(line)
+	return lb.Labels(), entry, nil
+}
+
+// document is a JSON document pushed to the Elasticsearch or Splunk HEC endpoints, with the metadata fields of its
+// request. The metadata fields are never stored in the JSON line of the documents without a line field.
+type document struct {
+	fields   map[string]any
+	metadata map[string]any
+}
+
+// take removes the field at the path from the document and returns its value and label name, falling back to the
+// metadata field named path.
+func (d document) take(path string) (any, string, bool) {
+	if value, ok := takeField(d.fields, path); ok {
+		return value, prometheus.NormalizeLabel(path), true
+	}
+	value, ok := d.metadata[path]
+	return value, prometheus.NormalizeLabel(strings.TrimPrefix(path, "_")), ok
+}
+
+// takeField removes the field at the path from the document and returns its value. The keys of the document can
+// contain dots themselves, like the fields flattened by the shippers.
+func takeField(doc map[string]any, path string) (any, bool) {
+	if value, ok := doc[path]; ok {
+		delete(doc, path)
+		return value, true
+	}
+	for i := 0; i < len(path); i++ {
+		if path[i] != '.' {
+			continue
+		}
+		nested, ok := doc[path[:i]].(map[string]any)
+		if !ok {
+			continue
+		}
+		value, ok := takeField(nested, path[i+1:])
+		if !ok {
+			continue
+		}
+		if len(nested) == 0 {
+			delete(doc, path[:i])
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// fieldString returns the value of a field as a string, objects and arrays are encoded as JSON.
+func fieldString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// parseDocumentTimestamp parses a RFC3339 timestamp, or a number of units since the epoch. The numbers are parsed as
+// decimals so their fractional units are exact, and are rejected when they don't fit in nanoseconds since the epoch.
+func parseDocumentTimestamp(value any, unit time.Duration) (time.Time, error) {
+	s := fieldString(value)
+	if r, ok := new(big.Rat).SetString(s); ok {
+		r.Mul(r, new(big.Rat).SetInt64(int64(unit)))
+		ns := new(big.Int).Quo(r.Num(), r.Denom())
+		if !ns.IsInt64() {
+			return time.Time{}, fmt.Errorf("timestamp %s is out of range", s)
+		}
+		return time.Unix(0, ns.Int64()), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// documentStreams groups the entries of the documents by stream, in the order of the documents.
+type documentStreams struct {
+	streams []logproto.Stream
+	index   map[string]int
+}
+
+func (s *documentStreams) add(lbs labels.Labels, entry logproto.Entry) {
+	if s.index == nil {
+		s.index = map[string]int{}
+	}
+	key := lbs.String()
+	i, ok := s.index[key]
+	if !ok {
+		i = len(s.streams)
+		s.index[key] = i
+		s.streams = append(s.streams, logproto.Stream{Labels: key})
+	}
+	s.streams[i].Entries = append(s.streams[i].Entries, entry)
+}
+
+// parseDocumentsRequest parses the JSON documents of a push request with the mapping. The documents are decoded
+// by decodeDocument, which returns io.EOF once all the documents are decoded.
+func parseDocumentsRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker, mapping DocumentMapping, unit time.Duration, decodeDocument func(*json.Decoder) (document, error)) (*logproto.PushRequest, *Stats, error) {
+	body, bodySize, contentEncoding, closeBody, err := requestBody(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeBody()
+
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+
+	var streams documentStreams
+	now := time.Now()
+	for {
+		doc, err := decodeDocument(decoder)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		lbs, entry, err := mapping.documentToEntry(doc, unit, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		streams.add(lbs, entry)
+	}
+
+	req := &logproto.PushRequest{Streams: streams.streams}
+	pushStats := newPushStats()
+	pushStats.BodySize = bodySize.Size()
+	pushStats.ContentType = r.Header.Get(contentType)
+	pushStats.ContentEncoding = contentEncoding
+	if err := streamsStats(r.Context(), userID, req, pushStats, tenantsRetention, limits, tracker); err != nil {
+		return nil, nil, err
+	}
+	return req, pushStats, nil
+}
@@ -0,0 +1,112 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// ElasticsearchIndexField is the metadata field of the Elasticsearch documents holding their index, which can be
+// mapped to the index stream label or structured metadata like the fields of the documents.
+const ElasticsearchIndexField = "_index"
+
+// DefaultElasticsearchMapping maps the documents following the Elastic Common Schema: the message field is the line
+// and the @timestamp field is the timestamp, numeric timestamps being milliseconds since the epoch.
+var DefaultElasticsearchMapping = DocumentMapping{
+	LineField:      "message",
+	TimestampField: "@timestamp",
+}
+
+// ElasticsearchBulkAction is the action of a document of an Elasticsearch _bulk request.
+type ElasticsearchBulkAction struct {
+	// Operation is index or create.
+	Operation string
+	// Index is the _index of the action, or the index of the request path if the action has none.
+	Index string
+}
+
+// ParseElasticsearchBulkRequest parses the NDJSON body of an Elasticsearch _bulk request. Each index or create action
+// is followed by a document, which is mapped to an entry with the Elasticsearch mapping of the tenant. The other
+// actions are rejected, the pushed entries can't be updated or deleted. The actions are returned in the order of the
+// request, to be reported in the response.
+func ParseElasticsearchBulkRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, []ElasticsearchBulkAction, error) {
+	defaultIndex := mux.Vars(r)["index"]
+	var actions []ElasticsearchBulkAction
+	req, stats, err := parseDocumentsRequest(userID, r, tenantsRetention, limits, tracker, limits.ElasticsearchMapping(userID), time.Millisecond, func(decoder *json.Decoder) (document, error) {
+		n := len(actions)
+		var action map[string]struct {
+			Index string `json:"_index"`
+		}
+		if err := decoder.Decode(&action); err != nil {
+			if err == io.EOF {
+				return document{}, err
+			}
+			return document{}, fmt.Errorf("invalid bulk action %d: %w", n, err)
+		}
+		if len(action) != 1 {
+			return document{}, fmt.Errorf("invalid bulk action %d: it must have exactly one operation", n)
+		}
+		a := ElasticsearchBulkAction{Index: defaultIndex}
+		for op, meta := range action {
+			if op != "index" && op != "create" {
+				return document{}, fmt.Errorf("unsupported bulk action %q, only index and create actions are supported", op)
+			}
+			a.Operation = op
+			if meta.Index != "" {
+				a.Index = meta.Index
+			}
+		}
+
+		var doc map[string]any
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return document{}, fmt.Errorf("invalid bulk document %d: %w", n, err)
+		}
+		actions = append(actions, a)
+
+		var metadata map[string]any
+		if a.Index != "" {
+			metadata = map[string]any{ElasticsearchIndexField: a.Index}
+		}
+		return document{fields: doc, metadata: metadata}, nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return req, stats, actions, nil
+}
+
+type elasticsearchBulkItem struct {
+	Index  string `json:"_index,omitempty"`
+	Status int    `json:"status"`
+}
+
+type elasticsearchBulkResponse struct {
+	Took   int64                              `json:"took"`
+	Errors bool                               `json:"errors"`
+	Items  []map[string]elasticsearchBulkItem `json:"items"`
+}
+
+// WriteElasticsearchBulkResponse writes the response of a successful _bulk request, reporting its documents as
+// created with the action of the request, in the order of the request, as expected by the clients retrying the
+// documents which failed.
+func WriteElasticsearchBulkResponse(w http.ResponseWriter, actions []ElasticsearchBulkAction, took time.Duration) error {
+	resp := elasticsearchBulkResponse{
+		Took:  took.Milliseconds(),
+		Items: make([]map[string]elasticsearchBulkItem, len(actions)),
+	}
+	for i, a := range actions {
+		resp.Items[i] = map[string]elasticsearchBulkItem{a.Operation: {Index: a.Index, Status: http.StatusCreated}}
+	}
+	w.Header().Set(contentType, applicationJSON)
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	return json.NewEncoder(w).Encode(resp)
+}
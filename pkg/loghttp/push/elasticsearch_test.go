@@ -0,0 +1,171 @@
+package push
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+type mappingLimits struct {
+	EmptyLimits
+	elasticsearch DocumentMapping
+}
+
+func (l mappingLimits) ElasticsearchMapping(string) DocumentMapping {
+	return l.elasticsearch
+}
+
+func TestParseElasticsearchBulkRequest(t *testing.T) {
+	body := `{"index":{"_index":"logs"}}
+{"@timestamp":"2024-05-01T10:00:00.5Z","message":"GET /api 200","service":{"name":"api"},"log.level":"info","trace":{"id":"abc"}}
+{"create":{}}
+{"@timestamp":1714557601000,"message":"GET /web 500","service":{"name":"web"},"log.level":"error"}
+
+{"index":{}}
+{"@timestamp":"2024-05-01T10:00:02Z","message":"POST /api 201","service":{"name":"api"}}
+`
+	limits := mappingLimits{elasticsearch: DocumentMapping{
+		LineField:          "message",
+		TimestampField:     "@timestamp",
+		IndexLabels:        []string{"service.name"},
+		StructuredMetadata: []string{"log.level", "trace.id"},
+	}}
+	tracker := NewMockTracker()
+
+	req := httptest.NewRequest(http.MethodPost, "/elasticsearch/_bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	pushReq, stats, actions, err := ParseElasticsearchBulkRequest("fake", req, nil, limits, tracker)
+	require.NoError(t, err)
+	require.Equal(t, []ElasticsearchBulkAction{{Operation: "index", Index: "logs"}, {Operation: "create"}, {Operation: "index"}}, actions)
+	require.Equal(t, []logproto.Stream{
+		{
+			Labels: `{service_name="api"}`,
+			Entries: []logproto.Entry{
+				{
+					Timestamp:          time.Date(2024, 5, 1, 10, 0, 0, 5e8, time.UTC),
+					Line:               "GET /api 200",
+					StructuredMetadata: push.LabelsAdapter{{Name: "log_level", Value: "info"}, {Name: "trace_id", Value: "abc"}},
+				},
+				{
+					Timestamp: time.Date(2024, 5, 1, 10, 0, 2, 0, time.UTC),
+					Line:      "POST /api 201",
+				},
+			},
+		},
+		{
+			Labels: `{service_name="web"}`,
+			Entries: []logproto.Entry{
+				{
+					Timestamp:          time.UnixMilli(1714557601000),
+					Line:               "GET /web 500",
+					StructuredMetadata: push.LabelsAdapter{{Name: "log_level", Value: "error"}},
+				},
+			},
+		},
+	}, pushReq.Streams)
+	require.Equal(t, int64(3), stats.NumLines)
+	require.Equal(t, int64(len(body)), stats.BodySize)
+	require.Equal(t, float64(75), tracker.Total())
+}
+
+func TestParseElasticsearchBulkRequestUnmappedDocument(t *testing.T) {
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	_, _ = gz.Write([]byte("{\"index\":{}}\n{\"@timestamp\":\"2024-05-01T10:00:00Z\",\"host\":{\"name\":\"h1\",\"ip\":\"10.0.0.1\"},\"status\":200}\n"))
+	require.NoError(t, gz.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/elasticsearch/_bulk", &body)
+	req.Header.Set("Content-Encoding", "gzip")
+	limits := mappingLimits{elasticsearch: DocumentMapping{
+		LineField:      "message",
+		TimestampField: "@timestamp",
+		IndexLabels:    []string{"host.name"},
+	}}
+	pushReq, _, _, err := ParseElasticsearchBulkRequest("fake", req, nil, limits, nil)
+	require.NoError(t, err)
+	require.Len(t, pushReq.Streams, 1)
+	require.Equal(t, `{host_name="h1"}`, pushReq.Streams[0].Labels)
+	// the documents without a line field are stored as JSON, without their mapped fields.
+	require.Equal(t, `{"host":{"ip":"10.0.0.1"},"status":200}`, pushReq.Streams[0].Entries[0].Line)
+}
+
+func TestParseElasticsearchBulkRequestIndex(t *testing.T) {
+	body := `{"index":{"_index":"logs"}}
+{"message":"line 1"}
+{"create":{}}
+{"message":"line 2"}
+`
+	limits := mappingLimits{elasticsearch: DocumentMapping{
+		LineField:   "message",
+		IndexLabels: []string{ElasticsearchIndexField},
+	}}
+
+	req := httptest.NewRequest(http.MethodPost, "/elasticsearch/app/_bulk", strings.NewReader(body))
+	// the index of the request path is the default index of the actions.
+	req = mux.SetURLVars(req, map[string]string{"index": "app"})
+	pushReq, _, actions, err := ParseElasticsearchBulkRequest("fake", req, nil, limits, nil)
+	require.NoError(t, err)
+	require.Equal(t, []ElasticsearchBulkAction{{Operation: "index", Index: "logs"}, {Operation: "create", Index: "app"}}, actions)
+	require.Len(t, pushReq.Streams, 2)
+	require.Equal(t, `{index="logs"}`, pushReq.Streams[0].Labels)
+	require.Equal(t, `{index="app"}`, pushReq.Streams[1].Labels)
+
+	// the index is only stored when it is mapped.
+	req = httptest.NewRequest(http.MethodPost, "/elasticsearch/_bulk", strings.NewReader("{\"index\":{\"_index\":\"logs\"}}\n{\"status\":200}\n"))
+	pushReq, _, _, err = ParseElasticsearchBulkRequest("fake", req, nil, EmptyLimits{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"status":200}`, pushReq.Streams[0].Entries[0].Line)
+}
+
+func TestParseElasticsearchBulkRequestErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+		err  string
+	}{
+		{
+			name: "unsupported action",
+			body: "{\"delete\":{\"_id\":\"1\"}}\n",
+			err:  `unsupported bulk action "delete"`,
+		},
+		{
+			name: "missing document",
+			body: "{\"index\":{}}\n",
+			err:  "invalid bulk document 0: unexpected EOF",
+		},
+		{
+			name: "invalid timestamp",
+			body: "{\"index\":{}}\n{\"@timestamp\":\"yesterday\",\"message\":\"line\"}\n",
+			err:  `invalid timestamp field "@timestamp"`,
+		},
+		{
+			name: "timestamp out of range",
+			body: "{\"index\":{}}\n{\"@timestamp\":1e30,\"message\":\"line\"}\n",
+			err:  `invalid timestamp field "@timestamp": timestamp 1e30 is out of range`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/elasticsearch/_bulk", strings.NewReader(tc.body))
+			_, _, _, err := ParseElasticsearchBulkRequest("fake", req, nil, EmptyLimits{}, nil)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestWriteElasticsearchBulkResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	require.NoError(t, WriteElasticsearchBulkResponse(w, []ElasticsearchBulkAction{{Operation: "index", Index: "logs"}, {Operation: "create"}}, 5*time.Millisecond))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"took":5,"errors":false,"items":[{"index":{"_index":"logs","status":201}},{"create":{"status":201}}]}`, w.Body.String())
+}
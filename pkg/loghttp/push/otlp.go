@@ -10,6 +10,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/go-kit/log"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
@@ -26,10 +27,14 @@ import (
 
 const (
 	pbContentType       = "application/x-protobuf"
+	grpcContentType     = "application/grpc"
 	gzipContentEncoding = "gzip"
 	attrServiceName     = "service.name"
 
 	OTLPSeverityNumber = "severity_number"
+
+	// OTLPExportMethod is the gRPC method of the OTLP logs export requests.
+	OTLPExportMethod = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
 )
 
 func newPushStats() *Stats {
@@ -51,6 +56,19 @@ func ParseOTLPRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 	return req, stats, nil
 }
 
+// ParseOTLPExportRequest converts an OTLP logs export request received over gRPC to a push request, and records its
+// stats like the requests parsed by ParseRequest.
+func ParseOTLPExportRequest(ctx context.Context, logger log.Logger, userID string, req plogotlp.ExportRequest, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker) *logproto.PushRequest {
+	stats := newPushStats()
+	stats.ContentType = grpcContentType
+	logs := req.Logs()
+	stats.BodySize = int64((&plog.ProtoMarshaler{}).LogsSize(logs))
+
+	pushReq := otlpToLokiPushRequest(ctx, logs, userID, tenantsRetention, limits.OTLPConfig(userID), limits.DiscoverServiceName(userID), tracker, stats)
+	recordPushStats(logger, userID, OTLPExportMethod, pushReq, stats)
+	return pushReq
+}
+
 func extractLogs(r *http.Request, pushStats *Stats) (plog.Logs, error) {
 	pushStats.ContentEncoding = r.Header.Get(contentEnc)
 	// bodySize should always reflect the compressed size of the request body
@@ -0,0 +1,217 @@
+package push
+
+import (
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage/remote/otlptranslator/prometheus"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// StreamsToOTLPLogs converts streams to OTLP logs, inverting the mapping of the OTLP logs pushed with the OTLP config:
+//   - the stream labels, and the structured metadata configured as resource attributes, are the resource attributes.
+//   - the scope fields, and the structured metadata configured as scope attributes, are the scope of the log records.
+//   - the log record fields are read back from their structured metadata, the rest of it are the log attributes.
+//
+// The attributes are named after the attributes of the OTLP config matching their normalized labels, the other
+// attributes keep the name of their label, the names of the attributes before their normalization being unknown.
+func StreamsToOTLPLogs(streams []logproto.Stream, otlpConfig OTLPConfig) plog.Logs {
+	ld := plog.NewLogs()
+	for _, stream := range streams {
+		lbs, err := syntax.ParseLabels(stream.Labels)
+		if err != nil {
+			continue
+		}
+
+		// the entries of a stream are grouped by resource and scope attributes.
+		resources := map[string]plog.ResourceLogs{}
+		scopes := map[string]plog.ScopeLogs{}
+		for _, entry := range stream.Entries {
+			resourceAttrs, scopeAttrs, record := otlpConfig.entryToLogRecord(entry)
+
+			resourceKey := attributesKey(resourceAttrs)
+			rl, ok := resources[resourceKey]
+			if !ok {
+				rl = ld.ResourceLogs().AppendEmpty()
+				attrs := rl.Resource().Attributes()
+				lbs.Range(func(l labels.Label) {
+					attrs.PutStr(otlpConfig.resourceAttributeName(l.Name), l.Value)
+				})
+				putAttributes(attrs, resourceAttrs)
+				resources[resourceKey] = rl
+			}
+
+			scopeKey := resourceKey + "\xff" + attributesKey(scopeAttrs)
+			sl, ok := scopes[scopeKey]
+			if !ok {
+				sl = rl.ScopeLogs().AppendEmpty()
+				setScope(sl.Scope(), scopeAttrs)
+				scopes[scopeKey] = sl
+			}
+			record.MoveTo(sl.LogRecords().AppendEmpty())
+		}
+	}
+	return ld
+}
+
+// entryToLogRecord returns the resource attributes, the scope attributes and fields, and the log record of the entry.
+func (c *OTLPConfig) entryToLogRecord(entry logproto.Entry) (push.LabelsAdapter, push.LabelsAdapter, plog.LogRecord) {
+	record := plog.NewLogRecord()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(entry.Timestamp))
+	record.Body().SetStr(entry.Line)
+
+	var resourceAttrs, scopeAttrs push.LabelsAdapter
+	for _, l := range entry.StructuredMetadata {
+		if logRecordField(record, l) {
+			continue
+		}
+		switch l.Name {
+		case "scope_name", "scope_version", "scope_dropped_attributes_count":
+			scopeAttrs = append(scopeAttrs, l)
+			continue
+		}
+		if name, ok := attributeName(l.Name, c.ResourceAttributes.AttributesConfig, StructuredMetadata); ok {
+			resourceAttrs = append(resourceAttrs, push.LabelAdapter{Name: name, Value: l.Value})
+			continue
+		}
+		if name, ok := attributeName(l.Name, c.ScopeAttributes, StructuredMetadata); ok {
+			scopeAttrs = append(scopeAttrs, push.LabelAdapter{Name: name, Value: l.Value})
+			continue
+		}
+		c.putLogAttribute(record, l)
+	}
+	// the labels extracted by the query are log attributes.
+	for _, l := range entry.Parsed {
+		c.putLogAttribute(record, l)
+	}
+	return resourceAttrs, scopeAttrs, record
+}
+
+func (c *OTLPConfig) putLogAttribute(record plog.LogRecord, l push.LabelAdapter) {
+	name, _ := attributeName(l.Name, c.LogAttributes, StructuredMetadata)
+	record.Attributes().PutStr(name, l.Value)
+}
+
+// logRecordField sets the field of the log record stored in the structured metadata, it returns false if the
+// structured metadata isn't a valid log record field.
+func logRecordField(record plog.LogRecord, l push.LabelAdapter) bool {
+	switch l.Name {
+	case "observed_timestamp":
+		ts, err := strconv.ParseInt(l.Value, 10, 64)
+		if err != nil {
+			return false
+		}
+		record.SetObservedTimestamp(pcommon.Timestamp(ts))
+	case OTLPSeverityNumber:
+		n, err := strconv.ParseInt(l.Value, 10, 32)
+		if err != nil {
+			return false
+		}
+		record.SetSeverityNumber(plog.SeverityNumber(n))
+	case "severity_text":
+		record.SetSeverityText(l.Value)
+	case "dropped_attributes_count":
+		n, err := strconv.ParseUint(l.Value, 10, 32)
+		if err != nil {
+			return false
+		}
+		record.SetDroppedAttributesCount(uint32(n))
+	case "flags":
+		n, err := strconv.ParseUint(l.Value, 10, 32)
+		if err != nil {
+			return false
+		}
+		record.SetFlags(plog.LogRecordFlags(n))
+	case "trace_id":
+		var id pcommon.TraceID
+		b, err := hex.DecodeString(l.Value)
+		if err != nil || len(b) != len(id) {
+			return false
+		}
+		copy(id[:], b)
+		record.SetTraceID(id)
+	case "span_id":
+		var id pcommon.SpanID
+		b, err := hex.DecodeString(l.Value)
+		if err != nil || len(b) != len(id) {
+			return false
+		}
+		copy(id[:], b)
+		record.SetSpanID(id)
+	default:
+		return false
+	}
+	return true
+}
+
+func setScope(scope pcommon.InstrumentationScope, attrs push.LabelsAdapter) {
+	for _, l := range attrs {
+		switch l.Name {
+		case "scope_name":
+			scope.SetName(l.Value)
+		case "scope_version":
+			scope.SetVersion(l.Value)
+		case "scope_dropped_attributes_count":
+			if n, err := strconv.ParseUint(l.Value, 10, 32); err == nil {
+				scope.SetDroppedAttributesCount(uint32(n))
+				continue
+			}
+			scope.Attributes().PutStr(l.Name, l.Value)
+		default:
+			scope.Attributes().PutStr(l.Name, l.Value)
+		}
+	}
+}
+
+func putAttributes(attrs pcommon.Map, lbs push.LabelsAdapter) {
+	for _, l := range lbs {
+		attrs.PutStr(l.Name, l.Value)
+	}
+}
+
+// attributesKey returns a key identifying the attributes regardless of their order.
+func attributesKey(attrs push.LabelsAdapter) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(attrs))
+	for _, l := range attrs {
+		pairs = append(pairs, l.Name+"\xfe"+l.Value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\xfd")
+}
+
+// resourceAttributeName returns the name of the resource attribute stored in the stream label.
+func (c *OTLPConfig) resourceAttributeName(label string) string {
+	name, _ := attributeName(label, c.ResourceAttributes.AttributesConfig, IndexLabel)
+	return name
+}
+
+// attributeName returns the name of the attribute listed by the attribute configs with the action whose normalized
+// name is the label, and true if the label is matched by those configs. The label itself is returned otherwise.
+func attributeName(label string, cfgs []AttributesConfig, action Action) (string, bool) {
+	for _, cfg := range cfgs {
+		if cfg.Action != action {
+			continue
+		}
+		for _, attr := range cfg.Attributes {
+			if prometheus.NormalizeLabel(attr) == label {
+				return attr, true
+			}
+		}
+		if cfg.Regex.Regexp != nil && cfg.Regex.MatchString(label) {
+			return label, true
+		}
+	}
+	return label, false
+}
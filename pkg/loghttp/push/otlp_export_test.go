@@ -0,0 +1,98 @@
+package push
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestStreamsToOTLPLogs(t *testing.T) {
+	now := time.Unix(0, time.Now().UnixNano())
+	otlpConfig := OTLPConfig{
+		ResourceAttributes: ResourceAttributesConfig{
+			AttributesConfig: []AttributesConfig{
+				{Action: IndexLabel, Attributes: []string{"service.name"}},
+				{Action: StructuredMetadata, Attributes: []string{"k8s.pod.name"}},
+			},
+		},
+		ScopeAttributes: []AttributesConfig{{Action: StructuredMetadata, Attributes: []string{"scope.attr"}}},
+		LogAttributes:   []AttributesConfig{{Action: StructuredMetadata, Attributes: []string{"user.id"}}},
+	}
+
+	ld := plog.NewLogs()
+	for _, pod := range []string{"pod-1", "pod-2"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "api")
+		rl.Resource().Attributes().PutStr("k8s.pod.name", pod)
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName("lib")
+		sl.Scope().SetVersion("1.0")
+		sl.Scope().Attributes().PutStr("scope.attr", "value")
+		record := sl.LogRecords().AppendEmpty()
+		record.SetTimestamp(pcommon.NewTimestampFromTime(now))
+		record.Body().SetStr("line from " + pod)
+		record.SetSeverityNumber(plog.SeverityNumberWarn)
+		record.SetSeverityText("WARN")
+		record.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+		record.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+		record.Attributes().PutStr("user.id", "42")
+	}
+
+	pushReq := otlpToLokiPushRequest(context.Background(), ld, "test", fakeRetention{}, otlpConfig, nil, nil, newPushStats())
+	require.Len(t, pushReq.Streams, 1)
+
+	logs := StreamsToOTLPLogs(pushReq.Streams, otlpConfig)
+	require.Equal(t, 2, logs.ResourceLogs().Len())
+	for i, pod := range []string{"pod-1", "pod-2"} {
+		rl := logs.ResourceLogs().At(i)
+		require.Equal(t, map[string]any{"service.name": "api", "k8s.pod.name": pod}, rl.Resource().Attributes().AsRaw())
+		require.Equal(t, 1, rl.ScopeLogs().Len())
+
+		sl := rl.ScopeLogs().At(0)
+		require.Equal(t, "lib", sl.Scope().Name())
+		require.Equal(t, "1.0", sl.Scope().Version())
+		require.Equal(t, map[string]any{"scope.attr": "value"}, sl.Scope().Attributes().AsRaw())
+		require.Equal(t, 1, sl.LogRecords().Len())
+
+		record := sl.LogRecords().At(0)
+		require.Equal(t, now, record.Timestamp().AsTime().In(now.Location()))
+		require.Equal(t, "line from "+pod, record.Body().Str())
+		require.Equal(t, plog.SeverityNumberWarn, record.SeverityNumber())
+		require.Equal(t, "WARN", record.SeverityText())
+		require.Equal(t, pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, record.TraceID())
+		require.Equal(t, pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8}, record.SpanID())
+		require.Equal(t, map[string]any{"user.id": "42"}, record.Attributes().AsRaw())
+	}
+}
+
+func TestStreamsToOTLPLogsUnknownAttributes(t *testing.T) {
+	logs := StreamsToOTLPLogs([]logproto.Stream{
+		{
+			Labels: `{service_name="api", env="prod"}`,
+			Entries: []logproto.Entry{
+				{
+					Timestamp:          time.Unix(0, 1),
+					Line:               "line",
+					StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "not hex"}, {Name: "pod", Value: "pod-1"}},
+					Parsed:             push.LabelsAdapter{{Name: "level", Value: "info"}},
+				},
+			},
+		},
+	}, DefaultOTLPConfig(GlobalOTLPConfig{DefaultOTLPResourceAttributesAsIndexLabels: []string{"service.name"}}))
+
+	require.Equal(t, 1, logs.ResourceLogs().Len())
+	rl := logs.ResourceLogs().At(0)
+	// the labels not matching the OTLP config keep their name.
+	require.Equal(t, map[string]any{"service.name": "api", "env": "prod"}, rl.Resource().Attributes().AsRaw())
+	record := rl.ScopeLogs().At(0).LogRecords().At(0)
+	require.True(t, record.TraceID().IsEmpty())
+	require.Equal(t, map[string]any{"trace_id": "not hex", "pod": "pod-1", "level": "info"}, record.Attributes().AsRaw())
+}
@@ -3,6 +3,7 @@ package push
 import (
 	"compress/flate"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"math"
@@ -71,6 +72,8 @@ type TenantsRetention interface {
 type Limits interface {
 	OTLPConfig(userID string) OTLPConfig
 	DiscoverServiceName(userID string) []string
+	ElasticsearchMapping(userID string) DocumentMapping
+	SplunkHECMapping(userID string) DocumentMapping
 }
 
 type EmptyLimits struct{}
@@ -83,6 +86,14 @@ func (EmptyLimits) DiscoverServiceName(string) []string {
 	return nil
 }
 
+func (EmptyLimits) ElasticsearchMapping(string) DocumentMapping {
+	return DefaultElasticsearchMapping
+}
+
+func (EmptyLimits) SplunkHECMapping(string) DocumentMapping {
+	return DefaultSplunkHECMapping
+}
+
 type (
 	RequestParser        func(userID string, r *http.Request, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error)
 	RequestParserWrapper func(inner RequestParser) RequestParser
@@ -112,6 +123,12 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 		return nil, err
 	}
 
+	recordPushStats(logger, userID, r.URL.Path, req, pushStats)
+	return req, nil
+}
+
+// recordPushStats records the metrics of a parsed push request and logs its stats.
+func recordPushStats(logger log.Logger, userID, path string, req *logproto.PushRequest, pushStats *Stats) {
 	var (
 		entriesSize            int64
 		structuredMetadataSize int64
@@ -146,7 +163,7 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 
 	logValues := []interface{}{
 		"msg", "push request parsed",
-		"path", r.URL.Path,
+		"path", path,
 		"contentType", pushStats.ContentType,
 		"contentEncoding", pushStats.ContentEncoding,
 		"bodySize", humanize.Bytes(uint64(pushStats.BodySize)),
@@ -160,38 +177,14 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 	}
 	logValues = append(logValues, pushStats.Extra...)
 	level.Debug(logger).Log(logValues...)
-
-	return req, nil
 }
 
 func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error) {
-	// Body
-	var body io.Reader
-	// bodySize should always reflect the compressed size of the request body
-	bodySize := loki_util.NewSizeReader(r.Body)
-	contentEncoding := r.Header.Get(contentEnc)
-	switch contentEncoding {
-	case "":
-		body = bodySize
-	case "snappy":
-		// Snappy-decoding is done by `util.ParseProtoReader(..., util.RawSnappy)` below.
-		// Pass on body bytes. Note: HTTP clients do not need to set this header,
-		// but they sometimes do. See #3407.
-		body = bodySize
-	case "gzip":
-		gzipReader, err := gzip.NewReader(bodySize)
-		if err != nil {
-			return nil, nil, err
-		}
-		defer gzipReader.Close()
-		body = gzipReader
-	case "deflate":
-		flateReader := flate.NewReader(bodySize)
-		defer flateReader.Close()
-		body = flateReader
-	default:
-		return nil, nil, fmt.Errorf("Content-Encoding %q not supported", contentEncoding)
+	body, bodySize, contentEncoding, closeBody, err := requestBody(r)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer closeBody()
 
 	contentType := r.Header.Get(contentType)
 	var (
@@ -199,7 +192,7 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 		pushStats = newPushStats()
 	)
 
-	contentType, _ /* params */, err := mime.ParseMediaType(contentType)
+	contentType, _ /* params */, err = mime.ParseMediaType(contentType)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -233,6 +226,44 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 	pushStats.ContentType = contentType
 	pushStats.ContentEncoding = contentEncoding
 
+	if err := streamsStats(r.Context(), userID, &req, pushStats, tenantsRetention, limits, tracker); err != nil {
+		return nil, nil, err
+	}
+
+	return &req, pushStats, nil
+}
+
+// requestBody returns the decompressed body of a push request, the reader of its compressed size and its encoding.
+// Snappy bodies are returned as is, to be decoded with the protobuf push requests.
+func requestBody(r *http.Request) (io.Reader, loki_util.SizeReader, string, func(), error) {
+	// bodySize should always reflect the compressed size of the request body
+	bodySize := loki_util.NewSizeReader(r.Body)
+	contentEncoding := r.Header.Get(contentEnc)
+	switch contentEncoding {
+	case "":
+		return bodySize, bodySize, contentEncoding, func() {}, nil
+	case "snappy":
+		// Snappy-decoding is done by `util.ParseProtoReader(..., util.RawSnappy)` in ParseLokiRequest.
+		// Pass on body bytes. Note: HTTP clients do not need to set this header,
+		// but they sometimes do. See #3407.
+		return bodySize, bodySize, contentEncoding, func() {}, nil
+	case "gzip":
+		gzipReader, err := gzip.NewReader(bodySize)
+		if err != nil {
+			return nil, nil, "", nil, err
+		}
+		return gzipReader, bodySize, contentEncoding, func() { gzipReader.Close() }, nil
+	case "deflate":
+		flateReader := flate.NewReader(bodySize)
+		return flateReader, bodySize, contentEncoding, func() { flateReader.Close() }, nil
+	default:
+		return nil, nil, "", nil, fmt.Errorf("Content-Encoding %q not supported", contentEncoding)
+	}
+}
+
+// streamsStats adds the service name discovered from the labels of the streams, records their sizes in the stats
+// and reports them to the usage tracker.
+func streamsStats(ctx context.Context, userID string, req *logproto.PushRequest, pushStats *Stats, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker) error {
 	discoverServiceName := limits.DiscoverServiceName(userID)
 	for i := range req.Streams {
 		s := req.Streams[i]
@@ -240,7 +271,7 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 
 		lbs, err := syntax.ParseLabels(s.Labels)
 		if err != nil {
-			return nil, nil, fmt.Errorf("couldn't parse labels: %w", err)
+			return fmt.Errorf("couldn't parse labels: %w", err)
 		}
 
 		if lbs.Has(AggregatedMetricLabel) {
@@ -278,8 +309,8 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 			pushStats.StructuredMetadataBytes[retentionPeriod] += entryLabelsSize
 
 			if tracker != nil {
-				tracker.ReceivedBytesAdd(r.Context(), userID, retentionPeriod, lbs, float64(len(e.Line)))
-				tracker.ReceivedBytesAdd(r.Context(), userID, retentionPeriod, lbs, float64(entryLabelsSize))
+				tracker.ReceivedBytesAdd(ctx, userID, retentionPeriod, lbs, float64(len(e.Line)))
+				tracker.ReceivedBytesAdd(ctx, userID, retentionPeriod, lbs, float64(entryLabelsSize))
 			}
 
 			if e.Timestamp.After(pushStats.MostRecentEntryTimestamp) {
@@ -290,7 +321,7 @@ func ParseLokiRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 		req.Streams[i] = s
 	}
 
-	return &req, pushStats, nil
+	return nil
 }
 
 func RetentionPeriodToString(retentionPeriod time.Duration) string {
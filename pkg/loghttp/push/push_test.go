@@ -454,6 +454,14 @@ func (f *fakeLimits) DiscoverServiceName(_ string) []string {
 	}
 }
 
+func (f *fakeLimits) ElasticsearchMapping(_ string) DocumentMapping {
+	return DefaultElasticsearchMapping
+}
+
+func (f *fakeLimits) SplunkHECMapping(_ string) DocumentMapping {
+	return DefaultSplunkHECMapping
+}
+
 type MockCustomTracker struct {
 	receivedBytes  map[string]float64
 	discardedBytes map[string]float64
@@ -0,0 +1,64 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// DefaultSplunkHECMapping maps the events like the Splunk indexers: the event field is the line, the time field is
+// the timestamp in seconds since the epoch, and the host, source and sourcetype fields are the stream labels.
+var DefaultSplunkHECMapping = DocumentMapping{
+	LineField:      "event",
+	TimestampField: "time",
+	IndexLabels:    []string{"host", "source", "sourcetype"},
+}
+
+// ParseSplunkHECRequest parses the JSON events of a Splunk HTTP Event Collector request, which are mapped to entries
+// with the Splunk HEC mapping of the tenant. The fields of the events are addressed like the events, for example
+// fields.env for an indexed field or event.level for a field of a JSON event.
+func ParseSplunkHECRequest(userID string, r *http.Request, tenantsRetention TenantsRetention, limits Limits, tracker UsageTracker) (*logproto.PushRequest, *Stats, error) {
+	var n int
+	return parseDocumentsRequest(userID, r, tenantsRetention, limits, tracker, limits.SplunkHECMapping(userID), time.Second, func(decoder *json.Decoder) (document, error) {
+		var event map[string]any
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				return document{}, err
+			}
+			return document{}, fmt.Errorf("invalid event %d: %w", n, err)
+		}
+		n++
+		return document{fields: event}, nil
+	})
+}
+
+// SplunkHECToken returns the token of the Authorization header of a Splunk HEC request, sent as "Splunk <token>".
+func SplunkHECToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Splunk") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Splunk HEC status codes, returned in the body of the responses.
+const (
+	SplunkHECSuccess       = 0
+	SplunkHECTokenRequired = 2
+	SplunkHECInvalidToken  = 4
+)
+
+// WriteSplunkHECResponse writes the response of a Splunk HEC request with the HEC status code and its text.
+func WriteSplunkHECResponse(w http.ResponseWriter, status, code int, text string) error {
+	w.Header().Set(contentType, applicationJSON)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(struct {
+		Text string `json:"text"`
+		Code int    `json:"code"`
+	}{Text: text, Code: code})
+}
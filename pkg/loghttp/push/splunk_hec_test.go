@@ -0,0 +1,80 @@
+package push
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestParseSplunkHECRequest(t *testing.T) {
+	// the events are concatenated, with or without whitespace between them.
+	body := `{"time":1714557600.25,"host":"h1","sourcetype":"access","event":"GET /api 200","fields":{"env":"prod"}}{"time":"1714557601","host":"h1","sourcetype":"access","event":{"msg":"GET /web","status":500},"fields":{"env":"prod"}}
+{"host":"h2","event":"no time"}`
+	limits := mappingLimits{}
+	hecLimits := hecMappingLimits{mappingLimits: limits, hec: DocumentMapping{
+		LineField:          "event",
+		TimestampField:     "time",
+		IndexLabels:        []string{"host", "source", "sourcetype"},
+		StructuredMetadata: []string{"fields.env"},
+	}}
+
+	req := httptest.NewRequest(http.MethodPost, "/services/collector/event", strings.NewReader(body))
+	before := time.Now()
+	pushReq, stats, err := ParseSplunkHECRequest("fake", req, nil, hecLimits, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stats.NumLines)
+	require.Len(t, pushReq.Streams, 2)
+	require.Equal(t, logproto.Stream{
+		Labels: `{host="h1", sourcetype="access"}`,
+		Entries: []logproto.Entry{
+			{
+				Timestamp:          time.Unix(1714557600, 25e7),
+				Line:               "GET /api 200",
+				StructuredMetadata: push.LabelsAdapter{{Name: "fields_env", Value: "prod"}},
+			},
+			{
+				Timestamp:          time.Unix(1714557601, 0),
+				Line:               `{"msg":"GET /web","status":500}`,
+				StructuredMetadata: push.LabelsAdapter{{Name: "fields_env", Value: "prod"}},
+			},
+		},
+	}, pushReq.Streams[0])
+	require.Equal(t, `{host="h2"}`, pushReq.Streams[1].Labels)
+	require.Equal(t, "no time", pushReq.Streams[1].Entries[0].Line)
+	// the events without time are timestamped when they are received.
+	require.False(t, pushReq.Streams[1].Entries[0].Timestamp.Before(before))
+
+	req = httptest.NewRequest(http.MethodPost, "/services/collector/event", strings.NewReader(`{"event":"line"} not json`))
+	_, _, err = ParseSplunkHECRequest("fake", req, nil, hecLimits, nil)
+	require.ErrorContains(t, err, "invalid event 1")
+}
+
+type hecMappingLimits struct {
+	mappingLimits
+	hec DocumentMapping
+}
+
+func (l hecMappingLimits) SplunkHECMapping(string) DocumentMapping {
+	return l.hec
+}
+
+func TestSplunkHECToken(t *testing.T) {
+	for header, token := range map[string]string{
+		"Splunk 1234-abcd": "1234-abcd",
+		"splunk 1234-abcd": "1234-abcd",
+		"Bearer 1234-abcd": "",
+		"":                 "",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/services/collector/event", nil)
+		req.Header.Set("Authorization", header)
+		require.Equal(t, token, SplunkHECToken(req))
+	}
+}
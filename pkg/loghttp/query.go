@@ -226,6 +226,17 @@ type QueryResponseData struct {
 	ResultType ResultType   `json:"resultType"`
 	Result     ResultValue  `json:"result"`
 	Statistics stats.Result `json:"stats"`
+	// Approximation is set for sampled metric queries.
+	*Approximation
+}
+
+// Approximation describes the results of a sampled metric query, computed from a subset of the streams.
+type Approximation struct {
+	Approximate bool `json:"approximate"`
+	// SampleRatio is the ratio of the streams read by the query.
+	SampleRatio float64 `json:"sampleRatio"`
+	// RelativeError is the estimated relative standard error of the sums and counts of the query.
+	RelativeError float64 `json:"relativeError"`
 }
 
 // Type implements the promql.Value interface
@@ -361,6 +372,14 @@ func (q *QueryResponseData) UnmarshalJSON(data []byte) error {
 			if err := json.Unmarshal(value, &q.Statistics); err != nil {
 				return err
 			}
+		case "approximate":
+			approximate, err := jsonparser.ParseBoolean(value)
+			if err != nil {
+				return err
+			}
+			q.Approximation = &Approximation{Approximate: approximate}
+			q.Approximation.SampleRatio, _ = jsonparser.GetFloat(data, "sampleRatio")
+			q.Approximation.RelativeError, _ = jsonparser.GetFloat(data, "relativeError")
 		}
 		return nil
 	})
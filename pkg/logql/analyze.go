@@ -0,0 +1,253 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+)
+
+type analyzerCtxKey int
+
+// analyzerKey marks the contexts of the evaluators created by an analyzer.
+const analyzerKey analyzerCtxKey = 0
+
+// analyzer is the evaluator factory of queries executed with explain=analyze.
+// It wraps each step evaluator to measure the time spent in it, and collects the
+// statistics of the evaluator and its children in a statistics context of their own.
+type analyzer struct {
+	EvaluatorFactory
+
+	root *analyzeStepEvaluator
+
+	// visited collects the evaluators in the order they are explained, see analysis.
+	visited    []visitedEvaluator
+	collecting bool
+}
+
+type visitedEvaluator struct {
+	ev    *analyzeStepEvaluator
+	name  string
+	level int
+}
+
+func newAnalyzer(factory EvaluatorFactory) *analyzer {
+	return &analyzer{EvaluatorFactory: factory}
+}
+
+// NewStepEvaluator creates the step evaluator with the wrapped factory, children included.
+func (a *analyzer) NewStepEvaluator(
+	ctx context.Context,
+	_ SampleEvaluatorFactory,
+	expr syntax.SampleExpr,
+	p Params,
+) (StepEvaluator, error) {
+	start := time.Now()
+	nodeStats, nodeCtx := stats.NewContext(ctx)
+	nodeCtx = context.WithValue(nodeCtx, analyzerKey, a)
+
+	ev, err := a.EvaluatorFactory.NewStepEvaluator(nodeCtx, a, expr, p)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &analyzeStepEvaluator{
+		StepEvaluator: ev,
+		analyzer:      a,
+		ctx:           ctx,
+		stats:         nodeStats,
+		wallTime:      time.Since(start),
+	}
+	if ctx.Value(analyzerKey) != a {
+		a.root = res
+	}
+	return res, nil
+}
+
+// analysis returns the statistics of each node of the executed query.
+// The nodes of the evaluators are ordered like explain prints them.
+func (a *analyzer) analysis(expr syntax.Expr, wallTime time.Duration, res stats.Result) *stats.Analysis {
+	if a.root == nil {
+		// log queries have no evaluators, the query is a single node selecting lines.
+		if _, ok := expr.(syntax.LogSelectorExpr); !ok {
+			return nil
+		}
+		return &stats.Analysis{
+			Nodes: appendAnalysisNode(nil, logQueryNodeName(expr), 0, wallTime, res, true),
+		}
+	}
+
+	a.visited = a.visited[:0]
+	a.collecting = true
+	a.root.Explain(NewTree())
+	a.collecting = false
+
+	var (
+		nodes []stats.AnalysisNode
+		// levels are the explain levels of the analyzed ancestors of the current evaluator.
+		levels []int
+	)
+	for i, v := range a.visited {
+		for len(levels) > 0 && levels[len(levels)-1] >= v.level {
+			levels = levels[:len(levels)-1]
+		}
+		depth := int32(len(levels))
+		levels = append(levels, v.level)
+
+		leaf := i == len(a.visited)-1 || a.visited[i+1].level <= v.level
+		nodes = appendAnalysisNode(nodes, v.name, depth, v.ev.wallTime, v.ev.result, leaf)
+	}
+	return &stats.Analysis{Nodes: nodes}
+}
+
+func logQueryNodeName(expr syntax.Expr) string {
+	switch expr.(type) {
+	case DownstreamLogSelectorExpr:
+		return "Downstream"
+	case *ConcatLogSelectorExpr:
+		return "Concat"
+	default:
+		return "Logs"
+	}
+}
+
+// appendAnalysisNode appends the node of an evaluator or of a log query followed by the nodes of
+// the downstream queries it fanned out to, or by the stages of its log pipelines for a leaf.
+func appendAnalysisNode(nodes []stats.AnalysisNode, name string, depth int32, wallTime time.Duration, res stats.Result, leaf bool) []stats.AnalysisNode {
+	nodes = append(nodes, stats.AnalysisNode{
+		Name:           name,
+		Depth:          depth,
+		WallTime:       wallTime.Nanoseconds(),
+		LinesIn:        res.Summary.TotalLinesProcessed,
+		LinesOut:       res.Summary.TotalPostFilterLines,
+		BytesProcessed: res.Summary.TotalBytesProcessed,
+		Shards:         res.Summary.Shards,
+	})
+
+	if res.Analysis != nil {
+		for _, n := range res.Analysis.Nodes {
+			n.Depth += depth + 1
+			nodes = append(nodes, n)
+		}
+		return nodes
+	}
+	if !leaf {
+		return nodes
+	}
+	for _, s := range res.PipelineStages() {
+		nodes = append(nodes, stats.AnalysisNode{
+			Name:           s.Name,
+			Depth:          depth + 1,
+			WallTime:       s.WallTime,
+			LinesIn:        s.LinesIn,
+			LinesOut:       s.LinesOut,
+			BytesProcessed: s.BytesIn,
+		})
+	}
+	return nodes
+}
+
+// analyzeStepEvaluator measures the time spent in a step evaluator and its children.
+// Their statistics are joined into the parent context once the evaluator is closed.
+type analyzeStepEvaluator struct {
+	StepEvaluator
+
+	analyzer *analyzer
+	ctx      context.Context
+	stats    *stats.Context
+	wallTime time.Duration
+	result   stats.Result
+	closed   bool
+}
+
+func (e *analyzeStepEvaluator) Next() (bool, int64, StepResult) {
+	start := time.Now()
+	ok, ts, r := e.StepEvaluator.Next()
+	e.wallTime += time.Since(start)
+	return ok, ts, r
+}
+
+func (e *analyzeStepEvaluator) Close() error {
+	err := e.StepEvaluator.Close()
+	if e.closed {
+		return err
+	}
+	e.closed = true
+
+	e.result = e.stats.Result(0, 0, 0)
+	// the analyses of the downstream queries belong to this node only.
+	joined := e.result
+	joined.Analysis = nil
+	stats.JoinResults(e.ctx, joined)
+	return err
+}
+
+func (e *analyzeStepEvaluator) Explain(parent Node) {
+	if !e.analyzer.collecting {
+		e.StepEvaluator.Explain(parent)
+		return
+	}
+	i := len(e.analyzer.visited)
+	e.analyzer.visited = append(e.analyzer.visited, visitedEvaluator{ev: e, level: parent.level})
+	e.StepEvaluator.Explain(parent)
+	e.analyzer.visited[i].name = parent.lastChildText()
+}
+
+// RecordPipelineStages returns the expression with log pipelines recording the statistics of their
+// stages into the statistics context, if the query is executed with explain=analyze or pipeline_stats=true.
+func RecordPipelineStages[T syntax.Expr](ctx context.Context, expr T) (T, error) {
+	if !httpreq.IsExplainAnalyze(ctx) && !httpreq.IsPipelineStats(ctx) {
+		return expr, nil
+	}
+	statsCtx := stats.FromContext(ctx)
+	return syntax.RecordStages(expr, func(name string, stage log.Stage) log.Stage {
+		return log.NewStatsStage(stage, statsCtx.PipelineStage(name))
+	})
+}
+
+// PrintAnalysis prints the analysis of a query like the tree printed by explain,
+// each node followed by its statistics.
+func PrintAnalysis(a *stats.Analysis) string {
+	var (
+		sb      strings.Builder
+		tree    Node
+		parents []Node
+	)
+	for _, n := range a.GetNodes() {
+		depth := int(n.Depth)
+		if depth == 0 {
+			// merged analyses of different plans have several roots.
+			if parents != nil {
+				sb.WriteString(tree.String())
+			}
+			tree = NewTree()
+			parents = []Node{tree}
+		}
+		if depth >= len(parents) {
+			depth = len(parents) - 1
+		}
+		parents = append(parents[:depth+1], parents[depth].Child(formatAnalysisNode(n)))
+	}
+	if parents != nil {
+		sb.WriteString(tree.String())
+	}
+	return sb.String()
+}
+
+func formatAnalysisNode(n stats.AnalysisNode) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s (time: %s, lines: %d -> %d, bytes: %s",
+		n.Name, time.Duration(n.WallTime), n.LinesIn, n.LinesOut, humanize.Bytes(uint64(n.BytesProcessed)))
+	if n.Shards > 0 {
+		fmt.Fprintf(&sb, ", shards: %d", n.Shards)
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+)
+
+func TestAnalyze(t *testing.T) {
+	type node struct {
+		name              string
+		depth             int32
+		linesIn, linesOut int64
+		shards            int64
+	}
+	for _, tc := range []struct {
+		query    string
+		shards   int
+		expected []node
+	}{
+		{
+			query: `sum by (app) (count_over_time({app=~"foo|bar"} |= "1" | logfmt [30s]))`,
+			expected: []node{
+				{name: "[sum,  by (app)] VectorAgg"},
+				{name: `|= "1"`, depth: 1, linesIn: 120, linesOut: 30},
+				{name: "| logfmt", depth: 1, linesIn: 30, linesOut: 30},
+			},
+		},
+		{
+			query:  `sum by (app) (count_over_time({app=~"foo|bar"} |= "1" | logfmt [30s]))`,
+			shards: 2,
+			expected: []node{
+				{name: "[sum,  by (app)] VectorAgg", shards: 2},
+				{name: "Concat", depth: 1, shards: 2},
+				{name: "[sum,  by (app)] VectorAgg", depth: 2},
+				{name: `|= "1"`, depth: 3, linesIn: 120, linesOut: 30},
+				{name: "| logfmt", depth: 3, linesIn: 30, linesOut: 30},
+			},
+		},
+		{
+			query: `{app="foo"} |= "1" | logfmt`,
+			expected: []node{
+				{name: "Logs"},
+				{name: `|= "1"`, depth: 1, linesIn: 60, linesOut: 15},
+				{name: "| logfmt", depth: 1, linesIn: 15, linesOut: 15},
+			},
+		},
+		{
+			query:  `{app="foo"} |= "1" | logfmt`,
+			shards: 2,
+			expected: []node{
+				{name: "Concat", shards: 2},
+				{name: "Logs", depth: 1},
+				{name: `|= "1"`, depth: 2, linesIn: 60, linesOut: 15},
+				{name: "| logfmt", depth: 2, linesIn: 15, linesOut: 15},
+			},
+		},
+	} {
+		t.Run(fmt.Sprintf("%s/%d", tc.query, tc.shards), func(t *testing.T) {
+			analysis := analyzeQuery(t, tc.shards, tc.query)
+			require.NotNil(t, analysis)
+
+			actual := make([]node, 0, len(analysis.Nodes))
+			for _, n := range analysis.Nodes {
+				actual = append(actual, node{name: n.Name, depth: n.Depth, linesIn: n.LinesIn, linesOut: n.LinesOut, shards: n.Shards})
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAnalyzeDisabled(t *testing.T) {
+	querier := NewMockQuerier(1, []logproto.Stream{newStream(60, identity, `{app="foo"}`)})
+	engine := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+	params, err := NewLiteralParams(`count_over_time({app="foo"}[30s])`, time.Unix(0, 0), time.Unix(60, 0), 30*time.Second, 0, logproto.FORWARD, 1000, nil, nil)
+	require.NoError(t, err)
+
+	res, err := engine.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+	require.NoError(t, err)
+	require.Nil(t, res.Statistics.Analysis)
+}
+
+func TestPipelineStats(t *testing.T) {
+	querier := NewMockQuerier(1, []logproto.Stream{newStream(60, identity, `{app="foo"}`)})
+	engine := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+	params, err := NewLiteralParams(`{app="foo"} |= "1" | logfmt`, time.Unix(0, 0), time.Unix(60, 0), 0, 0, logproto.FORWARD, 1000, nil, nil)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	ctx = httpreq.InjectHeader(ctx, httpreq.LokiPipelineStatsHeader, "true")
+	res, err := engine.Query(params).Exec(ctx)
+	require.NoError(t, err)
+
+	require.Nil(t, res.Statistics.Analysis)
+	stages := res.Statistics.PipelineStages()
+	require.Len(t, stages, 2)
+	require.Equal(t, `|= "1"`, stages[0].Name)
+	require.Equal(t, int64(60), stages[0].LinesIn)
+	require.Equal(t, int64(15), stages[0].LinesOut)
+	require.Equal(t, "| logfmt", stages[1].Name)
+	require.Equal(t, int64(15), stages[1].LinesIn)
+	require.Equal(t, int64(15), stages[1].LinesOut)
+}
+
+func TestPrintAnalysis(t *testing.T) {
+	analysis := &stats.Analysis{Nodes: []stats.AnalysisNode{
+		{Name: "[sum,  by (app)] VectorAgg", WallTime: int64(3 * time.Millisecond), Shards: 2},
+		{Name: "Concat", Depth: 1, WallTime: int64(2 * time.Millisecond), Shards: 2},
+		{Name: "Logs", Depth: 2, WallTime: int64(time.Millisecond), LinesIn: 100, LinesOut: 10, BytesProcessed: 2048},
+		{Name: `|= "error"`, Depth: 3, WallTime: int64(time.Microsecond), LinesIn: 100, LinesOut: 10, BytesProcessed: 2048},
+		{Name: "| json", Depth: 3, WallTime: int64(2 * time.Microsecond), LinesIn: 10, LinesOut: 10, BytesProcessed: 300},
+		{Name: "Logs", WallTime: int64(time.Millisecond)},
+	}}
+
+	expected := `[sum,  by (app)] VectorAgg (time: 3ms, lines: 0 -> 0, bytes: 0 B, shards: 2)
+ └── Concat (time: 2ms, lines: 0 -> 0, bytes: 0 B, shards: 2)
+      └── Logs (time: 1ms, lines: 100 -> 10, bytes: 2.0 kB)
+           ├── |= "error" (time: 1µs, lines: 100 -> 10, bytes: 2.0 kB)
+           └── | json (time: 2µs, lines: 10 -> 10, bytes: 300 B)
+Logs (time: 1ms, lines: 0 -> 0, bytes: 0 B)
+`
+	require.Equal(t, expected, PrintAnalysis(analysis))
+}
+
+func analyzeQuery(t *testing.T, shards int, query string) *stats.Analysis {
+	t.Helper()
+
+	streams := []logproto.Stream{
+		newStream(60, identity, `{app="foo"}`),
+		newStream(60, identity, `{app="bar"}`),
+	}
+	querier := NewMockQuerier(max(shards, 1), streams)
+	engine := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	params, err := NewLiteralParams(query, time.Unix(0, 0), time.Unix(60, 0), 30*time.Second, 0, logproto.FORWARD, 1000, nil, nil)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	ctx = httpreq.InjectHeader(ctx, httpreq.LokiExplainHeader, httpreq.ExplainAnalyze)
+
+	var q Query
+	if shards > 0 {
+		mapper := NewShardMapper(NewPowerOfTwoStrategy(ConstantShards(shards)), nilShardMetrics, nil)
+		_, _, mapped, err := mapper.Parse(syntax.MustParseExpr(query))
+		require.NoError(t, err)
+		sharded := NewDownstreamEngine(EngineOpts{}, MockDownstreamer{engine}, NoLimits, log.NewNopLogger())
+		q = sharded.Query(ctx, ParamsWithExpressionOverride{Params: params, ExpressionOverride: mapped})
+	} else {
+		q = engine.Query(params)
+	}
+	res, err := q.Exec(ctx)
+	require.NoError(t, err)
+	return res.Statistics.Analysis
+}
@@ -0,0 +1,390 @@
+package logql
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/sketch"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+const (
+	CountDistinctSketchMatrixType = "CountDistinctSketchMatrix"
+)
+
+type (
+	CountDistinctVector []CountDistinctSample
+	CountDistinctMatrix []CountDistinctVector
+)
+
+func (q CountDistinctVector) Merge(right CountDistinctVector) (CountDistinctVector, error) {
+	// labels hash to vector index map
+	groups := streamHashPool.Get().(map[uint64]int)
+	defer func() {
+		clear(groups)
+		streamHashPool.Put(groups)
+	}()
+	for i, sample := range q {
+		groups[sample.Metric.Hash()] = i
+	}
+
+	for _, sample := range right {
+		i, ok := groups[sample.Metric.Hash()]
+		if !ok {
+			q = append(q, sample)
+			continue
+		}
+
+		if err := q[i].F.Merge(sample.F); err != nil {
+			return q, err
+		}
+	}
+
+	return q, nil
+}
+
+func (CountDistinctVector) SampleVector() promql.Vector {
+	return promql.Vector{}
+}
+
+func (CountDistinctVector) QuantileSketchVec() ProbabilisticQuantileVector {
+	return ProbabilisticQuantileVector{}
+}
+
+func (q CountDistinctVector) CountDistinctVec() CountDistinctVector {
+	return q
+}
+
+func (q CountDistinctVector) ToProto() (*logproto.CountDistinctSketchVector, error) {
+	samples := make([]*logproto.CountDistinctSketchSample, len(q))
+	for i, sample := range q {
+		s, err := sample.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = s
+	}
+	return &logproto.CountDistinctSketchVector{Samples: samples}, nil
+}
+
+func CountDistinctVectorFromProto(proto *logproto.CountDistinctSketchVector) (CountDistinctVector, error) {
+	out := make([]CountDistinctSample, len(proto.Samples))
+	for i, sample := range proto.Samples {
+		s, err := countDistinctSampleFromProto(sample)
+		if err != nil {
+			return CountDistinctVector{}, err
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+func (CountDistinctMatrix) String() string {
+	return "CountDistinctSketchMatrix()"
+}
+
+func (m CountDistinctMatrix) Merge(right CountDistinctMatrix) (CountDistinctMatrix, error) {
+	if len(m) != len(right) {
+		return nil, fmt.Errorf("failed to merge count distinct matrix: lengths differ %d!=%d", len(m), len(right))
+	}
+	var err error
+	for i, vec := range m {
+		m[i], err = vec.Merge(right[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge count distinct matrix: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+func (CountDistinctMatrix) Type() promql_parser.ValueType { return CountDistinctSketchMatrixType }
+
+func (m CountDistinctMatrix) ToProto() (*logproto.CountDistinctSketchMatrix, error) {
+	values := make([]*logproto.CountDistinctSketchVector, len(m))
+	for i, vec := range m {
+		v, err := vec.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return &logproto.CountDistinctSketchMatrix{Values: values}, nil
+}
+
+func CountDistinctMatrixFromProto(proto *logproto.CountDistinctSketchMatrix) (CountDistinctMatrix, error) {
+	out := make([]CountDistinctVector, len(proto.Values))
+	for i, v := range proto.Values {
+		s, err := CountDistinctVectorFromProto(v)
+		if err != nil {
+			return CountDistinctMatrix{}, err
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+type CountDistinctSample struct {
+	T int64
+	F *sketch.CountDistinctSketch
+
+	Metric labels.Labels
+}
+
+func (q CountDistinctSample) ToProto() (*logproto.CountDistinctSketchSample, error) {
+	metric := make([]*logproto.LabelPair, len(q.Metric))
+	for i, m := range q.Metric {
+		metric[i] = &logproto.LabelPair{Name: m.Name, Value: m.Value}
+	}
+	return q.F.ToProto(q.T, metric)
+}
+
+func countDistinctSampleFromProto(proto *logproto.CountDistinctSketchSample) (CountDistinctSample, error) {
+	s, err := sketch.CountDistinctSketchFromBinary(proto.Hyperloglog)
+	if err != nil {
+		return CountDistinctSample{}, err
+	}
+	out := CountDistinctSample{
+		T:      proto.TimestampMs,
+		F:      s,
+		Metric: make(labels.Labels, len(proto.Metric)),
+	}
+
+	for i, p := range proto.Metric {
+		out.Metric[i] = labels.Label{Name: p.Name, Value: p.Value}
+	}
+
+	return out, nil
+}
+
+// countDistinctOverTime returns the estimated number of distinct hashed values of the range.
+func countDistinctOverTime(samples []promql.FPoint) float64 {
+	return float64(countDistinctSketch(samples).Estimate())
+}
+
+func countDistinctSketch(samples []promql.FPoint) *sketch.CountDistinctSketch {
+	s := sketch.NewCountDistinctSketch()
+	for _, v := range samples {
+		s.AddHash(uint64(v.F))
+	}
+	return s
+}
+
+// CountDistinctOverTime streaming aggregates the hashed values of a range into a HyperLogLog sketch.
+type CountDistinctOverTime struct {
+	sketch *sketch.CountDistinctSketch
+}
+
+func (a *CountDistinctOverTime) agg(sample promql.FPoint) {
+	if a.sketch == nil {
+		a.sketch = sketch.NewCountDistinctSketch()
+	}
+	a.sketch.AddHash(uint64(sample.F))
+}
+
+func (a *CountDistinctOverTime) at() float64 {
+	if a.sketch == nil {
+		return 0
+	}
+	return float64(a.sketch.Estimate())
+}
+
+type CountDistinctSketchStepEvaluator struct {
+	iter RangeVectorIterator
+
+	err error
+}
+
+func (e *CountDistinctSketchStepEvaluator) Next() (bool, int64, StepResult) {
+	next := e.iter.Next()
+	if !next {
+		return false, 0, CountDistinctVector{}
+	}
+	ts, r := e.iter.At()
+	vec := r.CountDistinctVec()
+	for _, s := range vec {
+		// Errors are not allowed in metrics unless they've been specifically requested.
+		if s.Metric.Has(logqlmodel.ErrorLabel) && s.Metric.Get(logqlmodel.PreserveErrorLabel) != "true" {
+			e.err = logqlmodel.NewPipelineErr(s.Metric)
+			return false, 0, CountDistinctVector{}
+		}
+	}
+	return true, ts, vec
+}
+
+func (e *CountDistinctSketchStepEvaluator) Close() error { return e.iter.Close() }
+
+func (e *CountDistinctSketchStepEvaluator) Error() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.iter.Error()
+}
+
+func (e *CountDistinctSketchStepEvaluator) Explain(parent Node) {
+	parent.Child("CountDistinctSketch")
+}
+
+func newCountDistinctSketchIterator(
+	it iter.PeekingSampleIterator,
+	selRange, step, start, end, offset int64,
+) RangeVectorIterator {
+	inner := &batchRangeVectorIterator{
+		iter:     it,
+		step:     step,
+		end:      end,
+		selRange: selRange,
+		metrics:  map[string]labels.Labels{},
+		window:   map[string]*promql.Series{},
+		agg:      nil,
+		current:  start - step, // first loop iteration will set it to start
+		offset:   offset,
+	}
+	return &countDistinctSketchBatchRangeVectorIterator{
+		batchRangeVectorIterator: inner,
+	}
+}
+
+type countDistinctSketchBatchRangeVectorIterator struct {
+	*batchRangeVectorIterator
+}
+
+func (r *countDistinctSketchBatchRangeVectorIterator) At() (int64, StepResult) {
+	at := make([]CountDistinctSample, 0, len(r.window))
+	// convert ts from nano to milli seconds as the iterator work with nanoseconds
+	ts := r.current/1e+6 + r.offset/1e+6
+	for _, series := range r.window {
+		at = append(at, CountDistinctSample{
+			F:      countDistinctSketch(series.Floats),
+			T:      ts,
+			Metric: series.Metric,
+		})
+	}
+	return ts, CountDistinctVector(at)
+}
+
+// MergeCountDistinctSketchVector joins the results from stepEvaluator into a CountDistinctMatrix.
+func MergeCountDistinctSketchVector(next bool, r StepResult, stepEvaluator StepEvaluator, params Params) (promql_parser.Value, error) {
+	vec := r.CountDistinctVec()
+	if stepEvaluator.Error() != nil {
+		return nil, stepEvaluator.Error()
+	}
+
+	if GetRangeType(params) == InstantType {
+		return CountDistinctMatrix{vec}, nil
+	}
+
+	stepCount := int(math.Ceil(float64(params.End().Sub(params.Start()).Nanoseconds()) / float64(params.Step().Nanoseconds())))
+	if stepCount <= 0 {
+		stepCount = 1
+	}
+
+	result := make(CountDistinctMatrix, 0, stepCount)
+
+	for next {
+		result = append(result, vec)
+		next, _, r = stepEvaluator.Next()
+		vec = r.CountDistinctVec()
+		if stepEvaluator.Error() != nil {
+			return nil, stepEvaluator.Error()
+		}
+	}
+
+	return result, stepEvaluator.Error()
+}
+
+// CountDistinctSketchMatrixStepEvaluator steps through a matrix of HyperLogLog
+// sketch vectors.
+type CountDistinctSketchMatrixStepEvaluator struct {
+	start, end, ts time.Time
+	step           time.Duration
+	m              CountDistinctMatrix
+}
+
+func NewCountDistinctSketchMatrixStepEvaluator(m CountDistinctMatrix, params Params) *CountDistinctSketchMatrixStepEvaluator {
+	var (
+		start = params.Start()
+		end   = params.End()
+		step  = params.Step()
+	)
+	return &CountDistinctSketchMatrixStepEvaluator{
+		start: start,
+		end:   end,
+		ts:    start.Add(-step), // will be corrected on first Next() call
+		step:  step,
+		m:     m,
+	}
+}
+
+func (m *CountDistinctSketchMatrixStepEvaluator) Next() (bool, int64, StepResult) {
+	m.ts = m.ts.Add(m.step)
+	if m.ts.After(m.end) {
+		return false, 0, nil
+	}
+
+	ts := m.ts.UnixNano() / int64(time.Millisecond)
+
+	if len(m.m) == 0 {
+		return false, 0, nil
+	}
+
+	vec := m.m[0]
+
+	// Reset for next step
+	m.m = m.m[1:]
+
+	return true, ts, vec
+}
+
+func (*CountDistinctSketchMatrixStepEvaluator) Close() error { return nil }
+
+func (*CountDistinctSketchMatrixStepEvaluator) Error() error { return nil }
+
+func (*CountDistinctSketchMatrixStepEvaluator) Explain(parent Node) {
+	parent.Child("CountDistinctSketchMatrix")
+}
+
+// CountDistinctSketchVectorStepEvaluator evaluates the HyperLogLog sketches into
+// a promql.Vector of the estimated distinct counts.
+type CountDistinctSketchVectorStepEvaluator struct {
+	inner StepEvaluator
+}
+
+var _ StepEvaluator = NewCountDistinctSketchVectorStepEvaluator(nil)
+
+func NewCountDistinctSketchVectorStepEvaluator(inner StepEvaluator) *CountDistinctSketchVectorStepEvaluator {
+	return &CountDistinctSketchVectorStepEvaluator{
+		inner: inner,
+	}
+}
+
+func (e *CountDistinctSketchVectorStepEvaluator) Next() (bool, int64, StepResult) {
+	ok, ts, r := e.inner.Next()
+	if !ok {
+		return false, 0, SampleVector{}
+	}
+	countDistinctVec := r.CountDistinctVec()
+
+	vec := make(promql.Vector, len(countDistinctVec))
+
+	for i, s := range countDistinctVec {
+		vec[i] = promql.Sample{
+			T:      s.T,
+			F:      float64(s.F.Estimate()),
+			Metric: s.Metric,
+		}
+	}
+
+	return ok, ts, SampleVector(vec)
+}
+
+func (*CountDistinctSketchVectorStepEvaluator) Close() error { return nil }
+
+func (*CountDistinctSketchVectorStepEvaluator) Error() error { return nil }
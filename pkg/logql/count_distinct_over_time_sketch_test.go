@@ -0,0 +1,107 @@
+package logql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/sketch"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+func TestCountDistinctMatrixSerialization(t *testing.T) {
+	s := sketch.NewCountDistinctSketch()
+	for _, v := range []string{"a", "b", "c", "a"} {
+		s.Add(v)
+	}
+
+	matrix := CountDistinctMatrix([]CountDistinctVector{
+		[]CountDistinctSample{
+			{T: 10, F: s, Metric: []labels.Label{{Name: "foo", Value: "bar"}}},
+		},
+	})
+
+	proto, err := matrix.ToProto()
+	require.NoError(t, err)
+	require.Len(t, proto.Values, 1)
+	require.Len(t, proto.Values[0].Samples, 1)
+	require.Equal(t, int64(10), proto.Values[0].Samples[0].TimestampMs)
+
+	actual, err := CountDistinctMatrixFromProto(proto)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Len(t, actual[0], 1)
+	require.Equal(t, matrix[0][0].Metric, actual[0][0].Metric)
+	require.Equal(t, uint64(3), actual[0][0].F.Estimate())
+}
+
+func TestCountDistinctMatrixMerge(t *testing.T) {
+	sample := func(values ...string) CountDistinctSample {
+		s := sketch.NewCountDistinctSketch()
+		for _, v := range values {
+			s.Add(v)
+		}
+		return CountDistinctSample{F: s, Metric: labels.FromStrings("app", values[0])}
+	}
+
+	left := CountDistinctMatrix{{sample("foo", "a", "b")}, {sample("foo", "a")}}
+	right := CountDistinctMatrix{{sample("foo", "b", "c"), sample("bar", "a")}, {}}
+
+	merged, err := left.Merge(right)
+	require.NoError(t, err)
+	require.Len(t, merged[0], 2)
+	require.Equal(t, uint64(4), merged[0][0].F.Estimate())
+	require.Equal(t, uint64(2), merged[0][1].F.Estimate())
+	require.Len(t, merged[1], 1)
+
+	_, err = left.Merge(CountDistinctMatrix{})
+	require.Error(t, err)
+}
+
+func TestCountDistinctOverTime(t *testing.T) {
+	hash := func(v string) promql.FPoint {
+		f, err := log.LabelExtractorWithStages("user", log.ConvertHash, nil, false, false, nil, log.NoopStage)
+		require.NoError(t, err)
+		h, _, ok := f.ForStream(labels.EmptyLabels()).ProcessString(0, "", labels.Label{Name: "user", Value: v})
+		require.True(t, ok)
+		return promql.FPoint{F: h}
+	}
+	samples := []promql.FPoint{hash("alice"), hash("bob"), hash("alice"), hash("carol")}
+	require.Equal(t, 3., countDistinctOverTime(samples))
+
+	agg := &CountDistinctOverTime{}
+	require.Equal(t, 0., agg.at())
+	for _, s := range samples {
+		agg.agg(s)
+	}
+	require.Equal(t, 3., agg.at())
+}
+
+func TestCountDistinctSketchStepEvaluatorError(t *testing.T) {
+	iter := errorRangeVectorIterator{
+		result: CountDistinctVector([]CountDistinctSample{
+			{T: 43, F: nil, Metric: labels.Labels{{Name: logqlmodel.ErrorLabel, Value: "my error"}}},
+		}),
+	}
+	ev := CountDistinctSketchStepEvaluator{
+		iter: iter,
+	}
+	ok, _, _ := ev.Next()
+	require.False(t, ok)
+
+	err := ev.Error()
+	require.ErrorContains(t, err, "my error")
+}
+
+func TestJoinCountDistinctSketchVectorError(t *testing.T) {
+	result := CountDistinctVector{}
+	ev := errorStepEvaluator{
+		err: errors.New("could not evaluate"),
+	}
+	_, err := MergeCountDistinctSketchVector(true, result, ev, LiteralParams{})
+	require.ErrorContains(t, err, "could not evaluate")
+}
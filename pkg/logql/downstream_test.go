@@ -74,6 +74,14 @@ func TestMappingEquivalence(t *testing.T) {
 		{`first_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, false, []string{ShardFirstOverTime}},
 		{`last_over_time({a=~".+"} | logfmt | unwrap value [1s])`, false, []string{ShardLastOverTime}},
 		{`last_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, false, []string{ShardLastOverTime}},
+		{`histogram_over_time({a=~".+"} | logfmt | unwrap value [1s])`, false, nil},
+		{`histogram_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, true, nil},
+		{`sum by (a) (histogram_over_time({a=~".+"} | logfmt | unwrap value [1s]))`, true, nil},
+		{`count_distinct_over_time({a=~".+"} | logfmt | unwrap value [1s])`, false, nil},
+		{`count_distinct_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, false, nil},
+		{`count_distinct_over_time({a=~".+"} | logfmt | drop level | unwrap value [1s])`, false, nil},
+		{`max_over_time(rate({a=~".+"}[1s])[5s:1s])`, false, nil},
+		{`sum(avg_over_time(sum by (a) (rate({a=~".+"}[1s]))[5s:2s]))`, false, nil},
 		// topk prefers already-seen values in tiebreakers. Since the test data generates
 		// the same log lines for each series & the resulting promql.Vectors aren't deterministically
 		// sorted by labels, we don't expect this to pass.
@@ -615,6 +623,14 @@ func approximatelyEquals(t *testing.T, as, bs promql.Matrix) {
 			bSample := &b.Floats[j]
 			bSample.F = math.Round(bSample.F*1e6) / 1e6
 		}
+		require.Lenf(t, b.Histograms, len(a.Histograms), "at step %d", i)
+
+		for j := 0; j < len(a.Histograms); j++ {
+			aSample := a.Histograms[j].H
+			aSample.Sum = math.Round(aSample.Sum*1e6) / 1e6
+			bSample := b.Histograms[j].H
+			bSample.Sum = math.Round(bSample.Sum*1e6) / 1e6
+		}
 		require.Equalf(t, a, b, "metric %s differs from %s at %d", a.Metric, b.Metric, i)
 	}
 }
@@ -634,6 +650,14 @@ func approximatelyEqualsVector(t *testing.T, as, bs promql.Vector) {
 		bSample := b.F
 		bSample = math.Round(bSample*1e6) / 1e6
 		require.Equalf(t, aSample, bSample, "metric %s differs from %s at %d", a.Metric, b.Metric, i)
+
+		if a.H != nil || b.H != nil {
+			require.NotNil(t, a.H)
+			require.NotNil(t, b.H)
+			a.H.Sum = math.Round(a.H.Sum*1e6) / 1e6
+			b.H.Sum = math.Round(b.H.Sum*1e6) / 1e6
+			require.Equalf(t, a.H, b.H, "metric %s differs from %s at %d", a.Metric, b.Metric, i)
+		}
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	promql_parser "github.com/prometheus/prometheus/promql/parser"
@@ -176,7 +177,7 @@ func NewEngine(opts EngineOpts, q Querier, l Limits, logger log.Logger) *Engine
 	}
 	return &Engine{
 		logger:           logger,
-		evaluatorFactory: NewDefaultEvaluator(q, opts.MaxLookBackPeriod),
+		evaluatorFactory: NewDefaultEvaluator(q, l, opts.MaxLookBackPeriod),
 		limits:           l,
 		opts:             opts,
 	}
@@ -219,6 +220,8 @@ func (q *query) resultLength(res promql_parser.Value) int {
 		return int(r.Lines())
 	case ProbabilisticQuantileMatrix:
 		return len(r)
+	case CountDistinctMatrix:
+		return len(r)
 	default:
 		// for `scalar` or `string` or any other return type, we just return `0` as result length.
 		return 0
@@ -257,11 +260,20 @@ func (q *query) Exec(ctx context.Context) (logqlmodel.Result, error) {
 	statsCtx, ctx := stats.NewContext(ctx)
 	metadataCtx, ctx := metadata.NewContext(ctx)
 
+	var analyzer *analyzer
+	if httpreq.IsExplainAnalyze(ctx) {
+		analyzer = newAnalyzer(q.evaluator)
+		q.evaluator = analyzer
+	}
+
 	data, err := q.Eval(ctx)
 
 	queueTime, _ := ctx.Value(httpreq.QueryQueueTimeHTTPHeader).(time.Duration)
 
 	statResult := statsCtx.Result(time.Since(start), queueTime, q.resultLength(data))
+	if analyzer != nil {
+		statResult.Analysis = analyzer.analysis(q.params.GetExpression(), time.Since(start), statResult)
+	}
 	sp.LogKV(statResult.KVList()...)
 
 	status, _ := server.ClientHTTPStatusAndError(err)
@@ -376,6 +388,8 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 			return q.JoinSampleVector(next, vec, stepEvaluator, maxSeries, mfl)
 		case ProbabilisticQuantileVector:
 			return MergeQuantileSketchVector(next, vec, stepEvaluator, q.params)
+		case CountDistinctVector:
+			return MergeCountDistinctSketchVector(next, vec, stepEvaluator, q.params)
 		default:
 			return nil, fmt.Errorf("unsupported result type: %T", r)
 		}
@@ -399,10 +413,17 @@ func vectorsToSeries(vec promql.Vector, sm map[uint64]promql.Series) {
 			}
 			sm[hash] = series
 		}
-		series.Floats = append(series.Floats, promql.FPoint{
-			T: p.T,
-			F: p.F,
-		})
+		if p.H != nil {
+			series.Histograms = append(series.Histograms, promql.HPoint{
+				T: p.T,
+				H: p.H,
+			})
+		} else {
+			series.Floats = append(series.Floats, promql.FPoint{
+				T: p.T,
+				F: p.F,
+			})
+		}
 		sm[hash] = series
 	}
 }
@@ -474,6 +495,11 @@ func (q *query) checkIntervalLimit(expr syntax.SampleExpr, limit time.Duration)
 				return
 			}
 			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Left.Interval), model.Duration(limit))
+		case *syntax.SubqueryExpr:
+			if e.Range <= limit {
+				return
+			}
+			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Range), model.Duration(limit))
 		}
 	})
 	return err
@@ -591,4 +617,5 @@ type groupedAggregation struct {
 	groupCount  int
 	heap        vectorByValueHeap
 	reverseHeap vectorByReverseValueHeap
+	histogram   *histogram.FloatHistogram
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache/resultscache"
 	"github.com/grafana/loki/v3/pkg/util"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
 )
 
 type QueryRangeType string
@@ -284,17 +285,27 @@ func EvaluatorUnsupportedType(expr syntax.Expr, ev EvaluatorFactory) error {
 type DefaultEvaluator struct {
 	maxLookBackPeriod time.Duration
 	querier           Querier
+	limits            Limits
 }
 
 // NewDefaultEvaluator constructs a DefaultEvaluator
-func NewDefaultEvaluator(querier Querier, maxLookBackPeriod time.Duration) *DefaultEvaluator {
+func NewDefaultEvaluator(querier Querier, limits Limits, maxLookBackPeriod time.Duration) *DefaultEvaluator {
 	return &DefaultEvaluator{
 		querier:           querier,
+		limits:            limits,
 		maxLookBackPeriod: maxLookBackPeriod,
 	}
 }
 
 func (ev *DefaultEvaluator) NewIterator(ctx context.Context, expr syntax.LogSelectorExpr, q Params) (iter.EntryIterator, error) {
+	if e, ok := expr.(*syntax.JoinExpr); ok {
+		start := q.Start()
+		if GetRangeType(q) == InstantType {
+			start = start.Add(-ev.maxLookBackPeriod)
+		}
+		return ev.newJoinIterator(ctx, e, start, q.End(), q.Direction())
+	}
+
 	params := SelectLogParams{
 		QueryRequest: &logproto.QueryRequest{
 			Start:     q.Start(),
@@ -329,21 +340,8 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 			// if range expression is wrapped with a vector expression
 			// we should send the vector expression for allowing reducing labels at the source.
 			nextEvFactory = SampleEvaluatorFunc(func(ctx context.Context, _ SampleEvaluatorFactory, _ syntax.SampleExpr, _ Params) (StepEvaluator, error) {
-				it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
-					&logproto.SampleQueryRequest{
-						// extend startTs backwards by step
-						Start: q.Start().Add(-rangExpr.Left.Interval).Add(-rangExpr.Left.Offset),
-						// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
-						End: q.End().Add(-rangExpr.Left.Offset).Add(time.Nanosecond),
-						// intentionally send the vector for reducing labels.
-						Selector: e.String(),
-						Shards:   q.Shards(),
-						Plan: &plan.QueryPlan{
-							AST: expr,
-						},
-						StoreChunks: q.GetStoreChunks(),
-					},
-				})
+				// intentionally send the vector for reducing labels.
+				it, err := ev.selectSamples(ctx, e, rangExpr, q)
 				if err != nil {
 					return nil, err
 				}
@@ -352,25 +350,13 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 		}
 		return newVectorAggEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.RangeAggregationExpr:
-		it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
-			&logproto.SampleQueryRequest{
-				// extend startTs backwards by step
-				Start: q.Start().Add(-e.Left.Interval).Add(-e.Left.Offset),
-				// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
-				End: q.End().Add(-e.Left.Offset).Add(time.Nanosecond),
-				// intentionally send the vector for reducing labels.
-				Selector: e.String(),
-				Shards:   q.Shards(),
-				Plan: &plan.QueryPlan{
-					AST: expr,
-				},
-				StoreChunks: q.GetStoreChunks(),
-			},
-		})
+		it, err := ev.selectSamples(ctx, e, e, q)
 		if err != nil {
 			return nil, err
 		}
 		return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), e, q, e.Left.Offset)
+	case *syntax.SubqueryAggregationExpr:
+		return newSubqueryEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.BinOpExpr:
 		return newBinOpStepEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.LabelReplaceExpr:
@@ -386,6 +372,46 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 	}
 }
 
+// selectSamples selects the samples of the range aggregation rangeExpr for the query q
+// using the extractor of expr, which can reduce the labels at the source.
+// The samples of sampled queries are selected from the streams of the sample only.
+func (ev *DefaultEvaluator) selectSamples(ctx context.Context, expr syntax.SampleExpr, rangeExpr *syntax.RangeAggregationExpr, q Params) (_ iter.SampleIterator, err error) {
+	// extend startTs backwards by step
+	start := q.Start().Add(-rangeExpr.Left.Interval).Add(-rangeExpr.Left.Offset)
+	// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
+	end := q.End().Add(-rangeExpr.Left.Offset).Add(time.Nanosecond)
+
+	if join, ok := rangeExpr.Left.Left.(*syntax.JoinExpr); ok {
+		return ev.newJoinSampleIterator(ctx, expr, join, start, end)
+	}
+
+	shards, storeChunks := q.Shards(), q.GetStoreChunks()
+	if ratio, ok := httpreq.SampleRatio(ctx); ok {
+		var sampled bool
+		shards, sampled, err = SampleShards(shards, ratio)
+		if err != nil {
+			return nil, err
+		}
+		if !sampled {
+			return iter.NoopSampleIterator, nil
+		}
+		storeChunks = SampleChunkRefs(storeChunks, ratio)
+	}
+
+	return ev.querier.SelectSamples(ctx, SelectSampleParams{
+		&logproto.SampleQueryRequest{
+			Start:    start,
+			End:      end,
+			Selector: expr.String(),
+			Shards:   shards,
+			Plan: &plan.QueryPlan{
+				AST: expr,
+			},
+			StoreChunks: storeChunks,
+		},
+	})
+}
+
 func newVectorAggEvaluator(
 	ctx context.Context,
 	evFactory SampleEvaluatorFactory,
@@ -414,6 +440,7 @@ type VectorAggEvaluator struct {
 	expr          *syntax.VectorAggregationExpr
 	buf           []byte
 	lb            *labels.Builder
+	err           error
 }
 
 func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
@@ -466,6 +493,9 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 				mean:       s.F,
 				groupCount: 1,
 			}
+			if s.H != nil && e.expr.Operation == syntax.OpTypeSum {
+				result[groupingKey].histogram = s.H.Copy()
+			}
 
 			inputVecLen := len(vec)
 			resultSize := e.expr.Params
@@ -504,6 +534,16 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 		switch e.expr.Operation {
 		case syntax.OpTypeSum:
 			group.value += s.F
+			switch {
+			case s.H == nil:
+			case group.histogram == nil:
+				group.histogram = s.H.Copy()
+			default:
+				if _, err := group.histogram.Add(s.H); err != nil {
+					e.err = err
+					return false, 0, SampleVector{}
+				}
+			}
 
 		case syntax.OpTypeAvg:
 			group.groupCount++
@@ -601,12 +641,19 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 				})
 			}
 			continue // Bypass default append.
+		case syntax.OpTypeSum:
+			if aggr.histogram != nil {
+				// merging histograms leaves empty buckets and fragmented spans,
+				// compacting gives the same layout whatever the order they were added in.
+				aggr.histogram.Compact(0)
+			}
 		default:
 		}
 		vec = append(vec, promql.Sample{
 			Metric: aggr.labels,
 			T:      ts,
 			F:      aggr.value,
+			H:      aggr.histogram,
 		})
 	}
 	return next, ts, SampleVector(vec)
@@ -617,6 +664,9 @@ func (e *VectorAggEvaluator) Close() error {
 }
 
 func (e *VectorAggEvaluator) Error() error {
+	if e.err != nil {
+		return e.err
+	}
 	return e.nextEvaluator.Error()
 }
 
@@ -657,6 +707,17 @@ func newRangeAggEvaluator(
 		return &QuantileSketchStepEvaluator{
 			iter: iter,
 		}, nil
+	case syntax.OpRangeTypeCountDistinctSketch:
+		iter := newCountDistinctSketchIterator(
+			it,
+			expr.Left.Interval.Nanoseconds(),
+			q.Step().Nanoseconds(),
+			q.Start().UnixNano(), q.End().UnixNano(), o.Nanoseconds(),
+		)
+
+		return &CountDistinctSketchStepEvaluator{
+			iter: iter,
+		}, nil
 	case syntax.OpRangeTypeFirstWithTimestamp:
 		iter := newFirstWithTimestampIterator(
 			it,
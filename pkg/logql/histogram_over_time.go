@@ -0,0 +1,95 @@
+package logql
+
+import (
+	"math"
+	"sort"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+const (
+	// HistogramSchema is the schema of the native histograms built by histogram_over_time.
+	// Schema 3 gives 8 buckets per power of two, each bucket being ~9% wider than the previous one.
+	HistogramSchema int32 = 3
+	// HistogramZeroThreshold is the width of the zero bucket, the same default as the Prometheus client libraries.
+	HistogramZeroThreshold = 2.938735877055719e-39 // 2^-128
+)
+
+// histogramBounds are the upper bounds of the buckets of an octave (a power of two) for the histogram schema,
+// expressed as the fraction returned by math.Frexp.
+var histogramBounds = func() []float64 {
+	n := 1 << HistogramSchema
+	bounds := make([]float64, n)
+	for i := range bounds {
+		bounds[i] = math.Exp2(float64(i)/float64(n) - 1)
+	}
+	return bounds
+}()
+
+// histogramBucketKey returns the index of the bucket of the absolute value v.
+// Buckets are upper inclusive: the bucket i covers (2^((i-1)/2^schema), 2^(i/2^schema)].
+func histogramBucketKey(v float64) int32 {
+	frac, exp := math.Frexp(v)
+	return int32(sort.SearchFloat64s(histogramBounds, frac) + (exp-1)*len(histogramBounds))
+}
+
+// HistogramOverTime streaming aggregates the unwrapped values of a range into a native histogram.
+type HistogramOverTime struct {
+	count, sum, zeroCount float64
+	positive, negative    map[int32]float64
+}
+
+func newHistogramOverTime() *HistogramOverTime {
+	return &HistogramOverTime{
+		positive: map[int32]float64{},
+		negative: map[int32]float64{},
+	}
+}
+
+func (a *HistogramOverTime) agg(sample promql.FPoint) {
+	v := sample.F
+	a.count++
+	a.sum += v
+	switch {
+	case math.IsNaN(v), math.IsInf(v, 0):
+		// non-finite values are only accounted in the count and the sum, as in Prometheus.
+	case math.Abs(v) <= HistogramZeroThreshold:
+		a.zeroCount++
+	case v > 0:
+		a.positive[histogramBucketKey(v)]++
+	default:
+		a.negative[histogramBucketKey(-v)]++
+	}
+}
+
+// at returns the number of values of the histogram. The samples of the range vector iterators
+// only carry the histogram, at is there to implement RangeStreamingAgg.
+func (a *HistogramOverTime) at() float64 {
+	return a.count
+}
+
+func (a *HistogramOverTime) histogram() *histogram.FloatHistogram {
+	h := &histogram.FloatHistogram{
+		CounterResetHint: histogram.GaugeType,
+		Schema:           HistogramSchema,
+		ZeroThreshold:    HistogramZeroThreshold,
+		ZeroCount:        a.zeroCount,
+		Count:            a.count,
+		Sum:              a.sum,
+	}
+	h.PositiveSpans, h.PositiveBuckets = logqlmodel.HistogramSpans(a.positive)
+	h.NegativeSpans, h.NegativeBuckets = logqlmodel.HistogramSpans(a.negative)
+	return h
+}
+
+// histogramOverTime returns the native histogram of the values of the range.
+func histogramOverTime(samples []promql.FPoint) *histogram.FloatHistogram {
+	a := newHistogramOverTime()
+	for _, s := range samples {
+		a.agg(s)
+	}
+	return a.histogram()
+}
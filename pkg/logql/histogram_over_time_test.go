@@ -0,0 +1,84 @@
+package logql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+func TestHistogramBucketKey(t *testing.T) {
+	for _, tc := range []struct {
+		v   float64
+		key int32
+	}{
+		{1, 0},
+		{1.01, 1},
+		{2, 8},
+		{2.1, 9},
+		{0.5, -8},
+		{100, 54},
+		{1e-9, -239},
+	} {
+		require.Equal(t, tc.key, histogramBucketKey(tc.v), "value %v", tc.v)
+	}
+
+	// every value is in the bucket (2^((key-1)/8), 2^(key/8)].
+	for v := 0.001; v < 1e6; v *= 1.37 {
+		key := float64(histogramBucketKey(v))
+		require.Greater(t, v, math.Exp2((key-1)/8), "value %v", v)
+		require.LessOrEqual(t, v, math.Exp2(key/8)*(1+1e-12), "value %v", v)
+	}
+}
+
+func TestHistogramOverTime(t *testing.T) {
+	h := histogramOverTime([]promql.FPoint{
+		{T: 1, F: 1},
+		{T: 2, F: 1},
+		{T: 3, F: 2},
+		{T: 4, F: 0},
+		{T: 5, F: -0.5},
+		{T: 6, F: math.NaN()},
+	})
+	require.NoError(t, h.Validate())
+	require.Equal(t, histogram.GaugeType, h.CounterResetHint)
+	require.Equal(t, HistogramSchema, h.Schema)
+	require.Equal(t, 6., h.Count)
+	require.True(t, math.IsNaN(h.Sum))
+	require.Equal(t, 1., h.ZeroCount)
+	require.Equal(t, []histogram.Span{{Offset: 0, Length: 1}, {Offset: 7, Length: 1}}, h.PositiveSpans)
+	require.Equal(t, []float64{2, 1}, h.PositiveBuckets)
+	require.Equal(t, []histogram.Span{{Offset: -8, Length: 1}}, h.NegativeSpans)
+	require.Equal(t, []float64{1}, h.NegativeBuckets)
+}
+
+func Test_RangeVectorIterator_Histogram(t *testing.T) {
+	expected := histogramOverTime([]promql.FPoint{{T: 2, F: 1}, {T: 3, F: 2}, {T: 4, F: 3}})
+	expr := &syntax.RangeAggregationExpr{Left: &syntax.LogRange{Interval: 3}, Operation: syntax.OpRangeTypeHistogram}
+
+	for _, tc := range []struct {
+		name       string
+		start, end int64
+	}{
+		{"instant", 4, 4},
+		{"range", 3, 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			it, err := newRangeVectorIterator(sampleIter(false), expr, 3, 1, tc.start, tc.end, 0)
+			require.NoError(t, err)
+
+			//nolint:revive
+			for it.Next() {
+			}
+			_, value := it.At()
+			vec := value.SampleVector()
+			require.Len(t, vec, 1)
+			require.Zero(t, vec[0].F)
+			require.Equal(t, expected, vec[0].H)
+		})
+	}
+}
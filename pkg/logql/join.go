@@ -0,0 +1,373 @@
+package logql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/util"
+	"github.com/grafana/loki/v3/pkg/util/validation"
+)
+
+type joinSide int
+
+const (
+	joinLeft joinSide = iota
+	joinRight
+)
+
+// joinRightStreamHash is mixed into the stream hashes of the right side of a join so
+// that the heap merge never deduplicates an entry selected by both sides.
+const joinRightStreamHash = 0x9e3779b97f4a7c15
+
+// newJoinIterator selects both sides of the join and correlates their entries between start and end.
+func (ev *DefaultEvaluator) newJoinIterator(ctx context.Context, expr *syntax.JoinExpr, start, end time.Time, direction logproto.Direction) (iter.EntryIterator, error) {
+	tenants, _ := tenant.TenantIDs(ctx)
+	maxWindowCapture := func(id string) int { return ev.limits.MaxQueryJoinWindowEntries(ctx, id) }
+	maxWindow := validation.SmallestPositiveIntPerTenant(tenants, maxWindowCapture)
+
+	sides := make([]iter.EntryIterator, 0, 2)
+	for side, selector := range []syntax.LogSelectorExpr{expr.Left, expr.Right} {
+		it, err := ev.querier.SelectLogs(ctx, SelectLogParams{
+			QueryRequest: &logproto.QueryRequest{
+				// entries within the tolerance of the query range can still match.
+				Start:     start.Add(-expr.Tolerance),
+				End:       end.Add(expr.Tolerance),
+				Direction: direction,
+				Selector:  selector.String(),
+				Plan: &plan.QueryPlan{
+					AST: selector,
+				},
+			},
+		})
+		if err != nil {
+			for _, s := range sides {
+				util.LogErrorWithContext(ctx, "closing iterator", s.Close)
+			}
+			return nil, err
+		}
+		sides = append(sides, &joinSideIterator{EntryIterator: it, side: joinSide(side)})
+	}
+
+	return newJoinIterator(
+		iter.NewMergeEntryIterator(ctx, sides, direction),
+		expr, start, end, maxWindow,
+	), nil
+}
+
+// newJoinSampleIterator extracts the samples of expr from the entries of the join between start and end.
+func (ev *DefaultEvaluator) newJoinSampleIterator(ctx context.Context, expr syntax.SampleExpr, join *syntax.JoinExpr, start, end time.Time) (iter.SampleIterator, error) {
+	extractor, err := expr.Extractor()
+	if err != nil {
+		return nil, err
+	}
+	it, err := ev.newJoinIterator(ctx, join, start, end, logproto.FORWARD)
+	if err != nil {
+		return nil, err
+	}
+	return &joinSampleIterator{
+		EntryIterator: it,
+		extractor:     extractor,
+		streams:       map[string]log.StreamSampleExtractor{},
+	}, nil
+}
+
+// joinSideIterator marks the entries of one side of a join, the side is
+// prepended to the labels to be recovered after the heap merge.
+type joinSideIterator struct {
+	iter.EntryIterator
+	side joinSide
+
+	lbs, marked string
+}
+
+func (it *joinSideIterator) Labels() string {
+	if lbs := it.EntryIterator.Labels(); lbs != it.lbs || it.marked == "" {
+		it.lbs = lbs
+		if it.side == joinRight {
+			it.marked = "R" + lbs
+		} else {
+			it.marked = "L" + lbs
+		}
+	}
+	return it.marked
+}
+
+func (it *joinSideIterator) StreamHash() uint64 {
+	if it.side == joinRight {
+		return it.EntryIterator.StreamHash() ^ joinRightStreamHash
+	}
+	return it.EntryIterator.StreamHash()
+}
+
+type joinEntry struct {
+	logproto.Entry
+	labels     string
+	streamHash uint64
+	side       joinSide
+	key        string
+	matched    bool
+}
+
+type joinStream struct {
+	labels string
+	side   joinSide
+	key    string
+	ok     bool
+}
+
+// joinIterator correlates the entries of the two sides of a join merged in the query direction.
+// Entries are kept in a window until no entry of the other side within the tolerance can follow,
+// they are then returned if they are matched, or not matched for join_unmatched.
+type joinIterator struct {
+	iter      iter.EntryIterator
+	expr      *syntax.JoinExpr
+	start     time.Time
+	end       time.Time
+	maxWindow int
+
+	streams map[string]joinStream
+	// window holds the entries that can still be matched in iteration order,
+	// index holds the same entries by side and key.
+	window []*joinEntry
+	index  [2]map[string][]*joinEntry
+
+	buf  []*joinEntry
+	curr *joinEntry
+	done bool
+	err  error
+}
+
+func newJoinIterator(it iter.EntryIterator, expr *syntax.JoinExpr, start, end time.Time, maxWindow int) *joinIterator {
+	return &joinIterator{
+		iter:      it,
+		expr:      expr,
+		start:     start,
+		end:       end,
+		maxWindow: maxWindow,
+		streams:   map[string]joinStream{},
+		index:     [2]map[string][]*joinEntry{{}, {}},
+	}
+}
+
+func (it *joinIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if !it.iter.Next() {
+			it.done = true
+			it.evict(time.Time{})
+			continue
+		}
+		it.push(it.iter.At(), it.iter.Labels(), it.iter.StreamHash())
+	}
+	it.curr, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+func (it *joinIterator) push(entry logproto.Entry, lbs string, streamHash uint64) {
+	it.evict(entry.Timestamp)
+
+	stream, err := it.stream(lbs)
+	if err != nil {
+		it.err = err
+		return
+	}
+	if !stream.ok {
+		// entries without the join labels cannot be correlated.
+		return
+	}
+	if stream.side == joinRight {
+		streamHash ^= joinRightStreamHash
+	}
+
+	e := &joinEntry{
+		Entry:      entry,
+		labels:     stream.labels,
+		streamHash: streamHash,
+		side:       stream.side,
+		key:        stream.key,
+	}
+	// every entry left in the window is within the tolerance.
+	for _, other := range it.index[1-e.side][e.key] {
+		other.matched = true
+		e.matched = true
+	}
+	it.window = append(it.window, e)
+	it.index[e.side][e.key] = append(it.index[e.side][e.key], e)
+
+	if it.maxWindow > 0 && len(it.window) > it.maxWindow {
+		it.err = logqlmodel.NewJoinWindowLimitError(it.maxWindow)
+	}
+}
+
+// evict removes the entries that are further than the tolerance from ts, or all of them if ts is zero.
+func (it *joinIterator) evict(ts time.Time) {
+	for len(it.window) > 0 {
+		e := it.window[0]
+		if !ts.IsZero() && absDuration(ts.Sub(e.Timestamp)) <= it.expr.Tolerance {
+			return
+		}
+		it.window[0] = nil
+		it.window = it.window[1:]
+
+		// entries of a key are evicted in the order they were added.
+		byKey := it.index[e.side][e.key]
+		byKey[0] = nil
+		if len(byKey) == 1 {
+			delete(it.index[e.side], e.key)
+		} else {
+			it.index[e.side][e.key] = byKey[1:]
+		}
+
+		if it.keep(e) {
+			it.buf = append(it.buf, e)
+		}
+	}
+}
+
+func (it *joinIterator) keep(e *joinEntry) bool {
+	if e.Timestamp.Before(it.start) || !e.Timestamp.Before(it.end) {
+		return false
+	}
+	if it.expr.Operation == syntax.OpJoinUnmatched {
+		return e.side == joinLeft && !e.matched
+	}
+	return e.matched
+}
+
+// stream returns the side, the original labels and the join key of the labels of a merged entry.
+func (it *joinIterator) stream(lbs string) (joinStream, error) {
+	if s, ok := it.streams[lbs]; ok {
+		return s, nil
+	}
+	s := joinStream{side: joinLeft, labels: lbs[1:]}
+	if lbs[0] == 'R' {
+		s.side = joinRight
+	}
+	parsed, err := syntax.ParseLabels(s.labels)
+	if err != nil {
+		return joinStream{}, err
+	}
+	values := make([]string, 0, len(it.expr.On))
+	for _, name := range it.expr.On {
+		v := parsed.Get(name)
+		if v == "" {
+			it.streams[lbs] = s
+			return s, nil
+		}
+		values = append(values, v)
+	}
+	s.key = strings.Join(values, "\xff")
+	s.ok = true
+	it.streams[lbs] = s
+	return s, nil
+}
+
+func (it *joinIterator) At() logproto.Entry {
+	return it.curr.Entry
+}
+
+func (it *joinIterator) Labels() string {
+	return it.curr.labels
+}
+
+func (it *joinIterator) StreamHash() uint64 {
+	return it.curr.streamHash
+}
+
+func (it *joinIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.iter.Err()
+}
+
+func (it *joinIterator) Close() error {
+	it.window, it.buf = nil, nil
+	return it.iter.Close()
+}
+
+// joinSampleIterator extracts samples from the entries of a join.
+type joinSampleIterator struct {
+	iter.EntryIterator
+	extractor log.SampleExtractor
+	streams   map[string]log.StreamSampleExtractor
+
+	curr       logproto.Sample
+	currLabels string
+	// pending are the next samples of the current entry, an entry exploded by the extractor has several samples.
+	pending []joinSample
+	err     error
+}
+
+type joinSample struct {
+	sample logproto.Sample
+	labels string
+}
+
+func (it *joinSampleIterator) Next() bool {
+	for len(it.pending) == 0 && it.EntryIterator.Next() {
+		lbs := it.EntryIterator.Labels()
+		stream, ok := it.streams[lbs]
+		if !ok {
+			parsed, err := syntax.ParseLabels(lbs)
+			if err != nil {
+				it.err = err
+				return false
+			}
+			stream = it.extractor.ForStream(parsed)
+			it.streams[lbs] = stream
+		}
+		entry := it.EntryIterator.At()
+		log.ExtractEach(stream, entry.Timestamp.UnixNano(), entry.Line, nil, func(n int, value float64, result log.LabelsResult) {
+			it.pending = append(it.pending, joinSample{
+				sample: logproto.Sample{
+					Timestamp: entry.Timestamp.UnixNano(),
+					Value:     value,
+					// the samples of an exploded entry must not be deduplicated.
+					Hash: xxhash.Sum64String(entry.Line) + uint64(n),
+				},
+				labels: result.String(),
+			})
+		})
+	}
+	if len(it.pending) == 0 {
+		return false
+	}
+	it.curr, it.currLabels = it.pending[0].sample, it.pending[0].labels
+	it.pending = it.pending[1:]
+	return true
+}
+
+func (it *joinSampleIterator) At() logproto.Sample {
+	return it.curr
+}
+
+func (it *joinSampleIterator) Labels() string {
+	return it.currLabels
+}
+
+func (it *joinSampleIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.EntryIterator.Err()
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
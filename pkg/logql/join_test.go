@@ -0,0 +1,176 @@
+package logql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+func joinTestStreams() []logproto.Stream {
+	entry := func(sec int64, line string) logproto.Entry {
+		return logproto.Entry{Timestamp: time.Unix(sec, 0), Line: line}
+	}
+	return []logproto.Stream{
+		{
+			Labels: `{app="gateway"}`,
+			Entries: []logproto.Entry{
+				entry(1, "request_id=a"),
+				entry(10, "request_id=b"),
+				entry(20, "request_id=c"),
+				entry(30, "path=/health"),
+			},
+		},
+		{
+			Labels: `{app="backend"}`,
+			Entries: []logproto.Entry{
+				entry(2, "request_id=a"),
+				entry(18, "request_id=b"),
+				entry(24, "request_id=c"),
+				entry(40, "request_id=d"),
+			},
+		},
+	}
+}
+
+func TestJoinLogQuery(t *testing.T) {
+	for _, tc := range []struct {
+		query     string
+		direction logproto.Direction
+		expected  []string
+	}{
+		{
+			query:     `join(5s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id)`,
+			direction: logproto.FORWARD,
+			expected:  []string{"gateway a", "backend a", "gateway c", "backend c"},
+		},
+		{
+			query:     `join(5s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id)`,
+			direction: logproto.BACKWARD,
+			expected:  []string{"backend c", "gateway c", "backend a", "gateway a"},
+		},
+		{
+			query:     `join(10s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id)`,
+			direction: logproto.FORWARD,
+			expected:  []string{"gateway a", "backend a", "gateway b", "backend b", "gateway c", "backend c"},
+		},
+		{
+			query:     `join_unmatched(5s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id)`,
+			direction: logproto.FORWARD,
+			expected:  []string{"gateway b"},
+		},
+		{
+			query:     `join_unmatched(5s, {app="backend"} | logfmt, {app="gateway"} | logfmt) on (request_id)`,
+			direction: logproto.BACKWARD,
+			expected:  []string{"backend d", "backend b"},
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			eng := NewEngine(EngineOpts{}, NewMockQuerier(0, joinTestStreams()), NoLimits, nil)
+			params, err := NewLiteralParams(tc.query, time.Unix(0, 0), time.Unix(60, 0), 0, 0, tc.direction, 100, nil, nil)
+			require.NoError(t, err)
+
+			res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+
+			it := iter.NewStreamsIterator(res.Data.(logqlmodel.Streams), tc.direction)
+			var actual []string
+			for it.Next() {
+				lbs, err := syntax.ParseLabels(it.Labels())
+				require.NoError(t, err)
+				actual = append(actual, lbs.Get("app")+" "+lbs.Get("request_id"))
+			}
+			require.NoError(t, it.Close())
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestJoinMetricQuery(t *testing.T) {
+	eng := NewEngine(EngineOpts{}, NewMockQuerier(0, joinTestStreams()), NoLimits, nil)
+	params, err := NewLiteralParams(
+		`sum(count_over_time(join_unmatched(5s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id) [30s]))`,
+		time.Unix(30, 0), time.Unix(60, 0), 30*time.Second, 0, logproto.FORWARD, 0, nil, nil,
+	)
+	require.NoError(t, err)
+
+	res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+	require.NoError(t, err)
+	require.Equal(t, promql.Matrix{
+		{
+			Metric: labels.EmptyLabels(),
+			Floats: []promql.FPoint{{T: 30000, F: 1}},
+		},
+	}, res.Data)
+}
+
+func TestJoinExplodedLines(t *testing.T) {
+	streams := []logproto.Stream{
+		{
+			Labels:  `{app="gateway"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: `{"requests":[{"request_id":"a"},{"request_id":"b"},{"request_id":"c"}]}`}},
+		},
+		{
+			Labels: `{app="backend"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(2, 0), Line: "request_id=a"},
+				{Timestamp: time.Unix(3, 0), Line: "request_id=c"},
+			},
+		},
+	}
+	eng := NewEngine(EngineOpts{}, NewMockQuerier(0, streams), NoLimits, nil)
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	params, err := NewLiteralParams(
+		`join(5s, {app="gateway"} | json_array "requests" | json, {app="backend"} | logfmt) on (request_id)`,
+		time.Unix(0, 0), time.Unix(60, 0), 0, 0, logproto.FORWARD, 100, nil, nil,
+	)
+	require.NoError(t, err)
+	res, err := eng.Query(params).Exec(ctx)
+	require.NoError(t, err)
+
+	it := iter.NewStreamsIterator(res.Data.(logqlmodel.Streams), logproto.FORWARD)
+	var actual []string
+	for it.Next() {
+		lbs, err := syntax.ParseLabels(it.Labels())
+		require.NoError(t, err)
+		actual = append(actual, lbs.Get("app")+" "+lbs.Get("request_id"))
+	}
+	require.NoError(t, it.Close())
+	require.Equal(t, []string{"gateway a", "gateway c", "backend a", "backend c"}, actual)
+
+	params, err = NewLiteralParams(
+		`sum(count_over_time(join_unmatched(5s, {app="gateway"} | json_array "requests" | json, {app="backend"} | logfmt) on (request_id) [30s]))`,
+		time.Unix(30, 0), time.Unix(30, 0), 0, 0, logproto.FORWARD, 0, nil, nil,
+	)
+	require.NoError(t, err)
+	res, err = eng.Query(params).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, promql.Vector{{Metric: labels.EmptyLabels(), T: 30000, F: 1}}, res.Data)
+}
+
+func TestJoinWindowLimit(t *testing.T) {
+	streams := []logproto.Stream{{Labels: `{app="gateway"}`}}
+	for i := int64(0); i < 10; i++ {
+		streams[0].Entries = append(streams[0].Entries, logproto.Entry{Timestamp: time.Unix(i, 0), Line: "request_id=a"})
+	}
+
+	eng := NewEngine(EngineOpts{}, NewMockQuerier(0, streams), fakeLimits{maxJoinWindow: 5}, nil)
+	params, err := NewLiteralParams(
+		`join(10s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id)`,
+		time.Unix(0, 0), time.Unix(60, 0), 0, 0, logproto.FORWARD, 100, nil, nil,
+	)
+	require.NoError(t, err)
+
+	_, err = eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+	require.ErrorIs(t, err, logqlmodel.ErrLimit)
+}
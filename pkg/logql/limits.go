@@ -18,6 +18,7 @@ type Limits interface {
 	MaxQueryRange(ctx context.Context, userID string) time.Duration
 	QueryTimeout(context.Context, string) time.Duration
 	BlockedQueries(context.Context, string) []*validation.BlockedQuery
+	MaxQueryJoinWindowEntries(context.Context, string) int
 }
 
 type fakeLimits struct {
@@ -26,6 +27,7 @@ type fakeLimits struct {
 	blockedQueries []*validation.BlockedQuery
 	rangeLimit     time.Duration
 	requiredLabels []string
+	maxJoinWindow  int
 }
 
 func (f fakeLimits) MaxQuerySeries(_ context.Context, _ string) int {
@@ -44,6 +46,10 @@ func (f fakeLimits) BlockedQueries(_ context.Context, _ string) []*validation.Bl
 	return f.blockedQueries
 }
 
+func (f fakeLimits) MaxQueryJoinWindowEntries(_ context.Context, _ string) int {
+	return f.maxJoinWindow
+}
+
 func (f fakeLimits) RequiredLabels(_ context.Context, _ string) []string {
 	return f.requiredLabels
 }
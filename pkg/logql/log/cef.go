@@ -0,0 +1,327 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+var (
+	_ Stage = &CEFParser{}
+	_ Stage = &LEEFParser{}
+
+	cefPrefix  = []byte("CEF:")
+	leefPrefix = []byte("LEEF:")
+
+	// cefHeaderLabels are the labels of the pipe-delimited CEF header fields, in order.
+	cefHeaderLabels = []string{"cef_version", "device_vendor", "device_product", "device_version", "device_event_class_id", "name", "severity"}
+	// leefHeaderLabels are the labels of the pipe-delimited LEEF header fields, in order.
+	leefHeaderLabels = []string{"leef_version", "vendor", "product", "product_version", "event_id"}
+
+	errMissingCEFHeader     = errors.New("no CEF header found")
+	errIncompleteCEFHeader  = errors.New("incomplete CEF header")
+	errMissingLEEFHeader    = errors.New("no LEEF header found")
+	errIncompleteLEEFHeader = errors.New("incomplete LEEF header")
+)
+
+// securityLogParser holds the state shared by the CEF and LEEF parsers to extract labels.
+type securityLogParser struct {
+	keys     internedStringSet
+	valueBuf []byte // buffer used to unescape values
+}
+
+// set adds the label to the builder and tells if parsing should continue.
+// errLabelDoesNotMatch is returned when the line can be thrown away, errFoundAllLabels when all required labels are extracted.
+func (p *securityLogParser) set(key []byte, value []byte, lbs *LabelsBuilder) error {
+	parserHints := lbs.ParserLabelHints()
+	name, ok := p.keys.Get(key, func() (string, bool) {
+		sanitized := sanitizeLabelKey(string(key), true)
+		if len(sanitized) == 0 {
+			return "", false
+		}
+		if lbs.BaseHas(sanitized) {
+			sanitized = sanitized + duplicateSuffix
+		}
+		if !parserHints.ShouldExtract(sanitized) {
+			return "", false
+		}
+		return sanitized, true
+	})
+	if !ok {
+		return nil
+	}
+
+	if bytes.ContainsRune(value, utf8.RuneError) {
+		value = bytes.Map(removeInvalidUtf, value)
+	}
+
+	lbs.Set(ParsedLabel, name, string(value))
+	if !parserHints.ShouldContinueParsingLine(name, lbs) {
+		return errLabelDoesNotMatch
+	}
+	if parserHints.AllRequiredExtracted() {
+		return errFoundAllLabels
+	}
+	return nil
+}
+
+// done handles the result of parsing a line the same way for both parsers.
+func (p *securityLogParser) done(line []byte, err error, errMsg string, lbs *LabelsBuilder) ([]byte, bool) {
+	switch {
+	case err == nil, errors.Is(err, errFoundAllLabels):
+		return line, true
+	case errors.Is(err, errLabelDoesNotMatch):
+		return line, false
+	}
+
+	addErrLabel(errMsg, err, lbs)
+	if !lbs.ParserLabelHints().ShouldContinueParsingLine(logqlmodel.ErrorLabel, lbs) {
+		return line, false
+	}
+	return line, true
+}
+
+// unescape replaces the backslash escape sequences of the value using the buffer.
+// `\n` and `\r` are only unescaped in extension values.
+func (p *securityLogParser) unescape(value []byte, extension bool) []byte {
+	if bytes.IndexByte(value, '\\') < 0 {
+		return value
+	}
+	p.valueBuf = p.valueBuf[:0]
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' || i+1 == len(value) {
+			p.valueBuf = append(p.valueBuf, c)
+			continue
+		}
+		i++
+		switch next := value[i]; {
+		case extension && next == 'n':
+			p.valueBuf = append(p.valueBuf, '\n')
+		case extension && next == 'r':
+			p.valueBuf = append(p.valueBuf, '\r')
+		case next == '\\', next == '|', next == '=':
+			p.valueBuf = append(p.valueBuf, next)
+		default:
+			p.valueBuf = append(p.valueBuf, c, next)
+		}
+	}
+	return p.valueBuf
+}
+
+type CEFParser struct {
+	securityLogParser
+}
+
+// NewCEFParser creates a parser that extracts labels from an ArcSight Common Event Format (CEF) log line.
+// The header fields are extracted as cef_version, device_vendor, device_product, device_version,
+// device_event_class_id, name and severity, and each extension key is extracted into a respective label.
+// Any prefix before `CEF:`, like a syslog header, is ignored.
+func NewCEFParser() *CEFParser {
+	return &CEFParser{
+		securityLogParser: securityLogParser{keys: internedStringSet{}},
+	}
+}
+
+func (c *CEFParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+	return c.done(line, c.parse(line, lbs), errCEF, lbs)
+}
+
+func (c *CEFParser) parse(line []byte, lbs *LabelsBuilder) error {
+	start := bytes.Index(line, cefPrefix)
+	if start < 0 {
+		return errMissingCEFHeader
+	}
+	data := line[start+len(cefPrefix):]
+
+	// the header is made of 7 fields separated by unescaped pipes.
+	for _, name := range cefHeaderLabels {
+		end := indexUnescaped(data, '|')
+		if end < 0 {
+			return errIncompleteCEFHeader
+		}
+		if err := c.set(unsafeGetBytes(name), c.unescape(data[:end], false), lbs); err != nil {
+			return err
+		}
+		data = data[end+1:]
+	}
+
+	return c.parseExtension(data, lbs)
+}
+
+// parseExtension parses the space separated key=value pairs of the CEF extension.
+// Values can contain spaces, a value ends with the last space before the next unescaped `=`.
+func (c *CEFParser) parseExtension(data []byte, lbs *LabelsBuilder) error {
+	var key []byte
+	valueStart := -1
+	// lastSpace is the position of the last space before i, escaped or not.
+	lastSpace := -1
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			// skip the escaped character.
+			i++
+			if i < len(data) && data[i] == ' ' {
+				lastSpace = i
+			}
+			continue
+		case ' ':
+			lastSpace = i
+			continue
+		case '=':
+		default:
+			continue
+		}
+
+		// the key of this pair is the word preceding the '=' sign.
+		keyStart := lastSpace + 1
+		if valueStart >= 0 && keyStart <= valueStart {
+			// no space since the previous key, this is an unescaped '=' in the value.
+			continue
+		}
+		if keyStart == i {
+			continue
+		}
+		if key != nil {
+			if err := c.set(key, c.unescape(bytes.TrimRight(data[valueStart:keyStart], " "), true), lbs); err != nil {
+				return err
+			}
+		}
+		key = data[keyStart:i]
+		valueStart = i + 1
+	}
+
+	if key != nil {
+		return c.set(key, c.unescape(bytes.TrimRight(data[valueStart:], " "), true), lbs)
+	}
+	return nil
+}
+
+func (c *CEFParser) RequiredLabelNames() []string { return []string{} }
+
+type LEEFParser struct {
+	securityLogParser
+}
+
+// NewLEEFParser creates a parser that extracts labels from an IBM QRadar Log Event Extended Format (LEEF) log line.
+// The header fields are extracted as leef_version, vendor, product, product_version and event_id,
+// and each attribute is extracted into a respective label.
+// Attributes are separated by a tab in LEEF 1.0 and by the delimiter defined in the header in LEEF 2.0.
+func NewLEEFParser() *LEEFParser {
+	return &LEEFParser{
+		securityLogParser: securityLogParser{keys: internedStringSet{}},
+	}
+}
+
+func (l *LEEFParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+	return l.done(line, l.parse(line, lbs), errLEEF, lbs)
+}
+
+func (l *LEEFParser) parse(line []byte, lbs *LabelsBuilder) error {
+	start := bytes.Index(line, leefPrefix)
+	if start < 0 {
+		return errMissingLEEFHeader
+	}
+	data := line[start+len(leefPrefix):]
+
+	var version []byte
+	for i, name := range leefHeaderLabels {
+		end := bytes.IndexByte(data, '|')
+		if end < 0 {
+			return errIncompleteLEEFHeader
+		}
+		if i == 0 {
+			version = data[:end]
+		}
+		if err := l.set(unsafeGetBytes(name), data[:end], lbs); err != nil {
+			return err
+		}
+		data = data[end+1:]
+	}
+
+	delimiter := []byte{'\t'}
+	if !bytes.HasPrefix(version, []byte("1.")) {
+		// LEEF 2.0 adds the attribute delimiter to the header.
+		end := bytes.IndexByte(data, '|')
+		if end < 0 {
+			return errIncompleteLEEFHeader
+		}
+		d, err := parseLEEFDelimiter(data[:end])
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			delimiter = d
+		}
+		data = data[end+1:]
+	}
+
+	for len(data) > 0 {
+		attr := data
+		if end := bytes.Index(data, delimiter); end >= 0 {
+			attr, data = data[:end], data[end+len(delimiter):]
+		} else {
+			data = nil
+		}
+
+		eq := bytes.IndexByte(attr, '=')
+		if eq <= 0 {
+			continue
+		}
+		if err := l.set(attr[:eq], attr[eq+1:], lbs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLEEFDelimiter parses the LEEF 2.0 delimiter, either a single character or its hex value like `x5E` or `0x5E`.
+// A nil delimiter is returned when the header field is empty.
+func parseLEEFDelimiter(d []byte) ([]byte, error) {
+	switch {
+	case len(d) == 0:
+		return nil, nil
+	case utf8.RuneCount(d) == 1:
+		return d, nil
+	}
+
+	var hex []byte
+	switch {
+	case bytes.HasPrefix(d, []byte("0x")):
+		hex = d[2:]
+	case bytes.HasPrefix(d, []byte("x")):
+		hex = d[1:]
+	default:
+		return nil, fmt.Errorf("invalid LEEF delimiter %q", d)
+	}
+	r, err := strconv.ParseUint(string(hex), 16, 32)
+	if err != nil || !utf8.ValidRune(rune(r)) {
+		return nil, fmt.Errorf("invalid LEEF delimiter %q", d)
+	}
+	return utf8.AppendRune(nil, rune(r)), nil
+}
+
+func (l *LEEFParser) RequiredLabelNames() []string { return []string{} }
+
+// indexUnescaped returns the index of the first instance of c not escaped with a backslash, or -1.
+func indexUnescaped(data []byte, c byte) int {
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
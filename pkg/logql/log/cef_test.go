@@ -0,0 +1,238 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+func Test_cefParser_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  []byte
+		lbs   labels.Labels
+		want  labels.Labels
+		hints ParserHint
+	}{
+		{
+			"header and extension",
+			[]byte(`Sep 19 08:26:10 host CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232`),
+			labels.EmptyLabels(),
+			labels.FromStrings("cef_version", "0",
+				"device_vendor", "Security",
+				"device_product", "threatmanager",
+				"device_version", "1.0",
+				"device_event_class_id", "100",
+				"name", "worm successfully stopped",
+				"severity", "10",
+				"src", "10.0.0.1",
+				"dst", "2.1.2.2",
+				"spt", "1232",
+			),
+			NoParserHints(),
+		},
+		{
+			"escaped header and extension values",
+			[]byte(`CEF:0|security|threat\|manager|1.0|100|detected a \\ in message|10|act=blocked a \= dst=1.1.1.1 msg=line1\nline2 with spaces cs1Label=url cs1=http://foo.com/?a\=b`),
+			labels.EmptyLabels(),
+			labels.FromStrings("cef_version", "0",
+				"device_vendor", "security",
+				"device_product", "threat|manager",
+				"device_version", "1.0",
+				"device_event_class_id", "100",
+				"name", `detected a \ in message`,
+				"severity", "10",
+				"act", "blocked a =",
+				"dst", "1.1.1.1",
+				"msg", "line1\nline2 with spaces",
+				"cs1Label", "url",
+				"cs1", "http://foo.com/?a=b",
+			),
+			NoParserHints(),
+		},
+		{
+			"unescaped equal signs in values",
+			[]byte(`CEF:0|Security|threatmanager|1.0|100|worm|10|request=/?a=b=c&d=e suser=bob cs1=x==y`),
+			labels.EmptyLabels(),
+			labels.FromStrings("cef_version", "0",
+				"device_vendor", "Security",
+				"device_product", "threatmanager",
+				"device_version", "1.0",
+				"device_event_class_id", "100",
+				"name", "worm",
+				"severity", "10",
+				"request", "/?a=b=c&d=e",
+				"suser", "bob",
+				"cs1", "x==y",
+			),
+			NoParserHints(),
+		},
+		{
+			"empty extension and duplicate",
+			[]byte(`CEF:1|Vendor|Product|2|id|name|Low|`),
+			labels.FromStrings("severity", "High"),
+			labels.FromStrings("cef_version", "1",
+				"device_vendor", "Vendor",
+				"device_product", "Product",
+				"device_version", "2",
+				"device_event_class_id", "id",
+				"name", "name",
+				"severity", "High",
+				"severity_extracted", "Low",
+			),
+			NoParserHints(),
+		},
+		{
+			"incomplete header",
+			[]byte(`CEF:0|Security|threatmanager|1.0`),
+			labels.EmptyLabels(),
+			labels.FromStrings("cef_version", "0",
+				"device_vendor", "Security",
+				"device_product", "threatmanager",
+				logqlmodel.ErrorLabel, errCEF,
+				logqlmodel.ErrorDetailsLabel, "incomplete CEF header",
+			),
+			NoParserHints(),
+		},
+		{
+			"not cef",
+			[]byte(`level=info msg=hello`),
+			labels.EmptyLabels(),
+			labels.FromStrings(logqlmodel.ErrorLabel, errCEF,
+				logqlmodel.ErrorDetailsLabel, "no CEF header found",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]byte(`CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232`),
+			labels.EmptyLabels(),
+			labels.FromStrings("severity", "10", "src", "10.0.0.1"),
+			NewParserHint([]string{"severity", "src"}, nil, false, true, "", nil),
+		},
+	}
+	for _, tt := range tests {
+		p := NewCEFParser()
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func Test_leefParser_Parse(t *testing.T) {
+	tests := []struct {
+		name string
+		line []byte
+		want labels.Labels
+	}{
+		{
+			"leef 1.0",
+			[]byte("LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5\tcat=anomaly\tmsg=the=message"),
+			labels.FromStrings("leef_version", "1.0",
+				"vendor", "Microsoft",
+				"product", "MSExchange",
+				"product_version", "4.0 SP1",
+				"event_id", "15345",
+				"src", "192.0.2.0",
+				"dst", "172.50.123.1",
+				"sev", "5",
+				"cat", "anomaly",
+				"msg", "the=message",
+			),
+		},
+		{
+			"leef 2.0 with delimiter",
+			[]byte("<13>Jan 18 11:07:53 host LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^sev=5"),
+			labels.FromStrings("leef_version", "2.0",
+				"vendor", "Lancope",
+				"product", "StealthWatch",
+				"product_version", "1.0",
+				"event_id", "41",
+				"src", "10.0.1.8",
+				"dst", "10.0.0.5",
+				"sev", "5",
+			),
+		},
+		{
+			"leef 2.0 with hex delimiter",
+			[]byte("LEEF:2.0|Lancope|StealthWatch|1.0|41|0x7C|src=10.0.1.8|dst=10.0.0.5"),
+			labels.FromStrings("leef_version", "2.0",
+				"vendor", "Lancope",
+				"product", "StealthWatch",
+				"product_version", "1.0",
+				"event_id", "41",
+				"src", "10.0.1.8",
+				"dst", "10.0.0.5",
+			),
+		},
+		{
+			"leef 2.0 with invalid delimiter",
+			[]byte("LEEF:2.0|Lancope|StealthWatch|1.0|41|foo|src=10.0.1.8"),
+			labels.FromStrings("leef_version", "2.0",
+				"vendor", "Lancope",
+				"product", "StealthWatch",
+				"product_version", "1.0",
+				"event_id", "41",
+				logqlmodel.ErrorLabel, errLEEF,
+				logqlmodel.ErrorDetailsLabel, `invalid LEEF delimiter "foo"`,
+			),
+		},
+		{
+			"leef 2.0 with short hex delimiter",
+			[]byte("LEEF:2.0|Lancope|StealthWatch|1.0|41|x5E|src=10.0.1.8^dst=10.0.0.5"),
+			labels.FromStrings("leef_version", "2.0",
+				"vendor", "Lancope",
+				"product", "StealthWatch",
+				"product_version", "1.0",
+				"event_id", "41",
+				"src", "10.0.1.8",
+				"dst", "10.0.0.5",
+			),
+		},
+		{
+			"leef 2.0 with hex delimiter without prefix",
+			[]byte("LEEF:2.0|Lancope|StealthWatch|1.0|41|05|src=10.0.1.8"),
+			labels.FromStrings("leef_version", "2.0",
+				"vendor", "Lancope",
+				"product", "StealthWatch",
+				"product_version", "1.0",
+				"event_id", "41",
+				logqlmodel.ErrorLabel, errLEEF,
+				logqlmodel.ErrorDetailsLabel, `invalid LEEF delimiter "05"`,
+			),
+		},
+		{
+			"not leef",
+			[]byte(`CEF:0|Security|threatmanager|1.0|100|worm|10|`),
+			labels.FromStrings(logqlmodel.ErrorLabel, errLEEF,
+				logqlmodel.ErrorDetailsLabel, "no LEEF header found",
+			),
+		},
+	}
+	for _, tt := range tests {
+		p := NewLEEFParser()
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilder().ForLabels(labels.EmptyLabels(), 0)
+			b.Reset()
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestCEFParserLabelFilterShortCircuit(t *testing.T) {
+	line := []byte(`CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2`)
+	filter := NewStringLabelFilter(labels.MustNewMatcher(labels.MatchEqual, "src", "10.0.0.2"))
+	hints := NewParserHint([]string{"src"}, nil, false, true, "", []Stage{filter})
+
+	b := NewBaseLabelsBuilderWithGrouping(nil, hints, false, false).ForLabels(labels.EmptyLabels(), 0)
+	b.Reset()
+	_, ok := NewCEFParser().Process(0, line, b)
+	require.False(t, ok)
+}
@@ -4,6 +4,10 @@ var (
 	// Possible errors thrown by a log pipeline.
 	errJSON             = "JSONParserErr"
 	errLogfmt           = "LogfmtParserErr"
+	errXML              = "XMLParserErr"
+	errCSV              = "CSVParserErr"
+	errCEF              = "CEFParserErr"
+	errLEEF             = "LEEFParserErr"
 	errSampleExtraction = "SampleExtractionErr"
 	errLabelFilter      = "LabelFilterErr"
 	errTemplateFormat   = "TemplateFormatErr"
@@ -0,0 +1,211 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logql/log/jsonexpr"
+)
+
+var _ Stage = &JSONArrayExploder{}
+
+// JSONArrayExploder is a stage that explodes a log line into one result per element of a json array.
+// The stage returns a single result per call, the pipeline selects the element to return
+// with the explode index of the labels builder and loops over the elements of the line.
+type JSONArrayExploder struct {
+	path []string
+	buf  []byte
+
+	// line and elements are the last line parsed and the elements of its array. The pipeline processes the same
+	// line once per element, starting with the first one, so the array is only parsed once per line.
+	line     []byte
+	elements []jsonArrayElement
+}
+
+type jsonArrayElement struct {
+	value []byte
+	typ   jsonparser.ValueType
+}
+
+// NewJSONArrayExploder creates a stage replacing the log line with each element of the array found at the given path.
+// An empty path uses the log line itself as the array.
+func NewJSONArrayExploder(expression string) (*JSONArrayExploder, error) {
+	if expression == "" {
+		return &JSONArrayExploder{}, nil
+	}
+	path, err := jsonexpr.Parse(expression, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse expression [%s]: %w", expression, err)
+	}
+	for _, p := range path {
+		if _, ok := p.(jsonexpr.Wildcard); ok {
+			return nil, fmt.Errorf("cannot use array wildcard in expression [%s]", expression)
+		}
+	}
+	return &JSONArrayExploder{
+		path: pathsToString(path),
+	}, nil
+}
+
+func (j *JSONArrayExploder) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	lbs.explodeMore = false
+	if len(line) == 0 {
+		return line, false
+	}
+
+	// the first element starts a new line, the buffer of the last line can be reused by the previous stages.
+	if lbs.explodeIndex == 0 || !sameBytes(j.line, line) {
+		j.parse(line)
+	}
+	if lbs.explodeIndex >= len(j.elements) {
+		return line, false
+	}
+	lbs.explodeMore = lbs.explodeIndex < len(j.elements)-1
+	element := j.elements[lbs.explodeIndex]
+
+	// string elements are unescaped, the other elements are kept as json.
+	if element.typ != jsonparser.String || bytes.IndexByte(element.value, '\\') < 0 {
+		return element.value, true
+	}
+	unescaped, err := jsonparser.Unescape(element.value, j.buf[:0])
+	if err != nil {
+		return element.value, true
+	}
+	j.buf = unescaped
+	return j.buf, true
+}
+
+// parse caches the elements of the array of the line, there are none if the line doesn't have an array at the path.
+func (j *JSONArrayExploder) parse(line []byte) {
+	j.line = line
+	j.elements = j.elements[:0]
+
+	array, typ, _, err := jsonparser.Get(line, j.path...)
+	if err != nil || typ != jsonparser.Array {
+		return
+	}
+	_, _ = jsonparser.ArrayEach(array, func(value []byte, typ jsonparser.ValueType, _ int, err error) {
+		if err != nil {
+			return
+		}
+		j.elements = append(j.elements, jsonArrayElement{value: value, typ: typ})
+	})
+}
+
+// sameBytes returns true if both slices are the same memory.
+func sameBytes(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+func (j *JSONArrayExploder) RequiredLabelNames() []string { return []string{} }
+
+// ExplodingStreamPipeline is a StreamPipeline that can return several results for a single log line.
+type ExplodingStreamPipeline interface {
+	StreamPipeline
+	// ProcessExploded processes a log line and returns its n-th result.
+	// more tells if the line has results after the n-th one, even when the n-th result doesn't match.
+	ProcessExploded(ts int64, line []byte, n int, structuredMetadata ...labels.Label) (resultLine []byte, resultLabels LabelsResult, matches, more bool)
+	// Explodes tells if a log line can have more than one result.
+	Explodes() bool
+}
+
+// ExplodingStreamSampleExtractor is a StreamSampleExtractor that can return several samples for a single log line.
+type ExplodingStreamSampleExtractor interface {
+	StreamSampleExtractor
+	// ProcessExploded processes a log line and returns its n-th sample.
+	// more tells if the line has samples after the n-th one, even when the n-th sample doesn't match.
+	ProcessExploded(ts int64, line []byte, n int, structuredMetadata ...labels.Label) (value float64, resultLabels LabelsResult, matches, more bool)
+	// Explodes tells if a log line can have more than one sample.
+	Explodes() bool
+}
+
+// AsExplodingPipeline returns the pipeline if it can return several results for a single log line, nil otherwise.
+func AsExplodingPipeline(p StreamPipeline) ExplodingStreamPipeline {
+	if e, ok := p.(ExplodingStreamPipeline); ok && e.Explodes() {
+		return e
+	}
+	return nil
+}
+
+// AsExplodingSampleExtractor returns the extractor if it can return several samples for a single log line, nil otherwise.
+func AsExplodingSampleExtractor(ex StreamSampleExtractor) ExplodingStreamSampleExtractor {
+	if e, ok := ex.(ExplodingStreamSampleExtractor); ok && e.Explodes() {
+		return e
+	}
+	return nil
+}
+
+// ProcessEach processes a log line and calls fn with every matching result and its index.
+// The line passed to fn is a copy.
+func ProcessEach(p StreamPipeline, ts int64, line string, structuredMetadata []labels.Label, fn func(n int, line string, lbs LabelsResult)) {
+	e := AsExplodingPipeline(p)
+	if e == nil {
+		if newLine, lbs, matches := p.ProcessString(ts, line, structuredMetadata...); matches {
+			fn(0, newLine, lbs)
+		}
+		return
+	}
+
+	for n, more := 0, true; more; n++ {
+		var (
+			newLine []byte
+			lbs     LabelsResult
+			matches bool
+		)
+		// Stages only read from the line.
+		newLine, lbs, matches, more = e.ProcessExploded(ts, unsafeGetBytes(line), n, structuredMetadata...)
+		if matches {
+			fn(n, string(newLine), lbs)
+		}
+	}
+}
+
+// ExtractEach extracts the samples of a log line and calls fn with every matching sample and its index.
+func ExtractEach(ex StreamSampleExtractor, ts int64, line string, structuredMetadata []labels.Label, fn func(n int, value float64, lbs LabelsResult)) {
+	e := AsExplodingSampleExtractor(ex)
+	if e == nil {
+		if value, lbs, ok := ex.ProcessString(ts, line, structuredMetadata...); ok {
+			fn(0, value, lbs)
+		}
+		return
+	}
+
+	for n, more := 0, true; more; n++ {
+		var (
+			value float64
+			lbs   LabelsResult
+			ok    bool
+		)
+		value, lbs, ok, more = e.ProcessExploded(ts, unsafeGetBytes(line), n, structuredMetadata...)
+		if ok {
+			fn(n, value, lbs)
+		}
+	}
+}
+
+// explodes tells if one of the stages can return several results for a single log line.
+func explodes(stages []Stage) bool {
+	for _, s := range stages {
+		if _, ok := unwrapStage(s).(*JSONArrayExploder); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// processFirst returns the first matching result of an exploded log line, for callers not aware of exploded lines.
+func processFirst[T any](process func(n int) (T, LabelsResult, bool, bool)) (T, LabelsResult, bool) {
+	for n := 0; ; n++ {
+		res, lbs, matches, more := process(n)
+		if matches {
+			return res, lbs, true
+		}
+		if !more {
+			var zero T
+			return zero, nil, false
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package log
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONArrayExploder(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		line []byte
+		want []string
+	}{
+		{
+			"objects",
+			"errors",
+			[]byte(`{"level":"error","errors":[{"code":"E1"},{"code":"E2"}]}`),
+			[]string{`{"code":"E1"}`, `{"code":"E2"}`},
+		},
+		{
+			"unescaped strings",
+			`request["tags"]`,
+			[]byte(`{"request":{"tags":["a","b \"quoted\"",3,null]}}`),
+			[]string{"a", `b "quoted"`, "3", "null"},
+		},
+		{
+			"top-level array",
+			"",
+			[]byte(`[1,[2,3]]`),
+			[]string{"1", "[2,3]"},
+		},
+		{
+			"empty array",
+			"errors",
+			[]byte(`{"errors":[]}`),
+			nil,
+		},
+		{
+			"not an array",
+			"errors",
+			[]byte(`{"errors":"E1"}`),
+			nil,
+		},
+		{
+			"missing",
+			"errors",
+			[]byte(`{"level":"info"}`),
+			nil,
+		},
+		{
+			"invalid json",
+			"errors",
+			[]byte(`level=info`),
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := NewJSONArrayExploder(tt.path)
+			require.NoError(t, err)
+			p := NewPipeline([]Stage{j}).ForStream(labels.FromStrings("app", "foo"))
+
+			e := AsExplodingPipeline(p)
+			require.NotNil(t, e)
+
+			var got []string
+			for n, more := 0, true; more; n++ {
+				var line []byte
+				var matches bool
+				line, _, matches, more = e.ProcessExploded(0, tt.line, n)
+				if matches {
+					got = append(got, string(line))
+				}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSONArrayExploderReusedBuffer(t *testing.T) {
+	j, err := NewJSONArrayExploder("a")
+	require.NoError(t, err)
+	p := NewPipeline([]Stage{j}).ForStream(labels.FromStrings("app", "foo"))
+	e := AsExplodingPipeline(p)
+
+	// a buffer reused for the next line is parsed again from its first element.
+	buf := []byte(`{"a":[1,2,3]}`)
+	line, _, _, more := e.ProcessExploded(0, buf, 1)
+	require.Equal(t, "2", string(line))
+	require.True(t, more)
+
+	copy(buf, `{"a":[4,5,6]}`)
+	line, _, _, _ = e.ProcessExploded(0, buf, 0)
+	require.Equal(t, "4", string(line))
+	line, _, _, more = e.ProcessExploded(0, buf, 2)
+	require.Equal(t, "6", string(line))
+	require.False(t, more)
+
+	// another line is parsed again at any element.
+	line, _, _, _ = e.ProcessExploded(0, []byte(`{"a":[7,8]}`), 1)
+	require.Equal(t, "8", string(line))
+}
+
+func BenchmarkJSONArrayExploder(b *testing.B) {
+	j, err := NewJSONArrayExploder("a")
+	require.NoError(b, err)
+	p := NewPipeline([]Stage{j}).ForStream(labels.FromStrings("app", "foo"))
+
+	var sb strings.Builder
+	sb.WriteString(`{"a":[`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(`{"code":"E1"}`)
+	}
+	sb.WriteString(`]}`)
+	line := sb.String()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ProcessEach(p, 0, line, nil, func(_ int, _ string, _ LabelsResult) {})
+	}
+}
+
+func TestNewJSONArrayExploderFailures(t *testing.T) {
+	_, err := NewJSONArrayExploder(`errors[*].code`)
+	require.EqualError(t, err, "cannot use array wildcard in expression [errors[*].code]")
+
+	_, err = NewJSONArrayExploder(`errors..code`)
+	require.Error(t, err)
+}
+
+func TestJSONArrayPipeline(t *testing.T) {
+	line := []byte(`{"level":"error","errors":[{"code":"E1"},{"code":"E2"},{"code":"E2"}]}`)
+	exploder, err := NewJSONArrayExploder("errors")
+	require.NoError(t, err)
+	stages := []Stage{
+		exploder,
+		NewJSONParser(),
+		NewStringLabelFilter(labels.MustNewMatcher(labels.MatchEqual, "code", "E2")),
+	}
+
+	t.Run("first matching element", func(t *testing.T) {
+		p := NewPipeline(stages).ForStream(labels.FromStrings("app", "foo"))
+		res, lbs, matches := p.Process(0, line)
+		require.True(t, matches)
+		require.Equal(t, `{"code":"E2"}`, string(res))
+		require.Equal(t, labels.FromStrings("app", "foo", "code", "E2"), lbs.Labels())
+	})
+
+	t.Run("each matching element", func(t *testing.T) {
+		p := NewPipeline(stages).ForStream(labels.FromStrings("app", "foo"))
+		var got []int
+		ProcessEach(p, 0, string(line), nil, func(n int, l string, lbs LabelsResult) {
+			require.Equal(t, `{"code":"E2"}`, l)
+			require.Equal(t, labels.FromStrings("app", "foo", "code", "E2"), lbs.Labels())
+			got = append(got, n)
+		})
+		require.Equal(t, []int{1, 2}, got)
+	})
+
+	t.Run("count elements", func(t *testing.T) {
+		ex, err := NewLineSampleExtractor(CountExtractor, stages, []string{"code"}, false, false)
+		require.NoError(t, err)
+		var count float64
+		ExtractEach(ex.ForStream(labels.FromStrings("app", "foo")), 0, string(line), nil, func(_ int, v float64, lbs LabelsResult) {
+			require.Equal(t, labels.FromStrings("code", "E2"), lbs.Labels())
+			count += v
+		})
+		require.Equal(t, 2., count)
+	})
+
+	t.Run("filtering pipeline", func(t *testing.T) {
+		p := NewFilteringPipeline([]PipelineFilter{
+			newPipelineFilter(2, 4, labels.FromStrings("app", "foo"), labels.EmptyLabels(), "level"),
+		}, NewPipeline(stages))
+
+		var got int
+		ProcessEach(p.ForStream(labels.FromStrings("app", "foo")), 0, string(line), nil, func(int, string, LabelsResult) { got++ })
+		require.Equal(t, 2, got)
+
+		got = 0
+		ProcessEach(p.ForStream(labels.FromStrings("app", "foo")), 3, string(line), nil, func(int, string, LabelsResult) { got++ })
+		require.Equal(t, 0, got)
+	})
+}
@@ -17,13 +17,14 @@ func setScannerData(lex interface{}, data []interface{}) {
     int     int
 }
 
-%token<empty>   DOT LSB RSB
+%token<empty>   DOT LSB RSB WILDCARD
 %token<str>     STRING
 %token<field>   FIELD
 %token<int>     INDEX
 
 %type<int>  index index_access
 %type<str>  field key key_access
+%type<empty> wildcard_access
 %type<list> values
 
 %%
@@ -35,8 +36,10 @@ values:
     field                   { $$ = []interface{}{$1} }
   | key_access              { $$ = []interface{}{$1} }
   | index_access            { $$ = []interface{}{$1} }
+  | wildcard_access         { $$ = []interface{}{Wildcard{}} }
   | values key_access       { $$ = append($1, $2) }
   | values index_access     { $$ = append($1, $2) }
+  | values wildcard_access  { $$ = append($1, Wildcard{}) }
   | values DOT field        { $$ = append($1, $3) }
   ;
 
@@ -46,6 +49,9 @@ key_access:
 index_access:
     LSB index RSB   { $$ = $2 }
 
+wildcard_access:
+    LSB WILDCARD RSB { $$ = $2 }
+
 field:
   FIELD             { $$ = $1 }
 
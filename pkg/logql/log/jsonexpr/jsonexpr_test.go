@@ -76,6 +76,18 @@ func TestJSONExpressionParser(t *testing.T) {
 			[]interface{}{"pod", "deployment", "params", 0, "param"},
 			nil,
 		},
+		{
+			"array wildcard access",
+			`pod.deployment.params[*].param`,
+			[]interface{}{"pod", "deployment", "params", Wildcard{}, "param"},
+			nil,
+		},
+		{
+			"top-level array wildcard access",
+			`[*][0]`,
+			[]interface{}{Wildcard{}, 0},
+			nil,
+		},
 		{
 			"empty",
 			``,
@@ -119,6 +131,12 @@ func TestJSONExpressionParser(t *testing.T) {
 			nil,
 			fmt.Errorf("syntax error: unexpected $end, expecting RSB"),
 		},
+		{
+			"wildcard without brackets",
+			`pod.*`,
+			nil,
+			fmt.Errorf("syntax error: unexpected WILDCARD, expecting FIELD"),
+		},
 		{
 			"identifier with number",
 			`utf8`,
@@ -8,6 +8,9 @@ func init() {
 	JSONExprErrorVerbose = true
 }
 
+// Wildcard is the path element of the `[*]` array access, it matches every element of an array.
+type Wildcard struct{}
+
 func Parse(expr string, debug bool) ([]interface{}, error) {
 	s := NewScanner(strings.NewReader(expr), debug)
 	JSONExprParse(s)
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// LabelPrefilter drops the lines that cannot have a label with the given value once it is parsed from them.
+// It runs ahead of the parsers, a parsed value is then a substring of the line unless it was unescaped or
+// had invalid runes removed by the parser, which both require a backslash or an invalid line.
+// Lines already having the label before parsing, e.g. from the stream or the structured metadata, are kept.
+type LabelPrefilter struct {
+	name  string
+	value []byte
+}
+
+// NewLabelPrefilter creates a stage dropping the lines that cannot have the label with the value once parsed.
+func NewLabelPrefilter(name, value string) *LabelPrefilter {
+	return &LabelPrefilter{
+		name:  name,
+		value: []byte(value),
+	}
+}
+
+func (f *LabelPrefilter) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if _, ok := lbs.Get(f.name); ok {
+		return line, true
+	}
+	if bytes.Contains(line, f.value) || bytes.IndexByte(line, '\\') >= 0 || !utf8.Valid(line) {
+		return line, true
+	}
+	return line, false
+}
+
+func (f *LabelPrefilter) RequiredLabelNames() []string { return []string{} }
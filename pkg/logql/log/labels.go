@@ -151,6 +151,11 @@ type LabelsBuilder struct {
 	currentResult LabelsResult
 	groupedResult LabelsResult
 
+	// explodeIndex is the index of the result returned for a log line exploded by the json_array stage,
+	// explodeMore is set by the stage when the line has results after this index.
+	explodeIndex int
+	explodeMore  bool
+
 	*BaseLabelsBuilder
 }
 
@@ -263,6 +268,12 @@ func (b *LabelsBuilder) SetErrorDetails(desc string) *LabelsBuilder {
 	return b
 }
 
+// setExplodeIndex sets the index of the result to return for an exploded log line.
+func (b *LabelsBuilder) setExplodeIndex(n int) {
+	b.explodeIndex = n
+	b.explodeMore = false
+}
+
 func (b *LabelsBuilder) ResetError() *LabelsBuilder {
 	b.err = ""
 	return b
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
 
@@ -16,6 +17,9 @@ const (
 	ConvertBytes    = "bytes"
 	ConvertDuration = "duration"
 	ConvertFloat    = "float"
+	// ConvertHash hashes the label value instead of parsing it, the hash keeps 53 bits so that it
+	// is exactly represented by the sample value. It is used to count distinct label values.
+	ConvertHash = "hash"
 )
 
 // LineExtractor extracts a float64 from a log line.
@@ -49,6 +53,7 @@ type SampleExtractorWrapper interface {
 type lineSampleExtractor struct {
 	Stage
 	LineExtractor
+	explodes bool
 
 	baseBuilder      *BaseLabelsBuilder
 	streamExtractors map[uint64]StreamSampleExtractor
@@ -62,6 +67,7 @@ func NewLineSampleExtractor(ex LineExtractor, stages []Stage, groups []string, w
 	return &lineSampleExtractor{
 		Stage:            s,
 		LineExtractor:    ex,
+		explodes:         explodes(stages),
 		baseBuilder:      NewBaseLabelsBuilderWithGrouping(groups, hints, without, noLabels),
 		streamExtractors: make(map[uint64]StreamSampleExtractor),
 	}, nil
@@ -76,6 +82,7 @@ func (l *lineSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtrac
 	res := &streamLineSampleExtractor{
 		Stage:         l.Stage,
 		LineExtractor: l.LineExtractor,
+		explodes:      l.explodes,
 		builder:       l.baseBuilder.ForLabels(labels, hash),
 	}
 	l.streamExtractors[hash] = res
@@ -85,7 +92,8 @@ func (l *lineSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtrac
 type streamLineSampleExtractor struct {
 	Stage
 	LineExtractor
-	builder *LabelsBuilder
+	explodes bool
+	builder  *LabelsBuilder
 }
 
 func (l *streamLineSampleExtractor) ReferencedStructuredMetadata() bool {
@@ -93,6 +101,23 @@ func (l *streamLineSampleExtractor) ReferencedStructuredMetadata() bool {
 }
 
 func (l *streamLineSampleExtractor) Process(ts int64, line []byte, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
+	if l.explodes {
+		return processFirst(func(n int) (float64, LabelsResult, bool, bool) {
+			return l.ProcessExploded(ts, line, n, structuredMetadata...)
+		})
+	}
+	return l.process(ts, line, structuredMetadata...)
+}
+
+func (l *streamLineSampleExtractor) ProcessExploded(ts int64, line []byte, n int, structuredMetadata ...labels.Label) (float64, LabelsResult, bool, bool) {
+	l.builder.setExplodeIndex(n)
+	v, lbs, ok := l.process(ts, line, structuredMetadata...)
+	return v, lbs, ok, l.builder.explodeMore
+}
+
+func (l *streamLineSampleExtractor) Explodes() bool { return l.explodes }
+
+func (l *streamLineSampleExtractor) process(ts int64, line []byte, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
 	l.builder.Reset()
 	l.builder.Add(StructuredMetadataLabel, structuredMetadata...)
 
@@ -122,6 +147,7 @@ type labelSampleExtractor struct {
 	postFilter   Stage
 	labelName    string
 	conversionFn convertionFn
+	explodes     bool
 
 	baseBuilder      *BaseLabelsBuilder
 	streamExtractors map[uint64]StreamSampleExtractor
@@ -144,6 +170,8 @@ func LabelExtractorWithStages(
 		convFn = convertDuration
 	case ConvertFloat:
 		convFn = convertFloat
+	case ConvertHash:
+		convFn = convertHash
 	default:
 		return nil, errors.Errorf("unsupported conversion operation %s", conversion)
 	}
@@ -159,6 +187,7 @@ func LabelExtractorWithStages(
 		conversionFn:     convFn,
 		labelName:        labelName,
 		postFilter:       postFilter,
+		explodes:         explodes(preStages),
 		baseBuilder:      NewBaseLabelsBuilderWithGrouping(groups, hints, without, noLabels),
 		streamExtractors: make(map[uint64]StreamSampleExtractor),
 	}, nil
@@ -188,6 +217,23 @@ func (l *labelSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtra
 }
 
 func (l *streamLabelSampleExtractor) Process(ts int64, line []byte, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
+	if l.explodes {
+		return processFirst(func(n int) (float64, LabelsResult, bool, bool) {
+			return l.ProcessExploded(ts, line, n, structuredMetadata...)
+		})
+	}
+	return l.process(ts, line, structuredMetadata...)
+}
+
+func (l *streamLabelSampleExtractor) ProcessExploded(ts int64, line []byte, n int, structuredMetadata ...labels.Label) (float64, LabelsResult, bool, bool) {
+	l.builder.setExplodeIndex(n)
+	v, lbs, ok := l.process(ts, line, structuredMetadata...)
+	return v, lbs, ok, l.builder.explodeMore
+}
+
+func (l *streamLabelSampleExtractor) Explodes() bool { return l.explodes }
+
+func (l *streamLabelSampleExtractor) process(ts int64, line []byte, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
 	// Apply the pipeline first.
 	l.builder.Reset()
 	l.builder.Add(StructuredMetadataLabel, structuredMetadata...)
@@ -286,6 +332,29 @@ func (sp *filteringStreamExtractor) Process(ts int64, line []byte, structuredMet
 	return sp.extractor.Process(ts, line)
 }
 
+func (sp *filteringStreamExtractor) ProcessExploded(ts int64, line []byte, n int, structuredMetadata ...labels.Label) (float64, LabelsResult, bool, bool) {
+	for _, filter := range sp.filters {
+		if ts < filter.start || ts > filter.end {
+			continue
+		}
+
+		_, _, matches := filter.pipeline.Process(ts, line, structuredMetadata...)
+		if matches { // When the filter matches, don't run the next step
+			return 0, nil, false, false
+		}
+	}
+
+	if e := AsExplodingSampleExtractor(sp.extractor); e != nil {
+		return e.ProcessExploded(ts, line, n)
+	}
+	v, lbs, ok := sp.extractor.Process(ts, line)
+	return v, lbs, ok, false
+}
+
+func (sp *filteringStreamExtractor) Explodes() bool {
+	return AsExplodingSampleExtractor(sp.extractor) != nil
+}
+
 func (sp *filteringStreamExtractor) ProcessString(ts int64, line string, structuredMetadata ...labels.Label) (float64, LabelsResult, bool) {
 	for _, filter := range sp.filters {
 		if ts < filter.start || ts > filter.end {
@@ -305,6 +374,10 @@ func convertFloat(v string) (float64, error) {
 	return strconv.ParseFloat(v, 64)
 }
 
+func convertHash(v string) (float64, error) {
+	return float64(xxhash.Sum64String(v) & (1<<53 - 1)), nil
+}
+
 func convertDuration(v string) (float64, error) {
 	d, err := time.ParseDuration(v)
 	if err != nil {
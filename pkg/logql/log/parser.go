@@ -2,8 +2,11 @@ package log
 
 import (
 	"bytes"
+	"encoding/csv"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"unicode/utf8"
 
@@ -33,6 +36,9 @@ var (
 	_ Stage = &JSONParser{}
 	_ Stage = &RegexpParser{}
 	_ Stage = &LogfmtParser{}
+	_ Stage = &XMLParser{}
+	_ Stage = &XMLExpressionParser{}
+	_ Stage = &CSVParser{}
 
 	trueBytes = []byte("true")
 
@@ -40,6 +46,7 @@ var (
 	errMissingCapture       = errors.New("at least one named capture must be supplied")
 	errFoundAllLabels       = errors.New("found all required labels")
 	errLabelDoesNotMatch    = errors.New("found a label with a matcher that didn't match")
+	errMissingXMLRoot       = errors.New("no xml root element found")
 
 	// the rune error replacement is rejected by Prometheus hence replacing them with space.
 	removeInvalidUtf = func(r rune) rune {
@@ -555,11 +562,18 @@ type JSONExpressionParser struct {
 	ids   []string
 	paths [][]string
 	keys  internedStringSet
+
+	// wildcardIDs and wildcardPaths are the expressions using the `[*]` array access,
+	// paths are split at each wildcard.
+	wildcardIDs   []string
+	wildcardPaths [][][]string
+	valueBuf      []byte
 }
 
 func NewJSONExpressionParser(expressions []LabelExtractionExpr) (*JSONExpressionParser, error) {
-	var ids []string
+	var ids, wildcardIDs []string
 	var paths [][]string
+	var wildcardPaths [][][]string
 	for _, exp := range expressions {
 		path, err := jsonexpr.Parse(exp.Expression, false)
 		if err != nil {
@@ -570,14 +584,21 @@ func NewJSONExpressionParser(expressions []LabelExtractionExpr) (*JSONExpression
 			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
 		}
 
+		if segments := splitWildcards(path); len(segments) > 1 {
+			wildcardIDs = append(wildcardIDs, exp.Identifier)
+			wildcardPaths = append(wildcardPaths, segments)
+			continue
+		}
 		ids = append(ids, exp.Identifier)
 		paths = append(paths, pathsToString(path))
 	}
 
 	return &JSONExpressionParser{
-		ids:   ids,
-		paths: paths,
-		keys:  internedStringSet{},
+		ids:           ids,
+		paths:         paths,
+		keys:          internedStringSet{},
+		wildcardIDs:   wildcardIDs,
+		wildcardPaths: wildcardPaths,
 	}, nil
 }
 
@@ -594,6 +615,21 @@ func pathsToString(paths []interface{}) []string {
 	return stingPaths
 }
 
+// splitWildcards splits the path at each array wildcard.
+// A path without wildcard returns a single segment.
+func splitWildcards(path []interface{}) [][]string {
+	segments := [][]string{nil}
+	for _, p := range path {
+		if _, ok := p.(jsonexpr.Wildcard); ok {
+			segments = append(segments, nil)
+			continue
+		}
+		last := len(segments) - 1
+		segments[last] = append(segments[last], pathsToString([]interface{}{p})...)
+	}
+	return segments
+}
+
 func (j *JSONExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
 	if len(line) == 0 || lbs.ParserLabelHints().NoLabels() {
 		return line, true
@@ -608,35 +644,43 @@ func (j *JSONExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder)
 	}
 
 	var matches int
-	jsonparser.EachKey(line, func(idx int, data []byte, typ jsonparser.ValueType, err error) {
-		if err != nil {
-			addErrLabel(errJSON, err, lbs)
-			return
-		}
+	if len(j.paths) > 0 {
+		jsonparser.EachKey(line, func(idx int, data []byte, typ jsonparser.ValueType, err error) {
+			if err != nil {
+				addErrLabel(errJSON, err, lbs)
+				return
+			}
 
-		identifier := j.ids[idx]
-		key, _ := j.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
-			if lbs.BaseHas(identifier) {
-				identifier = identifier + duplicateSuffix
+			switch typ {
+			case jsonparser.Null:
+				lbs.Set(ParsedLabel, j.key(j.ids[idx], lbs), "")
+			default:
+				lbs.Set(ParsedLabel, j.key(j.ids[idx], lbs), unescapeJSONString(data))
 			}
-			return identifier, true
-		})
 
-		switch typ {
-		case jsonparser.Null:
-			lbs.Set(ParsedLabel, key, "")
-		default:
-			lbs.Set(ParsedLabel, key, unescapeJSONString(data))
-		}
+			matches++
+		}, j.paths...)
+	}
 
+	for i, segments := range j.wildcardPaths {
+		j.valueBuf = append(j.valueBuf[:0], '[')
+		j.valueBuf = appendWildcardMatches(j.valueBuf, line, jsonparser.Unknown, segments)
+		if len(j.valueBuf) == 1 {
+			// nothing matched, the label is set empty below.
+			continue
+		}
+		j.valueBuf = append(j.valueBuf, ']')
+		lbs.Set(ParsedLabel, j.key(j.wildcardIDs[i], lbs), string(j.valueBuf))
 		matches++
-	}, j.paths...)
+	}
 
 	// Ensure there's a label for every value
-	if matches < len(j.ids) {
-		for _, id := range j.ids {
-			if _, ok := lbs.Get(id); !ok {
-				lbs.Set(ParsedLabel, id, "")
+	if matches < len(j.ids)+len(j.wildcardIDs) {
+		for _, ids := range [][]string{j.ids, j.wildcardIDs} {
+			for _, id := range ids {
+				if _, ok := lbs.Get(id); !ok {
+					lbs.Set(ParsedLabel, id, "")
+				}
 			}
 		}
 	}
@@ -644,6 +688,55 @@ func (j *JSONExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder)
 	return line, true
 }
 
+func (j *JSONExpressionParser) key(identifier string, lbs *LabelsBuilder) string {
+	key, _ := j.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+		if lbs.BaseHas(identifier) {
+			identifier = identifier + duplicateSuffix
+		}
+		return identifier, true
+	})
+	return key
+}
+
+// appendWildcardMatches appends to dst, as JSON array items, every value matching the path segments
+// where each segment after the first one is applied to all the elements of the array found by the previous segment.
+// The type is Unknown when data is the raw log line.
+func appendWildcardMatches(dst []byte, data []byte, typ jsonparser.ValueType, segments [][]string) []byte {
+	if len(segments[0]) > 0 || typ == jsonparser.Unknown {
+		if typ != jsonparser.Unknown && typ != jsonparser.Object && typ != jsonparser.Array {
+			return dst
+		}
+		var err error
+		if data, typ, _, err = jsonparser.Get(data, segments[0]...); err != nil {
+			return dst
+		}
+	}
+
+	if len(segments) == 1 {
+		if len(dst) > 1 {
+			dst = append(dst, ',')
+		}
+		if typ == jsonparser.String {
+			// jsonparser strips the quotes of string values but doesn't unescape them.
+			dst = append(dst, '"')
+			dst = append(dst, data...)
+			return append(dst, '"')
+		}
+		return append(dst, data...)
+	}
+
+	if typ != jsonparser.Array {
+		return dst
+	}
+	_, _ = jsonparser.ArrayEach(data, func(value []byte, typ jsonparser.ValueType, _ int, err error) {
+		if err != nil {
+			return
+		}
+		dst = appendWildcardMatches(dst, value, typ, segments[1:])
+	})
+	return dst
+}
+
 func isValidJSONStart(data []byte) bool {
 	switch data[0] {
 	case '"', '{', '[':
@@ -765,3 +858,526 @@ func (u *UnpackParser) unpack(entry []byte, lbs *LabelsBuilder) ([]byte, error)
 	}
 	return entry, nil
 }
+
+type XMLParser struct {
+	prefixBuffer []byte              // buffer used to build xml keys
+	prefixLens   []int               // length of the prefix buffer for each open element
+	extracted    map[string]struct{} // keys extracted from the current line
+
+	keys internedStringSet
+}
+
+// NewXMLParser creates a log stage that can parse a xml log line and add elements and attributes as labels.
+// Nested element names are joined with an underscore, e.g. `<a><b>c</b></a>` extracts `a_b="c"`.
+// Attributes are extracted the same way using the attribute name as the last key part, e.g. `<a b="c"/>` extracts `a_b="c"`.
+// Namespace declarations are ignored and if a key appears twice, only the first value is kept.
+func NewXMLParser() *XMLParser {
+	return &XMLParser{
+		prefixBuffer: make([]byte, 0, 1024),
+		prefixLens:   make([]int, 0, 16),
+		extracted:    map[string]struct{}{},
+		keys:         internedStringSet{},
+	}
+}
+
+func (x *XMLParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	// reset the state.
+	x.prefixBuffer = x.prefixBuffer[:0]
+	x.prefixLens = x.prefixLens[:0]
+	clear(x.extracted)
+
+	if err := x.parse(line, lbs); err != nil {
+		if errors.Is(err, errFoundAllLabels) {
+			// Short-circuited
+			return line, true
+		}
+
+		if errors.Is(err, errLabelDoesNotMatch) {
+			// one of the label matchers does not match. The whole line can be thrown away
+			return line, false
+		}
+
+		addErrLabel(errXML, err, lbs)
+
+		return line, true
+	}
+	return line, true
+}
+
+func (x *XMLParser) parse(line []byte, lbs *LabelsBuilder) error {
+	parserHints := lbs.ParserLabelHints()
+	dec := xml.NewDecoder(bytes.NewReader(line))
+
+	var foundRoot bool
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if !foundRoot {
+					return errMissingXMLRoot
+				}
+				return nil
+			}
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			foundRoot = true
+			x.prefixLens = append(x.prefixLens, len(x.prefixBuffer))
+			if len(x.prefixBuffer) != 0 {
+				x.prefixBuffer = append(x.prefixBuffer, byte(jsonSpacer))
+			}
+			x.prefixBuffer = appendSanitized(x.prefixBuffer, unsafeGetBytes(t.Name.Local))
+
+			if !parserHints.ShouldExtractPrefix(unsafeGetString(x.prefixBuffer)) {
+				// nothing to extract in this element, skip it entirely.
+				if err := dec.Skip(); err != nil {
+					return err
+				}
+				x.popPrefix()
+				continue
+			}
+
+			for _, attr := range t.Attr {
+				if isXMLNamespaceDeclaration(attr) {
+					continue
+				}
+				prefixLen := len(x.prefixBuffer)
+				x.prefixBuffer = append(x.prefixBuffer, byte(jsonSpacer))
+				x.prefixBuffer = appendSanitized(x.prefixBuffer, unsafeGetBytes(attr.Name.Local))
+				err := x.setLabel(attr.Value, lbs)
+				x.prefixBuffer = x.prefixBuffer[:prefixLen]
+				if err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			x.popPrefix()
+		case xml.CharData:
+			if len(x.prefixLens) == 0 {
+				continue
+			}
+			value := bytes.TrimSpace(t)
+			if len(value) == 0 {
+				continue
+			}
+			if err := x.setLabel(string(value), lbs); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// popPrefix rollbacks the prefix as we exit the current element.
+func (x *XMLParser) popPrefix() {
+	if len(x.prefixLens) == 0 {
+		return
+	}
+	last := len(x.prefixLens) - 1
+	x.prefixBuffer = x.prefixBuffer[:x.prefixLens[last]]
+	x.prefixLens = x.prefixLens[:last]
+}
+
+// setLabel sets the label for the key currently held in the prefix buffer.
+func (x *XMLParser) setLabel(value string, lbs *LabelsBuilder) error {
+	parserHints := lbs.ParserLabelHints()
+	key, ok := x.keys.Get(x.prefixBuffer, func() (string, bool) {
+		field := string(x.prefixBuffer)
+		if lbs.BaseHas(field) {
+			field = field + duplicateSuffix
+		}
+		if !parserHints.ShouldExtract(field) {
+			return "", false
+		}
+		return field, true
+	})
+	if !ok {
+		return nil
+	}
+
+	// only the first value of a repeated key is kept.
+	if _, ok := x.extracted[key]; ok {
+		return nil
+	}
+	x.extracted[key] = struct{}{}
+
+	if strings.ContainsRune(value, utf8.RuneError) {
+		value = strings.Map(removeInvalidUtf, value)
+	}
+
+	lbs.Set(ParsedLabel, key, value)
+	if !parserHints.ShouldContinueParsingLine(key, lbs) {
+		return errLabelDoesNotMatch
+	}
+	if parserHints.AllRequiredExtracted() {
+		// Not actually an error. Parsing can be short-circuited.
+		return errFoundAllLabels
+	}
+	return nil
+}
+
+func (x *XMLParser) RequiredLabelNames() []string { return []string{} }
+
+// isXMLNamespaceDeclaration tells if the attribute is a namespace declaration like `xmlns="..."` or `xmlns:soap="..."`.
+func isXMLNamespaceDeclaration(attr xml.Attr) bool {
+	return attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+}
+
+// xmlPath is a simplified XPath expression selecting either the text of an element or one of its attributes,
+// e.g. `/response/status` or `/response/@code`.
+type xmlPath struct {
+	elements  []string
+	attribute string
+}
+
+func parseXMLPath(expr string) (xmlPath, error) {
+	var p xmlPath
+	if !strings.HasPrefix(expr, "/") {
+		return p, fmt.Errorf("xml path must be absolute: %s", expr)
+	}
+	parts := strings.Split(expr[1:], "/")
+	for i, part := range parts {
+		if part == "" {
+			return p, fmt.Errorf("empty element name in xml path: %s", expr)
+		}
+		if strings.HasPrefix(part, "@") {
+			if i != len(parts)-1 || len(part) == 1 {
+				return p, fmt.Errorf("attribute must be the last part of the xml path: %s", expr)
+			}
+			p.attribute = part[1:]
+			continue
+		}
+		p.elements = append(p.elements, part)
+	}
+	if len(p.elements) == 0 {
+		return p, fmt.Errorf("xml path must select at least one element: %s", expr)
+	}
+	return p, nil
+}
+
+// matches tells if the path selects the element at the top of the given stack.
+func (p xmlPath) matches(stack []string) bool {
+	if len(stack) != len(p.elements) {
+		return false
+	}
+	for i := range stack {
+		if stack[i] != p.elements[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type XMLExpressionParser struct {
+	ids   []string
+	paths []xmlPath
+	keys  internedStringSet
+
+	// per line state
+	stack []string
+	found []bool
+	text  [][]byte
+}
+
+// NewXMLExpressionParser creates a parser that extracts the given xml paths into labels.
+// Only the first element matching a path is extracted.
+func NewXMLExpressionParser(expressions []LabelExtractionExpr) (*XMLExpressionParser, error) {
+	if len(expressions) == 0 {
+		return nil, fmt.Errorf("no xml expression provided")
+	}
+	ids := make([]string, 0, len(expressions))
+	paths := make([]xmlPath, 0, len(expressions))
+	for _, exp := range expressions {
+		path, err := parseXMLPath(exp.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse expression [%s]: %w", exp.Expression, err)
+		}
+
+		if !model.LabelName(exp.Identifier).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
+		}
+
+		ids = append(ids, exp.Identifier)
+		paths = append(paths, path)
+	}
+
+	return &XMLExpressionParser{
+		ids:   ids,
+		paths: paths,
+		keys:  internedStringSet{},
+		stack: make([]string, 0, 16),
+		found: make([]bool, len(ids)),
+		text:  make([][]byte, len(ids)),
+	}, nil
+}
+
+func (x *XMLExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+
+	// reset the state.
+	x.stack = x.stack[:0]
+	for i := range x.found {
+		x.found[i] = false
+		x.text[i] = x.text[i][:0]
+	}
+
+	err := x.parse(line, lbs)
+	if err != nil && !errors.Is(err, errFoundAllLabels) {
+		addErrLabel(errXML, err, lbs)
+	}
+
+	// Ensure there's a label for every value
+	for i, id := range x.ids {
+		if !x.found[i] {
+			x.set(id, "", lbs)
+		}
+	}
+
+	return line, true
+}
+
+func (x *XMLExpressionParser) parse(line []byte, lbs *LabelsBuilder) error {
+	dec := xml.NewDecoder(bytes.NewReader(line))
+
+	var foundRoot bool
+	matches := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if !foundRoot {
+					return errMissingXMLRoot
+				}
+				return nil
+			}
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			foundRoot = true
+			x.stack = append(x.stack, t.Name.Local)
+			for i, p := range x.paths {
+				if x.found[i] || p.attribute == "" || !p.matches(x.stack) {
+					continue
+				}
+				for _, attr := range t.Attr {
+					if attr.Name.Local == p.attribute {
+						x.found[i] = true
+						matches++
+						x.set(x.ids[i], attr.Value, lbs)
+						break
+					}
+				}
+			}
+		case xml.CharData:
+			for i, p := range x.paths {
+				if x.found[i] || p.attribute != "" || !p.matches(x.stack) {
+					continue
+				}
+				x.text[i] = append(x.text[i], t...)
+			}
+		case xml.EndElement:
+			for i, p := range x.paths {
+				if x.found[i] || p.attribute != "" || !p.matches(x.stack) {
+					continue
+				}
+				x.found[i] = true
+				matches++
+				x.set(x.ids[i], string(bytes.TrimSpace(x.text[i])), lbs)
+			}
+			if len(x.stack) > 0 {
+				x.stack = x.stack[:len(x.stack)-1]
+			}
+		}
+
+		if matches == len(x.ids) {
+			return errFoundAllLabels
+		}
+	}
+}
+
+func (x *XMLExpressionParser) set(identifier, value string, lbs *LabelsBuilder) {
+	key, _ := x.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+		if lbs.BaseHas(identifier) {
+			identifier = identifier + duplicateSuffix
+		}
+		return identifier, true
+	})
+	if strings.ContainsRune(value, utf8.RuneError) {
+		value = strings.Map(removeInvalidUtf, value)
+	}
+	lbs.Set(ParsedLabel, key, value)
+}
+
+func (x *XMLExpressionParser) RequiredLabelNames() []string { return []string{} }
+
+const (
+	DefaultCSVDelimiter = ','
+	DefaultCSVQuote     = '"'
+)
+
+type CSVParser struct {
+	columns   []string
+	delimiter rune
+	quote     rune
+
+	fieldBuf []byte // buffer used to unescape quoted fields
+	keys     internedStringSet
+}
+
+// NewCSVParser creates a parser that extracts the columns of a delimited log line into labels.
+// Columns are named in order, an empty or `_` name skips the column. Fields can be quoted as described in RFC 4180,
+// a zero quote disables quoting.
+func NewCSVParser(columns []string, delimiter, quote rune) (*CSVParser, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no csv column provided")
+	}
+	if !validCSVRune(delimiter) {
+		return nil, fmt.Errorf("invalid csv delimiter %q", delimiter)
+	}
+	if quote != 0 && !validCSVRune(quote) {
+		return nil, fmt.Errorf("invalid csv quote %q", quote)
+	}
+	if delimiter == quote {
+		return nil, fmt.Errorf("csv delimiter and quote must be different")
+	}
+
+	names := make([]string, 0, len(columns))
+	var found bool
+	for _, c := range columns {
+		c = strings.TrimSpace(c)
+		if c == "_" {
+			c = ""
+		}
+		if c != "" {
+			if !model.LabelName(c).IsValid() {
+				return nil, fmt.Errorf("invalid csv column name '%s'", c)
+			}
+			found = true
+		}
+		names = append(names, c)
+	}
+	if !found {
+		return nil, fmt.Errorf("at least one named csv column must be supplied")
+	}
+
+	return &CSVParser{
+		columns:   names,
+		delimiter: delimiter,
+		quote:     quote,
+		keys:      internedStringSet{},
+	}, nil
+}
+
+func validCSVRune(r rune) bool {
+	return r != 0 && r != '\r' && r != '\n' && r != utf8.RuneError && utf8.ValidRune(r)
+}
+
+func (c *CSVParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	rest := line
+	for _, name := range c.columns {
+		field, next, more, err := c.nextField(rest)
+		if err != nil {
+			addErrLabel(errCSV, err, lbs)
+			if !parserHints.ShouldContinueParsingLine(logqlmodel.ErrorLabel, lbs) {
+				return line, false
+			}
+			return line, true
+		}
+		rest = next
+
+		if name != "" {
+			key, ok := c.keys.Get(unsafeGetBytes(name), func() (string, bool) {
+				if lbs.BaseHas(name) {
+					name = name + duplicateSuffix
+				}
+				if !parserHints.ShouldExtract(name) {
+					return "", false
+				}
+				return name, true
+			})
+			if ok {
+				if bytes.ContainsRune(field, utf8.RuneError) {
+					field = bytes.Map(removeInvalidUtf, field)
+				}
+				lbs.Set(ParsedLabel, key, string(field))
+				if !parserHints.ShouldContinueParsingLine(key, lbs) {
+					return line, false
+				}
+				if parserHints.AllRequiredExtracted() {
+					break
+				}
+			}
+		}
+
+		if !more {
+			break
+		}
+	}
+	return line, true
+}
+
+// nextField returns the next field of the record, the remaining data and whether more fields follow.
+func (c *CSVParser) nextField(data []byte) ([]byte, []byte, bool, error) {
+	if c.quote == 0 || !hasPrefixRune(data, c.quote) {
+		field, rest, more := data, []byte(nil), false
+		if i := bytes.IndexRune(data, c.delimiter); i >= 0 {
+			field, rest, more = data[:i], data[i+utf8.RuneLen(c.delimiter):], true
+		}
+		if c.quote != 0 && bytes.ContainsRune(field, c.quote) {
+			return nil, nil, false, csv.ErrBareQuote
+		}
+		return field, rest, more, nil
+	}
+
+	// quoted field, a doubled quote is an escaped quote.
+	quoteLen := utf8.RuneLen(c.quote)
+	c.fieldBuf = c.fieldBuf[:0]
+	data = data[quoteLen:]
+	for {
+		i := bytes.IndexRune(data, c.quote)
+		if i < 0 {
+			return nil, nil, false, csv.ErrQuote
+		}
+		c.fieldBuf = append(c.fieldBuf, data[:i]...)
+		data = data[i+quoteLen:]
+		if !hasPrefixRune(data, c.quote) {
+			break
+		}
+		c.fieldBuf = append(c.fieldBuf, data[:quoteLen]...)
+		data = data[quoteLen:]
+	}
+
+	switch {
+	case len(data) == 0:
+		return c.fieldBuf, nil, false, nil
+	case hasPrefixRune(data, c.delimiter):
+		return c.fieldBuf, data[utf8.RuneLen(c.delimiter):], true, nil
+	default:
+		return nil, nil, false, csv.ErrQuote
+	}
+}
+
+func hasPrefixRune(data []byte, r rune) bool {
+	if r < utf8.RuneSelf {
+		return len(data) > 0 && data[0] == byte(r)
+	}
+	first, _ := utf8.DecodeRune(data)
+	return first == r
+}
+
+func (c *CSVParser) RequiredLabelNames() []string { return []string{} }
@@ -144,7 +144,7 @@ func NewParserHint(requiredLabelNames, groups []string, without, noLabels bool,
 	var labelNames []string
 	var labelFilters []LabelFilterer
 	for _, s := range stages {
-		switch f := s.(type) {
+		switch f := unwrapStage(s).(type) {
 		case *BinaryLabelFilter:
 			// TODO: as long as each leg of the binary filter operates on the same (and only 1) label,
 			// we should be able to add this to our filters
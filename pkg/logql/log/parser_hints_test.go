@@ -42,6 +42,8 @@ var (
 		"_entry":"foo"
 	}`)
 
+	csvLine = []byte(`2021-02-02T14:35:05.983992774Z,POST,"/api/v1/push, /api/v1/query",204,us-east-west`)
+
 	logfmtLine = []byte(`ts=2021-02-02T14:35:05.983992774Z caller=spanlogger.go:79 org_id=3677 traceID=2e5c7234b8640997 Ingester.TotalReached=15 Ingester.TotalChunksMatched=0 Ingester.TotalBatches=0`)
 )
 
@@ -224,6 +226,34 @@ func Test_ParserHints(t *testing.T) {
 			1,
 			`{app="nginx", message_message="foo"}`,
 		},
+		{
+			`sum by (method)(count_over_time({app="nginx"} | csv "ts,method,path,status,cluster" | status = 204 [1m]))`,
+			csvLine,
+			true,
+			1,
+			`{method="POST"}`,
+		},
+		{
+			`sum by (cluster_extracted)(count_over_time({app="nginx"} | csv "ts,method,path,status,cluster" [1m]))`,
+			csvLine,
+			true,
+			1,
+			`{cluster_extracted="us-east-west"}`,
+		},
+		{
+			`sum(count_over_time({app="nginx"} | csv "ts,method,path,status,cluster" | method="GET" [1m]))`,
+			csvLine,
+			false,
+			0,
+			``,
+		},
+		{
+			`sum by (path)(count_over_time({app="nginx"} | csv "ts,method,path" [1m]))`,
+			csvLine,
+			true,
+			1,
+			`{path="/api/v1/push, /api/v1/query"}`,
+		},
 	} {
 		tt := tt
 		t.Run(tt.expr, func(t *testing.T) {
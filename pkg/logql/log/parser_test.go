@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
@@ -522,6 +523,55 @@ func TestJSONExpressionParser(t *testing.T) {
 			),
 			NoParserHints(),
 		},
+		{
+			"array wildcard",
+			[]byte(`{"errors":[{"code":"E1","retry":true},{"code":"E\"2"},{"retry":false},{"code":3}]}`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("codes", `errors[*].code`),
+				NewLabelExtractionExpr("retries", `errors[*]["retry"]`),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("codes", `["E1","E\"2",3]`,
+				"retries", `[true,false]`,
+			),
+			NoParserHints(),
+		},
+		{
+			"nested array wildcards",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("params", `pod.deployment.params[*]`),
+				NewLabelExtractionExpr("app", `app`),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("params", `[1,2,3,"string_value"]`,
+				"app", "foo",
+			),
+			NoParserHints(),
+		},
+		{
+			"top-level array wildcard",
+			[]byte(`[[1,2],[3],{"a":4}]`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("values", `[*][*]`),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("values", `[1,2,3]`),
+			NoParserHints(),
+		},
+		{
+			"array wildcard without match",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("values", `app[*]`),
+				NewLabelExtractionExpr("missing", `missing[*].code`),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("values", "",
+				"missing", "",
+			),
+			NoParserHints(),
+		},
 	}
 	for _, tt := range tests {
 		j, err := NewJSONExpressionParser(tt.expressions)
@@ -594,6 +644,7 @@ func Benchmark_Parser(b *testing.B) {
 	logfmtLine := `level=info ts=2020-12-14T21:25:20.947307459Z caller=metrics.go:83 org_id=29 traceID=c80e691e8db08e2 latency=fast query="sum by (object_name) (rate(({container=\"metrictank\", cluster=\"hm-us-east2\"} |= \"PANIC\")[5m]))" query_type=metric range_type=range length=5m0s step=15s duration=322.623724ms status=200 throughput=1.2GB total_bytes=375MB`
 	nginxline := `10.1.0.88 - - [14/Dec/2020:22:56:24 +0000] "GET /static/img/about/bob.jpg HTTP/1.1" 200 60755 "https://grafana.com/go/observabilitycon/grafana-the-open-and-composable-observability-platform/?tech=ggl-o&pg=oss-graf&plcmt=hero-txt" "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0.1 Safari/605.1.15" "123.123.123.123, 35.35.122.223" "TLSv1.3"`
 	packedLike := `{"job":"123","pod":"someuid123","app":"foo","_entry":"10.1.0.88 - - [14/Dec/2020:22:56:24 +0000] "GET /static/img/about/bob.jpg HTTP/1.1"}`
+	cefLine := `CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232 request=https://example.com/?` + strings.Repeat("a=b&", 200) + ` msg=worm successfully stopped`
 
 	for _, tt := range []struct {
 		name                 string
@@ -610,6 +661,7 @@ func Benchmark_Parser(b *testing.B) {
 		{"regex greedy", nginxline, mustStage(NewRegexpParser(`GET (?P<path>.*?)/\?`)), []string{"path"}, labels.MustNewMatcher(labels.MatchEqual, "path", "nope")},
 		{"regex status digits", nginxline, mustStage(NewRegexpParser(`HTTP/1.1" (?P<statuscode>\d{3}) `)), []string{"statuscode"}, labels.MustNewMatcher(labels.MatchEqual, "status_code", "nope")},
 		{"pattern", nginxline, mustStage(NewPatternParser(`<_> "<method> <path> <_>"<_>`)), []string{"path"}, labels.MustNewMatcher(labels.MatchEqual, "method", "nope")},
+		{"cef", cefLine, NewCEFParser(), []string{"src"}, labels.MustNewMatcher(labels.MatchEqual, "dst", "nope")},
 	} {
 		b.Run(tt.name, func(b *testing.B) {
 			line := []byte(tt.line)
@@ -1399,6 +1451,382 @@ func Test_PatternParser(t *testing.T) {
 	}
 }
 
+func Test_xmlParser_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  []byte
+		lbs   labels.Labels
+		want  labels.Labels
+		hints ParserHint
+	}{
+		{
+			"multi depth",
+			[]byte(`<response code="200"><status>ok</status><user><name>foo</name></user></response>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("response_code", "200",
+				"response_status", "ok",
+				"response_user_name", "foo",
+			),
+			NoParserHints(),
+		},
+		{
+			"namespaces and bad keys",
+			[]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><my-value>1</my-value></soap:Body></soap:Envelope>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("Envelope_Body_my_value", "1"),
+			NoParserHints(),
+		},
+		{
+			"default namespace and prefixed attribute",
+			[]byte(`<item xmlns="urn:foo" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="a" type="b"/>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("item_type", "a"),
+			NoParserHints(),
+		},
+		{
+			"repeated siblings keep first value",
+			[]byte(`<items><item>a</item><item>b</item></items>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("items_item", "a"),
+			NoParserHints(),
+		},
+		{
+			"mixed content keeps first value",
+			[]byte(`<a>x<b/>z</a>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("a", "x"),
+			NoParserHints(),
+		},
+		{
+			"empty line",
+			[]byte(``),
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "XMLParserErr",
+				"__error_details__", "no xml root element found",
+			),
+			NoParserHints(),
+		},
+		{
+			"escaped",
+			[]byte(`<msg text="a &amp; b">&lt;tag&gt;</msg>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("msg", "<tag>",
+				"msg_text", "a & b",
+			),
+			NoParserHints(),
+		},
+		{
+			"errors",
+			[]byte(`<response><status>ok</response>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("response_status", "ok",
+				"__error__", "XMLParserErr",
+				"__error_details__", "XML syntax error on line 1: element <status> closed by </response>",
+			),
+			NoParserHints(),
+		},
+		{
+			"not xml",
+			[]byte(`foo bar`),
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "XMLParserErr",
+				"__error_details__", "no xml root element found",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]byte(`<response code="200"><status>ok</status><user><name>foo</name></user></response>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("response_user_name", "foo"),
+			NewParserHint([]string{"response_user_name"}, nil, false, true, "", nil),
+		},
+		{
+			"duplicate extraction",
+			[]byte(`<app name="foo"/>`),
+			labels.FromStrings("app_name", "bar"),
+			labels.FromStrings("app_name", "bar",
+				"app_name_extracted", "foo",
+			),
+			NoParserHints(),
+		},
+	}
+	for _, tt := range tests {
+		x := NewXMLParser()
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParser(t *testing.T) {
+	testLine := []byte(`<response code="200"><status>ok</status><items><item id="1">a</item><item id="2">b</item></items></response>`)
+
+	tests := []struct {
+		name        string
+		line        []byte
+		expressions []LabelExtractionExpr
+		lbs         labels.Labels
+		want        labels.Labels
+	}{
+		{
+			"attribute",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("code", "/response/@code"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("code", "200"),
+		},
+		{
+			"element and first match",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("status", "/response/status"),
+				NewLabelExtractionExpr("item", "/response/items/item"),
+				NewLabelExtractionExpr("item_id", "/response/items/item/@id"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("status", "ok",
+				"item", "a",
+				"item_id", "1",
+			),
+		},
+		{
+			"missing path",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("status", "/response/status"),
+				NewLabelExtractionExpr("missing", "/response/missing"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("status", "ok",
+				"missing", "",
+			),
+		},
+		{
+			"duplicate label",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("status", "/response/status"),
+			},
+			labels.FromStrings("status", "bar"),
+			labels.FromStrings("status", "bar",
+				"status_extracted", "ok",
+			),
+		},
+		{
+			"empty line",
+			[]byte(``),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("status", "/response/status"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("status", "",
+				logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, "no xml root element found",
+			),
+		},
+		{
+			"invalid xml",
+			[]byte(`{"status":"ok"}`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("status", "/response/status"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("status", "",
+				logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, "no xml root element found",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := NewXMLExpressionParser(tt.expressions)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParserFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression LabelExtractionExpr
+		error      string
+	}{
+		{
+			"relative path",
+			NewLabelExtractionExpr("foo", "response/status"),
+			"cannot parse expression [response/status]: xml path must be absolute: response/status",
+		},
+		{
+			"attribute not last",
+			NewLabelExtractionExpr("foo", "/response/@code/status"),
+			"cannot parse expression [/response/@code/status]: attribute must be the last part of the xml path: /response/@code/status",
+		},
+		{
+			"empty element",
+			NewLabelExtractionExpr("foo", "/response//status"),
+			"cannot parse expression [/response//status]: empty element name in xml path: /response//status",
+		},
+		{
+			"invalid label name",
+			NewLabelExtractionExpr("foo-bar", "/response/status"),
+			"invalid extracted label name 'foo-bar'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewXMLExpressionParser([]LabelExtractionExpr{tt.expression})
+			require.EqualError(t, err, tt.error)
+		})
+	}
+}
+
+func Test_csvParser_Parse(t *testing.T) {
+	tests := []struct {
+		name      string
+		columns   []string
+		delimiter rune
+		quote     rune
+		line      []byte
+		lbs       labels.Labels
+		want      labels.Labels
+	}{
+		{
+			"simple",
+			[]string{"ts", "method", "path", "status"},
+			DefaultCSVDelimiter, DefaultCSVQuote,
+			[]byte(`2024-01-01T00:00:00Z,GET,/api/v1/push,204`),
+			labels.EmptyLabels(),
+			labels.FromStrings("ts", "2024-01-01T00:00:00Z",
+				"method", "GET",
+				"path", "/api/v1/push",
+				"status", "204",
+			),
+		},
+		{
+			"quoted fields",
+			[]string{"method", "msg", "status"},
+			DefaultCSVDelimiter, DefaultCSVQuote,
+			[]byte(`GET,"hello, ""world""",200`),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "GET",
+				"msg", `hello, "world"`,
+				"status", "200",
+			),
+		},
+		{
+			"skipped columns and missing fields",
+			[]string{"_", "method", "", "status"},
+			DefaultCSVDelimiter, DefaultCSVQuote,
+			[]byte(`ts,POST,path`),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "POST"),
+		},
+		{
+			"empty fields",
+			[]string{"a", "b", "c"},
+			DefaultCSVDelimiter, DefaultCSVQuote,
+			[]byte(`,"",`),
+			labels.EmptyLabels(),
+			labels.FromStrings("a", "", "b", "", "c", ""),
+		},
+		{
+			"tab delimiter and custom quote",
+			[]string{"method", "msg"},
+			'\t', '\'',
+			[]byte("GET\t'a\tb'"),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "GET",
+				"msg", "a\tb",
+			),
+		},
+		{
+			"quoting disabled",
+			[]string{"method", "msg"},
+			DefaultCSVDelimiter, 0,
+			[]byte(`GET,"hello"`),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "GET",
+				"msg", `"hello"`,
+			),
+		},
+		{
+			"duplicate",
+			[]string{"method"},
+			DefaultCSVDelimiter, DefaultCSVQuote,
+			[]byte(`GET`),
+			labels.FromStrings("method", "POST"),
+			labels.FromStrings("method", "POST",
+				"method_extracted", "GET",
+			),
+		},
+		{
+			"bare quote",
+			[]string{"method", "msg"},
+			DefaultCSVDelimiter, DefaultCSVQuote,
+			[]byte(`GET,hel"lo`),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "GET",
+				logqlmodel.ErrorLabel, errCSV,
+				logqlmodel.ErrorDetailsLabel, `bare " in non-quoted-field`,
+			),
+		},
+		{
+			"unterminated quote",
+			[]string{"method", "msg"},
+			DefaultCSVDelimiter, DefaultCSVQuote,
+			[]byte(`GET,"hello`),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "GET",
+				logqlmodel.ErrorLabel, errCSV,
+				logqlmodel.ErrorDetailsLabel, `extraneous or missing " in quoted-field`,
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewCSVParser(tt.columns, tt.delimiter, tt.quote)
+			require.NoError(t, err)
+
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = p.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestNewCSVParserFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		columns   []string
+		delimiter rune
+		quote     rune
+		error     string
+	}{
+		{"no named column", []string{"_", ""}, DefaultCSVDelimiter, DefaultCSVQuote, "at least one named csv column must be supplied"},
+		{"invalid column", []string{"foo-bar"}, DefaultCSVDelimiter, DefaultCSVQuote, "invalid csv column name 'foo-bar'"},
+		{"newline delimiter", []string{"foo"}, '\n', DefaultCSVQuote, `invalid csv delimiter '\n'`},
+		{"same delimiter and quote", []string{"foo"}, '"', '"', "csv delimiter and quote must be different"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCSVParser(tt.columns, tt.delimiter, tt.quote)
+			require.EqualError(t, err, tt.error)
+		})
+	}
+}
+
 func BenchmarkJsonExpressionParser(b *testing.B) {
 	simpleJsn := []byte(`{
       "data": "Click Here",
@@ -184,10 +184,11 @@ type streamPipeline struct {
 	stages     []Stage
 	builder    *LabelsBuilder
 	offsetsBuf []int
+	explodes   bool
 }
 
 func NewStreamPipeline(stages []Stage, labelsBuilder *LabelsBuilder) StreamPipeline {
-	return &streamPipeline{stages, labelsBuilder, make([]int, 0, 10)}
+	return &streamPipeline{stages, labelsBuilder, make([]int, 0, 10), explodes(stages)}
 }
 
 func (p *pipeline) ForStream(labels labels.Labels) StreamPipeline {
@@ -224,6 +225,23 @@ func (p *streamPipeline) ReferencedStructuredMetadata() bool {
 }
 
 func (p *streamPipeline) Process(ts int64, line []byte, structuredMetadata ...labels.Label) ([]byte, LabelsResult, bool) {
+	if p.explodes {
+		return processFirst(func(n int) ([]byte, LabelsResult, bool, bool) {
+			return p.ProcessExploded(ts, line, n, structuredMetadata...)
+		})
+	}
+	return p.process(ts, line, structuredMetadata...)
+}
+
+func (p *streamPipeline) ProcessExploded(ts int64, line []byte, n int, structuredMetadata ...labels.Label) ([]byte, LabelsResult, bool, bool) {
+	p.builder.setExplodeIndex(n)
+	line, lbs, matches := p.process(ts, line, structuredMetadata...)
+	return line, lbs, matches, p.builder.explodeMore
+}
+
+func (p *streamPipeline) Explodes() bool { return p.explodes }
+
+func (p *streamPipeline) process(ts int64, line []byte, structuredMetadata ...labels.Label) ([]byte, LabelsResult, bool) {
 	var ok bool
 	p.builder.Reset()
 
@@ -342,6 +360,29 @@ func (sp *filteringStreamPipeline) Process(ts int64, line []byte, structuredMeta
 	return sp.pipeline.Process(ts, line, structuredMetadata...)
 }
 
+func (sp *filteringStreamPipeline) ProcessExploded(ts int64, line []byte, n int, structuredMetadata ...labels.Label) ([]byte, LabelsResult, bool, bool) {
+	for _, filter := range sp.filters {
+		if ts < filter.start || ts > filter.end {
+			continue
+		}
+
+		_, _, matches := filter.pipeline.Process(ts, line, structuredMetadata...)
+		if matches { // When the filter matches, don't run the next step
+			return nil, nil, false, false
+		}
+	}
+
+	if e := AsExplodingPipeline(sp.pipeline); e != nil {
+		return e.ProcessExploded(ts, line, n, structuredMetadata...)
+	}
+	newLine, lbs, matches := sp.pipeline.Process(ts, line, structuredMetadata...)
+	return newLine, lbs, matches, false
+}
+
+func (sp *filteringStreamPipeline) Explodes() bool {
+	return AsExplodingPipeline(sp.pipeline) != nil
+}
+
 func (sp *filteringStreamPipeline) ProcessString(ts int64, line string, structuredMetadata ...labels.Label) (string, LabelsResult, bool) {
 	for _, filter := range sp.filters {
 		if ts < filter.start || ts > filter.end {
@@ -0,0 +1,43 @@
+package log
+
+import (
+	"sync/atomic" //lint:ignore faillint we can't use go.uber.org/atomic with a protobuf struct without wrapping it.
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+)
+
+// statsStage records the lines going through a stage into the statistics of the stage.
+type statsStage struct {
+	Stage
+	stats *stats.PipelineStage
+}
+
+// NewStatsStage returns a stage recording the lines going through s into st.
+func NewStatsStage(s Stage, st *stats.PipelineStage) Stage {
+	if s == NoopStage {
+		return s
+	}
+	return &statsStage{Stage: s, stats: st}
+}
+
+func (s *statsStage) Process(ts int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	start := time.Now()
+	atomic.AddInt64(&s.stats.LinesIn, 1)
+	atomic.AddInt64(&s.stats.BytesIn, int64(len(line)))
+
+	line, ok := s.Stage.Process(ts, line, lbs)
+	if ok {
+		atomic.AddInt64(&s.stats.LinesOut, 1)
+	}
+	atomic.AddInt64(&s.stats.WallTime, int64(time.Since(start)))
+	return line, ok
+}
+
+// unwrapStage returns the stage recorded by a stats stage, so that its type can be inspected.
+func unwrapStage(s Stage) Stage {
+	if st, ok := s.(*statsStage); ok {
+		return st.Stage
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package macros
+
+import (
+	"net/http"
+
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+const queryParam = "query"
+
+var errMultiTenantMacros = logqlmodel.NewParseError("pipeline macros are not supported in multi-tenant queries", 0, 0)
+
+// NewMiddleware creates a middleware expanding the pipeline macros of the `query` parameter of the request,
+// so the rest of the query path only sees expanded queries.
+func NewMiddleware(resolver *Resolver) middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				serverutil.WriteError(err, w)
+				return
+			}
+			query := r.Form.Get(queryParam)
+			if query == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tenants, err := tenant.TenantIDs(r.Context())
+			if err != nil {
+				serverutil.WriteError(err, w)
+				return
+			}
+
+			expanded, err := expand(r, resolver, tenants, query)
+			if err != nil {
+				serverutil.WriteError(err, w)
+				return
+			}
+			if expanded != query {
+				setQuery(r, expanded)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+func expand(r *http.Request, resolver *Resolver, tenants []string, query string) (string, error) {
+	if len(tenants) == 1 {
+		return resolver.Expand(r.Context(), tenants[0], query)
+	}
+	// macros are defined per tenant, fail only if the query references one.
+	if _, ok := parseMacros(query); ok {
+		return "", errMultiTenantMacros
+	}
+	return query, nil
+}
+
+// setQuery replaces the query in the parsed form and in the source it was parsed from.
+func setQuery(r *http.Request, query string) {
+	r.Form.Set(queryParam, query)
+	if r.PostForm.Has(queryParam) {
+		r.PostForm.Set(queryParam, query)
+	}
+	if values := r.URL.Query(); values.Has(queryParam) {
+		values.Set(queryParam, query)
+		r.URL.RawQuery = values.Encode()
+	}
+}
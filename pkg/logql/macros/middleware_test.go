@@ -0,0 +1,131 @@
+package macros
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStore struct {
+	macros map[string]map[string]string
+	calls  int
+}
+
+func (m *mockStore) ListMacros(_ context.Context, userID string) (map[string]string, error) {
+	m.calls++
+	return m.macros[userID], nil
+}
+func (m *mockStore) GetMacro(context.Context, string, string) (string, error) { return "", nil }
+func (m *mockStore) SetMacro(context.Context, string, string, string) error   { return nil }
+func (m *mockStore) DeleteMacro(context.Context, string, string) error        { return nil }
+
+func TestMiddleware(t *testing.T) {
+	store := &mockStore{macros: map[string]map[string]string{
+		"user1": {"errors": `| logfmt | level="error"`},
+	}}
+	var got string
+	handler := NewMiddleware(NewResolver(store, time.Minute)).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		got = r.Form.Get("query")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range []struct {
+		name   string
+		req    func() *http.Request
+		orgID  string
+		status int
+		query  string
+	}{
+		{
+			name: "get",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?query="+url.QueryEscape(`{app="foo"} | @errors`), nil)
+			},
+			orgID:  "user1",
+			status: http.StatusOK,
+			query:  `{app="foo"} | logfmt | level="error"`,
+		},
+		{
+			name: "post",
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/loki/api/v1/query", strings.NewReader("query="+url.QueryEscape(`rate({app="foo"} | @errors [1m])`)))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			},
+			orgID:  "user1",
+			status: http.StatusOK,
+			query:  `rate({app="foo"} | logfmt | level="error"[1m])`,
+		},
+		{
+			name: "no macros",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?query="+url.QueryEscape(`{app="foo"} |= "@errors"`), nil)
+			},
+			orgID:  "user2",
+			status: http.StatusOK,
+			query:  `{app="foo"} |= "@errors"`,
+		},
+		{
+			name: "unknown macro",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?query="+url.QueryEscape(`{app="foo"} | @errors`), nil)
+			},
+			orgID:  "user2",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "multi-tenant",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?query="+url.QueryEscape(`{app="foo"} | @errors`), nil)
+			},
+			orgID:  "user1|user2",
+			status: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got = ""
+			req := tc.req()
+			req = req.WithContext(user.InjectOrgID(req.Context(), tc.orgID))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tc.status, w.Code, w.Body.String())
+			require.Equal(t, tc.query, got)
+		})
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+	store := &mockStore{macros: map[string]map[string]string{
+		"user1": {"errors": `|= "error"`},
+	}}
+	now := time.Unix(0, 0)
+	r := NewResolver(store, time.Minute)
+	r.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		q, err := r.Expand(context.Background(), "user1", `{app="foo"} | @errors`)
+		require.NoError(t, err)
+		require.Equal(t, `{app="foo"} |= "error"`, q)
+	}
+	require.Equal(t, 1, store.calls)
+
+	store.macros["user1"]["errors"] = `|= "warn"`
+	now = now.Add(time.Minute)
+	q, err := r.Expand(context.Background(), "user1", `{app="foo"} | @errors`)
+	require.NoError(t, err)
+	require.Equal(t, `{app="foo"} |= "warn"`, q)
+	require.Equal(t, 2, store.calls)
+
+	// queries without macros don't load them.
+	_, err = r.Expand(context.Background(), "user2", `{app="foo"} |= "@"`)
+	require.NoError(t, err)
+	require.Equal(t, 2, store.calls)
+}
@@ -0,0 +1,92 @@
+package macros
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/ruler/rulestore"
+)
+
+// DefaultCacheTTL is how long the macros of a tenant are cached before being loaded again from the store.
+const DefaultCacheTTL = time.Minute
+
+type cacheEntry struct {
+	definitions map[string]string
+	expiry      time.Time
+}
+
+// Resolver expands the pipeline macros referenced by queries with the definitions of the tenant.
+// Definitions are loaded from the macro store and cached for a TTL.
+type Resolver struct {
+	store rulestore.MacroStore
+	ttl   time.Duration
+
+	mtx   sync.Mutex
+	cache map[string]cacheEntry
+	now   func() time.Time
+}
+
+// NewResolver creates a resolver loading the macros from the store.
+func NewResolver(store rulestore.MacroStore, ttl time.Duration) *Resolver {
+	return &Resolver{
+		store: store,
+		ttl:   ttl,
+		cache: map[string]cacheEntry{},
+		now:   time.Now,
+	}
+}
+
+// Expand returns the query with the macros it references expanded.
+// Queries without macros, or that can't be parsed, are returned unchanged so the error is reported by the query path.
+func (r *Resolver) Expand(ctx context.Context, tenantID, query string) (string, error) {
+	expr, ok := parseMacros(query)
+	if !ok {
+		return query, nil
+	}
+
+	definitions, err := r.Definitions(ctx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load pipeline macros: %w", err)
+	}
+	expanded, err := syntax.ExpandMacros(expr, definitions)
+	if err != nil {
+		return "", err
+	}
+	return expanded.String(), nil
+}
+
+// parseMacros returns the parsed query if it references macros.
+func parseMacros(query string) (syntax.Expr, bool) {
+	if !syntax.HasMacros(query) {
+		return nil, false
+	}
+	expr, err := syntax.ParseExpr(query)
+	if err != nil || len(syntax.MacroNames(expr)) == 0 {
+		return nil, false
+	}
+	return expr, true
+}
+
+// Definitions returns the macro definitions of the tenant.
+func (r *Resolver) Definitions(ctx context.Context, tenantID string) (map[string]string, error) {
+	now := r.now()
+	r.mtx.Lock()
+	entry, ok := r.cache[tenantID]
+	r.mtx.Unlock()
+	if ok && now.Before(entry.expiry) {
+		return entry.definitions, nil
+	}
+
+	definitions, err := r.store.ListMacros(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mtx.Lock()
+	r.cache[tenantID] = cacheEntry{definitions: definitions, expiry: now.Add(r.ttl)}
+	r.mtx.Unlock()
+	return definitions, nil
+}
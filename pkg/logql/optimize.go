@@ -1,6 +1,16 @@
 package logql
 
-import "github.com/grafana/loki/v3/pkg/logql/syntax"
+import (
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/log/jsonexpr"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
 
 // optimizeSampleExpr Attempt to optimize the SampleExpr to another that will run faster but will produce the same result.
 func optimizeSampleExpr(expr syntax.SampleExpr) (syntax.SampleExpr, error) {
@@ -8,7 +18,7 @@ func optimizeSampleExpr(expr syntax.SampleExpr) (syntax.SampleExpr, error) {
 	// we skip sharding AST for now, it's not easy to clone them since they are not part of the language.
 	expr.Walk(func(e syntax.Expr) {
 		switch e.(type) {
-		case *ConcatSampleExpr, DownstreamSampleExpr, *QuantileSketchEvalExpr, *QuantileSketchMergeExpr, *MergeFirstOverTimeExpr, *MergeLastOverTimeExpr:
+		case *ConcatSampleExpr, DownstreamSampleExpr, *QuantileSketchEvalExpr, *QuantileSketchMergeExpr, *CountDistinctSketchEvalExpr, *CountDistinctSketchMergeExpr, *MergeFirstOverTimeExpr, *MergeLastOverTimeExpr:
 			skip = true
 			return
 		}
@@ -71,6 +81,22 @@ func removeLineformat(expr syntax.SampleExpr) {
 					found = true
 					break
 				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.XMLExpressionParser); ok {
+					found = true
+					break
+				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.CSVParserExpr); ok {
+					found = true
+					break
+				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.JSONArrayExpr); ok {
+					found = true
+					break
+				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.MacroExpr); ok {
+					found = true
+					break
+				}
 			}
 			if found {
 				// we cannot remove safely the linefmtExpr.
@@ -84,3 +110,336 @@ func removeLineformat(expr syntax.SampleExpr) {
 		}
 	})
 }
+
+// OptimizePipelines returns a copy of the expression whose log pipelines process fewer lines with the same result.
+// The stages are rewritten where the pipelines are built, after the expression is received, since the label
+// prefilters are not part of the language.
+//
+//   - line filters are pushed ahead of the parsers and label stages, which don't change the line.
+//   - equality label filters on parsed labels add a prefilter of the line ahead of the parsers.
+//   - adjacent independent filters are ordered by their estimated selectivity.
+func OptimizePipelines[T syntax.Expr](expr T) (T, error) {
+	var found bool
+	expr.Walk(func(e syntax.Expr) {
+		if _, ok := e.(*syntax.PipelineExpr); ok {
+			found = true
+		}
+	})
+	if !found {
+		return expr, nil
+	}
+
+	copied, err := syntax.Clone(expr)
+	if err != nil {
+		return expr, err
+	}
+	copied.Walk(func(e syntax.Expr) {
+		if p, ok := e.(*syntax.PipelineExpr); ok {
+			p.MultiStages = optimizeStages(p.MultiStages, heuristicSelectivity{})
+		}
+	})
+	return copied, nil
+}
+
+func optimizeStages(stages syntax.MultiStageExpr, s selectivity) syntax.MultiStageExpr {
+	stages = pushLineFilters(stages)
+	stages = prefilterLabelFilters(stages)
+	return orderFilters(stages, s)
+}
+
+// pushLineFilters moves the line filters ahead of the stages which don't change the line.
+// Filtering is independent of the labels, a line dropped later is dropped earlier instead.
+func pushLineFilters(stages syntax.MultiStageExpr) syntax.MultiStageExpr {
+	result := make(syntax.MultiStageExpr, 0, len(stages))
+	for _, s := range stages {
+		if _, ok := s.(*syntax.LineFilterExpr); !ok {
+			result = append(result, s)
+			continue
+		}
+		i := len(result)
+		for i > 0 && keepsLine(result[i-1]) {
+			i--
+		}
+		result = append(result, nil)
+		copy(result[i+1:], result[i:])
+		result[i] = s
+	}
+	return result
+}
+
+// keepsLine returns true for the stages which neither change the line nor are line filters.
+func keepsLine(s syntax.StageExpr) bool {
+	switch e := s.(type) {
+	case *syntax.LabelParserExpr:
+		// unpack replaces the line with the packed entry.
+		return e.Op != syntax.OpParserTypeUnpack
+	case *syntax.LogfmtParserExpr, *syntax.JSONExpressionParser, *syntax.LogfmtExpressionParser,
+		*syntax.LabelFilterExpr, *syntax.LabelFmtExpr, *syntax.DropLabelsExpr, *syntax.KeepLabelsExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+// prefilterLabelFilters adds a label prefilter ahead of the parsers for each equality label filter
+// on a label they may parse. Up to the label filter, the stages following the parsers must only parse,
+// filter or drop labels, so that a matching value can only be parsed from the line.
+func prefilterLabelFilters(stages syntax.MultiStageExpr) syntax.MultiStageExpr {
+	prefilters := make(map[int][]*syntax.LabelPrefilterExpr)
+	for i, s := range stages {
+		f, ok := s.(*syntax.LabelFilterExpr)
+		if !ok {
+			continue
+		}
+		first := -1
+		for j := i - 1; j >= 0 && prefilterable(stages[j]); j-- {
+			if parses(stages[j]) {
+				first = j
+			}
+		}
+		if first < 0 {
+			continue
+		}
+		for _, m := range equalityMatchers(f.LabelFilterer, nil) {
+			prefilters[first] = appendPrefilter(prefilters[first], m.Name, m.Value)
+		}
+	}
+	if len(prefilters) == 0 {
+		return stages
+	}
+
+	result := make(syntax.MultiStageExpr, 0, len(stages)+len(prefilters))
+	for i, s := range stages {
+		for _, p := range prefilters[i] {
+			result = append(result, p)
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func appendPrefilter(prefilters []*syntax.LabelPrefilterExpr, name, value string) []*syntax.LabelPrefilterExpr {
+	for _, p := range prefilters {
+		if p.Name == name && p.Value == value {
+			return prefilters
+		}
+	}
+	return append(prefilters, &syntax.LabelPrefilterExpr{Name: name, Value: value})
+}
+
+func prefilterable(s syntax.StageExpr) bool {
+	switch s.(type) {
+	case *syntax.LineFilterExpr, *syntax.LabelFilterExpr, *syntax.DropLabelsExpr, *syntax.KeepLabelsExpr:
+		return true
+	default:
+		return parses(s)
+	}
+}
+
+// parses returns true for the parsers extracting the values of labels as they are written in the line,
+// unless they are escaped with a backslash. The xml parser decodes entities, and the cef and leef parsers
+// are left out with the other parsers decoding their values, so their values may not be in the line.
+// The json expressions with `[*]` wildcards are left out too, they build arrays of the matching values.
+func parses(s syntax.StageExpr) bool {
+	switch e := s.(type) {
+	case *syntax.LabelParserExpr:
+		switch e.Op {
+		case syntax.OpParserTypeJSON, syntax.OpParserTypeLogfmt, syntax.OpParserTypeRegexp, syntax.OpParserTypePattern:
+			return true
+		default:
+			return false
+		}
+	case *syntax.JSONExpressionParser:
+		return !hasWildcard(e.Expressions)
+	case *syntax.LogfmtParserExpr, *syntax.LogfmtExpressionParser:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasWildcard returns true if the path of any of the json expressions has a `[*]` wildcard,
+// or can't be parsed.
+func hasWildcard(expressions []log.LabelExtractionExpr) bool {
+	for _, exp := range expressions {
+		path, err := jsonexpr.Parse(exp.Expression, false)
+		if err != nil {
+			return true
+		}
+		for _, p := range path {
+			if _, ok := p.(jsonexpr.Wildcard); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// equalityMatchers returns the equality matchers a line must match to be kept by the label filter.
+func equalityMatchers(f log.LabelFilterer, matchers []*labels.Matcher) []*labels.Matcher {
+	var m *labels.Matcher
+	switch e := f.(type) {
+	case *log.BinaryLabelFilter:
+		if !e.And {
+			return matchers
+		}
+		return equalityMatchers(e.Right, equalityMatchers(e.Left, matchers))
+	case *log.StringLabelFilter:
+		m = e.Matcher
+	case *log.LineFilterLabelFilter:
+		m = e.Matcher
+	default:
+		return matchers
+	}
+	if m.Type != labels.MatchEqual || m.Value == "" || strings.HasPrefix(m.Name, "__") {
+		return matchers
+	}
+	return append(matchers, m)
+}
+
+// orderFilters orders the runs of adjacent line filters and of adjacent string label filters by selectivity,
+// the filters keeping the fewest lines first. The line filters of a run are combined into a single stage.
+func orderFilters(stages syntax.MultiStageExpr, s selectivity) syntax.MultiStageExpr {
+	result := make(syntax.MultiStageExpr, 0, len(stages))
+	for i := 0; i < len(stages); {
+		j := i
+		switch stages[i].(type) {
+		case *syntax.LineFilterExpr:
+			var filters []*syntax.LineFilterExpr
+			for ; j < len(stages); j++ {
+				f, ok := stages[j].(*syntax.LineFilterExpr)
+				if !ok {
+					break
+				}
+				filters = appendLineFilters(filters, f)
+			}
+			sort.SliceStable(filters, func(a, b int) bool {
+				return s.lineFilter(filters[a]) < s.lineFilter(filters[b])
+			})
+			for k := range filters {
+				filters[k].Left = nil
+				if k > 0 {
+					filters[k].Left = filters[k-1]
+				}
+			}
+			result = append(result, filters[len(filters)-1])
+		case *syntax.LabelFilterExpr:
+			start := len(result)
+			for ; j < len(stages); j++ {
+				f, ok := stages[j].(*syntax.LabelFilterExpr)
+				if !ok || !independent(f.LabelFilterer) {
+					break
+				}
+				result = append(result, f)
+			}
+			if j == i {
+				// a filter depending on the previous ones, e.g. on __error__.
+				result = append(result, stages[i])
+				j++
+			}
+			run := result[start:]
+			sort.SliceStable(run, func(a, b int) bool {
+				return s.labelFilter(run[a].(*syntax.LabelFilterExpr).LabelFilterer) < s.labelFilter(run[b].(*syntax.LabelFilterExpr).LabelFilterer)
+			})
+		default:
+			result = append(result, stages[i])
+			j++
+		}
+		i = j
+	}
+	return result
+}
+
+// appendLineFilters appends the filters of a chain of line filters, which are all required to keep a line.
+func appendLineFilters(filters []*syntax.LineFilterExpr, f *syntax.LineFilterExpr) []*syntax.LineFilterExpr {
+	if f.Left != nil {
+		filters = appendLineFilters(filters, f.Left)
+	}
+	return append(filters, f)
+}
+
+// independent returns true for the label filters which only compare strings and don't depend on the errors
+// of the previous stages, their order doesn't change the result.
+func independent(f log.LabelFilterer) bool {
+	switch e := f.(type) {
+	case *log.BinaryLabelFilter:
+		return independent(e.Left) && independent(e.Right)
+	case *log.StringLabelFilter:
+		return !isErrorLabel(e.Name)
+	case *log.LineFilterLabelFilter:
+		return !isErrorLabel(e.Name)
+	case *log.NoopLabelFilter:
+		return true
+	default:
+		return false
+	}
+}
+
+func isErrorLabel(name string) bool {
+	return name == logqlmodel.ErrorLabel || name == logqlmodel.ErrorDetailsLabel
+}
+
+// selectivity estimates the fraction of the lines kept by a filter.
+type selectivity interface {
+	lineFilter(f *syntax.LineFilterExpr) float64
+	labelFilter(f log.LabelFilterer) float64
+}
+
+// heuristicSelectivity estimates the selectivity of the filters from their operators and values:
+// the longer a searched string, the fewer lines contain it.
+type heuristicSelectivity struct{}
+
+func (h heuristicSelectivity) lineFilter(f *syntax.LineFilterExpr) float64 {
+	var kept float64
+	switch f.Ty {
+	case log.LineMatchEqual:
+		kept = containsSelectivity(f.Match)
+	case log.LineMatchNotEqual:
+		kept = 1 - containsSelectivity(f.Match)
+	default:
+		kept = 0.5
+	}
+	if f.Op != "" {
+		kept = 0.5
+	}
+	if f.Or != nil {
+		or := h.lineFilter(f.Or)
+		if f.Ty == log.LineMatchNotEqual || f.Ty == log.LineMatchNotRegexp || f.Ty == log.LineMatchNotPattern {
+			// negated alternatives must all match.
+			return kept * or
+		}
+		return min(1, kept+or)
+	}
+	return kept
+}
+
+func (h heuristicSelectivity) labelFilter(f log.LabelFilterer) float64 {
+	var m *labels.Matcher
+	switch e := f.(type) {
+	case *log.BinaryLabelFilter:
+		if e.And {
+			return h.labelFilter(e.Left) * h.labelFilter(e.Right)
+		}
+		return min(1, h.labelFilter(e.Left)+h.labelFilter(e.Right))
+	case *log.StringLabelFilter:
+		m = e.Matcher
+	case *log.LineFilterLabelFilter:
+		m = e.Matcher
+	case *log.NoopLabelFilter:
+		return 1
+	default:
+		return 0.5
+	}
+	switch m.Type {
+	case labels.MatchEqual:
+		return containsSelectivity(m.Value)
+	case labels.MatchNotEqual:
+		return 1 - containsSelectivity(m.Value)
+	default:
+		return 0.5
+	}
+}
+
+func containsSelectivity(s string) float64 {
+	return 1 / float64(2+len(s))
+}
@@ -1,8 +1,14 @@
 package logql
 
 import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/quick"
 
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
@@ -38,3 +44,160 @@ func Test_optimizeSampleExpr(t *testing.T) {
 		})
 	}
 }
+
+func Test_OptimizePipelines(t *testing.T) {
+	for _, tt := range []struct {
+		in, expected string
+	}{
+		// noop
+		{`{app="foo"}`, `{app="foo"}`},
+		{`{app="foo"} |= "bar"`, `{app="foo"} |= "bar"`},
+		{`sum(rate({app="foo"}[5m]))`, `sum(rate({app="foo"}[5m]))`},
+
+		// push line filters ahead of parsers and label stages.
+		{`{app="foo"} | json | status >= 500 |= "error"`, `{app="foo"} |= "error" | json | status>=500`},
+		{`{app="foo"} | logfmt | label_format a=b | drop c |= "error"`, `{app="foo"} |= "error" | logfmt | label_format a=b | drop c`},
+		{`{app="foo"} | json | line_format "{{.msg}}" |= "error"`, `{app="foo"} | json | line_format "{{.msg}}" |= "error"`},
+		{`{app="foo"} | unpack |= "error"`, `{app="foo"} | unpack |= "error"`},
+		{`{app="foo"} | decolorize |= "error"`, `{app="foo"} | decolorize |= "error"`},
+
+		// prefilter equality label filters on parsed labels.
+		{`{app="foo"} | json | level="error"`, `{app="foo"} | prefilter(level="error") | json | level="error"`},
+		{`{app="foo"} |= "timeout" | logfmt | status >= 500 | level="error", component="db"`, `{app="foo"} |= "timeout" | prefilter(level="error") | prefilter(component="db") | logfmt | status>=500 | ( level="error" , component="db" )`},
+		{`{app="foo"} | json | drop msg | level="error"`, `{app="foo"} | prefilter(level="error") | json | drop msg | level="error"`},
+		{`{app="foo"} | json | logfmt | level="error"`, `{app="foo"} | prefilter(level="error") | json | logfmt | level="error"`},
+		{`{app="foo"} | level="error"`, `{app="foo"} | level="error"`},
+		{`{app="foo"} | json | level!="error"`, `{app="foo"} | json | level!="error"`},
+		{`{app="foo"} | json | level="error" or level="warn"`, `{app="foo"} | json | ( level="error" or level="warn" )`},
+		{`{app="foo"} | json | label_format level="{{.lvl}}" | level="error"`, `{app="foo"} | json | label_format level="{{.lvl}}" | level="error"`},
+		{`{app="foo"} | json | __error__=""`, `{app="foo"} | json | __error__=""`},
+		{`{app="foo"} | unpack | level="error"`, `{app="foo"} | unpack | level="error"`},
+		{`{app="foo"} | xml | a_b="x&y"`, `{app="foo"} | xml | a_b="x&y"`},
+		{`{app="foo"} | csv "ts,level,msg" | msg="a\"b"`, `{app="foo"} | csv "ts,level,msg" | msg="a\"b"`},
+		{`{app="foo"} | cef | msg="a=b"`, `{app="foo"} | cef | msg="a=b"`},
+		{`{app="foo"} | leef | level="error"`, `{app="foo"} | leef | level="error"`},
+		{`{app="foo"} | json lvl="level" | lvl="error"`, `{app="foo"} | prefilter(lvl="error") | json lvl="level" | lvl="error"`},
+		{`{app="foo"} | json tags="x[*].n" | tags="[\"a\"]"`, `{app="foo"} | json tags="x[*].n" | tags="[\"a\"]"`},
+
+		// order filters by selectivity.
+		{`{app="foo"} |= "a" != "debug" |= "timeout"`, `{app="foo"} |= "timeout" |= "a" != "debug"`},
+		{`{app="foo"} |= "a" | json |= "timeout"`, `{app="foo"} |= "timeout" |= "a" | json`},
+		{`{app="foo"} | json | app!="bar" | method="GET"`, `{app="foo"} | prefilter(method="GET") | json | method="GET" | app!="bar"`},
+		{`{app="foo"} | json | app!="bar" | __error__="" | method="GET"`, `{app="foo"} | prefilter(method="GET") | json | app!="bar" | __error__="" | method="GET"`},
+		{`{app="foo"} | json | size > 10 | app!="bar" | method="GET"`, `{app="foo"} | prefilter(method="GET") | json | size>10 | method="GET" | app!="bar"`},
+
+		// every pipeline of the query is optimized.
+		{`sum(count_over_time({app="foo"} | json | level="error" |= "timeout" [5m])) / sum(count_over_time({app="foo"} | logfmt |= "a" |= "timeout" [5m]))`, `(sum(count_over_time({app="foo"} |= "timeout" | prefilter(level="error") | json | level="error"[5m])) / sum(count_over_time({app="foo"} |= "timeout" |= "a" | logfmt[5m])))`},
+	} {
+		t.Run(tt.in, func(t *testing.T) {
+			e, err := syntax.ParseExpr(tt.in)
+			require.NoError(t, err)
+			unoptimized := e.String()
+
+			got, err := OptimizePipelines(e)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got.String())
+			// the expression is copied.
+			require.Equal(t, unoptimized, e.String())
+		})
+	}
+}
+
+// optimizerStages are the stages the pipelines of Test_OptimizePipelines_SameResult are made of.
+var optimizerStages = []string{
+	`|= "error"`, `!= "debug"`, `|~ "err.r"`, `|= "timeout" or "refused"`, `!= "a" or "b"`, `|> "<_> level=<_>"`,
+	`| json`, `| logfmt`, `| logfmt --strict`, `| unpack`, `| json lvl="level"`, `| logfmt lvl="level"`,
+	`| xml`, `| csv "ts,level,msg"`, `| cef`, `| leef`, `| a_b="x&y"`, `| msg="a=b"`,
+	`| json tags="x[*].n"`, `| tags="[\"a\"]"`,
+	`| regexp "level=(?P<level>\\w+)"`, `| pattern "<_> level=<level> <_>"`,
+	`| level="error"`, `| level="info"`, `| level!="info"`, `| lvl="error"`, `| app="foo"`, `| msg="a\"b"`,
+	`| level="error", app="foo"`, `| level="error" or level="warn"`, `| status > 400`, `| __error__=""`, `| __error__!=""`,
+	`| label_format level="{{.app}}"`, `| line_format "{{.msg}}"`, `| drop level`, `| keep level,app,msg`, `| decolorize`,
+}
+
+// optimizerLines are the lines processed by the pipelines of Test_OptimizePipelines_SameResult,
+// including values which are escaped or invalid in the line.
+var optimizerLines = []string{
+	`{"level":"error","msg":"a\"b","status":500,"app":"foo"}`,
+	`{"level":"error","msg":"timeout","status":200}`,
+	"{\"level\":\"err\xffor\",\"msg\":\"refused\"}",
+	`{"level":"info","msg":"debug","app":"bar"}`,
+	`{"x":[{"n":"a"}],"level":"error"}`,
+	`{"_entry":"ts=1 level=error msg=timeout","level":"info"}`,
+	`ts=1 level=error msg="a \"b\"" status=404`,
+	`ts=2 level=info msg=hello app=foo`,
+	`ts=3 level="error" msg=refused`,
+	`ts=4 lvl=error msg="no level" status=abc`,
+	"\x1b[31mts=5 level=error\x1b[0m msg=colored",
+	`<a><b>x&amp;y</b><level>error</level></a>`,
+	`1,error,"a""b"`,
+	`CEF:0|acme|fw|1.0|100|blocked|5|level=error msg=a\=b`,
+	"LEEF:1.0|acme|fw|1.0|100|level=error\tmsg=a=b",
+	`plain line without labels`,
+}
+
+type optimizerQuery struct {
+	stages []string
+}
+
+func (optimizerQuery) Generate(r *rand.Rand, _ int) reflect.Value {
+	q := optimizerQuery{stages: make([]string, 1+r.Intn(6))}
+	for i := range q.stages {
+		q.stages[i] = optimizerStages[r.Intn(len(optimizerStages))]
+	}
+	return reflect.ValueOf(q)
+}
+
+func Test_OptimizePipelines_SameResult(t *testing.T) {
+	streams := []labels.Labels{
+		labels.FromStrings("app", "foo"),
+		labels.FromStrings("app", "foo", "level", "error"),
+	}
+	structuredMetadata := [][]labels.Label{
+		nil,
+		{{Name: "level", Value: "error"}},
+		{{Name: "lvl", Value: "info"}},
+	}
+
+	f := func(q optimizerQuery) bool {
+		query := `{app="foo"} ` + strings.Join(q.stages, " ")
+		expr, err := syntax.ParseLogSelector(query, true)
+		if err != nil {
+			// e.g. a line filter following keep is parsed as a matcher of the kept labels.
+			return true
+		}
+		optimized, err := OptimizePipelines(expr)
+		require.NoError(t, err, query)
+
+		for _, stream := range streams {
+			for _, line := range optimizerLines {
+				for _, sm := range structuredMetadata {
+					msg := fmt.Sprintf("query: %s\noptimized: %s\nstream: %s\nline: %s\nmetadata: %v", query, optimized, stream, line, sm)
+					expectedLine, expectedLbs, expectedOk := processLine(t, expr, stream, line, sm)
+					actualLine, actualLbs, actualOk := processLine(t, optimized, stream, line, sm)
+					require.Equal(t, expectedOk, actualOk, msg)
+					if !expectedOk {
+						continue
+					}
+					require.Equal(t, expectedLine, actualLine, msg)
+					require.Equal(t, expectedLbs, actualLbs, msg)
+				}
+			}
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(f, &quick.Config{MaxCount: 1500, Rand: rand.New(rand.NewSource(1))}))
+}
+
+// processLine processes the line with a new pipeline of the expression.
+// Parsers cache the names of the keys they extract across lines, which would make the results depend on the
+// lines that reached them before, and the optimized pipelines drop lines ahead of the parsers.
+func processLine(t *testing.T, expr syntax.LogSelectorExpr, stream labels.Labels, line string, structuredMetadata []labels.Label) (string, string, bool) {
+	p, err := expr.Pipeline()
+	require.NoError(t, err, expr.String())
+	l, lbs, ok := p.ForStream(stream).ProcessString(0, line, append([]labels.Label(nil), structuredMetadata...)...)
+	if !ok {
+		return "", "", false
+	}
+	return l, lbs.Labels().String(), true
+}
@@ -0,0 +1,267 @@
+package queryblocks
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/dskit/kv/memberlist"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/util"
+)
+
+// Block blocks the queries of a tenant matching either its hash, its regex or its fingerprint until it expires.
+type Block struct {
+	ID     string `json:"id"`
+	Tenant string `json:"tenant"`
+
+	// Hash is the hash of the query, as logged in the query_hash field of the query statistics.
+	Hash uint32 `json:"hash,omitempty"`
+	// Regex matches the query.
+	Regex string `json:"regex,omitempty"`
+	// Fingerprint is the fingerprint of the query, see Fingerprint.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Types are the types of the blocked queries, all the types are blocked if empty.
+	Types []string `json:"types,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Deleted marks the tombstone of a deleted block, so the deletion is propagated to all the replicas.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// placeholder replaces the literals of the queries before they are fingerprinted.
+const placeholder = "?"
+
+// Fingerprint returns the fingerprint of a query, the hash of its normalized form with the label values, filter
+// values and numbers replaced by a placeholder, so queries only differing by their formatting or their literals
+// have the same fingerprint.
+func Fingerprint(expr syntax.Expr) string {
+	normalized, err := syntax.Clone(expr)
+	if err != nil {
+		// the sharded expressions can't be cloned, they are not received by the query frontends.
+		return strconv.FormatUint(uint64(util.HashedQuery(expr.String())), 16)
+	}
+	normalized.Walk(func(e syntax.Expr) {
+		switch e := e.(type) {
+		case *syntax.MatchersExpr:
+			for _, m := range e.Mts {
+				m.Value = placeholder
+			}
+		case *syntax.LineFilterExpr:
+			normalizeLineFilter(e)
+		case *syntax.LabelFilterExpr:
+			normalizeLabelFilter(e.LabelFilterer)
+		case *syntax.LogRange:
+			if e.Unwrap != nil {
+				for _, f := range e.Unwrap.PostFilters {
+					normalizeLabelFilter(f)
+				}
+			}
+		case *syntax.RangeAggregationExpr:
+			// the parameters may be shared with the query.
+			if e.Params != nil {
+				e.Params = new(float64)
+			}
+			if e.Args != nil {
+				e.Args = make([]float64, len(e.Args))
+			}
+		case *syntax.VectorAggregationExpr:
+			e.Params = 0
+		case *syntax.LiteralExpr:
+			e.Val = 0
+		case *syntax.VectorExpr:
+			e.Val = 0
+		}
+	})
+	return strconv.FormatUint(uint64(util.HashedQuery(normalized.String())), 16)
+}
+
+func normalizeLineFilter(f *syntax.LineFilterExpr) {
+	// the alternatives of a filter are not walked.
+	for ; f != nil; f = f.Or {
+		f.Match = placeholder
+	}
+}
+
+func normalizeLabelFilter(f log.LabelFilterer) {
+	switch f := f.(type) {
+	case *log.BinaryLabelFilter:
+		normalizeLabelFilter(f.Left)
+		normalizeLabelFilter(f.Right)
+	case *log.StringLabelFilter:
+		if f.Matcher != nil {
+			f.Value = placeholder
+		}
+	case *log.LineFilterLabelFilter:
+		if f.Matcher != nil {
+			// the filter is only used by the string of the regex filters, to print their regex.
+			f.Value = placeholder
+			f.Filter = nil
+		}
+	case *log.NoopLabelFilter:
+		if f.Matcher != nil {
+			f.Value = placeholder
+		}
+	case *log.NumericLabelFilter:
+		f.Value = 0
+	case *log.DurationLabelFilter:
+		f.Value = 0
+	case *log.BytesLabelFilter:
+		f.Value = 0
+	case *log.IPLabelFilter:
+		f.Pattern = placeholder
+	}
+}
+
+// newerThan tells if the block is a more recent version of the other one. Deletions win ties.
+func (b *Block) newerThan(other *Block) bool {
+	if b.UpdatedAt.Equal(other.UpdatedAt) {
+		return b.Deleted && !other.Deleted
+	}
+	return b.UpdatedAt.After(other.UpdatedAt)
+}
+
+func (b *Block) active(now time.Time) bool {
+	return !b.Deleted && now.Before(b.ExpiresAt)
+}
+
+// expiredTombstone tells if the block has been a tombstone since before the limit.
+func (b *Block) expiredTombstone(limit time.Time) bool {
+	return b.tombstoneTime().Before(limit)
+}
+
+// tombstoneTime is when the block became a tombstone, either deleted or expired.
+func (b *Block) tombstoneTime() time.Time {
+	if b.Deleted {
+		return b.UpdatedAt
+	}
+	return b.ExpiresAt
+}
+
+func (b *Block) clone() *Block {
+	clone := *b
+	clone.Types = append([]string(nil), b.Types...)
+	return &clone
+}
+
+// Desc is the value of the query blocks shared in the key-value store.
+type Desc struct {
+	Blocks map[string]*Block `json:"blocks"`
+}
+
+func NewDesc() *Desc {
+	return &Desc{Blocks: map[string]*Block{}}
+}
+
+// Merge implements the memberlist.Mergeable interface.
+// Blocks are merged by ID, the most recently updated version of a block wins. A local CAS removes the tombstones
+// older than the retention which are missing from the new value.
+func (d *Desc) Merge(mergeable memberlist.Mergeable, localCAS bool) (memberlist.Mergeable, error) {
+	if mergeable == nil {
+		return nil, nil
+	}
+	other, ok := mergeable.(*Desc)
+	if !ok {
+		return nil, fmt.Errorf("expected *queryblocks.Desc, got %T", mergeable)
+	}
+	if other == nil {
+		return nil, nil
+	}
+	if d.Blocks == nil {
+		d.Blocks = map[string]*Block{}
+	}
+
+	// the tombstones older than the retention are removed, they must not be added back by the replicas which
+	// haven't removed them yet.
+	limit := time.Now().Add(-tombstoneRetention)
+	var change *Desc
+	for id, b := range other.Blocks {
+		current, ok := d.Blocks[id]
+		if ok && !b.newerThan(current) {
+			continue
+		}
+		if !ok && b.expiredTombstone(limit) {
+			continue
+		}
+		d.Blocks[id] = b
+		if change == nil {
+			change = NewDesc()
+		}
+		change.Blocks[id] = b
+	}
+	if localCAS {
+		for id, b := range d.Blocks {
+			if _, ok := other.Blocks[id]; ok || !b.expiredTombstone(limit) {
+				continue
+			}
+			delete(d.Blocks, id)
+			if change == nil {
+				change = NewDesc()
+			}
+			change.Blocks[id] = b
+		}
+	}
+	if change == nil {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// MergeContent implements the memberlist.Mergeable interface.
+func (d *Desc) MergeContent() []string {
+	ids := make([]string, 0, len(d.Blocks))
+	for id := range d.Blocks {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// RemoveTombstones implements the memberlist.Mergeable interface.
+// Deleted and expired blocks are both tombstones.
+func (d *Desc) RemoveTombstones(limit time.Time) (total, removed int) {
+	now := time.Now()
+	for id, b := range d.Blocks {
+		if b.active(now) {
+			continue
+		}
+		total++
+		if limit.IsZero() || b.expiredTombstone(limit) {
+			delete(d.Blocks, id)
+			removed++
+		}
+	}
+	return total, removed
+}
+
+// Clone implements the memberlist.Mergeable interface.
+func (d *Desc) Clone() memberlist.Mergeable {
+	clone := NewDesc()
+	for id, b := range d.Blocks {
+		clone.Blocks[id] = b.clone()
+	}
+	return clone
+}
+
+// JSONCodec encodes the query blocks shared in the key-value store.
+var JSONCodec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(data []byte) (interface{}, error) {
+	desc := NewDesc()
+	if err := jsoniter.ConfigFastest.Unmarshal(data, desc); err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
+
+func (jsonCodec) Encode(obj interface{}) ([]byte, error) {
+	return jsoniter.ConfigFastest.Marshal(obj)
+}
+
+func (jsonCodec) CodecID() string { return "queryblocks.jsonCodec" }
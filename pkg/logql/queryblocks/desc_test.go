@@ -0,0 +1,133 @@
+package queryblocks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+func TestDesc_Merge(t *testing.T) {
+	now := time.Now()
+	block := func(id string, updatedAt time.Time, deleted bool) *Block {
+		return &Block{ID: id, Tenant: "tenant", Hash: 1, ExpiresAt: now.Add(time.Hour), UpdatedAt: updatedAt, Deleted: deleted}
+	}
+
+	desc := NewDesc()
+	desc.Blocks["a"] = block("a", now, false)
+
+	// new blocks are added.
+	change, err := desc.Merge(&Desc{Blocks: map[string]*Block{"b": block("b", now, false)}}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, change.MergeContent())
+	require.Len(t, desc.Blocks, 2)
+
+	// the same state doesn't change anything.
+	change, err = desc.Merge(desc.Clone(), false)
+	require.NoError(t, err)
+	require.Nil(t, change)
+
+	// older versions are ignored.
+	change, err = desc.Merge(&Desc{Blocks: map[string]*Block{"a": block("a", now.Add(-time.Minute), true)}}, false)
+	require.NoError(t, err)
+	require.Nil(t, change)
+	require.False(t, desc.Blocks["a"].Deleted)
+
+	// deletions win ties.
+	change, err = desc.Merge(&Desc{Blocks: map[string]*Block{"a": block("a", now, true)}}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, change.MergeContent())
+	require.True(t, desc.Blocks["a"].Deleted)
+
+	// the deleted block is a tombstone.
+	total, removed := desc.RemoveTombstones(now.Add(-time.Minute))
+	require.Equal(t, 1, total)
+	require.Equal(t, 0, removed)
+	total, removed = desc.RemoveTombstones(time.Time{})
+	require.Equal(t, 1, total)
+	require.Equal(t, 1, removed)
+	require.Equal(t, []string{"b"}, desc.MergeContent())
+}
+
+func TestDesc_MergeExpiredTombstones(t *testing.T) {
+	now := time.Now()
+	expired := &Block{ID: "expired", Tenant: "tenant", Hash: 1, ExpiresAt: now.Add(-2 * tombstoneRetention), UpdatedAt: now.Add(-3 * tombstoneRetention)}
+	active := &Block{ID: "active", Tenant: "tenant", Hash: 2, ExpiresAt: now.Add(time.Hour), UpdatedAt: now}
+
+	// the expired tombstones of the other replicas aren't added back.
+	desc := NewDesc()
+	change, err := desc.Merge(&Desc{Blocks: map[string]*Block{"expired": expired, "active": active}}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"active"}, change.MergeContent())
+	require.Equal(t, []string{"active"}, desc.MergeContent())
+
+	// a local CAS without the expired tombstones removes them.
+	desc.Blocks["expired"] = expired
+	_, err = desc.Merge(&Desc{Blocks: map[string]*Block{"active": active}}, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"active"}, desc.MergeContent())
+
+	// merges from the other replicas don't remove the blocks they are missing.
+	desc.Blocks["expired"] = expired
+	_, err = desc.Merge(&Desc{Blocks: map[string]*Block{"active": active}}, false)
+	require.NoError(t, err)
+	require.Len(t, desc.Blocks, 2)
+}
+
+func TestJSONCodec(t *testing.T) {
+	desc := NewDesc()
+	desc.Blocks["a"] = &Block{ID: "a", Tenant: "tenant", Regex: ".*", Types: []string{"metric"}, ExpiresAt: time.Unix(100, 0).UTC()}
+
+	data, err := JSONCodec.Encode(desc)
+	require.NoError(t, err)
+	decoded, err := JSONCodec.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, desc, decoded)
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(query string) string {
+		expr, err := syntax.ParseExpr(query)
+		require.NoError(t, err)
+		return Fingerprint(expr)
+	}
+
+	for _, tc := range []struct {
+		a, b  string
+		equal bool
+	}{
+		// formatting.
+		{`sum(rate({app="foo"} |= "error" [5m]))`, `sum(rate({app="foo"}|="error"[5m]))`, true},
+		// literals.
+		{`sum(rate({app="foo"} |= "error" [5m]))`, `sum(rate({app="bar"} |= "timeout" [5m]))`, true},
+		{`{app="foo"} |= "a" or "b" != "c"`, `{app="bar"} |= "d" or "e" != "f"`, true},
+		{`{app="foo"} | logfmt | level="error" or status=~"5.." | duration > 10s | size > 1KB | count > 5`, `{app="foo"} | logfmt | level="warn" or status=~"4.." | duration > 1m | size > 2MB | count > 50`, true},
+		{`sum by (app) (count_over_time({app="foo"} | json | latency > 1 [5m])) > 100`, `sum by (app) (count_over_time({app="bar"} | json | latency > 2 [5m])) > 10`, true},
+		{`quantile_over_time(0.99, {app="foo"} | logfmt | unwrap latency | latency < 10 [5m])`, `quantile_over_time(0.5, {app="foo"} | logfmt | unwrap latency | latency < 20 [5m])`, true},
+		{`topk(10, sum by (app) (rate({app="foo"}[5m])))`, `topk(5, sum by (app) (rate({app="foo"}[5m])))`, true},
+		// structure.
+		{`sum(rate({app="foo"} |= "error" [5m]))`, `sum(rate({job="foo"} |= "error" [5m]))`, false},
+		{`sum(rate({app="foo"} |= "error" [5m]))`, `sum(rate({app="foo"} != "error" [5m]))`, false},
+		{`sum(rate({app="foo"} |= "error" [5m]))`, `sum(count_over_time({app="foo"} |= "error" [5m]))`, false},
+		{`{app="foo"} | logfmt | level="error"`, `{app="foo"} | logfmt | level!="error"`, false},
+		{`{app="foo"} | logfmt | level="error"`, `{app="foo"} | json | level="error"`, false},
+	} {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.equal, fingerprint(tc.a) == fingerprint(tc.b))
+		})
+	}
+
+	// the query is not changed.
+	for _, query := range []string{
+		`{app="foo"} | logfmt | level=~"err.*" |= "a" or "b"`,
+		`quantile_over_time(0.99, {app="foo"} | logfmt | unwrap latency | latency < 10 [5m]) > 1`,
+	} {
+		expr, err := syntax.ParseExpr(query)
+		require.NoError(t, err)
+		before := expr.String()
+		Fingerprint(expr)
+		require.Equal(t, before, expr.String())
+	}
+}
@@ -0,0 +1,202 @@
+package queryblocks
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/util"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+// createRequest is the body of a request creating a block.
+type createRequest struct {
+	Hash        uint32 `json:"hash"`
+	Regex       string `json:"regex"`
+	Fingerprint string `json:"fingerprint"`
+	// Query blocks the fingerprint of the query.
+	Query string   `json:"query"`
+	Types []string `json:"types"`
+
+	// The block expires after its TTL or at its expiry time.
+	TTL       model.Duration `json:"ttl"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+type response struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
+
+// CreateHandler creates a block for the tenant of the request.
+func (m *Manager) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error()), w)
+		return
+	}
+
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "invalid query block: %s", err.Error()), w)
+		return
+	}
+
+	b := &Block{
+		Tenant:      tenantID,
+		Hash:        req.Hash,
+		Regex:       req.Regex,
+		Fingerprint: req.Fingerprint,
+		Types:       req.Types,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if req.Query != "" {
+		if req.Fingerprint != "" {
+			serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "a query block must have either a query or a fingerprint"), w)
+			return
+		}
+		expr, err := syntax.ParseExpr(req.Query)
+		if err != nil {
+			serverutil.WriteError(err, w)
+			return
+		}
+		b.Fingerprint = Fingerprint(expr)
+	}
+	if req.TTL > 0 {
+		b.ExpiresAt = m.now().Add(time.Duration(req.TTL))
+	}
+	if err := validate(b, m.now()); err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "invalid query block: %s", err.Error()), w)
+		return
+	}
+
+	if err := m.Create(r.Context(), b); err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	util.WriteJSONResponse(w, response{Status: "success", Data: b})
+}
+
+// ListHandler lists the blocks of the tenant of the request.
+func (m *Manager) ListHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error()), w)
+		return
+	}
+	util.WriteJSONResponse(w, response{Status: "success", Data: m.List(tenantID)})
+}
+
+// DeleteHandler deletes the block of the tenant of the request.
+func (m *Manager) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error()), w)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	err = m.Delete(r.Context(), tenantID, id)
+	if errors.Is(err, ErrBlockNotFound) {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusNotFound, "query block %s not found", id), w)
+		return
+	}
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var statusPageTemplate = template.Must(template.New("query-blocks").Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Query Blocks</title>
+</head>
+<body>
+<h1>Query Blocks</h1>
+<p>Current time: {{ .Now }}</p>
+<p>Hits are counted by this query frontend since it started. The regexes are listed by the API of each tenant.</p>
+<table width="100%" border="1">
+    <thead>
+    <tr>
+        <th>ID</th>
+        <th>Tenant</th>
+        <th>Hash</th>
+        <th>Fingerprint</th>
+        <th>Regex</th>
+        <th>Types</th>
+        <th>Created At</th>
+        <th>Expires At</th>
+        <th>Hits</th>
+    </tr>
+    </thead>
+    <tbody>
+    {{ range .Blocks }}
+    <tr{{ if .Expired }} bgcolor="#BEBEBE"{{ end }}>
+        <td>{{ .ID }}</td>
+        <td>{{ .Tenant }}</td>
+        <td>{{ if .Hash }}{{ .Hash }}{{ end }}</td>
+        <td>{{ .Fingerprint }}</td>
+        <td>{{ if .Regex }}hidden{{ end }}</td>
+        <td>{{ range $i, $t := .Types }}{{ if $i }}, {{ end }}{{ $t }}{{ end }}</td>
+        <td>{{ .CreatedAt }}</td>
+        <td>{{ .ExpiresAt }}{{ if .Expired }} (expired){{ end }}</td>
+        <td>{{ .Hits }}</td>
+    </tr>
+    {{ end }}
+    </tbody>
+</table>
+</body>
+</html>`))
+
+// statusBlock is a block on the status page. The page is not authenticated, so it leaves out the regexes, which
+// are parts of the queries of the tenants.
+type statusBlock struct {
+	ID          string    `json:"id"`
+	Tenant      string    `json:"tenant"`
+	Hash        uint32    `json:"hash,omitempty"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	Regex       bool      `json:"regex,omitempty"`
+	Types       []string  `json:"types,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Expired     bool      `json:"expired"`
+	Hits        int64     `json:"hits"`
+}
+
+// StatusHandler renders the blocks of all the tenants with their hits.
+func (m *Manager) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	listed := m.List("")
+	blocks := make([]statusBlock, 0, len(listed))
+	for _, b := range listed {
+		blocks = append(blocks, statusBlock{
+			ID:          b.ID,
+			Tenant:      b.Tenant,
+			Hash:        b.Hash,
+			Fingerprint: b.Fingerprint,
+			Regex:       b.Regex != "",
+			Types:       b.Types,
+			CreatedAt:   b.CreatedAt,
+			ExpiresAt:   b.ExpiresAt,
+			Expired:     b.Expired,
+			Hits:        b.Hits,
+		})
+	}
+	util.RenderHTTPResponse(w, struct {
+		Now    time.Time     `json:"now"`
+		Blocks []statusBlock `json:"blocks"`
+	}{
+		Now:    m.now(),
+		Blocks: blocks,
+	}, statusPageTemplate, r)
+}
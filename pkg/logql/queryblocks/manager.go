@@ -0,0 +1,344 @@
+package queryblocks
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/regexp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/util"
+)
+
+const (
+	// blocksKey is the key of the query blocks in the key-value store.
+	blocksKey = "query-blocks"
+
+	// tombstoneRetention is how long deleted and expired blocks are kept in the key-value store,
+	// so their removal is propagated to all the replicas.
+	tombstoneRetention = time.Hour
+
+	// tombstonesCleanupPeriod is how often the tombstones older than the retention are removed from the key-value
+	// store, so they don't stay in the value when no block is created.
+	tombstonesCleanupPeriod = 10 * time.Minute
+)
+
+var (
+	ErrBlockNotFound = errors.New("query block not found")
+
+	queryTypes = []string{logql.QueryTypeMetric, logql.QueryTypeFilter, logql.QueryTypeLimited}
+)
+
+type Config struct {
+	Enabled bool      `yaml:"enabled"`
+	KVStore kv.Config `yaml:"kvstore" doc:"description=The key-value store used to share the query blocks across the query frontends."`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Enable the API blocking queries at runtime, in addition to the blocked_queries limit. The blocks are shared across the query frontends through the key-value store.")
+	cfg.KVStore.RegisterFlagsWithPrefix(prefix, "query-blocks/", f)
+}
+
+// Manager keeps the query blocks created with the API in sync with the key-value store and blocks the queries
+// matching them. The hits of the blocks are counted by each replica.
+type Manager struct {
+	services.Service
+
+	kv     kv.Client
+	logger log.Logger
+	now    func() time.Time
+
+	mtx sync.RWMutex
+	// blocks are the blocks of each tenant, deleted blocks excluded.
+	blocks map[string][]*matcher
+	hits   map[string]*atomic.Int64
+}
+
+// matcher is a block with its compiled regex.
+type matcher struct {
+	*Block
+	regex *regexp.Regexp
+}
+
+func NewManager(cfg Config, reg prometheus.Registerer, logger log.Logger) (*Manager, error) {
+	client, err := kv.NewClient(cfg.KVStore, JSONCodec, kv.RegistererWithKVName(reg, "query-blocks"), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the query blocks key-value store client: %w", err)
+	}
+	return newManager(client, logger), nil
+}
+
+func newManager(client kv.Client, logger log.Logger) *Manager {
+	m := &Manager{
+		kv:     client,
+		logger: logger,
+		now:    time.Now,
+		blocks: map[string][]*matcher{},
+		hits:   map[string]*atomic.Int64{},
+	}
+	m.Service = services.NewBasicService(m.starting, m.running, nil).WithName("query blocks")
+	return m
+}
+
+func (m *Manager) starting(ctx context.Context) error {
+	return m.load(ctx)
+}
+
+func (m *Manager) running(ctx context.Context) error {
+	go m.kv.WatchKey(ctx, blocksKey, func(v interface{}) bool {
+		if desc, ok := v.(*Desc); ok {
+			m.update(desc)
+		}
+		return true
+	})
+
+	ticker := time.NewTicker(tombstonesCleanupPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := m.removeTombstones(ctx); err != nil {
+				level.Warn(m.logger).Log("msg", "failed to remove the query block tombstones", "err", err)
+			}
+		}
+	}
+}
+
+// removeTombstones removes the tombstones older than the retention from the key-value store.
+func (m *Manager) removeTombstones(ctx context.Context) error {
+	limit := m.now().Add(-tombstoneRetention)
+	return m.kv.CAS(ctx, blocksKey, func(in interface{}) (interface{}, bool, error) {
+		desc, _ := in.(*Desc)
+		if desc == nil {
+			return nil, false, nil
+		}
+		if _, removed := desc.RemoveTombstones(limit); removed == 0 {
+			return nil, false, nil
+		}
+		return desc, true, nil
+	})
+}
+
+// load updates the blocks with the ones of the key-value store.
+func (m *Manager) load(ctx context.Context) error {
+	v, err := m.kv.Get(ctx, blocksKey)
+	if err != nil {
+		return fmt.Errorf("failed to load the query blocks: %w", err)
+	}
+	desc, _ := v.(*Desc)
+	m.update(desc)
+	return nil
+}
+
+func (m *Manager) update(desc *Desc) {
+	blocks := map[string][]*matcher{}
+	if desc != nil {
+		for _, b := range desc.Blocks {
+			if b.Deleted {
+				continue
+			}
+			mt := &matcher{Block: b}
+			if b.Regex != "" {
+				regex, err := regexp.Compile(b.Regex)
+				if err != nil {
+					level.Error(m.logger).Log("msg", "query block regex does not compile", "id", b.ID, "regex", b.Regex, "err", err)
+					continue
+				}
+				mt.regex = regex
+			}
+			blocks[b.Tenant] = append(blocks[b.Tenant], mt)
+		}
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	// the hits of the removed blocks are dropped.
+	hits := make(map[string]*atomic.Int64, len(m.hits))
+	for _, tenantBlocks := range blocks {
+		for _, b := range tenantBlocks {
+			if h, ok := m.hits[b.ID]; ok {
+				hits[b.ID] = h
+				continue
+			}
+			hits[b.ID] = atomic.NewInt64(0)
+		}
+	}
+	m.blocks = blocks
+	m.hits = hits
+}
+
+// Create validates the block and adds it to the blocks of its tenant.
+func (m *Manager) Create(ctx context.Context, b *Block) error {
+	now := m.now()
+	if err := validate(b, now); err != nil {
+		return err
+	}
+	b.ID = uuid.NewString()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+
+	err := m.kv.CAS(ctx, blocksKey, func(in interface{}) (interface{}, bool, error) {
+		desc, _ := in.(*Desc)
+		if desc == nil {
+			desc = NewDesc()
+		}
+		desc.RemoveTombstones(now.Add(-tombstoneRetention))
+		desc.Blocks[b.ID] = b
+		return desc, true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the query block: %w", err)
+	}
+	return m.load(ctx)
+}
+
+// Delete deletes the block of the tenant.
+func (m *Manager) Delete(ctx context.Context, tenantID, id string) error {
+	now := m.now()
+	err := m.kv.CAS(ctx, blocksKey, func(in interface{}) (interface{}, bool, error) {
+		desc, _ := in.(*Desc)
+		if desc == nil {
+			return nil, false, ErrBlockNotFound
+		}
+		b, ok := desc.Blocks[id]
+		if !ok || b.Tenant != tenantID || b.Deleted {
+			return nil, false, ErrBlockNotFound
+		}
+		b.Deleted = true
+		b.UpdatedAt = now
+		return desc, true, nil
+	})
+	if errors.Is(err, ErrBlockNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete the query block: %w", err)
+	}
+	return m.load(ctx)
+}
+
+// BlockStatus is a block with its number of hits in this replica.
+type BlockStatus struct {
+	*Block
+	Expired bool  `json:"expired"`
+	Hits    int64 `json:"hits"`
+}
+
+// List returns the blocks of the tenant, or the blocks of all the tenants if the tenant is empty.
+func (m *Manager) List(tenantID string) []BlockStatus {
+	now := m.now()
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	res := []BlockStatus{}
+	for t, blocks := range m.blocks {
+		if tenantID != "" && t != tenantID {
+			continue
+		}
+		for _, b := range blocks {
+			res = append(res, BlockStatus{
+				Block:   b.Block,
+				Expired: !b.active(now),
+				Hits:    m.hits[b.ID].Load(),
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Tenant != res[j].Tenant {
+			return res[i].Tenant < res[j].Tenant
+		}
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+	return res
+}
+
+// Blocked returns the block of the tenant matching the query, if any, and counts its hit.
+func (m *Manager) Blocked(tenantID, query string, expr syntax.Expr) *Block {
+	now := m.now()
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	var fingerprint string
+	for _, b := range m.blocks[tenantID] {
+		if !b.active(now) {
+			continue
+		}
+
+		switch {
+		case b.Hash != 0:
+			if b.Hash != util.HashedQuery(query) {
+				continue
+			}
+		case b.regex != nil:
+			if !b.regex.MatchString(query) {
+				continue
+			}
+		case b.Fingerprint != "":
+			if fingerprint == "" {
+				fingerprint = Fingerprint(expr)
+			}
+			if b.Fingerprint != fingerprint {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if len(b.Types) > 0 {
+			typ, _ := logql.QueryType(expr)
+			if !slices.Contains(b.Types, typ) {
+				continue
+			}
+		}
+
+		m.hits[b.ID].Inc()
+		return b.Block
+	}
+	return nil
+}
+
+func validate(b *Block, now time.Time) error {
+	criteria := 0
+	if b.Hash != 0 {
+		criteria++
+	}
+	if b.Regex != "" {
+		criteria++
+		if _, err := regexp.Compile(b.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+	if b.Fingerprint != "" {
+		criteria++
+	}
+	if criteria != 1 {
+		return errors.New("a query block must have exactly one of hash, regex or fingerprint")
+	}
+
+	for _, typ := range b.Types {
+		if !slices.Contains(queryTypes, typ) {
+			return fmt.Errorf("invalid query type %q, expected one of %v", typ, queryTypes)
+		}
+	}
+
+	if !b.ExpiresAt.After(now) {
+		return errors.New("a query block must expire in the future")
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package queryblocks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/util"
+)
+
+func newTestManager(t *testing.T, client *consul.Client) *Manager {
+	m := newManager(client, log.NewNopLogger())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), m))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), m))
+	})
+	return m
+}
+
+func TestManager_Blocked(t *testing.T) {
+	client, closer := consul.NewInMemoryClient(JSONCodec, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+	m := newTestManager(t, client)
+	ctx := context.Background()
+	expires := time.Now().Add(time.Hour)
+
+	metricQuery := `sum(rate({app="foo"} |= "error" [5m]))`
+	expr, err := syntax.ParseExpr(`sum(rate({app="foo"}|="error"[5m]))`)
+	require.NoError(t, err)
+
+	require.Nil(t, m.Blocked("tenant", metricQuery, expr))
+
+	require.NoError(t, m.Create(ctx, &Block{Tenant: "tenant", Fingerprint: Fingerprint(expr), ExpiresAt: expires}))
+	require.NoError(t, m.Create(ctx, &Block{Tenant: "tenant", Regex: `.*app="bar".*`, Types: []string{"filter"}, ExpiresAt: expires}))
+	require.NoError(t, m.Create(ctx, &Block{Tenant: "other", Hash: util.HashedQuery(`{app="foo"}`), ExpiresAt: expires}))
+
+	// the fingerprint matches the query whatever its formatting.
+	b := m.Blocked("tenant", metricQuery, mustParse(t, metricQuery))
+	require.NotNil(t, b)
+	require.Equal(t, Fingerprint(expr), b.Fingerprint)
+
+	// blocks only match their types.
+	require.NotNil(t, m.Blocked("tenant", `{app="bar"} |= "error"`, mustParse(t, `{app="bar"} |= "error"`)))
+	require.Nil(t, m.Blocked("tenant", `{app="bar"}`, mustParse(t, `{app="bar"}`)))
+
+	// blocks only match the queries of their tenant.
+	require.Nil(t, m.Blocked("tenant", `{app="foo"}`, mustParse(t, `{app="foo"}`)))
+	require.NotNil(t, m.Blocked("other", `{app="foo"}`, mustParse(t, `{app="foo"}`)))
+
+	blocks := m.List("tenant")
+	require.Len(t, blocks, 2)
+	require.Equal(t, int64(1), blocks[0].Hits)
+	require.Equal(t, int64(1), blocks[1].Hits)
+
+	// expired blocks no longer match.
+	m.now = func() time.Time { return expires }
+	require.Nil(t, m.Blocked("tenant", metricQuery, expr))
+	require.True(t, m.List("tenant")[0].Expired)
+	m.now = time.Now
+
+	// deleted blocks no longer match.
+	require.ErrorIs(t, m.Delete(ctx, "other", blocks[0].ID), ErrBlockNotFound)
+	require.NoError(t, m.Delete(ctx, "tenant", blocks[0].ID))
+	require.ErrorIs(t, m.Delete(ctx, "tenant", blocks[0].ID), ErrBlockNotFound)
+	require.Nil(t, m.Blocked("tenant", metricQuery, expr))
+	require.Len(t, m.List("tenant"), 1)
+
+	// the hits of the deleted blocks are dropped.
+	require.Len(t, m.hits, 2)
+	require.NotContains(t, m.hits, blocks[0].ID)
+}
+
+func TestManager_SharesBlocks(t *testing.T) {
+	client, closer := consul.NewInMemoryClient(JSONCodec, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+	m1 := newTestManager(t, client)
+	m2 := newTestManager(t, client)
+
+	query := `{app="foo"}`
+	require.NoError(t, m1.Create(context.Background(), &Block{Tenant: "tenant", Hash: util.HashedQuery(query), ExpiresAt: time.Now().Add(time.Hour)}))
+	require.Eventually(t, func() bool {
+		return m2.Blocked("tenant", query, mustParse(t, query)) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_RemoveTombstones(t *testing.T) {
+	client, closer := consul.NewInMemoryClient(JSONCodec, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+	m := newTestManager(t, client)
+	ctx := context.Background()
+
+	require.NoError(t, m.Create(ctx, &Block{Tenant: "tenant", Hash: 1, ExpiresAt: time.Now().Add(time.Hour)}))
+	id := m.List("tenant")[0].ID
+	require.NoError(t, m.Delete(ctx, "tenant", id))
+
+	// the tombstone is kept for the retention period.
+	require.NoError(t, m.removeTombstones(ctx))
+	v, err := client.Get(ctx, blocksKey)
+	require.NoError(t, err)
+	require.Len(t, v.(*Desc).Blocks, 1)
+
+	m.now = func() time.Time { return time.Now().Add(2 * tombstoneRetention) }
+	require.NoError(t, m.removeTombstones(ctx))
+	v, err = client.Get(ctx, blocksKey)
+	require.NoError(t, err)
+	require.Empty(t, v.(*Desc).Blocks)
+}
+
+func TestManager_Handlers(t *testing.T) {
+	client, closer := consul.NewInMemoryClient(JSONCodec, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+	m := newTestManager(t, client)
+
+	router := mux.NewRouter()
+	router.Path("/loki/api/v1/query_blocks").Methods("GET").HandlerFunc(m.ListHandler)
+	router.Path("/loki/api/v1/query_blocks").Methods("POST").HandlerFunc(m.CreateHandler)
+	router.Path("/loki/api/v1/query_blocks/{id}").Methods("DELETE").HandlerFunc(m.DeleteHandler)
+	router.Path("/query_blocks").Methods("GET").HandlerFunc(m.StatusHandler)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/loki/api/v1/query_blocks", `{"regex": "(", "ttl": "1h"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = do("POST", "/loki/api/v1/query_blocks", `{"hash": 1, "regex": ".*", "ttl": "1h"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = do("POST", "/loki/api/v1/query_blocks", `{"hash": 1}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do("POST", "/loki/api/v1/query_blocks", `{"query": "sum(rate({app=\"foo\"}[5m]))", "types": ["metric"], "ttl": "1h"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var created struct {
+		Data Block `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.Equal(t, Fingerprint(mustParse(t, `sum(rate({app="foo"}[5m]))`)), created.Data.Fingerprint)
+	require.WithinDuration(t, time.Now().Add(time.Hour), created.Data.ExpiresAt, time.Minute)
+
+	w = do("GET", "/loki/api/v1/query_blocks", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed struct {
+		Status string        `json:"status"`
+		Data   []BlockStatus `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Equal(t, "success", listed.Status)
+	require.Len(t, listed.Data, 1)
+	require.Equal(t, created.Data.ID, listed.Data[0].ID)
+
+	w = do("GET", "/query_blocks", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), created.Data.ID)
+
+	// the status page leaves out the regexes.
+	w = do("POST", "/loki/api/v1/query_blocks", `{"regex": ".*secret.*", "ttl": "1h"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = do("GET", "/query_blocks", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "secret")
+	req := httptest.NewRequest("GET", "/query_blocks", nil)
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"regex":true`)
+	require.NotContains(t, w.Body.String(), "secret")
+
+	w = do("DELETE", "/loki/api/v1/query_blocks/unknown", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = do("DELETE", "/loki/api/v1/query_blocks/"+created.Data.ID, "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, m.List("tenant"), 1)
+}
+
+func mustParse(t *testing.T, query string) syntax.Expr {
+	expr, err := syntax.ParseExpr(query)
+	require.NoError(t, err)
+	return expr
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	promql_parser "github.com/prometheus/prometheus/promql/parser"
@@ -21,6 +22,14 @@ import (
 // the range.
 type BatchRangeVectorAggregator func([]promql.FPoint) float64
 
+// BatchRangeVectorTimeAggregator aggregates samples for a given range of samples
+// relative to the end of the range, in nanoseconds.
+type BatchRangeVectorTimeAggregator func(int64, []promql.FPoint) float64
+
+// BatchRangeHistogramAggregator aggregates samples for a given range of samples
+// into a native histogram.
+type BatchRangeHistogramAggregator func([]promql.FPoint) *histogram.FloatHistogram
+
 // RangeStreamingAgg streaming aggregates sample for each sample
 type RangeStreamingAgg interface {
 	// agg func works inside the Next func of RangeVectorIterator, agg used to agg each sample.
@@ -30,6 +39,13 @@ type RangeStreamingAgg interface {
 	at() float64
 }
 
+// RangeStreamingHistogramAgg is a RangeStreamingAgg producing a native histogram instead of a float.
+type RangeStreamingHistogramAgg interface {
+	RangeStreamingAgg
+	// histogram works inside the At func of RangeVectorIterator, like at, and provides the histogram of the sample.
+	histogram() *histogram.FloatHistogram
+}
+
 // RangeVectorIterator iterates through a range of samples.
 // To fetch the current vector use `At` with a `BatchRangeVectorAggregator` or `RangeStreamingAgg`.
 type RangeVectorIterator interface {
@@ -55,7 +71,9 @@ func newRangeVectorIterator(
 	if selRange >= step && start != end {
 		overlap = true
 	}
-	if !overlap {
+	// aggregations depending on the end of the range are always evaluated in batches.
+	timeAgg := timeAggregator(expr)
+	if !overlap && timeAgg == nil {
 		_, err := streamingAggregator(expr)
 		if err != nil {
 			return nil, err
@@ -71,21 +89,30 @@ func newRangeVectorIterator(
 			offset:   offset,
 		}, nil
 	}
-	vectorAggregator, err := aggregator(expr)
-	if err != nil {
-		return nil, err
-	}
-	return &batchRangeVectorIterator{
+	batch := &batchRangeVectorIterator{
 		iter:     it,
 		step:     step,
 		end:      end,
 		selRange: selRange,
 		metrics:  map[string]labels.Labels{},
 		window:   map[string]*promql.Series{},
-		agg:      vectorAggregator,
 		current:  start - step, // first loop iteration will set it to start
 		offset:   offset,
-	}, nil
+	}
+	if expr.Operation == syntax.OpRangeTypeHistogram {
+		batch.histogramAgg = histogramOverTime
+		return batch, nil
+	}
+	if timeAgg != nil {
+		batch.timeAgg = timeAgg
+		return batch, nil
+	}
+	vectorAggregator, err := aggregator(expr)
+	if err != nil {
+		return nil, err
+	}
+	batch.agg = vectorAggregator
+	return batch, nil
 }
 
 //batch
@@ -97,6 +124,8 @@ type batchRangeVectorIterator struct {
 	metrics                              map[string]labels.Labels
 	at                                   []promql.Sample
 	agg                                  BatchRangeVectorAggregator
+	timeAgg                              BatchRangeVectorTimeAggregator
+	histogramAgg                         BatchRangeHistogramAggregator
 }
 
 func (r *batchRangeVectorIterator) Next() bool {
@@ -194,11 +223,19 @@ func (r *batchRangeVectorIterator) At() (int64, StepResult) {
 	// convert ts from nano to milli seconds as the iterator work with nanoseconds
 	ts := r.current/1e+6 + r.offset/1e+6
 	for _, series := range r.window {
-		r.at = append(r.at, promql.Sample{
-			F:      r.agg(series.Floats),
+		s := promql.Sample{
 			T:      ts,
 			Metric: series.Metric,
-		})
+		}
+		switch {
+		case r.histogramAgg != nil:
+			s.H = r.histogramAgg(series.Floats)
+		case r.timeAgg != nil:
+			s.F = r.timeAgg(r.current, series.Floats)
+		default:
+			s.F = r.agg(series.Floats)
+		}
+		r.at = append(r.at, s)
 	}
 	return ts, SampleVector(r.at)
 }
@@ -250,11 +287,33 @@ func aggregator(r *syntax.RangeAggregationExpr) (BatchRangeVectorAggregator, err
 		return last, nil
 	case syntax.OpRangeTypeAbsent:
 		return one, nil
+	case syntax.OpRangeTypeCountDistinct:
+		return countDistinctOverTime, nil
+	case syntax.OpRangeTypeDeriv:
+		return deriv, nil
+	case syntax.OpRangeTypeChanges:
+		return changes, nil
+	case syntax.OpRangeTypeResets:
+		return resets, nil
+	case syntax.OpRangeTypeHoltWinters:
+		return holtWinters(r.Args[0], r.Args[1]), nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
 }
 
+// timeAggregator returns the aggregator of the operations depending on the end of the range, or nil.
+func timeAggregator(r *syntax.RangeAggregationExpr) BatchRangeVectorTimeAggregator {
+	switch r.Operation {
+	case syntax.OpRangeTypeDelta:
+		return delta(r.Left.Interval)
+	case syntax.OpRangeTypePredictLinear:
+		return predictLinear(r.Args[0])
+	default:
+		return nil
+	}
+}
+
 // rateLogs calculates the per-second rate of log lines or values extracted
 // from log lines
 func rateLogs(selRange time.Duration, computeValues bool) func(samples []promql.FPoint) float64 {
@@ -504,6 +563,169 @@ func one(_ []promql.FPoint) float64 {
 	return 1.0
 }
 
+// delta calculates the difference between the first and last values of the range,
+// extrapolated to the boundaries of the range like the Prometheus delta function.
+func delta(selRange time.Duration) func(end int64, samples []promql.FPoint) float64 {
+	return func(end int64, samples []promql.FPoint) float64 {
+		if len(samples) < 2 {
+			return 0
+		}
+		var (
+			firstT = samples[0].T
+			lastT  = samples[len(samples)-1].T
+			result = samples[len(samples)-1].F - samples[0].F
+		)
+		// Duration between first/last samples and boundary of range.
+		durationToStart := float64(firstT-(end-selRange.Nanoseconds())) / 1e9
+		durationToEnd := float64(end-lastT) / 1e9
+
+		sampledInterval := float64(lastT-firstT) / 1e9
+		averageDurationBetweenSamples := sampledInterval / float64(len(samples)-1)
+
+		// If the first/last samples are close to the boundaries of the range,
+		// extrapolate the result.
+		extrapolationThreshold := averageDurationBetweenSamples * 1.1
+		extrapolateToInterval := sampledInterval
+		if durationToStart < extrapolationThreshold {
+			extrapolateToInterval += durationToStart
+		} else {
+			extrapolateToInterval += averageDurationBetweenSamples / 2
+		}
+		if durationToEnd < extrapolationThreshold {
+			extrapolateToInterval += durationToEnd
+		} else {
+			extrapolateToInterval += averageDurationBetweenSamples / 2
+		}
+		return result * (extrapolateToInterval / sampledInterval)
+	}
+}
+
+// deriv calculates the per-second derivative of the values using a simple linear regression.
+func deriv(samples []promql.FPoint) float64 {
+	// the first timestamp is close to the values in use and avoids floating point accuracy issues.
+	slope, _ := linearRegression(samples, samples[0].T)
+	return slope
+}
+
+// predictLinear predicts the value duration seconds after the end of the range using a simple linear regression.
+func predictLinear(duration float64) func(end int64, samples []promql.FPoint) float64 {
+	return func(end int64, samples []promql.FPoint) float64 {
+		slope, intercept := linearRegression(samples, end)
+		return slope*duration + intercept
+	}
+}
+
+// linearRegression function is taken from prometheus code promql/functions.go
+// with timestamps in nanoseconds. It returns the slope, and the intercept value at the provided time.
+func linearRegression(samples []promql.FPoint, interceptTime int64) (slope, intercept float64) {
+	var (
+		n          float64
+		sumX, cX   float64
+		sumY, cY   float64
+		sumXY, cXY float64
+		sumX2, cX2 float64
+		initY      float64
+		constY     bool
+	)
+	initY = samples[0].F
+	constY = true
+	for i, sample := range samples {
+		// Set constY to false if any new y values are encountered.
+		if constY && i > 0 && sample.F != initY {
+			constY = false
+		}
+		n += 1.0
+		x := float64(sample.T-interceptTime) / 1e9
+		sumX, cX = kahanSumInc(x, sumX, cX)
+		sumY, cY = kahanSumInc(sample.F, sumY, cY)
+		sumXY, cXY = kahanSumInc(x*sample.F, sumXY, cXY)
+		sumX2, cX2 = kahanSumInc(x*x, sumX2, cX2)
+	}
+	if constY {
+		if math.IsInf(initY, 0) {
+			return math.NaN(), math.NaN()
+		}
+		return 0, initY
+	}
+	sumX += cX
+	sumY += cY
+	sumXY += cXY
+	sumX2 += cX2
+
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+
+	slope = covXY / varX
+	intercept = sumY/n - slope*sumX/n
+	return slope, intercept
+}
+
+func kahanSumInc(inc, sum, c float64) (newSum, newC float64) {
+	t := sum + inc
+	switch {
+	case math.IsInf(t, 0):
+		c = 0
+
+	// Using Neumaier improvement, swap if next term larger than sum.
+	case math.Abs(sum) >= math.Abs(inc):
+		c += (sum - t) + inc
+	default:
+		c += (inc - t) + sum
+	}
+	return t, c
+}
+
+// changes counts the number of times the value changed within the range.
+func changes(samples []promql.FPoint) float64 {
+	var changes float64
+	prev := samples[0].F
+	for _, sample := range samples[1:] {
+		if sample.F != prev && !(math.IsNaN(sample.F) && math.IsNaN(prev)) {
+			changes++
+		}
+		prev = sample.F
+	}
+	return changes
+}
+
+// resets counts the number of times the value decreased within the range, like a counter reset.
+func resets(samples []promql.FPoint) float64 {
+	var resets float64
+	prev := samples[0].F
+	for _, sample := range samples[1:] {
+		if sample.F < prev {
+			resets++
+		}
+		prev = sample.F
+	}
+	return resets
+}
+
+// holtWinters produces a smoothed value of the range using double exponential smoothing,
+// sf is the smoothing factor and tf the trend factor, both between 0 and 1.
+func holtWinters(sf, tf float64) func(samples []promql.FPoint) float64 {
+	return func(samples []promql.FPoint) float64 {
+		// Can't do the smoothing operation with less than two points.
+		if len(samples) < 2 {
+			return samples[len(samples)-1].F
+		}
+		var s0, s1, b float64
+		// Set initial values.
+		s1 = samples[0].F
+		b = samples[1].F - samples[0].F
+
+		// Run the smoothing operation.
+		for i := 1; i < len(samples); i++ {
+			// Scale the last smoothed value with the trend at this point.
+			if i > 1 {
+				b = tf*(s1-s0) + (1-tf)*b
+			}
+			s0, s1 = s1, sf*samples[i].F+(1-sf)*(s1+b)
+		}
+		return s1
+	}
+}
+
 // streaming range agg
 type streamRangeVectorIterator struct {
 	iter                                 iter.PeekingSampleIterator
@@ -588,11 +810,16 @@ func (r *streamRangeVectorIterator) At() (int64, StepResult) {
 	// convert ts from nano to milli seconds as the iterator work with nanoseconds
 	ts := r.current/1e+6 + r.offset/1e+6
 	for lbs, rangeAgg := range r.windowRangeAgg {
-		r.at = append(r.at, promql.Sample{
-			F:      rangeAgg.at(),
+		s := promql.Sample{
 			T:      ts,
 			Metric: r.metrics[lbs],
-		})
+		}
+		if h, ok := rangeAgg.(RangeStreamingHistogramAgg); ok {
+			s.H = h.histogram()
+		} else {
+			s.F = rangeAgg.at()
+		}
+		r.at = append(r.at, s)
 	}
 	return ts, SampleVector(r.at)
 }
@@ -627,6 +854,18 @@ func streamingAggregator(r *syntax.RangeAggregationExpr) (RangeStreamingAgg, err
 		return &LastOverTime{}, nil
 	case syntax.OpRangeTypeAbsent:
 		return &OneOverTime{}, nil
+	case syntax.OpRangeTypeHistogram:
+		return newHistogramOverTime(), nil
+	case syntax.OpRangeTypeCountDistinct:
+		return &CountDistinctOverTime{}, nil
+	case syntax.OpRangeTypeDeriv:
+		return &DerivOverTime{}, nil
+	case syntax.OpRangeTypeChanges:
+		return &ChangesOverTime{}, nil
+	case syntax.OpRangeTypeResets:
+		return &ResetsOverTime{}, nil
+	case syntax.OpRangeTypeHoltWinters:
+		return &HoltWintersOverTime{sf: r.Args[0], tf: r.Args[1]}, nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -851,3 +1090,64 @@ func (a *OneOverTime) agg(_ promql.FPoint) {
 func (a *OneOverTime) at() float64 {
 	return 1.0
 }
+
+type DerivOverTime struct {
+	samples []promql.FPoint
+}
+
+func (a *DerivOverTime) agg(sample promql.FPoint) {
+	a.samples = append(a.samples, sample)
+}
+
+func (a *DerivOverTime) at() float64 {
+	return deriv(a.samples)
+}
+
+type ChangesOverTime struct {
+	prev    float64
+	changes float64
+	hasData bool
+}
+
+func (a *ChangesOverTime) agg(sample promql.FPoint) {
+	if a.hasData && sample.F != a.prev && !(math.IsNaN(sample.F) && math.IsNaN(a.prev)) {
+		a.changes++
+	}
+	a.prev = sample.F
+	a.hasData = true
+}
+
+func (a *ChangesOverTime) at() float64 {
+	return a.changes
+}
+
+type ResetsOverTime struct {
+	prev    float64
+	resets  float64
+	hasData bool
+}
+
+func (a *ResetsOverTime) agg(sample promql.FPoint) {
+	if a.hasData && sample.F < a.prev {
+		a.resets++
+	}
+	a.prev = sample.F
+	a.hasData = true
+}
+
+func (a *ResetsOverTime) at() float64 {
+	return a.resets
+}
+
+type HoltWintersOverTime struct {
+	samples []promql.FPoint
+	sf, tf  float64
+}
+
+func (a *HoltWintersOverTime) agg(sample promql.FPoint) {
+	a.samples = append(a.samples, sample)
+}
+
+func (a *HoltWintersOverTime) at() float64 {
+	return holtWinters(a.sf, a.tf)(a.samples)
+}
@@ -644,3 +644,53 @@ func TestQuantiles(t *testing.T) {
 		}
 	}
 }
+
+func Test_RangeVectorTrendAggregations(t *testing.T) {
+	trendSamples := []logproto.Sample{
+		{Timestamp: time.Unix(10, 0).UnixNano(), Hash: 1, Value: 1.},
+		{Timestamp: time.Unix(20, 0).UnixNano(), Hash: 2, Value: 3.},
+		{Timestamp: time.Unix(30, 0).UnixNano(), Hash: 3, Value: 2.},
+		{Timestamp: time.Unix(40, 0).UnixNano(), Hash: 4, Value: 6.},
+	}
+
+	for _, tc := range []struct {
+		query    string
+		expected float64
+	}{
+		{`deriv({app="foo"} | unwrap v [40s])`, 0.14},
+		{`predict_linear({app="foo"} | unwrap v [40s], 10)`, 6.5},
+		{`delta({app="foo"} | unwrap v [40s])`, 20. / 3},
+		{`changes({app="foo"} | unwrap v [40s])`, 3},
+		{`resets({app="foo"} | unwrap v [40s])`, 1},
+		{`holt_winters({app="foo"} | unwrap v [40s], 0.5, 0.5)`, 5.375},
+	} {
+		expr, err := syntax.ParseSampleExpr(tc.query)
+		require.NoError(t, err)
+		rangeExpr := expr.(*syntax.RangeAggregationExpr)
+
+		for _, mode := range []struct {
+			name       string
+			start, end time.Time
+		}{
+			{"instant", time.Unix(40, 0), time.Unix(40, 0)},
+			{"overlapping steps", time.Unix(30, 0), time.Unix(40, 0)},
+		} {
+			t.Run(fmt.Sprintf("%s %s", tc.query, mode.name), func(t *testing.T) {
+				it, err := newRangeVectorIterator(newfakePeekingSampleIterator(trendSamples), rangeExpr,
+					(40 * time.Second).Nanoseconds(), (10 * time.Second).Nanoseconds(),
+					mode.start.UnixNano(), mode.end.UnixNano(), 0)
+				require.NoError(t, err)
+
+				var vec promql.Vector
+				for it.Next() {
+					_, value := it.At()
+					vec = value.SampleVector()
+				}
+				require.Len(t, vec, 2)
+				for _, s := range vec {
+					require.InDelta(t, tc.expected, s.F, 1e-9)
+				}
+			})
+		}
+	}
+}
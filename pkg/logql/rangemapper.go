@@ -175,6 +175,15 @@ func (m RangeMapper) Map(expr syntax.SampleExpr, vectorAggrPushdown *syntax.Vect
 		}
 		e.Left = lhsMapped
 		return e, nil
+	case *syntax.SubqueryAggregationExpr:
+		// the inner query is split on its own, the outer vector aggregation
+		// cannot be pushed down through the aggregation over time.
+		innerMapped, err := m.Map(e.Left.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left.Left = innerMapped
+		return e, nil
 	case *syntax.LiteralExpr:
 		return e, nil
 	case *syntax.VectorExpr:
@@ -570,6 +579,8 @@ func isSplittableByRange(expr syntax.SampleExpr) bool {
 		return isSplittableByRange(e.SampleExpr) || literalLHS && isSplittableByRange(e.RHS) || literalRHS
 	case *syntax.LabelReplaceExpr:
 		return isSplittableByRange(e.Left)
+	case *syntax.SubqueryAggregationExpr:
+		return isSplittableByRange(e.Left.Left)
 	case *syntax.VectorExpr:
 		return false
 	default:
@@ -301,6 +301,21 @@ func Test_SplitRangeVectorMapping(t *testing.T) {
 			)`,
 			3,
 		},
+
+		// Subqueries
+		{
+			`max_over_time(sum(count_over_time({app="foo"}[3m]))[1h:1m])`,
+			`max_over_time(
+				sum(
+					sum without () (
+						   downstream<sum(count_over_time({app="foo"}[1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum(count_over_time({app="foo"}[1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum(count_over_time({app="foo"}[1m])), shard=<nil>>
+					)
+				)[1h:1m]
+			)`,
+			3,
+		},
 		{
 			`sum(sum_over_time({app="foo"} | unwrap bar [3m]))`,
 			`sum(
@@ -1987,6 +2002,12 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`(sum(last_over_time({app="foo"} | logfmt | unwrap total_count [1d]) by (foo)) or vector(0.000000))`,
 		},
 
+		// should be noop if the inner query of a subquery is not split
+		{
+			`max_over_time(rate({app="foo"}[1m])[1h:1m])`,
+			`max_over_time(rate({app="foo"}[1m])[1h:1m])`,
+		},
+
 		// should be noop if literal expression
 		{
 			`5`,
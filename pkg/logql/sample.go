@@ -0,0 +1,156 @@
+package logql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	v1 "github.com/grafana/loki/v3/pkg/storage/bloom/v1"
+)
+
+// Sampled metric queries read the streams whose fingerprint falls within the first ratio of the fingerprint space.
+// The fingerprints are hashes of the labels of the streams, so the subset is deterministic and uniformly distributed,
+// and the same subset is read by every shard, split and querier of a query.
+
+// SampleBounds returns the fingerprint bounds of the streams read by a query sampled with the given ratio.
+func SampleBounds(ratio float64) v1.FingerprintBounds {
+	if ratio >= 1 {
+		return v1.NewBounds(0, math.MaxUint64)
+	}
+	// the number of fingerprints of the sample, the bounds are inclusive.
+	n := ratio * math.Exp2(64)
+	if n < 1 {
+		return v1.NewBounds(0, 0)
+	}
+	return v1.NewBounds(0, model.Fingerprint(n)-1)
+}
+
+// SampleShards restricts the shards of a query to the streams read by the sample ratio. A query without shards is
+// restricted to a single bounded shard. It returns false if none of the streams of the shards are sampled.
+func SampleShards(shards []string, ratio float64) ([]string, bool, error) {
+	bounds := SampleBounds(ratio)
+	if len(shards) == 0 {
+		return Shards{NewBoundedShard(logproto.Shard{Bounds: logproto.FPBounds(bounds)})}.Encode(), true, nil
+	}
+
+	parsed, _, err := ParseShards(shards)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sampled := make(Shards, 0, len(parsed))
+	for _, shard := range parsed {
+		intersection := shardBounds(shard).Intersection(bounds)
+		if intersection == nil {
+			continue
+		}
+		bounded := logproto.Shard{Bounds: logproto.FPBounds(*intersection)}
+		if shard.Bounded != nil {
+			bounded.Stats = shard.Bounded.Stats
+		}
+		sampled = append(sampled, NewBoundedShard(bounded))
+	}
+	if len(sampled) == 0 {
+		return nil, false, nil
+	}
+	return sampled.Encode(), true, nil
+}
+
+// shardBounds returns the inclusive fingerprint bounds of the shard.
+func shardBounds(shard Shard) v1.FingerprintBounds {
+	if shard.Bounded != nil {
+		return v1.BoundsFromProto(shard.Bounded.Bounds)
+	}
+	from, through := shard.GetFromThrough()
+	// the through of power of two shards is exclusive, except for the last one.
+	if through != math.MaxUint64 {
+		through--
+	}
+	return v1.NewBounds(from, through)
+}
+
+// SampleChunkRefs returns the chunk refs of the streams read by the sample ratio.
+func SampleChunkRefs(group *logproto.ChunkRefGroup, ratio float64) *logproto.ChunkRefGroup {
+	if group == nil {
+		return nil
+	}
+	bounds := SampleBounds(ratio)
+	sampled := &logproto.ChunkRefGroup{Refs: make([]*logproto.ChunkRef, 0, len(group.Refs))}
+	for _, ref := range group.Refs {
+		if bounds.Match(model.Fingerprint(ref.Fingerprint)) {
+			sampled.Refs = append(sampled.Refs, ref)
+		}
+	}
+	return sampled
+}
+
+// ScaleSampledExpr scales the sum and count aggregations of the samples of the streams of a query sampled with
+// the given ratio by the inverse of the ratio, so they estimate the aggregations of all the streams. The other
+// aggregations, like avg, min, max or topk, and the samples of the streams themselves are not scaled.
+func ScaleSampledExpr(expr syntax.SampleExpr, ratio float64) (syntax.SampleExpr, error) {
+	var err error
+	expr.Walk(func(e syntax.Expr) {
+		if _, ok := e.(*syntax.JoinExpr); ok {
+			err = fmt.Errorf("sampling is not supported for %s queries", syntax.OpJoin)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// scale a copy, the parsed expression can be shared with the rest of the request.
+	clone, err := syntax.ParseSampleExpr(expr.String())
+	if err != nil {
+		return nil, err
+	}
+	return scaleSampled(clone, strconv.FormatFloat(1/ratio, 'g', -1, 64))
+}
+
+func scaleSampled(expr syntax.SampleExpr, factor string) (syntax.SampleExpr, error) {
+	var err error
+	switch e := expr.(type) {
+	case *syntax.VectorAggregationExpr:
+		if (e.Operation == syntax.OpTypeSum || e.Operation == syntax.OpTypeCount) && !hasVectorAggregation(e.Left) {
+			return syntax.ParseSampleExpr(fmt.Sprintf("(%s) * %s", e.String(), factor))
+		}
+		e.Left, err = scaleSampled(e.Left, factor)
+	case *syntax.BinOpExpr:
+		if e.SampleExpr, err = scaleSampled(e.SampleExpr, factor); err != nil {
+			return nil, err
+		}
+		e.RHS, err = scaleSampled(e.RHS, factor)
+	case *syntax.LabelReplaceExpr:
+		e.Left, err = scaleSampled(e.Left, factor)
+	case *syntax.SubqueryAggregationExpr:
+		e.Left.Left, err = scaleSampled(e.Left.Left, factor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// hasVectorAggregation returns true if the expression aggregates the samples of several streams, whose scaled
+// result must not be scaled again.
+func hasVectorAggregation(expr syntax.SampleExpr) bool {
+	var found bool
+	expr.Walk(func(e syntax.Expr) {
+		if _, ok := e.(*syntax.VectorAggregationExpr); ok {
+			found = true
+		}
+	})
+	return found
+}
+
+// SampleRelativeError estimates the relative standard error of the aggregations of a query sampled with the given
+// ratio, which read the given number of chunks.
+func SampleRelativeError(ratio float64, chunks int64) float64 {
+	if ratio >= 1 {
+		return 0
+	}
+	return math.Sqrt((1 - ratio) / float64(max(chunks, 1)))
+}
@@ -0,0 +1,145 @@
+package logql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
+)
+
+func TestSampleShards(t *testing.T) {
+	quarter := model.Fingerprint(math.MaxUint64 / 4)
+	bounded := func(min, max model.Fingerprint) string {
+		return NewBoundedShard(logproto.Shard{Bounds: logproto.FPBounds{Min: min, Max: max}}).String()
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		shards   []string
+		ratio    float64
+		expected []string
+	}{
+		{
+			desc:     "not sharded",
+			ratio:    0.25,
+			expected: []string{bounded(0, quarter)},
+		},
+		{
+			desc:     "power of two shard within the sample",
+			shards:   []string{NewPowerOfTwoShard(index.ShardAnnotation{Shard: 0, Of: 8}).String()},
+			ratio:    0.25,
+			expected: []string{bounded(0, quarter/2)},
+		},
+		{
+			desc:     "power of two shard overlapping the sample",
+			shards:   []string{NewPowerOfTwoShard(index.ShardAnnotation{Shard: 0, Of: 2}).String()},
+			ratio:    0.25,
+			expected: []string{bounded(0, quarter)},
+		},
+		{
+			desc:   "power of two shard outside the sample",
+			shards: []string{NewPowerOfTwoShard(index.ShardAnnotation{Shard: 1, Of: 2}).String()},
+			ratio:  0.25,
+		},
+		{
+			desc:     "bounded shard overlapping the sample",
+			shards:   []string{bounded(100, quarter+100)},
+			ratio:    0.25,
+			expected: []string{bounded(100, quarter)},
+		},
+		{
+			desc:   "bounded shard outside the sample",
+			shards: []string{bounded(quarter+1, math.MaxUint64)},
+			ratio:  0.25,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			shards, sampled, err := SampleShards(tc.shards, tc.ratio)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected != nil, sampled)
+			require.Equal(t, tc.expected, shards)
+		})
+	}
+
+	_, _, err := SampleShards([]string{"invalid"}, 0.5)
+	require.Error(t, err)
+}
+
+func TestSampleChunkRefs(t *testing.T) {
+	require.Nil(t, SampleChunkRefs(nil, 0.5))
+
+	in := &logproto.ChunkRefGroup{Refs: []*logproto.ChunkRef{
+		{Fingerprint: 1},
+		{Fingerprint: math.MaxUint64 / 3},
+		{Fingerprint: math.MaxUint64},
+	}}
+	out := SampleChunkRefs(in, 0.5)
+	require.Equal(t, []*logproto.ChunkRef{in.Refs[0], in.Refs[1]}, out.Refs)
+}
+
+func TestScaleSampledExpr(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    `count_over_time({app="foo"}[5m])`,
+			expected: `count_over_time({app="foo"}[5m])`,
+		},
+		{
+			query:    `sum by (level) (count_over_time({app="foo"} | logfmt [5m]))`,
+			expected: `(sum by (level)(count_over_time({app="foo"} | logfmt[5m])) * 20)`,
+		},
+		{
+			query:    `count(rate({app="foo"}[1m]))`,
+			expected: `(count(rate({app="foo"}[1m])) * 20)`,
+		},
+		{
+			query:    `sum(rate({app="foo"} |= "error" [1m])) / sum(rate({app="foo"}[1m]))`,
+			expected: `((sum(rate({app="foo"} |= "error"[1m])) * 20) / (sum(rate({app="foo"}[1m])) * 20))`,
+		},
+		{
+			query:    `topk(5, sum by (host) (bytes_over_time({app="foo"}[1m])))`,
+			expected: `topk(5,(sum by (host)(bytes_over_time({app="foo"}[1m])) * 20))`,
+		},
+		{
+			query:    `sum(sum by (host) (rate({app="foo"}[1m])))`,
+			expected: `sum((sum by (host)(rate({app="foo"}[1m])) * 20))`,
+		},
+		{
+			query:    `avg(rate({app="foo"}[1m]))`,
+			expected: `avg(rate({app="foo"}[1m]))`,
+		},
+		{
+			query:    `max_over_time(sum(rate({app="foo"}[1m]))[1h:5m])`,
+			expected: `max_over_time((sum(rate({app="foo"}[1m])) * 20)[1h:5m])`,
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := syntax.ParseSampleExpr(tc.query)
+			require.NoError(t, err)
+			original := expr.String()
+
+			scaled, err := ScaleSampledExpr(expr, 0.05)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, scaled.String())
+			require.Equal(t, original, expr.String())
+		})
+	}
+
+	expr, err := syntax.ParseSampleExpr(`sum(count_over_time(join(5s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id) [30s]))`)
+	require.NoError(t, err)
+	_, err = ScaleSampledExpr(expr, 0.05)
+	require.Error(t, err)
+}
+
+func TestSampleRelativeError(t *testing.T) {
+	require.Equal(t, 0.0, SampleRelativeError(1, 100))
+	require.InDelta(t, math.Sqrt(0.9/100), SampleRelativeError(0.1, 100), 1e-12)
+	require.InDelta(t, math.Sqrt(0.9), SampleRelativeError(0.1, 0), 1e-12)
+}
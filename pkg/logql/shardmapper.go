@@ -88,6 +88,9 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder, topLevel bool)
 		return e, 0, nil
 	case *syntax.MatchersExpr, *syntax.PipelineExpr:
 		return m.mapLogSelectorExpr(e.(syntax.LogSelectorExpr), r)
+	case *syntax.JoinExpr:
+		// matching entries of both sides can live in different shards
+		return noOp(e, m.shards.Resolver())
 	case *syntax.VectorAggregationExpr:
 		return m.mapVectorAggregationExpr(e, r, topLevel)
 	case *syntax.LabelReplaceExpr:
@@ -96,6 +99,8 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder, topLevel bool)
 		return m.mapRangeAggregationExpr(e, r, topLevel)
 	case *syntax.BinOpExpr:
 		return m.mapBinOpExpr(e, r, topLevel)
+	case *syntax.SubqueryAggregationExpr:
+		return m.mapSubqueryAggregationExpr(e, r)
 	default:
 		return nil, 0, errors.Errorf("unexpected expr type (%T) for ASTMapper type (%T) ", expr, m)
 	}
@@ -333,6 +338,29 @@ func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downs
 	return &cpy, bytesPerShard, nil
 }
 
+// mapSubqueryAggregationExpr shards the inner query of a subquery, which is then evaluated
+// on the frontend for every step of the subquery.
+func (m ShardMapper) mapSubqueryAggregationExpr(expr *syntax.SubqueryAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	// the results of the inner query are not merged with other results,
+	// so it is mapped like a top level query.
+	subMapped, bytesPerShard, err := m.Map(expr.Left.Left, r, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	if isNoOp(expr.Left.Left, subMapped) {
+		return noOp(expr, m.shards.Resolver())
+	}
+	sampleExpr, ok := subMapped.(syntax.SampleExpr)
+	if !ok {
+		return nil, 0, badASTMapping(subMapped)
+	}
+	cpy := *expr
+	sq := *expr.Left
+	sq.Left = sampleExpr
+	cpy.Left = &sq
+	return &cpy, bytesPerShard, nil
+}
+
 // These functions require a different merge strategy than the default
 // concatenation.
 // This is because the same label sets may exist on multiple shards when label-reducing parsing is applied or when
@@ -345,6 +373,8 @@ var rangeMergeMap = map[string]string{
 	syntax.OpRangeTypeBytes:     syntax.OpTypeSum,
 	syntax.OpRangeTypeBytesRate: syntax.OpTypeSum,
 	syntax.OpRangeTypeSum:       syntax.OpTypeSum,
+	// native histograms are summed bucket by bucket, which is exact.
+	syntax.OpRangeTypeHistogram: syntax.OpTypeSum,
 
 	// min & max require taking the min|max of the shards
 	syntax.OpRangeTypeMin: syntax.OpTypeMin,
@@ -358,7 +388,7 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 
 	switch expr.Operation {
 
-	case syntax.OpRangeTypeCount, syntax.OpRangeTypeRate, syntax.OpRangeTypeBytes, syntax.OpRangeTypeBytesRate, syntax.OpRangeTypeSum, syntax.OpRangeTypeMax, syntax.OpRangeTypeMin:
+	case syntax.OpRangeTypeCount, syntax.OpRangeTypeRate, syntax.OpRangeTypeBytes, syntax.OpRangeTypeBytesRate, syntax.OpRangeTypeSum, syntax.OpRangeTypeMax, syntax.OpRangeTypeMin, syntax.OpRangeTypeHistogram:
 		// if the expr can reduce labels, it can cause the same labelset to
 		// exist on separate shards and we'll need to merge the results
 		// accordingly. If it does not reduce labels and has no special grouping
@@ -498,6 +528,47 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			quantile: expr.Params,
 		}, bytesPerShard, nil
 
+	case syntax.OpRangeTypeCountDistinct:
+		potentialConflict := syntax.ReducesLabels(expr)
+		if !potentialConflict && (expr.Grouping == nil || expr.Grouping.Noop()) {
+			return m.mapSampleExpr(expr, r)
+		}
+
+		shards, bytesPerShard, err := m.shards.Resolver().Shards(expr)
+		if err != nil {
+			return nil, 0, err
+		}
+		if shards == 0 {
+			return noOp(expr, m.shards.Resolver())
+		}
+
+		// Unlike quantile sketches, merging HyperLogLog sketches is lossless so
+		// sharding doesn't degrade the estimate and isn't behind a flag.
+		// count_distinct_over_time() by (foo) ->
+		// count_distinct_sketch_eval(count_distinct_merge by (foo)
+		// (__count_distinct_sketch_over_time__() by (foo)))
+
+		downstreams := make([]DownstreamSampleExpr, 0, shards)
+		expr.Operation = syntax.OpRangeTypeCountDistinctSketch
+		for shard := shards - 1; shard >= 0; shard-- {
+			s := NewPowerOfTwoShard(index.ShardAnnotation{
+				Shard: uint32(shard),
+				Of:    uint32(shards),
+			})
+			downstreams = append(downstreams, DownstreamSampleExpr{
+				shard: &ShardWithChunkRefs{
+					Shard: s,
+				},
+				SampleExpr: expr,
+			})
+		}
+
+		return &CountDistinctSketchEvalExpr{
+			countDistinctMergeExpr: &CountDistinctSketchMergeExpr{
+				downstreams: downstreams,
+			},
+		}, bytesPerShard, nil
+
 	case syntax.OpRangeTypeFirst:
 		if !m.firstOverTimeSharding {
 			return noOp(expr, m.shards.Resolver())
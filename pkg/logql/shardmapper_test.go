@@ -356,6 +356,40 @@ func TestMappingStrings(t *testing.T) {
 				downstream<max_over_time({foo="ugh"}|unwrapbaz[1m])by(),shard=1_of_2>
 			)`,
 		},
+		{
+			in: `histogram_over_time({foo="ugh"} | unwrap baz [1m]) by (cluster)`,
+			out: `sum by (cluster) (
+				downstream<histogram_over_time({foo="ugh"}|unwrapbaz[1m])by(cluster),shard=0_of_2>
+				++
+				downstream<histogram_over_time({foo="ugh"}|unwrapbaz[1m])by(cluster),shard=1_of_2>
+			)`,
+		},
+		{
+			in: `sum by (cluster) (histogram_over_time({foo="ugh"} | unwrap baz [1m]))`,
+			out: `sum by (cluster) (
+				downstream<sum by (cluster) (histogram_over_time({foo="ugh"}|unwrapbaz[1m])),shard=0_of_2>
+				++
+				downstream<sum by (cluster) (histogram_over_time({foo="ugh"}|unwrapbaz[1m])),shard=1_of_2>
+			)`,
+		},
+		{
+			in: `count_distinct_over_time({foo="ugh"} | logfmt | unwrap user [1m])`,
+			out: `downstream<count_distinct_over_time({foo="ugh"}|logfmt|unwrapuser[1m]),shard=0_of_2>
+				++ downstream<count_distinct_over_time({foo="ugh"}|logfmt|unwrapuser[1m]),shard=1_of_2>`,
+		},
+		{
+			in: `count_distinct_over_time({foo="ugh"} | logfmt | unwrap user [1m]) by (cluster)`,
+			out: `countDistinctSketchEval<countDistinctSketchMerge<
+				downstream<__count_distinct_sketch_over_time__({foo="ugh"}|logfmt|unwrapuser[1m])by(cluster),shard=1_of_2>
+				++
+				downstream<__count_distinct_sketch_over_time__({foo="ugh"}|logfmt|unwrapuser[1m])by(cluster),shard=0_of_2>
+			>>`,
+		},
+		{
+			// sketches are only merged at the top level.
+			in:  `max by (cluster) (count_distinct_over_time({foo="ugh"} | logfmt | unwrap user [1m]) by (cluster, pod))`,
+			out: `maxby(cluster)(count_distinct_over_time({foo="ugh"}|logfmt|unwrapuser[1m])by(cluster,pod))`,
+		},
 		{
 			in: `avg(avg_over_time({job=~"myapps.*"} |= "stats" | json busy="utilization" | unwrap busy [5m]))`,
 			out: `(
@@ -465,6 +499,30 @@ func TestMappingStrings(t *testing.T) {
 			in:  `count by (foo) (sum by (foo, bar) (rate({job="bar"}[1m])))`,
 			out: `countby(foo)(sumby(foo,bar)(downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=1_of_2>))`,
 		},
+		{
+			// the inner query of a subquery is sharded like a top level query
+			in:  `max_over_time(rate({job="bar"}[1m])[1h:1m])`,
+			out: `max_over_time(downstream<rate({job="bar"}[1m]),shard=0_of_2>++downstream<rate({job="bar"}[1m]),shard=1_of_2>[1h:1m])`,
+		},
+		{
+			in:  `sum(max_over_time(sum by (foo) (rate({job="bar"}[1m]))[1h:] offset 1h))`,
+			out: `sum(max_over_time(sumby(foo)(downstream<sumby(foo)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sumby(foo)(rate({job="bar"}[1m])),shard=1_of_2>)[1h:]offset1h0m0s))`,
+		},
+		{
+			// series of the inner query are not merged across shards
+			in:  `max_over_time(quantile_over_time(0.9, {job="bar"} | unwrap foo [1m])[1h:1m])`,
+			out: `max_over_time(downstream<quantile_over_time(0.9,{job="bar"}|unwrapfoo[1m]),shard=0_of_2>++downstream<quantile_over_time(0.9,{job="bar"}|unwrapfoo[1m]),shard=1_of_2>[1h:1m])`,
+		},
+		{
+			// literals are not sharded
+			in:  `max_over_time(vector(1)[1h:1m])`,
+			out: `max_over_time(vector(1.000000)[1h:1m])`,
+		},
+		{
+			// joins are not sharded
+			in:  `join(5s, {app="a"} |= "x", {app="b"}) on (id)`,
+			out: `join(5s, {app="a"} |= "x", {app="b"}) on (id)`,
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 			ast, err := syntax.ParseExpr(tc.in)
@@ -0,0 +1,76 @@
+package sketch
+
+import (
+	"github.com/axiomhq/hyperloglog"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// CountDistinctSketch estimates the number of distinct values of a set using a HyperLogLog sketch.
+// Sketches are mergeable without loss, which allows to count distinct values across shards.
+type CountDistinctSketch struct {
+	hll *hyperloglog.Sketch
+}
+
+// NewCountDistinctSketch creates a sketch with a precision of 14, giving a standard error of ~0.8%
+// in 16KiB once the sketch leaves its sparse representation.
+func NewCountDistinctSketch() *CountDistinctSketch {
+	return &CountDistinctSketch{hll: hyperloglog.New14()}
+}
+
+// AddHash adds a value already hashed with at least 53 random bits, such as the values
+// extracted by count_distinct_over_time. The hash is mixed again since the sketch uses its
+// highest bits as register index.
+func (s *CountDistinctSketch) AddHash(h uint64) {
+	s.hll.InsertHash(fmix64(h))
+}
+
+// Add adds a value to the sketch.
+func (s *CountDistinctSketch) Add(v string) {
+	s.hll.Insert(unsafeGetBytes(v))
+}
+
+// Estimate returns the estimated number of distinct values added to the sketch.
+func (s *CountDistinctSketch) Estimate() uint64 {
+	return s.hll.Estimate()
+}
+
+// Merge adds the values of the other sketch into s.
+func (s *CountDistinctSketch) Merge(other *CountDistinctSketch) error {
+	return s.hll.Merge(other.hll)
+}
+
+func (s *CountDistinctSketch) MarshalBinary() ([]byte, error) {
+	return s.hll.MarshalBinary()
+}
+
+func CountDistinctSketchFromBinary(b []byte) (*CountDistinctSketch, error) {
+	hll := hyperloglog.New14()
+	if err := hll.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return &CountDistinctSketch{hll: hll}, nil
+}
+
+// ToProto returns the protobuf sample of the sketch for the given timestamp and labels.
+func (s *CountDistinctSketch) ToProto(ts int64, metric []*logproto.LabelPair) (*logproto.CountDistinctSketchSample, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &logproto.CountDistinctSketchSample{
+		Hyperloglog: b,
+		TimestampMs: ts,
+		Metric:      metric,
+	}, nil
+}
+
+// fmix64 is the finalizer of MurmurHash3, it spreads the entropy of every bit of k to all output bits.
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,49 @@
+package sketch
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountDistinctSketch(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000, 200000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			s := NewCountDistinctSketch()
+			for i := 0; i < n; i++ {
+				// every value is added twice, duplicates are not counted.
+				for j := 0; j < 2; j++ {
+					s.AddHash(xxhash.Sum64String(strconv.Itoa(i)) & (1<<53 - 1))
+				}
+			}
+			require.InEpsilon(t, float64(n)+1, float64(s.Estimate())+1, 0.02)
+		})
+	}
+}
+
+func TestCountDistinctSketchMerge(t *testing.T) {
+	// three overlapping shards of 10000 values each, 20000 distinct values in total.
+	shards := make([]*CountDistinctSketch, 3)
+	for i := range shards {
+		shards[i] = NewCountDistinctSketch()
+		for v := i * 5000; v < i*5000+10000; v++ {
+			shards[i].Add(strconv.Itoa(v))
+		}
+	}
+
+	merged := NewCountDistinctSketch()
+	for _, s := range shards {
+		b, err := s.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := CountDistinctSketchFromBinary(b)
+		require.NoError(t, err)
+		require.Equal(t, s.Estimate(), decoded.Estimate())
+		require.NoError(t, merged.Merge(decoded))
+	}
+	require.InEpsilon(t, 20000, float64(merged.Estimate()), 0.02)
+
+	_, err := CountDistinctSketchFromBinary([]byte("not a sketch"))
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 type StepResult interface {
 	SampleVector() promql.Vector
 	QuantileSketchVec() ProbabilisticQuantileVector
+	CountDistinctVec() CountDistinctVector
 }
 
 type SampleVector promql.Vector
@@ -21,6 +22,10 @@ func (p SampleVector) QuantileSketchVec() ProbabilisticQuantileVector {
 	return ProbabilisticQuantileVector{}
 }
 
+func (p SampleVector) CountDistinctVec() CountDistinctVector {
+	return CountDistinctVector{}
+}
+
 // StepEvaluator evaluate a single step of a query.
 type StepEvaluator interface {
 	// while Next returns a promql.Value, the only acceptable types are Scalar and Vector.
@@ -0,0 +1,167 @@
+package logql
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/util"
+)
+
+// defaultSubqueryStep is the resolution of subqueries without a step in instant queries.
+const defaultSubqueryStep = time.Minute
+
+// subqueryParams are the params of the inner query of a subquery.
+type subqueryParams struct {
+	Params
+	expr       syntax.SampleExpr
+	start, end time.Time
+	step       time.Duration
+}
+
+func newSubqueryParams(q Params, sq *syntax.SubqueryExpr) subqueryParams {
+	step := sq.Step
+	if step == 0 {
+		step = q.Step()
+	}
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+	// like Prometheus the steps of the inner query are aligned to multiples of the step,
+	// so that the same points are evaluated whatever the start of the outer query is.
+	start := q.Start().Add(-sq.Offset).Add(-sq.Range).UnixNano()
+	if mod := start % step.Nanoseconds(); mod != 0 {
+		start += step.Nanoseconds() - mod
+	}
+	return subqueryParams{
+		Params: q,
+		expr:   sq.Left,
+		start:  time.Unix(0, start),
+		end:    q.End().Add(-sq.Offset),
+		step:   step,
+	}
+}
+
+func (p subqueryParams) QueryString() string        { return p.expr.String() }
+func (p subqueryParams) Start() time.Time           { return p.start }
+func (p subqueryParams) End() time.Time             { return p.end }
+func (p subqueryParams) Step() time.Duration        { return p.step }
+func (p subqueryParams) GetExpression() syntax.Expr { return p.expr }
+
+// newSubqueryEvaluator evaluates the inner query of the subquery with nextEvFactory
+// and aggregates its results over the range of the subquery.
+func newSubqueryEvaluator(
+	ctx context.Context,
+	nextEvFactory SampleEvaluatorFactory,
+	expr *syntax.SubqueryAggregationExpr,
+	q Params,
+) (StepEvaluator, error) {
+	inner, err := nextEvFactory.NewStepEvaluator(ctx, nextEvFactory, expr.Left.Left, newSubqueryParams(q, expr.Left))
+	if err != nil {
+		return nil, err
+	}
+
+	// the results of the inner query are aggregated like the samples of a range aggregation.
+	rangeExpr := &syntax.RangeAggregationExpr{
+		Left: &syntax.LogRange{
+			Interval: expr.Left.Range,
+			Offset:   expr.Left.Offset,
+		},
+		Operation: expr.Operation,
+		Params:    expr.Params,
+		Args:      expr.Args,
+	}
+	it, err := newRangeVectorIterator(
+		iter.NewPeekingSampleIterator(newStepEvaluatorSampleIterator(inner)), rangeExpr,
+		expr.Left.Range.Nanoseconds(),
+		q.Step().Nanoseconds(),
+		q.Start().UnixNano(), q.End().UnixNano(), expr.Left.Offset.Nanoseconds(),
+	)
+	if err != nil {
+		util.LogErrorWithContext(ctx, "closing subquery", inner.Close)
+		return nil, err
+	}
+
+	return &SubqueryEvaluator{
+		RangeVectorEvaluator: RangeVectorEvaluator{iter: it},
+		expr:                 expr,
+		inner:                inner,
+	}, nil
+}
+
+// SubqueryEvaluator aggregates the results of the inner query of a subquery over time.
+type SubqueryEvaluator struct {
+	RangeVectorEvaluator
+
+	expr  *syntax.SubqueryAggregationExpr
+	inner StepEvaluator
+}
+
+// stepEvaluatorSampleIterator iterates over the results of a step evaluator as samples.
+// Samples are ordered by time since the steps are.
+type stepEvaluatorSampleIterator struct {
+	ev StepEvaluator
+
+	ts   int64
+	vec  promql.Vector
+	curr int
+
+	labels map[uint64]string
+	lbs    string
+	hash   uint64
+	done   bool
+}
+
+func newStepEvaluatorSampleIterator(ev StepEvaluator) *stepEvaluatorSampleIterator {
+	return &stepEvaluatorSampleIterator{
+		ev:     ev,
+		labels: map[uint64]string{},
+	}
+}
+
+func (it *stepEvaluatorSampleIterator) Next() bool {
+	it.curr++
+	for it.curr >= len(it.vec) {
+		if it.done {
+			return false
+		}
+		next, ts, r := it.ev.Next()
+		if !next {
+			it.done = true
+			it.vec = nil
+			return false
+		}
+		// step evaluators work in milliseconds, samples in nanoseconds.
+		it.ts = ts * int64(time.Millisecond)
+		it.vec = r.SampleVector()
+		it.curr = 0
+	}
+	metric := it.vec[it.curr].Metric
+	it.hash = metric.Hash()
+	lbs, ok := it.labels[it.hash]
+	if !ok {
+		lbs = metric.String()
+		it.labels[it.hash] = lbs
+	}
+	it.lbs = lbs
+	return true
+}
+
+func (it *stepEvaluatorSampleIterator) At() logproto.Sample {
+	return logproto.Sample{
+		Timestamp: it.ts,
+		Value:     it.vec[it.curr].F,
+	}
+}
+
+func (it *stepEvaluatorSampleIterator) Labels() string { return it.lbs }
+
+func (it *stepEvaluatorSampleIterator) StreamHash() uint64 { return it.hash }
+
+func (it *stepEvaluatorSampleIterator) Err() error { return it.ev.Error() }
+
+func (it *stepEvaluatorSampleIterator) Close() error { return it.ev.Close() }
@@ -0,0 +1,91 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// subqueryTestStreams has an error every second for 2 minutes, and two more every second in the 10s after 60s.
+func subqueryTestStreams() []logproto.Stream {
+	stream := logproto.Stream{Labels: `{app="api"}`}
+	for i := int64(1); i <= 120; i++ {
+		stream.Entries = append(stream.Entries,
+			logproto.Entry{Timestamp: time.Unix(i, 0), Line: fmt.Sprintf("error %d", i)},
+			logproto.Entry{Timestamp: time.Unix(i, 1), Line: fmt.Sprintf("ok %d", i)},
+		)
+		if i >= 60 && i < 70 {
+			stream.Entries = append(stream.Entries,
+				logproto.Entry{Timestamp: time.Unix(i, 2), Line: fmt.Sprintf("error %d again", i)},
+				logproto.Entry{Timestamp: time.Unix(i, 3), Line: fmt.Sprintf("error %d and again", i)},
+			)
+		}
+	}
+	return []logproto.Stream{stream}
+}
+
+func TestSubqueryEvaluation(t *testing.T) {
+	app := labels.FromStrings("app", "api")
+	for _, tc := range []struct {
+		query      string
+		start, end int64
+		step       time.Duration
+		expected   promql_parser.Value
+	}{
+		{
+			query: `max_over_time(rate({app="api"} |= "error" [10s])[60s:10s])`,
+			start: 60, end: 180, step: time.Minute,
+			expected: promql.Matrix{
+				{Metric: app, Floats: []promql.FPoint{{T: 60000, F: 1}, {T: 120000, F: 3}}},
+			},
+		},
+		{
+			query: `max_over_time(rate({app="api"} |= "error" [10s])[60s:10s])`,
+			start: 120, end: 120,
+			expected: promql.Vector{
+				{Metric: app, T: 120000, F: 3},
+			},
+		},
+		{
+			// the subquery step defaults to the step of the query.
+			query: `min_over_time(count_over_time({app="api"} |= "error" [10s])[30s:])`,
+			start: 90, end: 120, step: 30 * time.Second,
+			expected: promql.Matrix{
+				{Metric: app, Floats: []promql.FPoint{{T: 90000, F: 10}, {T: 120000, F: 10}}},
+			},
+		},
+		{
+			query: `max_over_time(count_over_time({app="api"} |= "error" [10s])[60s:10s] offset 60s)`,
+			start: 120, end: 120,
+			expected: promql.Vector{
+				{Metric: app, T: 120000, F: 10},
+			},
+		},
+		{
+			query: `count_over_time(sum(count_over_time({app="api"}[10s]))[60s:10s])`,
+			start: 120, end: 120,
+			expected: promql.Vector{
+				{Metric: labels.EmptyLabels(), T: 120000, F: 6},
+			},
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			eng := NewEngine(EngineOpts{}, NewMockQuerier(0, subqueryTestStreams()), NoLimits, nil)
+			params, err := NewLiteralParams(tc.query, time.Unix(tc.start, 0), time.Unix(tc.end, 0), tc.step, 0, logproto.FORWARD, 0, nil, nil)
+			require.NoError(t, err)
+
+			res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res.Data)
+		})
+	}
+}
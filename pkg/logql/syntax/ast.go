@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/grafana/loki/v3/pkg/util"
 
@@ -107,14 +108,15 @@ type StageExpr interface {
 type MultiStageExpr []StageExpr
 
 func (m MultiStageExpr) Pipeline() (log.Pipeline, error) {
-	stages, err := m.stages()
+	stages, err := m.stages(nil)
 	if err != nil {
 		return nil, err
 	}
 	return log.NewPipeline(stages), nil
 }
 
-func (m MultiStageExpr) stages() ([]log.Stage, error) {
+// stages returns the stages in the order they are executed, each wrapped with the recorder if any.
+func (m MultiStageExpr) stages(recorder StageRecorder) ([]log.Stage, error) {
 	c := make([]log.Stage, 0, len(m))
 	for _, e := range m.reorderStages() {
 		p, err := e.Stage()
@@ -124,11 +126,32 @@ func (m MultiStageExpr) stages() ([]log.Stage, error) {
 		if p == log.NoopStage {
 			continue
 		}
+		if recorder != nil {
+			p = recorder(e.String(), p)
+		}
 		c = append(c, p)
 	}
 	return c, nil
 }
 
+// StageRecorder wraps a stage of a pipeline, e.g. to record statistics about the lines going through it.
+// The name is the stage as written in the query.
+type StageRecorder func(name string, stage log.Stage) log.Stage
+
+// RecordStages returns a copy of the expression whose pipelines and extractors wrap their stages with the recorder.
+func RecordStages[T Expr](e T, recorder StageRecorder) (T, error) {
+	copied, err := Clone(e)
+	if err != nil {
+		return e, err
+	}
+	copied.Walk(func(e Expr) {
+		if p, ok := e.(*PipelineExpr); ok {
+			p.recorder = recorder
+		}
+	})
+	return copied, nil
+}
+
 // reorderStages reorders m such that LineFilters
 // are as close to the front of the filter as possible.
 func (m MultiStageExpr) reorderStages() []StageExpr {
@@ -163,6 +186,11 @@ func (m MultiStageExpr) reorderStages() []StageExpr {
 
 			notLineFilters = append(notLineFilters, f)
 
+			combineFilters()
+		case *JSONArrayExpr:
+			// json_array replaces the line with the array elements.
+			notLineFilters = append(notLineFilters, f)
+
 			combineFilters()
 		case *LabelParserExpr:
 			notLineFilters = append(notLineFilters, f)
@@ -269,9 +297,22 @@ type PipelineExpr struct {
 	MultiStages MultiStageExpr
 	Left        *MatchersExpr
 	implicit
+
+	// recorder wraps the stages of the pipeline, see RecordStages.
+	recorder StageRecorder
 }
 
 func newPipelineExpr(left *MatchersExpr, pipeline MultiStageExpr) LogSelectorExpr {
+	var jsonArrays int
+	for _, s := range pipeline {
+		if _, ok := s.(*JSONArrayExpr); ok {
+			jsonArrays++
+		}
+	}
+	if jsonArrays > 1 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("only one %s stage is allowed per pipeline", OpJSONArray), 0, 0))
+	}
+
 	return &PipelineExpr{
 		Left:        left,
 		MultiStages: pipeline,
@@ -319,7 +360,11 @@ func (e *PipelineExpr) String() string {
 }
 
 func (e *PipelineExpr) Pipeline() (log.Pipeline, error) {
-	return e.MultiStages.Pipeline()
+	stages, err := e.MultiStages.stages(e.recorder)
+	if err != nil {
+		return nil, err
+	}
+	return log.NewPipeline(stages), nil
 }
 
 // HasFilter returns true if the pipeline contains stage that can filter out lines.
@@ -340,6 +385,81 @@ func (e *PipelineExpr) HasFilter() bool {
 	return false
 }
 
+// JoinExpr correlates the entries of two log queries having the same values for the On labels
+// within Tolerance of each other.
+// e.g: join(5s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id)
+type JoinExpr struct {
+	Left, Right LogSelectorExpr
+	Operation   string
+	Tolerance   time.Duration
+	On          []string
+	implicit
+}
+
+func newJoinExpr(operation string, tolerance time.Duration, left, right LogSelectorExpr, on []string) *JoinExpr {
+	if tolerance < 0 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("%s tolerance must not be negative", operation), 0, 0))
+	}
+	for _, s := range []LogSelectorExpr{left, right} {
+		if _, ok := s.(*JoinExpr); ok {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("%s cannot be nested", operation), 0, 0))
+		}
+	}
+	return &JoinExpr{
+		Left:      left,
+		Right:     right,
+		Operation: operation,
+		Tolerance: tolerance,
+		On:        on,
+	}
+}
+
+func (e *JoinExpr) isLogSelectorExpr() {}
+
+// Shardable returns false, matching entries can live in different shards.
+func (e *JoinExpr) Shardable(_ bool) bool { return false }
+
+func (e *JoinExpr) Walk(f WalkFn) {
+	f(e)
+	walkAll(f, e.Left, e.Right)
+}
+
+func (e *JoinExpr) Accept(v RootVisitor) { v.VisitJoin(e) }
+
+// Matchers returns the matchers of both sides of the join.
+func (e *JoinExpr) Matchers() []*labels.Matcher {
+	matchers := make([]*labels.Matcher, 0, len(e.Left.Matchers())+len(e.Right.Matchers()))
+	matchers = append(matchers, e.Left.Matchers()...)
+	return append(matchers, e.Right.Matchers()...)
+}
+
+// Pipeline returns an error since each side of the join is selected with its own pipeline.
+func (e *JoinExpr) Pipeline() (log.Pipeline, error) {
+	return nil, fmt.Errorf("%s has no pipeline, its sides must be selected separately", e.Operation)
+}
+
+// HasFilter returns true since entries without a match are filtered out.
+func (e *JoinExpr) HasFilter() bool {
+	return true
+}
+
+func (e *JoinExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	sb.WriteString(model.Duration(e.Tolerance).String())
+	sb.WriteString(", ")
+	sb.WriteString(e.Left.String())
+	sb.WriteString(", ")
+	sb.WriteString(e.Right.String())
+	sb.WriteString(") ")
+	sb.WriteString(OpOn)
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(e.On, ","))
+	sb.WriteString(")")
+	return sb.String()
+}
+
 type LineFilter struct {
 	Ty    log.LineMatchType
 	Match string
@@ -672,6 +792,12 @@ func (e *LabelParserExpr) Stage() (log.Stage, error) {
 		return log.NewUnpackParser(), nil
 	case OpParserTypePattern:
 		return log.NewPatternParser(e.Param)
+	case OpParserTypeXML:
+		return log.NewXMLParser(), nil
+	case OpParserTypeCEF:
+		return log.NewCEFParser(), nil
+	case OpParserTypeLEEF:
+		return log.NewLEEFParser(), nil
 	default:
 		return nil, fmt.Errorf("unknown parser operator: %s", e.Op)
 	}
@@ -736,6 +862,31 @@ func newLineFmtExpr(value string) *LineFmtExpr {
 	}
 }
 
+// LabelPrefilterExpr is a stage added by the optimizer ahead of the parsers, dropping the lines
+// which cannot match a label filter on a parsed label before parsing them.
+// It is not part of the language, pipelines are serialized without it.
+type LabelPrefilterExpr struct {
+	Name  string
+	Value string
+	implicit
+}
+
+func (*LabelPrefilterExpr) isStageExpr() {}
+
+func (e *LabelPrefilterExpr) Shardable(_ bool) bool { return true }
+
+func (e *LabelPrefilterExpr) Stage() (log.Stage, error) {
+	return log.NewLabelPrefilter(e.Name, e.Value), nil
+}
+
+func (e *LabelPrefilterExpr) String() string {
+	return fmt.Sprintf("%s prefilter(%s=%s)", OpPipe, e.Name, strconv.Quote(e.Value))
+}
+
+func (e *LabelPrefilterExpr) Walk(f WalkFn) { f(e) }
+
+func (e *LabelPrefilterExpr) Accept(v RootVisitor) { v.VisitLabelPrefilter(e) }
+
 type DecolorizeExpr struct {
 	implicit
 }
@@ -758,6 +909,66 @@ func (e *DecolorizeExpr) Walk(f WalkFn) { f(e) }
 
 func (e *DecolorizeExpr) Accept(v RootVisitor) { v.VisitDecolorize(e) }
 
+// JSONArrayExpr explodes the log line into one result per element of a json array.
+type JSONArrayExpr struct {
+	Path string
+	implicit
+}
+
+func newJSONArrayExpr(path string) *JSONArrayExpr {
+	if _, err := log.NewJSONArrayExploder(path); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid %s: %s", OpJSONArray, err.Error()), 0, 0))
+	}
+	return &JSONArrayExpr{Path: path}
+}
+
+func (*JSONArrayExpr) isStageExpr() {}
+
+func (e *JSONArrayExpr) Shardable(_ bool) bool { return true }
+
+func (e *JSONArrayExpr) Stage() (log.Stage, error) {
+	return log.NewJSONArrayExploder(e.Path)
+}
+
+func (e *JSONArrayExpr) String() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s %s", OpPipe, OpJSONArray)
+	}
+	return fmt.Sprintf("%s %s %s", OpPipe, OpJSONArray, strconv.Quote(e.Path))
+}
+
+func (e *JSONArrayExpr) Walk(f WalkFn) { f(e) }
+
+func (e *JSONArrayExpr) Accept(v RootVisitor) { v.VisitJSONArray(e) }
+
+// MacroExpr is a reference to a pipeline macro, e.g. `| @nginx_parse`.
+// Macros must be expanded with ExpandMacros before the pipeline is evaluated.
+type MacroExpr struct {
+	Name string
+	implicit
+}
+
+func newMacroExpr(name string) *MacroExpr {
+	return &MacroExpr{Name: name}
+}
+
+func (*MacroExpr) isStageExpr() {}
+
+// Shardable returns false since the stages of the macro are unknown until it is expanded.
+func (e *MacroExpr) Shardable(_ bool) bool { return false }
+
+func (e *MacroExpr) Stage() (log.Stage, error) {
+	return nil, fmt.Errorf("pipeline macro %s%s was not expanded", OpMacro, e.Name)
+}
+
+func (e *MacroExpr) String() string {
+	return fmt.Sprintf("%s %s%s", OpPipe, OpMacro, e.Name)
+}
+
+func (e *MacroExpr) Walk(f WalkFn) { f(e) }
+
+func (e *MacroExpr) Accept(v RootVisitor) { v.VisitMacro(e) }
+
 type DropLabelsExpr struct {
 	dropLabels []log.DropLabel
 	implicit
@@ -946,6 +1157,130 @@ func (j *JSONExpressionParser) String() string {
 	return sb.String()
 }
 
+type XMLExpressionParser struct {
+	Expressions []log.LabelExtractionExpr
+
+	implicit
+}
+
+func newXMLExpressionParser(expressions []log.LabelExtractionExpr) *XMLExpressionParser {
+	_, err := log.NewXMLExpressionParser(expressions)
+	if err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid xml parser: %s", err.Error()), 0, 0))
+	}
+
+	return &XMLExpressionParser{
+		Expressions: expressions,
+	}
+}
+
+func (*XMLExpressionParser) isStageExpr() {}
+
+func (x *XMLExpressionParser) Shardable(_ bool) bool { return true }
+
+func (x *XMLExpressionParser) Walk(f WalkFn) { f(x) }
+
+func (x *XMLExpressionParser) Accept(v RootVisitor) { v.VisitXMLExpressionParser(x) }
+
+func (x *XMLExpressionParser) Stage() (log.Stage, error) {
+	return log.NewXMLExpressionParser(x.Expressions)
+}
+
+func (x *XMLExpressionParser) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpParserTypeXML))
+	for i, exp := range x.Expressions {
+		sb.WriteString(exp.Identifier)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(exp.Expression))
+
+		if i+1 != len(x.Expressions) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
+type CSVParserExpr struct {
+	Columns   []string
+	Delimiter rune
+	Quote     rune
+
+	implicit
+}
+
+// csvOption is a `name="value"` option of the csv parser.
+type csvOption struct {
+	name, value string
+}
+
+func newCSVParserExpr(columns string, options []csvOption) *CSVParserExpr {
+	e := CSVParserExpr{
+		Columns:   strings.Split(columns, ","),
+		Delimiter: log.DefaultCSVDelimiter,
+		Quote:     log.DefaultCSVQuote,
+	}
+
+	for _, o := range options {
+		r, n := utf8.DecodeRuneInString(o.value)
+		switch {
+		case o.name == OpCSVDelimiter && n > 0 && n == len(o.value):
+			e.Delimiter = r
+		case o.name == OpCSVQuote && n == len(o.value):
+			// an empty quote disables quoting.
+			e.Quote = 0
+			if n > 0 {
+				e.Quote = r
+			}
+		case o.name == OpCSVDelimiter, o.name == OpCSVQuote:
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: %s must be a single character", o.name), 0, 0))
+		default:
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: unknown option %s", o.name), 0, 0))
+		}
+	}
+
+	if _, err := log.NewCSVParser(e.Columns, e.Delimiter, e.Quote); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: %s", err.Error()), 0, 0))
+	}
+
+	return &e
+}
+
+func (*CSVParserExpr) isStageExpr() {}
+
+func (e *CSVParserExpr) Shardable(_ bool) bool { return true }
+
+func (e *CSVParserExpr) Walk(f WalkFn) { f(e) }
+
+func (e *CSVParserExpr) Accept(v RootVisitor) { v.VisitCSVParser(e) }
+
+func (e *CSVParserExpr) Stage() (log.Stage, error) {
+	return log.NewCSVParser(e.Columns, e.Delimiter, e.Quote)
+}
+
+func (e *CSVParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpParserTypeCSV))
+	sb.WriteString(strconv.Quote(strings.Join(e.Columns, ",")))
+
+	var options []string
+	if e.Delimiter != log.DefaultCSVDelimiter {
+		options = append(options, OpCSVDelimiter+"="+strconv.Quote(string(e.Delimiter)))
+	}
+	if e.Quote != log.DefaultCSVQuote {
+		quote := ""
+		if e.Quote != 0 {
+			quote = string(e.Quote)
+		}
+		options = append(options, OpCSVQuote+"="+strconv.Quote(quote))
+	}
+	if len(options) > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(strings.Join(options, ", "))
+	}
+	return sb.String()
+}
+
 type internedStringSet map[string]struct {
 	s  string
 	ok bool
@@ -1169,21 +1504,29 @@ const (
 	OpTypeSortDesc = "sort_desc"
 
 	// range vector ops
-	OpRangeTypeCount       = "count_over_time"
-	OpRangeTypeRate        = "rate"
-	OpRangeTypeRateCounter = "rate_counter"
-	OpRangeTypeBytes       = "bytes_over_time"
-	OpRangeTypeBytesRate   = "bytes_rate"
-	OpRangeTypeAvg         = "avg_over_time"
-	OpRangeTypeSum         = "sum_over_time"
-	OpRangeTypeMin         = "min_over_time"
-	OpRangeTypeMax         = "max_over_time"
-	OpRangeTypeStdvar      = "stdvar_over_time"
-	OpRangeTypeStddev      = "stddev_over_time"
-	OpRangeTypeQuantile    = "quantile_over_time"
-	OpRangeTypeFirst       = "first_over_time"
-	OpRangeTypeLast        = "last_over_time"
-	OpRangeTypeAbsent      = "absent_over_time"
+	OpRangeTypeCount         = "count_over_time"
+	OpRangeTypeRate          = "rate"
+	OpRangeTypeRateCounter   = "rate_counter"
+	OpRangeTypeBytes         = "bytes_over_time"
+	OpRangeTypeBytesRate     = "bytes_rate"
+	OpRangeTypeAvg           = "avg_over_time"
+	OpRangeTypeSum           = "sum_over_time"
+	OpRangeTypeMin           = "min_over_time"
+	OpRangeTypeMax           = "max_over_time"
+	OpRangeTypeStdvar        = "stdvar_over_time"
+	OpRangeTypeStddev        = "stddev_over_time"
+	OpRangeTypeQuantile      = "quantile_over_time"
+	OpRangeTypeFirst         = "first_over_time"
+	OpRangeTypeLast          = "last_over_time"
+	OpRangeTypeAbsent        = "absent_over_time"
+	OpRangeTypeHistogram     = "histogram_over_time"
+	OpRangeTypeCountDistinct = "count_distinct_over_time"
+	OpRangeTypeDeriv         = "deriv"
+	OpRangeTypeDelta         = "delta"
+	OpRangeTypeChanges       = "changes"
+	OpRangeTypeResets        = "resets"
+	OpRangeTypePredictLinear = "predict_linear"
+	OpRangeTypeHoltWinters   = "holt_winters"
 
 	//vector
 	OpTypeVector = "vector"
@@ -1215,10 +1558,20 @@ const (
 	OpParserTypeRegexp  = "regexp"
 	OpParserTypeUnpack  = "unpack"
 	OpParserTypePattern = "pattern"
+	OpParserTypeXML     = "xml"
+	OpParserTypeCSV     = "csv"
+	OpParserTypeCEF     = "cef"
+	OpParserTypeLEEF    = "leef"
+
+	// csv parser options
+	OpCSVDelimiter = "delimiter"
+	OpCSVQuote     = "quote"
 
 	OpFmtLine    = "line_format"
 	OpFmtLabel   = "label_format"
 	OpDecolorize = "decolorize"
+	OpJSONArray  = "json_array"
+	OpMacro      = "@"
 
 	OpPipe   = "|"
 	OpUnwrap = "unwrap"
@@ -1230,6 +1583,10 @@ const (
 	OpGroupLeft  = "group_left"
 	OpGroupRight = "group_right"
 
+	// joins
+	OpJoin          = "join"
+	OpJoinUnmatched = "join_unmatched"
+
 	// conversion Op
 	OpConvBytes           = "bytes"
 	OpConvDuration        = "duration"
@@ -1253,9 +1610,10 @@ const (
 	// internal expressions not represented in LogQL. These are used to
 	// evaluate expressions differently resulting in intermediate formats
 	// that are not consumable by LogQL clients but are used for sharding.
-	OpRangeTypeQuantileSketch     = "__quantile_sketch_over_time__"
-	OpRangeTypeFirstWithTimestamp = "__first_over_time_ts__"
-	OpRangeTypeLastWithTimestamp  = "__last_over_time_ts__"
+	OpRangeTypeQuantileSketch      = "__quantile_sketch_over_time__"
+	OpRangeTypeCountDistinctSketch = "__count_distinct_sketch_over_time__"
+	OpRangeTypeFirstWithTimestamp  = "__first_over_time_ts__"
+	OpRangeTypeLastWithTimestamp   = "__last_over_time_ts__"
 )
 
 func IsComparisonOperator(op string) bool {
@@ -1295,29 +1653,18 @@ type RangeAggregationExpr struct {
 	Left      *LogRange
 	Operation string
 
-	Params   *float64
+	Params *float64
+	// Args are the parameters following the range, such as the duration of predict_linear.
+	Args     []float64
 	Grouping *Grouping
 	err      error
 	implicit
 }
 
 func newRangeAggregationExpr(left *LogRange, operation string, gr *Grouping, stringParams *string) SampleExpr {
-	var params *float64
-	if stringParams != nil {
-		if operation != OpRangeTypeQuantile && operation != OpRangeTypeQuantileSketch {
-			return &RangeAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)}
-		}
-		var err error
-		params = new(float64)
-		*params, err = strconv.ParseFloat(*stringParams, 64)
-		if err != nil {
-			return &RangeAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)}
-		}
-
-	} else {
-		if operation == OpRangeTypeQuantile {
-			return &RangeAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
-		}
+	params, err := parseRangeAggregationParams(operation, stringParams)
+	if err != nil {
+		return &RangeAggregationExpr{err: err}
 	}
 	e := &RangeAggregationExpr{
 		Left:      left,
@@ -1330,6 +1677,78 @@ func newRangeAggregationExpr(left *LogRange, operation string, gr *Grouping, str
 	}
 	return e
 }
+
+// newRangeAggregationExprWithArgs creates a range aggregation taking its parameters after the range,
+// e.g. predict_linear({app="foo"} | unwrap bytes [1h], 3600).
+func newRangeAggregationExprWithArgs(left *LogRange, operation string, stringArgs ...string) SampleExpr {
+	args, err := parseRangeAggregationArgs(operation, stringArgs)
+	if err != nil {
+		return &RangeAggregationExpr{err: err}
+	}
+	e := &RangeAggregationExpr{
+		Left:      left,
+		Operation: operation,
+		Args:      args,
+	}
+	if err := e.validate(); err != nil {
+		return &RangeAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+// parseRangeAggregationParams parses the parameter preceding the range of a range aggregation.
+func parseRangeAggregationParams(operation string, stringParams *string) (*float64, error) {
+	if stringParams == nil {
+		switch operation {
+		case OpRangeTypeQuantile, OpRangeTypePredictLinear, OpRangeTypeHoltWinters:
+			return nil, logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)
+		}
+		return nil, nil
+	}
+	if operation != OpRangeTypeQuantile && operation != OpRangeTypeQuantileSketch {
+		return nil, logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)
+	}
+	params, err := strconv.ParseFloat(*stringParams, 64)
+	if err != nil {
+		return nil, logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)
+	}
+	return &params, nil
+}
+
+// parseRangeAggregationArgs parses the parameters following the range of a range aggregation.
+func parseRangeAggregationArgs(operation string, stringArgs []string) ([]float64, error) {
+	var expected int
+	switch operation {
+	case OpRangeTypePredictLinear:
+		expected = 1
+	case OpRangeTypeHoltWinters:
+		expected = 2
+	}
+	if len(stringArgs) != expected {
+		return nil, logqlmodel.NewParseError(fmt.Sprintf("operation %s expects %d parameter(s) after the range, got %d", operation, expected, len(stringArgs)), 0, 0)
+	}
+	if len(stringArgs) == 0 {
+		return nil, nil
+	}
+	args := make([]float64, 0, len(stringArgs))
+	for _, a := range stringArgs {
+		v, err := strconv.ParseFloat(a, 64)
+		if err != nil {
+			return nil, logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)
+		}
+		args = append(args, v)
+	}
+	if operation == OpRangeTypeHoltWinters {
+		if args[0] <= 0 || args[0] >= 1 {
+			return nil, logqlmodel.NewParseError(fmt.Sprintf("invalid smoothing factor for operation %s, expected 0 < sf < 1, got %s", operation, stringArgs[0]), 0, 0)
+		}
+		if args[1] <= 0 || args[1] >= 1 {
+			return nil, logqlmodel.NewParseError(fmt.Sprintf("invalid trend factor for operation %s, expected 0 < tf < 1, got %s", operation, stringArgs[1]), 0, 0)
+		}
+	}
+	return args, nil
+}
+
 func (e *RangeAggregationExpr) isSampleExpr() {}
 
 func (e *RangeAggregationExpr) Selector() (LogSelectorExpr, error) {
@@ -1343,6 +1762,13 @@ func (e *RangeAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
 	if e.err != nil {
 		return nil, e.err
 	}
+	if j, ok := e.Left.Left.(*JoinExpr); ok {
+		// both sides of a join are selected separately.
+		return []MatcherRange{
+			{Matchers: j.Left.Matchers(), Interval: e.Left.Interval, Offset: e.Left.Offset},
+			{Matchers: j.Right.Matchers(), Interval: e.Left.Interval, Offset: e.Left.Offset},
+		}, nil
+	}
 	xs := e.Left.Left.Matchers()
 	if len(xs) > 0 {
 		return []MatcherRange{
@@ -1361,7 +1787,8 @@ func (e RangeAggregationExpr) validate() error {
 		switch e.Operation {
 		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile,
 			OpRangeTypeQuantileSketch, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst,
-			OpRangeTypeLast, OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp:
+			OpRangeTypeLast, OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp, OpRangeTypeHistogram,
+			OpRangeTypeCountDistinct, OpRangeTypeCountDistinctSketch:
 		default:
 			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
 		}
@@ -1371,7 +1798,15 @@ func (e RangeAggregationExpr) validate() error {
 		case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev,
 			OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeRate, OpRangeTypeRateCounter,
 			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeQuantileSketch,
-			OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp:
+			OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp, OpRangeTypeHistogram,
+			OpRangeTypeDeriv, OpRangeTypeDelta, OpRangeTypeChanges, OpRangeTypeResets,
+			OpRangeTypePredictLinear, OpRangeTypeHoltWinters:
+			return nil
+		case OpRangeTypeCountDistinct, OpRangeTypeCountDistinctSketch:
+			// distinct values are counted on the hash of the label value, conversions don't apply.
+			if e.Left.Unwrap.Operation != "" {
+				return fmt.Errorf("conversion function %s not supported for %s aggregation", e.Left.Unwrap.Operation, e.Operation)
+			}
 			return nil
 		default:
 			return fmt.Errorf("invalid aggregation %s with unwrap", e.Operation)
@@ -1399,6 +1834,10 @@ func (e *RangeAggregationExpr) String() string {
 		sb.WriteString(",")
 	}
 	sb.WriteString(e.Left.String())
+	for _, a := range e.Args {
+		sb.WriteString(",")
+		sb.WriteString(strconv.FormatFloat(a, 'f', -1, 64))
+	}
 	sb.WriteString(")")
 	if e.Grouping != nil {
 		sb.WriteString(e.Grouping.String())
@@ -1415,6 +1854,11 @@ func (e *RangeAggregationExpr) Shardable(topLevel bool) bool {
 	if e.Operation == OpRangeTypeQuantile && !topLevel {
 		return false
 	}
+	// The same applies to count_distinct_over_time, whose sketches are only
+	// merged and evaluated at the top level.
+	if e.Operation == OpRangeTypeCountDistinct && !topLevel {
+		return false
+	}
 	return shardableOps[e.Operation] && e.Left.Shardable(topLevel)
 }
 
@@ -1428,6 +1872,195 @@ func (e *RangeAggregationExpr) Walk(f WalkFn) {
 
 func (e *RangeAggregationExpr) Accept(v RootVisitor) { v.VisitRangeAggregation(e) }
 
+// SubqueryExpr is a range vector of the results of a metric query evaluated every Step,
+// e.g. rate({app="foo"}[1m])[1h:1m]. A zero Step uses the step of the query.
+type SubqueryExpr struct {
+	Left   SampleExpr
+	Range  time.Duration
+	Step   time.Duration
+	Offset time.Duration
+
+	err error
+	implicit
+}
+
+func newSubqueryExpr(left SampleExpr, rangeStep string, o *OffsetExpr) *SubqueryExpr {
+	e := &SubqueryExpr{Left: left}
+	if o != nil {
+		e.Offset = o.Offset
+	}
+	rng, step, _ := strings.Cut(rangeStep, ":")
+	d, err := model.ParseDuration(strings.TrimSpace(rng))
+	if err != nil {
+		e.err = logqlmodel.NewParseError(fmt.Sprintf("invalid subquery range: %s", err), 0, 0)
+		return e
+	}
+	e.Range = time.Duration(d)
+	if step = strings.TrimSpace(step); step != "" {
+		d, err = model.ParseDuration(step)
+		if err != nil {
+			e.err = logqlmodel.NewParseError(fmt.Sprintf("invalid subquery step: %s", err), 0, 0)
+			return e
+		}
+		e.Step = time.Duration(d)
+	}
+	if e.Range <= 0 {
+		e.err = logqlmodel.NewParseError("subquery range must be positive", 0, 0)
+	}
+	return e
+}
+
+// impls Stringer
+func (e *SubqueryExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Left.String())
+	sb.WriteString("[")
+	sb.WriteString(model.Duration(e.Range).String())
+	sb.WriteString(":")
+	if e.Step != 0 {
+		sb.WriteString(model.Duration(e.Step).String())
+	}
+	sb.WriteString("]")
+	if e.Offset != 0 {
+		offsetExpr := OffsetExpr{Offset: e.Offset}
+		sb.WriteString(offsetExpr.String())
+	}
+	return sb.String()
+}
+
+// Shardable is false since the results of the inner query are needed as a whole,
+// its own parts can still be sharded.
+func (e *SubqueryExpr) Shardable(_ bool) bool { return false }
+
+func (e *SubqueryExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *SubqueryExpr) Accept(v RootVisitor) { v.VisitSubquery(e) }
+
+// SubqueryAggregationExpr is a range vector aggregation over a subquery,
+// e.g. max_over_time(rate({app="foo"}[1m])[1h:1m]).
+type SubqueryAggregationExpr struct {
+	Left      *SubqueryExpr
+	Operation string
+
+	Params *float64
+	Args   []float64
+	err    error
+	implicit
+}
+
+func newSubqueryAggregationExpr(left *SubqueryExpr, operation string, stringParams *string, stringArgs ...string) SampleExpr {
+	if left.err != nil {
+		return &SubqueryAggregationExpr{err: left.err}
+	}
+	var params *float64
+	// predict_linear and holt_winters take their parameters after the range.
+	if stringParams != nil || (operation != OpRangeTypePredictLinear && operation != OpRangeTypeHoltWinters) {
+		var err error
+		if params, err = parseRangeAggregationParams(operation, stringParams); err != nil {
+			return &SubqueryAggregationExpr{err: err}
+		}
+	}
+	args, err := parseRangeAggregationArgs(operation, stringArgs)
+	if err != nil {
+		return &SubqueryAggregationExpr{err: err}
+	}
+	e := &SubqueryAggregationExpr{
+		Left:      left,
+		Operation: operation,
+		Params:    params,
+		Args:      args,
+	}
+	if err := e.validate(); err != nil {
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+func (e *SubqueryAggregationExpr) validate() error {
+	switch e.Operation {
+	case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeCount,
+		OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeRateCounter,
+		OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeDeriv, OpRangeTypeDelta, OpRangeTypeChanges,
+		OpRangeTypeResets, OpRangeTypePredictLinear, OpRangeTypeHoltWinters:
+	default:
+		return fmt.Errorf("invalid aggregation %s of a subquery", e.Operation)
+	}
+	if isHistogramExpr(e.Left.Left) {
+		return fmt.Errorf("subqueries are not supported on %s", OpRangeTypeHistogram)
+	}
+	return nil
+}
+
+func (e *SubqueryAggregationExpr) isSampleExpr() {}
+
+func (e *SubqueryAggregationExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Left.Selector()
+}
+
+func (e *SubqueryAggregationExpr) Extractor() (log.SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return nil, fmt.Errorf("subquery aggregation %s has no sample extractor", e.Operation)
+}
+
+// MatcherGroups returns the matcher groups of the inner query, with intervals and offsets extended
+// by the range and the offset of the subquery.
+func (e *SubqueryAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	groups, err := e.Left.Left.MatcherGroups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i].Interval += e.Left.Range
+		groups[i].Offset += e.Left.Offset
+	}
+	return groups, nil
+}
+
+// impls Stringer
+func (e *SubqueryAggregationExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	if e.Params != nil {
+		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+		sb.WriteString(",")
+	}
+	sb.WriteString(e.Left.String())
+	for _, a := range e.Args {
+		sb.WriteString(",")
+		sb.WriteString(strconv.FormatFloat(a, 'f', -1, 64))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// impl SampleExpr
+func (e *SubqueryAggregationExpr) Shardable(topLevel bool) bool { return e.Left.Shardable(topLevel) }
+
+func (e *SubqueryAggregationExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *SubqueryAggregationExpr) Accept(v RootVisitor) { v.VisitSubqueryAggregation(e) }
+
 // Grouping struct represents the grouping by/without label(s) for vector aggregators and range vector aggregators.
 // The representation is as follows:
 //   - No Grouping (labels dismissed): <operation> (<expr>) => Grouping{Without: false, Groups: nil}
@@ -2230,17 +2863,19 @@ var shardableOps = map[string]bool{
 	OpTypeMin:   true,
 
 	// range vector ops
-	OpRangeTypeAvg:       true,
-	OpRangeTypeCount:     true,
-	OpRangeTypeFirst:     true,
-	OpRangeTypeLast:      true,
-	OpRangeTypeRate:      true,
-	OpRangeTypeBytes:     true,
-	OpRangeTypeBytesRate: true,
-	OpRangeTypeSum:       true,
-	OpRangeTypeMax:       true,
-	OpRangeTypeMin:       true,
-	OpRangeTypeQuantile:  true,
+	OpRangeTypeAvg:           true,
+	OpRangeTypeCount:         true,
+	OpRangeTypeFirst:         true,
+	OpRangeTypeLast:          true,
+	OpRangeTypeRate:          true,
+	OpRangeTypeBytes:         true,
+	OpRangeTypeBytesRate:     true,
+	OpRangeTypeSum:           true,
+	OpRangeTypeMax:           true,
+	OpRangeTypeMin:           true,
+	OpRangeTypeQuantile:      true,
+	OpRangeTypeHistogram:     true,
+	OpRangeTypeCountDistinct: true,
 
 	// binops - arith
 	OpTypeAdd: true,
@@ -2256,6 +2891,8 @@ func MatcherGroups(expr Expr) ([]MatcherRange, error) {
 	switch e := expr.(type) {
 	case SampleExpr:
 		return e.MatcherGroups()
+	case *JoinExpr:
+		return []MatcherRange{{Matchers: e.Left.Matchers()}, {Matchers: e.Right.Matchers()}}, nil
 	case LogSelectorExpr:
 		if xs := e.Matchers(); len(xs) > 0 {
 			return []MatcherRange{
@@ -695,6 +695,22 @@ func TestStringer(t *testing.T) {
 			in:  `{app="foo"} |= "foo" or "bar" |= "buzz" or "fizz"`,
 			out: `{app="foo"} |= "foo" or "bar" |= "buzz" or "fizz"`,
 		},
+		{
+			in:  `{app="foo"} | csv "ts,method" quote="'", delimiter=";"`,
+			out: `{app="foo"} | csv "ts,method" delimiter=";", quote="'"`,
+		},
+		{
+			in:  `{app="foo"} | csv "ts,method" quote=""`,
+			out: `{app="foo"} | csv "ts,method" quote=""`,
+		},
+		{
+			in:  `{app="foo"} | json_array "errors" | json`,
+			out: `{app="foo"} | json_array "errors" | json`,
+		},
+		{
+			in:  `count_over_time({app="foo"} | json_array [5m])`,
+			out: `count_over_time({app="foo"} | json_array[5m])`,
+		},
 		{
 			out: `{app="foo"} |= "foo" or "bar" |~ "buzz|fizz"`,
 			in:  `{app="foo"} |= "foo" or "bar" |~ "buzz|fizz"`,
@@ -79,6 +79,30 @@ func (v *cloneVisitor) VisitRangeAggregation(e *RangeAggregationExpr) {
 		copied.Params = &tmp
 	}
 
+	if e.Args != nil {
+		copied.Args = make([]float64, len(e.Args))
+		copy(copied.Args, e.Args)
+	}
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitSubqueryAggregation(e *SubqueryAggregationExpr) {
+	copied := &SubqueryAggregationExpr{
+		Left:      MustClone[*SubqueryExpr](e.Left),
+		Operation: e.Operation,
+	}
+
+	if e.Params != nil {
+		tmp := *e.Params
+		copied.Params = &tmp
+	}
+
+	if e.Args != nil {
+		copied.Args = make([]float64, len(e.Args))
+		copy(copied.Args, e.Args)
+	}
+
 	v.cloned = copied
 }
 
@@ -117,6 +141,15 @@ func (v *cloneVisitor) VisitLogRange(e *LogRange) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitSubquery(e *SubqueryExpr) {
+	v.cloned = &SubqueryExpr{
+		Left:   MustClone[SampleExpr](e.Left),
+		Range:  e.Range,
+		Step:   e.Step,
+		Offset: e.Offset,
+	}
+}
+
 func (v *cloneVisitor) VisitMatchers(e *MatchersExpr) {
 	copied := &MatchersExpr{
 		Mts: make([]*labels.Matcher, len(e.Mts)),
@@ -140,10 +173,35 @@ func (v *cloneVisitor) VisitPipeline(e *PipelineExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitJoin(e *JoinExpr) {
+	copied := &JoinExpr{
+		Left:      MustClone[LogSelectorExpr](e.Left),
+		Right:     MustClone[LogSelectorExpr](e.Right),
+		Operation: e.Operation,
+		Tolerance: e.Tolerance,
+		On:        make([]string, len(e.On)),
+	}
+	copy(copied.On, e.On)
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitLabelPrefilter(e *LabelPrefilterExpr) {
+	v.cloned = &LabelPrefilterExpr{Name: e.Name, Value: e.Value}
+}
+
 func (v *cloneVisitor) VisitDecolorize(*DecolorizeExpr) {
 	v.cloned = &DecolorizeExpr{}
 }
 
+func (v *cloneVisitor) VisitJSONArray(e *JSONArrayExpr) {
+	v.cloned = &JSONArrayExpr{Path: e.Path}
+}
+
+func (v *cloneVisitor) VisitMacro(e *MacroExpr) {
+	v.cloned = &MacroExpr{Name: e.Name}
+}
+
 func (v *cloneVisitor) VisitDropLabels(e *DropLabelsExpr) {
 	copied := &DropLabelsExpr{
 		dropLabels: make([]log.DropLabel, len(e.dropLabels)),
@@ -300,3 +358,23 @@ func (v *cloneVisitor) VisitLogfmtParser(e *LogfmtParserExpr) {
 		KeepEmpty: e.KeepEmpty,
 	}
 }
+
+func (v *cloneVisitor) VisitCSVParser(e *CSVParserExpr) {
+	copied := &CSVParserExpr{
+		Columns:   make([]string, len(e.Columns)),
+		Delimiter: e.Delimiter,
+		Quote:     e.Quote,
+	}
+	copy(copied.Columns, e.Columns)
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitXMLExpressionParser(e *XMLExpressionParser) {
+	copied := &XMLExpressionParser{
+		Expressions: make([]log.LabelExtractionExpr, len(e.Expressions)),
+	}
+	copy(copied.Expressions, e.Expressions)
+
+	v.cloned = copied
+}
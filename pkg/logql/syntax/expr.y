@@ -61,9 +61,14 @@ import (
   LabelExtractionExpressionList []log.LabelExtractionExpr
   JSONExpressionParser          *JSONExpressionParser
   LogfmtExpressionParser        *LogfmtExpressionParser
+  XMLExpressionParser           *XMLExpressionParser
+  CSVParser                     *CSVParserExpr
+  CSVOption                     csvOption
+  CSVOptions                    []csvOption
 
   UnwrapExpr              *UnwrapExpr
   DecolorizeExpr          *DecolorizeExpr
+  JSONArrayExpr           *JSONArrayExpr
   OffsetExpr              *OffsetExpr
   DropLabel               log.DropLabel
   DropLabels              []log.DropLabel
@@ -80,11 +85,13 @@ import (
 %type <Grouping>              grouping
 %type <Labels>                labels
 %type <LogExpr>               logExpr
+%type <LogExpr>               joinExpr
 %type <MetricExpr>            metricExpr
 %type <LogRangeExpr>          logRangeExpr
 %type <Matcher>               matcher
 %type <Matchers>              matchers
 %type <RangeAggregationExpr>  rangeAggregationExpr
+%type <Expr>                  subqueryExpr
 %type <RangeOp>               rangeOp
 %type <ConvOp>                convOp
 %type <Selector>              selector
@@ -113,6 +120,7 @@ import (
 %type <ParserFlags>           parserFlags
 %type <LineFormatExpr>        lineFormatExpr
 %type <DecolorizeExpr>        decolorizeExpr
+%type <JSONArrayExpr>         jsonArrayExpr
 %type <DropLabelsExpr>        dropLabelsExpr
 %type <DropLabels>            dropLabels
 %type <DropLabel>             dropLabel
@@ -126,20 +134,25 @@ import (
 %type <LabelExtractionExpressionList>    labelExtractionExpressionList
 %type <LogfmtExpressionParser>           logfmtExpressionParser
 %type <JSONExpressionParser>             jsonExpressionParser
+%type <XMLExpressionParser>              xmlExpressionParser
+%type <CSVParser>                        csvParser
+%type <CSVOption>                        csvOption
+%type <CSVOptions>                       csvOptions
 %type <UnwrapExpr>            unwrapExpr
 %type <UnitFilter>            unitFilter
 %type <IPLabelFilter>         ipLabelFilter
 %type <OffsetExpr>            offsetExpr
 
 %token <bytes> BYTES
-%token <str>      IDENTIFIER STRING NUMBER PARSER_FLAG
+%token <str>      IDENTIFIER STRING NUMBER PARSER_FLAG MACRO JOIN SUBQUERY_RANGE
 %token <duration> DURATION RANGE
 %token <val>      MATCHERS LABELS EQ RE NRE NPA OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT PIPE_PATTERN
                   OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE RATE_COUNTER SUM SORT SORT_DESC AVG MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK
                   BYTES_OVER_TIME BYTES_RATE BOOL JSON REGEXP LOGFMT PIPE LINE_FMT LABEL_FMT UNWRAP AVG_OVER_TIME SUM_OVER_TIME MIN_OVER_TIME
                   MAX_OVER_TIME STDVAR_OVER_TIME STDDEV_OVER_TIME QUANTILE_OVER_TIME BYTES_CONV DURATION_CONV DURATION_SECONDS_CONV
                   FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
-                  DECOLORIZE DROP KEEP
+                  DECOLORIZE DROP KEEP XML CSV CEF LEEF JSON_ARRAY HISTOGRAM_OVER_TIME COUNT_DISTINCT_OVER_TIME
+                  DERIV DELTA CHANGES RESETS PREDICT_LINEAR HOLT_WINTERS
 
 // Operators are listed with increasing precedence.
 %left <binOp> OR
@@ -172,6 +185,11 @@ logExpr:
       selector                                    { $$ = newMatcherExpr($1)}
     | selector pipelineExpr                       { $$ = newPipelineExpr(newMatcherExpr($1), $2)}
     | OPEN_PARENTHESIS logExpr CLOSE_PARENTHESIS  { $$ = $2 }
+    | joinExpr                                    { $$ = $1 }
+    ;
+
+joinExpr:
+      JOIN OPEN_PARENTHESIS DURATION COMMA logExpr COMMA logExpr CLOSE_PARENTHESIS ON OPEN_PARENTHESIS labels CLOSE_PARENTHESIS { $$ = newJoinExpr($1, $3, $5, $7, $11) }
     ;
 
 logRangeExpr:
@@ -199,6 +217,8 @@ logRangeExpr:
     | selector RANGE offsetExpr pipelineExpr                                                { $$ = newLogRange(newPipelineExpr(newMatcherExpr($1), $4), $2, nil, $3 ) }
     | selector RANGE pipelineExpr unwrapExpr                                                { $$ = newLogRange(newPipelineExpr(newMatcherExpr($1), $3), $2, $4, nil ) }
     | selector RANGE offsetExpr pipelineExpr unwrapExpr                                     { $$ = newLogRange(newPipelineExpr(newMatcherExpr($1), $4), $2, $5, $3 ) }
+    | joinExpr RANGE                                                                        { $$ = newLogRange($1, $2, nil, nil) }
+    | joinExpr RANGE offsetExpr                                                             { $$ = newLogRange($1, $2, nil, $3) }
     | OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS                                       { $$ = $2 }
     | logRangeExpr error
     ;
@@ -220,6 +240,17 @@ rangeAggregationExpr:
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS           { $$ = newRangeAggregationExpr($5, $1, nil, &$3) }
     | rangeOp OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS grouping               { $$ = newRangeAggregationExpr($3, $1, $5, nil) }
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS grouping  { $$ = newRangeAggregationExpr($5, $1, $7, &$3) }
+    | rangeOp OPEN_PARENTHESIS logRangeExpr COMMA NUMBER CLOSE_PARENTHESIS           { $$ = newRangeAggregationExprWithArgs($3, $1, $5) }
+    | rangeOp OPEN_PARENTHESIS logRangeExpr COMMA NUMBER COMMA NUMBER CLOSE_PARENTHESIS { $$ = newRangeAggregationExprWithArgs($3, $1, $5, $7) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr CLOSE_PARENTHESIS                        { $$ = newSubqueryAggregationExpr($3.(*SubqueryExpr), $1, nil) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA subqueryExpr CLOSE_PARENTHESIS           { $$ = newSubqueryAggregationExpr($5.(*SubqueryExpr), $1, &$3) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr COMMA NUMBER CLOSE_PARENTHESIS           { $$ = newSubqueryAggregationExpr($3.(*SubqueryExpr), $1, nil, $5) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr COMMA NUMBER COMMA NUMBER CLOSE_PARENTHESIS { $$ = newSubqueryAggregationExpr($3.(*SubqueryExpr), $1, nil, $5, $7) }
+    ;
+
+subqueryExpr:
+      metricExpr SUBQUERY_RANGE                     { $$ = newSubqueryExpr($1, $2, nil) }
+    | metricExpr SUBQUERY_RANGE offsetExpr          { $$ = newSubqueryExpr($1, $2, $3) }
     ;
 
 vectorAggregationExpr:
@@ -276,12 +307,16 @@ pipelineStage:
   | PIPE labelParser             { $$ = $2 }
   | PIPE jsonExpressionParser    { $$ = $2 }
   | PIPE logfmtExpressionParser  { $$ = $2 }
+  | PIPE xmlExpressionParser     { $$ = $2 }
+  | PIPE csvParser               { $$ = $2 }
   | PIPE labelFilter             { $$ = &LabelFilterExpr{LabelFilterer: $2 }}
   | PIPE lineFormatExpr          { $$ = $2 }
   | PIPE decolorizeExpr          { $$ = $2 }
+  | PIPE jsonArrayExpr           { $$ = $2 }
   | PIPE labelFormatExpr         { $$ = $2 }
   | PIPE dropLabelsExpr          { $$ = $2 }
   | PIPE keepLabelsExpr          { $$ = $2 }
+  | PIPE MACRO                    { $$ = newMacroExpr($2) }
   ;
 
 filterOp:
@@ -321,11 +356,31 @@ labelParser:
   | REGEXP STRING       { $$ = newLabelParserExpr(OpParserTypeRegexp, $2) }
   | UNPACK              { $$ = newLabelParserExpr(OpParserTypeUnpack, "") }
   | PATTERN STRING      { $$ = newLabelParserExpr(OpParserTypePattern, $2) }
+  | XML                 { $$ = newLabelParserExpr(OpParserTypeXML, "") }
+  | CEF                 { $$ = newLabelParserExpr(OpParserTypeCEF, "") }
+  | LEEF                { $$ = newLabelParserExpr(OpParserTypeLEEF, "") }
   ;
 
 jsonExpressionParser:
     JSON labelExtractionExpressionList { $$ = newJSONExpressionParser($2) }
 
+xmlExpressionParser:
+    XML labelExtractionExpressionList { $$ = newXMLExpressionParser($2) }
+
+csvParser:
+    CSV STRING            { $$ = newCSVParserExpr($2, nil) }
+  | CSV STRING csvOptions { $$ = newCSVParserExpr($2, $3) }
+  ;
+
+csvOption:
+    IDENTIFIER EQ STRING { $$ = csvOption{name: $1, value: $3} }
+  ;
+
+csvOptions:
+    csvOption                  { $$ = []csvOption{$1} }
+  | csvOptions COMMA csvOption { $$ = append($1, $3) }
+  ;
+
 logfmtExpressionParser:
     LOGFMT parserFlags labelExtractionExpressionList  { $$ = newLogfmtExpressionParser($3, $2)}
   | LOGFMT labelExtractionExpressionList              { $$ = newLogfmtExpressionParser($2, nil)}
@@ -335,6 +390,11 @@ lineFormatExpr: LINE_FMT STRING { $$ = newLineFmtExpr($2) };
 
 decolorizeExpr: DECOLORIZE { $$ = newDecolorizeExpr() };
 
+jsonArrayExpr:
+    JSON_ARRAY         { $$ = newJSONArrayExpr("") }
+  | JSON_ARRAY STRING  { $$ = newJSONArrayExpr($2) }
+  ;
+
 labelFormat:
      IDENTIFIER EQ IDENTIFIER { $$ = log.NewRenameLabelFmt($1, $3)}
   |  IDENTIFIER EQ STRING     { $$ = log.NewTemplateLabelFmt($1, $3)}
@@ -563,6 +623,14 @@ rangeOp:
     | FIRST_OVER_TIME    { $$ = OpRangeTypeFirst }
     | LAST_OVER_TIME     { $$ = OpRangeTypeLast }
     | ABSENT_OVER_TIME   { $$ = OpRangeTypeAbsent }
+    | HISTOGRAM_OVER_TIME { $$ = OpRangeTypeHistogram }
+    | COUNT_DISTINCT_OVER_TIME { $$ = OpRangeTypeCountDistinct }
+    | DERIV              { $$ = OpRangeTypeDeriv }
+    | DELTA              { $$ = OpRangeTypeDelta }
+    | CHANGES            { $$ = OpRangeTypeChanges }
+    | RESETS             { $$ = OpRangeTypeResets }
+    | PREDICT_LINEAR     { $$ = OpRangeTypePredictLinear }
+    | HOLT_WINTERS       { $$ = OpRangeTypeHoltWinters }
     ;
 
 offsetExpr:
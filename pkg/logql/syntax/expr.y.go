@@ -67,9 +67,14 @@ type exprSymType struct {
 	LabelExtractionExpressionList []log.LabelExtractionExpr
 	JSONExpressionParser          *JSONExpressionParser
 	LogfmtExpressionParser        *LogfmtExpressionParser
+	XMLExpressionParser           *XMLExpressionParser
+	CSVParser                     *CSVParserExpr
+	CSVOption                     csvOption
+	CSVOptions                    []csvOption
 
 	UnwrapExpr     *UnwrapExpr
 	DecolorizeExpr *DecolorizeExpr
+	JSONArrayExpr  *JSONArrayExpr
 	OffsetExpr     *OffsetExpr
 	DropLabel      log.DropLabel
 	DropLabels     []log.DropLabel
@@ -84,92 +89,108 @@ const IDENTIFIER = 57347
 const STRING = 57348
 const NUMBER = 57349
 const PARSER_FLAG = 57350
-const DURATION = 57351
-const RANGE = 57352
-const MATCHERS = 57353
-const LABELS = 57354
-const EQ = 57355
-const RE = 57356
-const NRE = 57357
-const NPA = 57358
-const OPEN_BRACE = 57359
-const CLOSE_BRACE = 57360
-const OPEN_BRACKET = 57361
-const CLOSE_BRACKET = 57362
-const COMMA = 57363
-const DOT = 57364
-const PIPE_MATCH = 57365
-const PIPE_EXACT = 57366
-const PIPE_PATTERN = 57367
-const OPEN_PARENTHESIS = 57368
-const CLOSE_PARENTHESIS = 57369
-const BY = 57370
-const WITHOUT = 57371
-const COUNT_OVER_TIME = 57372
-const RATE = 57373
-const RATE_COUNTER = 57374
-const SUM = 57375
-const SORT = 57376
-const SORT_DESC = 57377
-const AVG = 57378
-const MAX = 57379
-const MIN = 57380
-const COUNT = 57381
-const STDDEV = 57382
-const STDVAR = 57383
-const BOTTOMK = 57384
-const TOPK = 57385
-const BYTES_OVER_TIME = 57386
-const BYTES_RATE = 57387
-const BOOL = 57388
-const JSON = 57389
-const REGEXP = 57390
-const LOGFMT = 57391
-const PIPE = 57392
-const LINE_FMT = 57393
-const LABEL_FMT = 57394
-const UNWRAP = 57395
-const AVG_OVER_TIME = 57396
-const SUM_OVER_TIME = 57397
-const MIN_OVER_TIME = 57398
-const MAX_OVER_TIME = 57399
-const STDVAR_OVER_TIME = 57400
-const STDDEV_OVER_TIME = 57401
-const QUANTILE_OVER_TIME = 57402
-const BYTES_CONV = 57403
-const DURATION_CONV = 57404
-const DURATION_SECONDS_CONV = 57405
-const FIRST_OVER_TIME = 57406
-const LAST_OVER_TIME = 57407
-const ABSENT_OVER_TIME = 57408
-const VECTOR = 57409
-const LABEL_REPLACE = 57410
-const UNPACK = 57411
-const OFFSET = 57412
-const PATTERN = 57413
-const IP = 57414
-const ON = 57415
-const IGNORING = 57416
-const GROUP_LEFT = 57417
-const GROUP_RIGHT = 57418
-const DECOLORIZE = 57419
-const DROP = 57420
-const KEEP = 57421
-const OR = 57422
-const AND = 57423
-const UNLESS = 57424
-const CMP_EQ = 57425
-const NEQ = 57426
-const LT = 57427
-const LTE = 57428
-const GT = 57429
-const GTE = 57430
-const ADD = 57431
-const SUB = 57432
-const MUL = 57433
-const DIV = 57434
-const MOD = 57435
-const POW = 57436
+const MACRO = 57351
+const JOIN = 57352
+const SUBQUERY_RANGE = 57353
+const DURATION = 57354
+const RANGE = 57355
+const MATCHERS = 57356
+const LABELS = 57357
+const EQ = 57358
+const RE = 57359
+const NRE = 57360
+const NPA = 57361
+const OPEN_BRACE = 57362
+const CLOSE_BRACE = 57363
+const OPEN_BRACKET = 57364
+const CLOSE_BRACKET = 57365
+const COMMA = 57366
+const DOT = 57367
+const PIPE_MATCH = 57368
+const PIPE_EXACT = 57369
+const PIPE_PATTERN = 57370
+const OPEN_PARENTHESIS = 57371
+const CLOSE_PARENTHESIS = 57372
+const BY = 57373
+const WITHOUT = 57374
+const COUNT_OVER_TIME = 57375
+const RATE = 57376
+const RATE_COUNTER = 57377
+const SUM = 57378
+const SORT = 57379
+const SORT_DESC = 57380
+const AVG = 57381
+const MAX = 57382
+const MIN = 57383
+const COUNT = 57384
+const STDDEV = 57385
+const STDVAR = 57386
+const BOTTOMK = 57387
+const TOPK = 57388
+const BYTES_OVER_TIME = 57389
+const BYTES_RATE = 57390
+const BOOL = 57391
+const JSON = 57392
+const REGEXP = 57393
+const LOGFMT = 57394
+const PIPE = 57395
+const LINE_FMT = 57396
+const LABEL_FMT = 57397
+const UNWRAP = 57398
+const AVG_OVER_TIME = 57399
+const SUM_OVER_TIME = 57400
+const MIN_OVER_TIME = 57401
+const MAX_OVER_TIME = 57402
+const STDVAR_OVER_TIME = 57403
+const STDDEV_OVER_TIME = 57404
+const QUANTILE_OVER_TIME = 57405
+const BYTES_CONV = 57406
+const DURATION_CONV = 57407
+const DURATION_SECONDS_CONV = 57408
+const FIRST_OVER_TIME = 57409
+const LAST_OVER_TIME = 57410
+const ABSENT_OVER_TIME = 57411
+const VECTOR = 57412
+const LABEL_REPLACE = 57413
+const UNPACK = 57414
+const OFFSET = 57415
+const PATTERN = 57416
+const IP = 57417
+const ON = 57418
+const IGNORING = 57419
+const GROUP_LEFT = 57420
+const GROUP_RIGHT = 57421
+const DECOLORIZE = 57422
+const DROP = 57423
+const KEEP = 57424
+const XML = 57425
+const CSV = 57426
+const CEF = 57427
+const LEEF = 57428
+const JSON_ARRAY = 57429
+const HISTOGRAM_OVER_TIME = 57430
+const COUNT_DISTINCT_OVER_TIME = 57431
+const DERIV = 57432
+const DELTA = 57433
+const CHANGES = 57434
+const RESETS = 57435
+const PREDICT_LINEAR = 57436
+const HOLT_WINTERS = 57437
+const OR = 57438
+const AND = 57439
+const UNLESS = 57440
+const CMP_EQ = 57441
+const NEQ = 57442
+const LT = 57443
+const LTE = 57444
+const GT = 57445
+const GTE = 57446
+const ADD = 57447
+const SUB = 57448
+const MUL = 57449
+const DIV = 57450
+const MOD = 57451
+const POW = 57452
 
 var exprToknames = [...]string{
 	"$end",
@@ -180,6 +201,9 @@ var exprToknames = [...]string{
 	"STRING",
 	"NUMBER",
 	"PARSER_FLAG",
+	"MACRO",
+	"JOIN",
+	"SUBQUERY_RANGE",
 	"DURATION",
 	"RANGE",
 	"MATCHERS",
@@ -251,6 +275,19 @@ var exprToknames = [...]string{
 	"DECOLORIZE",
 	"DROP",
 	"KEEP",
+	"XML",
+	"CSV",
+	"CEF",
+	"LEEF",
+	"JSON_ARRAY",
+	"HISTOGRAM_OVER_TIME",
+	"COUNT_DISTINCT_OVER_TIME",
+	"DERIV",
+	"DELTA",
+	"CHANGES",
+	"RESETS",
+	"PREDICT_LINEAR",
+	"HOLT_WINTERS",
 	"OR",
 	"AND",
 	"UNLESS",
@@ -274,7 +311,7 @@ const exprEofCode = 1
 const exprErrCode = 2
 const exprInitialStackSize = 16
 
-//line expr.y:582
+//line expr.y:650
 
 //line yacctab:1
 var exprExca = [...]int8{
@@ -285,260 +322,317 @@ var exprExca = [...]int8{
 
 const exprPrivate = 57344
 
-const exprLast = 639
+const exprLast = 904
 
 var exprAct = [...]int16{
-	289, 228, 84, 4, 214, 64, 182, 126, 204, 189,
-	75, 200, 197, 63, 237, 5, 152, 187, 77, 2,
-	56, 80, 48, 49, 50, 57, 58, 61, 62, 59,
-	60, 51, 52, 53, 54, 55, 56, 283, 10, 49,
-	50, 57, 58, 61, 62, 59, 60, 51, 52, 53,
-	54, 55, 56, 57, 58, 61, 62, 59, 60, 51,
-	52, 53, 54, 55, 56, 53, 54, 55, 56, 109,
-	217, 139, 215, 115, 51, 52, 53, 54, 55, 56,
-	266, 140, 221, 16, 216, 265, 292, 156, 136, 166,
-	167, 297, 262, 161, 220, 16, 294, 261, 154, 148,
-	150, 151, 366, 281, 184, 339, 16, 67, 280, 130,
-	163, 207, 150, 151, 168, 169, 170, 171, 172, 173,
-	174, 175, 176, 177, 178, 179, 180, 181, 278, 164,
-	165, 16, 366, 277, 239, 94, 293, 194, 142, 386,
-	191, 275, 202, 206, 16, 294, 274, 142, 264, 340,
-	85, 86, 292, 141, 272, 219, 316, 16, 381, 271,
-	260, 306, 235, 185, 183, 17, 18, 356, 229, 224,
-	149, 231, 232, 110, 306, 240, 294, 17, 18, 136,
-	355, 213, 208, 211, 212, 209, 210, 374, 17, 18,
-	248, 249, 250, 269, 331, 184, 16, 306, 268, 373,
-	130, 255, 306, 354, 252, 342, 343, 344, 353, 136,
-	239, 72, 74, 17, 18, 371, 363, 339, 306, 69,
-	70, 71, 285, 384, 308, 184, 17, 18, 287, 290,
-	130, 296, 314, 299, 369, 109, 302, 115, 303, 17,
-	18, 291, 154, 288, 359, 300, 263, 267, 270, 273,
-	276, 279, 282, 349, 185, 183, 330, 294, 293, 310,
-	312, 315, 317, 318, 72, 74, 202, 206, 325, 320,
-	324, 295, 69, 70, 71, 346, 72, 74, 17, 18,
-	73, 306, 239, 239, 69, 70, 71, 307, 328, 136,
-	239, 332, 304, 334, 336, 224, 338, 109, 294, 230,
-	243, 337, 348, 333, 313, 311, 109, 239, 295, 350,
-	130, 230, 241, 72, 74, 83, 224, 85, 86, 292,
-	301, 69, 70, 71, 233, 347, 144, 13, 136, 238,
-	143, 327, 153, 73, 360, 361, 155, 326, 284, 109,
-	362, 225, 13, 247, 184, 73, 364, 365, 230, 130,
-	246, 155, 370, 245, 244, 218, 160, 159, 158, 227,
-	16, 90, 89, 82, 72, 74, 376, 380, 377, 378,
-	13, 352, 69, 70, 71, 253, 298, 305, 259, 6,
-	382, 258, 73, 21, 22, 23, 36, 45, 46, 37,
-	39, 40, 38, 41, 42, 43, 44, 24, 25, 230,
-	256, 242, 234, 226, 183, 257, 254, 26, 27, 28,
-	29, 30, 31, 32, 81, 379, 368, 33, 34, 35,
-	47, 19, 236, 227, 367, 162, 345, 79, 72, 74,
-	335, 190, 13, 73, 251, 88, 69, 70, 71, 322,
-	323, 6, 17, 18, 87, 21, 22, 23, 36, 45,
-	46, 37, 39, 40, 38, 41, 42, 43, 44, 24,
-	25, 190, 375, 230, 188, 385, 383, 372, 358, 26,
-	27, 28, 29, 30, 31, 32, 146, 357, 3, 33,
-	34, 35, 47, 19, 157, 76, 329, 319, 309, 286,
-	72, 74, 145, 321, 13, 147, 198, 73, 69, 70,
-	71, 223, 222, 6, 17, 18, 136, 21, 22, 23,
-	36, 45, 46, 37, 39, 40, 38, 41, 42, 43,
-	44, 24, 25, 221, 220, 230, 195, 130, 193, 192,
-	351, 26, 27, 28, 29, 30, 31, 32, 205, 136,
-	201, 33, 34, 35, 47, 19, 190, 81, 122, 123,
-	121, 198, 131, 133, 297, 72, 74, 127, 128, 73,
-	130, 113, 114, 69, 70, 71, 17, 18, 196, 118,
-	124, 203, 125, 120, 199, 91, 119, 117, 132, 134,
-	135, 122, 123, 121, 116, 131, 133, 186, 65, 137,
-	66, 129, 138, 111, 112, 93, 92, 11, 9, 20,
-	12, 15, 8, 124, 341, 125, 14, 7, 78, 68,
-	1, 132, 134, 135, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 73, 95, 96, 97, 98, 99,
-	100, 101, 102, 103, 104, 105, 106, 107, 108,
+	270, 332, 260, 95, 74, 289, 3, 206, 4, 241,
+	145, 231, 213, 86, 227, 85, 5, 224, 7, 173,
+	211, 175, 73, 66, 319, 90, 244, 159, 87, 2,
+	63, 64, 65, 66, 335, 18, 190, 191, 15, 61,
+	62, 63, 64, 65, 66, 168, 170, 171, 14, 243,
+	317, 11, 314, 18, 441, 18, 242, 6, 316, 337,
+	313, 23, 24, 25, 46, 55, 56, 47, 49, 50,
+	48, 51, 52, 53, 54, 26, 27, 338, 120, 188,
+	189, 160, 105, 77, 128, 28, 29, 30, 31, 32,
+	33, 34, 385, 425, 450, 35, 36, 37, 57, 21,
+	96, 97, 179, 180, 425, 82, 84, 442, 392, 185,
+	176, 434, 178, 79, 80, 81, 38, 39, 40, 41,
+	42, 43, 44, 45, 335, 162, 433, 432, 161, 169,
+	336, 187, 337, 19, 20, 192, 193, 194, 195, 196,
+	197, 198, 199, 200, 201, 202, 203, 204, 205, 252,
+	162, 19, 20, 19, 20, 385, 156, 220, 215, 121,
+	252, 94, 218, 96, 97, 229, 233, 394, 395, 396,
+	337, 254, 422, 349, 431, 208, 349, 253, 246, 449,
+	149, 446, 408, 349, 86, 273, 85, 83, 380, 407,
+	430, 268, 349, 428, 262, 337, 178, 263, 406, 258,
+	58, 59, 60, 67, 68, 71, 72, 69, 70, 61,
+	62, 63, 64, 65, 66, 281, 282, 283, 67, 68,
+	71, 72, 69, 70, 61, 62, 63, 64, 65, 66,
+	164, 285, 59, 60, 67, 68, 71, 72, 69, 70,
+	61, 62, 63, 64, 65, 66, 349, 302, 207, 248,
+	18, 413, 405, 383, 321, 301, 401, 325, 323, 382,
+	381, 331, 333, 120, 179, 341, 343, 344, 375, 128,
+	345, 347, 176, 445, 178, 327, 346, 328, 353, 355,
+	358, 360, 334, 276, 156, 339, 299, 303, 306, 309,
+	312, 315, 318, 298, 336, 247, 18, 156, 379, 361,
+	252, 297, 272, 208, 378, 229, 233, 370, 149, 369,
+	365, 400, 311, 266, 308, 18, 208, 18, 300, 420,
+	310, 149, 307, 234, 170, 171, 163, 359, 342, 373,
+	372, 377, 384, 371, 337, 386, 272, 388, 390, 120,
+	272, 349, 398, 305, 120, 391, 18, 351, 19, 20,
+	349, 304, 15, 330, 272, 387, 350, 402, 82, 84,
+	272, 357, 14, 272, 296, 356, 79, 80, 81, 409,
+	397, 324, 156, 320, 257, 209, 207, 280, 440, 354,
+	256, 279, 278, 414, 416, 274, 277, 418, 271, 245,
+	419, 208, 120, 261, 19, 20, 149, 291, 156, 184,
+	183, 423, 424, 182, 101, 427, 240, 235, 238, 239,
+	236, 237, 100, 19, 20, 19, 20, 93, 92, 166,
+	404, 18, 149, 436, 15, 376, 438, 363, 439, 286,
+	348, 295, 294, 292, 14, 275, 267, 255, 165, 443,
+	83, 167, 251, 177, 19, 20, 447, 23, 24, 25,
+	46, 55, 56, 47, 49, 50, 48, 51, 52, 53,
+	54, 26, 27, 209, 207, 364, 293, 287, 437, 426,
+	421, 28, 29, 30, 31, 32, 33, 34, 399, 264,
+	265, 35, 36, 37, 57, 21, 82, 84, 389, 172,
+	214, 417, 448, 284, 79, 80, 81, 214, 91, 146,
+	212, 415, 38, 39, 40, 41, 42, 43, 44, 45,
+	269, 367, 368, 15, 89, 329, 326, 186, 99, 19,
+	20, 261, 98, 14, 444, 429, 412, 411, 410, 374,
+	366, 362, 6, 225, 147, 352, 23, 24, 25, 46,
+	55, 56, 47, 49, 50, 48, 51, 52, 53, 54,
+	26, 27, 322, 250, 249, 248, 247, 222, 221, 219,
+	28, 29, 30, 31, 32, 33, 34, 217, 83, 216,
+	35, 36, 37, 57, 21, 82, 84, 272, 435, 290,
+	403, 232, 228, 79, 80, 81, 214, 91, 225, 288,
+	127, 38, 39, 40, 41, 42, 43, 44, 45, 181,
+	126, 124, 15, 125, 102, 223, 132, 230, 19, 20,
+	76, 134, 14, 226, 133, 131, 130, 129, 210, 75,
+	157, 6, 148, 158, 122, 23, 24, 25, 46, 55,
+	56, 47, 49, 50, 48, 51, 52, 53, 54, 26,
+	27, 123, 104, 103, 12, 10, 22, 13, 17, 28,
+	29, 30, 31, 32, 33, 34, 9, 83, 393, 35,
+	36, 37, 57, 21, 106, 107, 108, 109, 110, 111,
+	112, 113, 114, 115, 116, 117, 118, 119, 16, 8,
+	38, 39, 40, 41, 42, 43, 44, 45, 174, 88,
+	259, 15, 78, 1, 0, 82, 84, 19, 20, 0,
+	0, 14, 0, 79, 80, 81, 0, 340, 0, 0,
+	177, 0, 0, 0, 23, 24, 25, 46, 55, 56,
+	47, 49, 50, 48, 51, 52, 53, 54, 26, 27,
+	261, 0, 0, 0, 0, 0, 0, 0, 28, 29,
+	30, 31, 32, 33, 34, 82, 84, 0, 35, 36,
+	37, 57, 21, 79, 80, 81, 0, 0, 0, 0,
+	0, 156, 0, 0, 0, 135, 0, 0, 0, 38,
+	39, 40, 41, 42, 43, 44, 45, 83, 330, 0,
+	261, 0, 0, 82, 84, 149, 19, 20, 0, 259,
+	0, 79, 80, 81, 82, 84, 0, 0, 0, 0,
+	335, 0, 79, 80, 81, 0, 137, 138, 136, 0,
+	150, 153, 338, 0, 0, 0, 0, 0, 261, 0,
+	0, 156, 0, 0, 0, 135, 0, 83, 139, 261,
+	140, 0, 0, 0, 0, 0, 151, 154, 155, 141,
+	144, 142, 143, 152, 0, 149, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 83, 137, 138, 136, 0,
+	150, 153, 0, 0, 0, 0, 83, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 139, 0,
+	140, 0, 0, 0, 0, 0, 151, 154, 155, 141,
+	144, 142, 143, 152,
 }
 
 var exprPact = [...]int16{
-	353, -1000, -58, -1000, -1000, 540, 353, -1000, -1000, -1000,
-	-1000, -1000, -1000, 409, 337, 289, -1000, 437, 428, 336,
-	335, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	28, -1000, 104, -1000, -1000, 557, 28, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, 493, 389, 388, 132, -1000, 515,
+	511, 383, 375, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 89, 89,
-	89, 89, 89, 89, 89, 89, 89, 89, 89, 89,
-	89, 89, 89, 540, -1000, 196, 534, -9, 75, -1000,
-	-1000, -1000, -1000, -1000, -1000, 303, 299, -58, 474, -1000,
-	-1000, 86, 325, 477, 332, 331, 330, -1000, -1000, 353,
-	418, 353, 56, 14, -1000, 353, 353, 353, 353, 353,
-	353, 353, 353, 353, 353, 353, 353, 353, 353, -1000,
-	-1000, -1000, -1000, -1000, -1000, 83, -1000, -1000, -1000, -1000,
-	-1000, 456, 541, 523, -1000, 522, -1000, -1000, -1000, -1000,
-	284, 520, -1000, 546, 535, 533, 98, -1000, -1000, 66,
-	-10, 329, -1000, -1000, -1000, -1000, -1000, 542, 518, 517,
-	496, 495, 314, 382, 413, 310, 297, 381, 415, 302,
-	285, 380, 273, -42, 328, 327, 324, 317, -30, -30,
-	-26, -26, -74, -74, -74, -74, -15, -15, -15, -15,
-	-15, -15, 83, 284, 284, 284, 426, 354, -1000, -1000,
-	393, 354, -1000, -1000, 174, -1000, 379, -1000, 392, 360,
-	-1000, 86, -1000, 357, -1000, 86, -1000, 88, 76, 189,
-	150, 137, 124, 99, -1000, -43, 312, 66, 483, -1000,
-	-1000, -1000, -1000, -1000, -1000, 122, 310, 249, 126, 261,
-	501, 349, 293, 122, 353, 265, 356, 260, -1000, -1000,
-	197, -1000, 482, -1000, 278, 277, 205, 129, 204, 83,
-	323, -1000, 354, 541, 481, -1000, 491, 434, 535, 533,
-	311, -1000, -1000, -1000, 305, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, 66, 480, -1000, 229, -1000, 167, 475,
-	46, 475, 421, 16, 284, 16, 95, 144, 416, 248,
-	298, -1000, -1000, 226, -1000, 353, 525, -1000, -1000, 350,
-	181, -1000, 176, -1000, -1000, 153, -1000, 140, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, 471, 462, -1000, 217,
-	-1000, 122, 46, 475, 46, -1000, -1000, 83, -1000, 16,
-	-1000, 190, -1000, -1000, -1000, 82, 414, 406, 207, 122,
-	188, -1000, 461, -1000, -1000, -1000, -1000, 172, 160, -1000,
-	-1000, 46, -1000, 457, 52, 46, 38, 16, 16, 405,
-	-1000, -1000, 346, -1000, -1000, 131, 46, -1000, -1000, 16,
-	460, -1000, -1000, 202, 459, 112, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 33, 33,
+	33, 33, 33, 33, 33, 33, 33, 33, 33, 33,
+	33, 33, 33, 557, -1000, 87, 816, -69, 75, -1000,
+	-1000, -1000, -1000, -1000, -1000, 296, 200, 104, 417, -1000,
+	-1000, 29, 477, 681, 592, 374, 371, 370, -1000, -1000,
+	28, 510, 28, 3, -42, -1000, 28, 28, 28, 28,
+	28, 28, 28, 28, 28, 28, 28, 28, 28, 28,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 279, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 492, 581, 563, -1000,
+	561, 581, -1000, -1000, 553, -1000, -1000, -1000, -1000, 393,
+	552, -1000, 551, 583, 577, 576, 307, -1000, -1000, 50,
+	-70, 360, -1000, -1000, -1000, -1000, -1000, 582, 550, 549,
+	548, 547, 418, 147, 413, 350, 776, 414, 466, 469,
+	283, 412, 503, 358, 355, 411, 253, 135, 357, 353,
+	352, 348, 119, 119, -77, -77, -87, -87, -87, -87,
+	-66, -66, -66, -66, -66, -66, 279, 393, 393, 393,
+	485, 405, -1000, -1000, 451, 405, -1000, -1000, 405, 574,
+	367, -1000, -1000, 409, -1000, 450, 408, -1000, 29, -1000,
+	407, -1000, 29, -1000, 289, 243, 339, 310, 308, 48,
+	46, -1000, -72, 344, 50, 546, -1000, -1000, -1000, -1000,
+	-1000, 342, -1000, 69, 509, 414, -1000, 508, 765, 727,
+	117, 756, 677, 298, -39, -39, 69, 28, 241, 406,
+	326, -1000, -1000, 317, -1000, 529, -1000, 349, 335, 331,
+	297, 292, 279, 151, -1000, 405, 581, 525, 403, -1000,
+	449, -1000, 528, 506, 577, 576, 304, -1000, -1000, -1000,
+	301, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 50,
+	523, -1000, 238, 401, 342, -1000, 274, 158, 230, 229,
+	-39, 79, 468, 6, 468, 476, -39, 393, 103, 340,
+	465, 281, -1000, -1000, -1000, -1000, 226, -1000, 28, 575,
+	-1000, -1000, 396, 222, -1000, 168, -1000, -1000, 159, -1000,
+	152, -1000, -1000, 574, 522, -1000, -1000, -1000, -1000, -1000,
+	-1000, 521, 520, -1000, 221, -1000, 342, 200, -1000, 494,
+	69, -1000, -1000, 484, -1000, -39, 6, 468, 6, -1000,
+	-1000, 279, -1000, 290, -1000, -1000, -1000, 457, 142, 51,
+	456, 69, 163, -1000, 519, -1000, -1000, -1000, -1000, -1000,
+	-1000, 160, 144, -1000, 97, 96, -1000, 81, -1000, 6,
+	573, -39, 455, 40, 6, 21, -39, -1000, -1000, 354,
+	-1000, -1000, -22, -1000, -1000, 77, -1000, -39, 6, -1000,
+	518, 244, -1000, -1000, 157, 572, 486, 149, 64, -1000,
+	-1000,
 }
 
 var exprPgo = [...]int16{
-	0, 610, 18, 609, 2, 14, 478, 3, 16, 7,
-	608, 607, 606, 604, 15, 602, 601, 600, 599, 84,
-	598, 38, 597, 575, 596, 595, 594, 593, 13, 5,
-	592, 591, 589, 6, 588, 107, 4, 587, 584, 577,
-	576, 574, 11, 573, 571, 8, 569, 12, 568, 9,
-	17, 562, 561, 1, 558, 557, 0,
+	0, 693, 28, 692, 3, 0, 6, 18, 8, 19,
+	10, 689, 679, 21, 678, 658, 16, 656, 648, 647,
+	646, 49, 645, 51, 644, 604, 643, 642, 641, 624,
+	22, 4, 623, 622, 620, 7, 619, 83, 9, 618,
+	617, 616, 615, 614, 613, 14, 611, 607, 11, 606,
+	17, 605, 12, 20, 603, 601, 600, 590, 5, 589,
+	2, 534, 499, 1,
 }
 
 var exprR1 = [...]int8{
-	0, 1, 2, 2, 7, 7, 7, 7, 7, 7,
-	7, 6, 6, 6, 8, 8, 8, 8, 8, 8,
-	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	53, 53, 53, 13, 13, 13, 11, 11, 11, 11,
-	15, 15, 15, 15, 15, 15, 22, 3, 3, 3,
-	3, 3, 3, 14, 14, 14, 10, 10, 9, 9,
-	9, 9, 28, 28, 29, 29, 29, 29, 29, 29,
-	29, 29, 29, 29, 29, 19, 36, 36, 36, 35,
-	35, 35, 34, 34, 34, 37, 37, 27, 27, 26,
-	26, 26, 26, 52, 51, 51, 38, 39, 47, 47,
-	48, 48, 48, 46, 33, 33, 33, 33, 33, 33,
-	33, 33, 33, 49, 49, 50, 50, 55, 55, 54,
-	54, 32, 32, 32, 32, 32, 32, 32, 30, 30,
-	30, 30, 30, 30, 30, 31, 31, 31, 31, 31,
-	31, 31, 42, 42, 41, 41, 40, 45, 45, 44,
-	44, 43, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 20, 20, 20, 20, 20, 24, 24, 25,
-	25, 25, 25, 23, 23, 23, 23, 23, 23, 23,
-	23, 21, 21, 21, 17, 18, 16, 16, 16, 16,
-	16, 16, 16, 16, 16, 16, 16, 12, 12, 12,
+	0, 1, 2, 2, 8, 8, 8, 8, 8, 8,
+	8, 6, 6, 6, 6, 7, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 60, 60, 60, 15, 15, 15,
 	12, 12, 12, 12, 12, 12, 12, 12, 12, 12,
-	12, 12, 56, 5, 5, 4, 4, 4, 4,
+	13, 13, 17, 17, 17, 17, 17, 17, 24, 3,
+	3, 3, 3, 3, 3, 16, 16, 16, 11, 11,
+	10, 10, 10, 10, 30, 30, 31, 31, 31, 31,
+	31, 31, 31, 31, 31, 31, 31, 31, 31, 31,
+	31, 21, 38, 38, 38, 37, 37, 37, 36, 36,
+	36, 39, 39, 29, 29, 28, 28, 28, 28, 28,
+	28, 28, 55, 56, 57, 57, 58, 59, 59, 54,
+	54, 40, 41, 42, 42, 50, 50, 51, 51, 51,
+	49, 35, 35, 35, 35, 35, 35, 35, 35, 35,
+	52, 52, 53, 53, 62, 62, 61, 61, 34, 34,
+	34, 34, 34, 34, 34, 32, 32, 32, 32, 32,
+	32, 32, 33, 33, 33, 33, 33, 33, 33, 45,
+	45, 44, 44, 43, 48, 48, 47, 47, 46, 22,
+	22, 22, 22, 22, 22, 22, 22, 22, 22, 22,
+	22, 22, 22, 22, 26, 26, 27, 27, 27, 27,
+	25, 25, 25, 25, 25, 25, 25, 25, 23, 23,
+	23, 19, 20, 18, 18, 18, 18, 18, 18, 18,
+	18, 18, 18, 18, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 63, 5, 5,
+	4, 4, 4, 4,
 }
 
 var exprR2 = [...]int8{
 	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	3, 1, 2, 3, 2, 3, 4, 5, 3, 4,
-	5, 6, 3, 4, 5, 6, 3, 4, 5, 6,
-	4, 5, 6, 7, 3, 4, 4, 5, 3, 2,
-	3, 6, 3, 1, 1, 1, 4, 6, 5, 7,
-	4, 5, 5, 6, 7, 7, 12, 1, 1, 1,
-	1, 1, 1, 3, 3, 2, 1, 3, 3, 3,
-	3, 3, 1, 2, 1, 2, 2, 2, 2, 2,
-	2, 2, 2, 2, 2, 1, 1, 4, 3, 2,
-	5, 4, 1, 3, 2, 1, 2, 1, 2, 1,
-	2, 1, 2, 2, 3, 2, 2, 1, 3, 3,
-	1, 3, 3, 2, 1, 1, 1, 1, 3, 2,
-	3, 3, 3, 3, 1, 1, 3, 6, 6, 1,
-	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	3, 1, 2, 3, 1, 12, 2, 3, 4, 5,
+	3, 4, 5, 6, 3, 4, 5, 6, 3, 4,
+	5, 6, 4, 5, 6, 7, 3, 4, 4, 5,
+	2, 3, 3, 2, 3, 6, 3, 1, 1, 1,
+	4, 6, 5, 7, 6, 8, 4, 6, 6, 8,
+	2, 3, 4, 5, 5, 6, 7, 7, 12, 1,
+	1, 1, 1, 1, 1, 3, 3, 2, 1, 3,
+	3, 3, 3, 3, 1, 2, 1, 2, 2, 2,
+	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+	2, 1, 1, 4, 3, 2, 5, 4, 1, 3,
+	2, 1, 2, 1, 2, 1, 2, 1, 2, 1,
+	1, 1, 2, 2, 2, 3, 3, 1, 3, 3,
+	2, 2, 1, 1, 2, 3, 3, 1, 3, 3,
+	2, 1, 1, 1, 1, 3, 2, 3, 3, 3,
+	3, 1, 1, 3, 6, 6, 1, 1, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 1, 1, 1, 3, 2, 1, 1, 1,
-	3, 2, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 4, 4, 4, 4, 0, 1, 5,
-	4, 5, 4, 1, 1, 2, 4, 5, 2, 4,
-	5, 1, 2, 2, 4, 1, 1, 1, 1, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 1,
+	1, 1, 3, 2, 1, 1, 1, 3, 2, 4,
+	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
+	4, 4, 4, 4, 0, 1, 5, 4, 5, 4,
+	1, 1, 2, 4, 5, 2, 4, 5, 1, 2,
+	2, 4, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 2, 1, 3, 4, 4, 3, 3,
+	1, 1, 1, 1, 1, 1, 1, 2, 1, 3,
+	4, 4, 3, 3,
 }
 
 var exprChk = [...]int16{
-	-1000, -1, -2, -6, -7, -14, 26, -11, -15, -20,
-	-21, -22, -17, 17, -12, -16, 7, 89, 90, 68,
-	-18, 30, 31, 32, 44, 45, 54, 55, 56, 57,
-	58, 59, 60, 64, 65, 66, 33, 36, 39, 37,
-	38, 40, 41, 42, 43, 34, 35, 67, 80, 81,
-	82, 89, 90, 91, 92, 93, 94, 83, 84, 87,
-	88, 85, 86, -28, -29, -34, 50, -35, -3, 23,
-	24, 25, 15, 84, 16, -7, -6, -2, -10, 18,
-	-9, 5, 26, 26, -4, 28, 29, 7, 7, 26,
-	26, -23, -24, -25, 46, -23, -23, -23, -23, -23,
-	-23, -23, -23, -23, -23, -23, -23, -23, -23, -29,
-	-35, -27, -26, -52, -51, -33, -38, -39, -46, -40,
-	-43, 49, 47, 48, 69, 71, -9, -55, -54, -31,
-	26, 51, 77, 52, 78, 79, 5, -32, -30, 80,
-	6, -19, 72, 27, 27, 18, 2, 21, 13, 84,
-	14, 15, -8, 7, -14, 26, -7, 7, 26, 26,
-	26, -7, 7, -2, 73, 74, 75, 76, -2, -2,
-	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-2, -2, -33, 81, 21, 80, -37, -50, 8, -49,
-	5, -50, 6, 6, -33, 6, -48, -47, 5, -41,
-	-42, 5, -9, -44, -45, 5, -9, 13, 84, 87,
-	88, 85, 86, 83, -36, 6, -19, 80, 26, -9,
-	6, 6, 6, 6, 2, 27, 21, 10, -53, -28,
-	50, -14, -8, 27, 21, -7, 7, -5, 27, 5,
-	-5, 27, 21, 27, 26, 26, 26, 26, -33, -33,
-	-33, 8, -50, 21, 13, 27, 21, 13, 21, 21,
-	72, 9, 4, -21, 72, 9, 4, -21, 9, 4,
-	-21, 9, 4, -21, 9, 4, -21, 9, 4, -21,
-	9, 4, -21, 80, 26, -36, 6, -4, -8, -56,
-	-53, -28, 70, 10, 50, 10, -53, 53, 27, -53,
-	-28, 27, -4, -7, 27, 21, 21, 27, 27, 6,
-	-5, 27, -5, 27, 27, -5, 27, -5, -49, 6,
-	-47, 2, 5, 6, -42, -45, 26, 26, -36, 6,
-	27, 27, -53, -28, -53, 9, -56, -33, -56, 10,
-	5, -13, 61, 62, 63, 10, 27, 27, -53, 27,
-	-7, 5, 21, 27, 27, 27, 27, 6, 6, 27,
-	-4, -53, -56, 26, -56, -53, 50, 10, 10, 27,
-	-4, 27, 6, 27, 27, 5, -53, -56, -56, 10,
-	21, 27, -56, 6, 21, 6, 27,
+	-1000, -1, -2, -6, -8, -16, 29, -7, -12, -17,
+	-22, -23, -24, -19, 20, 10, -14, -18, 7, 105,
+	106, 71, -20, 33, 34, 35, 47, 48, 57, 58,
+	59, 60, 61, 62, 63, 67, 68, 69, 88, 89,
+	90, 91, 92, 93, 94, 95, 36, 39, 42, 40,
+	41, 43, 44, 45, 46, 37, 38, 70, 96, 97,
+	98, 105, 106, 107, 108, 109, 110, 99, 100, 103,
+	104, 101, 102, -30, -31, -36, 53, -37, -3, 26,
+	27, 28, 18, 100, 19, -8, -6, -2, -11, 21,
+	-10, 5, 29, 29, 29, -4, 31, 32, 7, 7,
+	29, 29, -25, -26, -27, 49, -25, -25, -25, -25,
+	-25, -25, -25, -25, -25, -25, -25, -25, -25, -25,
+	-31, -37, -29, -28, -55, -54, -56, -57, -35, -40,
+	-41, -42, -49, -43, -46, 9, 52, 50, 51, 72,
+	74, 83, 85, 86, 84, -10, -62, -61, -33, 29,
+	54, 80, 87, 55, 81, 82, 5, -34, -32, 96,
+	6, -21, 75, 30, 30, 21, 2, 24, 16, 100,
+	17, 18, 12, -9, 7, -13, -16, 29, -7, -8,
+	-8, 7, 29, 29, 29, -8, 7, -2, 76, 77,
+	78, 79, -2, -2, -2, -2, -2, -2, -2, -2,
+	-2, -2, -2, -2, -2, -2, -35, 97, 24, 96,
+	-39, -53, 8, -52, 5, -53, 6, 6, -53, 6,
+	-35, 6, 6, -51, -50, 5, -44, -45, 5, -10,
+	-47, -48, 5, -10, 16, 100, 103, 104, 101, 102,
+	99, -38, 6, -21, 96, 29, -10, 6, 6, 6,
+	6, 24, 2, 30, 24, 24, 30, 24, -30, 13,
+	-60, 53, -16, -9, 13, 11, 30, 24, -8, 7,
+	-5, 30, 5, -5, 30, 24, 30, 29, 29, 29,
+	29, -35, -35, -35, 8, -53, 24, 16, -59, -58,
+	5, 30, 24, 16, 24, 24, 75, 12, 4, -23,
+	75, 12, 4, -23, 12, 4, -23, 12, 4, -23,
+	12, 4, -23, 12, 4, -23, 12, 4, -23, 96,
+	29, -38, 6, -6, 29, -4, 7, -9, -13, 7,
+	13, -60, -63, -60, -30, 73, 13, 53, 56, -30,
+	30, -60, 30, -63, -63, -4, -8, 30, 24, 24,
+	30, 30, 6, -5, 30, -5, 30, 30, -5, 30,
+	-5, -52, 6, 24, 16, -50, 2, 5, 6, -45,
+	-48, 29, 29, -38, 6, 30, 24, -6, 30, 24,
+	30, 30, 30, 24, -63, 13, -60, -30, -60, 12,
+	-63, -35, 5, -15, 64, 65, 66, 30, -60, 13,
+	30, 30, -8, 5, 24, 30, 30, 30, 30, -58,
+	6, 6, 6, 30, -6, 7, -4, 7, -63, -60,
+	29, 13, 30, -63, -60, 53, 13, -4, 30, 6,
+	30, 30, 30, 30, 30, 5, -63, 13, -60, -63,
+	24, 76, 30, -63, 6, 29, 24, -5, 6, 30,
+	30,
 }
 
 var exprDef = [...]int16{
-	0, -2, 1, 2, 3, 11, 0, 4, 5, 6,
-	7, 8, 9, 0, 0, 0, 191, 0, 0, 0,
-	0, 207, 208, 209, 210, 211, 212, 213, 214, 215,
-	216, 217, 218, 219, 220, 221, 196, 197, 198, 199,
-	200, 201, 202, 203, 204, 205, 206, 195, 177, 177,
-	177, 177, 177, 177, 177, 177, 177, 177, 177, 177,
-	177, 177, 177, 12, 72, 74, 0, 92, 0, 57,
-	58, 59, 60, 61, 62, 3, 2, 0, 0, 65,
-	66, 0, 0, 0, 0, 0, 0, 192, 193, 0,
-	0, 0, 183, 184, 178, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 73,
-	94, 75, 76, 77, 78, 79, 80, 81, 82, 83,
-	84, 97, 99, 0, 101, 0, 114, 115, 116, 117,
-	0, 0, 107, 0, 0, 0, 0, 129, 130, 0,
-	89, 0, 85, 10, 13, 63, 64, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 3, 191, 0, 0,
-	0, 3, 0, 162, 0, 0, 185, 188, 163, 164,
-	165, 166, 167, 168, 169, 170, 171, 172, 173, 174,
-	175, 176, 119, 0, 0, 0, 98, 105, 95, 125,
-	124, 103, 100, 102, 0, 106, 113, 110, 0, 156,
-	154, 152, 153, 161, 159, 157, 158, 0, 0, 0,
-	0, 0, 0, 0, 93, 86, 0, 0, 0, 67,
-	68, 69, 70, 71, 39, 46, 0, 14, 0, 0,
-	0, 0, 0, 50, 0, 3, 191, 0, 227, 223,
-	0, 228, 0, 194, 0, 0, 0, 0, 120, 121,
-	122, 96, 104, 0, 0, 118, 0, 0, 0, 0,
-	0, 136, 143, 150, 0, 135, 142, 149, 131, 138,
-	145, 132, 139, 146, 133, 140, 147, 134, 141, 148,
-	137, 144, 151, 0, 0, 91, 0, 48, 0, 15,
-	18, 34, 0, 22, 0, 26, 0, 0, 0, 0,
-	0, 38, 52, 3, 51, 0, 0, 225, 226, 0,
-	0, 180, 0, 182, 186, 0, 189, 0, 126, 123,
-	111, 112, 108, 109, 155, 160, 0, 0, 88, 0,
-	90, 47, 19, 35, 36, 222, 23, 42, 27, 30,
-	40, 0, 43, 44, 45, 16, 0, 0, 0, 53,
-	3, 224, 0, 179, 181, 187, 190, 0, 0, 87,
-	49, 37, 31, 0, 17, 20, 0, 24, 28, 0,
-	54, 55, 0, 127, 128, 0, 21, 25, 29, 32,
-	0, 41, 33, 0, 0, 0, 56,
+	0, -2, 1, 2, 3, 11, 0, 14, 4, 5,
+	6, 7, 8, 9, 0, 0, 0, 0, 218, 0,
+	0, 0, 0, 234, 235, 236, 237, 238, 239, 240,
+	241, 242, 243, 244, 245, 246, 247, 248, 249, 250,
+	251, 252, 253, 254, 255, 256, 223, 224, 225, 226,
+	227, 228, 229, 230, 231, 232, 233, 222, 204, 204,
+	204, 204, 204, 204, 204, 204, 204, 204, 204, 204,
+	204, 204, 204, 12, 84, 86, 0, 108, 0, 69,
+	70, 71, 72, 73, 74, 3, 2, 0, 0, 77,
+	78, 0, 0, 0, 0, 0, 0, 0, 219, 220,
+	0, 0, 0, 210, 211, 205, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	85, 110, 87, 88, 89, 90, 91, 92, 93, 94,
+	95, 96, 97, 98, 99, 100, 113, 115, 0, 117,
+	0, 119, 120, 121, 0, 141, 142, 143, 144, 0,
+	0, 132, 133, 0, 0, 0, 0, 156, 157, 0,
+	105, 0, 101, 10, 13, 75, 76, 0, 0, 0,
+	0, 0, 0, 0, 218, 0, 11, 0, 14, 3,
+	3, 218, 0, 0, 0, 3, 0, 189, 0, 0,
+	212, 215, 190, 191, 192, 193, 194, 195, 196, 197,
+	198, 199, 200, 201, 202, 203, 146, 0, 0, 0,
+	114, 130, 111, 152, 151, 122, 116, 118, 123, 124,
+	0, 131, 134, 140, 137, 0, 183, 181, 179, 180,
+	188, 186, 184, 185, 0, 0, 0, 0, 0, 0,
+	0, 109, 102, 0, 0, 0, 79, 80, 81, 82,
+	83, 0, 43, 50, 0, 0, 56, 0, 12, 16,
+	0, 0, 11, 0, 40, 60, 62, 0, 3, 218,
+	0, 262, 258, 0, 263, 0, 221, 0, 0, 0,
+	0, 147, 148, 149, 112, 129, 0, 0, 125, 127,
+	0, 145, 0, 0, 0, 0, 0, 163, 170, 177,
+	0, 162, 169, 176, 158, 165, 172, 159, 166, 173,
+	160, 167, 174, 161, 168, 175, 164, 171, 178, 0,
+	0, 107, 0, 0, 0, 52, 0, 0, 0, 0,
+	28, 0, 17, 20, 36, 0, 24, 0, 0, 12,
+	0, 0, 42, 41, 61, 64, 3, 63, 0, 0,
+	260, 261, 0, 0, 207, 0, 209, 213, 0, 216,
+	0, 153, 150, 0, 0, 138, 139, 135, 136, 182,
+	187, 0, 0, 104, 0, 106, 0, 0, 54, 0,
+	51, 57, 58, 0, 29, 32, 21, 37, 38, 257,
+	25, 46, 44, 0, 47, 48, 49, 0, 0, 18,
+	0, 65, 3, 259, 0, 206, 208, 214, 217, 128,
+	126, 0, 0, 103, 0, 0, 53, 0, 33, 39,
+	0, 30, 0, 19, 22, 0, 26, 66, 67, 0,
+	154, 155, 0, 55, 59, 0, 31, 34, 23, 27,
+	0, 0, 45, 35, 0, 0, 0, 0, 0, 15,
+	68,
 }
 
 var exprTok1 = [...]int8{
@@ -555,7 +649,8 @@ var exprTok2 = [...]int8{
 	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
-	92, 93, 94,
+	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
+	102, 103, 104, 105, 106, 107, 108, 109, 110,
 }
 
 var exprTok3 = [...]int8{
@@ -901,1368 +996,1578 @@ exprdefault:
 
 	case 1:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:154
+//line expr.y:167
 		{
 			exprlex.(*parser).expr = exprDollar[1].Expr
 		}
 	case 2:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:157
+//line expr.y:170
 		{
 			exprVAL.Expr = exprDollar[1].LogExpr
 		}
 	case 3:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:158
+//line expr.y:171
 		{
 			exprVAL.Expr = exprDollar[1].MetricExpr
 		}
 	case 4:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:162
+//line expr.y:175
 		{
 			exprVAL.MetricExpr = exprDollar[1].RangeAggregationExpr
 		}
 	case 5:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:163
+//line expr.y:176
 		{
 			exprVAL.MetricExpr = exprDollar[1].VectorAggregationExpr
 		}
 	case 6:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:164
+//line expr.y:177
 		{
 			exprVAL.MetricExpr = exprDollar[1].BinOpExpr
 		}
 	case 7:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:165
+//line expr.y:178
 		{
 			exprVAL.MetricExpr = exprDollar[1].LiteralExpr
 		}
 	case 8:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:166
+//line expr.y:179
 		{
 			exprVAL.MetricExpr = exprDollar[1].LabelReplaceExpr
 		}
 	case 9:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:167
+//line expr.y:180
 		{
 			exprVAL.MetricExpr = exprDollar[1].VectorExpr
 		}
 	case 10:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:168
+//line expr.y:181
 		{
 			exprVAL.MetricExpr = exprDollar[2].MetricExpr
 		}
 	case 11:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:172
+//line expr.y:185
 		{
 			exprVAL.LogExpr = newMatcherExpr(exprDollar[1].Selector)
 		}
 	case 12:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:173
+//line expr.y:186
 		{
 			exprVAL.LogExpr = newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr)
 		}
 	case 13:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:174
+//line expr.y:187
 		{
 			exprVAL.LogExpr = exprDollar[2].LogExpr
 		}
 	case 14:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:188
+		{
+			exprVAL.LogExpr = exprDollar[1].LogExpr
+		}
+	case 15:
+		exprDollar = exprS[exprpt-12 : exprpt+1]
+//line expr.y:192
+		{
+			exprVAL.LogExpr = newJoinExpr(exprDollar[1].str, exprDollar[3].duration, exprDollar[5].LogExpr, exprDollar[7].LogExpr, exprDollar[11].Labels)
+		}
+	case 16:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:178
+//line expr.y:196
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, nil)
 		}
-	case 15:
+	case 17:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:179
+//line expr.y:197
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 16:
+	case 18:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:180
+//line expr.y:198
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, nil)
 		}
-	case 17:
+	case 19:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:181
+//line expr.y:199
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, exprDollar[5].OffsetExpr)
 		}
-	case 18:
+	case 20:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:182
+//line expr.y:200
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 19:
+	case 21:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:183
+//line expr.y:201
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[4].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 20:
+	case 22:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:184
+//line expr.y:202
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[5].UnwrapExpr, nil)
 		}
-	case 21:
+	case 23:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:185
+//line expr.y:203
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[6].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 22:
+	case 24:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:186
+//line expr.y:204
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, nil)
 		}
-	case 23:
+	case 25:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:187
+//line expr.y:205
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, exprDollar[4].OffsetExpr)
 		}
-	case 24:
+	case 26:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:188
+//line expr.y:206
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 25:
+	case 27:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:189
+//line expr.y:207
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, exprDollar[6].OffsetExpr)
 		}
-	case 26:
+	case 28:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:190
+//line expr.y:208
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, nil)
 		}
-	case 27:
+	case 29:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:191
+//line expr.y:209
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, exprDollar[4].OffsetExpr)
 		}
-	case 28:
+	case 30:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:192
+//line expr.y:210
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, nil)
 		}
-	case 29:
+	case 31:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:193
+//line expr.y:211
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, exprDollar[6].OffsetExpr)
 		}
-	case 30:
+	case 32:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:194
+//line expr.y:212
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 31:
+	case 33:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:195
+//line expr.y:213
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 32:
+	case 34:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:196
+//line expr.y:214
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 33:
+	case 35:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:197
+//line expr.y:215
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, exprDollar[7].OffsetExpr)
 		}
-	case 34:
+	case 36:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:198
+//line expr.y:216
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, nil, nil)
 		}
-	case 35:
+	case 37:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:199
+//line expr.y:217
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 36:
+	case 38:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:200
+//line expr.y:218
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 37:
+	case 39:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:201
+//line expr.y:219
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, exprDollar[5].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 38:
+	case 40:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:220
+		{
+			exprVAL.LogRangeExpr = newLogRange(exprDollar[1].LogExpr, exprDollar[2].duration, nil, nil)
+		}
+	case 41:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:202
+//line expr.y:221
+		{
+			exprVAL.LogRangeExpr = newLogRange(exprDollar[1].LogExpr, exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
+		}
+	case 42:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:222
 		{
 			exprVAL.LogRangeExpr = exprDollar[2].LogRangeExpr
 		}
-	case 40:
+	case 44:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:207
+//line expr.y:227
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[3].str, "")
 		}
-	case 41:
+	case 45:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:208
+//line expr.y:228
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[5].str, exprDollar[3].ConvOp)
 		}
-	case 42:
+	case 46:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:209
+//line expr.y:229
 		{
 			exprVAL.UnwrapExpr = exprDollar[1].UnwrapExpr.addPostFilter(exprDollar[3].LabelFilter)
 		}
-	case 43:
+	case 47:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:213
+//line expr.y:233
 		{
 			exprVAL.ConvOp = OpConvBytes
 		}
-	case 44:
+	case 48:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:214
+//line expr.y:234
 		{
 			exprVAL.ConvOp = OpConvDuration
 		}
-	case 45:
+	case 49:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:215
+//line expr.y:235
 		{
 			exprVAL.ConvOp = OpConvDurationSeconds
 		}
-	case 46:
+	case 50:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:219
+//line expr.y:239
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, nil, nil)
 		}
-	case 47:
+	case 51:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:220
+//line expr.y:240
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, nil, &exprDollar[3].str)
 		}
-	case 48:
+	case 52:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:221
+//line expr.y:241
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[5].Grouping, nil)
 		}
-	case 49:
+	case 53:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:222
+//line expr.y:242
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 50:
+	case 54:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:243
+		{
+			exprVAL.RangeAggregationExpr = newRangeAggregationExprWithArgs(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[5].str)
+		}
+	case 55:
+		exprDollar = exprS[exprpt-8 : exprpt+1]
+//line expr.y:244
+		{
+			exprVAL.RangeAggregationExpr = newRangeAggregationExprWithArgs(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[5].str, exprDollar[7].str)
+		}
+	case 56:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:227
+//line expr.y:245
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[3].Expr.(*SubqueryExpr), exprDollar[1].RangeOp, nil)
+		}
+	case 57:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:246
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[5].Expr.(*SubqueryExpr), exprDollar[1].RangeOp, &exprDollar[3].str)
+		}
+	case 58:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:247
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[3].Expr.(*SubqueryExpr), exprDollar[1].RangeOp, nil, exprDollar[5].str)
+		}
+	case 59:
+		exprDollar = exprS[exprpt-8 : exprpt+1]
+//line expr.y:248
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[3].Expr.(*SubqueryExpr), exprDollar[1].RangeOp, nil, exprDollar[5].str, exprDollar[7].str)
+		}
+	case 60:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:252
+		{
+			exprVAL.Expr = newSubqueryExpr(exprDollar[1].MetricExpr, exprDollar[2].str, nil)
+		}
+	case 61:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:253
+		{
+			exprVAL.Expr = newSubqueryExpr(exprDollar[1].MetricExpr, exprDollar[2].str, exprDollar[3].OffsetExpr)
+		}
+	case 62:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:258
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
 		}
-	case 51:
+	case 63:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:228
+//line expr.y:259
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[4].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, nil)
 		}
-	case 52:
+	case 64:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:229
+//line expr.y:260
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, exprDollar[5].Grouping, nil)
 		}
-	case 53:
+	case 65:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:231
+//line expr.y:262
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, nil, &exprDollar[3].str)
 		}
-	case 54:
+	case 66:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:232
+//line expr.y:263
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 55:
+	case 67:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:233
+//line expr.y:264
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, &exprDollar[4].str)
 		}
-	case 56:
+	case 68:
 		exprDollar = exprS[exprpt-12 : exprpt+1]
-//line expr.y:238
+//line expr.y:269
 		{
 			exprVAL.LabelReplaceExpr = mustNewLabelReplaceExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].str, exprDollar[11].str)
 		}
-	case 57:
+	case 69:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:242
+//line expr.y:273
 		{
 			exprVAL.Filter = log.LineMatchRegexp
 		}
-	case 58:
+	case 70:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:243
+//line expr.y:274
 		{
 			exprVAL.Filter = log.LineMatchEqual
 		}
-	case 59:
+	case 71:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:244
+//line expr.y:275
 		{
 			exprVAL.Filter = log.LineMatchPattern
 		}
-	case 60:
+	case 72:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:245
+//line expr.y:276
 		{
 			exprVAL.Filter = log.LineMatchNotRegexp
 		}
-	case 61:
+	case 73:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:246
+//line expr.y:277
 		{
 			exprVAL.Filter = log.LineMatchNotEqual
 		}
-	case 62:
+	case 74:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:247
+//line expr.y:278
 		{
 			exprVAL.Filter = log.LineMatchNotPattern
 		}
-	case 63:
+	case 75:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:251
+//line expr.y:282
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 64:
+	case 76:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:252
+//line expr.y:283
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 65:
+	case 77:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:253
+//line expr.y:284
 		{
 		}
-	case 66:
+	case 78:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:257
+//line expr.y:288
 		{
 			exprVAL.Matchers = []*labels.Matcher{exprDollar[1].Matcher}
 		}
-	case 67:
+	case 79:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:258
+//line expr.y:289
 		{
 			exprVAL.Matchers = append(exprDollar[1].Matchers, exprDollar[3].Matcher)
 		}
-	case 68:
+	case 80:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:262
+//line expr.y:293
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 69:
+	case 81:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:263
+//line expr.y:294
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 70:
+	case 82:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:264
+//line expr.y:295
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 71:
+	case 83:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:265
+//line expr.y:296
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 72:
+	case 84:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:269
+//line expr.y:300
 		{
 			exprVAL.PipelineExpr = MultiStageExpr{exprDollar[1].PipelineStage}
 		}
-	case 73:
+	case 85:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:270
+//line expr.y:301
 		{
 			exprVAL.PipelineExpr = append(exprDollar[1].PipelineExpr, exprDollar[2].PipelineStage)
 		}
-	case 74:
+	case 86:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:274
+//line expr.y:305
 		{
 			exprVAL.PipelineStage = exprDollar[1].LineFilters
 		}
-	case 75:
+	case 87:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:275
+//line expr.y:306
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtParser
 		}
-	case 76:
+	case 88:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:276
+//line expr.y:307
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelParser
 		}
-	case 77:
+	case 89:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:277
+//line expr.y:308
 		{
 			exprVAL.PipelineStage = exprDollar[2].JSONExpressionParser
 		}
-	case 78:
+	case 90:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:278
+//line expr.y:309
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtExpressionParser
 		}
-	case 79:
+	case 91:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:279
+//line expr.y:310
+		{
+			exprVAL.PipelineStage = exprDollar[2].XMLExpressionParser
+		}
+	case 92:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:311
+		{
+			exprVAL.PipelineStage = exprDollar[2].CSVParser
+		}
+	case 93:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:312
 		{
 			exprVAL.PipelineStage = &LabelFilterExpr{LabelFilterer: exprDollar[2].LabelFilter}
 		}
-	case 80:
+	case 94:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:280
+//line expr.y:313
 		{
 			exprVAL.PipelineStage = exprDollar[2].LineFormatExpr
 		}
-	case 81:
+	case 95:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:281
+//line expr.y:314
 		{
 			exprVAL.PipelineStage = exprDollar[2].DecolorizeExpr
 		}
-	case 82:
+	case 96:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:282
+//line expr.y:315
+		{
+			exprVAL.PipelineStage = exprDollar[2].JSONArrayExpr
+		}
+	case 97:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:316
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelFormatExpr
 		}
-	case 83:
+	case 98:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:283
+//line expr.y:317
 		{
 			exprVAL.PipelineStage = exprDollar[2].DropLabelsExpr
 		}
-	case 84:
+	case 99:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:284
+//line expr.y:318
 		{
 			exprVAL.PipelineStage = exprDollar[2].KeepLabelsExpr
 		}
-	case 85:
+	case 100:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:319
+		{
+			exprVAL.PipelineStage = newMacroExpr(exprDollar[2].str)
+		}
+	case 101:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:288
+//line expr.y:323
 		{
 			exprVAL.FilterOp = OpFilterIP
 		}
-	case 86:
+	case 102:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:292
+//line expr.y:327
 		{
 			exprVAL.OrFilter = newLineFilterExpr(log.LineMatchEqual, "", exprDollar[1].str)
 		}
-	case 87:
+	case 103:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:293
+//line expr.y:328
 		{
 			exprVAL.OrFilter = newLineFilterExpr(log.LineMatchEqual, exprDollar[1].FilterOp, exprDollar[3].str)
 		}
-	case 88:
+	case 104:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:294
+//line expr.y:329
 		{
 			exprVAL.OrFilter = newOrLineFilter(newLineFilterExpr(log.LineMatchEqual, "", exprDollar[1].str), exprDollar[3].OrFilter)
 		}
-	case 89:
+	case 105:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:298
+//line expr.y:333
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str)
 		}
-	case 90:
+	case 106:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:299
+//line expr.y:334
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, exprDollar[2].FilterOp, exprDollar[4].str)
 		}
-	case 91:
+	case 107:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:300
+//line expr.y:335
 		{
 			exprVAL.LineFilter = newOrLineFilter(newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str), exprDollar[4].OrFilter)
 		}
-	case 92:
+	case 108:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:304
+//line expr.y:339
 		{
 			exprVAL.LineFilters = exprDollar[1].LineFilter
 		}
-	case 93:
+	case 109:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:305
+//line expr.y:340
 		{
 			exprVAL.LineFilters = newOrLineFilter(exprDollar[1].LineFilter, exprDollar[3].OrFilter)
 		}
-	case 94:
+	case 110:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:306
+//line expr.y:341
 		{
 			exprVAL.LineFilters = newNestedLineFilterExpr(exprDollar[1].LineFilters, exprDollar[2].LineFilter)
 		}
-	case 95:
+	case 111:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:310
+//line expr.y:345
 		{
 			exprVAL.ParserFlags = []string{exprDollar[1].str}
 		}
-	case 96:
+	case 112:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:311
+//line expr.y:346
 		{
 			exprVAL.ParserFlags = append(exprDollar[1].ParserFlags, exprDollar[2].str)
 		}
-	case 97:
+	case 113:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:315
+//line expr.y:350
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(nil)
 		}
-	case 98:
+	case 114:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:316
+//line expr.y:351
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(exprDollar[2].ParserFlags)
 		}
-	case 99:
+	case 115:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:320
+//line expr.y:355
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeJSON, "")
 		}
-	case 100:
+	case 116:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:321
+//line expr.y:356
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeRegexp, exprDollar[2].str)
 		}
-	case 101:
+	case 117:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:322
+//line expr.y:357
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeUnpack, "")
 		}
-	case 102:
+	case 118:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:323
+//line expr.y:358
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypePattern, exprDollar[2].str)
 		}
-	case 103:
+	case 119:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:359
+		{
+			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeXML, "")
+		}
+	case 120:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:360
+		{
+			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeCEF, "")
+		}
+	case 121:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:361
+		{
+			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeLEEF, "")
+		}
+	case 122:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:327
+//line expr.y:365
 		{
 			exprVAL.JSONExpressionParser = newJSONExpressionParser(exprDollar[2].LabelExtractionExpressionList)
 		}
-	case 104:
+	case 123:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:368
+		{
+			exprVAL.XMLExpressionParser = newXMLExpressionParser(exprDollar[2].LabelExtractionExpressionList)
+		}
+	case 124:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:371
+		{
+			exprVAL.CSVParser = newCSVParserExpr(exprDollar[2].str, nil)
+		}
+	case 125:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:372
+		{
+			exprVAL.CSVParser = newCSVParserExpr(exprDollar[2].str, exprDollar[3].CSVOptions)
+		}
+	case 126:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:376
+		{
+			exprVAL.CSVOption = csvOption{name: exprDollar[1].str, value: exprDollar[3].str}
+		}
+	case 127:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:380
+		{
+			exprVAL.CSVOptions = []csvOption{exprDollar[1].CSVOption}
+		}
+	case 128:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:330
+//line expr.y:381
+		{
+			exprVAL.CSVOptions = append(exprDollar[1].CSVOptions, exprDollar[3].CSVOption)
+		}
+	case 129:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:385
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[3].LabelExtractionExpressionList, exprDollar[2].ParserFlags)
 		}
-	case 105:
+	case 130:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:331
+//line expr.y:386
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[2].LabelExtractionExpressionList, nil)
 		}
-	case 106:
+	case 131:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:334
+//line expr.y:389
 		{
 			exprVAL.LineFormatExpr = newLineFmtExpr(exprDollar[2].str)
 		}
-	case 107:
+	case 132:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:336
+//line expr.y:391
 		{
 			exprVAL.DecolorizeExpr = newDecolorizeExpr()
 		}
-	case 108:
+	case 133:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:394
+		{
+			exprVAL.JSONArrayExpr = newJSONArrayExpr("")
+		}
+	case 134:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:395
+		{
+			exprVAL.JSONArrayExpr = newJSONArrayExpr(exprDollar[2].str)
+		}
+	case 135:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:339
+//line expr.y:399
 		{
 			exprVAL.LabelFormat = log.NewRenameLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 109:
+	case 136:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:340
+//line expr.y:400
 		{
 			exprVAL.LabelFormat = log.NewTemplateLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 110:
+	case 137:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:344
+//line expr.y:404
 		{
 			exprVAL.LabelsFormat = []log.LabelFmt{exprDollar[1].LabelFormat}
 		}
-	case 111:
+	case 138:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:345
+//line expr.y:405
 		{
 			exprVAL.LabelsFormat = append(exprDollar[1].LabelsFormat, exprDollar[3].LabelFormat)
 		}
-	case 113:
+	case 140:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:350
+//line expr.y:410
 		{
 			exprVAL.LabelFormatExpr = newLabelFmtExpr(exprDollar[2].LabelsFormat)
 		}
-	case 114:
+	case 141:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:353
+//line expr.y:413
 		{
 			exprVAL.LabelFilter = log.NewStringLabelFilter(exprDollar[1].Matcher)
 		}
-	case 115:
+	case 142:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:354
+//line expr.y:414
 		{
 			exprVAL.LabelFilter = exprDollar[1].IPLabelFilter
 		}
-	case 116:
+	case 143:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:355
+//line expr.y:415
 		{
 			exprVAL.LabelFilter = exprDollar[1].UnitFilter
 		}
-	case 117:
+	case 144:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:356
+//line expr.y:416
 		{
 			exprVAL.LabelFilter = exprDollar[1].NumberFilter
 		}
-	case 118:
+	case 145:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:357
+//line expr.y:417
 		{
 			exprVAL.LabelFilter = exprDollar[2].LabelFilter
 		}
-	case 119:
+	case 146:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:358
+//line expr.y:418
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[2].LabelFilter)
 		}
-	case 120:
+	case 147:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:359
+//line expr.y:419
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 121:
+	case 148:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:360
+//line expr.y:420
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 122:
+	case 149:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:361
+//line expr.y:421
 		{
 			exprVAL.LabelFilter = log.NewOrLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 123:
+	case 150:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:365
+//line expr.y:425
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 124:
+	case 151:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:366
+//line expr.y:426
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[1].str)
 		}
-	case 125:
+	case 152:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:369
+//line expr.y:429
 		{
 			exprVAL.LabelExtractionExpressionList = []log.LabelExtractionExpr{exprDollar[1].LabelExtractionExpression}
 		}
-	case 126:
+	case 153:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:370
+//line expr.y:430
 		{
 			exprVAL.LabelExtractionExpressionList = append(exprDollar[1].LabelExtractionExpressionList, exprDollar[3].LabelExtractionExpression)
 		}
-	case 127:
+	case 154:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:374
+//line expr.y:434
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterEqual)
 		}
-	case 128:
+	case 155:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:375
+//line expr.y:435
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterNotEqual)
 		}
-	case 129:
+	case 156:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:379
+//line expr.y:439
 		{
 			exprVAL.UnitFilter = exprDollar[1].DurationFilter
 		}
-	case 130:
+	case 157:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:380
+//line expr.y:440
 		{
 			exprVAL.UnitFilter = exprDollar[1].BytesFilter
 		}
-	case 131:
+	case 158:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:383
+//line expr.y:443
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 132:
+	case 159:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:384
+//line expr.y:444
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 133:
+	case 160:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:385
+//line expr.y:445
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 134:
+	case 161:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:386
+//line expr.y:446
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 135:
+	case 162:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:387
+//line expr.y:447
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 136:
+	case 163:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:388
+//line expr.y:448
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 137:
+	case 164:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:389
+//line expr.y:449
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 138:
+	case 165:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:393
+//line expr.y:453
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 139:
+	case 166:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:394
+//line expr.y:454
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 140:
+	case 167:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:395
+//line expr.y:455
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 141:
+	case 168:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:396
+//line expr.y:456
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 142:
+	case 169:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:397
+//line expr.y:457
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 143:
+	case 170:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:398
+//line expr.y:458
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 144:
+	case 171:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:399
+//line expr.y:459
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 145:
+	case 172:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:403
+//line expr.y:463
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 146:
+	case 173:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:404
+//line expr.y:464
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 147:
+	case 174:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:405
+//line expr.y:465
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 148:
+	case 175:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:406
+//line expr.y:466
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 149:
+	case 176:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:407
+//line expr.y:467
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 150:
+	case 177:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:408
+//line expr.y:468
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 151:
+	case 178:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:409
+//line expr.y:469
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 152:
+	case 179:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:413
+//line expr.y:473
 		{
 			exprVAL.DropLabel = log.NewDropLabel(nil, exprDollar[1].str)
 		}
-	case 153:
+	case 180:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:414
+//line expr.y:474
 		{
 			exprVAL.DropLabel = log.NewDropLabel(exprDollar[1].Matcher, "")
 		}
-	case 154:
+	case 181:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:417
+//line expr.y:477
 		{
 			exprVAL.DropLabels = []log.DropLabel{exprDollar[1].DropLabel}
 		}
-	case 155:
+	case 182:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:418
+//line expr.y:478
 		{
 			exprVAL.DropLabels = append(exprDollar[1].DropLabels, exprDollar[3].DropLabel)
 		}
-	case 156:
+	case 183:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:421
+//line expr.y:481
 		{
 			exprVAL.DropLabelsExpr = newDropLabelsExpr(exprDollar[2].DropLabels)
 		}
-	case 157:
+	case 184:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:424
+//line expr.y:484
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(nil, exprDollar[1].str)
 		}
-	case 158:
+	case 185:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:425
+//line expr.y:485
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(exprDollar[1].Matcher, "")
 		}
-	case 159:
+	case 186:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:428
+//line expr.y:488
 		{
 			exprVAL.KeepLabels = []log.KeepLabel{exprDollar[1].KeepLabel}
 		}
-	case 160:
+	case 187:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:429
+//line expr.y:489
 		{
 			exprVAL.KeepLabels = append(exprDollar[1].KeepLabels, exprDollar[3].KeepLabel)
 		}
-	case 161:
+	case 188:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:432
+//line expr.y:492
 		{
 			exprVAL.KeepLabelsExpr = newKeepLabelsExpr(exprDollar[2].KeepLabels)
 		}
-	case 162:
+	case 189:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:436
+//line expr.y:496
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("or", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 163:
+	case 190:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:437
+//line expr.y:497
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("and", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 164:
+	case 191:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:438
+//line expr.y:498
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("unless", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 165:
+	case 192:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:439
+//line expr.y:499
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("+", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 166:
+	case 193:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:440
+//line expr.y:500
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("-", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 167:
+	case 194:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:441
+//line expr.y:501
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("*", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 168:
+	case 195:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:442
+//line expr.y:502
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("/", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 169:
+	case 196:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:443
+//line expr.y:503
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("%", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 170:
+	case 197:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:444
+//line expr.y:504
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("^", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 171:
+	case 198:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:445
+//line expr.y:505
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("==", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 172:
+	case 199:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:446
+//line expr.y:506
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("!=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 173:
+	case 200:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:447
+//line expr.y:507
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 174:
+	case 201:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:448
+//line expr.y:508
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 175:
+	case 202:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:449
+//line expr.y:509
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 176:
+	case 203:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:450
+//line expr.y:510
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 177:
+	case 204:
 		exprDollar = exprS[exprpt-0 : exprpt+1]
-//line expr.y:454
+//line expr.y:514
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}
 		}
-	case 178:
+	case 205:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:458
+//line expr.y:518
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}, ReturnBool: true}
 		}
-	case 179:
+	case 206:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:465
+//line expr.y:525
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 180:
+	case 207:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:471
+//line expr.y:531
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 		}
-	case 181:
+	case 208:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:476
+//line expr.y:536
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 182:
+	case 209:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:481
+//line expr.y:541
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 		}
-	case 183:
+	case 210:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:487
+//line expr.y:547
 		{
 			exprVAL.BinOpModifier = exprDollar[1].BoolModifier
 		}
-	case 184:
+	case 211:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:488
+//line expr.y:548
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 		}
-	case 185:
+	case 212:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:490
+//line expr.y:550
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 186:
+	case 213:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:495
+//line expr.y:555
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 187:
+	case 214:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:500
+//line expr.y:560
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 188:
+	case 215:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:506
+//line expr.y:566
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 189:
+	case 216:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:511
+//line expr.y:571
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 190:
+	case 217:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:516
+//line expr.y:576
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 191:
+	case 218:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:524
+//line expr.y:584
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[1].str, false)
 		}
-	case 192:
+	case 219:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:525
+//line expr.y:585
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, false)
 		}
-	case 193:
+	case 220:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:526
+//line expr.y:586
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, true)
 		}
-	case 194:
+	case 221:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:530
+//line expr.y:590
 		{
 			exprVAL.VectorExpr = NewVectorExpr(exprDollar[3].str)
 		}
-	case 195:
+	case 222:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:533
+//line expr.y:593
 		{
 			exprVAL.Vector = OpTypeVector
 		}
-	case 196:
+	case 223:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:537
+//line expr.y:597
 		{
 			exprVAL.VectorOp = OpTypeSum
 		}
-	case 197:
+	case 224:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:538
+//line expr.y:598
 		{
 			exprVAL.VectorOp = OpTypeAvg
 		}
-	case 198:
+	case 225:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:539
+//line expr.y:599
 		{
 			exprVAL.VectorOp = OpTypeCount
 		}
-	case 199:
+	case 226:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:540
+//line expr.y:600
 		{
 			exprVAL.VectorOp = OpTypeMax
 		}
-	case 200:
+	case 227:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:541
+//line expr.y:601
 		{
 			exprVAL.VectorOp = OpTypeMin
 		}
-	case 201:
+	case 228:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:542
+//line expr.y:602
 		{
 			exprVAL.VectorOp = OpTypeStddev
 		}
-	case 202:
+	case 229:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:543
+//line expr.y:603
 		{
 			exprVAL.VectorOp = OpTypeStdvar
 		}
-	case 203:
+	case 230:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:544
+//line expr.y:604
 		{
 			exprVAL.VectorOp = OpTypeBottomK
 		}
-	case 204:
+	case 231:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:545
+//line expr.y:605
 		{
 			exprVAL.VectorOp = OpTypeTopK
 		}
-	case 205:
+	case 232:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:546
+//line expr.y:606
 		{
 			exprVAL.VectorOp = OpTypeSort
 		}
-	case 206:
+	case 233:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:547
+//line expr.y:607
 		{
 			exprVAL.VectorOp = OpTypeSortDesc
 		}
-	case 207:
+	case 234:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:551
+//line expr.y:611
 		{
 			exprVAL.RangeOp = OpRangeTypeCount
 		}
-	case 208:
+	case 235:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:552
+//line expr.y:612
 		{
 			exprVAL.RangeOp = OpRangeTypeRate
 		}
-	case 209:
+	case 236:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:553
+//line expr.y:613
 		{
 			exprVAL.RangeOp = OpRangeTypeRateCounter
 		}
-	case 210:
+	case 237:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:554
+//line expr.y:614
 		{
 			exprVAL.RangeOp = OpRangeTypeBytes
 		}
-	case 211:
+	case 238:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:555
+//line expr.y:615
 		{
 			exprVAL.RangeOp = OpRangeTypeBytesRate
 		}
-	case 212:
+	case 239:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:556
+//line expr.y:616
 		{
 			exprVAL.RangeOp = OpRangeTypeAvg
 		}
-	case 213:
+	case 240:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:557
+//line expr.y:617
 		{
 			exprVAL.RangeOp = OpRangeTypeSum
 		}
-	case 214:
+	case 241:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:558
+//line expr.y:618
 		{
 			exprVAL.RangeOp = OpRangeTypeMin
 		}
-	case 215:
+	case 242:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:559
+//line expr.y:619
 		{
 			exprVAL.RangeOp = OpRangeTypeMax
 		}
-	case 216:
+	case 243:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:560
+//line expr.y:620
 		{
 			exprVAL.RangeOp = OpRangeTypeStdvar
 		}
-	case 217:
+	case 244:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:561
+//line expr.y:621
 		{
 			exprVAL.RangeOp = OpRangeTypeStddev
 		}
-	case 218:
+	case 245:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:562
+//line expr.y:622
 		{
 			exprVAL.RangeOp = OpRangeTypeQuantile
 		}
-	case 219:
+	case 246:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:563
+//line expr.y:623
 		{
 			exprVAL.RangeOp = OpRangeTypeFirst
 		}
-	case 220:
+	case 247:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:564
+//line expr.y:624
 		{
 			exprVAL.RangeOp = OpRangeTypeLast
 		}
-	case 221:
+	case 248:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:565
+//line expr.y:625
 		{
 			exprVAL.RangeOp = OpRangeTypeAbsent
 		}
-	case 222:
+	case 249:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:626
+		{
+			exprVAL.RangeOp = OpRangeTypeHistogram
+		}
+	case 250:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:627
+		{
+			exprVAL.RangeOp = OpRangeTypeCountDistinct
+		}
+	case 251:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:628
+		{
+			exprVAL.RangeOp = OpRangeTypeDeriv
+		}
+	case 252:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:629
+		{
+			exprVAL.RangeOp = OpRangeTypeDelta
+		}
+	case 253:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:630
+		{
+			exprVAL.RangeOp = OpRangeTypeChanges
+		}
+	case 254:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:631
+		{
+			exprVAL.RangeOp = OpRangeTypeResets
+		}
+	case 255:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:632
+		{
+			exprVAL.RangeOp = OpRangeTypePredictLinear
+		}
+	case 256:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:633
+		{
+			exprVAL.RangeOp = OpRangeTypeHoltWinters
+		}
+	case 257:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:569
+//line expr.y:637
 		{
 			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[2].duration)
 		}
-	case 223:
+	case 258:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:572
+//line expr.y:640
 		{
 			exprVAL.Labels = []string{exprDollar[1].str}
 		}
-	case 224:
+	case 259:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:573
+//line expr.y:641
 		{
 			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
 		}
-	case 225:
+	case 260:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:577
+//line expr.y:645
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: exprDollar[3].Labels}
 		}
-	case 226:
+	case 261:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:578
+//line expr.y:646
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: exprDollar[3].Labels}
 		}
-	case 227:
+	case 262:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:579
+//line expr.y:647
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: nil}
 		}
-	case 228:
+	case 263:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:580
+//line expr.y:648
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: nil}
 		}
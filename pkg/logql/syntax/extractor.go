@@ -48,7 +48,7 @@ func (r RangeAggregationExpr) extractor(override *Grouping) (log.SampleExtractor
 	var stages []log.Stage
 	if p, ok := r.Left.Left.(*PipelineExpr); ok {
 		// if the expression is a pipeline then take all stages into account first.
-		st, err := p.MultiStages.stages()
+		st, err := p.MultiStages.stages(p.recorder)
 		if err != nil {
 			return nil, err
 		}
@@ -57,10 +57,12 @@ func (r RangeAggregationExpr) extractor(override *Grouping) (log.SampleExtractor
 	// unwrap...means we want to extract metrics from labels.
 	if r.Left.Unwrap != nil {
 		var convOp string
-		switch r.Left.Unwrap.Operation {
-		case OpConvBytes:
+		switch {
+		case r.Operation == OpRangeTypeCountDistinct || r.Operation == OpRangeTypeCountDistinctSketch:
+			convOp = log.ConvertHash
+		case r.Left.Unwrap.Operation == OpConvBytes:
 			convOp = log.ConvertBytes
-		case OpConvDuration, OpConvDurationSeconds:
+		case r.Left.Unwrap.Operation == OpConvDuration || r.Left.Unwrap.Operation == OpConvDurationSeconds:
 			convOp = log.ConvertDuration
 		default:
 			convOp = log.ConvertFloat
@@ -82,6 +82,19 @@ var tokens = map[string]int{
 	OpKeep: KEEP,
 }
 
+// pipelineTokens are tokens that are only keywords when they start a pipeline stage, e.g. `| xml`,
+// so they can still be used as label names, e.g. `| xml="x"` or `sum by (xml)`.
+var pipelineTokens = map[string]int{
+	// parsers
+	OpParserTypeXML:  XML,
+	OpParserTypeCSV:  CSV,
+	OpParserTypeCEF:  CEF,
+	OpParserTypeLEEF: LEEF,
+
+	// explode
+	OpJSONArray: JSON_ARRAY,
+}
+
 var parserFlags = map[string]struct{}{
 	OpStrict:    {},
 	OpKeepEmpty: {},
@@ -90,22 +103,30 @@ var parserFlags = map[string]struct{}{
 // functionTokens are tokens that needs to be suffixes with parenthesis
 var functionTokens = map[string]int{
 	// range vec ops
-	OpRangeTypeRate:        RATE,
-	OpRangeTypeRateCounter: RATE_COUNTER,
-	OpRangeTypeCount:       COUNT_OVER_TIME,
-	OpRangeTypeBytesRate:   BYTES_RATE,
-	OpRangeTypeBytes:       BYTES_OVER_TIME,
-	OpRangeTypeAvg:         AVG_OVER_TIME,
-	OpRangeTypeSum:         SUM_OVER_TIME,
-	OpRangeTypeMin:         MIN_OVER_TIME,
-	OpRangeTypeMax:         MAX_OVER_TIME,
-	OpRangeTypeStdvar:      STDVAR_OVER_TIME,
-	OpRangeTypeStddev:      STDDEV_OVER_TIME,
-	OpRangeTypeQuantile:    QUANTILE_OVER_TIME,
-	OpRangeTypeFirst:       FIRST_OVER_TIME,
-	OpRangeTypeLast:        LAST_OVER_TIME,
-	OpRangeTypeAbsent:      ABSENT_OVER_TIME,
-	OpTypeVector:           VECTOR,
+	OpRangeTypeRate:          RATE,
+	OpRangeTypeRateCounter:   RATE_COUNTER,
+	OpRangeTypeCount:         COUNT_OVER_TIME,
+	OpRangeTypeBytesRate:     BYTES_RATE,
+	OpRangeTypeBytes:         BYTES_OVER_TIME,
+	OpRangeTypeAvg:           AVG_OVER_TIME,
+	OpRangeTypeSum:           SUM_OVER_TIME,
+	OpRangeTypeMin:           MIN_OVER_TIME,
+	OpRangeTypeMax:           MAX_OVER_TIME,
+	OpRangeTypeStdvar:        STDVAR_OVER_TIME,
+	OpRangeTypeStddev:        STDDEV_OVER_TIME,
+	OpRangeTypeQuantile:      QUANTILE_OVER_TIME,
+	OpRangeTypeFirst:         FIRST_OVER_TIME,
+	OpRangeTypeLast:          LAST_OVER_TIME,
+	OpRangeTypeAbsent:        ABSENT_OVER_TIME,
+	OpRangeTypeHistogram:     HISTOGRAM_OVER_TIME,
+	OpRangeTypeCountDistinct: COUNT_DISTINCT_OVER_TIME,
+	OpRangeTypeDeriv:         DERIV,
+	OpRangeTypeDelta:         DELTA,
+	OpRangeTypeChanges:       CHANGES,
+	OpRangeTypeResets:        RESETS,
+	OpRangeTypePredictLinear: PREDICT_LINEAR,
+	OpRangeTypeHoltWinters:   HOLT_WINTERS,
+	OpTypeVector:             VECTOR,
 
 	// vec ops
 	OpTypeSum:      SUM,
@@ -128,15 +149,27 @@ var functionTokens = map[string]int{
 
 	// filterOp
 	OpFilterIP: IP,
+
+	// joins, the operation is the value of the token.
+	OpJoin:          JOIN,
+	OpJoinUnmatched: JOIN,
 }
 
 type lexer struct {
 	Scanner
 	errs    []logqlmodel.ParseError
 	builder strings.Builder
+	// lastTok is the last token returned, for the tokens depending on the previous one.
+	lastTok int
 }
 
 func (l *lexer) Lex(lval *exprSymType) int {
+	tok := l.lex(lval)
+	l.lastTok = tok
+	return tok
+}
+
+func (l *lexer) lex(lval *exprSymType) int {
 	r := l.Scan()
 
 	switch r {
@@ -182,6 +215,14 @@ func (l *lexer) Lex(lval *exprSymType) int {
 			return DURATION
 		}
 
+	case '@': // pipeline macros
+		if name, ok := tryScanMacro(&l.Scanner); ok {
+			lval.str = name
+			return MACRO
+		}
+		l.Error("invalid pipeline macro name")
+		return 0
+
 	case scanner.String, scanner.RawString:
 		var err error
 		tokenText := l.TokenText()
@@ -202,6 +243,11 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		l.builder.Reset()
 		for r := l.Next(); r != scanner.EOF; r = l.Next() {
 			if r == ']' {
+				// subquery ranges are parsed with their step, e.g. [1h:1m]
+				if strings.ContainsRune(l.builder.String(), ':') {
+					lval.str = l.builder.String()
+					return SUBQUERY_RANGE
+				}
 				i, err := model.ParseDuration(l.builder.String())
 				if err != nil {
 					l.Error(err.Error())
@@ -235,6 +281,11 @@ func (l *lexer) Lex(lval *exprSymType) int {
 			lval.str = tokenText
 			return IDENTIFIER
 		}
+		lval.str = tokenTextLower
+		return tok
+	}
+
+	if tok, ok := pipelineTokens[tokenTextLower]; ok && l.lastTok == PIPE && !isLabelFilter(l.Scanner) {
 		return tok
 	}
 
@@ -284,6 +335,20 @@ func tryScanFlag(l *Scanner) (string, bool) {
 	return flag, true
 }
 
+// tryScanMacro scans the name of a pipeline macro following the `@` sign.
+// A name starts with a letter or an underscore, followed by letters, digits or underscores.
+func tryScanMacro(l *Scanner) (string, bool) {
+	var sb strings.Builder
+	for r := l.Peek(); isMacroRune(r, sb.Len() == 0); r = l.Peek() {
+		_, _ = sb.WriteRune(l.Next())
+	}
+	return sb.String(), sb.Len() > 0
+}
+
+func isMacroRune(r rune, first bool) bool {
+	return r == '_' || unicode.IsLetter(r) || (!first && unicode.IsDigit(r))
+}
+
 func tryScanDuration(number string, l *Scanner) (time.Duration, bool) {
 	var sb strings.Builder
 	sb.WriteString(number)
@@ -405,6 +470,16 @@ func isFunction(sc Scanner) bool {
 	return false
 }
 
+// isLabelFilter returns whether the next token is a comparison operator, as in the label filter `| xml="x"`.
+func isLabelFilter(sc Scanner) bool {
+	sc = trimSpace(sc)
+	switch sc.Peek() {
+	case '=', '!', '<', '>':
+		return true
+	}
+	return false
+}
+
 func trimSpace(l Scanner) Scanner {
 	for n := l.Peek(); n != scanner.EOF; n = l.Peek() {
 		if unicode.IsSpace(n) {
@@ -0,0 +1,113 @@
+package syntax
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+// macroSelector is the stream selector used to parse macro definitions, which are pipelines without a selector.
+const macroSelector = `{__macro__="macro"}`
+
+// ValidateMacroName checks that the name can be used to reference a macro with `| @name`.
+func ValidateMacroName(name string) error {
+	if name == "" {
+		return fmt.Errorf("macro name cannot be empty")
+	}
+	for i, r := range name {
+		if !isMacroRune(r, i == 0) {
+			return fmt.Errorf("invalid macro name %q: must start with a letter or an underscore followed by letters, digits or underscores", name)
+		}
+	}
+	return nil
+}
+
+// ParseMacro parses the definition of a pipeline macro, e.g. `| pattern "<ip> <_>" | label_format ...`.
+// A definition is a non-empty pipeline and cannot reference other macros.
+func ParseMacro(definition string) (MultiStageExpr, error) {
+	expr, err := ParseExprWithoutValidation(macroSelector + " " + definition)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, ok := expr.(*PipelineExpr)
+	if !ok || pipeline.Left.String() != macroSelector {
+		return nil, errors.New("macro definition must be a log pipeline")
+	}
+	for _, s := range pipeline.MultiStages {
+		if m, ok := s.(*MacroExpr); ok {
+			return nil, fmt.Errorf("macro definition cannot reference macro %s%s", OpMacro, m.Name)
+		}
+	}
+	return pipeline.MultiStages, nil
+}
+
+// MacroNames returns the sorted names of the macros referenced by the expression.
+func MacroNames(expr Expr) []string {
+	var names []string
+	expr.Walk(func(e Expr) {
+		m, ok := e.(*MacroExpr)
+		if !ok {
+			return
+		}
+		for _, n := range names {
+			if n == m.Name {
+				return
+			}
+		}
+		names = append(names, m.Name)
+	})
+	sort.Strings(names)
+	return names
+}
+
+// HasMacros tells if the query may reference macros, without parsing it.
+func HasMacros(query string) bool {
+	return strings.Contains(query, OpMacro)
+}
+
+// ExpandMacros replaces each macro referenced by the expression with the stages of its definition.
+// The expression is not modified, the expanded expression is parsed and validated again.
+func ExpandMacros(expr Expr, definitions map[string]string) (Expr, error) {
+	names := MacroNames(expr)
+	if len(names) == 0 {
+		return expr, nil
+	}
+
+	stages := make(map[string]MultiStageExpr, len(names))
+	for _, name := range names {
+		definition, ok := definitions[name]
+		if !ok {
+			return nil, logqlmodel.NewParseError(fmt.Sprintf("unknown pipeline macro %s%s", OpMacro, name), 0, 0)
+		}
+		s, err := ParseMacro(definition)
+		if err != nil {
+			return nil, logqlmodel.NewParseError(fmt.Sprintf("invalid pipeline macro %s%s: %s", OpMacro, name, err.Error()), 0, 0)
+		}
+		stages[name] = s
+	}
+
+	expanded, err := Clone(expr)
+	if err != nil {
+		return nil, err
+	}
+	expanded.Walk(func(e Expr) {
+		p, ok := e.(*PipelineExpr)
+		if !ok {
+			return
+		}
+		result := make(MultiStageExpr, 0, len(p.MultiStages))
+		for _, s := range p.MultiStages {
+			if m, ok := s.(*MacroExpr); ok {
+				result = append(result, stages[m.Name]...)
+				continue
+			}
+			result = append(result, s)
+		}
+		p.MultiStages = result
+	})
+
+	return ParseExpr(expanded.String())
+}
@@ -0,0 +1,121 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMacroReference(t *testing.T) {
+	expr, err := ParseExpr(`sum by (status) (count_over_time({app="nginx"} |= "GET" | @nginx_parse | status >= 500 [5m]))`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"nginx_parse"}, MacroNames(expr))
+	require.Equal(t, `sum by (status)(count_over_time({app="nginx"} |= "GET" | @nginx_parse | status>=500[5m]))`, expr.String())
+
+	p, err := expr.(SampleExpr).Selector()
+	require.NoError(t, err)
+	_, err = p.Pipeline()
+	require.EqualError(t, err, "parse error : stage '| @nginx_parse' : pipeline macro @nginx_parse was not expanded")
+
+	for _, q := range []string{`{app="foo"} | @`, `{app="foo"} | @1abc`, `{app="foo"} |@-abc`} {
+		_, err := ParseExpr(q)
+		require.Error(t, err, q)
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	definitions := map[string]string{
+		"nginx_parse": `| pattern "<ip> - - <_> \"<method> <path> <_>\" <status> <_>" | label_format method="{{ ToLower .method }}"`,
+		"errors":      `|= "error" | json`,
+	}
+
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{
+			`{app="nginx"} | @nginx_parse | status >= 500`,
+			`{app="nginx"} | pattern "<ip> - - <_> \"<method> <path> <_>\" <status> <_>" | label_format method="{{ ToLower .method }}" | status>=500`,
+		},
+		{
+			`sum(rate({app="nginx"} | @errors [1m])) / sum(rate({app="nginx"} | @nginx_parse | @errors [1m]))`,
+			`(sum(rate({app="nginx"} |= "error" | json[1m])) / sum(rate({app="nginx"} | pattern "<ip> - - <_> \"<method> <path> <_>\" <status> <_>" | label_format method="{{ ToLower .method }}" |= "error" | json[1m])))`,
+		},
+		{
+			`{app="nginx"} |= "@foo"`,
+			`{app="nginx"} |= "@foo"`,
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := ParseExpr(tc.query)
+			require.NoError(t, err)
+			original := expr.String()
+
+			expanded, err := ExpandMacros(expr, definitions)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, expanded.String())
+			require.Empty(t, MacroNames(expanded))
+			require.Equal(t, original, expr.String())
+		})
+	}
+}
+
+func TestExpandMacrosErrors(t *testing.T) {
+	for _, tc := range []struct {
+		query       string
+		definitions map[string]string
+		err         string
+	}{
+		{
+			`{app="foo"} | @parse`,
+			nil,
+			"parse error : unknown pipeline macro @parse",
+		},
+		{
+			`{app="foo"} | @parse`,
+			map[string]string{"parse": `| json | @other`},
+			"parse error : invalid pipeline macro @parse: macro definition cannot reference macro @other",
+		},
+		{
+			`{app="foo"} | @parse`,
+			map[string]string{"parse": `{app="bar"}`},
+			"parse error : invalid pipeline macro @parse: parse error at line 1, col 21: syntax error: unexpected {",
+		},
+		{
+			`{app="foo"} | json_array | @parse`,
+			map[string]string{"parse": `| json_array "errors"`},
+			"parse error : only one json_array stage is allowed per pipeline",
+		},
+	} {
+		expr, err := ParseExpr(tc.query)
+		require.NoError(t, err)
+		_, err = ExpandMacros(expr, tc.definitions)
+		require.EqualError(t, err, tc.err)
+	}
+}
+
+func TestParseMacro(t *testing.T) {
+	stages, err := ParseMacro(`|= "foo" | logfmt | level="error"`)
+	require.NoError(t, err)
+	require.Equal(t, `|= "foo" | logfmt | level="error"`, stages.String())
+
+	for _, definition := range []string{
+		``,
+		`| json [5m]`,
+		`| json or {app="bar"}`,
+		`| unknown`,
+		`| @other`,
+	} {
+		_, err := ParseMacro(definition)
+		require.Error(t, err, definition)
+	}
+}
+
+func TestValidateMacroName(t *testing.T) {
+	for _, name := range []string{"nginx_parse", "_private", "v2"} {
+		require.NoError(t, ValidateMacroName(name))
+	}
+	for _, name := range []string{"", "2fast", "nginx-parse", "a b"} {
+		require.Error(t, ValidateMacroName(name))
+	}
+}
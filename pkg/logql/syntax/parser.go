@@ -47,6 +47,9 @@ func init() {
 	for str, tok := range tokens {
 		exprToknames[tok-exprPrivate+1] = str
 	}
+	for str, tok := range pipelineTokens {
+		exprToknames[tok-exprPrivate+1] = str
+	}
 }
 
 type parser struct {
@@ -58,6 +61,7 @@ type parser struct {
 
 func (p *parser) Parse() (Expr, error) {
 	p.lexer.errs = p.lexer.errs[:0]
+	p.lexer.lastTok = 0
 	p.lexer.Scanner.Error = func(_ *Scanner, msg string) {
 		p.lexer.Error(msg)
 	}
@@ -181,6 +185,9 @@ func validateSampleExpr(expr SampleExpr) error {
 		if e.err != nil {
 			return e.err
 		}
+		if isHistogramExpr(e.SampleExpr) || isHistogramExpr(e.RHS) {
+			return logqlmodel.NewParseError(fmt.Sprintf("binary operations are not supported on %s", OpRangeTypeHistogram), 0, 0)
+		}
 		if err := validateSampleExpr(e.SampleExpr); err != nil {
 			return err
 		}
@@ -204,7 +211,15 @@ func validateSampleExpr(expr SampleExpr) error {
 				return err
 			}
 		}
+		if e.Operation != OpTypeSum && isHistogramExpr(e.Left) {
+			return logqlmodel.NewParseError(fmt.Sprintf("%s aggregation is not supported on %s, only %s is", e.Operation, OpRangeTypeHistogram, OpTypeSum), 0, 0)
+		}
 		return validateSampleExpr(e.Left)
+	case *SubqueryAggregationExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left.Left)
 	default:
 		selector, err := e.Selector()
 		if err != nil {
@@ -214,10 +229,29 @@ func validateSampleExpr(expr SampleExpr) error {
 	}
 }
 
+// isHistogramExpr tells if the expression evaluates to native histograms instead of floats.
+func isHistogramExpr(expr SampleExpr) bool {
+	switch e := expr.(type) {
+	case *RangeAggregationExpr:
+		return e.Operation == OpRangeTypeHistogram
+	case *VectorAggregationExpr:
+		return e.Operation == OpTypeSum && isHistogramExpr(e.Left)
+	case *LabelReplaceExpr:
+		return isHistogramExpr(e.Left)
+	default:
+		return false
+	}
+}
+
 func validateLogSelectorExpression(expr LogSelectorExpr) error {
 	switch e := expr.(type) {
 	case *VectorExpr:
 		return nil
+	case *JoinExpr:
+		if err := validateLogSelectorExpression(e.Left); err != nil {
+			return err
+		}
+		return validateLogSelectorExpression(e.Right)
 	default:
 		return validateMatchers(e.Matchers())
 	}
@@ -1860,6 +1860,223 @@ var ParseTestCases = []struct {
 			OpRangeTypeMax, &Grouping{Without: true, Groups: []string{"foo", "bar"}}, nil,
 		),
 	},
+	{
+		in: `histogram_over_time({app="foo"} | unwrap latency [5m]) by (cluster)`,
+		exp: newRangeAggregationExpr(
+			newLogRange(
+				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+				5*time.Minute,
+				newUnwrapExpr("latency", ""),
+				nil),
+			OpRangeTypeHistogram, &Grouping{Groups: []string{"cluster"}}, nil,
+		),
+	},
+	{
+		in: `sum by (cluster) (histogram_over_time({app="foo"} | unwrap latency [5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				newLogRange(
+					newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+					5*time.Minute,
+					newUnwrapExpr("latency", ""),
+					nil),
+				OpRangeTypeHistogram, nil, nil,
+			),
+			OpTypeSum, &Grouping{Groups: []string{"cluster"}}, nil,
+		),
+	},
+	{
+		in:  `histogram_over_time({app="foo"} [5m])`,
+		err: logqlmodel.NewParseError("invalid aggregation histogram_over_time without unwrap", 0, 0),
+	},
+	{
+		in:  `max(histogram_over_time({app="foo"} | unwrap latency [5m]))`,
+		err: logqlmodel.NewParseError("max aggregation is not supported on histogram_over_time, only sum is", 0, 0),
+	},
+	{
+		in:  `sum(histogram_over_time({app="foo"} | unwrap latency [5m])) / 2`,
+		err: logqlmodel.NewParseError("binary operations are not supported on histogram_over_time", 0, 0),
+	},
+	{
+		in: `count_distinct_over_time({app="foo"} | logfmt | unwrap user [5m]) by (cluster)`,
+		exp: newRangeAggregationExpr(
+			newLogRange(
+				newPipelineExpr(
+					newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+					MultiStageExpr{newLogfmtParserExpr(nil)},
+				),
+				5*time.Minute,
+				newUnwrapExpr("user", ""),
+				nil),
+			OpRangeTypeCountDistinct, &Grouping{Groups: []string{"cluster"}}, nil,
+		),
+	},
+	{
+		in:  `count_distinct_over_time({app="foo"} [5m])`,
+		err: logqlmodel.NewParseError("invalid aggregation count_distinct_over_time without unwrap", 0, 0),
+	},
+	{
+		in:  `count_distinct_over_time({app="foo"} | unwrap bytes(size) [5m])`,
+		err: logqlmodel.NewParseError("conversion function bytes not supported for count_distinct_over_time aggregation", 0, 0),
+	},
+	{
+		in: `deriv({app="foo"} | unwrap bytes [5m])`,
+		exp: newRangeAggregationExpr(
+			newLogRange(
+				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+				5*time.Minute,
+				newUnwrapExpr("bytes", ""),
+				nil),
+			OpRangeTypeDeriv, nil, nil,
+		),
+	},
+	{
+		in: `sum by (cluster) (changes({app="foo"} | logfmt | unwrap status [5m] offset 1m))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				newLogRange(
+					newPipelineExpr(
+						newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+						MultiStageExpr{newLogfmtParserExpr(nil)},
+					),
+					5*time.Minute,
+					newUnwrapExpr("status", ""),
+					newOffsetExpr(time.Minute)),
+				OpRangeTypeChanges, nil, nil,
+			),
+			OpTypeSum, &Grouping{Groups: []string{"cluster"}}, nil,
+		),
+	},
+	{
+		in: `predict_linear({app="foo"} | unwrap bytes(size) [1h], 14400)`,
+		exp: newRangeAggregationExprWithArgs(
+			newLogRange(
+				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+				time.Hour,
+				newUnwrapExpr("size", OpConvBytes),
+				nil),
+			OpRangeTypePredictLinear, "14400",
+		),
+	},
+	{
+		in: `holt_winters({app="foo"} | unwrap latency [10m], 0.3, 0.7)`,
+		exp: newRangeAggregationExprWithArgs(
+			newLogRange(
+				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+				10*time.Minute,
+				newUnwrapExpr("latency", ""),
+				nil),
+			OpRangeTypeHoltWinters, "0.3", "0.7",
+		),
+	},
+	{
+		in:  `delta({app="foo"} [5m])`,
+		err: logqlmodel.NewParseError("invalid aggregation delta without unwrap", 0, 0),
+	},
+	{
+		in:  `resets({app="foo"} | unwrap bytes [5m]) by (cluster)`,
+		err: logqlmodel.NewParseError("grouping not allowed for resets aggregation", 0, 0),
+	},
+	{
+		in:  `predict_linear({app="foo"} | unwrap bytes [5m])`,
+		err: logqlmodel.NewParseError("parameter required for operation predict_linear", 0, 0),
+	},
+	{
+		in:  `deriv({app="foo"} | unwrap bytes [5m], 60)`,
+		err: logqlmodel.NewParseError("operation deriv expects 0 parameter(s) after the range, got 1", 0, 0),
+	},
+	{
+		in:  `holt_winters({app="foo"} | unwrap latency [10m], 1, 0.7)`,
+		err: logqlmodel.NewParseError("invalid smoothing factor for operation holt_winters, expected 0 < sf < 1, got 1", 0, 0),
+	},
+	{
+		in: `max_over_time(rate({app="foo"} |= "error" [1m])[1h:1m])`,
+		exp: newSubqueryAggregationExpr(
+			newSubqueryExpr(
+				newRangeAggregationExpr(
+					newLogRange(
+						newPipelineExpr(
+							newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+							MultiStageExpr{newLineFilterExpr(log.LineMatchEqual, "", "error")},
+						),
+						time.Minute, nil, nil),
+					OpRangeTypeRate, nil, nil,
+				),
+				"1h:1m", nil,
+			),
+			OpRangeTypeMax, nil,
+		),
+	},
+	{
+		in: `quantile_over_time(0.99, sum by (cluster) (count_over_time({app="foo"}[5m]))[1d:] offset 1h)`,
+		exp: newSubqueryAggregationExpr(
+			newSubqueryExpr(
+				mustNewVectorAggregationExpr(
+					newRangeAggregationExpr(
+						newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil, nil),
+						OpRangeTypeCount, nil, nil,
+					),
+					OpTypeSum, &Grouping{Groups: []string{"cluster"}}, nil,
+				),
+				"1d:", newOffsetExpr(time.Hour),
+			),
+			OpRangeTypeQuantile, NewStringLabelFilter("0.99"),
+		),
+	},
+	{
+		in: `holt_winters(max_over_time(rate({app="foo"}[1m])[1h:1m])[1d:1h], 0.3, 0.7)`,
+		exp: newSubqueryAggregationExpr(
+			newSubqueryExpr(
+				newSubqueryAggregationExpr(
+					newSubqueryExpr(
+						newRangeAggregationExpr(
+							newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), time.Minute, nil, nil),
+							OpRangeTypeRate, nil, nil,
+						),
+						"1h:1m", nil,
+					),
+					OpRangeTypeMax, nil,
+				),
+				"1d:1h", nil,
+			),
+			OpRangeTypeHoltWinters, nil, "0.3", "0.7",
+		),
+	},
+	{
+		in: `max_over_time((1 - sum(rate({app="foo"}[1m])))[1h:1m])`,
+		exp: newSubqueryAggregationExpr(
+			newSubqueryExpr(
+				mustNewBinOpExpr(OpTypeSub, &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}},
+					mustNewLiteralExpr("1", false),
+					mustNewVectorAggregationExpr(
+						newRangeAggregationExpr(
+							newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), time.Minute, nil, nil),
+							OpRangeTypeRate, nil, nil,
+						),
+						OpTypeSum, nil, nil,
+					),
+				),
+				"1h:1m", nil,
+			),
+			OpRangeTypeMax, nil,
+		),
+	},
+	{
+		in:  `rate(rate({app="foo"}[1m])[1h:1m])`,
+		err: logqlmodel.NewParseError("invalid aggregation rate of a subquery", 0, 0),
+	},
+	{
+		in:  `max_over_time(rate({app="foo"}[1m])[1h:1x])`,
+		err: logqlmodel.NewParseError(`invalid subquery step: unknown unit "x" in duration "1x"`, 0, 0),
+	},
+	{
+		in:  `max_over_time(rate({app="foo"}[1m])[0s:1m])`,
+		err: logqlmodel.NewParseError("subquery range must be positive", 0, 0),
+	},
+	{
+		in:  `max_over_time(histogram_over_time({app="foo"} | unwrap bytes [1m])[1h:1m])`,
+		err: logqlmodel.NewParseError("subqueries are not supported on histogram_over_time", 0, 0),
+	},
 	{
 		in: `max_over_time({app="foo"} | unwrap bar [5m] offset 5m) without (foo,bar)`,
 		exp: newRangeAggregationExpr(
@@ -2889,7 +3106,7 @@ var ParseTestCases = []struct {
 	},
 	{
 		in:  `quantile_over_time(foo,{namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms| unwrap latency [5m])`,
-		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or { or (", 1, 20),
+		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER", 1, 20),
 	},
 	{
 		in:  `vector(abc)`,
@@ -3137,6 +3354,204 @@ var ParseTestCases = []struct {
 			},
 		},
 	},
+	{
+		in: `{app="foo"} | xml`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeXML, ""),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | xml status="/response/@code", msg="/response/message"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newXMLExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("status", `/response/@code`),
+					log.NewLabelExtractionExpr("msg", `/response/message`),
+				}),
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | xml status="response/@code"`,
+		err: logqlmodel.NewParseError("invalid xml parser: cannot parse expression [response/@code]: xml path must be absolute: response/@code", 0, 0),
+	},
+	{
+		in: `{app="foo"} | cef | severity > 7`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeCEF, ""),
+				&LabelFilterExpr{
+					LabelFilterer: log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "severity", 7),
+				},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | leef`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeLEEF, ""),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | csv "ts,method,status"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&CSVParserExpr{
+					Columns:   []string{"ts", "method", "status"},
+					Delimiter: ',',
+					Quote:     '"',
+				},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | csv "ts,method,status" delimiter="\t", quote=""`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&CSVParserExpr{
+					Columns:   []string{"ts", "method", "status"},
+					Delimiter: '\t',
+				},
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | csv "ts,method" separator=";"`,
+		err: logqlmodel.NewParseError("invalid csv parser: unknown option separator", 0, 0),
+	},
+	{
+		in:  `{app="foo"} | csv "ts,method" delimiter=";;"`,
+		err: logqlmodel.NewParseError("invalid csv parser: delimiter must be a single character", 0, 0),
+	},
+	{
+		in: `{app="foo"} | json codes="errors[*].code"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newJSONExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("codes", `errors[*].code`),
+				}),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | json_array "errors" | json | code="E1"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&JSONArrayExpr{Path: "errors"},
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&LabelFilterExpr{
+					LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "code", "E1")),
+				},
+			},
+		},
+	},
+	{
+		in: `count_over_time({app="foo"} | json_array [5m])`,
+		exp: &RangeAggregationExpr{
+			Left: &LogRange{
+				Left: &PipelineExpr{
+					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+					MultiStages: MultiStageExpr{
+						&JSONArrayExpr{},
+					},
+				},
+				Interval: 5 * time.Minute,
+			},
+			Operation: OpRangeTypeCount,
+		},
+	},
+	{
+		in:  `{app="foo"} | json_array "errors[*]"`,
+		err: logqlmodel.NewParseError("invalid json_array: cannot use array wildcard in expression [errors[*]]", 0, 0),
+	},
+	{
+		in:  `{app="foo"} | json_array "errors" | json_array "causes"`,
+		err: logqlmodel.NewParseError("only one json_array stage is allowed per pipeline", 0, 0),
+	},
+	{
+		// the parser and explode keywords can still be used as label names.
+		in: `{xml="a", json_array="b"} | xml="x" | csv != "y" | cef=~"z.*" | leef > 2 | json_array="w" | label_format csv=xml`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{
+				mustNewMatcher(labels.MatchEqual, "xml", "a"),
+				mustNewMatcher(labels.MatchEqual, "json_array", "b"),
+			}),
+			MultiStages: MultiStageExpr{
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "xml", "x"))),
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchNotEqual, "csv", "y"))),
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchRegexp, "cef", "z.*"))),
+				newLabelFilterExpr(log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "leef", 2)),
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "json_array", "w"))),
+				newLabelFmtExpr([]log.LabelFmt{log.NewRenameLabelFmt("csv", "xml")}),
+			},
+		},
+	},
+	{
+		in: `sum by (xml, csv, cef, leef, json_array) (count_over_time({app="foo"} | xml [5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				newLogRange(
+					&PipelineExpr{
+						Left:        newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+						MultiStages: MultiStageExpr{newLabelParserExpr(OpParserTypeXML, "")},
+					},
+					5*time.Minute,
+					nil, nil),
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeSum, &Grouping{Groups: []string{"xml", "csv", "cef", "leef", "json_array"}}, nil,
+		),
+	},
+	{
+		in: `join(5s, {app="gateway"} | logfmt, {app="backend"}) on (request_id, cluster)`,
+		exp: &JoinExpr{
+			Left: &PipelineExpr{
+				Left:        newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "gateway")}),
+				MultiStages: MultiStageExpr{newLogfmtParserExpr(nil)},
+			},
+			Right:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "backend")}),
+			Operation: OpJoin,
+			Tolerance: 5 * time.Second,
+			On:        []string{"request_id", "cluster"},
+		},
+	},
+	{
+		in: `count_over_time(join_unmatched(1m, {app="gateway"}, {app="backend"}) on (request_id) [5m] offset 1h)`,
+		exp: &RangeAggregationExpr{
+			Left: &LogRange{
+				Left: &JoinExpr{
+					Left:      newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "gateway")}),
+					Right:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "backend")}),
+					Operation: OpJoinUnmatched,
+					Tolerance: time.Minute,
+					On:        []string{"request_id"},
+				},
+				Interval: 5 * time.Minute,
+				Offset:   time.Hour,
+			},
+			Operation: OpRangeTypeCount,
+		},
+	},
+	{
+		in:  `join(5s, join(5s, {app="a"}, {app="b"}) on (id), {app="c"}) on (id)`,
+		err: logqlmodel.NewParseError("join cannot be nested", 0, 0),
+	},
+	{
+		in:  `join(5s, {app="gateway"}, {app=~".*"}) on (request_id)`,
+		err: logqlmodel.NewParseError(errAtleastOneEqualityMatcherRequired, 0, 0),
+	},
 	{
 		in: `{app="foo"} |= "foo" or "bar" |= "buzz" or "fizz"`,
 		exp: &PipelineExpr{
@@ -3560,7 +3975,7 @@ func TestNoOpLabelToString(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `{container_name="app"} | foo=~".*"`, l.String())
 
-	stages, err := l.(*PipelineExpr).MultiStages.stages()
+	stages, err := l.(*PipelineExpr).MultiStages.stages(nil)
 	require.NoError(t, err)
 	require.Len(t, stages, 0)
 }
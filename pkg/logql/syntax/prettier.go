@@ -70,6 +70,22 @@ func (e *PipelineExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: join(5s, {app="gateway"} | logfmt, {app="backend"} | logfmt) on (request_id)
+func (e *JoinExpr) Pretty(level int) string {
+	s := Indent(level)
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation + "(\n"
+	s += Indent(level+1) + model.Duration(e.Tolerance).String() + ",\n"
+	s += e.Left.Pretty(level+1) + ",\n"
+	s += e.Right.Pretty(level+1) + "\n"
+	s += Indent(level) + ") " + OpOn + " (" + strings.Join(e.On, ",") + ")"
+
+	return s
+}
+
 // e.g: `|= "error" != "memcache" |= ip("192.168.0.1")`
 // NOTE: here `ip` is Op in this expression.
 func (e *LineFilterExpr) Pretty(level int) string {
@@ -131,11 +147,26 @@ func (e *LineFmtExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | prefilter(level="error")
+func (e *LabelPrefilterExpr) Pretty(_ int) string {
+	return e.String()
+}
+
 // e.g: | decolorize
 func (e *DecolorizeExpr) Pretty(_ int) string {
 	return e.String()
 }
 
+// e.g: | json_array "errors"
+func (e *JSONArrayExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | @nginx_parse
+func (e *MacroExpr) Pretty(_ int) string {
+	return e.String()
+}
+
 // e.g: | label_format dst="{{ .src }}"
 func (e *LabelFmtExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -151,6 +182,16 @@ func (e *LogfmtExpressionParser) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | csv "ts,method,path,status" delimiter=";"
+func (e *CSVParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | xml label="/path/to/element", another="/path/to/@attribute"
+func (e *XMLExpressionParser) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: sum_over_time({foo="bar"} | logfmt | unwrap bytes_processed [5m])
 func (e *UnwrapExpr) Pretty(level int) string {
 	s := Indent(level)
@@ -217,6 +258,10 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 
 	s += e.Left.Pretty(level + 1)
 
+	for _, a := range e.Args {
+		s = fmt.Sprintf("%s,\n%s%s", s, Indent(level+1), fmt.Sprint(a))
+	}
+
 	s += "\n" + Indent(level) + ")"
 
 	if e.Grouping != nil {
@@ -226,6 +271,50 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: rate({foo="bar"}[5m])[1h:1m]
+func (e *SubqueryExpr) Pretty(level int) string {
+	s := e.Left.Pretty(level)
+
+	step := ""
+	if e.Step != 0 {
+		step = model.Duration(e.Step).String()
+	}
+	s = fmt.Sprintf("%s[%s:%s]", s, model.Duration(e.Range), step)
+
+	if e.Offset != 0 {
+		oe := OffsetExpr{Offset: e.Offset}
+		s += oe.Pretty(level)
+	}
+
+	return s
+}
+
+// e.g: max_over_time(rate({foo="bar"}[5m])[1h:1m])
+func (e *SubqueryAggregationExpr) Pretty(level int) string {
+	s := Indent(level)
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation
+	s += "(\n"
+
+	if e.Params != nil {
+		s = fmt.Sprintf("%s%s%s,", s, Indent(level+1), fmt.Sprint(*e.Params))
+		s += "\n"
+	}
+
+	s += e.Left.Pretty(level + 1)
+
+	for _, a := range e.Args {
+		s = fmt.Sprintf("%s,\n%s%s", s, Indent(level+1), fmt.Sprint(a))
+	}
+
+	s += "\n" + Indent(level) + ")"
+
+	return s
+}
+
 // e.g:
 // sum(count_over_time({foo="bar"}[5m])) by (container)
 // topk(10, count_over_time({foo="bar"}[5m])) by (container)
@@ -56,6 +56,40 @@ func TestFormat(t *testing.T) {
 			exp: `count_over_time(
   {job="loki", instance="localhost"}
     | logfmt [1m]
+)`,
+		},
+		{
+			name: "join",
+			in:   `join(5s,{app="gateway"}|logfmt,{app="backend"}) on (request_id)`,
+			exp: `join(
+  5s,
+  {app="gateway"}
+    | logfmt,
+  {app="backend"}
+) on (request_id)`,
+		},
+		{
+			name: "unwrap_with_trailing_params",
+			in:   `holt_winters({container="ingress-nginx",service="hosted-grafana"}| json| unwrap response_latency_seconds| __error__=""[1m], 0.3, 0.7)`,
+			exp: `holt_winters(
+  {container="ingress-nginx", service="hosted-grafana"}
+    | json
+    | unwrap response_latency_seconds
+    | __error__="" [1m],
+  0.3,
+  0.7
+)`,
+		},
+		{
+			name: "subquery",
+			in:   `max_over_time(sum by (cluster) (rate({container="ingress-nginx",service="hosted-grafana"}|= "error"[1m]))[1h:1m] offset 1h)`,
+			exp: `max_over_time(
+  sum by (cluster)(
+    rate(
+      {container="ingress-nginx", service="hosted-grafana"}
+        |= "error" [1m]
+    )
+  )[1h:1m] offset 1h
 )`,
 		},
 		{
@@ -35,6 +35,7 @@ const (
 	Binary              = "binary"
 	Bytes               = "bytes"
 	And                 = "and"
+	Args                = "args"
 	Card                = "cardinality"
 	Dst                 = "dst"
 	Duration            = "duration"
@@ -68,7 +69,10 @@ const (
 	ReturnBool          = "return_bool"
 	RHS                 = "rhs"
 	Src                 = "src"
+	StepNanos           = "step_nanos"
 	StringField         = "string"
+	Subquery            = "subquery"
+	SubqueryAgg         = "subquery_agg"
 	NoopField           = "noop"
 	Type                = "type"
 	Unwrap              = "unwrap"
@@ -90,6 +94,8 @@ func DecodeJSON(raw string) (Expr, error) {
 		return decodeVectorAgg(iter)
 	case RangeAgg:
 		return decodeRangeAgg(iter)
+	case SubqueryAgg:
+		return decodeSubqueryAgg(iter)
 	case Literal:
 		return decodeLiteral(iter)
 	case Vector:
@@ -195,6 +201,19 @@ func (v *JSONSerializer) VisitRangeAggregation(e *RangeAggregationExpr) {
 		v.WriteFloat64(*e.Params)
 	}
 
+	if len(e.Args) > 0 {
+		v.WriteMore()
+		v.WriteObjectField(Args)
+		v.WriteArrayStart()
+		for i, a := range e.Args {
+			if i > 0 {
+				v.WriteMore()
+			}
+			v.WriteFloat64(a)
+		}
+		v.WriteArrayEnd()
+	}
+
 	v.WriteMore()
 	v.WriteObjectField(Range)
 	v.VisitLogRange(e.Left)
@@ -204,6 +223,63 @@ func (v *JSONSerializer) VisitRangeAggregation(e *RangeAggregationExpr) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitSubqueryAggregation(e *SubqueryAggregationExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(SubqueryAgg)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Op)
+	v.WriteString(e.Operation)
+
+	if e.Params != nil {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteFloat64(*e.Params)
+	}
+
+	if len(e.Args) > 0 {
+		v.WriteMore()
+		v.WriteObjectField(Args)
+		v.WriteArrayStart()
+		for i, a := range e.Args {
+			if i > 0 {
+				v.WriteMore()
+			}
+			v.WriteFloat64(a)
+		}
+		v.WriteArrayEnd()
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(Subquery)
+	v.VisitSubquery(e.Left)
+	v.WriteObjectEnd()
+
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitSubquery(e *SubqueryExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(IntervalNanos)
+	v.WriteInt64(int64(e.Range))
+	v.WriteMore()
+	v.WriteObjectField(StepNanos)
+	v.WriteInt64(int64(e.Step))
+	v.WriteMore()
+	v.WriteObjectField(OffsetNanos)
+	v.WriteInt64(int64(e.Offset))
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 func (v *JSONSerializer) VisitLogRange(e *LogRange) {
 	v.WriteObjectStart()
 
@@ -304,19 +380,33 @@ func (v *JSONSerializer) VisitPipeline(e *PipelineExpr) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitJoin(e *JoinExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(LogSelector)
+	encodeLogSelector(v.Stream, e)
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 // Below are StageExpr visitors that we are skipping since a pipeline is
 // serialized as a string.
+func (*JSONSerializer) VisitCSVParser(*CSVParserExpr)                       {}
 func (*JSONSerializer) VisitDecolorize(*DecolorizeExpr)                     {}
+func (*JSONSerializer) VisitJSONArray(*JSONArrayExpr)                       {}
+func (*JSONSerializer) VisitMacro(*MacroExpr)                               {}
 func (*JSONSerializer) VisitDropLabels(*DropLabelsExpr)                     {}
 func (*JSONSerializer) VisitJSONExpressionParser(*JSONExpressionParser)     {}
 func (*JSONSerializer) VisitKeepLabel(*KeepLabelsExpr)                      {}
 func (*JSONSerializer) VisitLabelFilter(*LabelFilterExpr)                   {}
 func (*JSONSerializer) VisitLabelFmt(*LabelFmtExpr)                         {}
 func (*JSONSerializer) VisitLabelParser(*LabelParserExpr)                   {}
+func (*JSONSerializer) VisitLabelPrefilter(*LabelPrefilterExpr)             {}
 func (*JSONSerializer) VisitLineFilter(*LineFilterExpr)                     {}
 func (*JSONSerializer) VisitLineFmt(*LineFmtExpr)                           {}
 func (*JSONSerializer) VisitLogfmtExpressionParser(*LogfmtExpressionParser) {}
 func (*JSONSerializer) VisitLogfmtParser(*LogfmtParserExpr)                 {}
+func (*JSONSerializer) VisitXMLExpressionParser(*XMLExpressionParser)       {}
 
 func encodeGrouping(s *jsoniter.Stream, g *Grouping) {
 	s.WriteObjectStart()
@@ -711,6 +801,8 @@ func decodeSample(iter *jsoniter.Iterator) (SampleExpr, error) {
 			expr, err = decodeVectorAgg(iter)
 		case RangeAgg:
 			expr, err = decodeRangeAgg(iter)
+		case SubqueryAgg:
+			expr, err = decodeSubqueryAgg(iter)
 		case Literal:
 			expr, err = decodeLiteral(iter)
 		case Vector:
@@ -848,6 +940,11 @@ func decodeRangeAgg(iter *jsoniter.Iterator) (*RangeAggregationExpr, error) {
 		case Params:
 			tmp := iter.ReadFloat64()
 			expr.Params = &tmp
+		case Args:
+			iter.ReadArrayCB(func(i *jsoniter.Iterator) bool {
+				expr.Args = append(expr.Args, i.ReadFloat64())
+				return true
+			})
 		case Range:
 			expr.Left, err = decodeLogRange(iter)
 		case GroupingField:
@@ -878,6 +975,50 @@ func decodeLogRange(iter *jsoniter.Iterator) (*LogRange, error) {
 	return expr, err
 }
 
+func decodeSubqueryAgg(iter *jsoniter.Iterator) (*SubqueryAggregationExpr, error) {
+	expr := &SubqueryAggregationExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Op:
+			expr.Operation = iter.ReadString()
+		case Params:
+			tmp := iter.ReadFloat64()
+			expr.Params = &tmp
+		case Args:
+			iter.ReadArrayCB(func(i *jsoniter.Iterator) bool {
+				expr.Args = append(expr.Args, i.ReadFloat64())
+				return true
+			})
+		case Subquery:
+			expr.Left, err = decodeSubquery(iter)
+		}
+	}
+
+	return expr, err
+}
+
+func decodeSubquery(iter *jsoniter.Iterator) (*SubqueryExpr, error) {
+	expr := &SubqueryExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case IntervalNanos:
+			expr.Range = time.Duration(iter.ReadInt64())
+		case StepNanos:
+			expr.Step = time.Duration(iter.ReadInt64())
+		case OffsetNanos:
+			expr.Offset = time.Duration(iter.ReadInt64())
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		}
+	}
+
+	return expr, err
+}
+
 func decodeLabelReplace(iter *jsoniter.Iterator) (*LabelReplaceExpr, error) {
 	var err error
 	var left SampleExpr
@@ -10,12 +10,14 @@ type RootVisitor interface {
 	StageExprVisitor
 
 	VisitLogRange(*LogRange)
+	VisitSubquery(*SubqueryExpr)
 }
 
 type SampleExprVisitor interface {
 	VisitBinOp(*BinOpExpr)
 	VisitVectorAggregation(*VectorAggregationExpr)
 	VisitRangeAggregation(*RangeAggregationExpr)
+	VisitSubqueryAggregation(*SubqueryAggregationExpr)
 	VisitLabelReplace(*LabelReplaceExpr)
 	VisitLiteral(*LiteralExpr)
 	VisitVector(*VectorExpr)
@@ -24,6 +26,7 @@ type SampleExprVisitor interface {
 type LogSelectorExprVisitor interface {
 	VisitMatchers(*MatchersExpr)
 	VisitPipeline(*PipelineExpr)
+	VisitJoin(*JoinExpr)
 	VisitLiteral(*LiteralExpr)
 	VisitVector(*VectorExpr)
 }
@@ -31,28 +34,37 @@ type LogSelectorExprVisitor interface {
 type StageExprVisitor interface {
 	VisitDecolorize(*DecolorizeExpr)
 	VisitDropLabels(*DropLabelsExpr)
+	VisitJSONArray(*JSONArrayExpr)
 	VisitJSONExpressionParser(*JSONExpressionParser)
 	VisitKeepLabel(*KeepLabelsExpr)
 	VisitLabelFilter(*LabelFilterExpr)
 	VisitLabelFmt(*LabelFmtExpr)
 	VisitLabelParser(*LabelParserExpr)
+	VisitLabelPrefilter(*LabelPrefilterExpr)
 	VisitLineFilter(*LineFilterExpr)
 	VisitLineFmt(*LineFmtExpr)
 	VisitLogfmtExpressionParser(*LogfmtExpressionParser)
 	VisitLogfmtParser(*LogfmtParserExpr)
+	VisitMacro(*MacroExpr)
+	VisitXMLExpressionParser(*XMLExpressionParser)
+	VisitCSVParser(*CSVParserExpr)
 }
 
 var _ RootVisitor = &DepthFirstTraversal{}
 
 type DepthFirstTraversal struct {
 	VisitBinOpFn                  func(v RootVisitor, e *BinOpExpr)
+	VisitCSVParserFn              func(v RootVisitor, e *CSVParserExpr)
 	VisitDecolorizeFn             func(v RootVisitor, e *DecolorizeExpr)
 	VisitDropLabelsFn             func(v RootVisitor, e *DropLabelsExpr)
+	VisitJoinFn                   func(v RootVisitor, e *JoinExpr)
+	VisitJSONArrayFn              func(v RootVisitor, e *JSONArrayExpr)
 	VisitJSONExpressionParserFn   func(v RootVisitor, e *JSONExpressionParser)
 	VisitKeepLabelFn              func(v RootVisitor, e *KeepLabelsExpr)
 	VisitLabelFilterFn            func(v RootVisitor, e *LabelFilterExpr)
 	VisitLabelFmtFn               func(v RootVisitor, e *LabelFmtExpr)
 	VisitLabelParserFn            func(v RootVisitor, e *LabelParserExpr)
+	VisitLabelPrefilterFn         func(v RootVisitor, e *LabelPrefilterExpr)
 	VisitLabelReplaceFn           func(v RootVisitor, e *LabelReplaceExpr)
 	VisitLineFilterFn             func(v RootVisitor, e *LineFilterExpr)
 	VisitLineFmtFn                func(v RootVisitor, e *LineFmtExpr)
@@ -60,11 +72,15 @@ type DepthFirstTraversal struct {
 	VisitLogRangeFn               func(v RootVisitor, e *LogRange)
 	VisitLogfmtExpressionParserFn func(v RootVisitor, e *LogfmtExpressionParser)
 	VisitLogfmtParserFn           func(v RootVisitor, e *LogfmtParserExpr)
+	VisitMacroFn                  func(v RootVisitor, e *MacroExpr)
 	VisitMatchersFn               func(v RootVisitor, e *MatchersExpr)
 	VisitPipelineFn               func(v RootVisitor, e *PipelineExpr)
 	VisitRangeAggregationFn       func(v RootVisitor, e *RangeAggregationExpr)
+	VisitSubqueryFn               func(v RootVisitor, e *SubqueryExpr)
+	VisitSubqueryAggregationFn    func(v RootVisitor, e *SubqueryAggregationExpr)
 	VisitVectorFn                 func(v RootVisitor, e *VectorExpr)
 	VisitVectorAggregationFn      func(v RootVisitor, e *VectorAggregationExpr)
+	VisitXMLExpressionParserFn    func(v RootVisitor, e *XMLExpressionParser)
 }
 
 // VisitBinOp implements RootVisitor.
@@ -80,6 +96,16 @@ func (v *DepthFirstTraversal) VisitBinOp(e *BinOpExpr) {
 	}
 }
 
+// VisitCSVParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitCSVParser(e *CSVParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitCSVParserFn != nil {
+		v.VisitCSVParserFn(v, e)
+	}
+}
+
 // VisitDecolorize implements RootVisitor.
 func (v *DepthFirstTraversal) VisitDecolorize(e *DecolorizeExpr) {
 	if e == nil {
@@ -100,6 +126,29 @@ func (v *DepthFirstTraversal) VisitDropLabels(e *DropLabelsExpr) {
 	}
 }
 
+// VisitJoin implements RootVisitor.
+func (v *DepthFirstTraversal) VisitJoin(e *JoinExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitJoinFn != nil {
+		v.VisitJoinFn(v, e)
+	} else {
+		e.Left.Accept(v)
+		e.Right.Accept(v)
+	}
+}
+
+// VisitJSONArray implements RootVisitor.
+func (v *DepthFirstTraversal) VisitJSONArray(e *JSONArrayExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitJSONArrayFn != nil {
+		v.VisitJSONArrayFn(v, e)
+	}
+}
+
 // VisitJSONExpressionParser implements RootVisitor.
 func (v *DepthFirstTraversal) VisitJSONExpressionParser(e *JSONExpressionParser) {
 	if e == nil {
@@ -150,6 +199,16 @@ func (v *DepthFirstTraversal) VisitLabelParser(e *LabelParserExpr) {
 	}
 }
 
+// VisitLabelPrefilter implements RootVisitor.
+func (v *DepthFirstTraversal) VisitLabelPrefilter(e *LabelPrefilterExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitLabelPrefilterFn != nil {
+		v.VisitLabelPrefilterFn(v, e)
+	}
+}
+
 // VisitLabelReplace implements RootVisitor.
 func (v *DepthFirstTraversal) VisitLabelReplace(e *LabelReplaceExpr) {
 	if e == nil {
@@ -229,6 +288,16 @@ func (v *DepthFirstTraversal) VisitLogfmtParser(e *LogfmtParserExpr) {
 	}
 }
 
+// VisitMacro implements RootVisitor.
+func (v *DepthFirstTraversal) VisitMacro(e *MacroExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitMacroFn != nil {
+		v.VisitMacroFn(v, e)
+	}
+}
+
 // VisitMatchers implements RootVisitor.
 func (v *DepthFirstTraversal) VisitMatchers(e *MatchersExpr) {
 	if e == nil {
@@ -266,6 +335,30 @@ func (v *DepthFirstTraversal) VisitRangeAggregation(e *RangeAggregationExpr) {
 	}
 }
 
+// VisitSubquery implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSubquery(e *SubqueryExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSubqueryFn != nil {
+		v.VisitSubqueryFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
+// VisitSubqueryAggregation implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSubqueryAggregation(e *SubqueryAggregationExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSubqueryAggregationFn != nil {
+		v.VisitSubqueryAggregationFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitVector implements RootVisitor.
 func (v *DepthFirstTraversal) VisitVector(e *VectorExpr) {
 	if e == nil {
@@ -287,3 +380,13 @@ func (v *DepthFirstTraversal) VisitVectorAggregation(e *VectorAggregationExpr) {
 		e.Left.Accept(v)
 	}
 }
+
+// VisitXMLExpressionParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitXMLExpressionParser(e *XMLExpressionParser) {
+	if e == nil {
+		return
+	}
+	if v.VisitXMLExpressionParserFn != nil {
+		v.VisitXMLExpressionParserFn(v, e)
+	}
+}
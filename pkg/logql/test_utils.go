@@ -48,11 +48,20 @@ func (q MockQuerier) extractOldShard(xs []string) (*index.ShardAnnotation, error
 
 }
 
-func (q MockQuerier) SelectLogs(_ context.Context, req SelectLogParams) (iter.EntryIterator, error) {
+func (q MockQuerier) SelectLogs(ctx context.Context, req SelectLogParams) (iter.EntryIterator, error) {
 	expr, err := req.LogSelector()
 	if err != nil {
 		return nil, err
 	}
+	expr, err = OptimizePipelines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err = RecordPipelineStages(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
 	pipeline, err := expr.Pipeline()
 	if err != nil {
 		return nil, err
@@ -115,7 +124,7 @@ func processStream(in []logproto.Stream, pipeline log.Pipeline) []logproto.Strea
 	for _, stream := range in {
 		for _, e := range stream.Entries {
 			sp := pipeline.ForStream(mustParseLabels(stream.Labels))
-			if l, out, matches := sp.Process(e.Timestamp.UnixNano(), []byte(e.Line)); matches {
+			log.ProcessEach(sp, e.Timestamp.UnixNano(), e.Line, nil, func(_ int, l string, out log.LabelsResult) {
 				var s *logproto.Stream
 				var found bool
 				s, found = resByStream[out.String()]
@@ -125,9 +134,9 @@ func processStream(in []logproto.Stream, pipeline log.Pipeline) []logproto.Strea
 				}
 				s.Entries = append(s.Entries, logproto.Entry{
 					Timestamp: e.Timestamp,
-					Line:      string(l),
+					Line:      l,
 				})
-			}
+			})
 		}
 	}
 	streams := []logproto.Stream{}
@@ -143,7 +152,7 @@ func processSeries(in []logproto.Stream, ex log.SampleExtractor) []logproto.Seri
 	for _, stream := range in {
 		for _, e := range stream.Entries {
 			exs := ex.ForStream(mustParseLabels(stream.Labels))
-			if f, lbs, ok := exs.Process(e.Timestamp.UnixNano(), []byte(e.Line)); ok {
+			log.ExtractEach(exs, e.Timestamp.UnixNano(), e.Line, nil, func(n int, f float64, lbs log.LabelsResult) {
 				var s *logproto.Series
 				var found bool
 				s, found = resBySeries[lbs.String()]
@@ -154,9 +163,9 @@ func processSeries(in []logproto.Stream, ex log.SampleExtractor) []logproto.Seri
 				s.Samples = append(s.Samples, logproto.Sample{
 					Timestamp: e.Timestamp.UnixNano(),
 					Value:     f,
-					Hash:      xxhash.Sum64([]byte(e.Line)),
+					Hash:      xxhash.Sum64([]byte(e.Line)) + uint64(n),
 				})
-			}
+			})
 		}
 	}
 	series := []logproto.Series{}
@@ -167,7 +176,7 @@ func processSeries(in []logproto.Stream, ex log.SampleExtractor) []logproto.Seri
 	return series
 }
 
-func (q MockQuerier) SelectSamples(_ context.Context, req SelectSampleParams) (iter.SampleIterator, error) {
+func (q MockQuerier) SelectSamples(ctx context.Context, req SelectSampleParams) (iter.SampleIterator, error) {
 	selector, err := req.LogSelector()
 	if err != nil {
 		return nil, err
@@ -177,6 +186,15 @@ func (q MockQuerier) SelectSamples(_ context.Context, req SelectSampleParams) (i
 	if err != nil {
 		return nil, err
 	}
+	expr, err = OptimizePipelines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err = RecordPipelineStages(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
 
 	extractor, err := expr.Extractor()
 	if err != nil {
@@ -246,6 +264,15 @@ func (m MockDownstreamer) Downstream(ctx context.Context, queries []DownstreamQu
 		}
 		return []logqlmodel.Result{{Data: matrix}}, nil
 	}
+	if matrix, ok := results[0].Data.(CountDistinctMatrix); ok {
+		if len(results) == 1 {
+			return results, nil
+		}
+		for _, m := range results[1:] {
+			matrix, _ = matrix.Merge(m.Data.(CountDistinctMatrix))
+		}
+		return []logqlmodel.Result{{Data: matrix}}, nil
+	}
 	return results, nil
 }
 
@@ -162,6 +162,15 @@ func (n Node) childLine(text string) Node {
 	}
 }
 
+// lastChildText returns the first line of the last child added to the node, without its edge.
+func (n Node) lastChildText() string {
+	if len(n.tree.lastNode) <= n.level || n.tree.lastNode[n.level] == -1 {
+		return ""
+	}
+	row := n.tree.rows[n.tree.lastNode[n.level]]
+	return string(row[n.level*len(edgeLast):])
+}
+
 // AddEmptyLine adds an empty line to the output; used to introduce vertical
 // spacing as needed.
 func (n Node) AddEmptyLine() {
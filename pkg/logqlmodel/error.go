@@ -92,6 +92,12 @@ func NewSeriesLimitError(limit int) *LimitError {
 	}
 }
 
+func NewJoinWindowLimitError(limit int) *LimitError {
+	return &LimitError{
+		error: fmt.Errorf("maximum of entries (%d) waiting to be joined reached for a single query, reduce the join tolerance or select fewer entries", limit),
+	}
+}
+
 // Is allows to use errors.Is(err,ErrLimit) on this error.
 func (e LimitError) Is(target error) bool {
 	return target == ErrLimit
@@ -0,0 +1,121 @@
+package logqlmodel
+
+import (
+	"math"
+	"sort"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+)
+
+// NewSampleHistogram constructs a model.SampleHistogram from a native histogram.
+// Like in the Prometheus API, empty buckets are not exposed.
+func NewSampleHistogram(h *histogram.FloatHistogram) *model.SampleHistogram {
+	ret := &model.SampleHistogram{
+		Count: model.FloatString(h.Count),
+		Sum:   model.FloatString(h.Sum),
+	}
+	it := h.AllBucketIterator()
+	for it.Next() {
+		b := it.At()
+		if b.Count == 0 {
+			continue
+		}
+		ret.Buckets = append(ret.Buckets, &model.HistogramBucket{
+			Boundaries: bucketBoundaries(b),
+			Lower:      model.FloatString(b.Lower),
+			Upper:      model.FloatString(b.Upper),
+			Count:      model.FloatString(b.Count),
+		})
+	}
+	return ret
+}
+
+// bucketBoundaries returns the boundaries code of the bucket in the Prometheus API:
+// 0 upper inclusive, 1 lower inclusive, 2 both exclusive, 3 both inclusive.
+func bucketBoundaries(b histogram.Bucket[float64]) int32 {
+	switch {
+	case b.LowerInclusive && b.UpperInclusive:
+		return 3
+	case b.LowerInclusive:
+		return 1
+	case b.UpperInclusive:
+		return 0
+	default:
+		return 2
+	}
+}
+
+// NewFloatHistogram constructs a native histogram from a model.SampleHistogram, the reverse of NewSampleHistogram.
+// The schema of the histogram is inferred from the width of its buckets, so only exponential histograms
+// are supported. A histogram without regular buckets gets the highest resolution schema,
+// so it doesn't reduce the resolution of the histograms it is added to.
+func NewFloatHistogram(sh *model.SampleHistogram) *histogram.FloatHistogram {
+	h := &histogram.FloatHistogram{
+		// Loki only produces gauge histograms.
+		CounterResetHint: histogram.GaugeType,
+		Schema:           histogram.ExponentialSchemaMax,
+		Count:            float64(sh.Count),
+		Sum:              float64(sh.Sum),
+	}
+	for _, b := range sh.Buckets {
+		lower, upper := math.Abs(float64(b.Lower)), math.Abs(float64(b.Upper))
+		if b.Lower < 0 && b.Upper > 0 || lower == 0 || upper == 0 {
+			continue
+		}
+		schema := int32(math.Round(-math.Log2(math.Abs(math.Log2(upper / lower)))))
+		h.Schema = min(max(schema, histogram.ExponentialSchemaMin), histogram.ExponentialSchemaMax)
+		break
+	}
+
+	positive, negative := map[int32]float64{}, map[int32]float64{}
+	for _, b := range sh.Buckets {
+		switch {
+		case b.Lower <= 0 && b.Upper >= 0:
+			h.ZeroThreshold = float64(b.Upper)
+			h.ZeroCount += float64(b.Count)
+		case b.Lower > 0:
+			positive[bucketKey(float64(b.Upper), h.Schema)] += float64(b.Count)
+		default:
+			negative[bucketKey(-float64(b.Lower), h.Schema)] += float64(b.Count)
+		}
+	}
+	h.PositiveSpans, h.PositiveBuckets = HistogramSpans(positive)
+	h.NegativeSpans, h.NegativeBuckets = HistogramSpans(negative)
+	return h
+}
+
+// bucketKey returns the index of the bucket with the given (absolute) upper bound.
+func bucketKey(upper float64, schema int32) int32 {
+	return int32(math.Round(math.Log2(upper) * math.Exp2(float64(schema))))
+}
+
+// HistogramSpans converts bucket counts by index into the spans and buckets of a native histogram.
+func HistogramSpans(counts map[int32]float64) ([]histogram.Span, []float64) {
+	if len(counts) == 0 {
+		return nil, nil
+	}
+	keys := make([]int32, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var (
+		spans   []histogram.Span
+		buckets = make([]float64, 0, len(keys))
+	)
+	for i, k := range keys {
+		switch {
+		case i == 0:
+			// the offset of the first span is the index of its first bucket.
+			spans = append(spans, histogram.Span{Offset: k, Length: 1})
+		case k == keys[i-1]+1:
+			spans[len(spans)-1].Length++
+		default:
+			spans = append(spans, histogram.Span{Offset: k - keys[i-1] - 1, Length: 1})
+		}
+		buckets = append(buckets, counts[k])
+	}
+	return spans, buckets
+}
@@ -0,0 +1,65 @@
+package logqlmodel
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleHistogramRoundTrip(t *testing.T) {
+	for _, h := range []*histogram.FloatHistogram{
+		{
+			CounterResetHint: histogram.GaugeType,
+			Schema:           3,
+			ZeroThreshold:    2.938735877055719e-39,
+			ZeroCount:        1,
+			Count:            8,
+			Sum:              12.5,
+			PositiveSpans:    []histogram.Span{{Offset: 0, Length: 2}, {Offset: 6, Length: 1}},
+			PositiveBuckets:  []float64{2, 1, 3},
+			NegativeSpans:    []histogram.Span{{Offset: -8, Length: 1}},
+			NegativeBuckets:  []float64{1},
+		},
+		{
+			CounterResetHint: histogram.GaugeType,
+			Schema:           0,
+			ZeroThreshold:    0.001,
+			Count:            5,
+			Sum:              40,
+			PositiveSpans:    []histogram.Span{{Offset: 3, Length: 1}},
+			PositiveBuckets:  []float64{5},
+		},
+		{
+			CounterResetHint: histogram.GaugeType,
+			Schema:           -2,
+			Count:            3,
+			Sum:              -100,
+			NegativeSpans:    []histogram.Span{{Offset: 1, Length: 1}, {Offset: 1, Length: 1}},
+			NegativeBuckets:  []float64{1, 2},
+		},
+	} {
+		sh := NewSampleHistogram(h)
+		require.Equal(t, model.FloatString(h.Count), sh.Count)
+		require.Equal(t, model.FloatString(h.Sum), sh.Sum)
+
+		actual := NewFloatHistogram(sh)
+		require.NoError(t, actual.Validate())
+		if h.ZeroCount == 0 {
+			// the zero bucket is only exposed when not empty.
+			actual.ZeroThreshold = h.ZeroThreshold
+		}
+		require.Equal(t, h, actual)
+	}
+}
+
+func TestHistogramSpans(t *testing.T) {
+	spans, buckets := HistogramSpans(map[int32]float64{-3: 1, -2: 2, 1: 3, 2: 4, 10: 5})
+	require.Equal(t, []histogram.Span{{Offset: -3, Length: 2}, {Offset: 2, Length: 2}, {Offset: 7, Length: 1}}, spans)
+	require.Equal(t, []float64{1, 2, 3, 4, 5}, buckets)
+
+	spans, buckets = HistogramSpans(nil)
+	require.Nil(t, spans)
+	require.Nil(t, buckets)
+}
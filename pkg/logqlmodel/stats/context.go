@@ -21,6 +21,7 @@ package stats
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic" //lint:ignore faillint we can't use go.uber.org/atomic with a protobuf struct without wrapping it.
 	"time"
@@ -50,6 +51,8 @@ type Context struct {
 	store Store
 	// result accumulates results for JoinResult.
 	result Result
+	// stages are the statistics of the stages of the log pipelines, see PipelineStage.
+	stages []*PipelineStage
 
 	mtx sync.Mutex
 }
@@ -94,13 +97,15 @@ func (c *Context) Ingester() Ingester {
 		TotalChunksMatched: c.ingester.TotalChunksMatched,
 		TotalBatches:       c.ingester.TotalBatches,
 		TotalLinesSent:     c.ingester.TotalLinesSent,
-		Store:              c.store,
+		Store:              c.Store(),
 	}
 }
 
 // Store returns the store statistics accumulated so far.
 func (c *Context) Store() Store {
-	return c.store
+	s := c.store
+	s.PipelineStages = c.pipelineStages()
+	return s
 }
 
 // Caches returns the cache statistics accumulated so far.
@@ -128,6 +133,14 @@ func (c *Context) Reset() {
 	c.result.Reset()
 	c.caches.Reset()
 	c.index.Reset()
+
+	// pipelines keep recording into their stages, only the counters are reset.
+	for _, s := range c.stages {
+		atomic.StoreInt64(&s.LinesIn, 0)
+		atomic.StoreInt64(&s.LinesOut, 0)
+		atomic.StoreInt64(&s.BytesIn, 0)
+		atomic.StoreInt64(&s.WallTime, 0)
+	}
 }
 
 // Result calculates the summary based on store and ingester data.
@@ -136,7 +149,7 @@ func (c *Context) Result(execTime time.Duration, queueTime time.Duration, totalE
 
 	r.Merge(Result{
 		Querier: Querier{
-			Store: c.store,
+			Store: c.Store(),
 		},
 		Ingester: c.ingester,
 		Caches:   c.caches,
@@ -208,6 +221,70 @@ func (s *Store) Merge(m Store) {
 	if m.QueryReferencedStructured {
 		s.QueryReferencedStructured = true
 	}
+	s.PipelineStages = mergePipelineStages(s.PipelineStages, m.PipelineStages)
+}
+
+// mergePipelineStages sums the statistics of the stages having the same name.
+// The stages are copied so that merged results never share them.
+func mergePipelineStages(a, b []*PipelineStage) []*PipelineStage {
+	if len(b) == 0 {
+		return a
+	}
+	res := make([]*PipelineStage, 0, len(a)+len(b))
+	byName := make(map[string]*PipelineStage, len(a)+len(b))
+	for _, stages := range [][]*PipelineStage{a, b} {
+		for _, s := range stages {
+			merged, ok := byName[s.Name]
+			if !ok {
+				merged = &PipelineStage{Name: s.Name}
+				byName[s.Name] = merged
+				res = append(res, merged)
+			}
+			merged.LinesIn += s.LinesIn
+			merged.LinesOut += s.LinesOut
+			merged.BytesIn += s.BytesIn
+			merged.WallTime += s.WallTime
+		}
+	}
+	return res
+}
+
+// Merge sums the statistics of the nodes of two analyses of the same plan,
+// e.g. of the shards or splits of a query. Analyses of different plans are appended.
+// The nodes are copied so that merged results never share them.
+func (a *Analysis) Merge(m *Analysis) *Analysis {
+	if a == nil {
+		return m
+	}
+	if m == nil {
+		return a
+	}
+	res := &Analysis{Nodes: make([]AnalysisNode, len(a.Nodes), len(a.Nodes)+len(m.Nodes))}
+	copy(res.Nodes, a.Nodes)
+	if !a.samePlan(m) {
+		res.Nodes = append(res.Nodes, m.Nodes...)
+		return res
+	}
+	for i, n := range m.Nodes {
+		res.Nodes[i].WallTime += n.WallTime
+		res.Nodes[i].LinesIn += n.LinesIn
+		res.Nodes[i].LinesOut += n.LinesOut
+		res.Nodes[i].BytesProcessed += n.BytesProcessed
+		res.Nodes[i].Shards += n.Shards
+	}
+	return res
+}
+
+func (a *Analysis) samePlan(m *Analysis) bool {
+	if len(a.Nodes) != len(m.Nodes) {
+		return false
+	}
+	for i, n := range a.Nodes {
+		if n.Name != m.Nodes[i].Name || n.Depth != m.Nodes[i].Depth {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *Store) ChunksDownloadDuration() time.Duration {
@@ -279,6 +356,7 @@ func (r *Result) Merge(m Result) {
 	r.Caches.Merge(m.Caches)
 	r.Summary.Merge(m.Summary)
 	r.Index.Merge(m.Index)
+	r.Analysis = r.Analysis.Merge(m.Analysis)
 	r.ComputeSummary(ConvertSecondsToNanoseconds(r.Summary.ExecTime+m.Summary.ExecTime),
 		ConvertSecondsToNanoseconds(r.Summary.QueueTime+m.Summary.QueueTime), int(r.Summary.TotalEntriesReturned))
 }
@@ -325,6 +403,11 @@ func (r Result) TotalDecompressedLines() int64 {
 	return r.Querier.Store.Chunk.DecompressedLines + r.Ingester.Store.Chunk.DecompressedLines
 }
 
+// PipelineStages returns the statistics of the stages of the log pipelines run by queriers and ingesters.
+func (r Result) PipelineStages() []*PipelineStage {
+	return mergePipelineStages(r.Querier.Store.PipelineStages, r.Ingester.Store.PipelineStages)
+}
+
 func (r Result) QueryReferencedStructuredMetadata() bool {
 	return r.Querier.Store.QueryReferencedStructured || r.Ingester.Store.QueryReferencedStructured
 }
@@ -494,6 +577,44 @@ func (c *Context) SetQueryReferencedStructuredMetadata() {
 	c.store.QueryReferencedStructured = true
 }
 
+// PipelineStage returns the statistics of the stage of a log pipeline with the given name,
+// they are shared by all the pipelines of the query having the stage.
+// The statistics are updated atomically while the pipelines run.
+func (c *Context) PipelineStage(name string) *PipelineStage {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, s := range c.stages {
+		if s.Name == name {
+			return s
+		}
+	}
+	s := &PipelineStage{Name: name}
+	c.stages = append(c.stages, s)
+	return s
+}
+
+// pipelineStages returns a snapshot of the statistics of the stages of the log pipelines.
+func (c *Context) pipelineStages() []*PipelineStage {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if len(c.stages) == 0 {
+		return nil
+	}
+	res := make([]*PipelineStage, 0, len(c.stages))
+	for _, s := range c.stages {
+		res = append(res, &PipelineStage{
+			Name:     s.Name,
+			LinesIn:  atomic.LoadInt64(&s.LinesIn),
+			LinesOut: atomic.LoadInt64(&s.LinesOut),
+			BytesIn:  atomic.LoadInt64(&s.BytesIn),
+			WallTime: atomic.LoadInt64(&s.WallTime),
+		})
+	}
+	return res
+}
+
 func (c *Context) getCacheStatsByType(t CacheType) *Cache {
 	var stats *Cache
 	switch t {
@@ -555,10 +676,26 @@ func (r Result) KVList() []any {
 		"Querier.QueryReferencedStructuredMetadata", r.Querier.Store.QueryReferencedStructured,
 	}
 
+	result = append(result, pipelineStagesKVList(r.PipelineStages())...)
 	result = append(result, r.Caches.kvList()...)
 	return append(result, r.Summary.kvList()...)
 }
 
+func pipelineStagesKVList(stages []*PipelineStage) []any {
+	result := make([]any, 0, 10*len(stages))
+	for i, s := range stages {
+		prefix := fmt.Sprintf("Pipeline.%d.", i)
+		result = append(result,
+			prefix+"Stage", s.Name,
+			prefix+"LinesIn", s.LinesIn,
+			prefix+"LinesOut", s.LinesOut,
+			prefix+"BytesIn", humanize.Bytes(uint64(s.BytesIn)),
+			prefix+"WallTime", time.Duration(s.WallTime),
+		)
+	}
+	return result
+}
+
 func (s Summary) kvList() []any {
 	return []any{
 		"Summary.BytesProcessedPerSecond", humanize.Bytes(uint64(s.BytesProcessedPerSecond)),
@@ -359,3 +359,66 @@ func TestCaches(t *testing.T) {
 		},
 	}, statsCtx.Caches())
 }
+
+func TestPipelineStages(t *testing.T) {
+	statsCtx, _ := NewContext(context.Background())
+	filter := statsCtx.PipelineStage(`|= "foo"`)
+	require.Same(t, filter, statsCtx.PipelineStage(`|= "foo"`))
+	filter.LinesIn, filter.LinesOut, filter.BytesIn = 10, 2, 100
+
+	res := statsCtx.Result(0, 0, 0)
+	res.Merge(Result{Ingester: Ingester{Store: Store{PipelineStages: []*PipelineStage{
+		{Name: "| json", LinesIn: 5, LinesOut: 5, BytesIn: 50},
+		{Name: `|= "foo"`, LinesIn: 20, LinesOut: 5, BytesIn: 200},
+	}}}})
+	require.Equal(t, []*PipelineStage{
+		{Name: `|= "foo"`, LinesIn: 30, LinesOut: 7, BytesIn: 300},
+		{Name: "| json", LinesIn: 5, LinesOut: 5, BytesIn: 50},
+	}, res.PipelineStages())
+
+	// the snapshot of the result is not changed by the context.
+	filter.LinesIn = 11
+	require.Equal(t, int64(10), res.Querier.Store.PipelineStages[0].LinesIn)
+
+	statsCtx.Reset()
+	require.Equal(t, []*PipelineStage{{Name: `|= "foo"`}}, statsCtx.Store().PipelineStages)
+}
+
+func TestAnalysis_Merge(t *testing.T) {
+	a := &Analysis{Nodes: []AnalysisNode{
+		{Name: "Concat", WallTime: 10, Shards: 2},
+		{Name: "Logs", Depth: 1, WallTime: 5, LinesIn: 10, LinesOut: 5, BytesProcessed: 100},
+	}}
+	require.Same(t, a, a.Merge(nil))
+	require.Same(t, a, (*Analysis)(nil).Merge(a))
+
+	// the same plan executed by another shard is summed up.
+	merged := a.Merge(&Analysis{Nodes: []AnalysisNode{
+		{Name: "Concat", WallTime: 20, Shards: 2},
+		{Name: "Logs", Depth: 1, WallTime: 15, LinesIn: 20, LinesOut: 10, BytesProcessed: 200},
+	}})
+	require.Equal(t, &Analysis{Nodes: []AnalysisNode{
+		{Name: "Concat", WallTime: 30, Shards: 4},
+		{Name: "Logs", Depth: 1, WallTime: 20, LinesIn: 30, LinesOut: 15, BytesProcessed: 300},
+	}}, merged)
+	require.Equal(t, int64(10), a.Nodes[0].WallTime)
+
+	// different plans are kept side by side.
+	merged = a.Merge(&Analysis{Nodes: []AnalysisNode{{Name: "Logs", WallTime: 1}}})
+	require.Len(t, merged.Nodes, 3)
+	require.Equal(t, "Logs", merged.Nodes[2].Name)
+}
+
+func TestPipelineStagesKVList(t *testing.T) {
+	res := Result{Querier: Querier{Store: Store{PipelineStages: []*PipelineStage{
+		{Name: `|= "error"`, LinesIn: 100, LinesOut: 10, BytesIn: 2048, WallTime: int64(time.Millisecond)},
+	}}}}
+	kvs := res.KVList()
+	require.Subset(t, kvs, []any{
+		"Pipeline.0.Stage", `|= "error"`,
+		"Pipeline.0.LinesIn", int64(100),
+		"Pipeline.0.LinesOut", int64(10),
+		"Pipeline.0.BytesIn", "2.0 kB",
+		"Pipeline.0.WallTime", time.Millisecond,
+	})
+}
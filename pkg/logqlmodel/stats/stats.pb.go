@@ -33,6 +33,8 @@ type Result struct {
 	Ingester Ingester `protobuf:"bytes,3,opt,name=ingester,proto3" json:"ingester"`
 	Caches   Caches   `protobuf:"bytes,4,opt,name=caches,proto3" json:"cache"`
 	Index    Index    `protobuf:"bytes,5,opt,name=index,proto3" json:"index"`
+	// Statistics of each node of the query plan, only set when the query is executed with explain=analyze.
+	Analysis *Analysis `protobuf:"bytes,6,opt,name=analysis,proto3" json:"analysis,omitempty"`
 }
 
 func (m *Result) Reset()      { *m = Result{} }
@@ -102,6 +104,13 @@ func (m *Result) GetIndex() Index {
 	return Index{}
 }
 
+func (m *Result) GetAnalysis() *Analysis {
+	if m != nil {
+		return m.Analysis
+	}
+	return nil
+}
+
 type Caches struct {
 	Chunk               Cache `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk"`
 	Index               Cache `protobuf:"bytes,2,opt,name=index,proto3" json:"index"`
@@ -553,6 +562,8 @@ type Store struct {
 	CongestionControlLatency int64 `protobuf:"varint,6,opt,name=congestionControlLatency,proto3" json:"congestionControlLatency"`
 	// Total number of lines filtered by pipeline wrapper.
 	PipelineWrapperFilteredLines int64 `protobuf:"varint,7,opt,name=pipelineWrapperFilteredLines,proto3" json:"pipelineWrapperFilteredLines"`
+	// Statistics of the stages of the log pipelines, only collected when the query is executed with explain=analyze.
+	PipelineStages []*PipelineStage `protobuf:"bytes,8,rep,name=pipelineStages,proto3" json:"pipelineStages,omitempty"`
 }
 
 func (m *Store) Reset()      { *m = Store{} }
@@ -643,6 +654,93 @@ func (m *Store) GetPipelineWrapperFilteredLines() int64 {
 	return 0
 }
 
+func (m *Store) GetPipelineStages() []*PipelineStage {
+	if m != nil {
+		return m.PipelineStages
+	}
+	return nil
+}
+
+type PipelineStage struct {
+	// The stage as written in the query, e.g. `| json`.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name"`
+	// Total lines given to the stage.
+	LinesIn int64 `protobuf:"varint,2,opt,name=linesIn,proto3" json:"linesIn"`
+	// Total lines kept by the stage.
+	LinesOut int64 `protobuf:"varint,3,opt,name=linesOut,proto3" json:"linesOut"`
+	// Total bytes of the lines given to the stage.
+	BytesIn int64 `protobuf:"varint,4,opt,name=bytesIn,proto3" json:"bytesIn"`
+	// Time spent in the stage in nanoseconds.
+	WallTime int64 `protobuf:"varint,5,opt,name=wallTime,proto3" json:"wallTime"`
+}
+
+func (m *PipelineStage) Reset()      { *m = PipelineStage{} }
+func (*PipelineStage) ProtoMessage() {}
+func (*PipelineStage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6cdfe5d2aea33ebb, []int{7}
+}
+func (m *PipelineStage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PipelineStage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PipelineStage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PipelineStage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PipelineStage.Merge(m, src)
+}
+func (m *PipelineStage) XXX_Size() int {
+	return m.Size()
+}
+func (m *PipelineStage) XXX_DiscardUnknown() {
+	xxx_messageInfo_PipelineStage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PipelineStage proto.InternalMessageInfo
+
+func (m *PipelineStage) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PipelineStage) GetLinesIn() int64 {
+	if m != nil {
+		return m.LinesIn
+	}
+	return 0
+}
+
+func (m *PipelineStage) GetLinesOut() int64 {
+	if m != nil {
+		return m.LinesOut
+	}
+	return 0
+}
+
+func (m *PipelineStage) GetBytesIn() int64 {
+	if m != nil {
+		return m.BytesIn
+	}
+	return 0
+}
+
+func (m *PipelineStage) GetWallTime() int64 {
+	if m != nil {
+		return m.WallTime
+	}
+	return 0
+}
+
 type Chunk struct {
 	// Total bytes processed but was already in memory (found in the headchunk). Includes structured metadata bytes.
 	HeadChunkBytes int64 `protobuf:"varint,4,opt,name=headChunkBytes,proto3" json:"headChunkBytes"`
@@ -667,7 +765,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6cdfe5d2aea33ebb, []int{7}
+	return fileDescriptor_6cdfe5d2aea33ebb, []int{8}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -773,7 +871,7 @@ type Cache struct {
 func (m *Cache) Reset()      { *m = Cache{} }
 func (*Cache) ProtoMessage() {}
 func (*Cache) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6cdfe5d2aea33ebb, []int{8}
+	return fileDescriptor_6cdfe5d2aea33ebb, []int{9}
 }
 func (m *Cache) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -858,6 +956,149 @@ func (m *Cache) GetQueryLengthServed() int64 {
 	return 0
 }
 
+// Analysis contains the statistics of each node of a query plan.
+type Analysis struct {
+	// The nodes of the plan in depth-first order.
+	Nodes []AnalysisNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes"`
+}
+
+func (m *Analysis) Reset()      { *m = Analysis{} }
+func (*Analysis) ProtoMessage() {}
+func (*Analysis) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6cdfe5d2aea33ebb, []int{10}
+}
+func (m *Analysis) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Analysis) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Analysis.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Analysis) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Analysis.Merge(m, src)
+}
+func (m *Analysis) XXX_Size() int {
+	return m.Size()
+}
+func (m *Analysis) XXX_DiscardUnknown() {
+	xxx_messageInfo_Analysis.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Analysis proto.InternalMessageInfo
+
+func (m *Analysis) GetNodes() []AnalysisNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type AnalysisNode struct {
+	// The node as printed by explain, e.g. an evaluator or a stage of a log pipeline.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name"`
+	// Depth of the node in the plan, the root is at depth 0.
+	Depth int32 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth"`
+	// Time spent in the node and its children in nanoseconds, summed across shards.
+	WallTime int64 `protobuf:"varint,3,opt,name=wallTime,proto3" json:"wallTime"`
+	// Total lines processed by the node.
+	LinesIn int64 `protobuf:"varint,4,opt,name=linesIn,proto3" json:"linesIn"`
+	// Total lines kept by the node.
+	LinesOut int64 `protobuf:"varint,5,opt,name=linesOut,proto3" json:"linesOut"`
+	// Total bytes processed by the node.
+	BytesProcessed int64 `protobuf:"varint,6,opt,name=bytesProcessed,proto3" json:"bytesProcessed"`
+	// Total number of downstream queries the node fanned out to.
+	Shards int64 `protobuf:"varint,7,opt,name=shards,proto3" json:"shards"`
+}
+
+func (m *AnalysisNode) Reset()      { *m = AnalysisNode{} }
+func (*AnalysisNode) ProtoMessage() {}
+func (*AnalysisNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6cdfe5d2aea33ebb, []int{11}
+}
+func (m *AnalysisNode) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AnalysisNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AnalysisNode.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AnalysisNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnalysisNode.Merge(m, src)
+}
+func (m *AnalysisNode) XXX_Size() int {
+	return m.Size()
+}
+func (m *AnalysisNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnalysisNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnalysisNode proto.InternalMessageInfo
+
+func (m *AnalysisNode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AnalysisNode) GetDepth() int32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *AnalysisNode) GetWallTime() int64 {
+	if m != nil {
+		return m.WallTime
+	}
+	return 0
+}
+
+func (m *AnalysisNode) GetLinesIn() int64 {
+	if m != nil {
+		return m.LinesIn
+	}
+	return 0
+}
+
+func (m *AnalysisNode) GetLinesOut() int64 {
+	if m != nil {
+		return m.LinesOut
+	}
+	return 0
+}
+
+func (m *AnalysisNode) GetBytesProcessed() int64 {
+	if m != nil {
+		return m.BytesProcessed
+	}
+	return 0
+}
+
+func (m *AnalysisNode) GetShards() int64 {
+	if m != nil {
+		return m.Shards
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Result)(nil), "stats.Result")
 	proto.RegisterType((*Caches)(nil), "stats.Caches")
@@ -866,101 +1107,118 @@ func init() {
 	proto.RegisterType((*Querier)(nil), "stats.Querier")
 	proto.RegisterType((*Ingester)(nil), "stats.Ingester")
 	proto.RegisterType((*Store)(nil), "stats.Store")
+	proto.RegisterType((*PipelineStage)(nil), "stats.PipelineStage")
 	proto.RegisterType((*Chunk)(nil), "stats.Chunk")
 	proto.RegisterType((*Cache)(nil), "stats.Cache")
+	proto.RegisterType((*Analysis)(nil), "stats.Analysis")
+	proto.RegisterType((*AnalysisNode)(nil), "stats.AnalysisNode")
 }
 
 func init() { proto.RegisterFile("pkg/logqlmodel/stats/stats.proto", fileDescriptor_6cdfe5d2aea33ebb) }
 
 var fileDescriptor_6cdfe5d2aea33ebb = []byte{
-	// 1378 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x58, 0xcf, 0x6f, 0xdc, 0x44,
-	0x14, 0xde, 0xcd, 0xd6, 0x9b, 0x74, 0xf2, 0xab, 0x9d, 0xa4, 0x74, 0x4b, 0x2b, 0x3b, 0x2c, 0x54,
-	0x14, 0x21, 0x65, 0x55, 0x8a, 0x84, 0x40, 0x54, 0x42, 0x4e, 0x89, 0x54, 0x29, 0x15, 0xe5, 0x2d,
-	0x08, 0x04, 0x27, 0xc7, 0x9e, 0xec, 0x5a, 0xf5, 0xda, 0x8e, 0x3d, 0x0e, 0xcd, 0x09, 0xfe, 0x04,
-	0xee, 0xdc, 0x11, 0x17, 0x4e, 0x5c, 0xe0, 0xcc, 0xa5, 0xc7, 0x1e, 0x7b, 0xb2, 0xe8, 0xe6, 0x82,
-	0x7c, 0xea, 0x1f, 0xc0, 0x01, 0xcd, 0x9b, 0x59, 0xff, 0x5a, 0x6f, 0x9a, 0xcb, 0x7a, 0xde, 0xf7,
-	0xbe, 0xef, 0xcd, 0xf8, 0x79, 0xe6, 0xbd, 0xd1, 0x92, 0x9d, 0xf0, 0xc9, 0x68, 0xe0, 0x05, 0xa3,
-	0x63, 0x6f, 0x12, 0x38, 0xcc, 0x1b, 0xc4, 0xdc, 0xe2, 0xb1, 0xfc, 0xdd, 0x0d, 0xa3, 0x80, 0x07,
-	0x54, 0x43, 0xe3, 0xcd, 0xed, 0x51, 0x30, 0x0a, 0x10, 0x19, 0x88, 0x91, 0x74, 0xf6, 0x7f, 0x5d,
-	0x22, 0x5d, 0x60, 0x71, 0xe2, 0x71, 0xfa, 0x31, 0x59, 0x8e, 0x93, 0xc9, 0xc4, 0x8a, 0x4e, 0x7b,
-	0xed, 0x9d, 0xf6, 0x9d, 0xd5, 0x0f, 0x36, 0x76, 0x65, 0x98, 0xa1, 0x44, 0xcd, 0xcd, 0x67, 0xa9,
-	0xd1, 0xca, 0x52, 0x63, 0x46, 0x83, 0xd9, 0x40, 0x48, 0x8f, 0x13, 0x16, 0xb9, 0x2c, 0xea, 0x2d,
-	0x55, 0xa4, 0x5f, 0x4a, 0xb4, 0x90, 0x2a, 0x1a, 0xcc, 0x06, 0xf4, 0x3e, 0x59, 0x71, 0xfd, 0x11,
-	0x8b, 0x39, 0x8b, 0x7a, 0x1d, 0xd4, 0x6e, 0x2a, 0xed, 0x43, 0x05, 0x9b, 0x57, 0x94, 0x38, 0x27,
-	0x42, 0x3e, 0xa2, 0x1f, 0x92, 0xae, 0x6d, 0xd9, 0x63, 0x16, 0xf7, 0x2e, 0xa1, 0x78, 0x5d, 0x89,
-	0xf7, 0x10, 0x34, 0xd7, 0x95, 0x54, 0x43, 0x12, 0x28, 0x2e, 0xbd, 0x4b, 0x34, 0xd7, 0x77, 0xd8,
-	0xd3, 0x9e, 0x86, 0xa2, 0xb5, 0x7c, 0x46, 0x87, 0x3d, 0x2d, 0x34, 0x48, 0x01, 0xf9, 0xe8, 0xff,
-	0x72, 0x89, 0x74, 0xf7, 0x72, 0xb5, 0x3d, 0x4e, 0xfc, 0x27, 0x2a, 0x4d, 0x6b, 0xe5, 0x29, 0x4b,
-	0x33, 0x0a, 0x0a, 0xc8, 0x47, 0x31, 0xe1, 0xd2, 0x79, 0x92, 0xf2, 0x84, 0xe2, 0xcd, 0x22, 0xfc,
-	0x30, 0x2a, 0x2d, 0x55, 0xcd, 0x86, 0xd2, 0x28, 0x0e, 0xa8, 0x27, 0xdd, 0x23, 0xab, 0x48, 0x93,
-	0xdf, 0x54, 0x25, 0xa5, 0x2a, 0xdd, 0x52, 0xd2, 0x32, 0x11, 0xca, 0x06, 0xdd, 0x27, 0x6b, 0x27,
-	0x81, 0x97, 0x4c, 0x98, 0x8a, 0xa2, 0x35, 0x44, 0xd9, 0x56, 0x51, 0x2a, 0x4c, 0xa8, 0x58, 0x22,
-	0x4e, 0x2c, 0xbe, 0xf2, 0x6c, 0x35, 0xdd, 0xf3, 0xe2, 0x94, 0x99, 0x50, 0xb1, 0xc4, 0x4b, 0x79,
-	0xd6, 0x21, 0xf3, 0x54, 0x98, 0xe5, 0xf3, 0x5e, 0xaa, 0x44, 0x84, 0xb2, 0x41, 0xbf, 0x27, 0x5b,
-	0xae, 0x1f, 0x73, 0xcb, 0xe7, 0x8f, 0x18, 0x8f, 0x5c, 0x5b, 0x05, 0x5b, 0x69, 0x08, 0x76, 0x53,
-	0x05, 0x6b, 0x12, 0x40, 0x13, 0xd8, 0xff, 0xab, 0x4b, 0x96, 0xd5, 0x31, 0xa1, 0x5f, 0x93, 0xeb,
-	0x87, 0xa7, 0x9c, 0xc5, 0x8f, 0xa3, 0xc0, 0x66, 0x71, 0xcc, 0x9c, 0xc7, 0x2c, 0x1a, 0x32, 0x3b,
-	0xf0, 0x1d, 0xdc, 0x30, 0x1d, 0xf3, 0x66, 0x96, 0x1a, 0x8b, 0x28, 0xb0, 0xc8, 0x21, 0xc2, 0x7a,
-	0xae, 0xdf, 0x18, 0x76, 0xa9, 0x08, 0xbb, 0x80, 0x02, 0x8b, 0x1c, 0xf4, 0x21, 0xd9, 0xe2, 0x01,
-	0xb7, 0x3c, 0xb3, 0x32, 0x2d, 0xee, 0xb9, 0x8e, 0x79, 0x5d, 0x24, 0xa1, 0xc1, 0x0d, 0x4d, 0x60,
-	0x1e, 0xea, 0xa0, 0x32, 0x15, 0xee, 0xc1, 0x72, 0xa8, 0xaa, 0x1b, 0x9a, 0x40, 0x7a, 0x87, 0xac,
-	0xb0, 0xa7, 0xcc, 0xfe, 0xca, 0x9d, 0x30, 0xdc, 0x7d, 0x6d, 0x73, 0x4d, 0x14, 0x80, 0x19, 0x06,
-	0xf9, 0x88, 0xbe, 0x4f, 0x2e, 0x1f, 0x27, 0x2c, 0x61, 0x48, 0xed, 0x22, 0x75, 0x3d, 0x4b, 0x8d,
-	0x02, 0x84, 0x62, 0x48, 0x77, 0x09, 0x89, 0x93, 0x43, 0x59, 0x7a, 0x62, 0xdc, 0x47, 0x1d, 0x73,
-	0x23, 0x4b, 0x8d, 0x12, 0x0a, 0xa5, 0x31, 0x3d, 0x20, 0xdb, 0xb8, 0xba, 0xcf, 0x7d, 0x2e, 0xb7,
-	0x23, 0x4f, 0x22, 0x9f, 0x39, 0xb8, 0x69, 0x3a, 0x66, 0x2f, 0x4b, 0x8d, 0x46, 0x3f, 0x34, 0xa2,
-	0xb4, 0x4f, 0xba, 0x71, 0xe8, 0xb9, 0x3c, 0xee, 0x5d, 0x46, 0x3d, 0x11, 0xe7, 0x57, 0x22, 0xa0,
-	0x9e, 0xc8, 0x19, 0x5b, 0x91, 0x13, 0xf7, 0x48, 0x89, 0x83, 0x08, 0xa8, 0x67, 0xbe, 0xaa, 0xc7,
-	0x41, 0xcc, 0xf7, 0x5d, 0x8f, 0xb3, 0x08, 0xb3, 0xd7, 0x5b, 0xad, 0xad, 0xaa, 0xe6, 0x87, 0x46,
-	0x94, 0xfe, 0x48, 0x6e, 0x23, 0x3e, 0xe4, 0x51, 0x62, 0xf3, 0x24, 0x62, 0xce, 0x23, 0xc6, 0x2d,
-	0xc7, 0xe2, 0x56, 0x6d, 0x4b, 0xac, 0x61, 0xf8, 0xf7, 0xb2, 0xd4, 0xb8, 0x98, 0x00, 0x2e, 0x46,
-	0xeb, 0xff, 0xd9, 0x26, 0x1a, 0x56, 0x5e, 0x7a, 0x97, 0xac, 0xa2, 0x64, 0x4f, 0xd4, 0xcc, 0x58,
-	0x9d, 0x96, 0x4d, 0x71, 0xaa, 0x4b, 0x30, 0x94, 0x0d, 0xfa, 0x19, 0xb9, 0x12, 0xe6, 0x2f, 0xa4,
-	0x74, 0xf2, 0x38, 0x6c, 0x67, 0xa9, 0x31, 0xe7, 0x83, 0x39, 0x84, 0x7e, 0x42, 0x36, 0x64, 0x5e,
-	0x1f, 0x24, 0x91, 0xc5, 0xdd, 0xc0, 0x57, 0x7b, 0x9f, 0x66, 0xa9, 0x51, 0xf3, 0x40, 0xcd, 0xee,
-	0x7f, 0x4a, 0x96, 0x55, 0x87, 0x13, 0x15, 0x3e, 0xe6, 0x41, 0xc4, 0x6a, 0x4d, 0x61, 0x28, 0xb0,
-	0xa2, 0xc2, 0x23, 0x05, 0xe4, 0xa3, 0xff, 0xfb, 0x12, 0x59, 0x79, 0x58, 0x34, 0xb2, 0x35, 0x7c,
-	0x2f, 0x60, 0xa2, 0x04, 0xc9, 0x52, 0xa1, 0x99, 0x57, 0x44, 0x65, 0x2c, 0xe3, 0x50, 0xb1, 0xe8,
-	0x3e, 0xa1, 0xa5, 0x6c, 0x3c, 0xb2, 0x38, 0x6a, 0x65, 0x02, 0xde, 0xc8, 0x52, 0xa3, 0xc1, 0x0b,
-	0x0d, 0x58, 0x3e, 0xbb, 0x89, 0x76, 0xac, 0x52, 0x50, 0xcc, 0xae, 0x70, 0xa8, 0x58, 0x22, 0x75,
-	0xc5, 0xe1, 0x1d, 0x32, 0x9f, 0xab, 0xb3, 0x8e, 0xa9, 0xab, 0x7a, 0xa0, 0x66, 0x17, 0xf9, 0xd2,
-	0x2e, 0x9c, 0xaf, 0xff, 0x2e, 0x11, 0x0d, 0xfd, 0xf9, 0xc4, 0xea, 0xa3, 0xb2, 0x23, 0xb5, 0x57,
-	0x8a, 0x89, 0x73, 0x0f, 0xd4, 0x6c, 0xfa, 0x05, 0xb9, 0x56, 0x42, 0x1e, 0x04, 0x3f, 0xf8, 0x5e,
-	0x60, 0x39, 0x79, 0xd6, 0x6e, 0x64, 0xa9, 0xd1, 0x4c, 0x80, 0x66, 0x58, 0x7c, 0x03, 0xbb, 0x82,
-	0x61, 0x29, 0xea, 0x14, 0xdf, 0x60, 0xde, 0x0b, 0x0d, 0x18, 0xb5, 0xc9, 0x0d, 0x51, 0x77, 0x4e,
-	0x81, 0x1d, 0xb1, 0x88, 0xf9, 0x36, 0x73, 0x8a, 0xa3, 0xd3, 0x5b, 0xdf, 0x69, 0xdf, 0x59, 0x31,
-	0x6f, 0x67, 0xa9, 0xf1, 0xd6, 0x42, 0xd2, 0xec, 0x7c, 0xc1, 0xe2, 0x38, 0xc5, 0xdd, 0xa5, 0x76,
-	0x33, 0x10, 0xd8, 0x82, 0xbb, 0xcb, 0xec, 0xfd, 0x80, 0x1d, 0xc5, 0xfb, 0x8c, 0xdb, 0xe3, 0xbc,
-	0x2a, 0x97, 0xdf, 0xaf, 0xe2, 0x85, 0x06, 0x8c, 0x7e, 0x4b, 0x7a, 0x76, 0x80, 0xdb, 0xdd, 0x0d,
-	0xfc, 0xbd, 0xc0, 0xe7, 0x51, 0xe0, 0x1d, 0x58, 0x9c, 0xf9, 0xf6, 0x29, 0x16, 0xee, 0x8e, 0x79,
-	0x2b, 0x4b, 0x8d, 0x85, 0x1c, 0x58, 0xe8, 0xa1, 0x0e, 0xb9, 0x15, 0xba, 0x21, 0x13, 0x2d, 0xee,
-	0x9b, 0xc8, 0x0a, 0x43, 0x16, 0xc9, 0x13, 0xce, 0x1c, 0x59, 0x18, 0x65, 0xa1, 0xdf, 0xc9, 0x52,
-	0xe3, 0x5c, 0x1e, 0x9c, 0xeb, 0xed, 0xff, 0xa1, 0x11, 0x0d, 0xf3, 0x24, 0xb6, 0xdf, 0x98, 0x59,
-	0x8e, 0x4c, 0x9a, 0x28, 0x66, 0xe5, 0x7d, 0x5f, 0xf5, 0x40, 0xcd, 0xae, 0x68, 0xe5, 0xea, 0xb4,
-	0x06, 0xad, 0x5c, 0x4f, 0xcd, 0xa6, 0x7b, 0xe4, 0xaa, 0xc3, 0xec, 0x60, 0x12, 0x46, 0x58, 0x39,
-	0xe5, 0xd4, 0x32, 0x75, 0xd7, 0xb2, 0xd4, 0x98, 0x77, 0xc2, 0x3c, 0x54, 0x0f, 0x52, 0xce, 0xd0,
-	0x5c, 0x10, 0xb9, 0x8c, 0x79, 0x88, 0xde, 0x27, 0x9b, 0xf5, 0x75, 0xc8, 0x9e, 0xb8, 0x95, 0xa5,
-	0x46, 0xdd, 0x05, 0x75, 0x40, 0xc8, 0xf1, 0x2c, 0x3d, 0x48, 0x42, 0xcf, 0xb5, 0x2d, 0x21, 0xbf,
-	0x5c, 0xc8, 0x6b, 0x2e, 0xa8, 0x03, 0x42, 0x1e, 0xd6, 0x7a, 0x1f, 0x29, 0xe4, 0x35, 0x17, 0xd4,
-	0x01, 0x1a, 0x92, 0x9d, 0x3c, 0xb1, 0x0b, 0xba, 0x93, 0xea, 0xa5, 0xef, 0x64, 0xa9, 0xf1, 0x5a,
-	0x2e, 0xbc, 0x96, 0x41, 0x4f, 0xc9, 0xdb, 0xe5, 0x1c, 0x2e, 0x9a, 0x54, 0x76, 0xd8, 0x77, 0xb3,
-	0xd4, 0xb8, 0x08, 0x1d, 0x2e, 0x42, 0xea, 0xff, 0xdd, 0x21, 0x1a, 0xde, 0x6a, 0x45, 0x8d, 0x67,
-	0xf2, 0x46, 0xb2, 0x1f, 0x24, 0x7e, 0xa5, 0xc3, 0x94, 0x71, 0xa8, 0x58, 0xa2, 0xc1, 0xb2, 0xd9,
-	0x3d, 0xe6, 0x38, 0x11, 0xbd, 0x4a, 0x56, 0x4a, 0x4d, 0x36, 0xd8, 0xba, 0x0f, 0xe6, 0x10, 0xfa,
-	0x11, 0x59, 0x57, 0x18, 0x16, 0x6f, 0x79, 0xb7, 0xd4, 0xcc, 0xab, 0x59, 0x6a, 0x54, 0x1d, 0x50,
-	0x35, 0x85, 0x10, 0x2f, 0xc3, 0xc0, 0x6c, 0xe6, 0x9e, 0xe4, 0x37, 0x49, 0x14, 0x56, 0x1c, 0x50,
-	0x35, 0xc5, 0x9d, 0x10, 0x01, 0x6c, 0x49, 0xf2, 0x78, 0xe1, 0x9d, 0x30, 0x07, 0xa1, 0x18, 0x8a,
-	0xab, 0x66, 0x24, 0xd7, 0x2a, 0xcf, 0x92, 0x26, 0xaf, 0x9a, 0x33, 0x0c, 0xf2, 0x91, 0x48, 0xa0,
-	0x53, 0x2e, 0xf1, 0xcb, 0x45, 0x93, 0x2c, 0xe3, 0x50, 0xb1, 0xc4, 0x79, 0xc3, 0x72, 0x7c, 0xc0,
-	0xfc, 0x11, 0x1f, 0x0f, 0x59, 0x74, 0x92, 0x5f, 0x20, 0xf1, 0xbc, 0xcd, 0x39, 0x61, 0x1e, 0x32,
-	0xd9, 0xf3, 0x97, 0x7a, 0xeb, 0xc5, 0x4b, 0xbd, 0xf5, 0xea, 0xa5, 0xde, 0xfe, 0x69, 0xaa, 0xb7,
-	0x7f, 0x9b, 0xea, 0xed, 0x67, 0x53, 0xbd, 0xfd, 0x7c, 0xaa, 0xb7, 0xff, 0x99, 0xea, 0xed, 0x7f,
-	0xa7, 0x7a, 0xeb, 0xd5, 0x54, 0x6f, 0xff, 0x7c, 0xa6, 0xb7, 0x9e, 0x9f, 0xe9, 0xad, 0x17, 0x67,
-	0x7a, 0xeb, 0xbb, 0xc1, 0xc8, 0xe5, 0xe3, 0xe4, 0x70, 0xd7, 0x0e, 0x26, 0x83, 0x51, 0x64, 0x1d,
-	0x59, 0xbe, 0x35, 0xf0, 0x82, 0x27, 0xee, 0xe0, 0xe4, 0xde, 0xa0, 0xe9, 0x6f, 0x83, 0xc3, 0x2e,
-	0xfe, 0x29, 0x70, 0xef, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0x64, 0x90, 0x88, 0xaf, 0x55, 0x10,
-	0x00, 0x00,
+	// 1612 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x58, 0x4d, 0x6f, 0xdb, 0xcc,
+	0x11, 0xb6, 0x2c, 0x51, 0x96, 0xd7, 0x5f, 0xc9, 0xda, 0x69, 0x98, 0x26, 0x10, 0x5d, 0xb5, 0x41,
+	0x5d, 0xb4, 0xb0, 0x90, 0x0f, 0xa0, 0x6d, 0xd0, 0x00, 0x2d, 0xed, 0x1a, 0x30, 0xe0, 0x34, 0xee,
+	0xb8, 0x45, 0x8b, 0xf4, 0x44, 0x93, 0x6b, 0x89, 0x08, 0x45, 0xca, 0xe4, 0xd2, 0x89, 0x4f, 0xed,
+	0x4f, 0xe8, 0xbd, 0x7f, 0x20, 0x97, 0x9e, 0x7a, 0x69, 0xcf, 0xbd, 0xe4, 0xd6, 0x1c, 0x73, 0x22,
+	0x1a, 0xe7, 0xf2, 0x82, 0xef, 0x25, 0x3f, 0xe1, 0xc5, 0xce, 0x2e, 0x3f, 0x45, 0x39, 0xbe, 0x68,
+	0x77, 0x9e, 0x79, 0x9e, 0xd9, 0xe5, 0x72, 0x77, 0x76, 0x28, 0xb2, 0x3d, 0x7d, 0x3d, 0x1a, 0x7a,
+	0xc1, 0xe8, 0xdc, 0x9b, 0x04, 0x0e, 0xf3, 0x86, 0x11, 0xb7, 0x78, 0x24, 0x7f, 0x77, 0xa7, 0x61,
+	0xc0, 0x03, 0xaa, 0xa1, 0xf1, 0xfd, 0xad, 0x51, 0x30, 0x0a, 0x10, 0x19, 0x8a, 0x9e, 0x74, 0x0e,
+	0xbe, 0x5d, 0x24, 0x5d, 0x60, 0x51, 0xec, 0x71, 0xfa, 0x4b, 0xb2, 0x14, 0xc5, 0x93, 0x89, 0x15,
+	0x5e, 0xea, 0xad, 0xed, 0xd6, 0xce, 0xca, 0xe3, 0xf5, 0x5d, 0x19, 0xe6, 0x44, 0xa2, 0xe6, 0xc6,
+	0xfb, 0xc4, 0x58, 0x48, 0x13, 0x23, 0xa3, 0x41, 0xd6, 0x11, 0xd2, 0xf3, 0x98, 0x85, 0x2e, 0x0b,
+	0xf5, 0xc5, 0x8a, 0xf4, 0xf7, 0x12, 0x2d, 0xa4, 0x8a, 0x06, 0x59, 0x87, 0x3e, 0x27, 0x3d, 0xd7,
+	0x1f, 0xb1, 0x88, 0xb3, 0x50, 0x6f, 0xa3, 0x76, 0x43, 0x69, 0x0f, 0x15, 0x6c, 0xde, 0x52, 0xe2,
+	0x9c, 0x08, 0x79, 0x8f, 0x3e, 0x25, 0x5d, 0xdb, 0xb2, 0xc7, 0x2c, 0xd2, 0x3b, 0x28, 0x5e, 0x53,
+	0xe2, 0x3d, 0x04, 0xcd, 0x35, 0x25, 0xd5, 0x90, 0x04, 0x8a, 0x4b, 0x1f, 0x11, 0xcd, 0xf5, 0x1d,
+	0xf6, 0x56, 0xd7, 0x50, 0xb4, 0x9a, 0x8f, 0xe8, 0xb0, 0xb7, 0x85, 0x06, 0x29, 0x20, 0x1b, 0xba,
+	0x47, 0x7a, 0x96, 0x6f, 0x79, 0x97, 0x91, 0x1b, 0xe9, 0xdd, 0xca, 0x3c, 0x7f, 0xa3, 0x60, 0xf3,
+	0x7b, 0x69, 0x62, 0xd0, 0x8c, 0xf4, 0xb3, 0x60, 0xe2, 0x72, 0x36, 0x99, 0xf2, 0x4b, 0xc8, 0x85,
+	0x83, 0x7f, 0x74, 0x48, 0x77, 0x2f, 0x9f, 0x82, 0x3d, 0x8e, 0xfd, 0xd7, 0x7a, 0xab, 0x32, 0x05,
+	0xf4, 0x96, 0xa6, 0x2d, 0x28, 0x20, 0x9b, 0x62, 0xd6, 0x8b, 0xd7, 0x49, 0x2a, 0xb3, 0x7e, 0x4a,
+	0xba, 0x21, 0xbe, 0x5d, 0xbd, 0xdd, 0xa0, 0x59, 0x57, 0x1a, 0xc5, 0x01, 0xd5, 0xd2, 0x3d, 0xb2,
+	0x82, 0x34, 0xb9, 0x31, 0xf4, 0x4e, 0x83, 0x74, 0x53, 0x49, 0xcb, 0x44, 0x28, 0x1b, 0xf4, 0x80,
+	0xac, 0x5e, 0x04, 0x5e, 0x3c, 0x61, 0x2a, 0x8a, 0xd6, 0x10, 0x65, 0x4b, 0x45, 0xa9, 0x30, 0xa1,
+	0x62, 0x89, 0x38, 0x91, 0xd8, 0x2a, 0xd9, 0x6c, 0xba, 0xd7, 0xc5, 0x29, 0x33, 0xa1, 0x62, 0x89,
+	0x87, 0xf2, 0xac, 0x53, 0xe6, 0xa9, 0x30, 0x4b, 0xd7, 0x3d, 0x54, 0x89, 0x08, 0x65, 0x83, 0xfe,
+	0x85, 0x6c, 0xba, 0x7e, 0xc4, 0x2d, 0x9f, 0xbf, 0x60, 0x3c, 0x74, 0x6d, 0x15, 0xac, 0xd7, 0x10,
+	0xec, 0xbe, 0x0a, 0xd6, 0x24, 0x80, 0x26, 0x70, 0xf0, 0x9f, 0x2e, 0x59, 0x52, 0x67, 0x8d, 0xfe,
+	0x91, 0xdc, 0x3d, 0xbd, 0xe4, 0x2c, 0x3a, 0x0e, 0x03, 0x9b, 0x45, 0x11, 0x73, 0x8e, 0x59, 0x78,
+	0xc2, 0xec, 0xc0, 0x77, 0x70, 0xc3, 0xb4, 0xcd, 0xfb, 0x69, 0x62, 0xcc, 0xa3, 0xc0, 0x3c, 0x87,
+	0x08, 0xeb, 0xb9, 0x7e, 0x63, 0xd8, 0xc5, 0x22, 0xec, 0x1c, 0x0a, 0xcc, 0x73, 0xd0, 0x43, 0xb2,
+	0xc9, 0x03, 0x6e, 0x79, 0x66, 0x65, 0x58, 0xdc, 0x73, 0x6d, 0xf3, 0xae, 0x58, 0x84, 0x06, 0x37,
+	0x34, 0x81, 0x79, 0xa8, 0xa3, 0xca, 0x50, 0x7a, 0xa7, 0x16, 0xaa, 0xea, 0x86, 0x26, 0x90, 0xee,
+	0x90, 0x1e, 0x7b, 0xcb, 0xec, 0x3f, 0xb8, 0x13, 0x86, 0xbb, 0xaf, 0x65, 0xae, 0x8a, 0x2c, 0x92,
+	0x61, 0x90, 0xf7, 0xe8, 0x4f, 0xc9, 0xf2, 0x79, 0xcc, 0x62, 0x86, 0xd4, 0x2e, 0x52, 0xd7, 0xd2,
+	0xc4, 0x28, 0x40, 0x28, 0xba, 0x74, 0x97, 0x90, 0x28, 0x3e, 0x95, 0xf9, 0x2b, 0xc2, 0x7d, 0xd4,
+	0x36, 0xd7, 0xd3, 0xc4, 0x28, 0xa1, 0x50, 0xea, 0xd3, 0x23, 0xb2, 0x85, 0xb3, 0xfb, 0xad, 0xcf,
+	0xd1, 0xc7, 0x78, 0x1c, 0xfa, 0xcc, 0xc1, 0x4d, 0xd3, 0x36, 0xf5, 0x34, 0x31, 0x1a, 0xfd, 0xd0,
+	0x88, 0xd2, 0x01, 0xe9, 0x46, 0x53, 0xcf, 0xe5, 0x91, 0xbe, 0x8c, 0x7a, 0x22, 0xce, 0xaf, 0x44,
+	0x40, 0xb5, 0xc8, 0x19, 0x5b, 0xa1, 0x13, 0xe9, 0xa4, 0xc4, 0x41, 0x04, 0x54, 0x9b, 0xcf, 0xea,
+	0x38, 0x88, 0xf8, 0x81, 0xeb, 0x71, 0x16, 0xe2, 0xea, 0xe9, 0x2b, 0xb5, 0x59, 0xd5, 0xfc, 0xd0,
+	0x88, 0xd2, 0xbf, 0x92, 0x87, 0x88, 0x9f, 0xf0, 0x30, 0xb6, 0x79, 0x1c, 0x32, 0xe7, 0x05, 0xe3,
+	0x96, 0x63, 0x71, 0xab, 0xb6, 0x25, 0x56, 0x31, 0xfc, 0x4f, 0xd2, 0xc4, 0xb8, 0x99, 0x00, 0x6e,
+	0x46, 0x1b, 0xfc, 0xbb, 0x45, 0x34, 0x4c, 0xdf, 0xf4, 0x11, 0x59, 0x41, 0xc9, 0x9e, 0xc8, 0x99,
+	0x91, 0x3a, 0x2d, 0x1b, 0xe2, 0x54, 0x97, 0x60, 0x28, 0x1b, 0xf4, 0xd7, 0xe4, 0xd6, 0x34, 0x7f,
+	0x20, 0xa5, 0x93, 0xc7, 0x61, 0x2b, 0x4d, 0x8c, 0x19, 0x1f, 0xcc, 0x20, 0xf4, 0x19, 0x59, 0x97,
+	0xeb, 0xba, 0x1f, 0x87, 0x16, 0x77, 0x03, 0x5f, 0xed, 0x7d, 0x9a, 0x26, 0x46, 0xcd, 0x03, 0x35,
+	0x7b, 0xf0, 0x2b, 0xb2, 0xa4, 0xae, 0x49, 0x91, 0xe1, 0x23, 0x1e, 0x84, 0xac, 0x76, 0x29, 0x9c,
+	0x08, 0xac, 0xc8, 0xf0, 0x48, 0x01, 0xd9, 0x0c, 0xfe, 0xb9, 0x48, 0x7a, 0x87, 0xc5, 0x6d, 0xb8,
+	0x8a, 0xcf, 0x05, 0x4c, 0xa4, 0x20, 0x99, 0x2a, 0x34, 0xf3, 0x96, 0xc8, 0x8c, 0x65, 0x1c, 0x2a,
+	0x16, 0x3d, 0x20, 0xb4, 0xb4, 0x1a, 0x2f, 0x2c, 0x8e, 0x5a, 0xb9, 0x00, 0x78, 0xa7, 0xcd, 0x7a,
+	0xa1, 0x01, 0xcb, 0x47, 0x37, 0xd1, 0x8e, 0xd4, 0x12, 0x14, 0xa3, 0x2b, 0x1c, 0x2a, 0x96, 0x58,
+	0xba, 0xe2, 0xf0, 0x9e, 0x30, 0x9f, 0xeb, 0x9d, 0x62, 0xe9, 0xaa, 0x1e, 0xa8, 0xd9, 0xc5, 0x7a,
+	0x69, 0x37, 0x5e, 0xaf, 0x8f, 0x1a, 0xd1, 0xd0, 0x9f, 0x0f, 0xac, 0x5e, 0x2a, 0x3b, 0xd3, 0x5b,
+	0xb5, 0x81, 0x73, 0x0f, 0xd4, 0x6c, 0xfa, 0x92, 0xdc, 0x29, 0x21, 0xfb, 0xc1, 0x1b, 0xdf, 0x0b,
+	0x2c, 0x27, 0x5f, 0xb5, 0x7b, 0x69, 0x62, 0x34, 0x13, 0xa0, 0x19, 0x16, 0xef, 0xc0, 0xae, 0x60,
+	0x98, 0x8a, 0xda, 0xc5, 0x3b, 0x98, 0xf5, 0x42, 0x03, 0x46, 0x6d, 0x72, 0x4f, 0xe4, 0x9d, 0x4b,
+	0x60, 0x67, 0x2c, 0x64, 0xbe, 0xcd, 0x9c, 0xe2, 0xe8, 0xe8, 0x6b, 0xdb, 0xad, 0x9d, 0x9e, 0xf9,
+	0x30, 0x4d, 0x8c, 0x1f, 0xcc, 0x25, 0x65, 0xe7, 0x0b, 0xe6, 0xc7, 0x29, 0x6a, 0x97, 0x5a, 0x65,
+	0x20, 0xb0, 0x39, 0xb5, 0x4b, 0xf6, 0x7c, 0xc0, 0xce, 0xa2, 0x03, 0xc6, 0xed, 0x71, 0x9e, 0x95,
+	0xcb, 0xcf, 0x57, 0xf1, 0x42, 0x03, 0x46, 0xff, 0x4c, 0x74, 0x3b, 0xc0, 0xed, 0xee, 0x06, 0xfe,
+	0x5e, 0xe0, 0xf3, 0x30, 0xf0, 0x8e, 0x2c, 0xce, 0x7c, 0xfb, 0x12, 0x13, 0x77, 0xdb, 0x7c, 0x90,
+	0x26, 0xc6, 0x5c, 0x0e, 0xcc, 0xf5, 0x50, 0x87, 0x3c, 0x98, 0xba, 0x53, 0x26, 0xae, 0xb8, 0x3f,
+	0x85, 0xd6, 0x74, 0xca, 0x42, 0x79, 0xc2, 0x99, 0x23, 0x13, 0xa3, 0x4c, 0xf4, 0xdb, 0x69, 0x62,
+	0x5c, 0xcb, 0x83, 0x6b, 0xbd, 0xf4, 0x15, 0x59, 0xcf, 0xfc, 0x27, 0xdc, 0x1a, 0xb1, 0x48, 0xef,
+	0x6d, 0xb7, 0x77, 0x56, 0x1e, 0x6f, 0xa9, 0x35, 0x3c, 0x2e, 0x3b, 0xe5, 0xb3, 0x54, 0xf9, 0xa5,
+	0xba, 0xb2, 0x16, 0x69, 0xf0, 0xbf, 0x16, 0x59, 0xab, 0xe8, 0xe9, 0x03, 0xd2, 0xf1, 0xad, 0x89,
+	0x4c, 0x27, 0xcb, 0x66, 0x2f, 0x4d, 0x0c, 0xb4, 0x01, 0x7f, 0xe9, 0x43, 0xb2, 0x24, 0xa8, 0xd1,
+	0xa1, 0xaf, 0xb6, 0xed, 0x8a, 0xa8, 0xd0, 0x15, 0x04, 0x59, 0x47, 0x5c, 0xa3, 0xd8, 0x7d, 0x19,
+	0x73, 0xb5, 0x21, 0xf1, 0x1a, 0xcd, 0x30, 0xc8, 0x7b, 0x22, 0x20, 0x16, 0x1e, 0x87, 0xbe, 0xde,
+	0x29, 0x02, 0x2a, 0x08, 0xb2, 0x8e, 0x08, 0xf8, 0xc6, 0xf2, 0xbc, 0xd2, 0x0e, 0xc0, 0x80, 0x19,
+	0x06, 0x79, 0x6f, 0xf0, 0x2f, 0x8d, 0x68, 0xb8, 0xab, 0xc4, 0x61, 0x1d, 0x33, 0xcb, 0x41, 0x03,
+	0x53, 0x7f, 0x39, 0x4b, 0x54, 0x3d, 0x50, 0xb3, 0x2b, 0x5a, 0xf9, 0x2e, 0xb5, 0x06, 0xad, 0x7c,
+	0x7b, 0x35, 0x9b, 0xee, 0x91, 0xdb, 0x0e, 0xb3, 0x83, 0xc9, 0x34, 0xc4, 0x7b, 0x46, 0x0e, 0x2d,
+	0x37, 0xda, 0x9d, 0x34, 0x31, 0x66, 0x9d, 0x30, 0x0b, 0xd5, 0x83, 0x94, 0xf7, 0xd3, 0x4c, 0x10,
+	0x39, 0x8d, 0x59, 0x88, 0x3e, 0x27, 0x1b, 0xf5, 0x79, 0xc8, 0x0a, 0x62, 0x33, 0x4d, 0x8c, 0xba,
+	0x0b, 0xea, 0x80, 0x90, 0x63, 0xe6, 0xd9, 0x8f, 0xa7, 0x9e, 0x6b, 0x5b, 0x9c, 0x65, 0x05, 0x04,
+	0xca, 0x6b, 0x2e, 0xa8, 0x03, 0x42, 0x3e, 0xad, 0x55, 0x0a, 0xa4, 0x90, 0xd7, 0x5c, 0x50, 0x07,
+	0xe8, 0x94, 0x6c, 0xe7, 0x0b, 0x3b, 0xe7, 0x2e, 0x57, 0x95, 0xc7, 0x8f, 0xd2, 0xc4, 0xf8, 0x2a,
+	0x17, 0xbe, 0xca, 0xa0, 0x97, 0xe4, 0x87, 0xe5, 0x35, 0x9c, 0x37, 0xa8, 0xac, 0x47, 0x7e, 0x9c,
+	0x26, 0xc6, 0x4d, 0xe8, 0x70, 0x13, 0xd2, 0xe0, 0xbf, 0x6d, 0xa2, 0xe1, 0x37, 0x80, 0xb8, 0x11,
+	0x99, 0xac, 0xdf, 0x0e, 0x82, 0xd8, 0xaf, 0xdc, 0xc7, 0x65, 0x1c, 0x2a, 0x96, 0x28, 0x47, 0x58,
+	0x56, 0xf5, 0x9d, 0xc7, 0x2c, 0xe2, 0xea, 0x5e, 0xd1, 0x64, 0x39, 0x52, 0xf7, 0xc1, 0x0c, 0x42,
+	0x7f, 0x4e, 0xd6, 0x14, 0x86, 0x57, 0x9d, 0xac, 0xc4, 0x35, 0xf3, 0x76, 0x9a, 0x18, 0x55, 0x07,
+	0x54, 0x4d, 0x21, 0xc4, 0x53, 0x0a, 0xcc, 0x66, 0xee, 0x45, 0x5e, 0x77, 0xa3, 0xb0, 0xe2, 0x80,
+	0xaa, 0x29, 0x2a, 0x68, 0x04, 0xf0, 0x02, 0x97, 0xc7, 0x0b, 0x2b, 0xe8, 0x1c, 0x84, 0xa2, 0x2b,
+	0x12, 0x40, 0x28, 0xe7, 0x2a, 0xcf, 0x92, 0x26, 0x13, 0x40, 0x86, 0x41, 0xde, 0x13, 0x0b, 0xe8,
+	0x94, 0x2f, 0xc4, 0xa5, 0xa2, 0xa4, 0x28, 0xe3, 0x50, 0xb1, 0xc4, 0x79, 0xc3, 0xcb, 0xeb, 0x88,
+	0xf9, 0x23, 0x3e, 0x3e, 0x61, 0xe1, 0x45, 0x5e, 0x6e, 0xe3, 0x79, 0x9b, 0x71, 0xc2, 0x2c, 0x34,
+	0xd8, 0x27, 0xbd, 0xec, 0xcb, 0x9e, 0xfe, 0x82, 0x68, 0x7e, 0xe0, 0x30, 0x51, 0x4d, 0x8a, 0x64,
+	0xbd, 0x59, 0xfb, 0xf2, 0xff, 0x5d, 0xe0, 0x94, 0xca, 0x0d, 0x64, 0x82, 0x6c, 0x06, 0xef, 0x16,
+	0xc9, 0x6a, 0x99, 0xf6, 0x95, 0x94, 0x6c, 0x10, 0xcd, 0x61, 0x53, 0x3e, 0x56, 0xef, 0x7b, 0x59,
+	0xc4, 0x43, 0x00, 0x64, 0x53, 0xc9, 0x9d, 0xed, 0xeb, 0x72, 0x67, 0x39, 0xbb, 0x77, 0x6e, 0x98,
+	0xdd, 0xb5, 0x6b, 0xb3, 0xfb, 0x33, 0xb2, 0x5e, 0xfd, 0xac, 0xd4, 0xbb, 0x45, 0x1a, 0xad, 0x7a,
+	0xa0, 0x66, 0x97, 0xbe, 0x48, 0x96, 0xe6, 0x7d, 0x91, 0x98, 0xec, 0xc3, 0xa7, 0xfe, 0xc2, 0xc7,
+	0x4f, 0xfd, 0x85, 0x2f, 0x9f, 0xfa, 0xad, 0xbf, 0x5d, 0xf5, 0x5b, 0xef, 0xae, 0xfa, 0xad, 0xf7,
+	0x57, 0xfd, 0xd6, 0x87, 0xab, 0x7e, 0xeb, 0xff, 0x57, 0xfd, 0xd6, 0x37, 0x57, 0xfd, 0x85, 0x2f,
+	0x57, 0xfd, 0xd6, 0xdf, 0x3f, 0xf7, 0x17, 0x3e, 0x7c, 0xee, 0x2f, 0x7c, 0xfc, 0xdc, 0x5f, 0x78,
+	0x35, 0x1c, 0xb9, 0x7c, 0x1c, 0x9f, 0xee, 0xda, 0xc1, 0x64, 0x38, 0x0a, 0xad, 0x33, 0xcb, 0xb7,
+	0x86, 0x5e, 0xf0, 0xda, 0x1d, 0x5e, 0x3c, 0x19, 0x36, 0xfd, 0x35, 0x76, 0xda, 0xc5, 0x3f, 0xbe,
+	0x9e, 0x7c, 0x37, 0x00, 0xf0, 0xeb, 0x64, 0x43, 0x39, 0x13, 0x00, 0x00,
 }
 
 func (this *Result) Equal(that interface{}) bool {
@@ -997,6 +1255,9 @@ func (this *Result) Equal(that interface{}) bool {
 	if !this.Index.Equal(&that1.Index) {
 		return false
 	}
+	if !this.Analysis.Equal(that1.Analysis) {
+		return false
+	}
 	return true
 }
 func (this *Caches) Equal(that interface{}) bool {
@@ -1234,6 +1495,50 @@ func (this *Store) Equal(that interface{}) bool {
 	if this.PipelineWrapperFilteredLines != that1.PipelineWrapperFilteredLines {
 		return false
 	}
+	if len(this.PipelineStages) != len(that1.PipelineStages) {
+		return false
+	}
+	for i := range this.PipelineStages {
+		if !this.PipelineStages[i].Equal(that1.PipelineStages[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *PipelineStage) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PipelineStage)
+	if !ok {
+		that2, ok := that.(PipelineStage)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.LinesIn != that1.LinesIn {
+		return false
+	}
+	if this.LinesOut != that1.LinesOut {
+		return false
+	}
+	if this.BytesIn != that1.BytesIn {
+		return false
+	}
+	if this.WallTime != that1.WallTime {
+		return false
+	}
 	return true
 }
 func (this *Chunk) Equal(that interface{}) bool {
@@ -1329,17 +1634,91 @@ func (this *Cache) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *Analysis) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Analysis)
+	if !ok {
+		that2, ok := that.(Analysis)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Nodes) != len(that1.Nodes) {
+		return false
+	}
+	for i := range this.Nodes {
+		if !this.Nodes[i].Equal(&that1.Nodes[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *AnalysisNode) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AnalysisNode)
+	if !ok {
+		that2, ok := that.(AnalysisNode)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Depth != that1.Depth {
+		return false
+	}
+	if this.WallTime != that1.WallTime {
+		return false
+	}
+	if this.LinesIn != that1.LinesIn {
+		return false
+	}
+	if this.LinesOut != that1.LinesOut {
+		return false
+	}
+	if this.BytesProcessed != that1.BytesProcessed {
+		return false
+	}
+	if this.Shards != that1.Shards {
+		return false
+	}
+	return true
+}
 func (this *Result) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&stats.Result{")
 	s = append(s, "Summary: "+strings.Replace(this.Summary.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Querier: "+strings.Replace(this.Querier.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Ingester: "+strings.Replace(this.Ingester.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Caches: "+strings.Replace(this.Caches.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Index: "+strings.Replace(this.Index.GoString(), `&`, ``, 1)+",\n")
+	if this.Analysis != nil {
+		s = append(s, "Analysis: "+fmt.Sprintf("%#v", this.Analysis)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1421,7 +1800,7 @@ func (this *Store) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 12)
+	s := make([]string, 0, 13)
 	s = append(s, "&stats.Store{")
 	s = append(s, "TotalChunksRef: "+fmt.Sprintf("%#v", this.TotalChunksRef)+",\n")
 	s = append(s, "TotalChunksDownloaded: "+fmt.Sprintf("%#v", this.TotalChunksDownloaded)+",\n")
@@ -1431,6 +1810,23 @@ func (this *Store) GoString() string {
 	s = append(s, "ChunkRefsFetchTime: "+fmt.Sprintf("%#v", this.ChunkRefsFetchTime)+",\n")
 	s = append(s, "CongestionControlLatency: "+fmt.Sprintf("%#v", this.CongestionControlLatency)+",\n")
 	s = append(s, "PipelineWrapperFilteredLines: "+fmt.Sprintf("%#v", this.PipelineWrapperFilteredLines)+",\n")
+	if this.PipelineStages != nil {
+		s = append(s, "PipelineStages: "+fmt.Sprintf("%#v", this.PipelineStages)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PipelineStage) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&stats.PipelineStage{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "LinesIn: "+fmt.Sprintf("%#v", this.LinesIn)+",\n")
+	s = append(s, "LinesOut: "+fmt.Sprintf("%#v", this.LinesOut)+",\n")
+	s = append(s, "BytesIn: "+fmt.Sprintf("%#v", this.BytesIn)+",\n")
+	s = append(s, "WallTime: "+fmt.Sprintf("%#v", this.WallTime)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1469,20 +1865,52 @@ func (this *Cache) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func valueToGoStringStats(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
+func (this *Analysis) GoString() string {
+	if this == nil {
 		return "nil"
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
-}
-func (m *Result) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	s := make([]string, 0, 5)
+	s = append(s, "&stats.Analysis{")
+	if this.Nodes != nil {
+		vs := make([]AnalysisNode, len(this.Nodes))
+		for i := range vs {
+			vs[i] = this.Nodes[i]
+		}
+		s = append(s, "Nodes: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AnalysisNode) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&stats.AnalysisNode{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Depth: "+fmt.Sprintf("%#v", this.Depth)+",\n")
+	s = append(s, "WallTime: "+fmt.Sprintf("%#v", this.WallTime)+",\n")
+	s = append(s, "LinesIn: "+fmt.Sprintf("%#v", this.LinesIn)+",\n")
+	s = append(s, "LinesOut: "+fmt.Sprintf("%#v", this.LinesOut)+",\n")
+	s = append(s, "BytesProcessed: "+fmt.Sprintf("%#v", this.BytesProcessed)+",\n")
+	s = append(s, "Shards: "+fmt.Sprintf("%#v", this.Shards)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringStats(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *Result) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
 	return dAtA[:n], nil
 }
@@ -1497,6 +1925,18 @@ func (m *Result) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Analysis != nil {
+		{
+			size, err := m.Analysis.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintStats(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
 	{
 		size, err := m.Index.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -1892,6 +2332,20 @@ func (m *Store) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x68
 	}
+	if len(m.PipelineStages) > 0 {
+		for iNdEx := len(m.PipelineStages) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.PipelineStages[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintStats(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
 	if m.PipelineWrapperFilteredLines != 0 {
 		i = encodeVarintStats(dAtA, i, uint64(m.PipelineWrapperFilteredLines))
 		i--
@@ -1935,6 +2389,56 @@ func (m *Store) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *PipelineStage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PipelineStage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PipelineStage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.WallTime != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.WallTime))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.BytesIn != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.BytesIn))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.LinesOut != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.LinesOut))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.LinesIn != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.LinesIn))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintStats(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Chunk) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2066,6 +2570,103 @@ func (m *Cache) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *Analysis) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Analysis) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Analysis) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Nodes) > 0 {
+		for iNdEx := len(m.Nodes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Nodes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintStats(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AnalysisNode) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AnalysisNode) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AnalysisNode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Shards != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.Shards))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.BytesProcessed != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.BytesProcessed))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.LinesOut != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.LinesOut))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.LinesIn != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.LinesIn))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.WallTime != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.WallTime))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Depth != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.Depth))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintStats(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintStats(dAtA []byte, offset int, v uint64) int {
 	offset -= sovStats(v)
 	base := offset
@@ -2093,6 +2694,10 @@ func (m *Result) Size() (n int) {
 	n += 1 + l + sovStats(uint64(l))
 	l = m.Index.Size()
 	n += 1 + l + sovStats(uint64(l))
+	if m.Analysis != nil {
+		l = m.Analysis.Size()
+		n += 1 + l + sovStats(uint64(l))
+	}
 	return n
 }
 
@@ -2244,12 +2849,43 @@ func (m *Store) Size() (n int) {
 	if m.PipelineWrapperFilteredLines != 0 {
 		n += 1 + sovStats(uint64(m.PipelineWrapperFilteredLines))
 	}
+	if len(m.PipelineStages) > 0 {
+		for _, e := range m.PipelineStages {
+			l = e.Size()
+			n += 1 + l + sovStats(uint64(l))
+		}
+	}
 	if m.QueryReferencedStructured {
 		n += 2
 	}
 	return n
 }
 
+func (m *PipelineStage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.LinesIn != 0 {
+		n += 1 + sovStats(uint64(m.LinesIn))
+	}
+	if m.LinesOut != 0 {
+		n += 1 + sovStats(uint64(m.LinesOut))
+	}
+	if m.BytesIn != 0 {
+		n += 1 + sovStats(uint64(m.BytesIn))
+	}
+	if m.WallTime != 0 {
+		n += 1 + sovStats(uint64(m.WallTime))
+	}
+	return n
+}
+
 func (m *Chunk) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2319,6 +2955,52 @@ func (m *Cache) Size() (n int) {
 	return n
 }
 
+func (m *Analysis) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Nodes) > 0 {
+		for _, e := range m.Nodes {
+			l = e.Size()
+			n += 1 + l + sovStats(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AnalysisNode) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovStats(uint64(l))
+	}
+	if m.Depth != 0 {
+		n += 1 + sovStats(uint64(m.Depth))
+	}
+	if m.WallTime != 0 {
+		n += 1 + sovStats(uint64(m.WallTime))
+	}
+	if m.LinesIn != 0 {
+		n += 1 + sovStats(uint64(m.LinesIn))
+	}
+	if m.LinesOut != 0 {
+		n += 1 + sovStats(uint64(m.LinesOut))
+	}
+	if m.BytesProcessed != 0 {
+		n += 1 + sovStats(uint64(m.BytesProcessed))
+	}
+	if m.Shards != 0 {
+		n += 1 + sovStats(uint64(m.Shards))
+	}
+	return n
+}
+
 func sovStats(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -2335,6 +3017,7 @@ func (this *Result) String() string {
 		`Ingester:` + strings.Replace(strings.Replace(this.Ingester.String(), "Ingester", "Ingester", 1), `&`, ``, 1) + `,`,
 		`Caches:` + strings.Replace(strings.Replace(this.Caches.String(), "Caches", "Caches", 1), `&`, ``, 1) + `,`,
 		`Index:` + strings.Replace(strings.Replace(this.Index.String(), "Index", "Index", 1), `&`, ``, 1) + `,`,
+		`Analysis:` + strings.Replace(this.Analysis.String(), "Analysis", "Analysis", 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2417,6 +3100,11 @@ func (this *Store) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForPipelineStages := "[]*PipelineStage{"
+	for _, f := range this.PipelineStages {
+		repeatedStringForPipelineStages += strings.Replace(f.String(), "PipelineStage", "PipelineStage", 1) + ","
+	}
+	repeatedStringForPipelineStages += "}"
 	s := strings.Join([]string{`&Store{`,
 		`TotalChunksRef:` + fmt.Sprintf("%v", this.TotalChunksRef) + `,`,
 		`TotalChunksDownloaded:` + fmt.Sprintf("%v", this.TotalChunksDownloaded) + `,`,
@@ -2425,11 +3113,26 @@ func (this *Store) String() string {
 		`ChunkRefsFetchTime:` + fmt.Sprintf("%v", this.ChunkRefsFetchTime) + `,`,
 		`CongestionControlLatency:` + fmt.Sprintf("%v", this.CongestionControlLatency) + `,`,
 		`PipelineWrapperFilteredLines:` + fmt.Sprintf("%v", this.PipelineWrapperFilteredLines) + `,`,
+		`PipelineStages:` + repeatedStringForPipelineStages + `,`,
 		`QueryReferencedStructured:` + fmt.Sprintf("%v", this.QueryReferencedStructured) + `,`,
 		`}`,
 	}, "")
 	return s
 }
+func (this *PipelineStage) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PipelineStage{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`LinesIn:` + fmt.Sprintf("%v", this.LinesIn) + `,`,
+		`LinesOut:` + fmt.Sprintf("%v", this.LinesOut) + `,`,
+		`BytesIn:` + fmt.Sprintf("%v", this.BytesIn) + `,`,
+		`WallTime:` + fmt.Sprintf("%v", this.WallTime) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *Chunk) String() string {
 	if this == nil {
 		return "nil"
@@ -2465,6 +3168,37 @@ func (this *Cache) String() string {
 	}, "")
 	return s
 }
+func (this *Analysis) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForNodes := "[]AnalysisNode{"
+	for _, f := range this.Nodes {
+		repeatedStringForNodes += strings.Replace(strings.Replace(f.String(), "AnalysisNode", "AnalysisNode", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForNodes += "}"
+	s := strings.Join([]string{`&Analysis{`,
+		`Nodes:` + repeatedStringForNodes + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AnalysisNode) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AnalysisNode{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Depth:` + fmt.Sprintf("%v", this.Depth) + `,`,
+		`WallTime:` + fmt.Sprintf("%v", this.WallTime) + `,`,
+		`LinesIn:` + fmt.Sprintf("%v", this.LinesIn) + `,`,
+		`LinesOut:` + fmt.Sprintf("%v", this.LinesOut) + `,`,
+		`BytesProcessed:` + fmt.Sprintf("%v", this.BytesProcessed) + `,`,
+		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringStats(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -2667,6 +3401,42 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Analysis", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Analysis == nil {
+				m.Analysis = &Analysis{}
+			}
+			if err := m.Analysis.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
@@ -3807,11 +4577,11 @@ func (m *Store) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 13:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QueryReferencedStructured", wireType)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PipelineStages", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStats
@@ -3821,22 +4591,217 @@ func (m *Store) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.QueryReferencedStructured = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipStats(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthStats
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PipelineStages = append(m.PipelineStages, &PipelineStage{})
+			if err := m.PipelineStages[len(m.PipelineStages)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryReferencedStructured", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.QueryReferencedStructured = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PipelineStage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PipelineStage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PipelineStage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LinesIn", wireType)
+			}
+			m.LinesIn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LinesIn |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LinesOut", wireType)
+			}
+			m.LinesOut = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LinesOut |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesIn", wireType)
+			}
+			m.BytesIn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesIn |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WallTime", wireType)
+			}
+			m.WallTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WallTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthStats
 			}
 			if (iNdEx + skippy) > l {
@@ -4280,6 +5245,292 @@ func (m *Cache) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *Analysis) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Analysis: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Analysis: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nodes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nodes = append(m.Nodes, AnalysisNode{})
+			if err := m.Nodes[len(m.Nodes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AnalysisNode) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStats
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AnalysisNode: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AnalysisNode: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStats
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStats
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Depth", wireType)
+			}
+			m.Depth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Depth |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WallTime", wireType)
+			}
+			m.WallTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WallTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LinesIn", wireType)
+			}
+			m.LinesIn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LinesIn |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LinesOut", wireType)
+			}
+			m.LinesOut = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LinesOut |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesProcessed", wireType)
+			}
+			m.BytesProcessed = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesProcessed |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shards", wireType)
+			}
+			m.Shards = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Shards |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStats(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStats
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipStats(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -33,6 +33,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/collectors/version"
 	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 
 	"github.com/grafana/loki/v3/pkg/analytics"
 	"github.com/grafana/loki/v3/pkg/bloombuild/builder"
@@ -51,11 +52,14 @@ import (
 	ingesterkafka "github.com/grafana/loki/v3/pkg/kafka/ingester"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/macros"
+	"github.com/grafana/loki/v3/pkg/logql/queryblocks"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend/transport"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend/v1/frontendv1pb"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend/v2/frontendv2pb"
+	"github.com/grafana/loki/v3/pkg/lokifrontend/queryhistory"
 	"github.com/grafana/loki/v3/pkg/pattern"
 	"github.com/grafana/loki/v3/pkg/querier"
 	querierrf1 "github.com/grafana/loki/v3/pkg/querier-rf1"
@@ -63,6 +67,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/ruler"
 	base_ruler "github.com/grafana/loki/v3/pkg/ruler/base"
+	"github.com/grafana/loki/v3/pkg/ruler/rulestore"
 	"github.com/grafana/loki/v3/pkg/runtime"
 	"github.com/grafana/loki/v3/pkg/scheduler"
 	"github.com/grafana/loki/v3/pkg/scheduler/schedulerpb"
@@ -110,6 +115,8 @@ const (
 	IngesterQuerier          string = "ingester-querier"
 	IngesterGRPCInterceptors string = "ingester-query-tags-interceptors"
 	QueryFrontend            string = "query-frontend"
+	QueryBlocks              string = "query-blocks"
+	QueryHistory             string = "query-history"
 	QueryFrontendTripperware string = "query-frontend-tripperware"
 	QueryLimiter             string = "query-limiter"
 	QueryLimitsInterceptors  string = "query-limits-interceptors"
@@ -351,6 +358,8 @@ func (t *Loki) initDistributor() (services.Service, error) {
 	if !t.Cfg.isTarget(All) && !t.Cfg.isTarget(Write) && !t.Cfg.isTarget(Ingester) {
 		logproto.RegisterPusherServer(t.Server.GRPC, t.distributor)
 	}
+	// Register the distributor to receive OTLP logs over GRPC
+	plogotlp.RegisterGRPCServer(t.Server.GRPC, t.distributor.OTLPLogsServer())
 
 	httpPushHandlerMiddleware := middleware.Merge(
 		serverutil.RecoveryHTTPMiddleware,
@@ -359,6 +368,12 @@ func (t *Loki) initDistributor() (services.Service, error) {
 
 	lokiPushHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.PushHandler))
 	otlpPushHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.OTLPPushHandler))
+	elasticsearchBulkHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.ElasticsearchBulkHandler))
+	splunkHECHandler := httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.SplunkHECHandler))
+	if t.Cfg.Distributor.SplunkHEC.TenantsFromTokens() {
+		// the tenant of the HEC requests is the tenant of their token.
+		splunkHECHandler = serverutil.RecoveryHTTPMiddleware.Wrap(http.HandlerFunc(t.distributor.SplunkHECHandler))
+	}
 
 	t.Server.HTTP.Path("/distributor/ring").Methods("GET", "POST").Handler(t.distributor)
 
@@ -369,6 +384,12 @@ func (t *Loki) initDistributor() (services.Service, error) {
 	t.Server.HTTP.Path("/api/prom/push").Methods("POST").Handler(lokiPushHandler)
 	t.Server.HTTP.Path("/loki/api/v1/push").Methods("POST").Handler(lokiPushHandler)
 	t.Server.HTTP.Path("/otlp/v1/logs").Methods("POST").Handler(otlpPushHandler)
+	t.Server.HTTP.Path("/elasticsearch").Methods("GET").HandlerFunc(t.distributor.ElasticsearchInfoHandler)
+	t.Server.HTTP.Path("/elasticsearch/").Methods("GET").HandlerFunc(t.distributor.ElasticsearchInfoHandler)
+	t.Server.HTTP.Path("/elasticsearch/_bulk").Methods("POST", "PUT").Handler(elasticsearchBulkHandler)
+	t.Server.HTTP.Path("/elasticsearch/{index}/_bulk").Methods("POST", "PUT").Handler(elasticsearchBulkHandler)
+	t.Server.HTTP.Path("/services/collector").Methods("POST").Handler(splunkHECHandler)
+	t.Server.HTTP.Path("/services/collector/event").Methods("POST").Handler(splunkHECHandler)
 	return t.distributor, nil
 }
 
@@ -416,7 +437,7 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	}
 
 	if t.Cfg.Querier.MultiTenantQueriesEnabled {
-		t.Querier = querier.NewMultiTenantQuerier(t.Querier, util_log.Logger)
+		t.Querier = querier.NewMultiTenantQuerier(t.Querier, t.Overrides, util_log.Logger)
 	}
 
 	querierWorkerServiceConfig := querier.WorkerServiceConfig{
@@ -433,6 +454,9 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	toMerge := []middleware.Interface{
 		httpreq.ExtractQueryMetricsMiddleware(),
 		httpreq.ExtractQueryTagsMiddleware(),
+		httpreq.ExtractExplainMiddleware(),
+		httpreq.ExtractPipelineStatsMiddleware(),
+		httpreq.ExtractSampleMiddleware(),
 		httpreq.PropagateHeadersMiddleware(httpreq.LokiEncodingFlagsHeader, httpreq.LokiDisablePipelineWrappersHeader),
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
@@ -1109,19 +1133,38 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		level.Debug(util_log.Logger).Log("msg", "no query frontend configured")
 	}
 
-	roundTripper := queryrange.NewSerializeRoundTripper(t.QueryFrontEndMiddleware.Wrap(frontendTripper), queryrange.DefaultCodec)
+	// The running queries are tracked in this frontend only, they are listed and canceled by this frontend's API.
+	runningQueries := queryrange.NewRunningQueries()
+	// The sums and counts of sampled queries are scaled before the queries are split and sharded.
+	frontendMiddlewares := []queryrangebase.Middleware{runningQueries.Middleware(), queryrange.NewSampleMiddleware(t.Cfg.SchemaConfig.Configs), t.QueryFrontEndMiddleware}
+	if t.queryBlocks != nil {
+		// blocked queries are rejected before they are tracked as running.
+		frontendMiddlewares = append([]queryrangebase.Middleware{queryrange.NewQueryBlocksMiddleware(t.queryBlocks, log.With(util_log.Logger, "component", "query-blocks"))}, frontendMiddlewares...)
+	}
+	roundTripper := queryrange.NewSerializeRoundTripper(
+		queryrangebase.MergeMiddlewares(frontendMiddlewares...).Wrap(runningQueries.WrapDownstream(frontendTripper)),
+		queryrange.DefaultCodec.WithOTLPLimits(t.Overrides),
+	)
 
 	frontendHandler := transport.NewHandler(t.Cfg.Frontend.Handler, roundTripper, util_log.Logger, prometheus.DefaultRegisterer, t.Cfg.MetricsNamespace)
 	if t.Cfg.Frontend.CompressResponses {
 		frontendHandler = gziphandler.GzipHandler(frontendHandler)
 	}
 
+	statsHTTPMiddleware := queryrange.StatsHTTPMiddleware
+	if t.queryHistory != nil {
+		statsHTTPMiddleware = queryrange.NewStatsHTTPMiddleware(t.queryHistory)
+	}
+
 	toMerge := []middleware.Interface{
 		httpreq.ExtractQueryTagsMiddleware(),
+		httpreq.ExtractExplainMiddleware(),
+		httpreq.ExtractPipelineStatsMiddleware(),
+		httpreq.ExtractSampleMiddleware(),
 		httpreq.PropagateHeadersMiddleware(httpreq.LokiActorPathHeader, httpreq.LokiEncodingFlagsHeader, httpreq.LokiDisablePipelineWrappersHeader),
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
-		queryrange.StatsHTTPMiddleware,
+		statsHTTPMiddleware,
 		serverutil.NewPrepopulateMiddleware(),
 		serverutil.ResponseJSONMiddleware(),
 	}
@@ -1131,7 +1174,12 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		toMerge = append(toMerge, querylimits.NewQueryLimitsMiddleware(logger))
 	}
 
-	frontendHandler = middleware.Merge(toMerge...).Wrap(frontendHandler)
+	if t.macroResolver != nil {
+		toMerge = append(toMerge, macros.NewMiddleware(t.macroResolver))
+	}
+
+	frontendMiddleware := middleware.Merge(toMerge...)
+	frontendHandler = frontendMiddleware.Wrap(frontendHandler)
 
 	var defaultHandler http.Handler
 	// If this process also acts as a Querier we don't do any proxying of tail requests
@@ -1183,6 +1231,18 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	t.Server.HTTP.Path("/api/prom/label").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/series").Methods("GET", "POST").Handler(frontendHandler)
+	t.Server.HTTP.Path("/loki/api/v1/queries").Methods("GET").Handler(frontendMiddleware.Wrap(http.HandlerFunc(runningQueries.ListHandler)))
+	t.Server.HTTP.Path("/loki/api/v1/queries/{id}").Methods("DELETE").Handler(frontendMiddleware.Wrap(http.HandlerFunc(runningQueries.CancelHandler)))
+
+	if t.queryBlocks != nil {
+		t.Server.HTTP.Path("/loki/api/v1/query_blocks").Methods("GET").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.queryBlocks.ListHandler)))
+		t.Server.HTTP.Path("/loki/api/v1/query_blocks").Methods("POST").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.queryBlocks.CreateHandler)))
+		t.Server.HTTP.Path("/loki/api/v1/query_blocks/{id}").Methods("DELETE").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.queryBlocks.DeleteHandler)))
+		t.Server.HTTP.Path("/query_blocks").Methods("GET").Handler(http.HandlerFunc(t.queryBlocks.StatusHandler))
+	}
+	if t.queryHistory != nil {
+		t.Server.HTTP.Path("/loki/api/v1/query_history").Methods("GET").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.queryHistory.Handler)))
+	}
 
 	// Only register tailing requests if this process does not act as a Querier
 	// If this process is also a Querier the Querier will register the tail endpoints.
@@ -1218,6 +1278,41 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	}), nil
 }
 
+func (t *Loki) initQueryBlocks() (services.Service, error) {
+	if !t.Cfg.Frontend.QueryBlocks.Enabled {
+		return nil, nil
+	}
+
+	t.Cfg.Frontend.QueryBlocks.KVStore.MemberlistKV = t.MemberlistKV.GetMemberlistKV
+	manager, err := queryblocks.NewManager(t.Cfg.Frontend.QueryBlocks, prometheus.DefaultRegisterer, log.With(util_log.Logger, "component", "query-blocks"))
+	if err != nil {
+		return nil, err
+	}
+	t.queryBlocks = manager
+	return manager, nil
+}
+
+func (t *Loki) initQueryHistory() (services.Service, error) {
+	if !t.Cfg.Frontend.QueryHistory.Enabled {
+		return nil, nil
+	}
+
+	store := t.Cfg.Frontend.QueryHistory.Store
+	if store == "" {
+		period, err := t.Cfg.SchemaConfig.SchemaForTime(model.Now())
+		if err != nil {
+			return nil, err
+		}
+		store = period.ObjectType
+	}
+	objectClient, err := storage.NewObjectClient(store, t.Cfg.StorageConfig, t.ClientMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the query history object client: %w", err)
+	}
+	t.queryHistory = queryhistory.NewRecorder(t.Cfg.Frontend.QueryHistory, objectClient, prometheus.DefaultRegisterer, log.With(util_log.Logger, "component", "query-history"))
+	return t.queryHistory, nil
+}
+
 func (t *Loki) initRulerStorage() (_ services.Service, err error) {
 	// if the ruler is not configured and we're in single binary then let's just log an error and continue.
 	// unfortunately there is no way to generate a "default" config and compare default against actual
@@ -1228,6 +1323,11 @@ func (t *Loki) initRulerStorage() (_ services.Service, err error) {
 		level.Info(util_log.Logger).Log("msg", "Ruler storage is not configured; ruler will not be started.")
 		return
 	}
+	// the query frontend only uses the ruler storage to resolve pipeline macros.
+	if !t.Cfg.isTarget(Ruler) && t.Cfg.Ruler.StoreConfig.IsDefaults() {
+		level.Info(util_log.Logger).Log("msg", "Ruler storage is not configured; pipeline macros will not be resolved.")
+		return
+	}
 
 	// Make sure storage directory exists if using filesystem store
 	if t.Cfg.Ruler.StoreConfig.Type == "local" && t.Cfg.Ruler.StoreConfig.Local.Directory != "" {
@@ -1238,6 +1338,13 @@ func (t *Loki) initRulerStorage() (_ services.Service, err error) {
 	}
 
 	t.RulerStorage, err = base_ruler.NewLegacyRuleStore(t.Cfg.Ruler.StoreConfig, t.Cfg.StorageConfig.Hedging, t.ClientMetrics, ruler.GroupLoader{}, util_log.Logger)
+	if err != nil {
+		return
+	}
+
+	if macroStore, ok := t.RulerStorage.(rulestore.MacroStore); ok {
+		t.macroResolver = macros.NewResolver(macroStore, macros.DefaultCacheTTL)
+	}
 
 	return
 }
@@ -1299,6 +1406,12 @@ func (t *Loki) initRuler() (_ services.Service, err error) {
 		t.Server.HTTP.Path("/loki/api/v1/rules/{namespace}").Methods("DELETE").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.rulerAPI.DeleteNamespace)))
 		t.Server.HTTP.Path("/loki/api/v1/rules/{namespace}/{groupName}").Methods("GET").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.rulerAPI.GetRuleGroup)))
 		t.Server.HTTP.Path("/loki/api/v1/rules/{namespace}/{groupName}").Methods("DELETE").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.rulerAPI.DeleteRuleGroup)))
+
+		// Pipeline macros API endpoints
+		t.Server.HTTP.Path("/loki/api/v1/macros").Methods("GET").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.rulerAPI.ListMacros)))
+		t.Server.HTTP.Path("/loki/api/v1/macros/{name}").Methods("GET").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.rulerAPI.GetMacro)))
+		t.Server.HTTP.Path("/loki/api/v1/macros/{name}").Methods("POST").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.rulerAPI.CreateMacro)))
+		t.Server.HTTP.Path("/loki/api/v1/macros/{name}").Methods("DELETE").Handler(t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.rulerAPI.DeleteMacro)))
 	}
 
 	deleteStore, err := t.deleteRequestsClient("ruler", t.Overrides)
@@ -1348,6 +1461,7 @@ func (t *Loki) initRuleEvaluator() (services.Service, error) {
 		return nil, fmt.Errorf("failed to create %s rule evaluator: %w", mode, err)
 	}
 
+	evaluator = ruler.NewEvaluatorWithMacros(evaluator, t.macroResolver)
 	t.ruleEvaluator = ruler.NewEvaluatorWithJitter(evaluator, t.Cfg.Ruler.Evaluation.MaxJitter, fnv.New32a(), logger)
 
 	return nil, nil
@@ -1360,6 +1474,7 @@ func (t *Loki) initMemberlistKV() (services.Service, error) {
 	t.Cfg.MemberlistKV.Codecs = []codec.Codec{
 		ring.GetCodec(),
 		analytics.JSONCodec,
+		queryblocks.JSONCodec,
 		ring.GetPartitionRingCodec(),
 	}
 
@@ -5,9 +5,11 @@ import (
 
 	"github.com/grafana/dskit/crypto/tls"
 
+	"github.com/grafana/loki/v3/pkg/logql/queryblocks"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend/transport"
 	v1 "github.com/grafana/loki/v3/pkg/lokifrontend/frontend/v1"
 	v2 "github.com/grafana/loki/v3/pkg/lokifrontend/frontend/v2"
+	"github.com/grafana/loki/v3/pkg/lokifrontend/queryhistory"
 )
 
 type Config struct {
@@ -20,6 +22,9 @@ type Config struct {
 
 	TailProxyURL string           `yaml:"tail_proxy_url"`
 	TLS          tls.ClientConfig `yaml:"tail_tls_config"`
+
+	QueryBlocks  queryblocks.Config  `yaml:"query_blocks"`
+	QueryHistory queryhistory.Config `yaml:"query_history"`
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet.
@@ -28,8 +33,15 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.FrontendV1.RegisterFlags(f)
 	cfg.FrontendV2.RegisterFlags(f)
 	cfg.TLS.RegisterFlagsWithPrefix("frontend.tail-tls-config", f)
+	cfg.QueryBlocks.RegisterFlagsWithPrefix("frontend.query-blocks.", f)
+	cfg.QueryHistory.RegisterFlagsWithPrefix("frontend.query-history.", f)
 
 	f.BoolVar(&cfg.CompressResponses, "querier.compress-http-responses", true, "Compress HTTP responses.")
 	f.StringVar(&cfg.DownstreamURL, "frontend.downstream-url", "", "URL of downstream Loki.")
 	f.StringVar(&cfg.TailProxyURL, "frontend.tail-proxy-url", "", "URL of querier for tail proxy.")
 }
+
+// Validate validates the config.
+func (cfg *Config) Validate() error {
+	return cfg.QueryHistory.Validate()
+}
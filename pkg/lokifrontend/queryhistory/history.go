@@ -0,0 +1,401 @@
+// Package queryhistory records the log and metric queries executed by the query frontend per tenant in the object
+// store. It doesn't support saved queries: only executed queries are recorded, and they can't be named or pinned.
+package queryhistory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/services"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+)
+
+// dayLayout is the layout of the day partitioning the records of a tenant in the object store.
+const dayLayout = "2006-01-02"
+
+// retentionPeriod is how often the days of the query history older than the retention are deleted.
+const retentionPeriod = time.Hour
+
+type Config struct {
+	Enabled        bool          `yaml:"enabled"`
+	Store          string        `yaml:"store"`
+	StoreKeyPrefix string        `yaml:"store_key_prefix"`
+	FlushPeriod    time.Duration `yaml:"flush_period"`
+	MaxPending     int           `yaml:"max_pending_records"`
+	MaxQueryRange  time.Duration `yaml:"max_query_range"`
+	Retention      time.Duration `yaml:"retention_period"`
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Record the log and metric queries executed by the query frontend in the object store, with their time range, duration, bytes processed, status and user agent. The records of a tenant are queried with the /loki/api/v1/query_history endpoint.")
+	f.StringVar(&cfg.Store, prefix+"store", "", "Object store of the query history, for example s3 or gcs. Defaults to the object store of the current period of the schema config.")
+	f.StringVar(&cfg.StoreKeyPrefix, prefix+"store.key-prefix", "query-history/", "Path prefix of the query history in the object store.")
+	f.DurationVar(&cfg.FlushPeriod, prefix+"flush-period", time.Minute, "How often the recorded queries are flushed to the object store.")
+	f.IntVar(&cfg.MaxPending, prefix+"max-pending-records", 10000, "Maximum number of recorded queries waiting to be flushed. Queries recorded above this limit are dropped.")
+	f.DurationVar(&cfg.MaxQueryRange, prefix+"max-query-range", 30*24*time.Hour, "Maximum time range of the requests to the /loki/api/v1/query_history endpoint.")
+	f.DurationVar(&cfg.Retention, prefix+"retention-period", 30*24*time.Hour, "How long the recorded queries are kept in the object store. The days of the history older than the retention period are deleted by the query frontends. 0 disables the retention.")
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if err := config.ValidatePathPrefix(cfg.StoreKeyPrefix); err != nil {
+		return fmt.Errorf("validate query history store path prefix: %w", err)
+	}
+	if cfg.FlushPeriod <= 0 {
+		return fmt.Errorf("the query history flush period must be positive")
+	}
+	if cfg.MaxQueryRange <= 0 {
+		return fmt.Errorf("the query history max query range must be positive")
+	}
+	if cfg.Retention < 0 {
+		return fmt.Errorf("the query history retention period must not be negative")
+	}
+	return nil
+}
+
+type metrics struct {
+	recorded *prometheus.CounterVec
+	dropped  *prometheus.CounterVec
+	flushes  *prometheus.CounterVec
+	deleted  prometheus.Counter
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	return &metrics{
+		recorded: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_history_records_total",
+			Help:      "Total number of queries recorded in the query history.",
+		}, []string{"tenant"}),
+		dropped: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_history_records_dropped_total",
+			Help:      "Total number of queries not recorded in the query history because too many records were waiting to be flushed.",
+		}, []string{"tenant"}),
+		flushes: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_history_flushes_total",
+			Help:      "Total number of flushes of the query history to the object store.",
+		}, []string{"status"}),
+		deleted: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_history_objects_deleted_total",
+			Help:      "Total number of query history objects deleted from the object store because they were older than the retention period.",
+		}),
+	}
+}
+
+// Recorder records the queries executed by the query frontend. The records are buffered in memory and periodically
+// flushed to the object store, in an object per tenant, day and flush, so the history of a tenant is listed by day.
+// The days older than the retention period are periodically deleted.
+type Recorder struct {
+	services.Service
+
+	cfg      Config
+	client   client.ObjectClient
+	logger   log.Logger
+	metrics  *metrics
+	instance string
+	now      func() time.Time
+
+	mtx sync.Mutex
+	// pending are the records of each tenant waiting to be flushed.
+	pending      map[string][]loghttp.QueryHistoryRecord
+	pendingCount int
+
+	// lastRetention is when the retention was last applied, it's only used by the service loop.
+	lastRetention time.Time
+}
+
+func NewRecorder(cfg Config, objectClient client.ObjectClient, reg prometheus.Registerer, logger log.Logger) *Recorder {
+	instance, err := os.Hostname()
+	if err != nil || instance == "" {
+		instance = "query-frontend"
+	}
+
+	r := &Recorder{
+		cfg:      cfg,
+		client:   objectClient,
+		logger:   logger,
+		metrics:  newMetrics(reg),
+		instance: instance,
+		now:      time.Now,
+		pending:  map[string][]loghttp.QueryHistoryRecord{},
+	}
+	r.Service = services.NewTimerService(cfg.FlushPeriod, nil, r.iteration, r.stopping).WithName("query history")
+	return r
+}
+
+// RecordQuery adds the record to the history of the tenant.
+func (r *Recorder) RecordQuery(tenantID string, record loghttp.QueryHistoryRecord) {
+	if !r.add(tenantID, record) {
+		r.metrics.dropped.WithLabelValues(tenantID).Inc()
+		return
+	}
+	r.metrics.recorded.WithLabelValues(tenantID).Inc()
+}
+
+// add adds the record to the pending records of the tenant, unless there are already too many pending records.
+func (r *Recorder) add(tenantID string, record loghttp.QueryHistoryRecord) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.pendingCount >= r.cfg.MaxPending {
+		return false
+	}
+	r.pending[tenantID] = append(r.pending[tenantID], record)
+	r.pendingCount++
+	return true
+}
+
+func (r *Recorder) iteration(ctx context.Context) error {
+	if err := r.flush(ctx); err != nil {
+		level.Error(r.logger).Log("msg", "failed to flush the query history", "err", err)
+	}
+	if now := r.now(); r.cfg.Retention > 0 && now.Sub(r.lastRetention) >= retentionPeriod {
+		r.lastRetention = now
+		if err := r.applyRetention(ctx, now); err != nil {
+			level.Error(r.logger).Log("msg", "failed to apply the query history retention", "err", err)
+		}
+	}
+	return nil
+}
+
+// applyRetention deletes the objects of the days older than the retention period, the days are deleted once all
+// their records are older than the retention. All the query frontends apply the retention, so the objects already
+// deleted by the others are ignored.
+func (r *Recorder) applyRetention(ctx context.Context, now time.Time) error {
+	cutoff := now.Add(-r.cfg.Retention).UTC().Format(dayLayout)
+
+	_, tenants, err := r.client.List(ctx, r.cfg.StoreKeyPrefix, "/")
+	if err != nil {
+		return fmt.Errorf("failed to list the query history tenants: %w", err)
+	}
+	for _, tenant := range tenants {
+		_, days, err := r.client.List(ctx, string(tenant), "/")
+		if err != nil {
+			return fmt.Errorf("failed to list the query history days: %w", err)
+		}
+		for _, day := range days {
+			// the days are formatted so they sort in time order.
+			if path.Base(string(day)) >= cutoff {
+				continue
+			}
+			objects, _, err := r.client.List(ctx, string(day), "/")
+			if err != nil {
+				return fmt.Errorf("failed to list the query history objects: %w", err)
+			}
+			for _, object := range objects {
+				if err := r.client.DeleteObject(ctx, object.Key); err != nil && !r.client.IsObjectNotFoundErr(err) {
+					return fmt.Errorf("failed to delete the query history object %s: %w", object.Key, err)
+				}
+				r.metrics.deleted.Inc()
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) stopping(_ error) error {
+	// flush the pending records on shutdown, the service context is already canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.FlushPeriod)
+	defer cancel()
+	if err := r.flush(ctx); err != nil {
+		level.Error(r.logger).Log("msg", "failed to flush the query history", "err", err)
+	}
+	return nil
+}
+
+// flush writes the pending records to the object store. The records failing to be written are kept pending,
+// within the limit of pending records.
+func (r *Recorder) flush(ctx context.Context) error {
+	r.mtx.Lock()
+	pending := r.pending
+	r.pending = map[string][]loghttp.QueryHistoryRecord{}
+	r.pendingCount = 0
+	r.mtx.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	now := r.now()
+	var lastErr error
+	for tenantID, records := range pending {
+		days := map[string][]loghttp.QueryHistoryRecord{}
+		for _, record := range records {
+			day := record.Timestamp.UTC().Format(dayLayout)
+			days[day] = append(days[day], record)
+		}
+
+		for day, records := range days {
+			if err := r.write(ctx, r.objectKey(tenantID, day, now), records); err != nil {
+				lastErr = err
+				r.metrics.flushes.WithLabelValues("failure").Inc()
+				for _, record := range records {
+					if !r.add(tenantID, record) {
+						r.metrics.dropped.WithLabelValues(tenantID).Inc()
+					}
+				}
+				continue
+			}
+			r.metrics.flushes.WithLabelValues("success").Inc()
+		}
+	}
+	return lastErr
+}
+
+func (r *Recorder) write(ctx context.Context, key string, records []loghttp.QueryHistoryRecord) error {
+	var buf bytes.Buffer
+	enc := jsoniter.ConfigFastest.NewEncoder(&buf)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return r.client.PutObject(ctx, key, &buf)
+}
+
+// objectKey returns the key of the object flushed at the given time. The flush time prefixes the name of the object
+// so the objects flushed before the start of a query are skipped.
+func (r *Recorder) objectKey(tenantID, day string, flushedAt time.Time) string {
+	return path.Join(r.cfg.StoreKeyPrefix, tenantID, day, fmt.Sprintf("%d-%s.json", flushedAt.UnixNano(), r.instance))
+}
+
+// Query returns the most recent records of the tenant completed between start and end, the flushed ones and the ones
+// pending in this query frontend. The time range must not be longer than the max query range, and it starts at the
+// retention period at the earliest.
+//
+// The days are read from the most recent, and the objects of a day from the last flushed. The records of an object
+// were completed before it was flushed, and the records of a day are more recent than the ones of the days before,
+// so the objects are read until limit records more recent than the other objects are found.
+func (r *Recorder) Query(ctx context.Context, tenantID string, start, end time.Time, limit int) ([]loghttp.QueryHistoryRecord, error) {
+	if end.Sub(start) > r.cfg.MaxQueryRange {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "the query history time range (%s) exceeds the limit (%s)", end.Sub(start), r.cfg.MaxQueryRange)
+	}
+	if r.cfg.Retention > 0 {
+		if retained := r.now().Add(-r.cfg.Retention); start.Before(retained) {
+			start = retained
+		}
+	}
+
+	var records []loghttp.QueryHistoryRecord
+	include := func(record loghttp.QueryHistoryRecord) {
+		if record.Timestamp.Before(start) || record.Timestamp.After(end) {
+			return
+		}
+		records = append(records, record)
+	}
+	// complete returns true if the limit of records is reached by records more recent than the given time.
+	complete := func(t time.Time) bool {
+		sort.Slice(records, func(i, j int) bool {
+			return records[i].Timestamp.After(records[j].Timestamp)
+		})
+		if limit > 0 && len(records) > limit {
+			records = records[:limit]
+		}
+		return limit > 0 && len(records) == limit && records[limit-1].Timestamp.After(t)
+	}
+
+	r.mtx.Lock()
+	for _, record := range r.pending[tenantID] {
+		include(record)
+	}
+	r.mtx.Unlock()
+
+	first := start.UTC().Truncate(24 * time.Hour)
+	for day := end.UTC().Truncate(24 * time.Hour); !day.Before(first); day = day.Add(-24 * time.Hour) {
+		if complete(day.Add(24 * time.Hour)) {
+			break
+		}
+		prefix := path.Join(r.cfg.StoreKeyPrefix, tenantID, day.Format(dayLayout)) + "/"
+		objects, _, err := r.client.List(ctx, prefix, "/")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the query history: %w", err)
+		}
+		flushTimes := make(map[string]time.Time, len(objects))
+		for _, object := range objects {
+			flushedAt, ok := flushTime(object.Key)
+			if !ok {
+				// objects without a flush time are read first.
+				flushedAt = time.Unix(0, math.MaxInt64)
+			}
+			flushTimes[object.Key] = flushedAt
+		}
+		sort.Slice(objects, func(i, j int) bool {
+			return flushTimes[objects[i].Key].After(flushTimes[objects[j].Key])
+		})
+		for _, object := range objects {
+			// the records of an object were completed before it was flushed.
+			flushedAt := flushTimes[object.Key]
+			if flushedAt.Before(start) || complete(flushedAt) {
+				break
+			}
+			if err := r.read(ctx, object.Key, include); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	complete(end)
+	return records, nil
+}
+
+func (r *Recorder) read(ctx context.Context, key string, fn func(loghttp.QueryHistoryRecord)) error {
+	reader, _, err := r.client.GetObject(ctx, key)
+	if err != nil {
+		if r.client.IsObjectNotFoundErr(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read the query history object %s: %w", key, err)
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var record loghttp.QueryHistoryRecord
+		if err := jsoniter.ConfigFastest.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("failed to decode the query history object %s: %w", key, err)
+		}
+		fn(record)
+	}
+	return scanner.Err()
+}
+
+// flushTime returns the flush time prefixing the name of an object, see objectKey.
+func flushTime(key string) (time.Time, bool) {
+	name := path.Base(key)
+	i := strings.IndexByte(name, '-')
+	if i < 0 {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(name[:i], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
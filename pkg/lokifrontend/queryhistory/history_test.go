@@ -0,0 +1,193 @@
+package queryhistory
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/testutils"
+)
+
+func newTestRecorder() (*Recorder, *testutils.InMemoryObjectClient) {
+	cfg := Config{}
+	cfg.RegisterFlagsWithPrefix("", flag.NewFlagSet("", flag.PanicOnError))
+	objectClient := testutils.NewInMemoryObjectClient()
+	return NewRecorder(cfg, objectClient, prometheus.NewRegistry(), log.NewNopLogger()), objectClient
+}
+
+func record(query string, ts time.Time) loghttp.QueryHistoryRecord {
+	return loghttp.QueryHistoryRecord{Timestamp: ts.UTC(), Query: query, Type: "range", Start: ts.Add(-time.Hour).UTC(), End: ts.UTC(), Status: "200"}
+}
+
+func TestRecorder(t *testing.T) {
+	r, objectClient := newTestRecorder()
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 0, 30, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	yesterday := record(`{app="foo"}`, now.Add(-time.Hour))
+	today := record(`{app="bar"}`, now.Add(-time.Minute))
+	r.RecordQuery("tenant", yesterday)
+	r.RecordQuery("tenant", today)
+	r.RecordQuery("other", record(`{app="baz"}`, now))
+
+	// pending records are queried.
+	records, err := r.Query(ctx, "tenant", now.Add(-2*time.Hour), now, 100)
+	require.NoError(t, err)
+	require.Equal(t, []loghttp.QueryHistoryRecord{today, yesterday}, records)
+
+	// the records are flushed in an object per tenant and day.
+	require.NoError(t, r.flush(ctx))
+	require.Len(t, objectClient.Internals(), 3)
+	require.Contains(t, objectClient.Internals(), "query-history/tenant/2024-05-01/1714609800000000000-"+r.instance+".json")
+
+	later := record(`{app="foo"} |= "error"`, now.Add(time.Minute))
+	r.RecordQuery("tenant", later)
+
+	records, err = r.Query(ctx, "tenant", now.Add(-2*time.Hour), now.Add(time.Hour), 100)
+	require.NoError(t, err)
+	require.Equal(t, []loghttp.QueryHistoryRecord{later, today, yesterday}, records)
+
+	// the most recent records within the time range are returned.
+	records, err = r.Query(ctx, "tenant", now.Add(-2*time.Hour), now.Add(time.Hour), 2)
+	require.NoError(t, err)
+	require.Equal(t, []loghttp.QueryHistoryRecord{later, today}, records)
+	records, err = r.Query(ctx, "tenant", now.Add(-30*time.Minute), now, 100)
+	require.NoError(t, err)
+	require.Equal(t, []loghttp.QueryHistoryRecord{today}, records)
+
+	records, err = r.Query(ctx, "unknown", now.Add(-2*time.Hour), now.Add(time.Hour), 100)
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestRecorder_MaxPending(t *testing.T) {
+	r, _ := newTestRecorder()
+	r.cfg.MaxPending = 1
+	now := time.Now()
+
+	r.RecordQuery("tenant", record(`{app="foo"}`, now))
+	r.RecordQuery("tenant", record(`{app="bar"}`, now))
+
+	records, err := r.Query(context.Background(), "tenant", now.Add(-time.Minute), now.Add(time.Minute), 100)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, `{app="foo"}`, records[0].Query)
+}
+
+func TestRecorder_Handler(t *testing.T) {
+	r, _ := newTestRecorder()
+	now := time.Now()
+	r.RecordQuery("tenant", record(`{app="foo"}`, now))
+
+	do := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/loki/api/v1/query_history?"+query, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+		w := httptest.NewRecorder()
+		r.Handler(w, req)
+		return w
+	}
+
+	w := do("limit=-1")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do("since=1h")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp loghttp.QueryHistoryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "success", resp.Status)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, `{app="foo"}`, resp.Data[0].Query)
+}
+
+func TestRecorder_MaxQueryRange(t *testing.T) {
+	r, _ := newTestRecorder()
+	now := time.Now()
+
+	_, err := r.Query(context.Background(), "tenant", now.Add(-31*24*time.Hour), now, 100)
+	require.Error(t, err)
+
+	req := httptest.NewRequest("GET", "/loki/api/v1/query_history?start=0", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+	w := httptest.NewRecorder()
+	r.Handler(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRecorder_Retention(t *testing.T) {
+	r, objectClient := newTestRecorder()
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	expired := record(`{app="foo"}`, now.Add(-31*24*time.Hour))
+	retained := record(`{app="bar"}`, now.Add(-30*24*time.Hour+time.Hour))
+	r.RecordQuery("tenant", expired)
+	r.RecordQuery("tenant", retained)
+	r.RecordQuery("other", record(`{app="baz"}`, now))
+	require.NoError(t, r.flush(ctx))
+	require.Len(t, objectClient.Internals(), 3)
+
+	require.NoError(t, r.iteration(ctx))
+	require.Len(t, objectClient.Internals(), 2)
+	require.Equal(t, now, r.lastRetention)
+
+	records, err := r.Query(ctx, "tenant", now.Add(-30*24*time.Hour), now, 100)
+	require.NoError(t, err)
+	require.Equal(t, []loghttp.QueryHistoryRecord{retained}, records)
+}
+
+// countingObjectClient counts the objects read.
+type countingObjectClient struct {
+	client.ObjectClient
+	reads int
+}
+
+func (c *countingObjectClient) GetObject(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	c.reads++
+	return c.ObjectClient.GetObject(ctx, key)
+}
+
+func TestRecorder_QueryReadsRecentObjects(t *testing.T) {
+	r, objectClient := newTestRecorder()
+	counting := &countingObjectClient{ObjectClient: objectClient}
+	r.client = counting
+	ctx := context.Background()
+	now := time.Date(2024, 5, 20, 12, 0, 0, 0, time.UTC)
+
+	// an object per flush, two flushes per day over ten days.
+	var expected []loghttp.QueryHistoryRecord
+	for i := 0; i < 20; i++ {
+		flushedAt := now.Add(-time.Duration(i) * 12 * time.Hour)
+		r.now = func() time.Time { return flushedAt }
+		rec := record(`{app="foo"}`, flushedAt.Add(-time.Minute))
+		r.RecordQuery("tenant", rec)
+		require.NoError(t, r.flush(ctx))
+		expected = append(expected, rec)
+	}
+	r.now = func() time.Time { return now }
+
+	records, err := r.Query(ctx, "tenant", now.Add(-30*24*time.Hour), now, 3)
+	require.NoError(t, err)
+	require.Equal(t, expected[:3], records)
+	// the third object is read, then the fourth is known to hold older records.
+	require.Equal(t, 3, counting.reads)
+
+	counting.reads = 0
+	records, err = r.Query(ctx, "tenant", now.Add(-30*24*time.Hour), now, 100)
+	require.NoError(t, err)
+	require.Equal(t, expected, records)
+	require.Equal(t, 20, counting.reads)
+}
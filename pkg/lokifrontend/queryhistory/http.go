@@ -0,0 +1,37 @@
+package queryhistory
+
+import (
+	"net/http"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/util"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+// Handler returns the most recent records of the query history of the tenant of the request.
+func (r *Recorder) Handler(w http.ResponseWriter, req *http.Request) {
+	tenantID, err := tenant.TenantID(req.Context())
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error()), w)
+		return
+	}
+
+	params, err := loghttp.ParseQueryHistoryQuery(req)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error()), w)
+		return
+	}
+
+	records, err := r.Query(req.Context(), tenantID, params.Start, params.End, int(params.Limit))
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	if records == nil {
+		records = []loghttp.QueryHistoryRecord{}
+	}
+	util.WriteJSONResponse(w, loghttp.QueryHistoryResponse{Status: "success", Data: records})
+}
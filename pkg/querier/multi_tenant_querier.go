@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/storage/stores/index/seriesvolume"
@@ -11,6 +12,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/dskit/tenant"
@@ -28,20 +30,67 @@ const (
 	retainExistingPrefix = "original_"
 )
 
+// MultiTenantLimits are the limits restricting the data of each tenant of a multi-tenant query.
+type MultiTenantLimits interface {
+	MaxQueryLookback(context.Context, string) time.Duration
+}
+
 // MultiTenantQuerier is able to query across different tenants.
 type MultiTenantQuerier struct {
 	Querier
+	limits MultiTenantLimits
 	logger log.Logger
 }
 
 // NewMultiTenantQuerier returns a new querier able to query across different tenants.
-func NewMultiTenantQuerier(querier Querier, logger log.Logger) *MultiTenantQuerier {
+// The data of each tenant is queried within the tenant's own max query lookback. Like for single-tenant queries,
+// the retention of the data is left to the compactor, as it may differ between the streams of a tenant.
+func NewMultiTenantQuerier(querier Querier, limits MultiTenantLimits, logger log.Logger) *MultiTenantQuerier {
 	return &MultiTenantQuerier{
 		Querier: querier,
+		limits:  limits,
 		logger:  logger,
 	}
 }
 
+// minStart returns the start of the data of the tenant which can be queried, zero if it isn't limited.
+func (q *MultiTenantQuerier) minStart(ctx context.Context, tenantID string) time.Time {
+	lookback := q.limits.MaxQueryLookback(ctx, tenantID)
+	if lookback <= 0 {
+		return time.Time{}
+	}
+	return nowFunc().Add(-lookback)
+}
+
+// tenantStart returns the start of the time range of the query within the data of the tenant which can be queried.
+// It returns false if none of the data of the tenant can be queried.
+func (q *MultiTenantQuerier) tenantStart(ctx context.Context, tenantID string, start, end time.Time) (time.Time, bool) {
+	minStart := q.minStart(ctx, tenantID)
+	if minStart.IsZero() || !start.Before(minStart) {
+		return start, true
+	}
+	if !end.After(minStart) {
+		level.Debug(q.logger).Log("msg", "skipping tenant of multi-tenant query outside of its max query lookback", "tenant", tenantID, "start", start, "end", end)
+		return time.Time{}, false
+	}
+	return minStart, true
+}
+
+// tenantRangeQuery returns the range query clamped to the data of the tenant which can be queried.
+// It returns false if none of the data of the tenant can be queried.
+func (q *MultiTenantQuerier) tenantRangeQuery(ctx context.Context, tenantID string, req *loghttp.RangeQuery) (*loghttp.RangeQuery, bool) {
+	start, ok := q.tenantStart(ctx, tenantID, req.Start, req.End)
+	if !ok {
+		return nil, false
+	}
+	if start.Equal(req.Start) {
+		return req, true
+	}
+	r := *req
+	r.Start = start
+	return &r, true
+}
+
 func (q *MultiTenantQuerier) SelectLogs(ctx context.Context, params logql.SelectLogParams) (iter.EntryIterator, error) {
 	tenantIDs, err := tenant.TenantIDs(ctx)
 	if err != nil {
@@ -73,12 +122,20 @@ func (q *MultiTenantQuerier) SelectLogs(ctx context.Context, params logql.Select
 		storeOverridesByTenant = partitionChunkRefsByTenant(overrides.Refs)
 	}
 
-	iters := make([]iter.EntryIterator, len(matchedTenants))
-	i := 0
+	iters := make([]iter.EntryIterator, 0, len(matchedTenants))
 	for id := range matchedTenants {
+		start, ok := q.tenantStart(ctx, id, params.Start, params.End)
+		if !ok {
+			continue
+		}
 		singleContext := user.InjectOrgID(ctx, id)
 
 		tenantParams := params
+		if !start.Equal(params.Start) {
+			req := *params.QueryRequest
+			req.Start = start
+			tenantParams = logql.SelectLogParams{QueryRequest: &req}
+		}
 
 		if tenantChunkOverrides, ok := storeOverridesByTenant[id]; ok {
 			tenantParams = tenantParams.WithStoreChunks(&logproto.ChunkRefGroup{Refs: tenantChunkOverrides})
@@ -89,8 +146,7 @@ func (q *MultiTenantQuerier) SelectLogs(ctx context.Context, params logql.Select
 			return nil, err
 		}
 
-		iters[i] = NewTenantEntryIterator(iter, id)
-		i++
+		iters = append(iters, NewTenantEntryIterator(iter, id))
 	}
 	return iter.NewSortEntryIterator(iters, params.Direction), nil
 }
@@ -109,7 +165,13 @@ func (q *MultiTenantQuerier) SelectSamples(ctx context.Context, params logql.Sel
 	if err != nil {
 		return nil, err
 	}
-	params.Selector = updatedSelector.String()
+	// the queriers evaluate the plan, it must not match the tenant label either.
+	req := *params.SampleQueryRequest
+	req.Selector = updatedSelector.String()
+	req.Plan = &plan.QueryPlan{
+		AST: updatedSelector,
+	}
+	params = logql.SelectSampleParams{SampleQueryRequest: &req}
 
 	// in case of multiple tenants, we need to filter the store chunks by tenant if they are provided
 	storeOverridesByTenant := make(map[string][]*logproto.ChunkRef)
@@ -117,11 +179,20 @@ func (q *MultiTenantQuerier) SelectSamples(ctx context.Context, params logql.Sel
 		storeOverridesByTenant = partitionChunkRefsByTenant(params.GetStoreChunks().Refs)
 	}
 
-	iters := make([]iter.SampleIterator, len(matchedTenants))
-	i := 0
+	iters := make([]iter.SampleIterator, 0, len(matchedTenants))
 	for id := range matchedTenants {
+		start, ok := q.tenantStart(ctx, id, params.Start, params.End)
+		if !ok {
+			continue
+		}
 		singleContext := user.InjectOrgID(ctx, id)
+
 		tenantParams := params
+		if !start.Equal(params.Start) {
+			req := *params.SampleQueryRequest
+			req.Start = start
+			tenantParams = logql.SelectSampleParams{SampleQueryRequest: &req}
+		}
 
 		if tenantChunkOverrides, ok := storeOverridesByTenant[id]; ok {
 			tenantParams = tenantParams.WithStoreChunks(&logproto.ChunkRefGroup{Refs: tenantChunkOverrides})
@@ -132,8 +203,7 @@ func (q *MultiTenantQuerier) SelectSamples(ctx context.Context, params logql.Sel
 			return nil, err
 		}
 
-		iters[i] = NewTenantSampleIterator(iter, id)
-		i++
+		iters = append(iters, NewTenantSampleIterator(iter, id))
 	}
 	return iter.NewSortSampleIterator(iters), nil
 }
@@ -152,15 +222,28 @@ func (q *MultiTenantQuerier) Label(ctx context.Context, req *logproto.LabelReque
 		return q.Querier.Label(ctx, req)
 	}
 
-	responses := make([]*logproto.LabelResponse, len(tenantIDs))
-	for i, id := range tenantIDs {
+	responses := make([]*logproto.LabelResponse, 0, len(tenantIDs))
+	for _, id := range tenantIDs {
+		tenantReq := req
+		if req.Start != nil && req.End != nil {
+			start, ok := q.tenantStart(ctx, id, *req.Start, *req.End)
+			if !ok {
+				continue
+			}
+			if !start.Equal(*req.Start) {
+				r := *req
+				r.Start = &start
+				tenantReq = &r
+			}
+		}
+
 		singleContext := user.InjectOrgID(ctx, id)
-		resp, err := q.Querier.Label(singleContext, req)
+		resp, err := q.Querier.Label(singleContext, tenantReq)
 		if err != nil {
 			return nil, err
 		}
 
-		responses[i] = resp
+		responses = append(responses, resp)
 	}
 
 	// Append tenant ID label name if label names are requested.
@@ -181,10 +264,21 @@ func (q *MultiTenantQuerier) Series(ctx context.Context, req *logproto.SeriesReq
 		return q.Querier.Series(ctx, req)
 	}
 
-	responses := make([]*logproto.SeriesResponse, len(tenantIDs))
-	for i, id := range tenantIDs {
+	responses := make([]*logproto.SeriesResponse, 0, len(tenantIDs))
+	for _, id := range tenantIDs {
+		start, ok := q.tenantStart(ctx, id, req.Start, req.End)
+		if !ok {
+			continue
+		}
+		tenantReq := req
+		if !start.Equal(req.Start) {
+			r := *req
+			r.Start = start
+			tenantReq = &r
+		}
+
 		singleContext := user.InjectOrgID(ctx, id)
-		resp, err := q.Querier.Series(singleContext, req)
+		resp, err := q.Querier.Series(singleContext, tenantReq)
 		if err != nil {
 			return nil, err
 		}
@@ -196,7 +290,7 @@ func (q *MultiTenantQuerier) Series(ctx context.Context, req *logproto.SeriesReq
 			}
 		}
 
-		responses[i] = resp
+		responses = append(responses, resp)
 	}
 
 	return logproto.MergeSeriesResponses(responses)
@@ -212,15 +306,20 @@ func (q *MultiTenantQuerier) IndexStats(ctx context.Context, req *loghttp.RangeQ
 		return q.Querier.IndexStats(ctx, req)
 	}
 
-	responses := make([]*stats.Stats, len(tenantIDs))
-	for i, id := range tenantIDs {
+	responses := make([]*stats.Stats, 0, len(tenantIDs))
+	for _, id := range tenantIDs {
+		tenantReq, ok := q.tenantRangeQuery(ctx, id, req)
+		if !ok {
+			continue
+		}
+
 		singleContext := user.InjectOrgID(ctx, id)
-		resp, err := q.Querier.IndexStats(singleContext, req)
+		resp, err := q.Querier.IndexStats(singleContext, tenantReq)
 		if err != nil {
 			return nil, err
 		}
 
-		responses[i] = resp
+		responses = append(responses, resp)
 	}
 
 	merged := stats.MergeStats(responses...)
@@ -242,15 +341,23 @@ func (q *MultiTenantQuerier) IndexShards(
 		return q.Querier.IndexShards(ctx, req, targetBytesPerShard)
 	}
 
-	responses := make([]*logproto.ShardsResponse, len(tenantIDs))
-	for i, id := range tenantIDs {
+	responses := make([]*logproto.ShardsResponse, 0, len(tenantIDs))
+	for _, id := range tenantIDs {
+		tenantReq, ok := q.tenantRangeQuery(ctx, id, req)
+		if !ok {
+			continue
+		}
+
 		singleContext := user.InjectOrgID(ctx, id)
-		resp, err := q.Querier.IndexShards(singleContext, req, targetBytesPerShard)
+		resp, err := q.Querier.IndexShards(singleContext, tenantReq, targetBytesPerShard)
 		if err != nil {
 			return nil, err
 		}
 
-		responses[i] = resp
+		responses = append(responses, resp)
+	}
+	if len(responses) == 0 {
+		return &logproto.ShardsResponse{}, nil
 	}
 
 	// TODO(owen-d): better merging
@@ -272,15 +379,26 @@ func (q *MultiTenantQuerier) Volume(ctx context.Context, req *logproto.VolumeReq
 		return nil, err
 	}
 
-	responses := make([]*logproto.VolumeResponse, len(tenantIDs))
-	for i, id := range tenantIDs {
+	responses := make([]*logproto.VolumeResponse, 0, len(tenantIDs))
+	for _, id := range tenantIDs {
+		start, ok := q.tenantStart(ctx, id, req.From.Time(), req.Through.Time())
+		if !ok {
+			continue
+		}
+		tenantReq := req
+		if !start.Equal(req.From.Time()) {
+			r := *req
+			r.From = model.TimeFromUnixNano(start.UnixNano())
+			tenantReq = &r
+		}
+
 		singleContext := user.InjectOrgID(ctx, id)
-		resp, err := q.Querier.Volume(singleContext, req)
+		resp, err := q.Querier.Volume(singleContext, tenantReq)
 		if err != nil {
 			return nil, err
 		}
 
-		responses[i] = resp
+		responses = append(responses, resp)
 	}
 
 	merged := seriesvolume.Merge(responses, req.Limit)
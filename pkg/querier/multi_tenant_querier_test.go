@@ -11,17 +11,27 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/loghttp"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
+	"github.com/grafana/loki/v3/pkg/validation"
 )
 
+func newMultiTenantLimits(t *testing.T, tenantLimits map[string]*validation.Limits) *validation.Overrides {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), mockTenantLimits(tenantLimits))
+	require.NoError(t, err)
+	return limits
+}
+
 func TestMultiTenantQuerier_SelectLogs(t *testing.T) {
 	for _, tc := range []struct {
 		desc      string
@@ -77,7 +87,7 @@ func TestMultiTenantQuerier_SelectLogs(t *testing.T) {
 			querier := newQuerierMock()
 			querier.On("SelectLogs", mock.Anything, mock.Anything).Return(func() iter.EntryIterator { return mockStreamIterator(1, 2) }, nil)
 
-			multiTenantQuerier := NewMultiTenantQuerier(querier, log.NewNopLogger())
+			multiTenantQuerier := NewMultiTenantQuerier(querier, newMultiTenantLimits(t, nil), log.NewNopLogger())
 
 			ctx := user.InjectOrgID(context.Background(), tc.orgID)
 			params := logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{
@@ -154,7 +164,7 @@ func TestMultiTenantQuerier_SelectSamples(t *testing.T) {
 			querier := newQuerierMock()
 			querier.On("SelectSamples", mock.Anything, mock.Anything).Return(func() iter.SampleIterator { return newSampleIterator() }, nil)
 
-			multiTenantQuerier := NewMultiTenantQuerier(querier, log.NewNopLogger())
+			multiTenantQuerier := NewMultiTenantQuerier(querier, newMultiTenantLimits(t, nil), log.NewNopLogger())
 
 			ctx := user.InjectOrgID(context.Background(), tc.orgID)
 			params := logql.SelectSampleParams{SampleQueryRequest: &logproto.SampleQueryRequest{
@@ -175,6 +185,230 @@ func TestMultiTenantQuerier_SelectSamples(t *testing.T) {
 	}
 }
 
+func TestMultiTenantQuerier_TenantLimits(t *testing.T) {
+	tenantLimits := func(lookback, retention time.Duration) *validation.Limits {
+		limits := defaultLimitsTestConfig()
+		limits.MaxQueryLookback = model.Duration(lookback)
+		limits.RetentionPeriod = model.Duration(retention)
+		return &limits
+	}
+	limits := newMultiTenantLimits(t, map[string]*validation.Limits{
+		"1": tenantLimits(0, 0),
+		"2": tenantLimits(time.Hour, 0),
+		"3": tenantLimits(3*time.Hour, 0),
+		"4": tenantLimits(0, 2*time.Hour),
+	})
+	now := time.Now()
+	ctx := user.InjectOrgID(context.Background(), "1|2|3|4")
+
+	for _, tc := range []struct {
+		desc      string
+		start     time.Time
+		expStarts map[string]time.Time
+	}{
+		{
+			desc:  "within the limits of every tenant",
+			start: now.Add(-30 * time.Minute),
+			expStarts: map[string]time.Time{
+				"1": now.Add(-30 * time.Minute),
+				"2": now.Add(-30 * time.Minute),
+				"3": now.Add(-30 * time.Minute),
+				"4": now.Add(-30 * time.Minute),
+			},
+		},
+		{
+			desc:  "start clamped to the max query lookback of the tenant",
+			start: now.Add(-2 * time.Hour),
+			expStarts: map[string]time.Time{
+				"1": now.Add(-2 * time.Hour),
+				"2": now.Add(-time.Hour),
+				"3": now.Add(-2 * time.Hour),
+				"4": now.Add(-2 * time.Hour),
+			},
+		},
+		{
+			// the retention of the streams of a tenant may be longer than its retention period.
+			desc:  "start not clamped to the retention of the tenant",
+			start: now.Add(-4 * time.Hour),
+			expStarts: map[string]time.Time{
+				"1": now.Add(-4 * time.Hour),
+				"2": now.Add(-time.Hour),
+				"3": now.Add(-3 * time.Hour),
+				"4": now.Add(-4 * time.Hour),
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			defer func(fn func() time.Time) { nowFunc = fn }(nowFunc)
+			nowFunc = func() time.Time { return now }
+
+			logStarts := map[string]time.Time{}
+			sampleStarts := map[string]time.Time{}
+			querier := newQuerierMock()
+			querier.On("SelectLogs", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				id, _ := user.ExtractOrgID(args.Get(0).(context.Context))
+				logStarts[id] = args.Get(1).(logql.SelectLogParams).Start
+			}).Return(func() iter.EntryIterator { return mockStreamIterator(1, 2) }, nil)
+			querier.On("SelectSamples", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				id, _ := user.ExtractOrgID(args.Get(0).(context.Context))
+				sampleStarts[id] = args.Get(1).(logql.SelectSampleParams).Start
+			}).Return(func() iter.SampleIterator { return newSampleIterator() }, nil)
+
+			multiTenantQuerier := NewMultiTenantQuerier(querier, limits, log.NewNopLogger())
+
+			_, err := multiTenantQuerier.SelectLogs(ctx, logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{
+				Selector:  `{type="test"}`,
+				Direction: logproto.BACKWARD,
+				Start:     tc.start,
+				End:       now,
+				Plan:      &plan.QueryPlan{AST: syntax.MustParseExpr(`{type="test"}`)},
+			}})
+			require.NoError(t, err)
+			require.Equal(t, tc.expStarts, logStarts)
+
+			_, err = multiTenantQuerier.SelectSamples(ctx, logql.SelectSampleParams{SampleQueryRequest: &logproto.SampleQueryRequest{
+				Selector: `count_over_time({type="test"}[1m])`,
+				Start:    tc.start,
+				End:      now,
+				Plan:     &plan.QueryPlan{AST: syntax.MustParseExpr(`count_over_time({type="test"}[1m])`)},
+			}})
+			require.NoError(t, err)
+			require.Equal(t, tc.expStarts, sampleStarts)
+		})
+	}
+
+	t.Run("tenants outside of their limits are skipped", func(t *testing.T) {
+		querier := newQuerierMock()
+		querier.On("SelectLogs", mock.Anything, mock.Anything).Return(func() iter.EntryIterator { return mockStreamIterator(1, 2) }, nil)
+		multiTenantQuerier := NewMultiTenantQuerier(querier, limits, log.NewNopLogger())
+
+		it, err := multiTenantQuerier.SelectLogs(ctx, logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{
+			Selector:  `{type="test"}`,
+			Direction: logproto.BACKWARD,
+			Start:     time.Now().Add(-6 * time.Hour),
+			End:       time.Now().Add(-4 * time.Hour),
+			Plan:      &plan.QueryPlan{AST: syntax.MustParseExpr(`{type="test"}`)},
+		}})
+		require.NoError(t, err)
+
+		tenants := map[string]struct{}{}
+		for it.Next() {
+			lbls, err := syntax.ParseLabels(it.Labels())
+			require.NoError(t, err)
+			tenants[lbls.Get(defaultTenantLabel)] = struct{}{}
+		}
+		require.Equal(t, map[string]struct{}{"1": {}, "4": {}}, tenants)
+		querier.AssertNumberOfCalls(t, "SelectLogs", 2)
+	})
+}
+
+func TestMultiTenantQuerier_MetadataTenantLimits(t *testing.T) {
+	tenantLimits := func(lookback time.Duration) *validation.Limits {
+		limits := defaultLimitsTestConfig()
+		limits.MaxQueryLookback = model.Duration(lookback)
+		return &limits
+	}
+	limits := newMultiTenantLimits(t, map[string]*validation.Limits{
+		"1": tenantLimits(0),
+		"2": tenantLimits(time.Hour),
+		"3": tenantLimits(2 * time.Hour),
+	})
+	now := time.Now().Truncate(time.Millisecond)
+	start := now.Add(-90 * time.Minute)
+	ctx := user.InjectOrgID(context.Background(), "1|2|3")
+	expStarts := map[string]time.Time{
+		"1": start,
+		"2": now.Add(-time.Hour),
+		"3": start,
+	}
+
+	defer func(fn func() time.Time) { nowFunc = fn }(nowFunc)
+	nowFunc = func() time.Time { return now }
+
+	starts := map[string]map[string]time.Time{}
+	record := func(method string) func(args mock.Arguments) {
+		starts[method] = map[string]time.Time{}
+		return func(args mock.Arguments) {
+			id, _ := user.ExtractOrgID(args.Get(0).(context.Context))
+			switch req := args.Get(1).(type) {
+			case *logproto.LabelRequest:
+				starts[method][id] = *req.Start
+			case *logproto.SeriesRequest:
+				starts[method][id] = req.Start
+			case *loghttp.RangeQuery:
+				starts[method][id] = req.Start
+			case *logproto.VolumeRequest:
+				starts[method][id] = req.From.Time()
+			}
+		}
+	}
+	querier := newQuerierMock()
+	querier.On("Label", mock.Anything, mock.Anything).Run(record("Label")).Return(mockLabelResponse([]string{"test"}), nil)
+	querier.On("Series", mock.Anything, mock.Anything).Run(record("Series")).Return(func() *logproto.SeriesResponse { return mockSeriesResponse() }, nil)
+	querier.On("IndexStats", mock.Anything, mock.Anything).Run(record("IndexStats")).Return(&stats.Stats{}, nil)
+	querier.On("IndexShards", mock.Anything, mock.Anything, mock.Anything).Run(record("IndexShards")).Return(&logproto.ShardsResponse{}, nil)
+	querier.On("Volume", mock.Anything, mock.Anything).Run(record("Volume")).Return(mockLabelValueResponse(), nil)
+
+	multiTenantQuerier := NewMultiTenantQuerier(querier, limits, log.NewNopLogger())
+
+	_, err := multiTenantQuerier.Label(ctx, &logproto.LabelRequest{Name: "test", Values: true, Start: &start, End: &now})
+	require.NoError(t, err)
+	_, err = multiTenantQuerier.Series(ctx, &logproto.SeriesRequest{Start: start, End: now})
+	require.NoError(t, err)
+	_, err = multiTenantQuerier.IndexStats(ctx, &loghttp.RangeQuery{Start: start, End: now})
+	require.NoError(t, err)
+	_, err = multiTenantQuerier.IndexShards(ctx, &loghttp.RangeQuery{Start: start, End: now}, 0)
+	require.NoError(t, err)
+	_, err = multiTenantQuerier.Volume(ctx, &logproto.VolumeRequest{From: model.TimeFromUnixNano(start.UnixNano()), Through: model.TimeFromUnixNano(now.UnixNano()), Matchers: `{foo="bar"}`})
+	require.NoError(t, err)
+
+	for _, method := range []string{"Label", "Series", "IndexStats", "IndexShards", "Volume"} {
+		require.Equal(t, expStarts, starts[method], method)
+	}
+
+	t.Run("tenants outside of their limits are skipped", func(t *testing.T) {
+		querier := newQuerierMock()
+		querier.On("Series", mock.Anything, mock.Anything).Return(func() *logproto.SeriesResponse { return mockSeriesResponse() }, nil)
+		multiTenantQuerier := NewMultiTenantQuerier(querier, limits, log.NewNopLogger())
+
+		resp, err := multiTenantQuerier.Series(ctx, &logproto.SeriesRequest{Start: now.Add(-4 * time.Hour), End: now.Add(-90 * time.Minute)})
+		require.NoError(t, err)
+
+		tenants := map[string]struct{}{}
+		for _, s := range resp.GetSeries() {
+			tenants[s.Get(defaultTenantLabel)] = struct{}{}
+		}
+		require.Equal(t, map[string]struct{}{"1": {}, "3": {}}, tenants)
+		querier.AssertNumberOfCalls(t, "Series", 2)
+	})
+}
+
+func TestMultiTenantQuerier_SelectSamplesPlan(t *testing.T) {
+	var plans []string
+	querier := newQuerierMock()
+	querier.On("SelectSamples", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		params := args.Get(1).(logql.SelectSampleParams)
+		require.NotContains(t, params.Selector, defaultTenantLabel)
+		plans = append(plans, params.Plan.AST.String())
+	}).Return(func() iter.SampleIterator { return newSampleIterator() }, nil)
+	multiTenantQuerier := NewMultiTenantQuerier(querier, newMultiTenantLimits(t, nil), log.NewNopLogger())
+
+	selector := `count_over_time({foo="bar", __tenant_id__=~"1|2"}[1m])`
+	params := logql.SelectSampleParams{SampleQueryRequest: &logproto.SampleQueryRequest{
+		Selector: selector,
+		Plan:     &plan.QueryPlan{AST: syntax.MustParseExpr(selector)},
+	}}
+	_, err := multiTenantQuerier.SelectSamples(user.InjectOrgID(context.Background(), "1|2|3"), params)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+	for _, p := range plans {
+		require.NotContains(t, p, defaultTenantLabel)
+	}
+	// the request of the caller is left untouched.
+	require.Equal(t, selector, params.Selector)
+	require.Contains(t, params.Plan.AST.String(), defaultTenantLabel)
+}
+
 func TestMultiTenantQuerier_TenantFilter(t *testing.T) {
 	for _, tc := range []struct {
 		selector string
@@ -335,7 +569,7 @@ func TestMultiTenantQuerier_Label(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			querier := newQuerierMock()
 			querier.On("Label", mock.Anything, mock.Anything).Return(mockLabelResponse([]string{"test"}), nil)
-			multiTenantQuerier := NewMultiTenantQuerier(querier, log.NewNopLogger())
+			multiTenantQuerier := NewMultiTenantQuerier(querier, newMultiTenantLimits(t, nil), log.NewNopLogger())
 			ctx := user.InjectOrgID(context.Background(), tc.orgID)
 
 			resp, err := multiTenantQuerier.Label(ctx, mockLabelRequest(tc.name))
@@ -397,7 +631,7 @@ func TestMultiTenantQuerierSeries(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			querier := newQuerierMock()
 			querier.On("Series", mock.Anything, mock.Anything).Return(func() *logproto.SeriesResponse { return mockSeriesResponse() }, nil)
-			multiTenantQuerier := NewMultiTenantQuerier(querier, log.NewNopLogger())
+			multiTenantQuerier := NewMultiTenantQuerier(querier, newMultiTenantLimits(t, nil), log.NewNopLogger())
 			ctx := user.InjectOrgID(context.Background(), tc.orgID)
 
 			resp, err := multiTenantQuerier.Series(ctx, mockSeriesRequest())
@@ -432,7 +666,7 @@ func TestVolume(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			querier := newQuerierMock()
 			querier.On("Volume", mock.Anything, mock.Anything).Return(mockLabelValueResponse(), nil)
-			multiTenantQuerier := NewMultiTenantQuerier(querier, log.NewNopLogger())
+			multiTenantQuerier := NewMultiTenantQuerier(querier, newMultiTenantLimits(t, nil), log.NewNopLogger())
 			ctx := user.InjectOrgID(context.Background(), tc.orgID)
 
 			resp, err := multiTenantQuerier.Volume(ctx, mockLabelValueRequest())
@@ -701,8 +701,9 @@ func (q *querierMock) Tail(_ context.Context, _ *logproto.TailRequest, _ bool) (
 	return nil, errors.New("querierMock.Tail() has not been mocked")
 }
 
-func (q *querierMock) IndexStats(_ context.Context, _ *loghttp.RangeQuery) (*stats.Stats, error) {
-	return nil, nil
+func (q *querierMock) IndexStats(ctx context.Context, req *loghttp.RangeQuery) (*stats.Stats, error) {
+	args := q.Called(ctx, req)
+	return args.Get(0).(*stats.Stats), args.Error(1)
 }
 
 func (q *querierMock) GetShards(_ context.Context, _ string, _, _ model.Time, _ uint64, _ chunk.Predicate) ([]logproto.Shard, error) {
@@ -713,8 +714,9 @@ func (q *querierMock) HasForSeries(_, _ model.Time) (sharding.ForSeries, bool) {
 	return nil, false
 }
 
-func (q *querierMock) IndexShards(_ context.Context, _ *loghttp.RangeQuery, _ uint64) (*logproto.ShardsResponse, error) {
-	return nil, errors.New("unimplemented")
+func (q *querierMock) IndexShards(ctx context.Context, req *loghttp.RangeQuery, targetBytesPerShard uint64) (*logproto.ShardsResponse, error) {
+	args := q.Called(ctx, req, targetBytesPerShard)
+	return args.Get(0).(*logproto.ShardsResponse), args.Error(1)
 }
 
 func (q *querierMock) Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
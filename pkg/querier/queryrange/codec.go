@@ -15,15 +15,18 @@ import (
 	"time"
 
 	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/timestamp"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"golang.org/x/exp/maps"
 
 	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
@@ -49,7 +52,18 @@ const (
 
 var DefaultCodec = &Codec{}
 
-type Codec struct{}
+type Codec struct {
+	// otlpLimits are the OTLP configs of the tenants, the log query responses requested as OTLP logs are mapped
+	// with the default OTLP config if they are not set.
+	otlpLimits push.Limits
+}
+
+// WithOTLPLimits returns a copy of the codec mapping the log query responses requested as OTLP logs with the OTLP
+// config of their tenant.
+func (c Codec) WithOTLPLimits(limits push.Limits) *Codec {
+	c.otlpLimits = limits
+	return &c
+}
 
 type RequestProtobufCodec struct {
 	Codec
@@ -342,6 +356,14 @@ func (Codec) DecodeRequest(_ context.Context, r *http.Request, _ []string) (quer
 		if err != nil {
 			return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
 		}
+
+		// analyzed queries must run to collect their statistics, sampled results are not cached with the whole ones.
+		if httpreq.IsExplainAnalyze(r.Context()) || httpreq.IsPipelineStats(r.Context()) || httpreq.IsSampled(r.Context()) {
+			req.CachingOptions = queryrangebase.CachingOptions{
+				Disabled: true,
+			}
+		}
+
 		return req, nil
 	case InstantQueryOp:
 		req, err := parseInstantQuery(r)
@@ -350,7 +372,7 @@ func (Codec) DecodeRequest(_ context.Context, r *http.Request, _ []string) (quer
 		}
 
 		req.CachingOptions = queryrangebase.CachingOptions{
-			Disabled: disableCacheReq,
+			Disabled: disableCacheReq || httpreq.IsExplainAnalyze(r.Context()) || httpreq.IsPipelineStats(r.Context()) || httpreq.IsSampled(r.Context()),
 		}
 
 		return req, nil
@@ -507,6 +529,21 @@ func (Codec) DecodeHTTPGrpcRequest(ctx context.Context, r *httpgrpc.HTTPRequest)
 		httpreq.InjectHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader, disableWrappers)
 	}
 
+	// Add explain mode
+	if explain := httpReq.Header.Get(httpreq.LokiExplainHeader); explain != "" {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiExplainHeader, explain)
+	}
+
+	// Add pipeline stats
+	if pipelineStats := httpReq.Header.Get(httpreq.LokiPipelineStatsHeader); pipelineStats != "" {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiPipelineStatsHeader, pipelineStats)
+	}
+
+	// Add sample ratio
+	if sample := httpReq.Header.Get(httpreq.LokiSampleHeader); sample != "" {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiSampleHeader, sample)
+	}
+
 	// Add query metrics
 	if queueTimeHeader := httpReq.Header.Get(string(httpreq.QueryQueueTimeHTTPHeader)); queueTimeHeader != "" {
 		queueTime, err := time.ParseDuration(queueTimeHeader)
@@ -716,6 +753,21 @@ func (c Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*ht
 		header.Set(httpreq.LokiDisablePipelineWrappersHeader, disableWrappers)
 	}
 
+	// Add explain mode
+	if explain := httpreq.ExtractHeader(ctx, httpreq.LokiExplainHeader); explain != "" {
+		header.Set(httpreq.LokiExplainHeader, explain)
+	}
+
+	// Add pipeline stats
+	if pipelineStats := httpreq.ExtractHeader(ctx, httpreq.LokiPipelineStatsHeader); pipelineStats != "" {
+		header.Set(httpreq.LokiPipelineStatsHeader, pipelineStats)
+	}
+
+	// Add sample ratio
+	if sample := httpreq.ExtractHeader(ctx, httpreq.LokiSampleHeader); sample != "" {
+		header.Set(httpreq.LokiSampleHeader, sample)
+	}
+
 	// Add limits
 	if limits := querylimits.ExtractQueryLimitsContext(ctx); limits != nil {
 		err := querylimits.InjectQueryLimitsHeader(&header, limits)
@@ -1269,15 +1321,20 @@ func decodeResponseProtobuf(r *http.Response, req queryrangebase.Request) (query
 			return concrete.TopkSketches.WithHeaders(headers), nil
 		case *QueryResponse_QuantileSketches:
 			return concrete.QuantileSketches.WithHeaders(headers), nil
+		case *QueryResponse_CountDistinctSketches:
+			return concrete.CountDistinctSketches.WithHeaders(headers), nil
 		default:
 			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "unsupported response type, got (%T)", resp.Response)
 		}
 	}
 }
 
-func (Codec) EncodeResponse(ctx context.Context, req *http.Request, res queryrangebase.Response) (*http.Response, error) {
-	if req.Header.Get("Accept") == ProtobufType {
+func (c Codec) EncodeResponse(ctx context.Context, req *http.Request, res queryrangebase.Response) (*http.Response, error) {
+	switch req.Header.Get("Accept") {
+	case ProtobufType:
 		return encodeResponseProtobuf(ctx, res)
+	case OTLPType:
+		return encodeResponseOTLP(ctx, res, c.otlpConfig(ctx))
 	}
 
 	// Default to JSON.
@@ -1291,7 +1348,12 @@ func encodeResponseJSON(ctx context.Context, version loghttp.Version, res queryr
 	defer sp.Finish()
 	var buf bytes.Buffer
 
-	err := encodeResponseJSONTo(version, res, &buf, encodeFlags)
+	var err error
+	if response, ok := res.(*LokiPromResponse); ok {
+		err = response.encodeTo(&buf, sampleApproximation(ctx, response))
+	} else {
+		err = encodeResponseJSONTo(version, res, &buf, encodeFlags)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1311,7 +1373,7 @@ func encodeResponseJSON(ctx context.Context, version loghttp.Version, res queryr
 func encodeResponseJSONTo(version loghttp.Version, res queryrangebase.Response, w io.Writer, encodeFlags httpreq.EncodingFlags) error {
 	switch response := res.(type) {
 	case *LokiPromResponse:
-		return response.encodeTo(w)
+		return response.encodeTo(w, nil)
 	case *LokiResponse:
 		streams := make([]logproto.Stream, len(response.Data.Result))
 
@@ -1407,6 +1469,44 @@ func encodeResponseProtobuf(ctx context.Context, res queryrangebase.Response) (*
 	return &resp, nil
 }
 
+// otlpConfig returns the OTLP config of the tenant of the request, or the default OTLP config for multi-tenant
+// requests and codecs without OTLP limits.
+func (c Codec) otlpConfig(ctx context.Context) push.OTLPConfig {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if c.otlpLimits == nil || err != nil || len(tenantIDs) != 1 {
+		return push.EmptyLimits{}.OTLPConfig("")
+	}
+	return c.otlpLimits.OTLPConfig(tenantIDs[0])
+}
+
+// encodeResponseOTLP encodes the streams of a log query response as an OTLP ExportLogsServiceRequest.
+func encodeResponseOTLP(ctx context.Context, res queryrangebase.Response, otlpConfig push.OTLPConfig) (*http.Response, error) {
+	sp, _ := opentracing.StartSpanFromContext(ctx, "codec.EncodeResponse")
+	defer sp.Finish()
+
+	response, ok := res.(*LokiResponse)
+	if !ok {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s responses are only supported for log queries", OTLPType)
+	}
+
+	logs := push.StreamsToOTLPLogs(response.Data.Result, otlpConfig)
+	buf, err := plogotlp.NewExportRequestFromLogs(logs).MarshalProto()
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal OTLP logs: %w", err)
+	}
+
+	sp.LogFields(otlog.Int("bytes", len(buf)))
+
+	resp := http.Response{
+		Header: http.Header{
+			"Content-Type": []string{OTLPType},
+		},
+		Body:       io.NopCloser(bytes.NewBuffer(buf)),
+		StatusCode: http.StatusOK,
+	}
+	return &resp, nil
+}
+
 // NOTE: When we would start caching response from non-metric queries we would have to consider cache gen headers as well in
 // MergeResponse implementation for Loki codecs same as it is done in Cortex at https://github.com/cortexproject/cortex/blob/21bad57b346c730d684d6d0205efef133422ab28/pkg/querier/queryrange/query_range.go#L170
 func (Codec) MergeResponse(responses ...queryrangebase.Response) (queryrangebase.Response, error) {
@@ -1703,8 +1803,9 @@ func toProtoMatrix(m loghttp.Matrix) []queryrangebase.SampleStream {
 			})
 		}
 		res = append(res, queryrangebase.SampleStream{
-			Labels:  logproto.FromMetricsToLabelAdapters(stream.Metric),
-			Samples: samples,
+			Labels:     logproto.FromMetricsToLabelAdapters(stream.Metric),
+			Samples:    samples,
+			Histograms: queryrangebase.FromModelHistograms(stream.Histograms),
 		})
 	}
 	return res
@@ -1717,6 +1818,16 @@ func toProtoVector(v loghttp.Vector) []queryrangebase.SampleStream {
 		return res
 	}
 	for _, s := range v {
+		if s.Histogram != nil {
+			res = append(res, queryrangebase.SampleStream{
+				Histograms: queryrangebase.FromModelHistograms([]model.SampleHistogramPair{{
+					Timestamp: s.Timestamp,
+					Histogram: s.Histogram,
+				}}),
+				Labels: logproto.FromMetricsToLabelAdapters(s.Metric),
+			})
+			continue
+		}
 		res = append(res, queryrangebase.SampleStream{
 			Samples: []logproto.LegacySample{{
 				Value:       float64(s.Value),
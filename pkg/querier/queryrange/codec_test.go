@@ -16,6 +16,7 @@ import (
 
 	"github.com/axiomhq/hyperloglog"
 	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/prometheus/common/model"
@@ -24,8 +25,10 @@ import (
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 
 	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
@@ -1252,6 +1255,66 @@ func Test_codec_EncodeResponse(t *testing.T) {
 	}
 }
 
+type fakeOTLPLimits struct {
+	push.EmptyLimits
+	otlpConfig push.OTLPConfig
+}
+
+func (l fakeOTLPLimits) OTLPConfig(string) push.OTLPConfig {
+	return l.otlpConfig
+}
+
+func Test_codec_EncodeResponseOTLP(t *testing.T) {
+	u := &url.URL{Path: "/loki/api/v1/query_range"}
+	req := &http.Request{
+		Method:     "GET",
+		RequestURI: u.String(),
+		URL:        u,
+		Header:     http.Header{"Accept": []string{OTLPType}},
+	}
+	codec := DefaultCodec.WithOTLPLimits(fakeOTLPLimits{otlpConfig: push.OTLPConfig{
+		ResourceAttributes: push.ResourceAttributesConfig{
+			AttributesConfig: []push.AttributesConfig{{Action: push.IndexLabel, Attributes: []string{"service.name"}}},
+		},
+	}})
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	res, err := codec.EncodeResponse(ctx, req, &LokiResponse{
+		Status: loghttp.QueryStatusSuccess,
+		Data: LokiData{
+			ResultType: loghttp.ResultTypeStream,
+			Result: []logproto.Stream{
+				{
+					Labels:  `{service_name="api"}`,
+					Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "line 1"}, {Timestamp: time.Unix(0, 2), Line: "line 2"}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, OTLPType, res.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	exportReq := plogotlp.NewExportRequest()
+	require.NoError(t, exportReq.UnmarshalProto(body))
+	logs := exportReq.Logs()
+	require.Equal(t, 2, logs.LogRecordCount())
+	require.Equal(t, map[string]any{"service.name": "api"}, logs.ResourceLogs().At(0).Resource().Attributes().AsRaw())
+	require.Equal(t, "line 2", logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1).Body().Str())
+
+	// metric queries can't be encoded as OTLP logs.
+	_, err = codec.EncodeResponse(ctx, req, &LokiPromResponse{
+		Response: &queryrangebase.PrometheusResponse{
+			Status: loghttp.QueryStatusSuccess,
+			Data:   queryrangebase.PrometheusData{ResultType: loghttp.ResultTypeMatrix, Result: sampleStreams},
+		},
+	})
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusBadRequest), resp.Code)
+}
+
 func Test_codec_MergeResponse(t *testing.T) {
 	tests := []struct {
 		name         string
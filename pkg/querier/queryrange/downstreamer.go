@@ -113,6 +113,17 @@ func withoutOffset(query logql.DownstreamQuery) (string, time.Time, time.Time) {
 		newStart = query.Params.Start()
 		newEnd   = query.Params.End()
 	)
+	// the steps of a subquery are aligned to multiples of its step,
+	// moving the query would change the evaluated steps.
+	var subquery bool
+	expr.Walk(func(e syntax.Expr) {
+		if _, ok := e.(*syntax.SubqueryExpr); ok {
+			subquery = true
+		}
+	})
+	if subquery {
+		return expr.String(), newStart, newEnd
+	}
 	expr.Walk(func(e syntax.Expr) {
 		switch rng := e.(type) {
 		case *syntax.RangeAggregationExpr:
@@ -220,6 +231,12 @@ func sampleStreamToMatrix(streams []queryrangebase.SampleStream) parser.Value {
 				F: sample.Value,
 			})
 		}
+		for _, h := range stream.Histograms {
+			x.Histograms = append(x.Histograms, promql.HPoint{
+				T: h.TimestampMs,
+				H: h.Histogram.ToFloatHistogram(),
+			})
+		}
 
 		xs = append(xs, x)
 	}
@@ -235,8 +252,13 @@ func sampleStreamToVector(streams []queryrangebase.SampleStream) parser.Value {
 			x.Metric = append(x.Metric, labels.Label(l))
 		}
 
-		x.T = stream.Samples[0].TimestampMs
-		x.F = stream.Samples[0].Value
+		if len(stream.Histograms) > 0 {
+			x.T = stream.Histograms[0].TimestampMs
+			x.H = stream.Histograms[0].Histogram.ToFloatHistogram()
+		} else {
+			x.T = stream.Samples[0].TimestampMs
+			x.F = stream.Samples[0].Value
+		}
 
 		xs = append(xs, x)
 	}
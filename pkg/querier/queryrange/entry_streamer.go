@@ -0,0 +1,111 @@
+package queryrange
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+	"github.com/grafana/loki/v3/pkg/util/marshal"
+)
+
+const entryStreamerKey ctxKeyType = "entry_streamer"
+
+// entryStreamer writes the entries of a log query as NDJSON while the responses of its intervals are merged in
+// order, instead of merging all of them in a single response before writing it.
+// Each line is a stream of consecutive entries having the same labels, the last one has the statistics of the query.
+type entryStreamer struct {
+	w           io.Writer
+	direction   logproto.Direction
+	remaining   uint32
+	encodeFlags httpreq.EncodingFlags
+
+	// started is closed once the first line is written.
+	started   chan struct{}
+	startOnce sync.Once
+}
+
+func newEntryStreamer(w io.Writer, req *LokiRequest, encodeFlags httpreq.EncodingFlags) *entryStreamer {
+	return &entryStreamer{
+		w:           w,
+		direction:   req.Direction,
+		remaining:   req.Limit,
+		encodeFlags: encodeFlags,
+		started:     make(chan struct{}),
+	}
+}
+
+// withEntryStreamer returns a context whose log queries are streamed by the streamer, see SplitByIntervalMiddleware.
+func withEntryStreamer(ctx context.Context, s *entryStreamer) context.Context {
+	return context.WithValue(ctx, entryStreamerKey, s)
+}
+
+func entryStreamerFromContext(ctx context.Context) *entryStreamer {
+	s, _ := ctx.Value(entryStreamerKey).(*entryStreamer)
+	return s
+}
+
+// do executes the request with the streamer in its context and writes the entries left in the response,
+// then its statistics.
+func (s *entryStreamer) do(ctx context.Context, next queryrangebase.Handler, req *LokiRequest) error {
+	resp, err := next.Do(withEntryStreamer(ctx, s), req)
+	if err != nil {
+		return err
+	}
+	lokiResp, ok := resp.(*LokiResponse)
+	if !ok {
+		return fmt.Errorf("unexpected response type %T for a log query", resp)
+	}
+	if err := s.stream(lokiResp); err != nil {
+		return err
+	}
+	s.start()
+	return marshal.WriteQueryStatsNDJSON(lokiResp.Warnings, lokiResp.Statistics, s.w, s.encodeFlags)
+}
+
+// stream writes the entries of the response in the order of the query until the limit of the query is reached.
+// Responses must be streamed in the order of their intervals.
+func (s *entryStreamer) stream(resp *LokiResponse) error {
+	it := iter.NewStreamsIterator(resp.Data.Result, s.direction)
+	defer it.Close()
+
+	var stream logproto.Stream
+	for s.remaining > 0 && it.Next() {
+		if labels := it.Labels(); labels != stream.Labels {
+			if err := s.write(stream); err != nil {
+				return err
+			}
+			stream.Labels = labels
+			stream.Entries = stream.Entries[:0]
+		}
+		stream.Entries = append(stream.Entries, it.At())
+		s.remaining--
+	}
+	if err := s.write(stream); err != nil {
+		return err
+	}
+	return it.Err()
+}
+
+func (s *entryStreamer) write(stream logproto.Stream) error {
+	if len(stream.Entries) == 0 {
+		return nil
+	}
+	s.start()
+	return marshal.WriteStreamNDJSON(stream, s.w, s.encodeFlags)
+}
+
+func (s *entryStreamer) start() {
+	s.startOnce.Do(func() { close(s.started) })
+}
+
+// withoutEntries returns a copy of the response whose entries were streamed.
+func withoutEntries(resp *LokiResponse) *LokiResponse {
+	streamed := *resp
+	streamed.Data.Result = nil
+	return &streamed
+}
@@ -159,9 +159,10 @@ func (l limitsMiddleware) Do(ctx context.Context, r queryrangebase.Request) (que
 		return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
 	}
 
-	// Clamp the time range based on the max query lookback.
+	// Clamp the time range based on the max query lookback. The queriers clamp the time range of each tenant of a
+	// multi-tenant query to its own max query lookback, so the query is clamped to the largest one.
 	lookbackCapture := func(id string) time.Duration { return l.MaxQueryLookback(ctx, id) }
-	if maxQueryLookback := validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, lookbackCapture); maxQueryLookback > 0 {
+	if maxQueryLookback := validation.MaxDurationOrZeroPerTenant(tenantIDs, lookbackCapture); maxQueryLookback > 0 {
 		minStartTime := time.Now().Add(-maxQueryLookback)
 
 		if r.GetEnd().Before(minStartTime) {
@@ -187,10 +188,19 @@ func (l limitsMiddleware) Do(ctx context.Context, r queryrangebase.Request) (que
 		}
 	}
 
-	// Enforce the max query length.
-	lengthCapture := func(id string) time.Duration { return l.MaxQueryLength(ctx, id) }
-	if maxQueryLength := validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, lengthCapture); maxQueryLength > 0 {
-		queryLen := timestamp.Time(r.GetEnd().UnixMilli()).Sub(timestamp.Time(r.GetStart().UnixMilli()))
+	// Enforce the max query length of each tenant, within its own max query lookback.
+	for _, id := range tenantIDs {
+		maxQueryLength := l.MaxQueryLength(ctx, id)
+		if maxQueryLength <= 0 {
+			continue
+		}
+		start := r.GetStart()
+		if maxQueryLookback := l.MaxQueryLookback(ctx, id); maxQueryLookback > 0 {
+			if minStartTime := time.Now().Add(-maxQueryLookback); start.Before(minStartTime) {
+				start = minStartTime
+			}
+		}
+		queryLen := timestamp.Time(r.GetEnd().UnixMilli()).Sub(timestamp.Time(start.UnixMilli()))
 		if queryLen > maxQueryLength {
 			return nil, httpgrpc.Errorf(http.StatusBadRequest, validation.ErrQueryTooLong, queryLen, model.Duration(maxQueryLength))
 		}
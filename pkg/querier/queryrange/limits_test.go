@@ -399,6 +399,64 @@ func Test_MaxQueryLookBack_Types(t *testing.T) {
 	}
 }
 
+type tenantTimeRangeLimits struct {
+	fakeLimits
+	maxQueryLookback map[string]time.Duration
+	maxQueryLength   map[string]time.Duration
+}
+
+func (l tenantTimeRangeLimits) MaxQueryLookback(_ context.Context, id string) time.Duration {
+	return l.maxQueryLookback[id]
+}
+
+func (l tenantTimeRangeLimits) MaxQueryLength(_ context.Context, id string) time.Duration {
+	return l.maxQueryLength[id]
+}
+
+func Test_MultiTenantTimeRangeLimits(t *testing.T) {
+	m := NewLimitsMiddleware(tenantTimeRangeLimits{
+		maxQueryLookback: map[string]time.Duration{"1": time.Hour, "2": 6 * time.Hour},
+		maxQueryLength:   map[string]time.Duration{"1": 2 * time.Hour, "2": 4 * time.Hour},
+	})
+
+	var got base.Request
+	h := m.Wrap(base.HandlerFunc(func(_ context.Context, req base.Request) (base.Response, error) {
+		got = req
+		return &LokiResponse{}, nil
+	}))
+
+	now := time.Now()
+	request := func(start time.Time) *LokiRequest {
+		return &LokiRequest{
+			Query:   `{app="foo"}`,
+			StartTs: start,
+			EndTs:   now,
+			Plan:    &plan.QueryPlan{AST: syntax.MustParseExpr(`{app="foo"}`)},
+		}
+	}
+	ctx := user.InjectOrgID(context.Background(), "1|2")
+
+	// the query is clamped to the largest max query lookback of its tenants.
+	_, err := h.Do(ctx, request(now.Add(-3*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-3*time.Hour), got.GetStart())
+
+	_, err = h.Do(ctx, request(now.Add(-12*time.Hour)))
+	require.Error(t, err)
+
+	// the max query length of each tenant applies within its own max query lookback.
+	got = nil
+	_, err = h.Do(ctx, request(now.Add(-5*time.Hour)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the query time range exceeds the limit (query length: 5h0m0s, limit: 4h)")
+	require.Nil(t, got)
+
+	// an unlimited max query lookback of one tenant doesn't clamp the query.
+	_, err = h.Do(user.InjectOrgID(context.Background(), "1|3"), request(now.Add(-2*time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-2*time.Hour), got.GetStart())
+}
+
 func Test_GenerateCacheKey_NoDivideZero(t *testing.T) {
 	l := cacheKeyLimits{WithSplitByLimits(nil, 0), nil, nil}
 	start := time.Now()
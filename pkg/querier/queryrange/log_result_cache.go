@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/tenant"
 	"github.com/opentracing/opentracing-go"
@@ -22,6 +24,8 @@ import (
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/cache/resultscache"
+	"github.com/grafana/loki/v3/pkg/util"
 	"github.com/grafana/loki/v3/pkg/util/constants"
 	"github.com/grafana/loki/v3/pkg/util/httpreq"
 	"github.com/grafana/loki/v3/pkg/util/validation"
@@ -48,34 +52,38 @@ func NewLogResultCacheMetrics(registerer prometheus.Registerer) *LogResultCacheM
 }
 
 // NewLogResultCache creates a new log result cache middleware.
-// Currently it only caches empty filter queries, this is because those are usually easily and freely cacheable.
-// Log hits are difficult to handle because of the limit query parameter and the size of the response.
-// In the future it could be extended to cache non-empty query results.
+// By default it only caches empty filter queries, this is because those are usually easily and freely cacheable.
+// If cacheEntries is true, it also caches the entries of the results older than the ingester query window of iqo,
+// see handleEntries.
 // see https://docs.google.com/document/d/1_mACOpxdWZ5K0cIedaja5gzMbv-m0lUVazqZd2O4mEU/edit
 func NewLogResultCache(logger log.Logger, limits Limits, cache cache.Cache, shouldCache queryrangebase.ShouldCacheFn,
-	transformer UserIDTransformer, metrics *LogResultCacheMetrics) queryrangebase.Middleware {
+	transformer UserIDTransformer, iqo util.IngesterQueryOptions, cacheEntries bool, metrics *LogResultCacheMetrics) queryrangebase.Middleware {
 	if metrics == nil {
 		metrics = NewLogResultCacheMetrics(nil)
 	}
 	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
 		return &logResultCache{
-			next:        next,
-			limits:      limits,
-			cache:       cache,
-			logger:      logger,
-			shouldCache: shouldCache,
-			transformer: transformer,
-			metrics:     metrics,
+			next:         next,
+			limits:       limits,
+			cache:        cache,
+			logger:       logger,
+			shouldCache:  shouldCache,
+			transformer:  transformer,
+			iqo:          iqo,
+			cacheEntries: cacheEntries,
+			metrics:      metrics,
 		}
 	})
 }
 
 type logResultCache struct {
-	next        queryrangebase.Handler
-	limits      Limits
-	cache       cache.Cache
-	shouldCache queryrangebase.ShouldCacheFn
-	transformer UserIDTransformer
+	next         queryrangebase.Handler
+	limits       Limits
+	cache        cache.Cache
+	shouldCache  queryrangebase.ShouldCacheFn
+	transformer  UserIDTransformer
+	iqo          util.IngesterQueryOptions
+	cacheEntries bool
 
 	metrics *LogResultCacheMetrics
 	logger  log.Logger
@@ -96,7 +104,13 @@ func (l *logResultCache) Do(ctx context.Context, req queryrangebase.Request) (qu
 	cacheFreshnessCapture := func(id string) time.Duration { return l.limits.MaxCacheFreshness(ctx, id) }
 	maxCacheFreshness := validation.MaxDurationPerTenant(tenantIDs, cacheFreshnessCapture)
 	maxCacheTime := int64(model.Now().Add(-maxCacheFreshness))
-	if req.GetEnd().UnixMilli() > maxCacheTime {
+	if l.cacheEntries {
+		// the entries of the ingesters can still change, only the part of the query before them is cached.
+		maxCacheTime = min(maxCacheTime, int64(model.Now().Add(-l.ingesterQueryWindow())))
+		if req.GetStart().UnixMilli() >= maxCacheTime {
+			return l.next.Do(ctx, req)
+		}
+	} else if req.GetEnd().UnixMilli() > maxCacheTime {
 		return l.next.Do(ctx, req)
 	}
 
@@ -128,6 +142,13 @@ func (l *logResultCache) Do(ctx context.Context, req queryrangebase.Request) (qu
 	if httpreq.ExtractHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader) == "true" {
 		cacheKey = "pipeline-disabled:" + cacheKey
 	}
+	if l.cacheEntries {
+		// the labels of the cached entries are categorized like the ones of the request.
+		if flags := httpreq.ExtractEncodingFlagsFromCtx(ctx); flags.Has(httpreq.FlagCategorizeLabels) {
+			cacheKey = "categorized:" + cacheKey
+		}
+		return l.handleEntries(ctx, "entries:"+cacheKey, lokiReq, time.UnixMilli(maxCacheTime))
+	}
 
 	_, buff, _, err := l.cache.Fetch(ctx, []string{cache.HashKey(cacheKey)})
 	if err != nil {
@@ -300,6 +321,322 @@ func (l *logResultCache) handleHit(ctx context.Context, cacheKey string, cachedR
 	return result, nil
 }
 
+// ingesterQueryWindow returns how far back the ingesters are queried, zero if they're not.
+func (l *logResultCache) ingesterQueryWindow() time.Duration {
+	if l.iqo == nil || l.iqo.QueryStoreOnly() {
+		return 0
+	}
+	return l.iqo.QueryIngestersWithin()
+}
+
+// logEntriesExtent is a time range [start, end) of a log query whose entries are all cached. The entries of its
+// streams are sorted forward.
+type logEntriesExtent struct {
+	start, end time.Time
+	streams    []logproto.Stream
+}
+
+// handleEntries handles a log query whose entries are cached. Every cache key stores a single extent, the entries of
+// the query are read from the overlap of the query with the extent and the rest of the query is fetched from the
+// queriers, then merged respecting the limit and the direction of the query. The fetched entries older than
+// immutableEnd extend the extent if they are contiguous to it.
+//
+// The response to a query with a limit holds all the entries of its time range only if it has fewer entries than the
+// limit. Otherwise, only the time range before its last entry in the direction of the query is complete, the entries
+// of the same timestamp as the last one could have been cut off. Only complete time ranges are cached.
+func (l *logResultCache) handleEntries(ctx context.Context, cacheKey string, req *LokiRequest, immutableEnd time.Time) (queryrangebase.Response, error) {
+	extent := l.fetchExtent(ctx, cacheKey)
+	if extent == nil || !extent.start.Before(req.EndTs) || !req.StartTs.Before(extent.end) {
+		l.metrics.CacheMiss.Inc()
+		level.Debug(l.logger).Log("msg", "cache miss", "key", cacheKey)
+		resp, err := l.next.Do(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		lokiRes, ok := resp.(*LokiResponse)
+		if !ok {
+			return nil, fmt.Errorf("unexpected response type %T", resp)
+		}
+		if updated, ok := extendExtent(extent, completeExtent(req, lokiRes, immutableEnd), true); ok {
+			l.storeExtent(ctx, cacheKey, updated)
+		}
+		return resp, nil
+	}
+
+	l.metrics.CacheHit.Inc()
+	cached := extent.response(req, maxTime(req.StartTs, extent.start), minTime(req.EndTs, extent.end))
+
+	// the entries of the query can be read from the cache alone if they're all cached or if the cache holds
+	// the limit of entries from the start of the query in its direction.
+	covered := !extent.start.After(req.StartTs) && !extent.end.Before(req.EndTs)
+	front := !extent.start.After(req.StartTs)
+	if req.Direction == logproto.BACKWARD {
+		front = !extent.end.Before(req.EndTs)
+	}
+	if covered || (front && countEntries(cached) >= int(req.Limit)) {
+		return mergeLokiResponse(cached), nil
+	}
+
+	var (
+		startRequest, endRequest *LokiRequest
+		startResp, endResp       *LokiResponse
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	fetch := func(r *LokiRequest, res **LokiResponse) {
+		g.Go(func() error {
+			resp, err := l.next.Do(gctx, r)
+			if err != nil {
+				return err
+			}
+			var ok bool
+			if *res, ok = resp.(*LokiResponse); !ok {
+				return fmt.Errorf("unexpected response type %T", resp)
+			}
+			return nil
+		})
+	}
+	if req.StartTs.Before(extent.start) {
+		startRequest = req.WithStartEnd(req.StartTs, extent.start).(*LokiRequest)
+		fetch(startRequest, &startResp)
+	}
+	if req.EndTs.After(extent.end) {
+		endRequest = req.WithStartEnd(extent.end, req.EndTs).(*LokiRequest)
+		fetch(endRequest, &endResp)
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// the responses are merged in the direction of the query.
+	responses := make([]queryrangebase.Response, 0, 3)
+	for _, resp := range []*LokiResponse{startResp, cached, endResp} {
+		if resp == nil {
+			continue
+		}
+		if resp.Status != loghttp.QueryStatusSuccess {
+			return resp, nil
+		}
+		responses = append(responses, resp)
+	}
+	if req.Direction == logproto.BACKWARD {
+		slices.Reverse(responses)
+	}
+	result := mergeLokiResponse(responses...)
+
+	updated, updateCache := extent, false
+	if startResp != nil {
+		updated, updateCache = extendExtent(updated, completeExtent(startRequest, startResp, immutableEnd), false)
+	}
+	if endResp != nil {
+		var ok bool
+		if updated, ok = extendExtent(updated, completeExtent(endRequest, endResp, immutableEnd), false); ok {
+			updateCache = true
+		}
+	}
+	if updateCache {
+		l.storeExtent(ctx, cacheKey, updated)
+	}
+	return result, nil
+}
+
+// fetchExtent returns the extent cached for the key, nil if there is none.
+func (l *logResultCache) fetchExtent(ctx context.Context, cacheKey string) *logEntriesExtent {
+	_, buff, _, err := l.cache.Fetch(ctx, []string{cache.HashKey(cacheKey)})
+	if err != nil {
+		level.Warn(l.logger).Log("msg", "error fetching cache", "err", err, "cacheKey", cacheKey)
+		return nil
+	}
+	if len(buff) != 1 {
+		return nil
+	}
+
+	var cached resultscache.CachedResponse
+	if err := proto.Unmarshal(buff[0], &cached); err != nil {
+		level.Warn(l.logger).Log("msg", "error unmarshalling extent from cache", "err", err)
+		return nil
+	}
+	// the key can be different if there's a hash collision.
+	if cached.Key != cacheKey || len(cached.Extents) != 1 || cached.Extents[0].Response == nil {
+		return nil
+	}
+	var res LokiResponse
+	if err := types.UnmarshalAny(cached.Extents[0].Response, &res); err != nil {
+		level.Warn(l.logger).Log("msg", "error unmarshalling extent response from cache", "err", err)
+		return nil
+	}
+	return &logEntriesExtent{
+		start:   time.Unix(0, cached.Extents[0].Start),
+		end:     time.Unix(0, cached.Extents[0].End),
+		streams: res.Data.Result,
+	}
+}
+
+func (l *logResultCache) storeExtent(ctx context.Context, cacheKey string, extent *logEntriesExtent) {
+	res, err := types.MarshalAny(&LokiResponse{
+		Status: loghttp.QueryStatusSuccess,
+		Data: LokiData{
+			ResultType: loghttp.ResultTypeStream,
+			Result:     extent.streams,
+		},
+	})
+	if err != nil {
+		level.Warn(l.logger).Log("msg", "error marshalling extent response", "err", err)
+		return
+	}
+	data, err := proto.Marshal(&resultscache.CachedResponse{
+		Key: cacheKey,
+		Extents: []resultscache.Extent{{
+			Start:    extent.start.UnixNano(),
+			End:      extent.end.UnixNano(),
+			Response: res,
+		}},
+	})
+	if err != nil {
+		level.Warn(l.logger).Log("msg", "error marshalling extent", "err", err)
+		return
+	}
+	if err := l.cache.Store(ctx, []string{cache.HashKey(cacheKey)}, [][]byte{data}); err != nil {
+		level.Warn(l.logger).Log("msg", "error storing cache", "err", err)
+	}
+}
+
+// response returns the entries of the extent within [start, end) as the response to the request.
+func (e *logEntriesExtent) response(req *LokiRequest, start, end time.Time) *LokiResponse {
+	res := emptyResponse(req)
+	for _, stream := range e.streams {
+		entries := make([]logproto.Entry, 0, len(stream.Entries))
+		for _, entry := range stream.Entries {
+			if !entry.Timestamp.Before(start) && entry.Timestamp.Before(end) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		if req.Direction == logproto.BACKWARD {
+			slices.Reverse(entries)
+		}
+		res.Data.Result = append(res.Data.Result, logproto.Stream{Labels: stream.Labels, Entries: entries, Hash: stream.Hash})
+	}
+	return res
+}
+
+// completeExtent returns the extent of the response to the request whose entries are all in the response, up to
+// immutableEnd. It returns nil if there is none.
+func completeExtent(req *LokiRequest, res *LokiResponse, immutableEnd time.Time) *logEntriesExtent {
+	if res == nil || res.Status != loghttp.QueryStatusSuccess {
+		return nil
+	}
+	start, end := req.StartTs, minTime(req.EndTs, immutableEnd)
+	if countEntries(res) >= int(req.Limit) {
+		var first, last time.Time
+		for _, stream := range res.Data.Result {
+			for _, entry := range stream.Entries {
+				if first.IsZero() || entry.Timestamp.Before(first) {
+					first = entry.Timestamp
+				}
+				if last.IsZero() || entry.Timestamp.After(last) {
+					last = entry.Timestamp
+				}
+			}
+		}
+		if req.Direction == logproto.BACKWARD {
+			start = maxTime(start, first.Add(time.Nanosecond))
+		} else {
+			end = minTime(end, last)
+		}
+	}
+	if !start.Before(end) {
+		return nil
+	}
+
+	extent := &logEntriesExtent{start: start, end: end}
+	for _, stream := range res.Data.Result {
+		entries := make([]logproto.Entry, 0, len(stream.Entries))
+		for _, entry := range stream.Entries {
+			if !entry.Timestamp.Before(start) && entry.Timestamp.Before(end) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		slices.SortStableFunc(entries, func(a, b logproto.Entry) int { return a.Timestamp.Compare(b.Timestamp) })
+		extent.streams = append(extent.streams, logproto.Stream{Labels: stream.Labels, Entries: entries, Hash: stream.Hash})
+	}
+	return extent
+}
+
+// extendExtent extends the extent with the fetched extent if they're contiguous. Otherwise, if replace is true, the
+// fetched extent replaces the extent if it's longer. It returns false if the extent is unchanged.
+func extendExtent(extent, fetched *logEntriesExtent, replace bool) (*logEntriesExtent, bool) {
+	if fetched == nil {
+		return extent, false
+	}
+	if extent == nil {
+		return fetched, true
+	}
+	if fetched.start.After(extent.end) || extent.start.After(fetched.end) {
+		if replace && fetched.end.Sub(fetched.start) > extent.end.Sub(extent.start) {
+			return fetched, true
+		}
+		return extent, false
+	}
+	if !fetched.start.Before(extent.start) && !fetched.end.After(extent.end) {
+		return extent, false
+	}
+
+	merged := &logEntriesExtent{start: minTime(extent.start, fetched.start), end: maxTime(extent.end, fetched.end)}
+	index := make(map[string]int, len(extent.streams))
+	for _, stream := range extent.streams {
+		index[stream.Labels] = len(merged.streams)
+		merged.streams = append(merged.streams, logproto.Stream{Labels: stream.Labels, Entries: slices.Clone(stream.Entries), Hash: stream.Hash})
+	}
+	for _, stream := range fetched.streams {
+		// the entries within the extent are already cached.
+		entries := make([]logproto.Entry, 0, len(stream.Entries))
+		for _, entry := range stream.Entries {
+			if entry.Timestamp.Before(extent.start) || !entry.Timestamp.Before(extent.end) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		i, ok := index[stream.Labels]
+		if !ok {
+			i = len(merged.streams)
+			index[stream.Labels] = i
+			merged.streams = append(merged.streams, logproto.Stream{Labels: stream.Labels, Hash: stream.Hash})
+		}
+		merged.streams[i].Entries = append(merged.streams[i].Entries, entries...)
+		slices.SortStableFunc(merged.streams[i].Entries, func(a, b logproto.Entry) int { return a.Timestamp.Compare(b.Timestamp) })
+	}
+	return merged, true
+}
+
+func countEntries(res *LokiResponse) int {
+	var n int
+	for _, stream := range res.Data.Result {
+		n += len(stream.Entries)
+	}
+	return n
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
 // extractLokiResponse extracts response with interval [start, end)
 func extractLokiResponse(start, end time.Time, r *LokiResponse) *LokiResponse {
 	extractedResp := LokiResponse{
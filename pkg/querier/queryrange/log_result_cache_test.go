@@ -3,6 +3,7 @@ package queryrange
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -38,6 +39,8 @@ func Test_LogResultCacheSameRange(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			false,
+			nil,
 		)
 	)
 
@@ -80,6 +83,8 @@ func Test_LogResultCacheSameRangeNonEmpty(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			false,
+			nil,
 		)
 	)
 
@@ -128,6 +133,8 @@ func Test_LogResultCacheSmallerRange(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			false,
+			nil,
 		)
 	)
 
@@ -178,6 +185,8 @@ func Test_LogResultCacheDifferentRange(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			false,
+			nil,
 		)
 	)
 
@@ -254,6 +263,8 @@ func Test_LogResultCacheDifferentRangeNonEmpty(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			false,
+			nil,
 		)
 	)
 
@@ -341,6 +352,8 @@ func Test_LogResultCacheDifferentRangeNonEmptyAndEmpty(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			false,
+			nil,
 		)
 	)
 
@@ -450,6 +463,8 @@ func Test_LogResultNonOverlappingCache(t *testing.T) {
 			mockCache,
 			nil,
 			nil,
+			nil,
+			false,
 			metrics,
 		)
 	)
@@ -592,6 +607,8 @@ func Test_LogResultCacheDifferentLimit(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			false,
+			nil,
 		)
 	)
 
@@ -628,6 +645,161 @@ func Test_LogResultCacheDifferentLimit(t *testing.T) {
 	fake.AssertExpectations(t)
 }
 
+// logStore answers log queries with the entries of a single stream within their time range, respecting their limit
+// and direction. It records the queries it receives.
+type logStore struct {
+	entries []time.Time
+	queries []*LokiRequest
+}
+
+func (s *logStore) Do(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+	req := r.(*LokiRequest)
+	s.queries = append(s.queries, req)
+	return s.response(req), nil
+}
+
+func (s *logStore) response(req *LokiRequest) *LokiResponse {
+	res := emptyResponse(req)
+	entries := make([]logproto.Entry, 0, len(s.entries))
+	for _, ts := range s.entries {
+		if !ts.Before(req.StartTs) && ts.Before(req.EndTs) {
+			entries = append(entries, logproto.Entry{Timestamp: ts, Line: fmt.Sprintf("%d", ts.UnixNano())})
+		}
+	}
+	if req.Direction == logproto.BACKWARD {
+		slices.Reverse(entries)
+	}
+	if len(entries) > int(req.Limit) {
+		entries = entries[:req.Limit]
+	}
+	if len(entries) > 0 {
+		res.Data.Result = append(res.Data.Result, logproto.Stream{Labels: lblFooBar, Entries: entries})
+	}
+	return res
+}
+
+func newLogStore(from, through time.Time) *logStore {
+	s := &logStore{}
+	for ts := from; !ts.After(through); ts = ts.Add(time.Second) {
+		s.entries = append(s.entries, ts)
+	}
+	return s
+}
+
+func Test_LogResultCacheEntries(t *testing.T) {
+	newRequest := func(start, end int64, limit uint32, direction logproto.Direction) *LokiRequest {
+		return &LokiRequest{
+			StartTs:   time.Unix(start, 0),
+			EndTs:     time.Unix(end, 0),
+			Limit:     limit,
+			Direction: direction,
+		}
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		requests []*LokiRequest
+		// the number of queries sent to the queriers after each request.
+		expQueries []int
+	}{
+		{
+			desc: "complete extents",
+			requests: []*LokiRequest{
+				newRequest(60, 90, entriesLimit, logproto.FORWARD),
+				newRequest(60, 90, entriesLimit, logproto.FORWARD),
+				newRequest(70, 80, entriesLimit, logproto.BACKWARD),
+				// the end of the query is fetched and extends the cached extent.
+				newRequest(60, 120, entriesLimit, logproto.FORWARD),
+				newRequest(60, 120, 5, logproto.BACKWARD),
+				newRequest(65, 100, 5, logproto.FORWARD),
+			},
+			expQueries: []int{1, 1, 1, 2, 2, 2},
+		},
+		{
+			desc: "extents limited by the limit of the queries",
+			requests: []*LokiRequest{
+				// only [60s, 63s) is complete.
+				newRequest(60, 120, 3, logproto.FORWARD),
+				newRequest(60, 120, 2, logproto.FORWARD),
+				// the end of the query is fetched, but it isn't contiguous to the cached extent.
+				newRequest(60, 120, 2, logproto.BACKWARD),
+				newRequest(60, 120, 2, logproto.BACKWARD),
+				// the end of the query is fetched and extends the cached extent to [60s, 67s).
+				newRequest(60, 120, 5, logproto.FORWARD),
+				newRequest(60, 120, 6, logproto.FORWARD),
+				newRequest(60, 120, 7, logproto.FORWARD),
+			},
+			expQueries: []int{1, 1, 2, 3, 4, 4, 5},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), "foo")
+			store := newLogStore(time.Unix(61, 0), time.Unix(100, 0))
+			h := NewLogResultCache(
+				log.NewNopLogger(),
+				fakeLimits{
+					splitDuration: map[string]time.Duration{"foo": time.Minute},
+				},
+				cache.NewMockCache(),
+				nil,
+				nil,
+				nil,
+				true,
+				nil,
+			).Wrap(store)
+
+			for i, req := range tc.requests {
+				resp, err := h.Do(ctx, req)
+				require.NoError(t, err)
+				require.Equal(t, store.response(req).Data.Result, resp.(*LokiResponse).Data.Result, "request %d", i)
+				require.Len(t, store.queries, tc.expQueries[i], "request %d", i)
+			}
+		})
+	}
+}
+
+func Test_LogResultCacheEntriesIngesterQueryWindow(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "foo")
+	now := time.Now().Truncate(time.Second)
+	store := newLogStore(now.Add(-4*time.Hour), now)
+	h := NewLogResultCache(
+		log.NewNopLogger(),
+		fakeLimits{
+			splitDuration: map[string]time.Duration{"foo": 24 * time.Hour},
+		},
+		cache.NewMockCache(),
+		nil,
+		nil,
+		ingesterQueryOpts{queryIngestersWithin: 3 * time.Hour},
+		true,
+		nil,
+	).Wrap(store)
+
+	req := &LokiRequest{
+		StartTs:   now.Add(-4 * time.Hour),
+		EndTs:     now.Add(-2 * time.Hour),
+		Limit:     10000,
+		Direction: logproto.BACKWARD,
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := h.Do(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, store.response(req).Data.Result, resp.(*LokiResponse).Data.Result)
+	}
+	// only the time range of the ingesters is queried again.
+	require.Len(t, store.queries, 2)
+	require.Equal(t, req.EndTs, store.queries[1].EndTs)
+	require.WithinDuration(t, now.Add(-3*time.Hour), store.queries[1].StartTs, time.Minute)
+
+	// queries within the time range of the ingesters are not cached.
+	recent := req.WithStartEnd(now.Add(-time.Hour), now.Add(-30*time.Minute))
+	for i := 0; i < 2; i++ {
+		_, err := h.Do(ctx, recent)
+		require.NoError(t, err)
+	}
+	require.Len(t, store.queries, 4)
+}
+
 func TestExtractLokiResponse(t *testing.T) {
 	for _, tc := range []struct {
 		name           string
@@ -33,6 +33,9 @@ import (
 const (
 	JSONType     = `application/json; charset=utf-8`
 	ProtobufType = `application/vnd.google.protobuf`
+	NDJSONType   = `application/x-ndjson`
+	// OTLPType is the content type of the log query responses encoded as OTLP ExportLogsServiceRequest.
+	OTLPType = `application/x-protobuf`
 )
 
 // WriteQueryResponseProtobuf marshals the promql.Value to queryrange QueryResonse and then
@@ -136,6 +139,13 @@ func ResultToResponse(result logqlmodel.Result, params logql.Params) (queryrange
 			Warnings:   result.Warnings,
 			Statistics: result.Statistics,
 		}, nil
+	case logql.CountDistinctMatrix:
+		r, err := data.ToProto()
+		return &CountDistinctSketchResponse{
+			Response:   r,
+			Warnings:   result.Warnings,
+			Statistics: result.Statistics,
+		}, err
 	}
 
 	return nil, fmt.Errorf("unsupported data type: %T", result.Data)
@@ -202,6 +212,17 @@ func ResponseToResult(resp queryrangebase.Response) (logqlmodel.Result, error) {
 			Warnings:   r.Warnings,
 			Statistics: r.Statistics,
 		}, nil
+	case *CountDistinctSketchResponse:
+		matrix, err := logql.CountDistinctMatrixFromProto(r.Response)
+		if err != nil {
+			return logqlmodel.Result{}, fmt.Errorf("cannot decode count distinct sketch: %w", err)
+		}
+		return logqlmodel.Result{
+			Data:       matrix,
+			Headers:    resp.GetHeaders(),
+			Warnings:   r.Warnings,
+			Statistics: r.Statistics,
+		}, nil
 	default:
 		return logqlmodel.Result{}, fmt.Errorf("cannot decode (%T)", resp)
 	}
@@ -231,6 +252,8 @@ func QueryResponseUnwrap(res *QueryResponse) (queryrangebase.Response, error) {
 		return concrete.TopkSketches, nil
 	case *QueryResponse_QuantileSketches:
 		return concrete.QuantileSketches, nil
+	case *QueryResponse_CountDistinctSketches:
+		return concrete.CountDistinctSketches, nil
 	case *QueryResponse_PatternsResponse:
 		return concrete.PatternsResponse, nil
 	case *QueryResponse_DetectedLabels:
@@ -270,6 +293,8 @@ func QueryResponseWrap(res queryrangebase.Response) (*QueryResponse, error) {
 		p.Response = &QueryResponse_TopkSketches{response}
 	case *QuantileSketchResponse:
 		p.Response = &QueryResponse_QuantileSketches{response}
+	case *CountDistinctSketchResponse:
+		p.Response = &QueryResponse_CountDistinctSketches{response}
 	case *ShardsResponse:
 		p.Response = &QueryResponse_ShardsResponse{response}
 	case *QueryPatternsResponse:
@@ -312,6 +337,26 @@ func (Codec) QueryRequestUnwrap(ctx context.Context, req *QueryRequest) (queryra
 		ctx = httpreq.InjectHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader, disableWrappers)
 	}
 
+	// Add encoding flags
+	if encodingFlags, ok := req.Metadata[httpreq.LokiEncodingFlagsHeader]; ok {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiEncodingFlagsHeader, encodingFlags)
+	}
+
+	// Add explain mode
+	if explain, ok := req.Metadata[httpreq.LokiExplainHeader]; ok {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiExplainHeader, explain)
+	}
+
+	// Add pipeline stats
+	if pipelineStats, ok := req.Metadata[httpreq.LokiPipelineStatsHeader]; ok {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiPipelineStatsHeader, pipelineStats)
+	}
+
+	// Add sample ratio
+	if sample, ok := req.Metadata[httpreq.LokiSampleHeader]; ok {
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiSampleHeader, sample)
+	}
+
 	// Add limits
 	if encodedLimits, ok := req.Metadata[querylimits.HTTPHeaderQueryLimitsKey]; ok {
 		limits, err := querylimits.UnmarshalQueryLimits([]byte(encodedLimits))
@@ -429,6 +474,30 @@ func (Codec) QueryRequestWrap(ctx context.Context, r queryrangebase.Request) (*Q
 		result.Metadata[httpreq.LokiDisablePipelineWrappersHeader] = disableWrappers
 	}
 
+	// Keep encoding flags
+	encodingFlags := httpreq.ExtractHeader(ctx, httpreq.LokiEncodingFlagsHeader)
+	if encodingFlags != "" {
+		result.Metadata[httpreq.LokiEncodingFlagsHeader] = encodingFlags
+	}
+
+	// Keep explain mode
+	explain := httpreq.ExtractHeader(ctx, httpreq.LokiExplainHeader)
+	if explain != "" {
+		result.Metadata[httpreq.LokiExplainHeader] = explain
+	}
+
+	// Keep pipeline stats
+	pipelineStats := httpreq.ExtractHeader(ctx, httpreq.LokiPipelineStatsHeader)
+	if pipelineStats != "" {
+		result.Metadata[httpreq.LokiPipelineStatsHeader] = pipelineStats
+	}
+
+	// Keep sample ratio
+	sample := httpreq.ExtractHeader(ctx, httpreq.LokiSampleHeader)
+	if sample != "" {
+		result.Metadata[httpreq.LokiSampleHeader] = sample
+	}
+
 	// Add limits
 	limits := querylimits.ExtractQueryLimitsContext(ctx)
 	if limits != nil {
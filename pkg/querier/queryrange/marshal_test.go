@@ -47,6 +47,18 @@ func TestResultToResponse(t *testing.T) {
 				Headers: []queryrangebase.PrometheusResponseHeader(nil),
 			},
 		},
+		{
+			name: "empty count distinct matrix",
+			result: logqlmodel.Result{
+				Data: logql.CountDistinctMatrix([]logql.CountDistinctVector{}),
+			},
+			response: &CountDistinctSketchResponse{
+				Response: &logproto.CountDistinctSketchMatrix{
+					Values: []*logproto.CountDistinctSketchVector{},
+				},
+				Headers: []queryrangebase.PrometheusResponseHeader(nil),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -73,6 +85,7 @@ func TestResponseWrap(t *testing.T) {
 		{"streams", &LokiResponse{}, &QueryResponse_Streams{}},
 		{"topk", &TopKSketchesResponse{}, &QueryResponse_TopkSketches{}},
 		{"quantile", &QuantileSketchResponse{}, &QueryResponse_QuantileSketches{}},
+		{"count distinct", &CountDistinctSketchResponse{}, &QueryResponse_CountDistinctSketches{}},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			actual, err := QueryResponseWrap(tt.response)
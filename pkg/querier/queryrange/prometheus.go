@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache/resultscache"
@@ -46,7 +47,7 @@ func (p *LokiPromResponse) encode(ctx context.Context) (*http.Response, error) {
 	sp := opentracing.SpanFromContext(ctx)
 	var buf bytes.Buffer
 
-	err := p.encodeTo(&buf)
+	err := p.encodeTo(&buf, sampleApproximation(ctx, p))
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +66,8 @@ func (p *LokiPromResponse) encode(ctx context.Context) (*http.Response, error) {
 	return &resp, nil
 }
 
-func (p *LokiPromResponse) encodeTo(w io.Writer) error {
+// encodeTo encodes the response, with the approximation of its results if they are sampled.
+func (p *LokiPromResponse) encodeTo(w io.Writer, approximation *loghttp.Approximation) error {
 	var (
 		b   []byte
 		err error
@@ -73,11 +75,11 @@ func (p *LokiPromResponse) encodeTo(w io.Writer) error {
 
 	switch p.Response.Data.ResultType {
 	case loghttp.ResultTypeVector:
-		b, err = p.marshalVector()
+		b, err = p.marshalVector(approximation)
 	case loghttp.ResultTypeMatrix:
-		b, err = p.marshalMatrix()
+		b, err = p.marshalMatrix(approximation)
 	case loghttp.ResultTypeScalar:
-		b, err = p.marshalScalar()
+		b, err = p.marshalScalar(approximation)
 	}
 	if err != nil {
 		return err
@@ -87,13 +89,21 @@ func (p *LokiPromResponse) encodeTo(w io.Writer) error {
 	return err
 }
 
-func (p *LokiPromResponse) marshalVector() ([]byte, error) {
+func (p *LokiPromResponse) marshalVector(approximation *loghttp.Approximation) ([]byte, error) {
 	vec := make(loghttp.Vector, len(p.Response.Data.Result))
 	for i, v := range p.Response.Data.Result {
 		lbs := make(model.LabelSet, len(v.Labels))
 		for _, v := range v.Labels {
 			lbs[model.LabelName(v.Name)] = model.LabelValue(v.Value)
 		}
+		if len(v.Histograms) > 0 {
+			vec[i] = model.Sample{
+				Metric:    model.Metric(lbs),
+				Timestamp: model.Time(v.Histograms[0].TimestampMs),
+				Histogram: logqlmodel.NewSampleHistogram(v.Histograms[0].Histogram.ToFloatHistogram()),
+			}
+			continue
+		}
 		vec[i] = model.Sample{
 			Metric:    model.Metric(lbs),
 			Timestamp: model.Time(v.Samples[0].TimestampMs),
@@ -107,6 +117,7 @@ func (p *LokiPromResponse) marshalVector() ([]byte, error) {
 			ResultType string         `json:"resultType"`
 			Result     loghttp.Vector `json:"result"`
 			Statistics stats.Result   `json:"stats,omitempty"`
+			*loghttp.Approximation
 		} `json:"data,omitempty"`
 		ErrorType string   `json:"errorType,omitempty"`
 		Error     string   `json:"error,omitempty"`
@@ -117,10 +128,12 @@ func (p *LokiPromResponse) marshalVector() ([]byte, error) {
 			ResultType string         `json:"resultType"`
 			Result     loghttp.Vector `json:"result"`
 			Statistics stats.Result   `json:"stats,omitempty"`
+			*loghttp.Approximation
 		}{
-			ResultType: loghttp.ResultTypeVector,
-			Result:     vec,
-			Statistics: p.Statistics,
+			ResultType:    loghttp.ResultTypeVector,
+			Result:        vec,
+			Statistics:    p.Statistics,
+			Approximation: approximation,
 		},
 		ErrorType: p.Response.ErrorType,
 		Status:    p.Response.Status,
@@ -128,7 +141,7 @@ func (p *LokiPromResponse) marshalVector() ([]byte, error) {
 	})
 }
 
-func (p *LokiPromResponse) marshalMatrix() ([]byte, error) {
+func (p *LokiPromResponse) marshalMatrix(approximation *loghttp.Approximation) ([]byte, error) {
 
 	// Make sure nil is not encoded as null.
 	if p.Response.Data.Result == nil {
@@ -141,6 +154,7 @@ func (p *LokiPromResponse) marshalMatrix() ([]byte, error) {
 		Data   struct {
 			queryrangebase.PrometheusData
 			Statistics stats.Result `json:"stats,omitempty"`
+			*loghttp.Approximation
 		} `json:"data,omitempty"`
 		ErrorType string   `json:"errorType,omitempty"`
 		Error     string   `json:"error,omitempty"`
@@ -150,9 +164,11 @@ func (p *LokiPromResponse) marshalMatrix() ([]byte, error) {
 		Data: struct {
 			queryrangebase.PrometheusData
 			Statistics stats.Result `json:"stats,omitempty"`
+			*loghttp.Approximation
 		}{
 			PrometheusData: p.Response.Data,
 			Statistics:     p.Statistics,
+			Approximation:  approximation,
 		},
 		ErrorType: p.Response.ErrorType,
 		Status:    p.Response.Status,
@@ -160,7 +176,7 @@ func (p *LokiPromResponse) marshalMatrix() ([]byte, error) {
 	})
 }
 
-func (p *LokiPromResponse) marshalScalar() ([]byte, error) {
+func (p *LokiPromResponse) marshalScalar(approximation *loghttp.Approximation) ([]byte, error) {
 	var scalar loghttp.Scalar
 
 	for _, r := range p.Response.Data.Result {
@@ -181,6 +197,7 @@ func (p *LokiPromResponse) marshalScalar() ([]byte, error) {
 			ResultType string         `json:"resultType"`
 			Result     loghttp.Scalar `json:"result"`
 			Statistics stats.Result   `json:"stats,omitempty"`
+			*loghttp.Approximation
 		} `json:"data,omitempty"`
 		ErrorType string   `json:"errorType,omitempty"`
 		Error     string   `json:"error,omitempty"`
@@ -191,10 +208,12 @@ func (p *LokiPromResponse) marshalScalar() ([]byte, error) {
 			ResultType string         `json:"resultType"`
 			Result     loghttp.Scalar `json:"result"`
 			Statistics stats.Result   `json:"stats,omitempty"`
+			*loghttp.Approximation
 		}{
-			ResultType: loghttp.ResultTypeScalar,
-			Result:     scalar,
-			Statistics: p.Statistics,
+			ResultType:    loghttp.ResultTypeScalar,
+			Result:        scalar,
+			Statistics:    p.Statistics,
+			Approximation: approximation,
 		},
 		ErrorType: p.Response.ErrorType,
 		Status:    p.Response.Status,
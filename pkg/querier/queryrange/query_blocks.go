@@ -0,0 +1,59 @@
+package queryrange
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/queryblocks"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+// QueryBlocker returns the block of the tenant matching a query, if any.
+type QueryBlocker interface {
+	Blocked(tenantID, query string, expr syntax.Expr) *queryblocks.Block
+}
+
+// NewQueryBlocksMiddleware fails the log and metric queries matching a block of one of their tenants with
+// logqlmodel.ErrBlocked.
+func NewQueryBlocksMiddleware(blocker QueryBlocker, logger log.Logger) queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+			var expr syntax.Expr
+			switch r := req.(type) {
+			case *LokiRequest:
+				if r.Plan != nil {
+					expr = r.Plan.AST
+				}
+			case *LokiInstantRequest:
+				if r.Plan != nil {
+					expr = r.Plan.AST
+				}
+			default:
+				return next.Do(ctx, req)
+			}
+			if expr == nil {
+				return next.Do(ctx, req)
+			}
+
+			tenants, err := tenant.TenantIDs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, tenantID := range tenants {
+				if b := blocker.Blocked(tenantID, req.GetQuery(), expr); b != nil {
+					level.Warn(util_log.WithContext(ctx, logger)).Log("msg", "query matched a query block", "block", b.ID, "user", tenantID, "query", req.GetQuery())
+					logql.QueriesBlocked.WithLabelValues(tenantID).Inc()
+					return nil, logqlmodel.ErrBlocked
+				}
+			}
+			return next.Do(ctx, req)
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package queryrange
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/queryblocks"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+)
+
+type fakeQueryBlocker map[string]string
+
+func (f fakeQueryBlocker) Blocked(tenantID, query string, _ syntax.Expr) *queryblocks.Block {
+	if f[tenantID] != query {
+		return nil
+	}
+	return &queryblocks.Block{ID: "block", Tenant: tenantID}
+}
+
+func TestQueryBlocksMiddleware(t *testing.T) {
+	query := `{app="foo"}`
+	handler := NewQueryBlocksMiddleware(fakeQueryBlocker{"b": query}, log.NewNopLogger()).Wrap(queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		return &LokiResponse{}, nil
+	}))
+	req := &LokiRequest{Query: query, Plan: &plan.QueryPlan{AST: syntax.MustParseExpr(query)}}
+
+	_, err := handler.Do(user.InjectOrgID(context.Background(), "a"), req)
+	require.NoError(t, err)
+
+	// multi-tenant queries are blocked by the blocks of any of their tenants.
+	_, err = handler.Do(user.InjectOrgID(context.Background(), "a|b"), req)
+	require.ErrorIs(t, err, logqlmodel.ErrBlocked)
+
+	instant := &LokiInstantRequest{Query: query, Plan: &plan.QueryPlan{AST: syntax.MustParseExpr(query)}}
+	_, err = handler.Do(user.InjectOrgID(context.Background(), "b"), instant)
+	require.ErrorIs(t, err, logqlmodel.ErrBlocked)
+}
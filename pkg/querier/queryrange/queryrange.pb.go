@@ -908,6 +908,59 @@ func (m *QuantileSketchResponse) GetStatistics() stats.Result {
 	return stats.Result{}
 }
 
+type CountDistinctSketchResponse struct {
+	Response   *github_com_grafana_loki_v3_pkg_logproto.CountDistinctSketchMatrix                                      `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/v3/pkg/logproto.CountDistinctSketchMatrix" json:"response,omitempty"`
+	Headers    []github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+	Warnings   []string                                                                                                `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Statistics stats.Result                                                                                            `protobuf:"bytes,4,opt,name=statistics,proto3" json:"statistics"`
+}
+
+func (m *CountDistinctSketchResponse) Reset()      { *m = CountDistinctSketchResponse{} }
+func (*CountDistinctSketchResponse) ProtoMessage() {}
+func (*CountDistinctSketchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{13}
+}
+func (m *CountDistinctSketchResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CountDistinctSketchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CountDistinctSketchResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CountDistinctSketchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountDistinctSketchResponse.Merge(m, src)
+}
+func (m *CountDistinctSketchResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CountDistinctSketchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountDistinctSketchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountDistinctSketchResponse proto.InternalMessageInfo
+
+func (m *CountDistinctSketchResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+func (m *CountDistinctSketchResponse) GetStatistics() stats.Result {
+	if m != nil {
+		return m.Statistics
+	}
+	return stats.Result{}
+}
+
 type ShardsResponse struct {
 	Response *github_com_grafana_loki_v3_pkg_logproto.ShardsResponse                                                 `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/v3/pkg/logproto.ShardsResponse" json:"response,omitempty"`
 	Headers  []github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
@@ -916,7 +969,7 @@ type ShardsResponse struct {
 func (m *ShardsResponse) Reset()      { *m = ShardsResponse{} }
 func (*ShardsResponse) ProtoMessage() {}
 func (*ShardsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{13}
+	return fileDescriptor_51b9d53b40d11902, []int{14}
 }
 func (m *ShardsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -953,7 +1006,7 @@ type DetectedFieldsResponse struct {
 func (m *DetectedFieldsResponse) Reset()      { *m = DetectedFieldsResponse{} }
 func (*DetectedFieldsResponse) ProtoMessage() {}
 func (*DetectedFieldsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{14}
+	return fileDescriptor_51b9d53b40d11902, []int{15}
 }
 func (m *DetectedFieldsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -990,7 +1043,7 @@ type QueryPatternsResponse struct {
 func (m *QueryPatternsResponse) Reset()      { *m = QueryPatternsResponse{} }
 func (*QueryPatternsResponse) ProtoMessage() {}
 func (*QueryPatternsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{15}
+	return fileDescriptor_51b9d53b40d11902, []int{16}
 }
 func (m *QueryPatternsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1027,7 +1080,7 @@ type DetectedLabelsResponse struct {
 func (m *DetectedLabelsResponse) Reset()      { *m = DetectedLabelsResponse{} }
 func (*DetectedLabelsResponse) ProtoMessage() {}
 func (*DetectedLabelsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{16}
+	return fileDescriptor_51b9d53b40d11902, []int{17}
 }
 func (m *DetectedLabelsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1071,13 +1124,14 @@ type QueryResponse struct {
 	//	*QueryResponse_DetectedFields
 	//	*QueryResponse_PatternsResponse
 	//	*QueryResponse_DetectedLabels
+	//	*QueryResponse_CountDistinctSketches
 	Response isQueryResponse_Response `protobuf_oneof:"response"`
 }
 
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{17}
+	return fileDescriptor_51b9d53b40d11902, []int{18}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1149,19 +1203,23 @@ type QueryResponse_PatternsResponse struct {
 type QueryResponse_DetectedLabels struct {
 	DetectedLabels *DetectedLabelsResponse `protobuf:"bytes,13,opt,name=detectedLabels,proto3,oneof"`
 }
+type QueryResponse_CountDistinctSketches struct {
+	CountDistinctSketches *CountDistinctSketchResponse `protobuf:"bytes,14,opt,name=countDistinctSketches,proto3,oneof"`
+}
 
-func (*QueryResponse_Series) isQueryResponse_Response()           {}
-func (*QueryResponse_Labels) isQueryResponse_Response()           {}
-func (*QueryResponse_Stats) isQueryResponse_Response()            {}
-func (*QueryResponse_Prom) isQueryResponse_Response()             {}
-func (*QueryResponse_Streams) isQueryResponse_Response()          {}
-func (*QueryResponse_Volume) isQueryResponse_Response()           {}
-func (*QueryResponse_TopkSketches) isQueryResponse_Response()     {}
-func (*QueryResponse_QuantileSketches) isQueryResponse_Response() {}
-func (*QueryResponse_ShardsResponse) isQueryResponse_Response()   {}
-func (*QueryResponse_DetectedFields) isQueryResponse_Response()   {}
-func (*QueryResponse_PatternsResponse) isQueryResponse_Response() {}
-func (*QueryResponse_DetectedLabels) isQueryResponse_Response()   {}
+func (*QueryResponse_Series) isQueryResponse_Response()                {}
+func (*QueryResponse_Labels) isQueryResponse_Response()                {}
+func (*QueryResponse_Stats) isQueryResponse_Response()                 {}
+func (*QueryResponse_Prom) isQueryResponse_Response()                  {}
+func (*QueryResponse_Streams) isQueryResponse_Response()               {}
+func (*QueryResponse_Volume) isQueryResponse_Response()                {}
+func (*QueryResponse_TopkSketches) isQueryResponse_Response()          {}
+func (*QueryResponse_QuantileSketches) isQueryResponse_Response()      {}
+func (*QueryResponse_ShardsResponse) isQueryResponse_Response()        {}
+func (*QueryResponse_DetectedFields) isQueryResponse_Response()        {}
+func (*QueryResponse_PatternsResponse) isQueryResponse_Response()      {}
+func (*QueryResponse_DetectedLabels) isQueryResponse_Response()        {}
+func (*QueryResponse_CountDistinctSketches) isQueryResponse_Response() {}
 
 func (m *QueryResponse) GetResponse() isQueryResponse_Response {
 	if m != nil {
@@ -1261,6 +1319,13 @@ func (m *QueryResponse) GetDetectedLabels() *DetectedLabelsResponse {
 	return nil
 }
 
+func (m *QueryResponse) GetCountDistinctSketches() *CountDistinctSketchResponse {
+	if x, ok := m.GetResponse().(*QueryResponse_CountDistinctSketches); ok {
+		return x.CountDistinctSketches
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1276,6 +1341,7 @@ func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 		(*QueryResponse_DetectedFields)(nil),
 		(*QueryResponse_PatternsResponse)(nil),
 		(*QueryResponse_DetectedLabels)(nil),
+		(*QueryResponse_CountDistinctSketches)(nil),
 	}
 }
 
@@ -1298,7 +1364,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{18}
+	return fileDescriptor_51b9d53b40d11902, []int{19}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1490,6 +1556,7 @@ func init() {
 	proto.RegisterType((*VolumeResponse)(nil), "queryrange.VolumeResponse")
 	proto.RegisterType((*TopKSketchesResponse)(nil), "queryrange.TopKSketchesResponse")
 	proto.RegisterType((*QuantileSketchResponse)(nil), "queryrange.QuantileSketchResponse")
+	proto.RegisterType((*CountDistinctSketchResponse)(nil), "queryrange.CountDistinctSketchResponse")
 	proto.RegisterType((*ShardsResponse)(nil), "queryrange.ShardsResponse")
 	proto.RegisterType((*DetectedFieldsResponse)(nil), "queryrange.DetectedFieldsResponse")
 	proto.RegisterType((*QueryPatternsResponse)(nil), "queryrange.QueryPatternsResponse")
@@ -1504,129 +1571,133 @@ func init() {
 }
 
 var fileDescriptor_51b9d53b40d11902 = []byte{
-	// 1944 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcb, 0x6f, 0x1b, 0xc7,
-	0x19, 0xe7, 0xf2, 0x29, 0x7e, 0x7a, 0x58, 0x1d, 0xab, 0xca, 0x56, 0x71, 0xb8, 0x2c, 0x81, 0x26,
-	0x6a, 0xd1, 0x92, 0x31, 0x95, 0xb8, 0x89, 0xea, 0x1a, 0xf1, 0x5a, 0x76, 0x65, 0xd7, 0x69, 0x9c,
-	0x95, 0xd0, 0x43, 0x2f, 0xc5, 0x88, 0x1c, 0x91, 0x5b, 0x91, 0xbb, 0xeb, 0xdd, 0xa1, 0x6c, 0x01,
-	0x45, 0x91, 0x7f, 0x20, 0x68, 0xee, 0xbd, 0x17, 0xbd, 0x15, 0x05, 0x7a, 0xea, 0xa9, 0xc7, 0xe4,
-	0x50, 0xc0, 0xc7, 0x80, 0x40, 0xb7, 0x35, 0xdd, 0x43, 0xa1, 0x53, 0x80, 0xfe, 0x03, 0xc5, 0x3c,
-	0x76, 0x39, 0x43, 0x52, 0x35, 0xa5, 0x16, 0x05, 0x14, 0xe4, 0x22, 0xcd, 0xe3, 0xfb, 0xcd, 0xce,
-	0xfc, 0xbe, 0xdf, 0xf7, 0xcd, 0x83, 0xf0, 0x46, 0x70, 0xd4, 0x69, 0x3c, 0x1e, 0x90, 0xd0, 0x25,
-	0x21, 0xff, 0x7f, 0x12, 0x62, 0xaf, 0x43, 0x94, 0x62, 0x3d, 0x08, 0x7d, 0xea, 0x23, 0x18, 0xb7,
-	0x6c, 0x34, 0x3b, 0x2e, 0xed, 0x0e, 0x0e, 0xea, 0x2d, 0xbf, 0xdf, 0xe8, 0xf8, 0x1d, 0xbf, 0xd1,
-	0xf1, 0xfd, 0x4e, 0x8f, 0xe0, 0xc0, 0x8d, 0x64, 0xb1, 0x11, 0x06, 0xad, 0x46, 0x44, 0x31, 0x1d,
-	0x44, 0x02, 0xbf, 0xb1, 0xc6, 0x0c, 0x79, 0x91, 0x43, 0x64, 0xab, 0x25, 0xcd, 0x79, 0xed, 0x60,
-	0x70, 0xd8, 0xa0, 0x6e, 0x9f, 0x44, 0x14, 0xf7, 0x83, 0xc4, 0x80, 0xcd, 0xaf, 0xe7, 0x77, 0x04,
-	0xd2, 0xf5, 0xda, 0xe4, 0x69, 0x07, 0x53, 0xf2, 0x04, 0x9f, 0x48, 0x83, 0x57, 0x35, 0x83, 0xa4,
-	0x20, 0x3b, 0x37, 0xb4, 0xce, 0x00, 0x53, 0x4a, 0x42, 0x4f, 0xf6, 0x7d, 0x43, 0xeb, 0x8b, 0x8e,
-	0x08, 0x6d, 0x75, 0x65, 0x57, 0x55, 0x76, 0x3d, 0xee, 0xf5, 0xfd, 0x36, 0xe9, 0xf1, 0x85, 0x44,
-	0xe2, 0xaf, 0xb4, 0xb8, 0xca, 0x2c, 0x82, 0x41, 0xd4, 0xe5, 0x7f, 0x64, 0xe3, 0x9d, 0x97, 0x72,
-	0x79, 0x80, 0x23, 0xd2, 0x68, 0x93, 0x43, 0xd7, 0x73, 0xa9, 0xeb, 0x7b, 0x91, 0x5a, 0x96, 0x83,
-	0xdc, 0x98, 0x6f, 0x90, 0x49, 0xff, 0x6c, 0xbc, 0xc9, 0x70, 0x11, 0xf5, 0x43, 0xdc, 0x21, 0x8d,
-	0x56, 0x77, 0xe0, 0x1d, 0x35, 0x5a, 0xb8, 0xd5, 0x25, 0x8d, 0x90, 0x44, 0x83, 0x1e, 0x8d, 0x44,
-	0x85, 0x9e, 0x04, 0x44, 0x7e, 0xa9, 0xf6, 0x59, 0x1e, 0x16, 0x1f, 0xfa, 0x47, 0xae, 0x43, 0x1e,
-	0x0f, 0x48, 0x44, 0xd1, 0x1a, 0x14, 0xf8, 0xa8, 0xa6, 0x51, 0x35, 0x36, 0xcb, 0x8e, 0xa8, 0xb0,
-	0xd6, 0x9e, 0xdb, 0x77, 0xa9, 0x99, 0xad, 0x1a, 0x9b, 0xcb, 0x8e, 0xa8, 0x20, 0x04, 0xf9, 0x88,
-	0x92, 0xc0, 0xcc, 0x55, 0x8d, 0xcd, 0x9c, 0xc3, 0xcb, 0x68, 0x03, 0x16, 0x5c, 0x8f, 0x92, 0xf0,
-	0x18, 0xf7, 0xcc, 0x32, 0x6f, 0x4f, 0xeb, 0xe8, 0x16, 0x94, 0x22, 0x8a, 0x43, 0xba, 0x1f, 0x99,
-	0xf9, 0xaa, 0xb1, 0xb9, 0xd8, 0xdc, 0xa8, 0x0b, 0xcf, 0xd7, 0x13, 0xcf, 0xd7, 0xf7, 0x13, 0xcf,
-	0xdb, 0x0b, 0x9f, 0xc6, 0x56, 0xe6, 0x93, 0xbf, 0x59, 0x86, 0x93, 0x80, 0xd0, 0x36, 0x14, 0x88,
-	0xd7, 0xde, 0x8f, 0xcc, 0xc2, 0x39, 0xd0, 0x02, 0x82, 0xae, 0x43, 0xb9, 0xed, 0x86, 0xa4, 0xc5,
-	0x58, 0x36, 0x8b, 0x55, 0x63, 0x73, 0xa5, 0x79, 0xb5, 0x9e, 0x0a, 0x65, 0x27, 0xe9, 0x72, 0xc6,
-	0x56, 0x6c, 0x79, 0x01, 0xa6, 0x5d, 0xb3, 0xc4, 0x99, 0xe0, 0x65, 0x54, 0x83, 0x62, 0xd4, 0xc5,
-	0x61, 0x3b, 0x32, 0x17, 0xaa, 0xb9, 0xcd, 0xb2, 0x0d, 0xa7, 0xb1, 0x25, 0x5b, 0x1c, 0xf9, 0x1f,
-	0xfd, 0x1c, 0xf2, 0x41, 0x0f, 0x7b, 0x26, 0xf0, 0x59, 0xae, 0xd6, 0x15, 0x2f, 0x3d, 0xea, 0x61,
-	0xcf, 0x7e, 0x77, 0x18, 0x5b, 0x6f, 0xab, 0xc1, 0x13, 0xe2, 0x43, 0xec, 0xe1, 0x46, 0xcf, 0x3f,
-	0x72, 0x1b, 0xc7, 0x5b, 0x0d, 0xd5, 0xf7, 0x6c, 0xa0, 0xfa, 0x87, 0x6c, 0x00, 0x06, 0x75, 0xf8,
-	0xc0, 0xe8, 0x01, 0x2c, 0x32, 0x1f, 0x93, 0x3b, 0xcc, 0xc1, 0x91, 0xb9, 0xc8, 0xbf, 0xf3, 0xca,
-	0x78, 0x35, 0xbc, 0xdd, 0x21, 0x87, 0x3f, 0x0a, 0xfd, 0x41, 0x60, 0x5f, 0x39, 0x8d, 0x2d, 0xd5,
-	0xde, 0x51, 0x2b, 0xe8, 0x01, 0xac, 0x30, 0x51, 0xb8, 0x5e, 0xe7, 0x83, 0x80, 0x2b, 0xd0, 0x5c,
-	0xe2, 0xc3, 0x5d, 0xab, 0xab, 0x92, 0xa9, 0xdf, 0xd1, 0x6c, 0xec, 0x3c, 0xa3, 0xd7, 0x99, 0x40,
-	0xd6, 0x46, 0x39, 0x40, 0x4c, 0x4b, 0xf7, 0xbd, 0x88, 0x62, 0x8f, 0x5e, 0x44, 0x52, 0x37, 0xa1,
-	0xc8, 0x82, 0x7f, 0x3f, 0xe2, 0xa2, 0x9a, 0xd7, 0xc7, 0x12, 0xa3, 0x3b, 0x39, 0x7f, 0x2e, 0x27,
-	0x17, 0x66, 0x3a, 0xb9, 0xf8, 0x52, 0x27, 0x97, 0xfe, 0x4f, 0x4e, 0x5e, 0xf8, 0xdf, 0x3a, 0xb9,
-	0x7c, 0x61, 0x27, 0x9b, 0x90, 0x67, 0xb3, 0x44, 0xab, 0x90, 0x0b, 0xf1, 0x13, 0xee, 0xd3, 0x25,
-	0x87, 0x15, 0x6b, 0xa3, 0x3c, 0x2c, 0x89, 0x54, 0x12, 0x05, 0xbe, 0x17, 0x11, 0xc6, 0xe3, 0x1e,
-	0xcf, 0xfe, 0xc2, 0xf3, 0x92, 0x47, 0xde, 0xe2, 0xc8, 0x1e, 0xf4, 0x1e, 0xe4, 0x77, 0x30, 0xc5,
-	0x5c, 0x05, 0x8b, 0xcd, 0x35, 0x95, 0x47, 0x36, 0x16, 0xeb, 0xb3, 0xd7, 0xd9, 0x44, 0x4e, 0x63,
-	0x6b, 0xa5, 0x8d, 0x29, 0xfe, 0xae, 0xdf, 0x77, 0x29, 0xe9, 0x07, 0xf4, 0xc4, 0xe1, 0x48, 0xf4,
-	0x36, 0x94, 0xef, 0x86, 0xa1, 0x1f, 0xee, 0x9f, 0x04, 0x84, 0xab, 0xa6, 0x6c, 0xbf, 0x72, 0x1a,
-	0x5b, 0x57, 0x49, 0xd2, 0xa8, 0x20, 0xc6, 0x96, 0xe8, 0xdb, 0x50, 0xe0, 0x15, 0xae, 0x93, 0xb2,
-	0x7d, 0xf5, 0x34, 0xb6, 0xae, 0x70, 0x88, 0x62, 0x2e, 0x2c, 0x74, 0x59, 0x15, 0xe6, 0x92, 0x55,
-	0xaa, 0xee, 0xa2, 0xaa, 0x6e, 0x13, 0x4a, 0xc7, 0x24, 0x8c, 0xd8, 0x30, 0x25, 0xde, 0x9e, 0x54,
-	0xd1, 0x6d, 0x00, 0x46, 0x8c, 0x1b, 0x51, 0xb7, 0x95, 0x38, 0x7b, 0xb9, 0x2e, 0x36, 0x1b, 0x87,
-	0xfb, 0xc8, 0x46, 0x92, 0x05, 0xc5, 0xd0, 0x51, 0xca, 0xe8, 0xf7, 0x06, 0x94, 0x76, 0x09, 0x6e,
-	0x93, 0x90, 0xb9, 0x37, 0xb7, 0xb9, 0xd8, 0xfc, 0x56, 0x5d, 0xdd, 0x59, 0x1e, 0x85, 0x7e, 0x9f,
-	0xd0, 0x2e, 0x19, 0x44, 0x89, 0x83, 0x84, 0xb5, 0xed, 0x0d, 0x63, 0x8b, 0xcc, 0x29, 0xd5, 0xb9,
-	0x36, 0xb4, 0x33, 0x3f, 0x75, 0x1a, 0x5b, 0xc6, 0xf7, 0x9c, 0x64, 0x96, 0xa8, 0x09, 0x0b, 0x4f,
-	0x70, 0xe8, 0xb9, 0x5e, 0x27, 0x32, 0x81, 0x47, 0xda, 0xfa, 0x69, 0x6c, 0xa1, 0xa4, 0x4d, 0x71,
-	0x44, 0x6a, 0x57, 0xfb, 0xab, 0x01, 0x5f, 0x63, 0xc2, 0xd8, 0x63, 0xf3, 0x89, 0x94, 0x14, 0xd3,
-	0xc7, 0xb4, 0xd5, 0x35, 0x0d, 0x36, 0x8c, 0x23, 0x2a, 0xea, 0x7e, 0x93, 0xfd, 0xaf, 0xf6, 0x9b,
-	0xdc, 0xf9, 0xf7, 0x9b, 0x24, 0xaf, 0xe4, 0x67, 0xe6, 0x95, 0xc2, 0x59, 0x79, 0xa5, 0xf6, 0x6b,
-	0x99, 0x43, 0x93, 0xf5, 0x9d, 0x23, 0x94, 0xee, 0xa5, 0xa1, 0x94, 0xe3, 0xb3, 0x4d, 0x15, 0x2a,
-	0xc6, 0xba, 0xdf, 0x26, 0x1e, 0x75, 0x0f, 0x5d, 0x12, 0xbe, 0x24, 0xa0, 0x14, 0x95, 0xe6, 0x74,
-	0x95, 0xaa, 0x12, 0xcb, 0x5f, 0x0a, 0x89, 0xe9, 0x71, 0x55, 0xb8, 0x40, 0x5c, 0xd5, 0xfe, 0x95,
-	0x85, 0x75, 0xe6, 0x91, 0x87, 0xf8, 0x80, 0xf4, 0x7e, 0x82, 0xfb, 0xe7, 0xf4, 0xca, 0xeb, 0x8a,
-	0x57, 0xca, 0x36, 0xfa, 0x8a, 0xf5, 0xf9, 0x58, 0xff, 0xad, 0x01, 0x0b, 0xc9, 0x06, 0x80, 0xea,
-	0x00, 0x02, 0xc6, 0x73, 0xbc, 0xe0, 0x7a, 0x85, 0x81, 0xc3, 0xb4, 0xd5, 0x51, 0x2c, 0xd0, 0x2f,
-	0xa0, 0x28, 0x6a, 0x32, 0x16, 0x94, 0x6d, 0x73, 0x8f, 0x86, 0x04, 0xf7, 0x6f, 0xb7, 0x71, 0x40,
-	0x49, 0x68, 0xbf, 0xcb, 0x66, 0x31, 0x8c, 0xad, 0x37, 0xce, 0x62, 0x29, 0x39, 0xe1, 0x4b, 0x1c,
-	0xf3, 0xaf, 0xf8, 0xa6, 0x23, 0xbf, 0x50, 0xfb, 0xd8, 0x80, 0x55, 0x36, 0x51, 0x46, 0x4d, 0x2a,
-	0x8c, 0x1d, 0x58, 0x08, 0x65, 0x99, 0x4f, 0x77, 0xb1, 0x59, 0xab, 0xeb, 0xb4, 0xce, 0xa0, 0x92,
-	0x6f, 0xb8, 0x86, 0x93, 0x22, 0xd1, 0x96, 0x46, 0x63, 0x76, 0x16, 0x8d, 0x62, 0x8f, 0x56, 0x89,
-	0xfb, 0x73, 0x16, 0xd0, 0x7d, 0x76, 0x43, 0x62, 0xfa, 0x1b, 0x4b, 0xf5, 0xe9, 0xd4, 0x8c, 0xae,
-	0x8d, 0x49, 0x99, 0xb6, 0xb7, 0x6f, 0x0d, 0x63, 0x6b, 0xfb, 0x25, 0xda, 0xf9, 0x0f, 0x78, 0x65,
-	0x15, 0xaa, 0x7c, 0xb3, 0x97, 0x41, 0xbe, 0xb5, 0x3f, 0x66, 0x61, 0xe5, 0xa7, 0x7e, 0x6f, 0xd0,
-	0x27, 0x29, 0x7d, 0xc1, 0x14, 0x7d, 0xe6, 0x98, 0x3e, 0xdd, 0xd6, 0xde, 0x1e, 0xc6, 0xd6, 0x8d,
-	0x79, 0xa9, 0xd3, 0xb1, 0x97, 0x9a, 0xb6, 0xdf, 0xe4, 0x60, 0x6d, 0xdf, 0x0f, 0x7e, 0xbc, 0xc7,
-	0x6f, 0xd1, 0x4a, 0x9a, 0xec, 0x4e, 0x91, 0xb7, 0x36, 0x26, 0x8f, 0x21, 0xde, 0xc7, 0x34, 0x74,
-	0x9f, 0xda, 0x37, 0x86, 0xb1, 0xd5, 0x9c, 0x97, 0xb8, 0x31, 0xee, 0x32, 0x93, 0xa6, 0x9d, 0x81,
-	0x72, 0xf3, 0x9d, 0x81, 0x26, 0xf2, 0x42, 0x7e, 0xbe, 0xbc, 0xf0, 0x87, 0x1c, 0xac, 0x7f, 0x38,
-	0xc0, 0x1e, 0x75, 0x7b, 0x44, 0x78, 0x28, 0xf5, 0xcf, 0x2f, 0xa7, 0xfc, 0x53, 0x19, 0xfb, 0x47,
-	0xc7, 0x48, 0x4f, 0xbd, 0x37, 0x8c, 0xad, 0x9b, 0xf3, 0x7a, 0x6a, 0xd6, 0x08, 0x5f, 0xf9, 0x6c,
-	0x1e, 0x9f, 0xfd, 0x29, 0x0b, 0x2b, 0x7b, 0xe2, 0x7c, 0x98, 0xb0, 0x75, 0x3c, 0xc3, 0x57, 0xea,
-	0x83, 0x58, 0x70, 0x50, 0xd7, 0x11, 0xe7, 0x4b, 0x47, 0x3a, 0xf6, 0x52, 0xa7, 0xa3, 0xbf, 0x64,
-	0x61, 0x7d, 0x87, 0x50, 0xd2, 0xa2, 0xa4, 0x7d, 0xcf, 0x25, 0x3d, 0x85, 0xc4, 0x8f, 0x8c, 0x29,
-	0x16, 0xab, 0xca, 0x85, 0x6e, 0x26, 0xc8, 0xb6, 0x87, 0xb1, 0x75, 0x6b, 0x5e, 0x1e, 0x67, 0x8f,
-	0x71, 0xa9, 0xf9, 0xfc, 0x2c, 0x0b, 0x5f, 0x17, 0x8f, 0x14, 0xe2, 0x05, 0x75, 0x4c, 0xe7, 0xaf,
-	0xa6, 0xd8, 0xb4, 0xd4, 0xfc, 0x31, 0x03, 0x62, 0xdf, 0x1e, 0xc6, 0xd6, 0x0f, 0xe7, 0x4f, 0x20,
-	0x33, 0x86, 0xf8, 0xd2, 0x68, 0x93, 0xdf, 0x2b, 0xce, 0xab, 0x4d, 0x1d, 0x74, 0x31, 0x6d, 0xea,
-	0x63, 0x5c, 0x6a, 0x3e, 0xff, 0x51, 0x84, 0x65, 0xae, 0x92, 0x94, 0xc6, 0xef, 0x80, 0xbc, 0x88,
-	0x49, 0x0e, 0x51, 0x72, 0x79, 0x0f, 0x83, 0x56, 0x7d, 0x4f, 0x5e, 0xd1, 0x84, 0x05, 0x7a, 0x07,
-	0x8a, 0x11, 0xbf, 0x22, 0xcb, 0x33, 0x76, 0x65, 0xf2, 0x15, 0x4a, 0xbf, 0x8c, 0xef, 0x66, 0x1c,
-	0x69, 0x8f, 0x6e, 0x42, 0xb1, 0xc7, 0x59, 0x94, 0x4f, 0x04, 0xb5, 0x49, 0xe4, 0xf4, 0xa5, 0x91,
-	0xa1, 0x05, 0x06, 0xdd, 0x80, 0x02, 0xdf, 0x00, 0xe4, 0x76, 0xa0, 0x7d, 0x76, 0xfa, 0x48, 0xbd,
-	0x9b, 0x71, 0x84, 0x39, 0x6a, 0x42, 0x3e, 0x08, 0xfd, 0xbe, 0xbc, 0x58, 0x5d, 0x9b, 0xfc, 0xa6,
-	0x7a, 0x13, 0xd9, 0xcd, 0x38, 0xdc, 0x16, 0xbd, 0x05, 0xa5, 0x88, 0x5f, 0x61, 0x22, 0xfe, 0x24,
-	0xc5, 0xce, 0xaf, 0x13, 0x30, 0x05, 0x92, 0x98, 0xa2, 0xb7, 0xa0, 0x78, 0xcc, 0x0f, 0xa8, 0xf2,
-	0x9d, 0x73, 0x43, 0x05, 0xe9, 0x47, 0x57, 0xb6, 0x2e, 0x61, 0x8b, 0xee, 0xc1, 0x12, 0xf5, 0x83,
-	0xa3, 0xe4, 0x1c, 0x28, 0x9f, 0xb3, 0xaa, 0x2a, 0x76, 0xd6, 0x39, 0x71, 0x37, 0xe3, 0x68, 0x38,
-	0xf4, 0x08, 0x56, 0x1f, 0x6b, 0x67, 0x07, 0x92, 0x3c, 0x5c, 0x6a, 0x3c, 0xcf, 0x3e, 0xd5, 0xec,
-	0x66, 0x9c, 0x29, 0x34, 0xda, 0x81, 0x95, 0x48, 0xdb, 0xe1, 0xe4, 0x23, 0xbd, 0xb6, 0x2e, 0x7d,
-	0x0f, 0xdc, 0xcd, 0x38, 0x13, 0x18, 0xf4, 0x10, 0x56, 0xda, 0x5a, 0x7e, 0x97, 0x4f, 0xf0, 0xda,
-	0xac, 0x66, 0xef, 0x00, 0x6c, 0x34, 0x1d, 0x8b, 0x3e, 0x80, 0xd5, 0x60, 0x22, 0xb7, 0xc9, 0x37,
-	0xf8, 0x6f, 0xea, 0xab, 0x9c, 0x91, 0x04, 0xd9, 0x22, 0x27, 0xc1, 0xea, 0xf4, 0x44, 0x88, 0x9b,
-	0xcb, 0x67, 0x4f, 0x4f, 0x4f, 0x02, 0xea, 0xf4, 0x44, 0x8f, 0x0d, 0xe3, 0x74, 0x54, 0xfb, 0xb8,
-	0x08, 0x4b, 0x32, 0xcc, 0xc4, 0xbb, 0xdb, 0xf7, 0xd3, 0xc8, 0x11, 0x51, 0xf6, 0xda, 0x59, 0x91,
-	0xc3, 0xcd, 0x95, 0xc0, 0x79, 0x33, 0x0d, 0x1c, 0x11, 0x72, 0xeb, 0xe3, 0x14, 0xc7, 0xbf, 0xab,
-	0x20, 0x64, 0xb0, 0x6c, 0x25, 0xc1, 0x22, 0x22, 0xed, 0xd5, 0xd9, 0xb7, 0xd7, 0x04, 0x25, 0x23,
-	0x65, 0x1b, 0x4a, 0xae, 0xf8, 0x31, 0x62, 0x56, 0x8c, 0x4d, 0xff, 0x56, 0xc1, 0xb4, 0x2f, 0x01,
-	0x68, 0x6b, 0x1c, 0x31, 0x05, 0xf9, 0xf8, 0x3e, 0x15, 0x31, 0x29, 0x28, 0x09, 0x98, 0xeb, 0x69,
-	0xc0, 0x14, 0x27, 0x1f, 0xec, 0x93, 0x70, 0x49, 0x17, 0x26, 0xa3, 0xe5, 0x2e, 0x2c, 0x27, 0xfa,
-	0xe2, 0x5d, 0x32, 0x5c, 0x5e, 0x3b, 0xeb, 0x58, 0x97, 0xe0, 0x75, 0x14, 0xba, 0x3f, 0x25, 0xca,
-	0xf2, 0xe4, 0x56, 0x3c, 0x29, 0xc9, 0x64, 0xa4, 0x49, 0x45, 0x3e, 0x80, 0x2b, 0x63, 0x51, 0x89,
-	0x39, 0xc1, 0xf4, 0xb5, 0x40, 0x93, 0x63, 0x32, 0xd4, 0x24, 0x50, 0x9d, 0x96, 0x14, 0xe3, 0xe2,
-	0x59, 0xd3, 0x4a, 0xa4, 0x38, 0x35, 0x2d, 0xd1, 0x81, 0x76, 0x61, 0xa1, 0x4f, 0x28, 0x6e, 0x63,
-	0x8a, 0xcd, 0x12, 0xdf, 0x96, 0x5e, 0x9f, 0x0a, 0x10, 0x89, 0xae, 0xbf, 0x2f, 0x0d, 0xef, 0x7a,
-	0x34, 0x3c, 0x91, 0x27, 0xeb, 0x14, 0xbd, 0xf1, 0x03, 0x58, 0xd6, 0x0c, 0xd0, 0x2a, 0xe4, 0x8e,
-	0x48, 0xf2, 0x03, 0x15, 0x2b, 0xa2, 0x35, 0x28, 0x1c, 0xe3, 0xde, 0x80, 0x70, 0x7d, 0x96, 0x1d,
-	0x51, 0xd9, 0xce, 0xbe, 0x63, 0xd8, 0x65, 0x28, 0x85, 0xe2, 0x2b, 0x76, 0xe7, 0xd9, 0xf3, 0x4a,
-	0xe6, 0xf3, 0xe7, 0x95, 0xcc, 0x17, 0xcf, 0x2b, 0xc6, 0x47, 0xa3, 0x8a, 0xf1, 0xbb, 0x51, 0xc5,
-	0xf8, 0x74, 0x54, 0x31, 0x9e, 0x8d, 0x2a, 0xc6, 0xdf, 0x47, 0x15, 0xe3, 0x9f, 0xa3, 0x4a, 0xe6,
-	0x8b, 0x51, 0xc5, 0xf8, 0xe4, 0x45, 0x25, 0xf3, 0xec, 0x45, 0x25, 0xf3, 0xf9, 0x8b, 0x4a, 0xe6,
-	0x67, 0xd7, 0xcf, 0xbd, 0x43, 0x1e, 0x14, 0x39, 0x53, 0x5b, 0xff, 0x0e, 0x00, 0x00, 0xff, 0xff,
-	0x6b, 0xd4, 0xf8, 0x77, 0xa9, 0x1f, 0x00, 0x00,
+	// 2006 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcf, 0x8f, 0xdb, 0xc6,
+	0x15, 0x16, 0xf5, 0x73, 0x35, 0xda, 0x95, 0xb7, 0xe3, 0xcd, 0x86, 0x5d, 0x3b, 0xa2, 0xaa, 0xa2,
+	0xf1, 0xb6, 0x68, 0xa9, 0x58, 0x9b, 0xb8, 0xc9, 0x36, 0x35, 0x62, 0x7a, 0xed, 0xca, 0xae, 0xd3,
+	0x38, 0xdc, 0x45, 0x0f, 0xbd, 0x04, 0xb3, 0xd2, 0xac, 0xc4, 0xae, 0x44, 0xd2, 0xe4, 0x68, 0xed,
+	0x05, 0x8a, 0x22, 0xa7, 0xde, 0x82, 0xe6, 0xde, 0x7b, 0xd1, 0x5b, 0x51, 0xa0, 0xa7, 0x9e, 0xda,
+	0x43, 0x81, 0xe4, 0x50, 0xc0, 0xc7, 0x40, 0x40, 0xd9, 0x5a, 0xbe, 0x14, 0x7b, 0x0a, 0xd0, 0x7f,
+	0xa0, 0x98, 0x1f, 0xa4, 0x86, 0x22, 0x65, 0x4b, 0x6e, 0x51, 0x60, 0x03, 0x5f, 0x24, 0xce, 0xcc,
+	0xfb, 0x86, 0x33, 0xef, 0xfb, 0xde, 0xbc, 0x99, 0x21, 0xb8, 0xe2, 0x1e, 0xf7, 0x9a, 0x0f, 0x46,
+	0xd8, 0xb3, 0xb0, 0xc7, 0xfe, 0x4f, 0x3d, 0x64, 0xf7, 0xb0, 0xf4, 0xa8, 0xbb, 0x9e, 0x43, 0x1c,
+	0x08, 0xa6, 0x35, 0x5b, 0xad, 0x9e, 0x45, 0xfa, 0xa3, 0x43, 0xbd, 0xe3, 0x0c, 0x9b, 0x3d, 0xa7,
+	0xe7, 0x34, 0x7b, 0x8e, 0xd3, 0x1b, 0x60, 0xe4, 0x5a, 0xbe, 0x78, 0x6c, 0x7a, 0x6e, 0xa7, 0xe9,
+	0x13, 0x44, 0x46, 0x3e, 0xc7, 0x6f, 0x6d, 0x50, 0x43, 0xf6, 0xc8, 0x20, 0xa2, 0x56, 0x13, 0xe6,
+	0xac, 0x74, 0x38, 0x3a, 0x6a, 0x12, 0x6b, 0x88, 0x7d, 0x82, 0x86, 0x6e, 0x68, 0x40, 0xc7, 0x37,
+	0x70, 0x7a, 0x1c, 0x69, 0xd9, 0x5d, 0xfc, 0xa8, 0x87, 0x08, 0x7e, 0x88, 0x4e, 0x85, 0xc1, 0xa5,
+	0x98, 0x41, 0xf8, 0x20, 0x1a, 0xb7, 0x62, 0x8d, 0x2e, 0x22, 0x04, 0x7b, 0xb6, 0x68, 0xfb, 0x7a,
+	0xac, 0xcd, 0x3f, 0xc6, 0xa4, 0xd3, 0x17, 0x4d, 0x75, 0xd1, 0xf4, 0x60, 0x30, 0x74, 0xba, 0x78,
+	0xc0, 0x26, 0xe2, 0xf3, 0x5f, 0x61, 0x71, 0x91, 0x5a, 0xb8, 0x23, 0xbf, 0xcf, 0x7e, 0x44, 0xe5,
+	0xcd, 0xe7, 0xfa, 0xf2, 0x10, 0xf9, 0xb8, 0xd9, 0xc5, 0x47, 0x96, 0x6d, 0x11, 0xcb, 0xb1, 0x7d,
+	0xf9, 0x59, 0x74, 0x72, 0x6d, 0xb1, 0x4e, 0x66, 0xf9, 0xd9, 0x7a, 0x83, 0xe2, 0x7c, 0xe2, 0x78,
+	0xa8, 0x87, 0x9b, 0x9d, 0xfe, 0xc8, 0x3e, 0x6e, 0x76, 0x50, 0xa7, 0x8f, 0x9b, 0x1e, 0xf6, 0x47,
+	0x03, 0xe2, 0xf3, 0x02, 0x39, 0x75, 0xb1, 0x78, 0x53, 0xe3, 0xf3, 0x3c, 0xa8, 0xdc, 0x73, 0x8e,
+	0x2d, 0x13, 0x3f, 0x18, 0x61, 0x9f, 0xc0, 0x0d, 0x50, 0x60, 0xbd, 0xaa, 0x4a, 0x5d, 0xd9, 0x2e,
+	0x9b, 0xbc, 0x40, 0x6b, 0x07, 0xd6, 0xd0, 0x22, 0x6a, 0xb6, 0xae, 0x6c, 0xaf, 0x99, 0xbc, 0x00,
+	0x21, 0xc8, 0xfb, 0x04, 0xbb, 0x6a, 0xae, 0xae, 0x6c, 0xe7, 0x4c, 0xf6, 0x0c, 0xb7, 0xc0, 0x8a,
+	0x65, 0x13, 0xec, 0x9d, 0xa0, 0x81, 0x5a, 0x66, 0xf5, 0x51, 0x19, 0x5e, 0x07, 0x25, 0x9f, 0x20,
+	0x8f, 0x1c, 0xf8, 0x6a, 0xbe, 0xae, 0x6c, 0x57, 0x5a, 0x5b, 0x3a, 0x67, 0x5e, 0x0f, 0x99, 0xd7,
+	0x0f, 0x42, 0xe6, 0x8d, 0x95, 0xcf, 0x02, 0x2d, 0xf3, 0xe9, 0x3f, 0x34, 0xc5, 0x0c, 0x41, 0x70,
+	0x17, 0x14, 0xb0, 0xdd, 0x3d, 0xf0, 0xd5, 0xc2, 0x12, 0x68, 0x0e, 0x81, 0x57, 0x41, 0xb9, 0x6b,
+	0x79, 0xb8, 0x43, 0xbd, 0xac, 0x16, 0xeb, 0xca, 0x76, 0xb5, 0x75, 0x51, 0x8f, 0x84, 0xb2, 0x17,
+	0x36, 0x99, 0x53, 0x2b, 0x3a, 0x3d, 0x17, 0x91, 0xbe, 0x5a, 0x62, 0x9e, 0x60, 0xcf, 0xb0, 0x01,
+	0x8a, 0x7e, 0x1f, 0x79, 0x5d, 0x5f, 0x5d, 0xa9, 0xe7, 0xb6, 0xcb, 0x06, 0x38, 0x0b, 0x34, 0x51,
+	0x63, 0x8a, 0x7f, 0xf8, 0x11, 0xc8, 0xbb, 0x03, 0x64, 0xab, 0x80, 0x8d, 0x72, 0x5d, 0x97, 0x58,
+	0xba, 0x3f, 0x40, 0xb6, 0xf1, 0xce, 0x38, 0xd0, 0xde, 0x92, 0x83, 0xc7, 0x43, 0x47, 0xc8, 0x46,
+	0xcd, 0x81, 0x73, 0x6c, 0x35, 0x4f, 0x76, 0x9a, 0x32, 0xf7, 0xb4, 0x23, 0xfd, 0x43, 0xda, 0x01,
+	0x85, 0x9a, 0xac, 0x63, 0x78, 0x17, 0x54, 0x28, 0xc7, 0xf8, 0x26, 0x25, 0xd8, 0x57, 0x2b, 0xec,
+	0x3d, 0xaf, 0x4e, 0x67, 0xc3, 0xea, 0x4d, 0x7c, 0xf4, 0x23, 0xcf, 0x19, 0xb9, 0xc6, 0x85, 0xb3,
+	0x40, 0x93, 0xed, 0x4d, 0xb9, 0x00, 0xef, 0x82, 0x2a, 0x15, 0x85, 0x65, 0xf7, 0x3e, 0x70, 0x99,
+	0x02, 0xd5, 0x55, 0xd6, 0xdd, 0x65, 0x5d, 0x96, 0x8c, 0x7e, 0x33, 0x66, 0x63, 0xe4, 0xa9, 0x7b,
+	0xcd, 0x19, 0x64, 0x63, 0x92, 0x03, 0x90, 0x6a, 0xe9, 0x8e, 0xed, 0x13, 0x64, 0x93, 0x17, 0x91,
+	0xd4, 0xbb, 0xa0, 0x48, 0x83, 0xff, 0xc0, 0x57, 0x73, 0x4b, 0x70, 0x2c, 0x30, 0x71, 0x92, 0xf3,
+	0x4b, 0x91, 0x5c, 0x48, 0x25, 0xb9, 0xf8, 0x5c, 0x92, 0x4b, 0xff, 0x27, 0x92, 0x57, 0xfe, 0xb7,
+	0x24, 0x97, 0x5f, 0x98, 0x64, 0x15, 0xe4, 0xe9, 0x28, 0xe1, 0x3a, 0xc8, 0x79, 0xe8, 0x21, 0xe3,
+	0x74, 0xd5, 0xa4, 0x8f, 0x8d, 0x49, 0x1e, 0xac, 0xf2, 0xa5, 0xc4, 0x77, 0x1d, 0xdb, 0xc7, 0xd4,
+	0x8f, 0xfb, 0x6c, 0xf5, 0xe7, 0xcc, 0x0b, 0x3f, 0xb2, 0x1a, 0x53, 0xb4, 0xc0, 0xf7, 0x40, 0x7e,
+	0x0f, 0x11, 0xc4, 0x54, 0x50, 0x69, 0x6d, 0xc8, 0x7e, 0xa4, 0x7d, 0xd1, 0x36, 0x63, 0x93, 0x0e,
+	0xe4, 0x2c, 0xd0, 0xaa, 0x5d, 0x44, 0xd0, 0x77, 0x9d, 0xa1, 0x45, 0xf0, 0xd0, 0x25, 0xa7, 0x26,
+	0x43, 0xc2, 0xb7, 0x40, 0xf9, 0x96, 0xe7, 0x39, 0xde, 0xc1, 0xa9, 0x8b, 0x99, 0x6a, 0xca, 0xc6,
+	0xab, 0x67, 0x81, 0x76, 0x11, 0x87, 0x95, 0x12, 0x62, 0x6a, 0x09, 0xbf, 0x0d, 0x0a, 0xac, 0xc0,
+	0x74, 0x52, 0x36, 0x2e, 0x9e, 0x05, 0xda, 0x05, 0x06, 0x91, 0xcc, 0xb9, 0x45, 0x5c, 0x56, 0x85,
+	0x85, 0x64, 0x15, 0xa9, 0xbb, 0x28, 0xab, 0x5b, 0x05, 0xa5, 0x13, 0xec, 0xf9, 0x96, 0xc3, 0x75,
+	0xb3, 0x66, 0x86, 0x45, 0x78, 0x03, 0x00, 0xea, 0x18, 0xcb, 0x27, 0x56, 0x27, 0x24, 0x7b, 0x4d,
+	0xe7, 0xc9, 0xc6, 0x64, 0x1c, 0x19, 0x50, 0x78, 0x41, 0x32, 0x34, 0xa5, 0x67, 0xf8, 0x7b, 0x05,
+	0x94, 0xda, 0x18, 0x75, 0xb1, 0x47, 0xe9, 0xcd, 0x6d, 0x57, 0x5a, 0xdf, 0xd2, 0xe5, 0xcc, 0x72,
+	0xdf, 0x73, 0x86, 0x98, 0xf4, 0xf1, 0xc8, 0x0f, 0x09, 0xe2, 0xd6, 0x86, 0x3d, 0x0e, 0x34, 0xbc,
+	0xa0, 0x54, 0x17, 0x4a, 0x68, 0x73, 0x5f, 0x75, 0x16, 0x68, 0xca, 0xf7, 0xcc, 0x70, 0x94, 0xb0,
+	0x05, 0x56, 0x1e, 0x22, 0xcf, 0xb6, 0xec, 0x9e, 0xaf, 0x02, 0x16, 0x69, 0x9b, 0x67, 0x81, 0x06,
+	0xc3, 0x3a, 0x89, 0x88, 0xc8, 0xae, 0xf1, 0x77, 0x05, 0x7c, 0x8d, 0x0a, 0x63, 0x9f, 0x8e, 0xc7,
+	0x97, 0x96, 0x98, 0x21, 0x22, 0x9d, 0xbe, 0xaa, 0xd0, 0x6e, 0x4c, 0x5e, 0x90, 0xf3, 0x4d, 0xf6,
+	0xbf, 0xca, 0x37, 0xb9, 0xe5, 0xf3, 0x4d, 0xb8, 0xae, 0xe4, 0x53, 0xd7, 0x95, 0xc2, 0xbc, 0x75,
+	0xa5, 0xf1, 0x6b, 0xb1, 0x86, 0x86, 0xf3, 0x5b, 0x22, 0x94, 0x6e, 0x47, 0xa1, 0x94, 0x63, 0xa3,
+	0x8d, 0x14, 0xca, 0xfb, 0xba, 0xd3, 0xc5, 0x36, 0xb1, 0x8e, 0x2c, 0xec, 0x3d, 0x27, 0xa0, 0x24,
+	0x95, 0xe6, 0xe2, 0x2a, 0x95, 0x25, 0x96, 0x3f, 0x17, 0x12, 0x8b, 0xc7, 0x55, 0xe1, 0x05, 0xe2,
+	0xaa, 0xf1, 0xef, 0x2c, 0xd8, 0xa4, 0x8c, 0xdc, 0x43, 0x87, 0x78, 0xf0, 0x13, 0x34, 0x5c, 0x92,
+	0x95, 0xd7, 0x25, 0x56, 0xca, 0x06, 0x7c, 0xe9, 0xf5, 0xc5, 0xbc, 0xfe, 0x5b, 0x05, 0xac, 0x84,
+	0x09, 0x00, 0xea, 0x00, 0x70, 0x18, 0x5b, 0xe3, 0xb9, 0xaf, 0xab, 0x14, 0xec, 0x45, 0xb5, 0xa6,
+	0x64, 0x01, 0x7f, 0x0e, 0x8a, 0xbc, 0x24, 0x62, 0x41, 0x4a, 0x9b, 0xfb, 0xc4, 0xc3, 0x68, 0x78,
+	0xa3, 0x8b, 0x5c, 0x82, 0x3d, 0xe3, 0x1d, 0x3a, 0x8a, 0x71, 0xa0, 0x5d, 0x99, 0xe7, 0xa5, 0x70,
+	0x87, 0x2f, 0x70, 0x94, 0x5f, 0xfe, 0x4e, 0x53, 0xbc, 0xa1, 0xf1, 0x89, 0x02, 0xd6, 0xe9, 0x40,
+	0xa9, 0x6b, 0x22, 0x61, 0xec, 0x81, 0x15, 0x4f, 0x3c, 0xb3, 0xe1, 0x56, 0x5a, 0x0d, 0x3d, 0xee,
+	0xd6, 0x14, 0x57, 0xb2, 0x84, 0xab, 0x98, 0x11, 0x12, 0xee, 0xc4, 0xdc, 0x98, 0x4d, 0x73, 0x23,
+	0xcf, 0xd1, 0xb2, 0xe3, 0xfe, 0x9c, 0x05, 0xf0, 0x0e, 0x3d, 0x21, 0x51, 0xfd, 0x4d, 0xa5, 0xfa,
+	0x28, 0x31, 0xa2, 0xcb, 0x53, 0xa7, 0x24, 0xed, 0x8d, 0xeb, 0xe3, 0x40, 0xdb, 0x7d, 0x8e, 0x76,
+	0x9e, 0x81, 0x97, 0x66, 0x21, 0xcb, 0x37, 0x7b, 0x1e, 0xe4, 0xdb, 0xf8, 0x63, 0x16, 0x54, 0x7f,
+	0xea, 0x0c, 0x46, 0x43, 0x1c, 0xb9, 0xcf, 0x4d, 0xb8, 0x4f, 0x9d, 0xba, 0x2f, 0x6e, 0x6b, 0xec,
+	0x8e, 0x03, 0xed, 0xda, 0xa2, 0xae, 0x8b, 0x63, 0xcf, 0xb5, 0xdb, 0x7e, 0x93, 0x03, 0x1b, 0x07,
+	0x8e, 0xfb, 0xe3, 0x7d, 0x76, 0x8a, 0x96, 0x96, 0xc9, 0x7e, 0xc2, 0x79, 0x1b, 0x53, 0xe7, 0x51,
+	0xc4, 0xfb, 0x88, 0x78, 0xd6, 0x23, 0xe3, 0xda, 0x38, 0xd0, 0x5a, 0x8b, 0x3a, 0x6e, 0x8a, 0x3b,
+	0xcf, 0x4e, 0x8b, 0xed, 0x81, 0x72, 0x8b, 0xed, 0x81, 0x66, 0xd6, 0x85, 0xfc, 0x62, 0xeb, 0xc2,
+	0x1f, 0x72, 0x60, 0xf3, 0xc3, 0x11, 0xb2, 0x89, 0x35, 0xc0, 0x9c, 0xa1, 0x88, 0x9f, 0x5f, 0x24,
+	0xf8, 0xa9, 0x4d, 0xf9, 0x89, 0x63, 0x04, 0x53, 0xef, 0x8d, 0x03, 0xed, 0xdd, 0x45, 0x99, 0x4a,
+	0xeb, 0xe1, 0x25, 0x67, 0x8b, 0x70, 0xf6, 0x97, 0x1c, 0xb8, 0x74, 0xd3, 0x19, 0xd9, 0x64, 0x8f,
+	0x56, 0xd8, 0x1d, 0x32, 0x43, 0xdc, 0xaf, 0x94, 0x04, 0x73, 0xdf, 0x94, 0x4e, 0x88, 0x49, 0xa4,
+	0xa0, 0xef, 0xd6, 0x38, 0xd0, 0x6e, 0x2c, 0x4a, 0xdf, 0xdc, 0x6e, 0x5e, 0x72, 0xb8, 0x08, 0x87,
+	0x7f, 0xca, 0x82, 0xea, 0x3e, 0xdf, 0xe3, 0x87, 0xde, 0x3a, 0x49, 0x89, 0x37, 0xf9, 0x52, 0xd3,
+	0x3d, 0xd4, 0xe3, 0x88, 0xe5, 0x52, 0x4a, 0x1c, 0x7b, 0xae, 0x53, 0xca, 0xdf, 0xb2, 0x60, 0x73,
+	0x0f, 0x13, 0xdc, 0x21, 0xb8, 0x7b, 0xdb, 0xc2, 0x03, 0xc9, 0x89, 0x1f, 0x27, 0xb5, 0x5f, 0x97,
+	0x0e, 0xe5, 0xa9, 0x20, 0xc3, 0x18, 0x07, 0xda, 0xf5, 0x45, 0xfd, 0x98, 0xde, 0xc7, 0xb9, 0xf6,
+	0xe7, 0xe7, 0x59, 0xf0, 0x0a, 0xbf, 0x68, 0xe2, 0xb7, 0xe0, 0x53, 0x77, 0xfe, 0x32, 0xe1, 0x4d,
+	0x4d, 0xce, 0x01, 0x29, 0x10, 0xe3, 0xc6, 0x38, 0xd0, 0x7e, 0xb8, 0x78, 0x12, 0x48, 0xe9, 0xe2,
+	0x2b, 0xa3, 0x4d, 0x76, 0x36, 0x5c, 0x56, 0x9b, 0x71, 0xd0, 0x8b, 0x69, 0x33, 0xde, 0xc7, 0xb9,
+	0xf6, 0xe7, 0x5f, 0x4b, 0x60, 0x8d, 0xa9, 0x24, 0x72, 0xe3, 0x77, 0x80, 0x38, 0x4c, 0x0b, 0x1f,
+	0xc2, 0xf0, 0x02, 0xc6, 0x73, 0x3b, 0xfa, 0xbe, 0x38, 0x66, 0x73, 0x0b, 0xf8, 0x36, 0x28, 0xfa,
+	0x74, 0x50, 0xe1, 0x39, 0xa9, 0x36, 0x7b, 0x93, 0x18, 0xbf, 0x50, 0x69, 0x67, 0x4c, 0x61, 0x4f,
+	0xaf, 0x9c, 0x07, 0xcc, 0x8b, 0x6a, 0x2e, 0x71, 0x52, 0xd3, 0xd3, 0x0f, 0xfe, 0x14, 0xcd, 0x31,
+	0xf0, 0x1a, 0x28, 0xb0, 0x04, 0xa0, 0xe6, 0x93, 0xaf, 0x4d, 0x1e, 0x8b, 0xda, 0x19, 0x93, 0x9b,
+	0xc3, 0x16, 0xc8, 0xbb, 0x9e, 0x33, 0x14, 0x87, 0xe3, 0xcb, 0xb3, 0xef, 0x94, 0x4f, 0x93, 0xed,
+	0x8c, 0xc9, 0x6c, 0xe1, 0x9b, 0xf4, 0x3e, 0x8b, 0x1e, 0x43, 0x7d, 0xb5, 0x28, 0xce, 0x20, 0x33,
+	0x30, 0x09, 0x12, 0x9a, 0xc2, 0x37, 0x41, 0xf1, 0x84, 0x1d, 0x32, 0xc4, 0x5d, 0xf5, 0x96, 0x0c,
+	0x8a, 0x1f, 0x3f, 0xe8, 0xbc, 0xb8, 0x2d, 0xbc, 0x0d, 0x56, 0x89, 0xe3, 0x1e, 0x87, 0x7b, 0x79,
+	0x71, 0x25, 0x59, 0x97, 0xb1, 0x69, 0x7b, 0xfd, 0x76, 0xc6, 0x8c, 0xe1, 0xe0, 0x7d, 0xb0, 0xfe,
+	0x20, 0xb6, 0xff, 0xc3, 0xe1, 0xe5, 0x73, 0xcc, 0xcf, 0xe9, 0x3b, 0xd3, 0x76, 0xc6, 0x4c, 0xa0,
+	0xe1, 0x1e, 0xa8, 0xfa, 0xb1, 0x0c, 0xa7, 0x82, 0xe4, 0xbc, 0xe2, 0x39, 0xb0, 0x9d, 0x31, 0x67,
+	0x30, 0xf0, 0x1e, 0xa8, 0x76, 0x63, 0xeb, 0xbb, 0x5a, 0x49, 0x8e, 0x2a, 0x3d, 0x03, 0xd0, 0xde,
+	0xe2, 0x58, 0xf8, 0x01, 0x58, 0x77, 0x67, 0xd6, 0x36, 0xf1, 0x1d, 0xe5, 0x1b, 0xf1, 0x59, 0xa6,
+	0x2c, 0x82, 0x74, 0x92, 0xb3, 0x60, 0x79, 0x78, 0x3c, 0xc4, 0xd5, 0xb5, 0xf9, 0xc3, 0x8b, 0x2f,
+	0x02, 0xf2, 0xf0, 0x78, 0x0b, 0xfc, 0x08, 0xbc, 0xd2, 0x49, 0xee, 0xe2, 0xb0, 0xaf, 0x56, 0x59,
+	0xa7, 0x57, 0xe4, 0x4e, 0x9f, 0xb1, 0xdf, 0x6c, 0x67, 0xcc, 0xf4, 0x7e, 0x0c, 0x30, 0x5d, 0xef,
+	0x1a, 0x9f, 0x14, 0xc1, 0xaa, 0x88, 0x63, 0x7e, 0x39, 0xfb, 0xfd, 0x28, 0x34, 0x79, 0x18, 0xbf,
+	0x36, 0x2f, 0x34, 0x99, 0xb9, 0x14, 0x99, 0x6f, 0x44, 0x91, 0xc9, 0x63, 0x7a, 0x73, 0xba, 0x86,
+	0xb2, 0x89, 0x49, 0x08, 0x11, 0x8d, 0x3b, 0x61, 0x34, 0xf2, 0x50, 0xbe, 0x94, 0x7e, 0xc5, 0x11,
+	0xa2, 0x44, 0x28, 0xee, 0x82, 0x92, 0xc5, 0xbf, 0x58, 0xa5, 0x05, 0x71, 0xf2, 0x83, 0x16, 0x0d,
+	0x2e, 0x01, 0x80, 0x3b, 0xd3, 0x90, 0x2c, 0x88, 0x2f, 0x34, 0x89, 0x90, 0x8c, 0x40, 0x61, 0x44,
+	0x5e, 0x8d, 0x22, 0xb2, 0x38, 0xfb, 0x55, 0x27, 0x8c, 0xc7, 0x68, 0x62, 0x22, 0x1c, 0x6f, 0x81,
+	0xb5, 0x50, 0xc0, 0xac, 0x49, 0xc4, 0xe3, 0x6b, 0xf3, 0xf6, 0x8d, 0x21, 0x3e, 0x8e, 0x82, 0x77,
+	0x12, 0xaa, 0x2f, 0xcf, 0xe6, 0xfa, 0x59, 0xcd, 0x87, 0x3d, 0xcd, 0x4a, 0xfe, 0x2e, 0xb8, 0x30,
+	0x55, 0x2d, 0x1f, 0x13, 0x48, 0x9e, 0x1d, 0x63, 0x7a, 0x0f, 0xbb, 0x9a, 0x05, 0xca, 0xc3, 0x12,
+	0x6a, 0xaf, 0xcc, 0x1b, 0x56, 0xa8, 0xf5, 0xc4, 0xb0, 0x84, 0xd4, 0xdb, 0x60, 0x65, 0x88, 0x09,
+	0xa2, 0x57, 0xac, 0x6a, 0x89, 0xe5, 0xbd, 0xd7, 0x13, 0x11, 0x28, 0xd0, 0xfa, 0xfb, 0xc2, 0xf0,
+	0x96, 0x4d, 0xbc, 0x53, 0xb1, 0x75, 0x8f, 0xd0, 0x5b, 0x3f, 0x00, 0x6b, 0x31, 0x03, 0xfa, 0xc5,
+	0xeb, 0x18, 0x87, 0x5f, 0x31, 0xe9, 0x23, 0xfd, 0xec, 0x70, 0x82, 0x06, 0x23, 0xcc, 0xf4, 0x59,
+	0x36, 0x79, 0x61, 0x37, 0xfb, 0xb6, 0x62, 0x94, 0x41, 0xc9, 0xe3, 0x6f, 0x31, 0x7a, 0x8f, 0x9f,
+	0xd4, 0x32, 0x5f, 0x3c, 0xa9, 0x65, 0xbe, 0x7c, 0x52, 0x53, 0x3e, 0x9e, 0xd4, 0x94, 0xdf, 0x4d,
+	0x6a, 0xca, 0x67, 0x93, 0x9a, 0xf2, 0x78, 0x52, 0x53, 0xfe, 0x39, 0xa9, 0x29, 0xff, 0x9a, 0xd4,
+	0x32, 0x5f, 0x4e, 0x6a, 0xca, 0xa7, 0x4f, 0x6b, 0x99, 0xc7, 0x4f, 0x6b, 0x99, 0x2f, 0x9e, 0xd6,
+	0x32, 0x3f, 0xbb, 0xba, 0x74, 0x0a, 0x3e, 0x2c, 0x32, 0x4f, 0xed, 0xfc, 0x67, 0x00, 0xa6, 0xfa,
+	0x3e, 0x52, 0xce, 0x21, 0x00, 0x00,
 }
 
 func (this *LokiRequest) Equal(that interface{}) bool {
@@ -2200,6 +2271,53 @@ func (this *QuantileSketchResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *CountDistinctSketchResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CountDistinctSketchResponse)
+	if !ok {
+		that2, ok := that.(CountDistinctSketchResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Response == nil {
+		if this.Response != nil {
+			return false
+		}
+	} else if !this.Response.Equal(*that1.Response) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
+	if !this.Statistics.Equal(&that1.Statistics) {
+		return false
+	}
+	return true
+}
 func (this *ShardsResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2665,6 +2783,30 @@ func (this *QueryResponse_DetectedLabels) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *QueryResponse_CountDistinctSketches) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryResponse_CountDistinctSketches)
+	if !ok {
+		that2, ok := that.(QueryResponse_CountDistinctSketches)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.CountDistinctSketches.Equal(that1.CountDistinctSketches) {
+		return false
+	}
+	return true
+}
 func (this *QueryRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -3135,6 +3277,19 @@ func (this *QuantileSketchResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *CountDistinctSketchResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&queryrange.CountDistinctSketchResponse{")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	s = append(s, "Statistics: "+strings.Replace(this.Statistics.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *ShardsResponse) GoString() string {
 	if this == nil {
 		return "nil"
@@ -3183,7 +3338,7 @@ func (this *QueryResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 17)
+	s := make([]string, 0, 18)
 	s = append(s, "&queryrange.QueryResponse{")
 	if this.Status != nil {
 		s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
@@ -3290,6 +3445,14 @@ func (this *QueryResponse_DetectedLabels) GoString() string {
 		`DetectedLabels:` + fmt.Sprintf("%#v", this.DetectedLabels) + `}`}, ", ")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryResponse_CountDistinctSketches{` +
+		`CountDistinctSketches:` + fmt.Sprintf("%#v", this.CountDistinctSketches) + `}`}, ", ")
+	return s
+}
 func (this *QueryRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -4277,6 +4440,74 @@ func (m *QuantileSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
+func (m *CountDistinctSketchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CountDistinctSketchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountDistinctSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Statistics.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQueryrange(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Response != nil {
+		{
+			size := m.Response.Size()
+			i -= size
+			if _, err := m.Response.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ShardsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4757,6 +4988,27 @@ func (m *QueryResponse_DetectedLabels) MarshalToSizedBuffer(dAtA []byte) (int, e
 	}
 	return len(dAtA) - i, nil
 }
+func (m *QueryResponse_CountDistinctSketches) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResponse_CountDistinctSketches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CountDistinctSketches != nil {
+		{
+			size, err := m.CountDistinctSketches.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x72
+	}
+	return len(dAtA) - i, nil
+}
 func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5383,6 +5635,33 @@ func (m *QuantileSketchResponse) Size() (n int) {
 	return n
 }
 
+func (m *CountDistinctSketchResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	l = m.Statistics.Size()
+	n += 1 + l + sovQueryrange(uint64(l))
+	return n
+}
+
 func (m *ShardsResponse) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5619,6 +5898,18 @@ func (m *QueryResponse_DetectedLabels) Size() (n int) {
 	}
 	return n
 }
+func (m *QueryResponse_CountDistinctSketches) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CountDistinctSketches != nil {
+		l = m.CountDistinctSketches.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
 func (m *QueryRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5951,6 +6242,19 @@ func (this *QuantileSketchResponse) String() string {
 	}, "")
 	return s
 }
+func (this *CountDistinctSketchResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CountDistinctSketchResponse{`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`Statistics:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Statistics), "Result", "stats.Result", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *ShardsResponse) String() string {
 	if this == nil {
 		return "nil"
@@ -6126,6 +6430,16 @@ func (this *QueryResponse_DetectedLabels) String() string {
 	}, "")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse_CountDistinctSketches{`,
+		`CountDistinctSketches:` + strings.Replace(fmt.Sprintf("%v", this.CountDistinctSketches), "CountDistinctSketchResponse", "CountDistinctSketchResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QueryRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -8887,6 +9201,194 @@ func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *CountDistinctSketchResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CountDistinctSketchResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CountDistinctSketchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Response == nil {
+				m.Response = &github_com_grafana_loki_v3_pkg_logproto.CountDistinctSketchMatrix{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Statistics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Statistics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -9864,6 +10366,41 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Response = &QueryResponse_DetectedLabels{v}
 			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountDistinctSketches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CountDistinctSketchResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Response = &QueryResponse_CountDistinctSketches{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
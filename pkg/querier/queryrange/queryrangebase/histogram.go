@@ -0,0 +1,92 @@
+package queryrangebase
+
+import (
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+// FromFloatHistogram converts a native histogram to its protobuf representation.
+func FromFloatHistogram(h *histogram.FloatHistogram) SampleHistogram {
+	return SampleHistogram{
+		Schema:          h.Schema,
+		ZeroThreshold:   h.ZeroThreshold,
+		ZeroCount:       h.ZeroCount,
+		Count:           h.Count,
+		Sum:             h.Sum,
+		PositiveSpans:   fromSpans(h.PositiveSpans),
+		PositiveBuckets: h.PositiveBuckets,
+		NegativeSpans:   fromSpans(h.NegativeSpans),
+		NegativeBuckets: h.NegativeBuckets,
+	}
+}
+
+// ToFloatHistogram converts the protobuf representation of a native histogram back to a native histogram.
+func (m SampleHistogram) ToFloatHistogram() *histogram.FloatHistogram {
+	return &histogram.FloatHistogram{
+		// Loki only produces gauge histograms.
+		CounterResetHint: histogram.GaugeType,
+		Schema:           m.Schema,
+		ZeroThreshold:    m.ZeroThreshold,
+		ZeroCount:        m.ZeroCount,
+		Count:            m.Count,
+		Sum:              m.Sum,
+		PositiveSpans:    toSpans(m.PositiveSpans),
+		PositiveBuckets:  m.PositiveBuckets,
+		NegativeSpans:    toSpans(m.NegativeSpans),
+		NegativeBuckets:  m.NegativeBuckets,
+	}
+}
+
+func fromSpans(spans []histogram.Span) []BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	res := make([]BucketSpan, 0, len(spans))
+	for _, s := range spans {
+		res = append(res, BucketSpan{Offset: s.Offset, Length: s.Length})
+	}
+	return res
+}
+
+func toSpans(spans []BucketSpan) []histogram.Span {
+	if len(spans) == 0 {
+		return nil
+	}
+	res := make([]histogram.Span, 0, len(spans))
+	for _, s := range spans {
+		res = append(res, histogram.Span{Offset: s.Offset, Length: s.Length})
+	}
+	return res
+}
+
+// FromModelHistograms converts the histograms of the Prometheus API to their protobuf representation.
+func FromModelHistograms(histograms []model.SampleHistogramPair) []SampleHistogramPair {
+	if len(histograms) == 0 {
+		return nil
+	}
+	res := make([]SampleHistogramPair, 0, len(histograms))
+	for _, h := range histograms {
+		res = append(res, SampleHistogramPair{
+			TimestampMs: int64(h.Timestamp),
+			Histogram:   FromFloatHistogram(logqlmodel.NewFloatHistogram(h.Histogram)),
+		})
+	}
+	return res
+}
+
+// toModelHistograms converts the histograms to the representation of the Prometheus API.
+func toModelHistograms(histograms []SampleHistogramPair) []model.SampleHistogramPair {
+	if len(histograms) == 0 {
+		return nil
+	}
+	res := make([]model.SampleHistogramPair, 0, len(histograms))
+	for _, h := range histograms {
+		res = append(res, model.SampleHistogramPair{
+			Timestamp: model.Time(h.TimestampMs),
+			Histogram: logqlmodel.NewSampleHistogram(h.Histogram.ToFloatHistogram()),
+		})
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package queryrangebase
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+var testHistogram = &histogram.FloatHistogram{
+	CounterResetHint: histogram.GaugeType,
+	Schema:           3,
+	ZeroThreshold:    2.938735877055719e-39,
+	ZeroCount:        1,
+	Count:            8,
+	Sum:              12.5,
+	PositiveSpans:    []histogram.Span{{Offset: 0, Length: 2}, {Offset: 6, Length: 1}},
+	PositiveBuckets:  []float64{2, 1, 3},
+	NegativeSpans:    []histogram.Span{{Offset: -8, Length: 1}},
+	NegativeBuckets:  []float64{1},
+}
+
+func TestSampleHistogram(t *testing.T) {
+	require.Equal(t, testHistogram, FromFloatHistogram(testHistogram).ToFloatHistogram())
+
+	stream := SampleStream{
+		Labels: []logproto.LabelAdapter{{Name: "a", Value: "a1"}},
+		Histograms: []SampleHistogramPair{
+			{TimestampMs: 1000, Histogram: FromFloatHistogram(testHistogram)},
+			{TimestampMs: 2000, Histogram: FromFloatHistogram(testHistogram)},
+		},
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		data, err := stream.Marshal()
+		require.NoError(t, err)
+		var actual SampleStream
+		require.NoError(t, actual.Unmarshal(data))
+		require.Equal(t, stream, actual)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(&stream)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"histograms":[[1,{"count":"8","sum":"12.5","buckets":[`)
+
+		var actual SampleStream
+		require.NoError(t, json.Unmarshal(data, &actual))
+		require.Equal(t, stream, actual)
+	})
+}
+
+func TestMatrixMergeHistograms(t *testing.T) {
+	h := FromFloatHistogram(testHistogram)
+	labels := []logproto.LabelAdapter{{Name: "a", Value: "a1"}}
+	merged := matrixMerge([]*PrometheusResponse{
+		{Data: PrometheusData{Result: []SampleStream{{
+			Labels:     labels,
+			Histograms: []SampleHistogramPair{{TimestampMs: 1, Histogram: h}, {TimestampMs: 2, Histogram: h}},
+		}}}},
+		{Data: PrometheusData{Result: []SampleStream{{
+			Labels:     labels,
+			Histograms: []SampleHistogramPair{{TimestampMs: 2, Histogram: h}, {TimestampMs: 3, Histogram: h}},
+		}}}},
+	})
+	require.Len(t, merged, 1)
+	require.Equal(t, []SampleHistogramPair{
+		{TimestampMs: 1, Histogram: h},
+		{TimestampMs: 2, Histogram: h},
+		{TimestampMs: 3, Histogram: h},
+	}, merged[0].Histograms)
+}
@@ -283,25 +283,29 @@ func (prometheusCodec) EncodeResponse(ctx context.Context, _ *http.Request, res
 // UnmarshalJSON implements json.Unmarshaler.
 func (s *SampleStream) UnmarshalJSON(data []byte) error {
 	var stream struct {
-		Metric model.Metric            `json:"metric"`
-		Values []logproto.LegacySample `json:"values"`
+		Metric     model.Metric                `json:"metric"`
+		Values     []logproto.LegacySample     `json:"values"`
+		Histograms []model.SampleHistogramPair `json:"histograms"`
 	}
 	if err := json.Unmarshal(data, &stream); err != nil {
 		return err
 	}
 	s.Labels = logproto.FromMetricsToLabelAdapters(stream.Metric)
 	s.Samples = stream.Values
+	s.Histograms = FromModelHistograms(stream.Histograms)
 	return nil
 }
 
 // MarshalJSON implements json.Marshaler.
 func (s *SampleStream) MarshalJSON() ([]byte, error) {
 	stream := struct {
-		Metric model.Metric            `json:"metric"`
-		Values []logproto.LegacySample `json:"values"`
+		Metric     model.Metric                `json:"metric"`
+		Values     []logproto.LegacySample     `json:"values"`
+		Histograms []model.SampleHistogramPair `json:"histograms,omitempty"`
 	}{
-		Metric: logproto.FromLabelAdaptersToMetric(s.Labels),
-		Values: s.Samples,
+		Metric:     logproto.FromLabelAdaptersToMetric(s.Labels),
+		Values:     s.Samples,
+		Histograms: toModelHistograms(s.Histograms),
 	}
 	return json.Marshal(stream)
 }
@@ -331,6 +335,12 @@ func matrixMerge(resps []*PrometheusResponse) []SampleStream {
 				} // else there is no overlap, yay!
 			}
 			existing.Samples = append(existing.Samples, stream.Samples...)
+			// Same for histograms.
+			if len(existing.Histograms) > 0 && len(stream.Histograms) > 0 {
+				existingEndTs := existing.Histograms[len(existing.Histograms)-1].TimestampMs
+				stream.Histograms = sliceHistograms(stream.Histograms, existingEndTs)
+			}
+			existing.Histograms = append(existing.Histograms, stream.Histograms...)
 			output[metric] = existing
 		}
 	}
@@ -369,6 +379,15 @@ func sliceSamples(samples []logproto.LegacySample, minTs int64) []logproto.Legac
 	return samples[searchResult:]
 }
 
+// sliceHistograms is the same as sliceSamples for histograms.
+func sliceHistograms(histograms []SampleHistogramPair, minTs int64) []SampleHistogramPair {
+	searchResult := sort.Search(len(histograms), func(i int) bool {
+		return histograms[i].TimestampMs > minTs
+	})
+
+	return histograms[searchResult:]
+}
+
 func parseDurationMs(s string) (int64, error) {
 	if d, err := strconv.ParseFloat(s, 64); err == nil {
 		ts := d * float64(time.Second/time.Millisecond)
@@ -40,6 +40,13 @@ func FromValue(value parser.Value) ([]SampleStream, error) {
 	case promql.Vector:
 		res := make([]SampleStream, 0, len(v))
 		for _, sample := range v {
+			if sample.H != nil {
+				res = append(res, SampleStream{
+					Labels:     mapLabels(sample.Metric),
+					Histograms: mapHistograms(promql.HPoint{T: sample.T, H: sample.H}),
+				})
+				continue
+			}
 			res = append(res, SampleStream{
 				Labels: mapLabels(sample.Metric),
 				Samples: []logproto.LegacySample{
@@ -56,8 +63,9 @@ func FromValue(value parser.Value) ([]SampleStream, error) {
 		res := make([]SampleStream, 0, len(v))
 		for _, series := range v {
 			res = append(res, SampleStream{
-				Labels:  mapLabels(series.Metric),
-				Samples: mapPoints(series.Floats...),
+				Labels:     mapLabels(series.Metric),
+				Samples:    mapPoints(series.Floats...),
+				Histograms: mapHistograms(series.Histograms...),
 			})
 		}
 		return res, nil
@@ -89,6 +97,22 @@ func mapPoints(pts ...promql.FPoint) []logproto.LegacySample {
 	return result
 }
 
+func mapHistograms(pts ...promql.HPoint) []SampleHistogramPair {
+	if len(pts) == 0 {
+		return nil
+	}
+	result := make([]SampleHistogramPair, 0, len(pts))
+
+	for _, pt := range pts {
+		result = append(result, SampleHistogramPair{
+			TimestampMs: pt.T,
+			Histogram:   FromFloatHistogram(pt.H),
+		})
+	}
+
+	return result
+}
+
 // ResponseToSamples is needed to map back from api response to the underlying series data
 func ResponseToSamples(resp Response) ([]SampleStream, error) {
 	promRes, ok := resp.(*PrometheusResponse)
@@ -154,6 +154,34 @@ func TestFromValue(t *testing.T) {
 				},
 			},
 		},
+		// Histograms
+		{
+			input: &promql.Result{
+				Value: promql.Vector{
+					promql.Sample{
+						T: 1,
+						H: testHistogram,
+						Metric: labels.Labels{
+							{Name: "a", Value: "a1"},
+						},
+					},
+				},
+			},
+			err: false,
+			expected: []SampleStream{
+				{
+					Labels: []logproto.LabelAdapter{
+						{Name: "a", Value: "a1"},
+					},
+					Histograms: []SampleHistogramPair{
+						{
+							TimestampMs: 1,
+							Histogram:   FromFloatHistogram(testHistogram),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for i, c := range testExpr {
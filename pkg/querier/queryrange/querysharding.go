@@ -214,7 +214,8 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 			"query", r.GetQuery(),
 		)
 	}
-	strategy := version.Strategy(resolver, uint64(ast.limits.TSDBMaxBytesPerShard(tenants[0])))
+	maxBytesPerShard := validation.SmallestPositiveIntPerTenant(tenants, ast.limits.TSDBMaxBytesPerShard)
+	strategy := version.Strategy(resolver, uint64(maxBytesPerShard))
 
 	mapper := logql.NewShardMapper(strategy, ast.metrics, ast.shardAggregation)
 
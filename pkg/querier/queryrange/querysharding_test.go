@@ -374,6 +374,41 @@ func Test_ShardingByPass(t *testing.T) {
 	require.Equal(t, called, 1)
 }
 
+func Test_astMapper_Join(t *testing.T) {
+	var queries []string
+	handler := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		queries = append(queries, r.GetQuery())
+		return &LokiResponse{Status: loghttp.QueryStatusSuccess}, nil
+	})
+
+	mware := newASTMapperware(
+		ShardingConfigs{
+			config.PeriodConfig{
+				RowShards: 2,
+			},
+		},
+		testEngineOpts,
+		handler,
+		handler,
+		nil,
+		log.NewNopLogger(),
+		nilShardingMetrics,
+		fakeLimits{maxSeries: math.MaxInt32, maxQueryParallelism: 1, queryTimeout: time.Second},
+		0,
+		[]string{},
+	)
+
+	req := defaultReq()
+	req.Query = `join(5s, {app="a"} |= "x", {app="b"}) on (id)`
+	req.Plan = &plan.QueryPlan{
+		AST: syntax.MustParseExpr(req.Query),
+	}
+
+	_, err := mware.Do(user.InjectOrgID(context.Background(), "1"), req)
+	require.NoError(t, err)
+	require.Equal(t, []string{req.Query}, queries)
+}
+
 func Test_hasShards(t *testing.T) {
 	for i, tc := range []struct {
 		input    ShardingConfigs
@@ -884,3 +919,40 @@ func Test_ASTMapper_MaxLookBackPeriod(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+type tenantMaxBytesPerShardLimits struct {
+	fakeLimits
+	maxBytesPerShard map[string]int
+}
+
+func (l tenantMaxBytesPerShardLimits) TSDBMaxBytesPerShard(id string) int {
+	return l.maxBytesPerShard[id]
+}
+
+func Test_DynamicShardResolver_MultiTenant(t *testing.T) {
+	bytes := map[string]uint64{"a": 100, "b": 1000}
+	statsHandler := queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		id, err := user.ExtractOrgID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &IndexStatsResponse{Response: &logproto.IndexStatsResponse{Bytes: bytes[id]}}, nil
+	})
+	resolver := &dynamicShardResolver{
+		ctx:          user.InjectOrgID(context.Background(), "a|b"),
+		logger:       log.NewNopLogger(),
+		statsHandler: statsHandler,
+		limits: tenantMaxBytesPerShardLimits{
+			maxBytesPerShard: map[string]int{"a": 10, "b": 1000},
+		},
+		from:           model.Time(0),
+		through:        model.Time(1000),
+		maxParallelism: 1,
+	}
+
+	// the shards are sized for tenant a, with the largest shard factor.
+	factor, bytesPerShard, err := resolver.Shards(syntax.MustParseExpr(`{app="foo"}`))
+	require.NoError(t, err)
+	require.Equal(t, 16, factor)
+	require.Equal(t, uint64(1100/16), bytesPerShard)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
@@ -61,6 +62,7 @@ type Config struct {
 	SeriesCacheConfig            SeriesCacheConfig        `yaml:"series_results_cache" doc:"description=If series_results_cache is not configured and cache_series_results is true, the config for the results cache is used."`
 	CacheLabelResults            bool                     `yaml:"cache_label_results"`
 	LabelsCacheConfig            LabelsCacheConfig        `yaml:"label_results_cache" doc:"description=If label_results_cache is not configured and cache_label_results is true, the config for the results cache is used."`
+	CacheLogEntries              bool                     `yaml:"cache_log_entries"`
 }
 
 // RegisterFlags adds the flags required to configure this flag set.
@@ -77,6 +79,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.SeriesCacheConfig.RegisterFlags(f)
 	f.BoolVar(&cfg.CacheLabelResults, "querier.cache-label-results", true, "Cache label query results.")
 	cfg.LabelsCacheConfig.RegisterFlags(f)
+	f.BoolVar(&cfg.CacheLogEntries, "querier.cache-log-entries", false, "Cache the entries of log query results instead of only empty results. Only the time ranges older than query_ingesters_within and max_cache_freshness_per_query are cached. Requires cache_results.")
 }
 
 // Validate validates the config.
@@ -397,8 +400,15 @@ func (r roundTripper) Do(ctx context.Context, req base.Request) (base.Response,
 				return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
 			}
 
-			if err := validateMatchers(ctx, r.limits, e.Matchers()); err != nil {
-				return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+			matchers := [][]*labels.Matcher{e.Matchers()}
+			if j, ok := e.(*syntax.JoinExpr); ok {
+				// both sides of a join are selected separately.
+				matchers = [][]*labels.Matcher{j.Left.Matchers(), j.Right.Matchers()}
+			}
+			for _, m := range matchers {
+				if err := validateMatchers(ctx, r.limits, m); err != nil {
+					return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+				}
 			}
 
 			// Some queries we don't want to parallelize as aggressively, like limited queries and `datasample` queries
@@ -565,6 +575,8 @@ func NewLogFilterTripperware(cfg Config, engineOpts logql.EngineOpts, log log.Lo
 					return !r.GetCachingOptions().Disabled
 				},
 				cfg.Transformer,
+				iqo,
+				cfg.CacheLogEntries,
 				metrics.LogResultCacheMetrics,
 			)
 			queryRangeMiddleware = append(
@@ -0,0 +1,222 @@
+package queryrange
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/user"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+const runningQueryKey ctxKeyType = "running_query"
+
+// errQueryCanceled is the cause of the cancellation of the queries canceled with RunningQueries.Cancel.
+var errQueryCanceled = errors.New("query canceled")
+
+// RunningQueries tracks the log and metric queries running in the query frontend, so they can be listed and
+// canceled by the operators. Canceling a query cancels the context of its sub-queries, which are then canceled
+// in the schedulers and the queriers. The queries are only tracked by the query frontend running them, the responses
+// tell which instance served them so the operators can address the other ones.
+type RunningQueries struct {
+	instance string
+
+	mtx     sync.Mutex
+	queries map[string]*runningQuery
+}
+
+// RunningQuery is the state of a running query.
+type RunningQuery struct {
+	ID        string    `json:"id"`
+	Query     string    `json:"query"`
+	StartTime time.Time `json:"startTime"`
+	// ShardsTotal is the number of sub-queries sent to the queriers so far, it grows while the query is split and sharded.
+	ShardsTotal  int64 `json:"shardsTotal"`
+	ShardsDone   int64 `json:"shardsDone"`
+	BytesScanned int64 `json:"bytesScanned"`
+}
+
+type runningQuery struct {
+	id        string
+	tenant    string
+	query     string
+	startTime time.Time
+	cancel    context.CancelCauseFunc
+
+	shardsTotal  atomic.Int64
+	shardsDone   atomic.Int64
+	bytesScanned atomic.Int64
+}
+
+func NewRunningQueries() *RunningQueries {
+	instance, err := os.Hostname()
+	if err != nil || instance == "" {
+		instance = "query-frontend"
+	}
+	return &RunningQueries{instance: instance, queries: map[string]*runningQuery{}}
+}
+
+// Middleware registers the log and metric queries while they run.
+func (r *RunningQueries) Middleware() queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+			switch req.(type) {
+			case *LokiRequest, *LokiInstantRequest:
+			default:
+				return next.Do(ctx, req)
+			}
+
+			tenantID, err := user.ExtractOrgID(ctx)
+			if err != nil {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+			}
+
+			ctx, cancel := context.WithCancelCause(ctx)
+			q := &runningQuery{
+				id:        uuid.NewString(),
+				tenant:    tenantID,
+				query:     req.GetQuery(),
+				startTime: time.Now(),
+				cancel:    cancel,
+			}
+			r.add(q)
+			defer func() {
+				r.remove(q.id)
+				cancel(nil)
+			}()
+
+			resp, err := next.Do(context.WithValue(ctx, runningQueryKey, q), req)
+			if err != nil && errors.Is(context.Cause(ctx), errQueryCanceled) {
+				return nil, httpgrpc.Errorf(serverutil.StatusClientClosedRequest, "query %s was canceled", q.id)
+			}
+			return resp, err
+		})
+	})
+}
+
+// WrapDownstream counts the sub-queries of the running queries and the bytes they scanned.
+// The handler must be the one executing the sub-queries in the queriers.
+func (r *RunningQueries) WrapDownstream(next queryrangebase.Handler) queryrangebase.Handler {
+	return queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+		q, ok := ctx.Value(runningQueryKey).(*runningQuery)
+		if !ok {
+			return next.Do(ctx, req)
+		}
+
+		q.shardsTotal.Inc()
+		defer q.shardsDone.Inc()
+
+		resp, err := next.Do(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		switch r := resp.(type) {
+		case *LokiResponse:
+			q.bytesScanned.Add(r.Statistics.Summary.TotalBytesProcessed)
+		case *LokiPromResponse:
+			q.bytesScanned.Add(r.Statistics.Summary.TotalBytesProcessed)
+		}
+		return resp, nil
+	})
+}
+
+func (r *RunningQueries) add(q *runningQuery) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.queries[q.id] = q
+}
+
+func (r *RunningQueries) remove(id string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	delete(r.queries, id)
+}
+
+// List returns the running queries of the tenant, the oldest first.
+func (r *RunningQueries) List(tenantID string) []RunningQuery {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	res := []RunningQuery{}
+	for _, q := range r.queries {
+		if q.tenant != tenantID {
+			continue
+		}
+		res = append(res, RunningQuery{
+			ID:           q.id,
+			Query:        q.query,
+			StartTime:    q.startTime,
+			ShardsTotal:  q.shardsTotal.Load(),
+			ShardsDone:   q.shardsDone.Load(),
+			BytesScanned: q.bytesScanned.Load(),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].StartTime.Before(res[j].StartTime)
+	})
+	return res
+}
+
+// Cancel cancels the running query of the tenant. It returns false if the tenant has no such query.
+func (r *RunningQueries) Cancel(tenantID, id string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	q, ok := r.queries[id]
+	if !ok || q.tenant != tenantID {
+		return false
+	}
+	q.cancel(errQueryCanceled)
+	return true
+}
+
+// ListHandler lists the running queries of the tenant of the request.
+func (r *RunningQueries) ListHandler(w http.ResponseWriter, req *http.Request) {
+	tenantID, err := user.ExtractOrgID(req.Context())
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error()), w)
+		return
+	}
+
+	resp := struct {
+		Status string `json:"status"`
+		// Instance is the query frontend running the queries.
+		Instance string         `json:"instance"`
+		Data     []RunningQuery `json:"data"`
+	}{
+		Status:   "success",
+		Instance: r.instance,
+		Data:     r.List(tenantID),
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	if err := jsoniter.ConfigFastest.NewEncoder(w).Encode(resp); err != nil {
+		serverutil.WriteError(err, w)
+	}
+}
+
+// CancelHandler cancels the running query of the tenant of the request.
+func (r *RunningQueries) CancelHandler(w http.ResponseWriter, req *http.Request) {
+	tenantID, err := user.ExtractOrgID(req.Context())
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error()), w)
+		return
+	}
+
+	id := mux.Vars(req)["id"]
+	if !r.Cancel(tenantID, id) {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusNotFound, "query %s not found in the query frontend %s", id, r.instance), w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
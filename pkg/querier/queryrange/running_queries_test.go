@@ -0,0 +1,146 @@
+package queryrange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+func TestRunningQueries_TracksShardsAndBytes(t *testing.T) {
+	rq := NewRunningQueries()
+	downstream := rq.WrapDownstream(queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		return &LokiResponse{Statistics: stats.Result{Summary: stats.Summary{TotalBytesProcessed: 100}}}, nil
+	}))
+
+	var running []RunningQuery
+	handler := rq.Middleware().Wrap(queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+		for i := 0; i < 2; i++ {
+			if _, err := downstream.Do(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		running = rq.List("tenant")
+		return &LokiResponse{}, nil
+	}))
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	_, err := handler.Do(ctx, &LokiRequest{Query: `{app="foo"}`})
+	require.NoError(t, err)
+
+	require.Len(t, running, 1)
+	require.Equal(t, `{app="foo"}`, running[0].Query)
+	require.Equal(t, int64(2), running[0].ShardsTotal)
+	require.Equal(t, int64(2), running[0].ShardsDone)
+	require.Equal(t, int64(200), running[0].BytesScanned)
+
+	// finished queries are no longer listed.
+	require.Empty(t, rq.List("tenant"))
+}
+
+func TestRunningQueries_Cancel(t *testing.T) {
+	rq := NewRunningQueries()
+	started := make(chan struct{})
+	downstream := rq.WrapDownstream(queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	handler := rq.Middleware().Wrap(downstream)
+
+	errs := make(chan error, 1)
+	go func() {
+		ctx := user.InjectOrgID(context.Background(), "tenant")
+		_, err := handler.Do(ctx, &LokiInstantRequest{Query: `count_over_time({app="foo"}[1m])`})
+		errs <- err
+	}()
+	<-started
+
+	running := rq.List("tenant")
+	require.Len(t, running, 1)
+	require.Empty(t, rq.List("other"))
+
+	// queries can only be canceled by their tenant.
+	require.False(t, rq.Cancel("other", running[0].ID))
+	require.False(t, rq.Cancel("tenant", "unknown"))
+	require.True(t, rq.Cancel("tenant", running[0].ID))
+
+	select {
+	case err := <-errs:
+		resp, ok := httpgrpc.HTTPResponseFromError(err)
+		require.True(t, ok)
+		require.Equal(t, int32(serverutil.StatusClientClosedRequest), resp.Code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query was not canceled")
+	}
+	require.Empty(t, rq.List("tenant"))
+}
+
+func TestRunningQueries_Handlers(t *testing.T) {
+	rq := NewRunningQueries()
+	started := make(chan struct{})
+	handler := rq.Middleware().Wrap(queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctx := user.InjectOrgID(context.Background(), "tenant")
+		_, _ = handler.Do(ctx, &LokiRequest{Query: `{app="foo"}`})
+	}()
+	<-started
+
+	router := mux.NewRouter()
+	router.Path("/loki/api/v1/queries").Methods("GET").HandlerFunc(rq.ListHandler)
+	router.Path("/loki/api/v1/queries/{id}").Methods("DELETE").HandlerFunc(rq.CancelHandler)
+	do := func(method, path, tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("GET", "/loki/api/v1/queries", "tenant")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Status   string         `json:"status"`
+		Instance string         `json:"instance"`
+		Data     []RunningQuery `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "success", resp.Status)
+	require.Equal(t, rq.instance, resp.Instance)
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, `{app="foo"}`, resp.Data[0].Query)
+
+	w = do("DELETE", "/loki/api/v1/queries/"+resp.Data[0].ID, "other")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do("DELETE", "/loki/api/v1/queries/"+resp.Data[0].ID, "tenant")
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query was not canceled")
+	}
+
+	w = do("GET", "/loki/api/v1/queries", "tenant")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"status":"success","instance":"`+rq.instance+`","data":[]}`, w.Body.String())
+}
@@ -0,0 +1,140 @@
+package queryrange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/types"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+)
+
+var errSampledLogQuery = errors.New("sampling is only supported for metric queries")
+
+// NewSampleMiddleware scales the sums and counts of sampled metric queries, set by the sample URL parameter,
+// by the inverse of their sample ratio. The queriers only read the streams of the sample, see logql.SampleShards.
+// The samples are bounded shards, which only the TSDB index supports, so the queries reading a period of another
+// index are rejected.
+func NewSampleMiddleware(configs []config.PeriodConfig) queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return queryrangebase.HandlerFunc(func(ctx context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+			value := httpreq.ExtractHeader(ctx, httpreq.LokiSampleHeader)
+			if value == "" {
+				return next.Do(ctx, req)
+			}
+
+			switch req.(type) {
+			case *LokiRequest, *LokiInstantRequest:
+			default:
+				return next.Do(ctx, req)
+			}
+
+			ratio, err := httpreq.ParseSampleRatio(value)
+			if err != nil {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+			}
+			if ratio == 1 {
+				return next.Do(ctx, req)
+			}
+
+			scaled, err := scaleSampledRequest(req, ratio)
+			if err != nil {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+			}
+			if err := checkSampledPeriods(configs, scaled); err != nil {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+			}
+			return next.Do(ctx, scaled)
+		})
+	})
+}
+
+// scaleSampledRequest returns a copy of the request whose metric query is scaled by the inverse of the ratio.
+func scaleSampledRequest(req queryrangebase.Request, ratio float64) (queryrangebase.Request, error) {
+	var query *plan.QueryPlan
+	switch r := req.(type) {
+	case *LokiRequest:
+		query = r.Plan
+	case *LokiInstantRequest:
+		query = r.Plan
+	}
+	if query == nil {
+		return nil, errSampledLogQuery
+	}
+	expr, ok := query.AST.(syntax.SampleExpr)
+	if !ok {
+		return nil, errSampledLogQuery
+	}
+	scaled, err := logql.ScaleSampledExpr(expr, ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	switch r := req.(type) {
+	case *LokiRequest:
+		clone := *r
+		clone.Query = scaled.String()
+		clone.Plan = &plan.QueryPlan{AST: scaled}
+		return &clone, nil
+	case *LokiInstantRequest:
+		clone := *r
+		clone.Query = scaled.String()
+		clone.Plan = &plan.QueryPlan{AST: scaled}
+		return &clone, nil
+	}
+	return req, nil
+}
+
+// checkSampledPeriods returns an error if the sampled request reads a period not using the TSDB index,
+// including the ranges and offsets of its query.
+func checkSampledPeriods(configs []config.PeriodConfig, req queryrangebase.Request) error {
+	var expr syntax.Expr
+	switch r := req.(type) {
+	case *LokiRequest:
+		expr = r.Plan.AST
+	case *LokiInstantRequest:
+		expr = r.Plan.AST
+	}
+	maxRange, maxOffset, err := maxRangeVectorAndOffsetDuration(expr)
+	if err != nil {
+		return err
+	}
+	from := model.TimeFromUnixNano(req.GetStart().Add(-maxRange - maxOffset).UnixNano())
+	through := model.TimeFromUnixNano(req.GetEnd().Add(-maxOffset).UnixNano())
+	for i, cfg := range configs {
+		if cfg.From.Time.After(through) {
+			break
+		}
+		if i+1 < len(configs) && !configs[i+1].From.Time.After(from) {
+			continue
+		}
+		if cfg.IndexType != types.TSDBType {
+			return fmt.Errorf("sampling is only supported for the %s index, the query reads the period starting at %s using the %s index", types.TSDBType, cfg.From, cfg.IndexType)
+		}
+	}
+	return nil
+}
+
+// sampleApproximation returns the approximation of the results of a sampled query, nil if the query is not sampled.
+func sampleApproximation(ctx context.Context, res *LokiPromResponse) *loghttp.Approximation {
+	ratio, ok := httpreq.SampleRatio(ctx)
+	if !ok {
+		return nil
+	}
+	chunks := res.Statistics.Querier.Store.TotalChunksRef + res.Statistics.Ingester.TotalChunksMatched
+	return &loghttp.Approximation{
+		Approximate:   true,
+		SampleRatio:   ratio,
+		RelativeError: logql.SampleRelativeError(ratio, chunks),
+	}
+}
@@ -0,0 +1,158 @@
+package queryrange
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/types"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+)
+
+func TestSampleMiddleware(t *testing.T) {
+	var got queryrangebase.Request
+	handler := NewSampleMiddleware(nil).Wrap(queryrangebase.HandlerFunc(func(_ context.Context, req queryrangebase.Request) (queryrangebase.Response, error) {
+		got = req
+		return &LokiPromResponse{}, nil
+	}))
+	newRequest := func(query string) *LokiRequest {
+		return &LokiRequest{Query: query, Plan: &plan.QueryPlan{AST: syntax.MustParseExpr(query)}}
+	}
+	sampled := func(ratio string) context.Context {
+		return httpreq.InjectHeader(context.Background(), httpreq.LokiSampleHeader, ratio)
+	}
+
+	// queries are not rewritten without a sample ratio.
+	req := newRequest(`sum(count_over_time({app="foo"}[5m]))`)
+	_, err := handler.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Same(t, req, got)
+
+	_, err = handler.Do(sampled("1"), req)
+	require.NoError(t, err)
+	require.Same(t, req, got)
+
+	// the sums and counts of sampled metric queries are scaled.
+	_, err = handler.Do(sampled("0.05"), req)
+	require.NoError(t, err)
+	expected := `(sum(count_over_time({app="foo"}[5m])) * 20)`
+	require.Equal(t, expected, got.GetQuery())
+	require.Equal(t, expected, got.(*LokiRequest).Plan.AST.String())
+	require.Equal(t, `sum(count_over_time({app="foo"}[5m]))`, req.Query)
+
+	instant := &LokiInstantRequest{Query: `count(rate({app="foo"}[1m]))`, Plan: &plan.QueryPlan{AST: syntax.MustParseExpr(`count(rate({app="foo"}[1m]))`)}}
+	_, err = handler.Do(sampled("0.5"), instant)
+	require.NoError(t, err)
+	require.Equal(t, `(count(rate({app="foo"}[1m])) * 2)`, got.GetQuery())
+
+	// log queries and invalid ratios are rejected.
+	for _, tc := range []struct {
+		ratio string
+		req   queryrangebase.Request
+	}{
+		{ratio: "0.05", req: newRequest(`{app="foo"}`)},
+		{ratio: "2", req: req},
+		{ratio: "none", req: req},
+	} {
+		_, err = handler.Do(sampled(tc.ratio), tc.req)
+		resp, ok := httpgrpc.HTTPResponseFromError(err)
+		require.True(t, ok)
+		require.Equal(t, int32(http.StatusBadRequest), resp.Code)
+	}
+}
+
+func TestSampleMiddleware_SchemaPeriods(t *testing.T) {
+	tsdbFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	configs := []config.PeriodConfig{
+		{From: config.DayTime{Time: model.TimeFromUnix(tsdbFrom.AddDate(-1, 0, 0).Unix())}, IndexType: types.BoltDBShipperType},
+		{From: config.DayTime{Time: model.TimeFromUnix(tsdbFrom.Unix())}, IndexType: types.TSDBType},
+	}
+	handler := NewSampleMiddleware(configs).Wrap(queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		return &LokiPromResponse{}, nil
+	}))
+	ctx := httpreq.InjectHeader(context.Background(), httpreq.LokiSampleHeader, "0.1")
+
+	for _, tc := range []struct {
+		name       string
+		query      string
+		start, end time.Time
+		rejected   bool
+	}{
+		{name: "tsdb period", query: `sum(count_over_time({app="foo"}[1h]))`, start: tsdbFrom.Add(2 * time.Hour), end: tsdbFrom.Add(3 * time.Hour)},
+		{name: "previous period", query: `sum(count_over_time({app="foo"}[1h]))`, start: tsdbFrom.Add(-time.Hour), end: tsdbFrom.Add(time.Hour), rejected: true},
+		{name: "range reading the previous period", query: `sum(count_over_time({app="foo"}[1h]))`, start: tsdbFrom.Add(30 * time.Minute), end: tsdbFrom.Add(time.Hour), rejected: true},
+		{name: "offset reading the previous period", query: `sum(count_over_time({app="foo"}[1m] offset 3h))`, start: tsdbFrom.Add(2 * time.Hour), end: tsdbFrom.Add(3 * time.Hour), rejected: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &LokiRequest{Query: tc.query, StartTs: tc.start, EndTs: tc.end, Plan: &plan.QueryPlan{AST: syntax.MustParseExpr(tc.query)}}
+			_, err := handler.Do(ctx, req)
+			if !tc.rejected {
+				require.NoError(t, err)
+				return
+			}
+			resp, ok := httpgrpc.HTTPResponseFromError(err)
+			require.True(t, ok)
+			require.Equal(t, int32(http.StatusBadRequest), resp.Code)
+			require.Contains(t, string(resp.Body), "boltdb-shipper")
+
+			// the queries which are not sampled are not rejected.
+			_, err = handler.Do(context.Background(), req)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSampleApproximation(t *testing.T) {
+	res := &LokiPromResponse{
+		Response: &queryrangebase.PrometheusResponse{
+			Status: loghttp.QueryStatusSuccess,
+			Data: queryrangebase.PrometheusData{
+				ResultType: loghttp.ResultTypeVector,
+				Result: []queryrangebase.SampleStream{
+					{
+						Labels:  []logproto.LabelAdapter{{Name: "app", Value: "foo"}},
+						Samples: []logproto.LegacySample{{Value: 20, TimestampMs: 1000}},
+					},
+				},
+			},
+		},
+		Statistics: stats.Result{
+			Querier:  stats.Querier{Store: stats.Store{TotalChunksRef: 80}},
+			Ingester: stats.Ingester{TotalChunksMatched: 20},
+		},
+	}
+
+	decode := func(ctx context.Context) loghttp.QueryResponse {
+		r, err := encodeResponseJSON(ctx, loghttp.VersionV1, res, httpreq.EncodingFlags{})
+		require.NoError(t, err)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var resp loghttp.QueryResponse
+		require.NoError(t, json.Unmarshal(b, &resp))
+		return resp
+	}
+
+	require.Nil(t, decode(context.Background()).Data.Approximation)
+
+	ctx := httpreq.InjectHeader(context.Background(), httpreq.LokiSampleHeader, "0.1")
+	resp := decode(ctx)
+	require.Equal(t, loghttp.ResultType(loghttp.ResultTypeVector), resp.Data.ResultType)
+	require.NotNil(t, resp.Data.Approximation)
+	require.True(t, resp.Data.Approximate)
+	require.Equal(t, 0.1, resp.Data.SampleRatio)
+	require.InDelta(t, 0.0948, resp.Data.RelativeError, 0.0001)
+}
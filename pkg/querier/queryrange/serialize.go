@@ -1,13 +1,17 @@
 package queryrange
 
 import (
+	"context"
+	"io"
 	"net/http"
 
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/util/httpreq"
+	"github.com/grafana/loki/v3/pkg/util/marshal"
 	serverutil "github.com/grafana/loki/v3/pkg/util/server"
 )
 
@@ -28,11 +32,22 @@ func (rt *serializeRoundTripper) RoundTrip(r *http.Request) (*http.Response, err
 	sp, ctx := opentracing.StartSpanFromContext(ctx, "serializeRoundTripper.do")
 	defer sp.Finish()
 
+	// the OTLP logs tell the stream labels from the structured metadata and parsed labels of the entries.
+	if r.Header.Get("Accept") == OTLPType {
+		flags := httpreq.ExtractEncodingFlagsFromCtx(ctx)
+		flags.Set(httpreq.FlagCategorizeLabels)
+		ctx = httpreq.AddEncodingFlagsToContext(ctx, flags)
+	}
+
 	request, err := rt.codec.DecodeRequest(ctx, r, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	if req, ok := request.(*LokiRequest); ok && isStreamedLogQuery(r, req) {
+		return rt.streamEntries(ctx, r, req)
+	}
+
 	response, err := rt.next.Do(ctx, request)
 	if err != nil {
 		return nil, err
@@ -41,6 +56,62 @@ func (rt *serializeRoundTripper) RoundTrip(r *http.Request) (*http.Response, err
 	return rt.codec.EncodeResponse(ctx, r, response)
 }
 
+// isStreamedLogQuery returns whether the entries of the log query are streamed as NDJSON.
+func isStreamedLogQuery(r *http.Request, req *LokiRequest) bool {
+	if r.Header.Get("Accept") != NDJSONType || loghttp.GetVersion(r.RequestURI) != loghttp.VersionV1 || req.Plan == nil {
+		return false
+	}
+	_, ok := req.Plan.AST.(syntax.LogSelectorExpr)
+	return ok
+}
+
+// streamEntries executes the log query and writes its entries to the body of the response while the responses
+// of its intervals are merged. Errors happening before the first entry is written are returned like for any
+// other query, the later ones end the body with an error line.
+func (rt *serializeRoundTripper) streamEntries(ctx context.Context, r *http.Request, req *LokiRequest) (*http.Response, error) {
+	pr, pw := io.Pipe()
+	streamer := newEntryStreamer(pw, req, httpreq.ExtractEncodingFlags(r))
+
+	done, finished := make(chan error, 1), make(chan struct{})
+	go func() {
+		defer close(finished)
+		err := streamer.do(ctx, rt.next, req)
+		select {
+		case <-streamer.started:
+			if err != nil {
+				_ = marshal.WriteQueryErrorNDJSON(err, pw)
+			}
+			_ = pw.Close()
+		default:
+		}
+		done <- err
+	}()
+
+	select {
+	case <-streamer.started:
+	case err := <-done:
+		return nil, err
+	}
+
+	// the request context is canceled once the body is copied or the client is gone,
+	// unblock the writes of the streamer if it's not done yet.
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = pr.CloseWithError(ctx.Err())
+		case <-finished:
+		}
+	}()
+
+	return &http.Response{
+		Header: http.Header{
+			"Content-Type": []string{NDJSONType},
+		},
+		Body:       pr,
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
 type serializeHTTPHandler struct {
 	codec queryrangebase.Codec
 	next  queryrangebase.Handler
@@ -2,19 +2,28 @@ package queryrange
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/grafana/dskit/user"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 
 	"github.com/grafana/loki/v3/pkg/loghttp"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+
+	"github.com/grafana/loki/pkg/push"
 )
 
 func TestResponseFormat(t *testing.T) {
@@ -130,3 +139,146 @@ func TestResponseFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestSerializeRoundTripperStreamsEntries(t *testing.T) {
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		start := r.(*LokiRequest).StartTs.UnixNano()
+		entries := func(offsets ...int64) []logproto.Entry {
+			res := make([]logproto.Entry, 0, len(offsets))
+			for _, o := range offsets {
+				res = append(res, logproto.Entry{Timestamp: time.Unix(0, start+o), Line: fmt.Sprintf("%d", start+o)})
+			}
+			return res
+		}
+		first, second := entries(0, 2), entries(1)
+		if r.(*LokiRequest).Direction == logproto.BACKWARD {
+			first = entries(2, 0)
+		}
+		return &LokiResponse{
+			Status:    loghttp.QueryStatusSuccess,
+			Direction: r.(*LokiRequest).Direction,
+			Limit:     r.(*LokiRequest).Limit,
+			Version:   uint32(loghttp.VersionV1),
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result: []logproto.Stream{
+					{Labels: `{foo="bar", level="debug"}`, Entries: first},
+					{Labels: `{foo="bar", level="info"}`, Entries: second},
+				},
+			},
+		}, nil
+	})
+	split := SplitByIntervalMiddleware(testSchemas, WithSplitByLimits(fakeLimits{maxQueryParallelism: 2}, time.Hour), DefaultCodec, newDefaultSplitter(fakeLimits{}, nil), nilMetrics)
+	rt := NewSerializeRoundTripper(split.Wrap(next), DefaultCodec)
+
+	hour := time.Hour.Nanoseconds()
+	for _, tc := range []struct {
+		direction string
+		expected  []string
+	}{
+		{
+			direction: "forward",
+			expected: []string{
+				`{"stream":{"foo":"bar","level":"debug"},"values":[["0","0"]]}`,
+				`{"stream":{"foo":"bar","level":"info"},"values":[["1","1"]]}`,
+				`{"stream":{"foo":"bar","level":"debug"},"values":[["2","2"]]}`,
+				fmt.Sprintf(`{"stream":{"foo":"bar","level":"debug"},"values":[["%d","%d"]]}`, hour, hour),
+			},
+		},
+		{
+			direction: "backward",
+			expected: []string{
+				fmt.Sprintf(`{"stream":{"foo":"bar","level":"debug"},"values":[["%d","%d"]]}`, 3*hour+2, 3*hour+2),
+				fmt.Sprintf(`{"stream":{"foo":"bar","level":"info"},"values":[["%d","%d"]]}`, 3*hour+1, 3*hour+1),
+				fmt.Sprintf(`{"stream":{"foo":"bar","level":"debug"},"values":[["%d","%d"]]}`, 3*hour, 3*hour),
+				fmt.Sprintf(`{"stream":{"foo":"bar","level":"debug"},"values":[["%d","%d"]]}`, 2*hour+2, 2*hour+2),
+			},
+		},
+	} {
+		t.Run(tc.direction, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/loki/api/v1/query_range?query=%%7Bfoo%%3D%%22bar%%22%%7D&start=0&end=%d&limit=4&direction=%s", 4*hour, tc.direction), nil)
+			req.Header.Set("Accept", NDJSONType)
+			req = req.WithContext(user.InjectOrgID(context.Background(), "1"))
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, NDJSONType, resp.Header.Get("Content-Type"))
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+			require.Len(t, lines, len(tc.expected)+1)
+			for i, expected := range tc.expected {
+				require.JSONEq(t, expected, lines[i])
+			}
+			var last struct {
+				Status string       `json:"status"`
+				Stats  stats.Result `json:"stats"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+			require.Equal(t, "success", last.Status)
+			require.Equal(t, int64(2), last.Stats.Summary.Splits)
+		})
+	}
+}
+
+func TestSerializeRoundTripperStreamingError(t *testing.T) {
+	rt := NewSerializeRoundTripper(queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		return nil, errors.New("query failed")
+	}), DefaultCodec)
+
+	req := httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?query=%7Bfoo%3D%22bar%22%7D&start=0&end=1", nil)
+	req.Header.Set("Accept", NDJSONType)
+	req = req.WithContext(user.InjectOrgID(context.Background(), "1"))
+
+	_, err := rt.RoundTrip(req)
+	require.EqualError(t, err, "query failed")
+}
+
+func TestSerializeRoundTripperOTLP(t *testing.T) {
+	rt := NewSerializeRoundTripper(queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		// without categorized labels, the structured metadata of the entries is part of their stream labels.
+		streams := logqlmodel.Streams{
+			{Labels: `{app="foo", user="a"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "1"}}},
+			{Labels: `{app="foo", user="b"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(0, 2), Line: "2"}}},
+		}
+		if flags := httpreq.ExtractEncodingFlagsFromCtx(ctx); flags.Has(httpreq.FlagCategorizeLabels) {
+			streams = logqlmodel.Streams{
+				{Labels: `{app="foo"}`, Entries: []logproto.Entry{
+					{Timestamp: time.Unix(0, 1), Line: "1", StructuredMetadata: push.LabelsAdapter{{Name: "user", Value: "a"}}},
+					{Timestamp: time.Unix(0, 2), Line: "2", StructuredMetadata: push.LabelsAdapter{{Name: "user", Value: "b"}}},
+				}},
+			}
+		}
+		return &LokiResponse{
+			Status:    "success",
+			Direction: logproto.FORWARD,
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result:     streams,
+			},
+		}, nil
+	}), DefaultCodec)
+
+	req := httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range?query=%7Bapp%3D%22foo%22%7D&start=0&end=10", nil)
+	req.Header.Set("Accept", OTLPType)
+	req = req.WithContext(user.InjectOrgID(context.Background(), "1"))
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, OTLPType, resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	exportReq := plogotlp.NewExportRequest()
+	require.NoError(t, exportReq.UnmarshalProto(body))
+	logs := exportReq.Logs()
+	require.Equal(t, 1, logs.ResourceLogs().Len())
+
+	rl := logs.ResourceLogs().At(0)
+	require.Equal(t, map[string]any{"app": "foo"}, rl.Resource().Attributes().AsRaw())
+	records := rl.ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	require.Equal(t, map[string]any{"user": "a"}, records.At(0).Attributes().AsRaw())
+	require.Equal(t, map[string]any{"user": "b"}, records.At(1).Attributes().AsRaw())
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/concurrency"
 	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/common/model"
 
@@ -26,7 +27,6 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/types"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 	"github.com/grafana/loki/v3/pkg/util/spanlogger"
-	"github.com/grafana/loki/v3/pkg/util/validation"
 )
 
 func shardResolverForConf(
@@ -142,7 +142,11 @@ func getStatsForMatchers(
 }
 
 func (r *dynamicShardResolver) GetStats(e syntax.Expr) (stats.Stats, error) {
-	sp, ctx := opentracing.StartSpanFromContext(r.ctx, "dynamicShardResolver.GetStats")
+	return r.getStats(r.ctx, e)
+}
+
+func (r *dynamicShardResolver) getStats(ctx context.Context, e syntax.Expr) (stats.Stats, error) {
+	sp, ctx := opentracing.StartSpanFromContext(ctx, "dynamicShardResolver.GetStats")
 	defer sp.Finish()
 
 	start := time.Now()
@@ -188,18 +192,25 @@ func (r *dynamicShardResolver) Shards(e syntax.Expr) (int, uint64, error) {
 	log := spanlogger.FromContext(ctx)
 	defer log.Finish()
 
-	combined, err := r.GetStats(e)
-	if err != nil {
-		return 0, 0, err
-	}
-
 	tenantIDs, err := tenant.TenantIDs(ctx)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	maxBytesPerShard := validation.SmallestPositiveIntPerTenant(tenantIDs, r.limits.TSDBMaxBytesPerShard)
-	factor := sharding.GuessShardFactor(combined.Bytes, uint64(maxBytesPerShard), r.maxShards)
+	// The shards of a multi-tenant query are sized for the data and the max bytes per shard of each of its
+	// tenants, the query uses the largest shard factor of its tenants.
+	var (
+		combined stats.Stats
+		factor   int
+	)
+	for _, id := range tenantIDs {
+		tenantStats, err := r.getStats(user.InjectOrgID(ctx, id), e)
+		if err != nil {
+			return 0, 0, err
+		}
+		combined = stats.MergeStats(&combined, &tenantStats)
+		factor = max(factor, sharding.GuessShardFactor(tenantStats.Bytes, uint64(r.limits.TSDBMaxBytesPerShard(id)), r.maxShards))
+	}
 
 	var bytesPerShard = combined.Bytes
 	if factor > 0 {
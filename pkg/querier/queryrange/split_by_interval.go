@@ -101,6 +101,7 @@ func (h *splitByInterval) Process(
 	threshold int64,
 	input []*lokiResult,
 	maxSeries int,
+	streamer *entryStreamer,
 ) ([]queryrangebase.Response, error) {
 	var responses []queryrangebase.Response
 	ctx, cancel := context.WithCancelCause(ctx)
@@ -136,10 +137,18 @@ func (h *splitByInterval) Process(
 				return nil, data.err
 			}
 
+			casted, ok := data.resp.(*LokiResponse)
+			if ok && streamer != nil {
+				// the responses are received in the order of their intervals, their entries are written right away.
+				if err := streamer.stream(casted); err != nil {
+					return nil, err
+				}
+				data.resp = withoutEntries(casted)
+			}
 			responses = append(responses, data.resp)
 
 			// see if we can exit early if a limit has been reached
-			if casted, ok := data.resp.(*LokiResponse); !unlimited && ok {
+			if !unlimited && ok {
 				threshold -= casted.Count()
 
 				if threshold <= 0 {
@@ -215,10 +224,17 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 		return h.next.Do(ctx, intervals[0])
 	}
 
-	var limit int64
+	var (
+		limit    int64
+		streamer *entryStreamer
+	)
 	switch req := r.(type) {
 	case *LokiRequest:
 		limit = int64(req.Limit)
+		// the entries are streamed by this split rather than by the ones of the intervals, if any.
+		if streamer = entryStreamerFromContext(ctx); streamer != nil {
+			ctx = withEntryStreamer(ctx, nil)
+		}
 		if req.Direction == logproto.BACKWARD {
 			for i, j := 0, len(intervals)-1; i < j; i, j = i+1, j-1 {
 				intervals[i], intervals[j] = intervals[j], intervals[i]
@@ -247,7 +263,7 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 	maxSeriesCapture := func(id string) int { return h.limits.MaxQuerySeries(ctx, id) }
 	maxSeries := validation.SmallestPositiveIntPerTenant(tenantIDs, maxSeriesCapture)
 	maxParallelism := MinWeightedParallelism(ctx, tenantIDs, h.configs, h.limits, model.Time(r.GetStart().UnixMilli()), model.Time(r.GetEnd().UnixMilli()))
-	resps, err := h.Process(ctx, maxParallelism, limit, input, maxSeries)
+	resps, err := h.Process(ctx, maxParallelism, limit, input, maxSeries, streamer)
 	if err != nil {
 		return nil, err
 	}
@@ -12,8 +12,10 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
 	promql_parser "github.com/prometheus/prometheus/promql/parser"
 
+	"github.com/grafana/loki/v3/pkg/loghttp"
 	"github.com/grafana/loki/v3/pkg/logproto"
 
 	"github.com/grafana/loki/v3/pkg/logql"
@@ -46,9 +48,20 @@ var (
 		recordQueryMetrics(data)
 	})
 
-	StatsHTTPMiddleware middleware.Interface = statsHTTPMiddleware(defaultMetricRecorder)
+	StatsHTTPMiddleware middleware.Interface = statsHTTPMiddleware(defaultMetricRecorder, nil)
 )
 
+// QueryRecorder records the log and metric queries executed by the query frontend in the query history.
+type QueryRecorder interface {
+	RecordQuery(tenantID string, record loghttp.QueryHistoryRecord)
+}
+
+// NewStatsHTTPMiddleware returns the StatsHTTPMiddleware also recording the log and metric queries, failed ones
+// included, with the recorder.
+func NewStatsHTTPMiddleware(history QueryRecorder) middleware.Interface {
+	return statsHTTPMiddleware(defaultMetricRecorder, history)
+}
+
 // recordQueryMetrics will be called from Query Frontend middleware chain for any type of query.
 func recordQueryMetrics(data *queryData) {
 	logger := log.With(util_log.Logger, "component", "frontend")
@@ -93,10 +106,13 @@ type queryData struct {
 	match      []string // used in `series` query
 	label      string   // used in `labels` query
 
+	// history is the record of the log and metric queries in the query history.
+	history *loghttp.QueryHistoryRecord
+
 	recorded bool
 }
 
-func statsHTTPMiddleware(recorder metricRecorder) middleware.Interface {
+func statsHTTPMiddleware(recorder metricRecorder, history QueryRecorder) middleware.Interface {
 	return middleware.Func(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			data := &queryData{}
@@ -114,10 +130,62 @@ func statsHTTPMiddleware(recorder metricRecorder) middleware.Interface {
 				data.status = strconv.Itoa(interceptor.statusCode)
 				recorder.Record(data)
 			}
+			if history != nil && data.history != nil {
+				recordQueryHistory(r, history, data.history, strconv.Itoa(interceptor.statusCode))
+			}
 		})
 	})
 }
 
+// recordQueryHistory completes the record with the response and records it in the history of each tenant of the query.
+func recordQueryHistory(r *http.Request, history QueryRecorder, record *loghttp.QueryHistoryRecord, status string) {
+	tenants, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		return
+	}
+	record.Timestamp = time.Now()
+	record.Status = status
+	record.UserAgent = r.UserAgent()
+	for _, tenantID := range tenants {
+		history.RecordQuery(tenantID, *record)
+	}
+}
+
+// setQueryHistory sets the record of the log and metric queries in the query history, with the statistics of their
+// response if they succeeded.
+func setQueryHistory(ctx context.Context, req queryrangebase.Request, duration time.Duration, statistics *stats.Result) {
+	data, ok := ctx.Value(ctxKey).(*queryData)
+	if !ok {
+		return
+	}
+
+	record := &loghttp.QueryHistoryRecord{
+		Query:    req.GetQuery(),
+		Start:    req.GetStart(),
+		End:      req.GetEnd(),
+		Duration: duration,
+	}
+	switch r := req.(type) {
+	case *LokiRequest:
+		record.Type = string(logql.RangeType)
+		record.Step = time.Duration(r.Step) * time.Millisecond
+		record.Limit = r.Limit
+		record.Direction = r.Direction.String()
+	case *LokiInstantRequest:
+		record.Type = string(logql.InstantType)
+		record.Limit = r.Limit
+		record.Direction = r.Direction.String()
+	default:
+		return
+	}
+	if statistics != nil {
+		record.Duration = time.Duration(statistics.Summary.ExecTime * float64(time.Second))
+		record.BytesProcessed = statistics.Summary.TotalBytesProcessed
+		record.LinesProcessed = statistics.Summary.TotalLinesProcessed
+	}
+	data.history = record
+}
+
 // StatsCollectorMiddleware compute the stats summary based on the actual duration of the request and inject it in the request context.
 func StatsCollectorMiddleware() queryrangebase.Middleware {
 	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
@@ -131,6 +199,7 @@ func StatsCollectorMiddleware() queryrangebase.Middleware {
 			// execute the request
 			resp, err := next.Do(statsCtx, req)
 			if err != nil {
+				setQueryHistory(ctx, req, time.Since(start), nil)
 				return resp, err
 			}
 
@@ -199,6 +268,7 @@ func StatsCollectorMiddleware() queryrangebase.Middleware {
 					logger.Span.LogKV(responseStats.KVList()...)
 				}
 			}
+			setQueryHistory(ctx, req, time.Since(start), responseStats)
 			ctxValue := ctx.Value(ctxKey)
 			if data, ok := ctxValue.(*queryData); ok {
 				data.recorded = true
@@ -10,8 +10,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/user"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/v3/pkg/loghttp"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
@@ -183,7 +185,77 @@ func Test_StatsHTTP(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			statsHTTPMiddleware(metricRecorderFn(func(data *queryData) {
 				test.expect(t, data)
-			})).Wrap(test.next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/foo", strings.NewReader("")))
+			}), nil).Wrap(test.next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/foo", strings.NewReader("")))
+		})
+	}
+}
+
+type historyRecorder map[string][]loghttp.QueryHistoryRecord
+
+func (h historyRecorder) RecordQuery(tenantID string, record loghttp.QueryHistoryRecord) {
+	h[tenantID] = append(h[tenantID], record)
+}
+
+func TestStatsHTTPMiddleware_QueryHistory(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		name   string
+		req    queryrangebase.Request
+		resp   queryrangebase.Response
+		err    error
+		expect []loghttp.QueryHistoryRecord
+	}{
+		{
+			name: "range query",
+			req:  &LokiRequest{Query: `{app="foo"}`, StartTs: now.Add(-time.Hour), EndTs: now, Step: 1000, Limit: 100, Direction: logproto.BACKWARD},
+			resp: &LokiResponse{Statistics: stats.Result{Querier: stats.Querier{Store: stats.Store{Chunk: stats.Chunk{DecompressedBytes: 10, DecompressedLines: 2}}}}},
+			expect: []loghttp.QueryHistoryRecord{
+				{Query: `{app="foo"}`, Type: "range", Start: now.Add(-time.Hour), End: now, Step: time.Second, Limit: 100, Direction: "BACKWARD", BytesProcessed: 10, LinesProcessed: 2, Status: "200", UserAgent: "logcli"},
+			},
+		},
+		{
+			name: "failed instant query",
+			req:  &LokiInstantRequest{Query: `count_over_time({app="foo"}[1m])`, TimeTs: now, Direction: logproto.FORWARD},
+			err:  errors.New("failed"),
+			expect: []loghttp.QueryHistoryRecord{
+				{Query: `count_over_time({app="foo"}[1m])`, Type: "instant", Start: now, End: now, Direction: "FORWARD", Status: "500", UserAgent: "logcli"},
+			},
+		},
+		{
+			name: "series query",
+			req:  &LokiSeriesRequest{Match: []string{`{app="foo"}`}, StartTs: now.Add(-time.Hour), EndTs: now},
+			resp: &LokiSeriesResponse{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			history := historyRecorder{}
+			handler := statsHTTPMiddleware(metricRecorderFn(func(_ *queryData) {}), history).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := StatsCollectorMiddleware().Wrap(queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+					return test.resp, test.err
+				})).Do(r.Context(), test.req)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+
+			req := httptest.NewRequest("GET", "/loki/api/v1/query_range", nil)
+			req.Header.Set("User-Agent", "logcli")
+			handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(user.InjectOrgID(req.Context(), "a|b")))
+
+			if test.expect == nil {
+				require.Empty(t, history)
+				return
+			}
+			for _, tenantID := range []string{"a", "b"} {
+				records := history[tenantID]
+				require.Len(t, records, len(test.expect))
+				for i := range records {
+					require.WithinDuration(t, time.Now(), records[i].Timestamp, time.Minute)
+					records[i].Timestamp = time.Time{}
+					records[i].Duration = 0
+				}
+				require.Equal(t, test.expect, records)
+			}
 		})
 	}
 }
@@ -129,6 +129,9 @@ func (sp *schedulerProcessor) querierLoop(c schedulerpb.SchedulerForQuerier_Quer
 		start := time.Now()
 		request, err := c.Recv()
 		if err != nil {
+			// The scheduler closes the stream to cancel the inflight query, e.g. when the query is canceled
+			// in the frontend, so the query is canceled with the reason the stream was closed.
+			cancel(fmt.Errorf("query canceled by the scheduler: %w", err))
 			return err
 		}
 
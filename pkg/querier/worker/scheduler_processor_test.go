@@ -103,6 +103,60 @@ func TestSchedulerProcessor_processQueriesOnSingleStream(t *testing.T) {
 		loopClient.AssertCalled(t, "Send", &schedulerpb.QuerierToScheduler{QuerierID: "test-querier-id"})
 	})
 
+	t.Run("should cancel the inflight query when the query-scheduler closes the stream", func(t *testing.T) {
+		sp, loopClient, requestHandler := prepareSchedulerProcessor()
+
+		workerCtx, workerCancel := context.WithCancel(context.Background())
+		defer workerCancel()
+
+		var (
+			recvCount    = atomic.NewInt64(0)
+			queryStarted = make(chan struct{})
+			queryCause   = make(chan error, 1)
+		)
+
+		loopClient.On("Recv").Return(func() (*schedulerpb.SchedulerToQuerier, error) {
+			switch recvCount.Inc() {
+			case 1:
+				return &schedulerpb.SchedulerToQuerier{
+					QueryID: 1,
+					Request: &schedulerpb.SchedulerToQuerier_HttpRequest{
+						HttpRequest: &httpgrpc.HTTPRequest{
+							Method: "GET",
+							Url:    `/loki/api/v1/query_range?query={foo="bar"}&step=10&limit=200&direction=FORWARD`,
+						},
+					},
+					FrontendAddress: "127.0.0.2",
+					UserID:          "user-1",
+				}, nil
+			case 2:
+				// The scheduler closes the stream once the query is canceled in the frontend.
+				<-queryStarted
+				return nil, status.Error(codes.Canceled, context.Canceled.Error())
+			default:
+				workerCancel()
+				<-loopClient.Context().Done()
+				return nil, loopClient.Context().Err()
+			}
+		})
+
+		requestHandler.On("Do", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			close(queryStarted)
+			<-ctx.Done()
+			queryCause <- context.Cause(ctx)
+		}).Return(&queryrange.LokiResponse{}, nil)
+
+		sp.processQueriesOnSingleStream(workerCtx, nil, "127.0.0.1", "1")
+
+		select {
+		case cause := <-queryCause:
+			require.ErrorContains(t, cause, "query canceled by the scheduler")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the inflight query was not canceled")
+		}
+	})
+
 	t.Run("should not log an error when the query-scheduler is terminates while waiting for the next query to run", func(t *testing.T) {
 		sp, loopClient, requestHandler := prepareSchedulerProcessor()
 
@@ -130,6 +130,36 @@ func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, succes
 	}
 }
 
+// Remove removes the requests of the tenant enqueued with the given path and matching the predicate from the queue,
+// for example the requests canceled while they were queued, so they don't count against the maximum number of
+// outstanding requests. Only the requests of the sub-queue at the path are scanned.
+// It returns the number of removed requests.
+func (q *RequestQueue) Remove(tenant string, path []string, match func(Request) bool) int {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	queue := q.queues.mapping.GetByKey(tenant)
+	if queue == nil {
+		return 0
+	}
+
+	removed := queue.remove(path, match)
+	if removed == 0 {
+		return 0
+	}
+	if queue.Len() == 0 {
+		q.queues.deleteQueue(tenant)
+	}
+	for i := 0; i < removed; i++ {
+		q.queues.perUserQueueLen.Dec(tenant)
+	}
+	q.metrics.queueLength.WithLabelValues(tenant).Sub(float64(removed))
+
+	// Tell close() we've removed requests.
+	q.cond.Broadcast()
+	return removed
+}
+
 // ReleaseRequests returns items back to the slice pool.
 // Must only be called in combination with DequeueMany().
 func (q *RequestQueue) ReleaseRequests(items []Request) {
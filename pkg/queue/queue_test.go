@@ -336,6 +336,40 @@ func TestMaxQueueSize(t *testing.T) {
 	})
 }
 
+func TestRequestQueue_Remove(t *testing.T) {
+	maxSize := 3
+	queue := NewRequestQueue(maxSize, 0, noQueueLimits, NewMetrics(nil, constants.Loki, "query_scheduler"))
+	queue.RegisterConsumerConnection("querier")
+
+	assert.NoError(t, queue.Enqueue("tenant", nil, 1, nil))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, 2, nil))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"user-b"}, 3, nil))
+	assert.Equal(t, ErrTooManyRequests, queue.Enqueue("tenant", []string{"user-a"}, 4, nil))
+
+	// removed requests no longer count against the queue size of the tenant.
+	// only the requests enqueued with the given path are removed.
+	isEven := func(r Request) bool { return r.(int)%2 == 0 }
+	assert.Equal(t, 0, queue.Remove("tenant", nil, isEven))
+	assert.Equal(t, 0, queue.Remove("tenant", []string{"user-b"}, isEven))
+	assert.Equal(t, 1, queue.Remove("tenant", []string{"user-a"}, isEven))
+	assert.Equal(t, 0, queue.Remove("tenant", []string{"user-a"}, isEven))
+	assert.Equal(t, 0, queue.Remove("other", []string{"user-a"}, isEven))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, 5, nil))
+
+	var dequeued []Request
+	for i := 0; i < 3; i++ {
+		r, _, err := queue.Dequeue(context.Background(), StartIndexWithLocalQueue, "querier")
+		assert.NoError(t, err)
+		dequeued = append(dequeued, r)
+	}
+	assert.ElementsMatch(t, []Request{1, 3, 5}, dequeued)
+
+	// the queue of the tenant is deleted once all its requests are removed.
+	assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, 6, nil))
+	assert.Equal(t, 1, queue.Remove("tenant", []string{"user-a"}, isEven))
+	assert.True(t, queue.queues.hasNoTenantQueues())
+}
+
 type mockLimits struct {
 	maxConsumer int
 }
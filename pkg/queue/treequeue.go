@@ -110,6 +110,34 @@ func (q *TreeQueue) Dequeue() Request {
 	return nil
 }
 
+// remove removes the requests matching the predicate from the local queue of the sub-queue at the given path,
+// keeping the order of the remaining requests. Sub-queues left empty along the path are removed.
+// It returns the number of removed requests.
+func (q *TreeQueue) remove(path QueuePath, match func(Request) bool) int {
+	if len(path) > 0 {
+		subq := q.mapping.GetByKey(path[0])
+		if subq == nil {
+			return 0
+		}
+		removed := subq.remove(path[1:], match)
+		if removed > 0 && subq.Len() == 0 {
+			q.mapping.Remove(subq.name)
+		}
+		return removed
+	}
+
+	removed := 0
+	for n := len(q.ch); n > 0; n-- {
+		item := <-q.ch
+		if match(item) {
+			removed++
+			continue
+		}
+		q.ch <- item
+	}
+	return removed
+}
+
 // Name implements Queue
 func (q *TreeQueue) Name() string {
 	return q.name
@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/dskit/tenant"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/ruler/rulespb"
 	"github.com/grafana/loki/v3/pkg/ruler/rulestore"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
@@ -319,6 +320,10 @@ var (
 	ErrNoRuleGroups = errors.New("no rule groups found")
 	// ErrBadRuleGroup is returned when the provided rule group can not be unmarshalled
 	ErrBadRuleGroup = errors.New("unable to decoded rule group")
+	// ErrNoMacroName signals the request did not include a macro name
+	ErrNoMacroName = errors.New("a macro name must be provided")
+	// ErrMacrosNotSupported is returned when the rule store can't store pipeline macros
+	ErrMacrosNotSupported = errors.New("pipeline macros are not supported by the configured rule storage")
 )
 
 func marshalAndSend(output interface{}, w http.ResponseWriter, logger log.Logger) {
@@ -666,3 +671,132 @@ func (a *API) DeleteRuleGroup(w http.ResponseWriter, req *http.Request) {
 
 	respondAccepted(w, logger)
 }
+
+// Macro is a LogQL pipeline macro, referenced in queries with `| @name`.
+type Macro struct {
+	Name       string `yaml:"name"`
+	Definition string `yaml:"definition"`
+}
+
+// parseMacroRequest returns the user and the name of the macro of the request, and the macro store.
+func (a *API) parseMacroRequest(w http.ResponseWriter, req *http.Request, requireName bool) (string, string, rulestore.MacroStore, bool) {
+	logger := util_log.WithContext(req.Context(), a.logger)
+
+	store, ok := a.store.(rulestore.MacroStore)
+	if !ok {
+		http.Error(w, ErrMacrosNotSupported.Error(), http.StatusNotImplemented)
+		return "", "", nil, false
+	}
+
+	userID, err := tenant.TenantID(req.Context())
+	if err != nil {
+		respondServerError(logger, w, user.ErrNoOrgID.Error())
+		return "", "", nil, false
+	}
+
+	name, exists := mux.Vars(req)["name"]
+	if !exists && requireName {
+		http.Error(w, ErrNoMacroName.Error(), http.StatusBadRequest)
+		return "", "", nil, false
+	}
+	return userID, name, store, true
+}
+
+// ListMacros returns the pipeline macros of the user, sorted by name.
+func (a *API) ListMacros(w http.ResponseWriter, req *http.Request) {
+	logger := util_log.WithContext(req.Context(), a.logger)
+	userID, _, store, ok := a.parseMacroRequest(w, req, false)
+	if !ok {
+		return
+	}
+
+	definitions, err := store.ListMacros(req.Context(), userID)
+	if err != nil {
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	macros := make([]Macro, 0, len(definitions))
+	for name, definition := range definitions {
+		macros = append(macros, Macro{Name: name, Definition: definition})
+	}
+	sort.Slice(macros, func(i, j int) bool { return macros[i].Name < macros[j].Name })
+	marshalAndSend(macros, w, logger)
+}
+
+func (a *API) GetMacro(w http.ResponseWriter, req *http.Request) {
+	logger := util_log.WithContext(req.Context(), a.logger)
+	userID, name, store, ok := a.parseMacroRequest(w, req, true)
+	if !ok {
+		return
+	}
+
+	definition, err := store.GetMacro(req.Context(), userID, name)
+	if err != nil {
+		if errors.Is(err, rulestore.ErrMacroNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	marshalAndSend(Macro{Name: name, Definition: definition}, w, logger)
+}
+
+// CreateMacro creates or replaces a pipeline macro, the body of the request is the LogQL pipeline of the macro.
+func (a *API) CreateMacro(w http.ResponseWriter, req *http.Request) {
+	logger := util_log.WithContext(req.Context(), a.logger)
+	userID, name, store, ok := a.parseMacroRequest(w, req, true)
+	if !ok {
+		return
+	}
+	logger = log.With(logger, "userID", userID, "macro", name)
+
+	if err := syntax.ValidateMacroName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payload, err := io.ReadAll(req.Body)
+	if err != nil {
+		level.Error(logger).Log("msg", "unable to read macro payload", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	definition := strings.TrimSpace(string(payload))
+	if _, err := syntax.ParseMacro(definition); err != nil {
+		level.Debug(logger).Log("msg", "unable to validate macro payload", "err", err.Error())
+		http.Error(w, fmt.Sprintf("invalid macro definition: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err := store.SetMacro(req.Context(), userID, name, definition); err != nil {
+		level.Error(logger).Log("msg", "unable to store macro", "err", err.Error())
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	level.Info(logger).Log("msg", "stored macro", "definition", definition)
+	respondAccepted(w, logger)
+}
+
+func (a *API) DeleteMacro(w http.ResponseWriter, req *http.Request) {
+	logger := util_log.WithContext(req.Context(), a.logger)
+	userID, name, store, ok := a.parseMacroRequest(w, req, true)
+	if !ok {
+		return
+	}
+
+	if err := store.DeleteMacro(req.Context(), userID, name); err != nil {
+		if errors.Is(err, rulestore.ErrMacroNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	respondAccepted(w, logger)
+}
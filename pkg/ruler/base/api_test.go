@@ -25,6 +25,8 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/loki/v3/pkg/ruler/rulespb"
+	"github.com/grafana/loki/v3/pkg/ruler/rulestore/objectclient"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/testutils"
 )
 
 func TestRuler_PrometheusRules(t *testing.T) {
@@ -1004,6 +1006,56 @@ rules:
 	}
 }
 
+func TestRuler_Macros(t *testing.T) {
+	cfg := defaultRulerConfig(t, newMockRuleStore(make(map[string]rulespb.RuleGroupList)))
+
+	r := newTestRuler(t, cfg)
+	defer services.StopAndAwaitTerminated(context.Background(), r) //nolint:errcheck
+
+	a := NewAPI(r, objectclient.NewRuleStore(testutils.NewInMemoryObjectClient(), 1, log.NewNopLogger()), log.NewNopLogger())
+
+	router := mux.NewRouter()
+	router.Path("/loki/api/v1/macros").Methods(http.MethodGet).HandlerFunc(a.ListMacros)
+	router.Path("/loki/api/v1/macros/{name}").Methods(http.MethodGet).HandlerFunc(a.GetMacro)
+	router.Path("/loki/api/v1/macros/{name}").Methods(http.MethodPost).HandlerFunc(a.CreateMacro)
+	router.Path("/loki/api/v1/macros/{name}").Methods(http.MethodDelete).HandlerFunc(a.DeleteMacro)
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		output string
+	}{
+		{"create", http.MethodPost, "/loki/api/v1/macros/nginx_parse", `| pattern "<ip> <_>"` + "\n", http.StatusAccepted, `{"status":"success","data":null,"errorType":"","error":""}`},
+		{"create another", http.MethodPost, "/loki/api/v1/macros/errors", `|= "error"`, http.StatusAccepted, `{"status":"success","data":null,"errorType":"","error":""}`},
+		{"invalid name", http.MethodPost, "/loki/api/v1/macros/nginx-parse", `| json`, http.StatusBadRequest, "invalid macro name \"nginx-parse\": must start with a letter or an underscore followed by letters, digits or underscores\n"},
+		{"invalid definition", http.MethodPost, "/loki/api/v1/macros/nested", `| json | @errors`, http.StatusBadRequest, "invalid macro definition: macro definition cannot reference macro @errors\n"},
+		{"get", http.MethodGet, "/loki/api/v1/macros/nginx_parse", "", http.StatusOK, "name: nginx_parse\ndefinition: '| pattern \"<ip> <_>\"'\n"},
+		{"list", http.MethodGet, "/loki/api/v1/macros", "", http.StatusOK, "- name: errors\n  definition: '|= \"error\"'\n- name: nginx_parse\n  definition: '| pattern \"<ip> <_>\"'\n"},
+		{"delete", http.MethodDelete, "/loki/api/v1/macros/errors", "", http.StatusAccepted, `{"status":"success","data":null,"errorType":"","error":""}`},
+		{"get deleted", http.MethodGet, "/loki/api/v1/macros/errors", "", http.StatusNotFound, "macro does not exist\n"},
+		{"delete deleted", http.MethodDelete, "/loki/api/v1/macros/errors", "", http.StatusNotFound, "macro does not exist\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := requestFor(t, tc.method, "https://localhost:8080"+tc.path, strings.NewReader(tc.body), "user1")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, tc.status, w.Code)
+			require.Equal(t, tc.output, w.Body.String())
+		})
+	}
+
+	t.Run("unsupported store", func(t *testing.T) {
+		a := NewAPI(r, r.store, log.NewNopLogger())
+		req := requestFor(t, http.MethodGet, "https://localhost:8080/loki/api/v1/macros", nil, "user1")
+		w := httptest.NewRecorder()
+		a.ListMacros(w, req)
+		require.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+}
+
 func requestFor(t *testing.T, method string, url string, body io.Reader, userID string) *http.Request {
 	t.Helper()
 
@@ -0,0 +1,43 @@
+package ruler
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/logql/macros"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+// EvaluatorWithMacros wraps a given Evaluator and expands the pipeline macros of the tenant in the rule's query
+// before evaluating it.
+type EvaluatorWithMacros struct {
+	inner    Evaluator
+	resolver *macros.Resolver
+}
+
+func NewEvaluatorWithMacros(inner Evaluator, resolver *macros.Resolver) Evaluator {
+	if resolver == nil {
+		return inner
+	}
+
+	return &EvaluatorWithMacros{
+		inner:    inner,
+		resolver: resolver,
+	}
+}
+
+func (e *EvaluatorWithMacros) Eval(ctx context.Context, qs string, now time.Time) (*logqlmodel.Result, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := e.resolver.Expand(ctx, tenantID, qs)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.inner.Eval(ctx, expanded, now)
+}
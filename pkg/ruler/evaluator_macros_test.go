@@ -0,0 +1,60 @@
+package ruler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/macros"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/ruler/rulestore"
+)
+
+type queryRecorder struct {
+	queries []string
+}
+
+func (q *queryRecorder) Eval(_ context.Context, qs string, _ time.Time) (*logqlmodel.Result, error) {
+	q.queries = append(q.queries, qs)
+	return nil, nil
+}
+
+type mockMacroStore map[string]map[string]string
+
+func (m mockMacroStore) ListMacros(_ context.Context, userID string) (map[string]string, error) {
+	return m[userID], nil
+}
+func (m mockMacroStore) GetMacro(context.Context, string, string) (string, error) {
+	return "", rulestore.ErrMacroNotFound
+}
+func (m mockMacroStore) SetMacro(context.Context, string, string, string) error { return nil }
+func (m mockMacroStore) DeleteMacro(context.Context, string, string) error      { return nil }
+
+func TestEvaluationWithMacros(t *testing.T) {
+	store := mockMacroStore{
+		"user1": {"errors": `| logfmt | level="error"`},
+	}
+	inner := &queryRecorder{}
+	eval := NewEvaluatorWithMacros(inner, macros.NewResolver(store, time.Minute))
+
+	ctx := user.InjectOrgID(context.Background(), "user1")
+	_, err := eval.Eval(ctx, `count_over_time({app="foo"} | @errors [5m]) > 0`, time.Now())
+	require.NoError(t, err)
+	_, err = eval.Eval(ctx, `count_over_time({app="foo"}[5m])`, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`(count_over_time({app="foo"} | logfmt | level="error"[5m]) > 0)`,
+		`count_over_time({app="foo"}[5m])`,
+	}, inner.queries)
+
+	_, err = eval.Eval(user.InjectOrgID(context.Background(), "user2"), `count_over_time({app="foo"} | @errors [5m])`, time.Now())
+	require.True(t, errors.Is(err, logqlmodel.ErrParse))
+	require.EqualError(t, err, "parse error : unknown pipeline macro @errors")
+
+	_, err = eval.Eval(context.Background(), `count_over_time({app="foo"}[5m])`, time.Now())
+	require.Error(t, err)
+}
@@ -301,11 +301,15 @@ func (r *RemoteEvaluator) decodeResponse(ctx context.Context, resp *httpgrpc.HTT
 		vec := decoded.Data.Result.(loghttp.Vector)
 
 		for _, s := range vec {
-			res = append(res, promql.Sample{
+			sample := promql.Sample{
 				Metric: metricToLabels(s.Metric),
 				F:      float64(s.Value),
 				T:      int64(s.Timestamp),
-			})
+			}
+			if s.Histogram != nil {
+				sample.H = logqlmodel.NewFloatHistogram(s.Histogram)
+			}
+			res = append(res, sample)
 		}
 
 		instrument.ObserveWithExemplar(ctx, r.metrics.responseSizeSamples.WithLabelValues(orgID), float64(len(res)))
@@ -24,15 +24,23 @@ import (
 // Object Name: "rules/<user_id>/<base64 URL Encoded: namespace>/<base64 URL Encoded: group_name>"
 // Storage Format: Encoded RuleGroupDesc
 //
+// Object Macro Storage Schema
+// =======================
+// Object Name: "macros/<user_id>/<macro_name>"
+// Storage Format: LogQL pipeline of the macro
+//
 // Prometheus Rule Groups can include a large number of characters that are not valid object names
 // in common object storage systems. A URL Base64 encoding allows for generic consistent naming
 // across all backends
 
 const (
-	delim      = "/"
-	rulePrefix = "rules" + delim
+	delim       = "/"
+	rulePrefix  = "rules" + delim
+	macroPrefix = "macros" + delim
 )
 
+var _ rulestore.MacroStore = &RuleStore{}
+
 // RuleStore allows cortex rules to be stored using an object store backend.
 type RuleStore struct {
 	client          client.ObjectClient
@@ -247,6 +255,70 @@ func (o *RuleStore) DeleteNamespace(ctx context.Context, userID, namespace strin
 	return nil
 }
 
+// ListMacros implements rulestore.MacroStore.
+func (o *RuleStore) ListMacros(ctx context.Context, userID string) (map[string]string, error) {
+	prefix := generateMacroObjectKey(userID, "")
+	macroObjects, _, err := o.client.List(ctx, prefix, delim)
+	if err != nil {
+		return nil, err
+	}
+
+	macros := make(map[string]string, len(macroObjects))
+	for _, obj := range macroObjects {
+		name := strings.TrimPrefix(obj.Key, prefix)
+		definition, err := o.getMacro(ctx, obj.Key)
+		if errors.Is(err, rulestore.ErrMacroNotFound) {
+			// deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		macros[name] = definition
+	}
+	return macros, nil
+}
+
+// GetMacro implements rulestore.MacroStore.
+func (o *RuleStore) GetMacro(ctx context.Context, userID, name string) (string, error) {
+	return o.getMacro(ctx, generateMacroObjectKey(userID, name))
+}
+
+func (o *RuleStore) getMacro(ctx context.Context, objectKey string) (string, error) {
+	reader, _, err := o.client.GetObject(ctx, objectKey)
+	if err != nil {
+		if o.client.IsObjectNotFoundErr(err) {
+			return "", rulestore.ErrMacroNotFound
+		}
+		return "", errors.Wrapf(err, "failed to get macro %s", objectKey)
+	}
+	defer func() { _ = reader.Close() }()
+
+	buf, err := io.ReadAll(reader)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read macro %s", objectKey)
+	}
+	return string(buf), nil
+}
+
+// SetMacro implements rulestore.MacroStore.
+func (o *RuleStore) SetMacro(ctx context.Context, userID, name, definition string) error {
+	return o.client.PutObject(ctx, generateMacroObjectKey(userID, name), strings.NewReader(definition))
+}
+
+// DeleteMacro implements rulestore.MacroStore.
+func (o *RuleStore) DeleteMacro(ctx context.Context, userID, name string) error {
+	err := o.client.DeleteObject(ctx, generateMacroObjectKey(userID, name))
+	if o.client.IsObjectNotFoundErr(err) {
+		return rulestore.ErrMacroNotFound
+	}
+	return err
+}
+
+func generateMacroObjectKey(userID, name string) string {
+	return macroPrefix + userID + delim + name
+}
+
 func generateRuleObjectKey(userID, namespace, groupName string) string {
 	if userID == "" {
 		return rulePrefix
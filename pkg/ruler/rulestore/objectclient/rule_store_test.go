@@ -1,4 +1,60 @@
 package objectclient
 
-// This is an empty placeholder test file to signal that the tests for RuleStore are in:
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/ruler/rulespb"
+	"github.com/grafana/loki/v3/pkg/ruler/rulestore"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/client/testutils"
+)
+
+// The tests for the rule groups of RuleStore are in:
 // pkg/ruler/rulestore/bucketclient/bucket_client_test.go
+
+func TestMacros(t *testing.T) {
+	ctx := context.Background()
+	client := testutils.NewInMemoryObjectClient()
+	store := NewRuleStore(client, 5, log.NewNopLogger())
+
+	// a rule group of the same user to make sure macros are stored separately.
+	require.NoError(t, store.SetRuleGroup(ctx, "user1", "ns", rulespb.ToProto("user1", "ns", rulefmt.RuleGroup{Name: "group"})))
+	require.NoError(t, store.SetMacro(ctx, "user1", "nginx_parse", `| pattern "<ip> <_>"`))
+	require.NoError(t, store.SetMacro(ctx, "user1", "errors", `|= "error"`))
+	require.NoError(t, store.SetMacro(ctx, "user2", "errors", `| json | level="error"`))
+
+	// the macros are stored as macros/<user>/<name>.
+	for _, key := range []string{"macros/user1/nginx_parse", "macros/user1/errors", "macros/user2/errors"} {
+		require.Contains(t, client.Internals(), key)
+	}
+
+	macros, err := store.ListMacros(ctx, "user1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"nginx_parse": `| pattern "<ip> <_>"`, "errors": `|= "error"`}, macros)
+
+	users, err := store.ListAllUsers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user1"}, users)
+
+	definition, err := store.GetMacro(ctx, "user2", "errors")
+	require.NoError(t, err)
+	require.Equal(t, `| json | level="error"`, definition)
+
+	require.NoError(t, store.SetMacro(ctx, "user2", "errors", `|= "err"`))
+	definition, err = store.GetMacro(ctx, "user2", "errors")
+	require.NoError(t, err)
+	require.Equal(t, `|= "err"`, definition)
+
+	require.NoError(t, store.DeleteMacro(ctx, "user1", "errors"))
+	_, err = store.GetMacro(ctx, "user1", "errors")
+	require.ErrorIs(t, err, rulestore.ErrMacroNotFound)
+	require.ErrorIs(t, store.DeleteMacro(ctx, "user1", "errors"), rulestore.ErrMacroNotFound)
+
+	macros, err = store.ListMacros(ctx, "user3")
+	require.NoError(t, err)
+	require.Empty(t, macros)
+}
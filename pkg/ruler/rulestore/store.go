@@ -14,6 +14,8 @@ var (
 	ErrGroupNamespaceNotFound = errors.New("group namespace does not exist")
 	// ErrUserNotFound is returned if the user does not currently exist
 	ErrUserNotFound = errors.New("no rule groups found for user")
+	// ErrMacroNotFound is returned if a pipeline macro does not exist
+	ErrMacroNotFound = errors.New("macro does not exist")
 )
 
 // RuleStore is used to store and retrieve rules.
@@ -45,3 +47,16 @@ type RuleStore interface {
 	// If namespace is empty, deletes all rule groups for user.
 	DeleteNamespace(ctx context.Context, userID, namespace string) error
 }
+
+// MacroStore is used to store and retrieve the LogQL pipeline macros of a user.
+// It is implemented by the rule stores able to store macros alongside the rule groups.
+type MacroStore interface {
+	// ListMacros returns the definitions of all the macros of a user, by name.
+	ListMacros(ctx context.Context, userID string) (map[string]string, error)
+
+	GetMacro(ctx context.Context, userID, name string) (string, error)
+	SetMacro(ctx context.Context, userID, name, definition string) error
+
+	// DeleteMacro deletes a single macro, ErrMacroNotFound is returned if it doesn't exist.
+	DeleteMacro(ctx context.Context, userID, name string) error
+}
@@ -91,6 +91,12 @@ func (r walReplayer) replayWAL(reader *wlog.Reader) error {
 				return err
 			}
 			r.w.AppendExemplars(exemplars)
+		case record.FloatHistogramSamples:
+			histograms, err := dec.FloatHistogramSamples(rec, nil)
+			if err != nil {
+				return err
+			}
+			r.w.AppendFloatHistograms(histograms)
 		}
 	}
 
@@ -102,6 +108,8 @@ type walDataCollector struct {
 	samples   []record.RefSample
 	series    []record.RefSeries
 	exemplars []record.RefExemplar
+
+	floatHistograms []record.RefFloatHistogramSample
 }
 
 func (c *walDataCollector) AppendExemplars(exemplars []record.RefExemplar) bool {
@@ -132,8 +140,11 @@ func (c *walDataCollector) AppendHistograms(_ []record.RefHistogramSample) bool
 	return true
 }
 
-func (c *walDataCollector) AppendFloatHistograms(_ []record.RefFloatHistogramSample) bool {
-	// TODO: support native histograms
+func (c *walDataCollector) AppendFloatHistograms(histograms []record.RefFloatHistogramSample) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.floatHistograms = append(c.floatHistograms, histograms...)
 	return true
 }
 
@@ -205,6 +205,11 @@ func (w *Storage) loadWAL(r *wlog.Reader) (err error) {
 				return []record.RefSample{}
 			},
 		}
+		floatHistogramsPool = sync.Pool{
+			New: func() interface{} {
+				return []record.RefFloatHistogramSample{}
+			},
+		}
 	)
 
 	go func() {
@@ -235,6 +240,17 @@ func (w *Storage) loadWAL(r *wlog.Reader) (err error) {
 					}
 				}
 				decoded <- samples
+			case record.FloatHistogramSamples:
+				histograms := floatHistogramsPool.Get().([]record.RefFloatHistogramSample)[:0]
+				histograms, err = dec.FloatHistogramSamples(rec, histograms)
+				if err != nil {
+					errCh <- &wlog.CorruptionErr{
+						Err:     errors.Wrap(err, "decode float histograms"),
+						Segment: r.Segment(),
+						Offset:  r.Offset(),
+					}
+				}
+				decoded <- histograms
 			case record.Tombstones, record.Exemplars:
 				// We don't care about decoding tombstones or exemplars
 				continue
@@ -292,6 +308,23 @@ func (w *Storage) loadWAL(r *wlog.Reader) (err error) {
 
 			//nolint:staticcheck
 			samplesPool.Put(v)
+		case []record.RefFloatHistogramSample:
+			for _, h := range v {
+				series := w.series.getByID(h.Ref)
+				if series == nil {
+					level.Warn(w.logger).Log("msg", "found histogram referencing non-existing series, skipping")
+					continue
+				}
+
+				series.Lock()
+				if h.T > series.lastTs {
+					series.lastTs = h.T
+				}
+				series.Unlock()
+			}
+
+			//nolint:staticcheck
+			floatHistogramsPool.Put(v)
 		default:
 			panic(fmt.Errorf("unexpected decoded type: %T", d))
 		}
@@ -546,26 +579,45 @@ func dirSize(path string) (int64, error) {
 type appender struct {
 	w *Storage
 	// Notify the underlying storage that some sample is written
-	notify    func()
-	series    []record.RefSeries
-	samples   []record.RefSample
-	exemplars []record.RefExemplar
+	notify          func()
+	series          []record.RefSeries
+	samples         []record.RefSample
+	floatHistograms []record.RefFloatHistogramSample
+	exemplars       []record.RefExemplar
 }
 
 var _ storage.Appender = (*appender)(nil)
 
 func (a *appender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	series, err := a.getOrCreateSeries(ref, l, t)
+	if err != nil {
+		return 0, err
+	}
+
+	a.samples = append(a.samples, record.RefSample{
+		Ref: series.ref,
+		T:   t,
+		V:   v,
+	})
+
+	a.w.metrics.TotalAppendedSamples.Inc()
+	return storage.SeriesRef(series.ref), nil
+}
+
+// getOrCreateSeries returns the series of the reference or the labels, creating it if needed,
+// and updates its last recorded timestamp.
+func (a *appender) getOrCreateSeries(ref storage.SeriesRef, l labels.Labels, t int64) (*memSeries, error) {
 	series := a.w.series.getByID(chunks.HeadSeriesRef(ref))
 	if series == nil {
 		// Ensure no empty or duplicate labels have gotten through. This mirrors the
 		// equivalent validation code in the TSDB's headAppender.
 		l = l.WithoutEmpty()
 		if len(l) == 0 {
-			return 0, errors.Wrap(tsdb.ErrInvalidSample, "empty labelset")
+			return nil, errors.Wrap(tsdb.ErrInvalidSample, "empty labelset")
 		}
 
 		if lbl, dup := l.HasDuplicateLabelNames(); dup {
-			return 0, errors.Wrap(tsdb.ErrInvalidSample, fmt.Sprintf(`label name "%s" is not unique`, lbl))
+			return nil, errors.Wrap(tsdb.ErrInvalidSample, fmt.Sprintf(`label name "%s" is not unique`, lbl))
 		}
 
 		var created bool
@@ -587,15 +639,7 @@ func (a *appender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v flo
 	// Update last recorded timestamp. Used by Storage.gc to determine if a
 	// series is stale.
 	series.updateTs(t)
-
-	a.samples = append(a.samples, record.RefSample{
-		Ref: series.ref,
-		T:   t,
-		V:   v,
-	})
-
-	a.w.metrics.TotalAppendedSamples.Inc()
-	return storage.SeriesRef(series.ref), nil
+	return series, nil
 }
 
 func (a *appender) getOrCreate(l labels.Labels) (series *memSeries, created bool) {
@@ -650,9 +694,31 @@ func (a *appender) UpdateMetadata(_ storage.SeriesRef, _ labels.Labels, _ metada
 	return 0, nil
 }
 
-func (a *appender) AppendHistogram(_ storage.SeriesRef, _ labels.Labels, _ int64, _ *histogram.Histogram, _ *histogram.FloatHistogram) (storage.SeriesRef, error) {
-	// TODO: support native histograms
-	return 0, nil
+// AppendHistogram appends float native histograms, the only kind of histograms produced by LogQL.
+func (a *appender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	if fh == nil {
+		if h == nil {
+			return 0, nil
+		}
+		fh = h.ToFloat(nil)
+	}
+	if err := fh.Validate(); err != nil {
+		return 0, err
+	}
+
+	series, err := a.getOrCreateSeries(ref, l, t)
+	if err != nil {
+		return 0, err
+	}
+
+	a.floatHistograms = append(a.floatHistograms, record.RefFloatHistogramSample{
+		Ref: series.ref,
+		T:   t,
+		FH:  fh,
+	})
+
+	a.w.metrics.TotalAppendedSamples.Inc()
+	return storage.SeriesRef(series.ref), nil
 }
 
 func (a *appender) AppendCTZeroSample(_ storage.SeriesRef, _ labels.Labels, _ int64, _ int64) (storage.SeriesRef, error) {
@@ -688,6 +754,14 @@ func (a *appender) Commit() error {
 		buf = buf[:0]
 	}
 
+	if len(a.floatHistograms) > 0 {
+		buf = encoder.FloatHistogramSamples(a.floatHistograms, buf)
+		if err := a.w.wal.Log(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
 	if len(a.exemplars) > 0 {
 		buf = encoder.Exemplars(a.exemplars, buf)
 		if err := a.w.wal.Log(buf); err != nil {
@@ -707,20 +781,28 @@ func (a *appender) Commit() error {
 	a.w.bufPool.Put(buf)
 
 	for _, sample := range a.samples {
-		series := a.w.series.getByID(sample.Ref)
-		if series != nil {
-			series.Lock()
-			series.pendingCommit = false
-			series.Unlock()
-		}
+		a.clearPendingCommit(sample.Ref)
+	}
+	for _, h := range a.floatHistograms {
+		a.clearPendingCommit(h.Ref)
 	}
 
 	return a.Rollback()
 }
 
+func (a *appender) clearPendingCommit(ref chunks.HeadSeriesRef) {
+	series := a.w.series.getByID(ref)
+	if series != nil {
+		series.Lock()
+		series.pendingCommit = false
+		series.Unlock()
+	}
+}
+
 func (a *appender) Rollback() error {
 	a.series = a.series[:0]
 	a.samples = a.samples[:0]
+	a.floatHistograms = a.floatHistograms[:0]
 	a.exemplars = a.exemplars[:0]
 	a.w.appenderPool.Put(a)
 	return nil
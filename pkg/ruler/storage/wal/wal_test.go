@@ -13,6 +13,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/storage"
@@ -107,6 +108,49 @@ func TestStorage(t *testing.T) {
 	require.Equal(t, expectedExemplars, actualExemplars)
 }
 
+func TestStorage_Histograms(t *testing.T) {
+	walDir := t.TempDir()
+
+	s, err := newTestStorage(walDir)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	fh := &histogram.FloatHistogram{
+		CounterResetHint: histogram.GaugeType,
+		Schema:           3,
+		Count:            3,
+		Sum:              4,
+		PositiveSpans:    []histogram.Span{{Offset: 0, Length: 1}, {Offset: 7, Length: 1}},
+		PositiveBuckets:  []float64{2, 1},
+	}
+	lbls := labels.FromMap(map[string]string{"__name__": "latency"})
+
+	app := s.Appender(context.Background())
+	ref, err := app.AppendHistogram(0, lbls, 10, nil, fh)
+	require.NoError(t, err)
+	_, err = app.AppendHistogram(ref, lbls, 20, nil, fh)
+	require.NoError(t, err)
+
+	invalid := fh.Copy()
+	invalid.PositiveBuckets = []float64{1}
+	_, err = app.AppendHistogram(ref, lbls, 30, nil, invalid)
+	require.Error(t, err, "should reject invalid histograms")
+
+	require.NoError(t, app.Commit())
+
+	collector := walDataCollector{}
+	replayer := walReplayer{w: &collector}
+	require.NoError(t, replayer.Replay(s.wal.Dir()))
+
+	require.Len(t, collector.series, 1)
+	require.Equal(t, []record.RefFloatHistogramSample{
+		{Ref: chunks.HeadSeriesRef(ref), T: 10, FH: fh},
+		{Ref: chunks.HeadSeriesRef(ref), T: 20, FH: fh},
+	}, collector.floatHistograms)
+}
+
 func TestStorage_ExistingWAL(t *testing.T) {
 	walDir := t.TempDir()
 
@@ -225,6 +225,9 @@ type schedulerRequest struct {
 	statsEnabled    bool
 
 	queueTime time.Time
+	queuePath []string
+	// dequeued is set once the request is dequeued by a querier, so canceling it doesn't scan the queue.
+	dequeued atomic.Bool
 
 	ctx       context.Context
 	ctxCancel context.CancelFunc
@@ -280,7 +283,7 @@ func (s *Scheduler) FrontendLoop(frontend schedulerpb.SchedulerForFrontend_Front
 			}
 
 		case schedulerpb.CANCEL:
-			s.cancelRequestAndRemoveFromPending(frontendAddress, msg.QueryID)
+			s.cancelRequest(frontendAddress, msg.QueryID)
 			resp = &schedulerpb.SchedulerToFrontend{Status: schedulerpb.OK}
 
 		default:
@@ -392,6 +395,8 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		}
 	}
 
+	req.queuePath = queuePath
+
 	s.activeUsers.UpdateUserTimestamp(req.tenantID, now)
 	return s.requestQueue.Enqueue(req.tenantID, queuePath, req, func() {
 		shouldCancel = false
@@ -402,8 +407,21 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 	})
 }
 
+// cancelRequest cancels the request and removes it from the queue if it is still queued, so that
+// it no longer counts against the outstanding requests of the tenant.
+// Requests already forwarded to a querier are canceled by closing the querier stream, see forwardRequestToQuerier.
+func (s *Scheduler) cancelRequest(frontendAddr string, queryID uint64) {
+	req := s.cancelRequestAndRemoveFromPending(frontendAddr, queryID)
+	if req == nil || req.dequeued.Load() {
+		return
+	}
+	s.requestQueue.Remove(req.tenantID, req.queuePath, func(r queue.Request) bool {
+		return r == req
+	})
+}
+
 // This method doesn't do removal from the queue.
-func (s *Scheduler) cancelRequestAndRemoveFromPending(frontendAddr string, queryID uint64) {
+func (s *Scheduler) cancelRequestAndRemoveFromPending(frontendAddr string, queryID uint64) *schedulerRequest {
 	s.pendingRequestsMu.Lock()
 	defer s.pendingRequestsMu.Unlock()
 
@@ -413,6 +431,7 @@ func (s *Scheduler) cancelRequestAndRemoveFromPending(frontendAddr string, query
 		req.ctxCancel()
 	}
 	delete(s.pendingRequests, key)
+	return req
 }
 
 // QuerierLoop is started by querier to receive queries from scheduler.
@@ -443,6 +462,7 @@ func (s *Scheduler) QuerierLoop(querier schedulerpb.SchedulerForQuerier_QuerierL
 			level.Error(s.log).Log("msg", "dequeue() call resulted in nil response", "querier", querierID)
 		}
 		r := req.(*schedulerRequest)
+		r.dequeued.Store(true)
 
 		reqQueueTime := time.Since(r.queueTime)
 		s.queueDuration.Observe(reqQueueTime.Seconds())
@@ -63,6 +63,10 @@ func ExtractTestableLabelMatchers(expr syntax.Expr) []LabelMatcher {
 		VisitLabelParserFn:            func(v syntax.RootVisitor, e *syntax.LabelParserExpr) { foundParseStage = true },
 		VisitJSONExpressionParserFn:   func(v syntax.RootVisitor, e *syntax.JSONExpressionParser) { foundParseStage = true },
 		VisitLogfmtExpressionParserFn: func(v syntax.RootVisitor, e *syntax.LogfmtExpressionParser) { foundParseStage = true },
+		VisitXMLExpressionParserFn:    func(v syntax.RootVisitor, e *syntax.XMLExpressionParser) { foundParseStage = true },
+		VisitCSVParserFn:              func(v syntax.RootVisitor, e *syntax.CSVParserExpr) { foundParseStage = true },
+		VisitJSONArrayFn:              func(v syntax.RootVisitor, e *syntax.JSONArrayExpr) { foundParseStage = true },
+		VisitMacroFn:                  func(v syntax.RootVisitor, e *syntax.MacroExpr) { foundParseStage = true },
 		VisitLabelFmtFn:               func(v syntax.RootVisitor, e *syntax.LabelFmtExpr) { foundParseStage = true },
 		VisitKeepLabelFn:              func(v syntax.RootVisitor, e *syntax.KeepLabelsExpr) { foundParseStage = true },
 		VisitDropLabelsFn:             func(v syntax.RootVisitor, e *syntax.DropLabelsExpr) { foundParseStage = true },
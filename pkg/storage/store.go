@@ -510,6 +510,16 @@ func (s *LokiStore) SelectLogs(ctx context.Context, req logql.SelectLogParams) (
 		return nil, err
 	}
 
+	expr, err = logql.OptimizePipelines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err = logql.RecordPipelineStages(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+
 	pipeline, err := expr.Pipeline()
 	if err != nil {
 		return nil, err
@@ -557,6 +567,16 @@ func (s *LokiStore) SelectSamples(ctx context.Context, req logql.SelectSamplePar
 		return nil, err
 	}
 
+	expr, err = logql.OptimizePipelines(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err = logql.RecordPipelineStages(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+
 	extractor, err := expr.Extractor()
 	if err != nil {
 		return nil, err
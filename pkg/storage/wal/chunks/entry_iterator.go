@@ -17,6 +17,13 @@ type entryBufferedIterator struct {
 
 	cur        logproto.Entry
 	currLabels log.LabelsResult
+
+	// exploding is set when the pipeline can return several entries for a single line.
+	exploding   log.ExplodingStreamPipeline
+	explodeN    int
+	explodeMore bool
+	currTs      int64
+	currLine    []byte
 }
 
 // NewEntryIterator creates an iterator for efficiently traversing log entries in a chunk.
@@ -43,10 +50,11 @@ func NewEntryIterator(
 		return nil, err
 	}
 	it := &entryBufferedIterator{
-		reader:   chkReader,
-		pipeline: pipeline,
-		from:     from,
-		through:  through,
+		reader:    chkReader,
+		pipeline:  pipeline,
+		from:      from,
+		through:   through,
+		exploding: log.AsExplodingPipeline(pipeline),
 	}
 	if direction == logproto.FORWARD {
 		return it, nil
@@ -76,22 +84,23 @@ func (e *entryBufferedIterator) Labels() string {
 
 // Next implements iter.EntryIterator.
 func (e *entryBufferedIterator) Next() bool {
-	for e.reader.Next() {
-		ts, line := e.reader.At()
-		// check if the timestamp is within the range before applying the pipeline.
-		if ts < e.from {
-			continue
+	for e.explodeMore || e.reader.Next() {
+		if !e.explodeMore {
+			e.currTs, e.currLine = e.reader.At()
+			// check if the timestamp is within the range before applying the pipeline.
+			if e.currTs < e.from {
+				continue
+			}
+			if e.currTs >= e.through {
+				return false
+			}
 		}
-		if ts >= e.through {
-			return false
-		}
-		// todo: structured metadata.
-		newLine, lbs, matches := e.pipeline.Process(ts, line)
+		newLine, lbs, matches := e.process()
 		if !matches {
 			continue
 		}
 		e.currLabels = lbs
-		e.cur.Timestamp = time.Unix(0, ts)
+		e.cur.Timestamp = time.Unix(0, e.currTs)
 		e.cur.Line = string(newLine)
 		e.cur.StructuredMetadata = logproto.FromLabelsToLabelAdapters(lbs.StructuredMetadata())
 		e.cur.Parsed = logproto.FromLabelsToLabelAdapters(lbs.Parsed())
@@ -100,6 +109,22 @@ func (e *entryBufferedIterator) Next() bool {
 	return false
 }
 
+// process processes the current line, or its next result when the line is exploded.
+func (e *entryBufferedIterator) process() ([]byte, log.LabelsResult, bool) {
+	// todo: structured metadata.
+	if e.exploding == nil {
+		return e.pipeline.Process(e.currTs, e.currLine)
+	}
+	if e.explodeMore {
+		e.explodeN++
+	} else {
+		e.explodeN = 0
+	}
+	newLine, lbs, matches, more := e.exploding.ProcessExploded(e.currTs, e.currLine, e.explodeN)
+	e.explodeMore = more
+	return newLine, lbs, matches
+}
+
 // StreamHash implements iter.EntryIterator.
 func (e *entryBufferedIterator) StreamHash() uint64 {
 	return e.pipeline.BaseLabels().Hash()
@@ -112,4 +137,11 @@ type sampleBufferedIterator struct {
 
 	cur        logproto.Sample
 	currLabels log.LabelsResult
+
+	// exploding is set when the extractor can return several samples for a single line.
+	exploding   log.ExplodingStreamSampleExtractor
+	explodeN    int
+	explodeMore bool
+	currTs      int64
+	currLine    []byte
 }
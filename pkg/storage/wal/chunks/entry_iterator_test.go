@@ -91,6 +91,24 @@ func TestNewEntryIterator(t *testing.T) {
 				{Timestamp: time.Unix(0, 3), Line: "foo error: another error occurred"},
 			},
 		},
+		{
+			name: "Forward direction with exploded lines",
+			entries: []*logproto.Entry{
+				{Timestamp: time.Unix(0, 1).UTC(), Line: `["a","b"]`},
+				{Timestamp: time.Unix(0, 2).UTC(), Line: `[]`},
+				{Timestamp: time.Unix(0, 3).UTC(), Line: `["c"]`},
+				{Timestamp: time.Unix(0, 4).UTC(), Line: `["d","e"]`},
+			},
+			direction: logproto.FORWARD,
+			from:      1,
+			through:   4,
+			pipeline:  mustNewPipeline(t, `{foo="bar"} | json_array`),
+			expected: []*logproto.Entry{
+				{Timestamp: time.Unix(0, 1), Line: "a"},
+				{Timestamp: time.Unix(0, 1), Line: "b"},
+				{Timestamp: time.Unix(0, 3), Line: "c"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
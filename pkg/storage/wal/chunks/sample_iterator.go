@@ -28,10 +28,11 @@ func NewSampleIterator(
 		return nil, err
 	}
 	it := &sampleBufferedIterator{
-		reader:   chkReader,
-		pipeline: pipeline,
-		from:     from,
-		through:  through,
+		reader:    chkReader,
+		pipeline:  pipeline,
+		from:      from,
+		through:   through,
+		exploding: log.AsExplodingSampleExtractor(pipeline),
 	}
 	return it, nil
 }
@@ -58,29 +59,46 @@ func (s *sampleBufferedIterator) Labels() string {
 
 // Next implements iter.SampleIterator.
 func (s *sampleBufferedIterator) Next() bool {
-	for s.reader.Next() {
-		// todo: Only use length columns for bytes_over_time without filter.
-		ts, line := s.reader.At()
-		// check if the timestamp is within the range before applying the pipeline.
-		if ts < s.from {
-			continue
-		}
-		if ts >= s.through {
-			return false
+	for s.explodeMore || s.reader.Next() {
+		if !s.explodeMore {
+			// todo: Only use length columns for bytes_over_time without filter.
+			s.currTs, s.currLine = s.reader.At()
+			// check if the timestamp is within the range before applying the pipeline.
+			if s.currTs < s.from {
+				continue
+			}
+			if s.currTs >= s.through {
+				return false
+			}
 		}
-		// todo: structured metadata.
-		val, lbs, matches := s.pipeline.Process(ts, line)
+		val, lbs, matches := s.process()
 		if !matches {
 			continue
 		}
 		s.currLabels = lbs
 		s.cur.Value = val
-		s.cur.Timestamp = ts
+		s.cur.Timestamp = s.currTs
 		return true
 	}
 	return false
 }
 
+// process extracts the sample of the current line, or its next sample when the line is exploded.
+func (s *sampleBufferedIterator) process() (float64, log.LabelsResult, bool) {
+	// todo: structured metadata.
+	if s.exploding == nil {
+		return s.pipeline.Process(s.currTs, s.currLine)
+	}
+	if s.explodeMore {
+		s.explodeN++
+	} else {
+		s.explodeN = 0
+	}
+	val, lbs, ok, more := s.exploding.ProcessExploded(s.currTs, s.currLine, s.explodeN)
+	s.explodeMore = more
+	return val, lbs, ok
+}
+
 // StreamHash implements iter.SampleIterator.
 func (s *sampleBufferedIterator) StreamHash() uint64 {
 	return s.pipeline.BaseLabels().Hash()
@@ -0,0 +1,46 @@
+package httpreq
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/dskit/middleware"
+)
+
+const (
+	// LokiExplainHeader is the name of the header propagating the explain mode of a query
+	// to the components executing it.
+	LokiExplainHeader = "X-Loki-Explain"
+	// ExplainParam is the name of the URL parameter setting the explain mode of a query.
+	ExplainParam = "explain"
+	// ExplainAnalyze runs the query and collects the statistics of each node of its plan.
+	ExplainAnalyze = "analyze"
+)
+
+// ExtractExplainMiddleware injects the explain mode of a query, set by the explain URL parameter
+// or the X-Loki-Explain header, into the request context.
+func ExtractExplainMiddleware() middleware.Interface {
+	return extractParamMiddleware(ExplainParam, LokiExplainHeader)
+}
+
+// extractParamMiddleware injects the value of a URL parameter, or of the header it falls back to,
+// into the request context under the name of the header.
+func extractParamMiddleware(param, header string) middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			value := req.URL.Query().Get(param)
+			if value == "" {
+				value = req.Header.Get(header)
+			}
+			if value != "" {
+				req = req.WithContext(InjectHeader(req.Context(), header, value))
+			}
+			next.ServeHTTP(w, req)
+		})
+	})
+}
+
+// IsExplainAnalyze returns true if the query is executed with explain=analyze.
+func IsExplainAnalyze(ctx context.Context) bool {
+	return ExtractHeader(ctx, LokiExplainHeader) == ExplainAnalyze
+}
@@ -0,0 +1,28 @@
+package httpreq
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/grafana/dskit/middleware"
+)
+
+const (
+	// LokiPipelineStatsHeader is the name of the header propagating whether the stages of the
+	// log pipelines of a query count the entries they process.
+	LokiPipelineStatsHeader = "X-Loki-Pipeline-Stats"
+	// PipelineStatsParam is the name of the URL parameter enabling the statistics of the pipeline stages.
+	PipelineStatsParam = "pipeline_stats"
+)
+
+// ExtractPipelineStatsMiddleware injects whether the statistics of the pipeline stages are collected,
+// set by the pipeline_stats URL parameter or the X-Loki-Pipeline-Stats header, into the request context.
+func ExtractPipelineStatsMiddleware() middleware.Interface {
+	return extractParamMiddleware(PipelineStatsParam, LokiPipelineStatsHeader)
+}
+
+// IsPipelineStats returns true if the stages of the log pipelines of the query count the entries they process.
+func IsPipelineStats(ctx context.Context) bool {
+	enabled, _ := strconv.ParseBool(ExtractHeader(ctx, LokiPipelineStatsHeader))
+	return enabled
+}
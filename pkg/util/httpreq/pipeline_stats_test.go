@@ -0,0 +1,37 @@
+package httpreq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPipelineStatsMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		url      string
+		header   string
+		expected bool
+	}{
+		{desc: "disabled", url: "/loki/api/v1/query_range"},
+		{desc: "url parameter", url: "/loki/api/v1/query_range?pipeline_stats=true", expected: true},
+		{desc: "header", url: "/loki/api/v1/query_range", header: "true", expected: true},
+		{desc: "url parameter takes precedence", url: "/loki/api/v1/query_range?pipeline_stats=false", header: "true"},
+		{desc: "invalid value", url: "/loki/api/v1/query_range?pipeline_stats=yes"},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.header != "" {
+				req.Header.Set(LokiPipelineStatsHeader, tc.header)
+			}
+
+			var actual bool
+			ExtractPipelineStatsMiddleware().Wrap(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				actual = IsPipelineStats(req.Context())
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
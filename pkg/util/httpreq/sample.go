@@ -0,0 +1,50 @@
+package httpreq
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/dskit/middleware"
+)
+
+const (
+	// LokiSampleHeader is the name of the header propagating the ratio of the streams read by a sampled metric query.
+	LokiSampleHeader = "X-Loki-Sample"
+	// SampleParam is the name of the URL parameter sampling the streams read by a metric query.
+	SampleParam = "sample"
+)
+
+// ExtractSampleMiddleware injects the sample ratio, set by the sample URL parameter or the X-Loki-Sample header,
+// into the request context.
+func ExtractSampleMiddleware() middleware.Interface {
+	return extractParamMiddleware(SampleParam, LokiSampleHeader)
+}
+
+// SampleRatio returns the ratio of the streams read by a sampled query. It returns false if the query is not sampled,
+// that is the ratio is missing, invalid or 1.
+func SampleRatio(ctx context.Context) (float64, bool) {
+	ratio, err := ParseSampleRatio(ExtractHeader(ctx, LokiSampleHeader))
+	if err != nil || ratio == 1 {
+		return 0, false
+	}
+	return ratio, true
+}
+
+// IsSampled returns true if the query reads a subset of the streams.
+func IsSampled(ctx context.Context) bool {
+	_, ok := SampleRatio(ctx)
+	return ok
+}
+
+// ParseSampleRatio parses a sample ratio, greater than 0 and lower than or equal to 1.
+func ParseSampleRatio(s string) (float64, error) {
+	ratio, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample ratio %q: %w", s, err)
+	}
+	if !(ratio > 0 && ratio <= 1) {
+		return 0, fmt.Errorf("invalid sample ratio %q: must be greater than 0 and lower than or equal to 1", s)
+	}
+	return ratio, nil
+}
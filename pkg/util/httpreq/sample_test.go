@@ -0,0 +1,68 @@
+package httpreq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSampleMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		url      string
+		header   string
+		expected float64
+		sampled  bool
+	}{
+		{desc: "not sampled", url: "/loki/api/v1/query_range"},
+		{desc: "url parameter", url: "/loki/api/v1/query_range?sample=0.05", expected: 0.05, sampled: true},
+		{desc: "header", url: "/loki/api/v1/query_range", header: "0.5", expected: 0.5, sampled: true},
+		{desc: "url parameter takes precedence", url: "/loki/api/v1/query_range?sample=0.1", header: "0.5", expected: 0.1, sampled: true},
+		{desc: "whole", url: "/loki/api/v1/query_range?sample=1"},
+		{desc: "zero", url: "/loki/api/v1/query_range?sample=0"},
+		{desc: "invalid value", url: "/loki/api/v1/query_range?sample=half"},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.header != "" {
+				req.Header.Set(LokiSampleHeader, tc.header)
+			}
+
+			var actual float64
+			var sampled bool
+			ExtractSampleMiddleware().Wrap(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				actual, sampled = SampleRatio(req.Context())
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			require.Equal(t, tc.sampled, sampled)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseSampleRatio(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected float64
+		err      bool
+	}{
+		{value: "0.05", expected: 0.05},
+		{value: "1", expected: 1},
+		{value: "0", err: true},
+		{value: "-0.5", err: true},
+		{value: "1.5", err: true},
+		{value: "NaN", err: true},
+		{value: "half", err: true},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			actual, err := ParseSampleRatio(tc.value)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
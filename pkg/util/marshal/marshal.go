@@ -63,6 +63,68 @@ func WriteQueryResponseJSON(data parser.Value, warnings []string, statistics sta
 	return s.Flush()
 }
 
+// WriteStreamNDJSON marshals the logproto.Stream to a v1 loghttp JSON stream of a single line
+// and then writes it to the provided io.Writer. Query responses are streamed as such lines.
+func WriteStreamNDJSON(stream logproto.Stream, w io.Writer, encodeFlags httpreq.EncodingFlags) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	err := encodeStream(stream, s, encodeFlags)
+	if err != nil {
+		return fmt.Errorf("could not write NDJSON stream: %w", err)
+	}
+	s.WriteRaw("\n")
+	return s.Flush()
+}
+
+// WriteQueryStatsNDJSON writes the line ending a query response streamed as NDJSON, with the
+// warnings and the statistics of the query.
+func WriteQueryStatsNDJSON(warnings []string, statistics stats.Result, w io.Writer, encodeFlags httpreq.EncodingFlags) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+
+	s.WriteObjectStart()
+	s.WriteObjectField("status")
+	s.WriteString("success")
+
+	if len(warnings) > 0 {
+		s.WriteMore()
+		s.WriteObjectField("warnings")
+		s.WriteVal(warnings)
+	}
+
+	if len(encodeFlags) > 0 {
+		s.WriteMore()
+		s.WriteObjectField("encodingFlags")
+		if err := encodeEncodingFlags(s, encodeFlags); err != nil {
+			return err
+		}
+	}
+
+	s.WriteMore()
+	s.WriteObjectField("stats")
+	s.WriteVal(statistics)
+	s.WriteObjectEnd()
+	s.WriteRaw("\n")
+	return s.Flush()
+}
+
+// WriteQueryErrorNDJSON writes the line ending a query response streamed as NDJSON which failed
+// after some of its streams were written.
+func WriteQueryErrorNDJSON(err error, w io.Writer) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+
+	s.WriteObjectStart()
+	s.WriteObjectField("status")
+	s.WriteString("error")
+	s.WriteMore()
+	s.WriteObjectField("error")
+	s.WriteString(err.Error())
+	s.WriteObjectEnd()
+	s.WriteRaw("\n")
+	return s.Flush()
+}
+
 // WriteLabelResponseJSON marshals a logproto.LabelResponse to v1 loghttp JSON
 // and then writes it to the provided io.Writer.
 func WriteLabelResponseJSON(data []string, w io.Writer) error {
@@ -11,6 +11,7 @@ import (
 
 	json "github.com/json-iterator/go"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
@@ -931,6 +932,9 @@ func (w wrappedValue) Generate(rand *rand.Rand, _ int) reflect.Value {
 
 		l, _ := quick.Value(reflect.TypeOf(labels.Labels{}), rand)
 		series.Metric = l.Interface().(labels.Labels)
+		for i := range series.Histograms {
+			series.Histograms[i].H = randHistogram(rand)
+		}
 
 		matrix := promql.Matrix{series}
 		return reflect.ValueOf(wrappedValue{matrix})
@@ -957,6 +961,9 @@ func (w wrappedValue) Generate(rand *rand.Rand, _ int) reflect.Value {
 
 			l, _ := quick.Value(reflect.TypeOf(labels.Labels{}), rand)
 			sample.Metric = l.Interface().(labels.Labels)
+			if sample.H != nil {
+				sample.H = randHistogram(rand)
+			}
 			vector = append(vector, sample)
 		}
 		return reflect.ValueOf(wrappedValue{vector})
@@ -995,6 +1002,31 @@ func randLabels(rand *rand.Rand) labels.Labels {
 	return labels
 }
 
+func randHistogram(rand *rand.Rand) *histogram.FloatHistogram {
+	h := &histogram.FloatHistogram{
+		CounterResetHint: histogram.GaugeType,
+		Schema:           int32(rand.Intn(4)),
+		ZeroThreshold:    0.001,
+		ZeroCount:        float64(rand.Intn(10)),
+	}
+	h.PositiveSpans, h.PositiveBuckets = randBuckets(rand)
+	h.NegativeSpans, h.NegativeBuckets = randBuckets(rand)
+	h.Count = h.ZeroCount
+	for _, b := range append(h.PositiveBuckets, h.NegativeBuckets...) {
+		h.Count += b
+	}
+	h.Sum = rand.NormFloat64() * h.Count
+	return h
+}
+
+func randBuckets(rand *rand.Rand) ([]histogram.Span, []float64) {
+	buckets := make([]float64, rand.Intn(10))
+	for i := range buckets {
+		buckets[i] = float64(rand.Intn(100))
+	}
+	return []histogram.Span{{Offset: int32(rand.Intn(20) - 10), Length: uint32(len(buckets))}}, buckets
+}
+
 func randEntries(rand *rand.Rand) []logproto.Entry {
 	var entries []logproto.Entry
 	nEntries := rand.Intn(100)
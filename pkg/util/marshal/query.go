@@ -8,6 +8,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
@@ -135,6 +136,9 @@ func NewSample(s promql.Sample) model.Sample {
 		Timestamp: model.Time(s.T),
 		Metric:    NewMetric(s.Metric),
 	}
+	if s.H != nil {
+		ret.Histogram = logqlmodel.NewSampleHistogram(s.H)
+	}
 
 	return ret
 }
@@ -161,6 +165,12 @@ func NewSampleStream(s promql.Series) model.SampleStream {
 		ret.Values[i].Timestamp = model.Time(p.T)
 		ret.Values[i].Value = model.SampleValue(p.F)
 	}
+	for _, p := range s.Histograms {
+		ret.Histograms = append(ret.Histograms, model.SampleHistogramPair{
+			Timestamp: model.Time(p.T),
+			Histogram: logqlmodel.NewSampleHistogram(p.H),
+		})
+	}
 
 	return ret
 }
@@ -478,6 +488,11 @@ func encodeSample(sample promql.Sample, s *jsoniter.Stream) {
 	encodeMetric(sample.Metric, s)
 
 	s.WriteMore()
+	if sample.H != nil {
+		s.WriteObjectField("histogram")
+		encodeHistogram(sample.T, sample.H, s)
+		return
+	}
 	s.WriteObjectField("value")
 	encodeValue(sample.T, sample.F, s)
 }
@@ -490,6 +505,15 @@ func encodeValue(T int64, V float64, s *jsoniter.Stream) {
 	s.WriteArrayEnd()
 }
 
+// encodeHistogram encodes a native histogram in the same format as the model.SampleHistogram of NewSample.
+func encodeHistogram(T int64, H *histogram.FloatHistogram, s *jsoniter.Stream) {
+	s.WriteArrayStart()
+	s.WriteRaw(model.Time(T).String())
+	s.WriteMore()
+	s.WriteVal(logqlmodel.NewSampleHistogram(H))
+	s.WriteArrayEnd()
+}
+
 func encodeMetric(l labels.Labels, s *jsoniter.Stream) {
 	s.WriteObjectStart()
 	for i, label := range l {
@@ -523,14 +547,31 @@ func encodeSampleStream(stream promql.Series, s *jsoniter.Stream) {
 	s.WriteObjectField("metric")
 	encodeMetric(stream.Metric, s)
 
+	// like model.SampleStream, values are omitted from series of histograms only.
+	if len(stream.Floats) > 0 || len(stream.Histograms) == 0 {
+		s.WriteMore()
+		s.WriteObjectField("values")
+		s.WriteArrayStart()
+		for i, p := range stream.Floats {
+			if i > 0 {
+				s.WriteMore()
+			}
+			encodeValue(p.T, p.F, s)
+		}
+		s.WriteArrayEnd()
+	}
+
+	if len(stream.Histograms) == 0 {
+		return
+	}
 	s.WriteMore()
-	s.WriteObjectField("values")
+	s.WriteObjectField("histograms")
 	s.WriteArrayStart()
-	for i, p := range stream.Floats {
+	for i, p := range stream.Histograms {
 		if i > 0 {
 			s.WriteMore()
 		}
-		encodeValue(p.T, p.F, s)
+		encodeHistogram(p.T, p.H, s)
 	}
 	s.WriteArrayEnd()
 }
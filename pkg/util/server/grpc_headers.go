@@ -9,19 +9,35 @@ import (
 	"github.com/grafana/loki/v3/pkg/util/httpreq"
 )
 
+// propagatedHeaders are the headers propagated from the HTTP request context to the GRPC metadata.
+var propagatedHeaders = []string{
+	httpreq.LokiDisablePipelineWrappersHeader,
+	httpreq.LokiExplainHeader,
+	httpreq.LokiPipelineStatsHeader,
+	httpreq.LokiSampleHeader,
+}
+
 func injectHTTPHeadersIntoGRPCRequest(ctx context.Context) context.Context {
-	header := httpreq.ExtractHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader)
-	if header == "" {
-		return ctx
-	}
+	var md metadata.MD
+	for _, name := range propagatedHeaders {
+		header := httpreq.ExtractHeader(ctx, name)
+		if header == "" {
+			continue
+		}
 
-	// inject into GRPC metadata
-	md, ok := metadata.FromOutgoingContext(ctx)
-	if !ok {
-		md = metadata.New(map[string]string{})
+		// inject into GRPC metadata
+		if md == nil {
+			existing, ok := metadata.FromOutgoingContext(ctx)
+			if !ok {
+				existing = metadata.New(map[string]string{})
+			}
+			md = existing.Copy()
+		}
+		md.Set(name, header)
+	}
+	if md == nil {
+		return ctx
 	}
-	md = md.Copy()
-	md.Set(httpreq.LokiDisablePipelineWrappersHeader, header)
 
 	return metadata.NewOutgoingContext(ctx, md)
 }
@@ -33,12 +49,14 @@ func extractHTTPHeadersFromGRPCRequest(ctx context.Context) context.Context {
 		return ctx
 	}
 
-	headerValues := md.Get(httpreq.LokiDisablePipelineWrappersHeader)
-	if len(headerValues) == 0 {
-		return ctx
+	for _, name := range propagatedHeaders {
+		headerValues := md.Get(name)
+		if len(headerValues) == 0 {
+			continue
+		}
+		ctx = httpreq.InjectHeader(ctx, name, headerValues[0])
 	}
-
-	return httpreq.InjectHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader, headerValues[0])
+	return ctx
 }
 
 func UnaryClientHTTPHeadersInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
@@ -79,3 +79,18 @@ func TestExtractHTTPHeaderFromGRPCRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestPropagateExplainHeaderThroughGRPC(t *testing.T) {
+	ctx := httpreq.InjectHeader(context.Background(), httpreq.LokiExplainHeader, httpreq.ExplainAnalyze)
+	ctx = httpreq.InjectHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader, "true")
+
+	md, _ := metadata.FromOutgoingContext(injectHTTPHeadersIntoGRPCRequest(ctx))
+	require.EqualValues(t, metadata.New(map[string]string{
+		httpreq.LokiDisablePipelineWrappersHeader: "true",
+		httpreq.LokiExplainHeader:                 httpreq.ExplainAnalyze,
+	}), md)
+
+	ctx = extractHTTPHeadersFromGRPCRequest(metadata.NewIncomingContext(context.Background(), md))
+	require.True(t, httpreq.IsExplainAnalyze(ctx))
+	require.Equal(t, "true", httpreq.ExtractHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader))
+}
@@ -21,6 +21,8 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/compactor/deletionmode"
 	"github.com/grafana/loki/v3/pkg/compression"
+	"github.com/grafana/loki/v3/pkg/distributor/ingestpipeline"
+	"github.com/grafana/loki/v3/pkg/distributor/redaction"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logql"
@@ -100,6 +102,7 @@ type Limits struct {
 	MaxQueryLookback           model.Duration   `yaml:"max_query_lookback" json:"max_query_lookback"`
 	MaxQueryLength             model.Duration   `yaml:"max_query_length" json:"max_query_length"`
 	MaxQueryRange              model.Duration   `yaml:"max_query_range" json:"max_query_range"`
+	MaxQueryJoinWindowEntries  int              `yaml:"max_query_join_window_entries" json:"max_query_join_window_entries"`
 	MaxQueryParallelism        int              `yaml:"max_query_parallelism" json:"max_query_parallelism"`
 	TSDBMaxQueryParallelism    int              `yaml:"tsdb_max_query_parallelism" json:"tsdb_max_query_parallelism"`
 	TSDBMaxBytesPerShard       flagext.ByteSize `yaml:"tsdb_max_bytes_per_shard" json:"tsdb_max_bytes_per_shard"`
@@ -192,6 +195,9 @@ type Limits struct {
 
 	ShardStreams shardstreams.Config `yaml:"shard_streams" json:"shard_streams" doc:"description=Define streams sharding behavior."`
 
+	IngestPipeline ingestpipeline.Config `yaml:"ingest_pipeline,omitempty" json:"ingest_pipeline,omitempty" doc:"description=Stages applied in order by the distributor to the pushed streams before they are validated. Each stage sets exactly one of:\n- drop: drops the entries matching a LogQL log query.\n- relabel: applies Prometheus relabel configs to the stream labels, streams left without labels are dropped.\n- redact: replaces the matches of a regex in the lines, of the entries matching an optional LogQL log query.\n- structured_metadata: adds labels extracted by the parsers of a LogQL log query to the structured metadata.\nExample:\n ingest_pipeline:\n - drop:\n selector: '{app=\"api\", level=\"debug\"}'\n - relabel:\n - action: labeldrop\n regex: pod_uid\n - redact:\n regex: 'password=\\S+'\n replacement: 'password=<redacted>'"`
+	Redaction      redaction.Config      `yaml:"redaction" json:"redaction" doc:"description=Redaction of the secrets found in the pushed entries, after the ingest pipeline."`
+
 	BlockedQueries []*validation.BlockedQuery `yaml:"blocked_queries,omitempty" json:"blocked_queries,omitempty"`
 
 	RequiredLabels       []string `yaml:"required_labels,omitempty" json:"required_labels,omitempty" doc:"description=Define a list of required selector labels."`
@@ -219,6 +225,8 @@ type Limits struct {
 	MaxStructuredMetadataEntriesCount int                   `yaml:"max_structured_metadata_entries_count" json:"max_structured_metadata_entries_count" doc:"description=Maximum number of structured metadata entries per log line."`
 	OTLPConfig                        push.OTLPConfig       `yaml:"otlp_config" json:"otlp_config" doc:"description=OTLP log ingestion configurations"`
 	GlobalOTLPConfig                  push.GlobalOTLPConfig `yaml:"-" json:"-"`
+	ElasticsearchMapping              push.DocumentMapping  `yaml:"elasticsearch_mapping" json:"elasticsearch_mapping" doc:"description=Mapping of the fields of the documents pushed to the Elasticsearch _bulk endpoint. Nested fields are addressed by their path, like kubernetes.pod.name, and are named after their normalized path."`
+	SplunkHECMapping                  push.DocumentMapping  `yaml:"splunk_hec_mapping" json:"splunk_hec_mapping" doc:"description=Mapping of the fields of the events pushed to the Splunk HTTP Event Collector endpoint. The fields are addressed like in the events, for example fields.env for an indexed field or event.level for a field of a JSON event."`
 
 	BlockIngestionUntil      dskit_flagext.Time `yaml:"block_ingestion_until" json:"block_ingestion_until"`
 	BlockIngestionStatusCode int                `yaml:"block_ingestion_status_code" json:"block_ingestion_status_code"`
@@ -268,6 +276,8 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	}
 	f.Var((*dskit_flagext.StringSlice)(&l.DiscoverServiceName), "validation.discover-service-name", "If no service_name label exists, Loki maps a single label from the configured list to service_name. If none of the configured labels exist in the stream, label is set to unknown_service. Empty list disables setting the label.")
 	f.BoolVar(&l.DiscoverLogLevels, "validation.discover-log-levels", true, "Discover and add log levels during ingestion, if not present already. Levels would be added to Structured Metadata with name level/LEVEL/Level/Severity/severity/SEVERITY/lvl/LVL/Lvl (case-sensitive) and one of the values from 'trace', 'debug', 'info', 'warn', 'error', 'critical', 'fatal' (case insensitive).")
+	l.ElasticsearchMapping.RegisterFlagsWithPrefix("distributor.elasticsearch-mapping", push.DefaultElasticsearchMapping, f)
+	l.SplunkHECMapping.RegisterFlagsWithPrefix("distributor.splunk-hec-mapping", push.DefaultSplunkHECMapping, f)
 
 	_ = l.RejectOldSamplesMaxAge.Set("7d")
 	f.Var(&l.RejectOldSamplesMaxAge, "validation.reject-old-samples.max-age", "Maximum accepted sample age before rejecting.")
@@ -294,6 +304,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxQuerySeries, "querier.max-query-series", 500, "Limit the maximum of unique series that is returned by a metric query. When the limit is reached an error is returned.")
 	_ = l.MaxQueryRange.Set("0s")
 	f.Var(&l.MaxQueryRange, "querier.max-query-range", "Limit the length of the [range] inside a range query. Default is 0 or unlimited")
+	f.IntVar(&l.MaxQueryJoinWindowEntries, "querier.max-query-join-window-entries", 100000, "Limit the number of log entries a join query keeps in memory while waiting for matching entries within the join tolerance. When the limit is reached an error is returned. 0 to disable.")
 	_ = l.QueryTimeout.Set(DefaultPerTenantQueryTimeout)
 	f.Var(&l.QueryTimeout, "querier.query-timeout", "Timeout when querying backends (ingesters or storage) during the execution of a query request. When a specific per-tenant timeout is used, the global timeout is ignored.")
 
@@ -436,6 +447,8 @@ func (l *Limits) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		if err := yaml.Unmarshal(b, (*plain)(l)); err != nil {
 			return errors.Wrap(err, "cloning limits (unmarshaling)")
 		}
+		// secrets are masked when they are marshaled.
+		l.Redaction.HashKey = defaultLimits.Redaction.HashKey
 	}
 	if err := unmarshal((*plain)(l)); err != nil {
 		return err
@@ -464,6 +477,14 @@ func (l *Limits) Validate() error {
 		}
 	}
 
+	if err := l.IngestPipeline.Validate(); err != nil {
+		return err
+	}
+
+	if err := l.Redaction.Validate(); err != nil {
+		return err
+	}
+
 	if _, err := deletionmode.ParseMode(l.DeletionMode); err != nil {
 		return err
 	}
@@ -635,6 +656,11 @@ func (o *Overrides) MaxQueryRange(_ context.Context, userID string) time.Duratio
 	return time.Duration(o.getOverridesForUser(userID).MaxQueryRange)
 }
 
+// MaxQueryJoinWindowEntries returns the limit of entries waiting to be matched by a join query.
+func (o *Overrides) MaxQueryJoinWindowEntries(_ context.Context, userID string) int {
+	return o.getOverridesForUser(userID).MaxQueryJoinWindowEntries
+}
+
 // MaxQueriersPerUser returns the maximum number of queriers that can handle requests for this user.
 func (o *Overrides) MaxQueriersPerUser(userID string) uint {
 	return o.getOverridesForUser(userID).MaxQueriersPerTenant
@@ -922,6 +948,16 @@ func (o *Overrides) ShardStreams(userID string) shardstreams.Config {
 	return o.getOverridesForUser(userID).ShardStreams
 }
 
+// IngestPipeline returns the ingest pipeline for a given user.
+func (o *Overrides) IngestPipeline(userID string) ingestpipeline.Config {
+	return o.getOverridesForUser(userID).IngestPipeline
+}
+
+// Redaction returns the redaction config for a given user.
+func (o *Overrides) Redaction(userID string) redaction.Config {
+	return o.getOverridesForUser(userID).Redaction
+}
+
 func (o *Overrides) BlockedQueries(_ context.Context, userID string) []*validation.BlockedQuery {
 	return o.getOverridesForUser(userID).BlockedQueries
 }
@@ -955,6 +991,16 @@ func (o *Overrides) DiscoverServiceName(userID string) []string {
 	return o.getOverridesForUser(userID).DiscoverServiceName
 }
 
+// ElasticsearchMapping returns the mapping of the documents pushed to the Elasticsearch endpoint for a given user.
+func (o *Overrides) ElasticsearchMapping(userID string) push.DocumentMapping {
+	return o.getOverridesForUser(userID).ElasticsearchMapping
+}
+
+// SplunkHECMapping returns the mapping of the events pushed to the Splunk HEC endpoint for a given user.
+func (o *Overrides) SplunkHECMapping(userID string) push.DocumentMapping {
+	return o.getOverridesForUser(userID).SplunkHECMapping
+}
+
 func (o *Overrides) DiscoverLogLevels(userID string) bool {
 	return o.getOverridesForUser(userID).DiscoverLogLevels
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/flagext"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/compactor/deletionmode"
 	"github.com/grafana/loki/v3/pkg/compression"
+	"github.com/grafana/loki/v3/pkg/distributor/redaction"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logql"
 )
@@ -316,6 +318,22 @@ query_timeout: 5m
 	}
 }
 
+func TestLimitsKeepDefaultSecrets(t *testing.T) {
+	initialDefault := defaultLimits
+	defer func() {
+		defaultLimits = initialDefault
+	}()
+
+	SetDefaultLimitsForYAMLUnmarshalling(Limits{Redaction: redaction.Config{HashKey: flagext.SecretWithValue("default-key")}})
+
+	var out Limits
+	require.NoError(t, yaml.UnmarshalStrict([]byte("redaction:\n  detectors:\n  - name: email\n    mode: hash\n"), &out))
+	require.Equal(t, "default-key", out.Redaction.HashKey.String())
+
+	require.NoError(t, yaml.UnmarshalStrict([]byte("redaction:\n  hash_key: tenant-key\n"), &out))
+	require.Equal(t, "tenant-key", out.Redaction.HashKey.String())
+}
+
 func TestLimitsValidation(t *testing.T) {
 	for _, tc := range []struct {
 		limits   Limits
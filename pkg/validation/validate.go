@@ -69,6 +69,11 @@ const (
 	StructuredMetadataTooManyErrorMsg    = "stream '%s' has too many structured metadata labels: '%d', limit: '%d'. Please see `limits_config.max_structured_metadata_entries_count` or contact your Loki administrator to increase it."
 	BlockedIngestion                     = "blocked_ingestion"
 	BlockedIngestionErrorMsg             = "ingestion blocked for user %s until '%s' with status code '%d'"
+	// IngestPipeline is a reason for discarding or mutating log lines in the ingest pipeline of the tenant.
+	IngestPipeline = "ingest_pipeline"
+	// Redacted is a reason for discarding or mutating log lines containing secrets found by the redaction detectors
+	// of the tenant.
+	Redacted = "redacted"
 )
 
 type ErrStreamRateLimit struct {
@@ -104,6 +109,16 @@ var MutatedBytes = promauto.NewCounterVec(
 	[]string{ReasonLabel, "truncated"},
 )
 
+// RedactedSecrets is a metric of the number of secrets found in the log lines and structured metadata, by detector.
+var RedactedSecrets = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: constants.Loki,
+		Name:      "redacted_secrets_total",
+		Help:      "The total number of secrets redacted from the log lines and structured metadata.",
+	},
+	[]string{"detector", "tenant"},
+)
+
 // DiscardedBytes is a metric of the total discarded bytes, by reason.
 var DiscardedBytes = promauto.NewCounterVec(
 	prometheus.CounterOpts{
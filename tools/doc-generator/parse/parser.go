@@ -361,6 +361,8 @@ func getFieldCustomType(t reflect.Type) (string, bool) {
 		return fieldString, true
 	case reflect.TypeOf(flagext.CIDRSliceCSV{}).String():
 		return fieldString, true
+	case reflect.TypeOf(flagext.Secret{}).String():
+		return fieldString, true
 	case reflect.TypeOf([]*util.RelabelConfig{}).String():
 		return fieldRelabelConfig, true
 	case reflect.TypeOf([]*relabel.Config{}).String():
@@ -464,6 +466,8 @@ func getCustomFieldType(t reflect.Type) (string, bool) {
 		return "headers", true
 	case reflect.TypeOf(relabel.Regexp{}).String():
 		return fieldString, true
+	case reflect.TypeOf(flagext.Secret{}).String():
+		return fieldString, true
 	default:
 		return "", false
 	}